@@ -4,10 +4,11 @@ import (
 	"os"
 
 	"github.com/rbansal42/bitbucket-cli/internal/cmd"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
 )
 
 func main() {
 	if err := cmd.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(cmdutil.ExitCode(err))
 	}
 }
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// CommitFull represents a commit in a repository's history
+type CommitFull struct {
+	Hash    string    `json:"hash"`
+	Message string    `json:"message"`
+	Date    time.Time `json:"date"`
+	Author  struct {
+		Raw  string `json:"raw"`
+		User *User  `json:"user,omitempty"`
+	} `json:"author"`
+	Parents []struct {
+		Hash string `json:"hash"`
+	} `json:"parents,omitempty"`
+	Links struct {
+		HTML Link `json:"html"`
+	} `json:"links"`
+}
+
+// CommitListOptions are options for listing commits
+type CommitListOptions struct {
+	Branch  string   // Only commits reachable from this branch
+	Include []string // Only commits reachable from these revisions
+	Exclude []string // Leave out commits reachable from these revisions
+	Page    int      // Page number
+	Limit   int      // Number of items per page (pagelen)
+}
+
+// PRActivity is an event on a pull request: an update, such as its
+// creation, a push to it or a merge, an approval, a request for changes, or
+// a comment. Exactly one of the event fields is set.
+type PRActivity struct {
+	PullRequest struct {
+		ID    int64  `json:"id"`
+		Title string `json:"title"`
+		Links struct {
+			HTML Link `json:"html"`
+		} `json:"links"`
+	} `json:"pull_request"`
+	Update           *PRActivityUpdate   `json:"update,omitempty"`
+	Approval         *PRActivityApproval `json:"approval,omitempty"`
+	ChangesRequested *PRActivityApproval `json:"changes_requested,omitempty"`
+	Comment          *PRComment          `json:"comment,omitempty"`
+}
+
+// PRActivityUpdate is a change to a pull request
+type PRActivityUpdate struct {
+	State  PRState   `json:"state"`
+	Title  string    `json:"title"`
+	Author User      `json:"author"`
+	Date   time.Time `json:"date"`
+}
+
+// PRActivityApproval is a reviewer approving, or requesting changes to, a
+// pull request
+type PRActivityApproval struct {
+	User User      `json:"user"`
+	Date time.Time `json:"date"`
+}
+
+// ListCommits lists the commits of a repository, newest first
+func (c *Client) ListCommits(ctx context.Context, workspace, repoSlug string, opts *CommitListOptions) (*Paginated[CommitFull], error) {
+	path := fmt.Sprintf("/repositories/%s/%s/commits", workspace, repoSlug)
+
+	query := url.Values{}
+	if opts != nil {
+		if opts.Branch != "" {
+			path += "/" + url.PathEscape(opts.Branch)
+		}
+		for _, rev := range opts.Include {
+			query.Add("include", rev)
+		}
+		for _, rev := range opts.Exclude {
+			query.Add("exclude", rev)
+		}
+		if opts.Page > 0 {
+			query.Set("page", strconv.Itoa(opts.Page))
+		}
+		if opts.Limit > 0 {
+			query.Set("pagelen", strconv.Itoa(opts.Limit))
+		}
+	}
+
+	resp, err := c.Get(ctx, path, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*Paginated[CommitFull]](resp)
+}
+
+// GetCommit gets a commit of a repository by hash, branch or tag
+func (c *Client) GetCommit(ctx context.Context, workspace, repoSlug, rev string) (*CommitFull, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s", workspace, repoSlug, url.PathEscape(rev))
+
+	resp, err := c.Get(ctx, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*CommitFull](resp)
+}
+
+// ListPRActivity lists the events on all pull requests of a repository,
+// newest first. Bitbucket returns at most 50 per page.
+func (c *Client) ListPRActivity(ctx context.Context, workspace, repoSlug string, limit int) (*Paginated[PRActivity], error) {
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/activity", workspace, repoSlug)
+
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("pagelen", strconv.Itoa(limit))
+	}
+
+	resp, err := c.Get(ctx, path, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*Paginated[PRActivity]](resp)
+}
+
+// ListAllPullRequestActivity lists the events on a pull request, newest
+// first, following the pages of results
+func (c *Client) ListAllPullRequestActivity(ctx context.Context, workspace, repoSlug string, prID int64) ([]PRActivity, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%d/activity", workspace, repoSlug, prID)
+
+	query := url.Values{}
+	query.Set("pagelen", "50")
+	var events []PRActivity
+	for {
+		resp, err := c.Get(ctx, path, query)
+		if err != nil {
+			return nil, err
+		}
+		result, err := ParseResponse[*Paginated[PRActivity]](resp)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, result.Values...)
+		if result.Next == "" {
+			return events, nil
+		}
+		// Activity is paged by a cursor in the next page's link, not by
+		// page number
+		next, err := url.Parse(result.Next)
+		if err != nil {
+			return nil, fmt.Errorf("invalid next page link: %w", err)
+		}
+		query = next.Query()
+	}
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListCommits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/repositories/team/app/commits/feature%2Fx" {
+			t.Errorf("unexpected path %q", r.URL.EscapedPath())
+		}
+		if got := r.URL.Query().Get("pagelen"); got != "20" {
+			t.Errorf("pagelen = %q, want 20", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"values": [{"hash": "abc123", "message": "Fix", "date": "2024-05-01T10:00:00+00:00", "author": {"raw": "Jane <jane@example.com>"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+	result, err := client.ListCommits(context.Background(), "team", "app", &CommitListOptions{Branch: "feature/x", Limit: 20})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Values) != 1 || result.Values[0].Hash != "abc123" || result.Values[0].Date.IsZero() {
+		t.Errorf("unexpected commits %+v", result.Values)
+	}
+}
+
+func TestListPRActivity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/team/app/pullrequests/activity" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"values": [
+			{"pull_request": {"id": 3, "title": "Add search"}, "update": {"state": "MERGED", "author": {"display_name": "Jane"}, "date": "2024-05-01T10:00:00+00:00"}},
+			{"pull_request": {"id": 3, "title": "Add search"}, "changes_requested": {"user": {"display_name": "Sam"}, "date": "2024-05-01T09:00:00+00:00"}}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+	result, err := client.ListPRActivity(context.Background(), "team", "app", 50)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Values) != 2 {
+		t.Fatalf("got %d entries, want 2", len(result.Values))
+	}
+	if u := result.Values[0].Update; u == nil || u.State != PRStateMerged || result.Values[0].PullRequest.ID != 3 {
+		t.Errorf("unexpected update %+v", result.Values[0])
+	}
+	if c := result.Values[1].ChangesRequested; c == nil || c.User.DisplayName != "Sam" {
+		t.Errorf("unexpected changes requested %+v", result.Values[1])
+	}
+}
+
+func TestListCommitsBetween(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/team/app/commits" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("include") != "feature" || query.Get("exclude") != "main" || query.Get("page") != "2" {
+			t.Errorf("unexpected query %q", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"values": [{"hash": "abc123"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+	result, err := client.ListCommits(context.Background(), "team", "app", &CommitListOptions{Include: []string{"feature"}, Exclude: []string{"main"}, Page: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Values) != 1 || result.Values[0].Hash != "abc123" {
+		t.Errorf("unexpected commits %+v", result.Values)
+	}
+}
+
+func TestGetCommit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/team/app/commit/abc123" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"hash": "abc123", "message": "Merge", "parents": [{"hash": "p1"}, {"hash": "p2"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+	commit, err := client.GetCommit(context.Background(), "team", "app", "abc123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commit.Parents) != 2 || commit.Parents[0].Hash != "p1" {
+		t.Errorf("unexpected commit %+v", commit)
+	}
+}
+
+func TestListAllPullRequestActivity(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/team/app/pullrequests/7/activity" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("ctx") == "" {
+			fmt.Fprintf(w, `{"next": "%s/repositories/team/app/pullrequests/7/activity?ctx=abc&pagelen=50", "values": [{"update": {"state": "MERGED"}}]}`, server.URL)
+			return
+		}
+		fmt.Fprint(w, `{"values": [{"approval": {"user": {"display_name": "Ann"}}}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	events, err := client.ListAllPullRequestActivity(context.Background(), "team", "app", 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 || events[0].Update == nil || events[1].Approval == nil {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
@@ -113,3 +113,41 @@ func (c *Client) DeleteBranch(ctx context.Context, workspace, repoSlug, branchNa
 	_, err := c.Delete(ctx, path)
 	return err
 }
+
+// BranchingModel is the branching model of a repository: the branches work
+// starts from and is released from, and the prefixes of each kind of branch
+type BranchingModel struct {
+	Development *BranchingModelBranch `json:"development,omitempty"`
+	Production  *BranchingModelBranch `json:"production,omitempty"`
+	BranchTypes []BranchType          `json:"branch_types"`
+}
+
+// BranchingModelBranch is the development or production branch of a
+// branching model
+type BranchingModelBranch struct {
+	// Name is the configured branch name, empty if UseMainBranch is set
+	Name          string `json:"name"`
+	UseMainBranch bool   `json:"use_mainbranch"`
+	// Branch is the branch the model refers to, nil if it doesn't exist
+	Branch *BranchFull `json:"branch,omitempty"`
+}
+
+// BranchType is a kind of branch of a branching model, such as feature or
+// hotfix, and the prefix of the names of branches of that kind
+type BranchType struct {
+	Kind   string `json:"kind"`
+	Prefix string `json:"prefix"`
+}
+
+// GetBranchingModel retrieves the branching model in effect for a
+// repository, which may be inherited from its project
+func (c *Client) GetBranchingModel(ctx context.Context, workspace, repoSlug string) (*BranchingModel, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/effective-branching-model", workspace, repoSlug)
+
+	resp, err := c.Get(ctx, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*BranchingModel](resp)
+}
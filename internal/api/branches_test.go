@@ -850,3 +850,37 @@ func TestListBranchesPagination(t *testing.T) {
 		t.Errorf("expected 2 values, got %d", len(result.Values))
 	}
 }
+
+func TestGetBranchingModel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/workspace/repo/effective-branching-model" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"type": "branching_model",
+			"development": {"name": "develop", "use_mainbranch": false, "branch": {"name": "develop", "target": {"hash": "abc123"}}},
+			"production": {"use_mainbranch": true, "branch": {"name": "main"}},
+			"branch_types": [
+				{"kind": "feature", "prefix": "feature/"},
+				{"kind": "hotfix", "prefix": "hotfix/"}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	model, err := client.GetBranchingModel(context.Background(), "workspace", "repo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if model.Development == nil || model.Development.Name != "develop" || model.Development.Branch.Target.Hash != "abc123" {
+		t.Errorf("unexpected development branch %+v", model.Development)
+	}
+	if model.Production == nil || !model.Production.UseMainBranch || model.Production.Branch.Name != "main" {
+		t.Errorf("unexpected production branch %+v", model.Production)
+	}
+	if len(model.BranchTypes) != 2 || model.BranchTypes[1].Kind != "hotfix" || model.BranchTypes[1].Prefix != "hotfix/" {
+		t.Errorf("unexpected branch types %+v", model.BranchTypes)
+	}
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Branch restriction kinds that decide whether a pull request can be merged
+const (
+	RestrictionRequireApprovals      = "require_approvals_to_merge"
+	RestrictionRequirePassingBuilds  = "require_passing_builds_to_merge"
+	RestrictionRequireNoChangesAsked = "require_no_changes_requested"
+)
+
+// BranchRestriction is a rule applied to the branches of a repository that
+// match it
+type BranchRestriction struct {
+	ID   int    `json:"id"`
+	Kind string `json:"kind"`
+	// BranchMatchKind is "glob", matching branch names against Pattern, or
+	// "branching_model", matching branches of BranchType
+	BranchMatchKind string `json:"branch_match_kind"`
+	Pattern         string `json:"pattern,omitempty"`
+	BranchType      string `json:"branch_type,omitempty"`
+	// Value is the number a kind such as require_approvals_to_merge needs
+	Value *int `json:"value,omitempty"`
+}
+
+// ListBranchRestrictions lists the branch restrictions of a repository. The
+// token needs admin access to the repository.
+func (c *Client) ListBranchRestrictions(ctx context.Context, workspace, repoSlug string) ([]BranchRestriction, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/branch-restrictions", workspace, repoSlug)
+
+	var restrictions []BranchRestriction
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("pagelen", "100")
+
+		resp, err := c.Get(ctx, path, query)
+		if err != nil {
+			return nil, err
+		}
+		result, err := ParseResponse[*Paginated[BranchRestriction]](resp)
+		if err != nil {
+			return nil, err
+		}
+		restrictions = append(restrictions, result.Values...)
+		if result.Next == "" {
+			return restrictions, nil
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListBranchRestrictions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/team/api/branch-restrictions" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			w.Write([]byte(`{"values": [{"id": 1, "kind": "require_approvals_to_merge", "branch_match_kind": "glob", "pattern": "main", "value": 2}], "next": "page2"}`))
+			return
+		}
+		w.Write([]byte(`{"values": [{"id": 2, "kind": "push", "branch_match_kind": "branching_model", "branch_type": "production"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+	restrictions, err := client.ListBranchRestrictions(context.Background(), "team", "api")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(restrictions) != 2 {
+		t.Fatalf("expected the restrictions of both pages, got %+v", restrictions)
+	}
+	if r := restrictions[0]; r.Kind != RestrictionRequireApprovals || r.Pattern != "main" || r.Value == nil || *r.Value != 2 {
+		t.Errorf("unexpected first restriction %+v", r)
+	}
+	if r := restrictions[1]; r.Value != nil || r.BranchType != "production" {
+		t.Errorf("unexpected second restriction %+v", r)
+	}
+}
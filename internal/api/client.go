@@ -3,9 +3,11 @@ package api
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
@@ -30,6 +32,17 @@ type Client struct {
 	token      string
 	username   string // For Basic Auth with API tokens
 	apiToken   string // For Basic Auth with API tokens
+	hooks      []RequestHook
+	// tokenSource supplies and renews the bearer token, replacing token
+	tokenSource TokenSource
+	proxy       *ProxyConfig
+	tlsConfig   *tls.Config
+	// transportConfig tunes the connection pool, if set
+	transportConfig *TransportConfig
+	// cache serves responses offline, if set
+	cache *cacheTransport
+	// logger records requests, retries and cache use, if set
+	logger *slog.Logger
 }
 
 // ClientOption is a functional option for configuring the client
@@ -48,9 +61,86 @@ func NewClient(opts ...ClientOption) *Client {
 		opt(c)
 	}
 
+	if c.proxy != nil || c.tlsConfig != nil || c.transportConfig != nil {
+		httpClient := *c.httpClient
+		if transport, ok := cloneTransport(httpClient.Transport); ok {
+			if c.transportConfig != nil {
+				c.transportConfig.Apply(transport)
+			}
+			if c.proxy != nil {
+				transport.Proxy = c.proxy.ProxyFunc()
+			}
+			if c.tlsConfig != nil {
+				// Cloned, as the transport adds its protocols to the config
+				transport.TLSClientConfig = c.tlsConfig.Clone()
+			}
+			httpClient.Transport = transport
+			c.httpClient = &httpClient
+		}
+	}
+
+	if c.logger != nil {
+		httpClient := *c.httpClient
+		base := httpClient.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		httpClient.Transport = &logTransport{base: base, logger: c.logger}
+		c.httpClient = &httpClient
+	}
+
+	if len(c.hooks) > 0 {
+		// Copy the HTTP client so a shared one passed via WithHTTPClient is
+		// not modified
+		httpClient := *c.httpClient
+		base := httpClient.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		httpClient.Transport = &hookTransport{base: base, hooks: c.hooks}
+		c.httpClient = &httpClient
+	}
+
+	if c.tokenSource != nil {
+		httpClient := *c.httpClient
+		base := httpClient.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		httpClient.Transport = &authTransport{base: base, source: c.tokenSource, logger: c.log()}
+		c.httpClient = &httpClient
+	}
+
+	// The cache comes first, so offline requests don't renew tokens
+	if c.cache != nil {
+		httpClient := *c.httpClient
+		c.cache.base = httpClient.Transport
+		if c.cache.base == nil {
+			c.cache.base = http.DefaultTransport
+		}
+		c.cache.logger = c.log()
+		c.cache.hooks = c.hooks
+		httpClient.Transport = c.cache
+		c.httpClient = &httpClient
+	}
+
 	return c
 }
 
+// cloneTransport returns a copy of rt, or of http.DefaultTransport if rt is
+// nil, that can be configured. Transports other than *http.Transport can't
+// be.
+func cloneTransport(rt http.RoundTripper) (*http.Transport, bool) {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	transport, ok := rt.(*http.Transport)
+	if !ok {
+		return nil, false
+	}
+	return transport.Clone(), true
+}
+
 // WithToken sets the authentication token (Bearer token for OAuth/Access Tokens)
 func WithToken(token string) ClientOption {
 	return func(c *Client) {
@@ -81,6 +171,14 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 	}
 }
 
+// WithTLSConfig sets the TLS configuration used to connect to the server,
+// e.g. to trust a private CA
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return func(c *Client) {
+		c.tlsConfig = cfg
+	}
+}
+
 // WithTimeout sets the HTTP client timeout
 func WithTimeout(timeout time.Duration) ClientOption {
 	return func(c *Client) {
@@ -88,19 +186,74 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
+// RequestInfo describes a completed HTTP request made by the client
+type RequestInfo struct {
+	Method     string
+	URL        *url.URL
+	StatusCode int         // zero if no response was received
+	Header     http.Header // response headers, nil if no response was received
+	Duration   time.Duration
+	Err        error
+	Cached     bool // served from the response cache, not the network
+}
+
+// RequestHook is called after each HTTP request the client makes
+type RequestHook func(RequestInfo)
+
+// WithRequestHook registers a hook that observes every request, e.g. to
+// report API usage and timing
+func WithRequestHook(hook RequestHook) ClientOption {
+	return func(c *Client) {
+		c.hooks = append(c.hooks, hook)
+	}
+}
+
+// hookTransport reports each round trip to the registered hooks
+type hookTransport struct {
+	base  http.RoundTripper
+	hooks []RequestHook
+}
+
+func (t *hookTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	info := RequestInfo{
+		Method:   req.Method,
+		URL:      req.URL,
+		Duration: time.Since(start),
+		Err:      err,
+	}
+	if resp != nil {
+		info.StatusCode = resp.StatusCode
+		info.Header = resp.Header
+	}
+	for _, hook := range t.hooks {
+		hook(info)
+	}
+
+	return resp, err
+}
+
 // APIError represents an error returned by the Bitbucket API
 type APIError struct {
 	StatusCode int
 	Message    string            `json:"message"`
 	Detail     string            `json:"detail"`
 	Fields     map[string]string `json:"fields,omitempty"`
+	// Scope is the token scope a refused (403) request likely needed
+	Scope string `json:"-"`
 }
 
 func (e *APIError) Error() string {
+	msg := fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
 	if e.Detail != "" {
-		return fmt.Sprintf("API error %d: %s - %s", e.StatusCode, e.Message, e.Detail)
+		msg = fmt.Sprintf("API error %d: %s - %s", e.StatusCode, e.Message, e.Detail)
 	}
-	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+	if e.Scope != "" {
+		msg += fmt.Sprintf("\nThe token may be missing the %q scope. Log in again with a token that has it: bb auth login", e.Scope)
+	}
+	return msg
 }
 
 // Request represents an API request
@@ -121,10 +274,69 @@ type Response struct {
 
 // Do performs an API request
 func (c *Client) Do(ctx context.Context, req *Request) (*Response, error) {
-	// Build URL
-	reqURL, err := url.Parse(c.baseURL + "/" + strings.TrimPrefix(req.Path, "/"))
+	httpReq, httpResp, err := c.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	// Read response body
+	respBody, err := io.ReadAll(httpResp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("invalid request URL: %w", err)
+		return nil, fmt.Errorf("could not read response body: %w", err)
+	}
+
+	resp := &Response{
+		StatusCode: httpResp.StatusCode,
+		Headers:    httpResp.Header,
+		Body:       respBody,
+	}
+
+	// Check for errors
+	if httpResp.StatusCode >= 400 {
+		return resp, newAPIError(httpReq, httpResp, respBody)
+	}
+
+	return resp, nil
+}
+
+// maxStreamErrorBody bounds how much of an error response DoStream reads
+// to build the error
+const maxStreamErrorBody = 1 << 20
+
+// DoStream performs an API request and returns the response with its body
+// unread, for responses such as diffs and logs that are too large to hold
+// in memory. The caller must close the body.
+func (c *Client) DoStream(ctx context.Context, req *Request) (*http.Response, error) {
+	httpReq, httpResp, err := c.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if httpResp.StatusCode >= 400 {
+		defer httpResp.Body.Close()
+		respBody, _ := io.ReadAll(io.LimitReader(httpResp.Body, maxStreamErrorBody))
+		return nil, newAPIError(httpReq, httpResp, respBody)
+	}
+
+	return httpResp, nil
+}
+
+// send builds and sends the HTTP request for req
+func (c *Client) send(ctx context.Context, req *Request) (*http.Request, *http.Response, error) {
+	if err := c.checkDataCenterPath(req.Path); err != nil {
+		return nil, nil, err
+	}
+
+	// Build URL. Paths that are full URLs, such as those on the website
+	// rather than the API, are used as they are.
+	rawURL := c.baseURL + "/" + strings.TrimPrefix(req.Path, "/")
+	if strings.HasPrefix(req.Path, "https://") || strings.HasPrefix(req.Path, "http://") {
+		rawURL = req.Path
+	}
+	reqURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid request URL: %w", err)
 	}
 
 	if req.Query != nil {
@@ -136,7 +348,7 @@ func (c *Client) Do(ctx context.Context, req *Request) (*Response, error) {
 	if req.Body != nil {
 		bodyBytes, err := json.Marshal(req.Body)
 		if err != nil {
-			return nil, fmt.Errorf("could not marshal request body: %w", err)
+			return nil, nil, fmt.Errorf("could not marshal request body: %w", err)
 		}
 		bodyReader = bytes.NewReader(bodyBytes)
 	}
@@ -144,7 +356,7 @@ func (c *Client) Do(ctx context.Context, req *Request) (*Response, error) {
 	// Create HTTP request
 	httpReq, err := http.NewRequestWithContext(ctx, req.Method, reqURL.String(), bodyReader)
 	if err != nil {
-		return nil, fmt.Errorf("could not create request: %w", err)
+		return nil, nil, fmt.Errorf("could not create request: %w", err)
 	}
 
 	// Set headers
@@ -171,47 +383,45 @@ func (c *Client) Do(ctx context.Context, req *Request) (*Response, error) {
 	// Execute request
 	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer httpResp.Body.Close()
-
-	// Read response body
-	respBody, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("could not read response body: %w", err)
+		return nil, nil, fmt.Errorf("request failed: %w", err)
 	}
+	return httpReq, httpResp, nil
+}
 
-	resp := &Response{
+// newAPIError builds the error for a failed request from its response
+func newAPIError(httpReq *http.Request, httpResp *http.Response, respBody []byte) *APIError {
+	apiErr := &APIError{
 		StatusCode: httpResp.StatusCode,
-		Headers:    httpResp.Header,
-		Body:       respBody,
+		Message:    http.StatusText(httpResp.StatusCode),
+	}
+	if httpResp.StatusCode == http.StatusForbidden {
+		apiErr.Scope = likelyScope(httpReq, httpResp)
 	}
 
-	// Check for errors
-	if httpResp.StatusCode >= 400 {
-		apiErr := &APIError{
-			StatusCode: httpResp.StatusCode,
-			Message:    http.StatusText(httpResp.StatusCode),
-		}
+	// Try to parse error response
+	var errResp struct {
+		Error struct {
+			Message string            `json:"message"`
+			Detail  string            `json:"detail"`
+			Fields  map[string]string `json:"fields"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(respBody, &errResp) == nil && errResp.Error.Message != "" {
+		apiErr.Message = errResp.Error.Message
+		apiErr.Detail = errResp.Error.Detail
+		apiErr.Fields = errResp.Error.Fields
+	}
 
-		// Try to parse error response
-		var errResp struct {
-			Error struct {
-				Message string            `json:"message"`
-				Detail  string            `json:"detail"`
-				Fields  map[string]string `json:"fields"`
-			} `json:"error"`
-		}
-		if json.Unmarshal(respBody, &errResp) == nil && errResp.Error.Message != "" {
-			apiErr.Message = errResp.Error.Message
-			apiErr.Detail = errResp.Error.Detail
-			apiErr.Fields = errResp.Error.Fields
-		}
+	return apiErr
+}
 
-		return resp, apiErr
+// webBaseURL returns the base URL of the website the API belongs to, which
+// serves some content, such as source archives, that the API doesn't
+func (c *Client) webBaseURL() string {
+	if c.baseURL == DefaultBaseURL {
+		return "https://bitbucket.org"
 	}
-
-	return resp, nil
+	return strings.TrimSuffix(c.baseURL, "/2.0")
 }
 
 // Get performs a GET request
@@ -287,6 +497,10 @@ type User struct {
 
 // GetCurrentUser returns the authenticated user
 func (c *Client) GetCurrentUser(ctx context.Context) (*User, error) {
+	if c.IsDataCenter() {
+		return c.getDataCenterUser(ctx)
+	}
+
 	resp, err := c.Get(ctx, "/user", nil)
 	if err != nil {
 		return nil, err
@@ -2,6 +2,8 @@ package api
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -552,3 +554,107 @@ func TestClientDelete_ConvenienceMethod(t *testing.T) {
 		t.Errorf("expected status code %d, got %d", http.StatusNoContent, resp.StatusCode)
 	}
 }
+
+func TestWithRequestHook_ReportsRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var infos []RequestInfo
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRequestHook(func(info RequestInfo) {
+			infos = append(infos, info)
+		}),
+	)
+
+	if _, err := client.Get(context.Background(), "/user", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.Get(context.Background(), "/missing", nil)
+
+	if len(infos) != 2 {
+		t.Fatalf("expected 2 requests to be reported, got %d", len(infos))
+	}
+	if infos[0].Method != http.MethodGet || infos[0].URL.Path != "/user" || infos[0].StatusCode != http.StatusOK {
+		t.Errorf("unexpected first request info: %+v", infos[0])
+	}
+	if infos[1].StatusCode != http.StatusNotFound {
+		t.Errorf("expected status 404 for second request, got %d", infos[1].StatusCode)
+	}
+}
+
+func TestWithRequestHook_DoesNotModifySharedHTTPClient(t *testing.T) {
+	shared := &http.Client{}
+	NewClient(WithHTTPClient(shared), WithRequestHook(func(RequestInfo) {}))
+
+	if shared.Transport != nil {
+		t.Error("expected shared HTTP client transport to be left unchanged")
+	}
+}
+
+func TestWithTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"username": "alice"}`))
+	}))
+	defer server.Close()
+
+	// The test server's certificate is signed by its own CA
+	if _, err := NewClient(WithBaseURL(server.URL)).GetCurrentUser(context.Background()); err == nil {
+		t.Fatal("expected an error for an untrusted certificate")
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	client := NewClient(WithBaseURL(server.URL), WithTLSConfig(&tls.Config{RootCAs: pool}))
+	if _, err := client.GetCurrentUser(context.Background()); err != nil {
+		t.Errorf("expected the trusted CA to be accepted, got %v", err)
+	}
+
+	insecure := NewClient(WithBaseURL(server.URL), WithTLSConfig(&tls.Config{InsecureSkipVerify: true}))
+	if _, err := insecure.GetCurrentUser(context.Background()); err != nil {
+		t.Errorf("expected verification to be skipped, got %v", err)
+	}
+}
+
+func TestClientDoStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"message": "Step not found"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("line 1\nline 2\n"))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	resp, err := client.DoStream(context.Background(), &Request{Method: http.MethodGet, Path: "/log"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || string(body) != "line 1\nline 2\n" {
+		t.Errorf("unexpected body %q, %v", body, err)
+	}
+
+	_, err = client.DoStream(context.Background(), &Request{Method: http.MethodGet, Path: "/missing"})
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("expected error to be *APIError, got %T", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "Step not found" {
+		t.Errorf("unexpected error: %+v", apiErr)
+	}
+}
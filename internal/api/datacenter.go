@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// dataCenterAPIPath is the path Bitbucket Data Center serves its REST API
+// under, which tells its base URLs apart from those of Bitbucket Cloud
+const dataCenterAPIPath = "/rest/api/1.0"
+
+// ErrDataCenterUnsupported is returned for requests to a Bitbucket Data
+// Center server that bb can only make to Bitbucket Cloud so far
+var ErrDataCenterUnsupported = errors.New("not supported on Bitbucket Data Center yet")
+
+// dataCenterPaths are the API paths bb uses on Bitbucket Data Center servers
+var dataCenterPaths = []string{"/application-properties", "/users/"}
+
+// IsDataCenter reports whether the client talks to a Bitbucket Data Center
+// server rather than Bitbucket Cloud
+func (c *Client) IsDataCenter() bool {
+	return strings.HasSuffix(c.baseURL, dataCenterAPIPath)
+}
+
+// checkDataCenterPath returns ErrDataCenterUnsupported if path is a
+// Bitbucket Cloud API path and the client talks to a Data Center server.
+// Full URLs, such as the next page of a listing, are left alone.
+func (c *Client) checkDataCenterPath(path string) error {
+	if !c.IsDataCenter() || strings.HasPrefix(path, "https://") || strings.HasPrefix(path, "http://") {
+		return nil
+	}
+	path = "/" + strings.TrimPrefix(path, "/")
+	for _, p := range dataCenterPaths {
+		if strings.HasPrefix(path, p) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s %s: %w", c.baseURL, path, ErrDataCenterUnsupported)
+}
+
+// dataCenterUser is a user as Bitbucket Data Center describes one
+type dataCenterUser struct {
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+	Links        struct {
+		Self []struct {
+			Href string `json:"href"`
+		} `json:"self"`
+	} `json:"links"`
+}
+
+// getDataCenterUser returns the authenticated user of a Data Center server.
+// The server has no endpoint for the current user; it names them in the
+// X-AUSERNAME header of every response instead, which is then looked up.
+func (c *Client) getDataCenterUser(ctx context.Context) (*User, error) {
+	resp, err := c.Get(ctx, "/application-properties", nil)
+	if err != nil {
+		return nil, err
+	}
+	name := resp.Headers.Get("X-AUSERNAME")
+	if name == "" {
+		return nil, &APIError{StatusCode: http.StatusUnauthorized, Message: "the server didn't accept the credentials"}
+	}
+
+	resp, err = c.Get(ctx, "/users/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	dc, err := ParseResponse[dataCenterUser](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &User{
+		Username:    dc.Slug,
+		DisplayName: dc.DisplayName,
+		Nickname:    dc.Name,
+	}
+	if len(dc.Links.Self) > 0 {
+		user.Links.HTML.Href = dc.Links.Self[0].Href
+	}
+	return user, nil
+}
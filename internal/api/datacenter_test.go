@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newDataCenterServer serves the user lookup of a Bitbucket Data Center
+// server, naming the user in X-AUSERNAME when the request has token
+func newDataCenterServer(t *testing.T, token string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/1.0/application-properties", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer "+token {
+			w.Header().Set("X-AUSERNAME", "jane.doe@example.com")
+		}
+		w.Write([]byte(`{"version":"8.19.1","displayName":"Bitbucket"}`))
+	})
+	mux.HandleFunc("/rest/api/1.0/users/jane.doe@example.com", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"name": "jane.doe@example.com",
+			"slug": "jane.doe_example.com",
+			"displayName": "Jane Doe",
+			"links": {"self": [{"href": "https://bitbucket.example.com/users/jane.doe_example.com"}]}
+		}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetCurrentUser_DataCenter(t *testing.T) {
+	server := newDataCenterServer(t, "dc-token")
+	client := NewClient(WithBaseURL(server.URL+"/rest/api/1.0"), WithToken("dc-token"))
+
+	user, err := client.GetCurrentUser(context.Background())
+	if err != nil {
+		t.Fatalf("GetCurrentUser() returned error: %v", err)
+	}
+	if user.Username != "jane.doe_example.com" {
+		t.Errorf("Username = %q, want %q", user.Username, "jane.doe_example.com")
+	}
+	if user.DisplayName != "Jane Doe" {
+		t.Errorf("DisplayName = %q, want %q", user.DisplayName, "Jane Doe")
+	}
+	if want := "https://bitbucket.example.com/users/jane.doe_example.com"; user.Links.HTML.Href != want {
+		t.Errorf("Links.HTML.Href = %q, want %q", user.Links.HTML.Href, want)
+	}
+}
+
+func TestGetCurrentUser_DataCenterRejectedToken(t *testing.T) {
+	server := newDataCenterServer(t, "dc-token")
+	client := NewClient(WithBaseURL(server.URL+"/rest/api/1.0"), WithToken("wrong-token"))
+
+	_, err := client.GetCurrentUser(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("GetCurrentUser() error = %v, want a 401 APIError", err)
+	}
+}
+
+func TestClientDo_DataCenterUnsupported(t *testing.T) {
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL + "/rest/api/1.0"))
+	_, err := client.Get(context.Background(), "/repositories/PROJ/repo/pullrequests", nil)
+	if !errors.Is(err, ErrDataCenterUnsupported) {
+		t.Errorf("Get() error = %v, want ErrDataCenterUnsupported", err)
+	}
+	if requested {
+		t.Error("Get() sent the request, want it refused before sending")
+	}
+}
+
+func TestIsDataCenter(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		want    bool
+	}{
+		{DefaultBaseURL, false},
+		{"https://bitbucket.example.com/rest/api/1.0", true},
+		{"https://bitbucket.example.com/rest/api/1.0/", true},
+		{"https://bitbucket.example.com/2.0", false},
+	}
+	for _, tt := range tests {
+		if got := NewClient(WithBaseURL(tt.baseURL)).IsDataCenter(); got != tt.want {
+			t.Errorf("IsDataCenter() for %q = %v, want %v", tt.baseURL, got, tt.want)
+		}
+	}
+}
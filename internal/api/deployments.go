@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Environment is a deployment environment of a repository, such as test,
+// staging or production, that pipeline steps deploy to
+type Environment struct {
+	UUID            string `json:"uuid"`
+	Name            string `json:"name"`
+	Slug            string `json:"slug"`
+	EnvironmentType struct {
+		// Name is Test, Staging or Production
+		Name string `json:"name"`
+	} `json:"environment_type"`
+	Rank int `json:"rank"`
+}
+
+// DeploymentVariable is a variable set for the pipeline steps that deploy
+// to an environment. The values of secured variables are masked in logs
+// and aren't returned by the API.
+type DeploymentVariable struct {
+	UUID    string `json:"uuid,omitempty"`
+	Key     string `json:"key"`
+	Value   string `json:"value,omitempty"`
+	Secured bool   `json:"secured"`
+}
+
+// ListEnvironments lists the deployment environments of a repository,
+// following the pages of results
+func (c *Client) ListEnvironments(ctx context.Context, workspace, repoSlug string) ([]Environment, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/environments", workspace, repoSlug)
+
+	var environments []Environment
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("pagelen", "100")
+
+		resp, err := c.Get(ctx, path, query)
+		if err != nil {
+			return nil, err
+		}
+		result, err := ParseResponse[*Paginated[Environment]](resp)
+		if err != nil {
+			return nil, err
+		}
+		environments = append(environments, result.Values...)
+		if result.Next == "" {
+			return environments, nil
+		}
+	}
+}
+
+// ListDeploymentVariables lists the variables of a deployment environment,
+// given by UUID, following the pages of results
+func (c *Client) ListDeploymentVariables(ctx context.Context, workspace, repoSlug, environmentUUID string) ([]DeploymentVariable, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/deployments_config/environments/%s/variables", workspace, repoSlug, environmentUUID)
+
+	var variables []DeploymentVariable
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("pagelen", "100")
+
+		resp, err := c.Get(ctx, path, query)
+		if err != nil {
+			return nil, err
+		}
+		result, err := ParseResponse[*Paginated[DeploymentVariable]](resp)
+		if err != nil {
+			return nil, err
+		}
+		variables = append(variables, result.Values...)
+		if result.Next == "" {
+			return variables, nil
+		}
+	}
+}
+
+// CreateDeploymentVariable adds a variable to a deployment environment
+func (c *Client) CreateDeploymentVariable(ctx context.Context, workspace, repoSlug, environmentUUID string, variable *DeploymentVariable) (*DeploymentVariable, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/deployments_config/environments/%s/variables", workspace, repoSlug, environmentUUID)
+
+	resp, err := c.Post(ctx, path, variable)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*DeploymentVariable](resp)
+}
+
+// UpdateDeploymentVariable replaces the value of a variable of a
+// deployment environment, given by variable.UUID
+func (c *Client) UpdateDeploymentVariable(ctx context.Context, workspace, repoSlug, environmentUUID string, variable *DeploymentVariable) (*DeploymentVariable, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/deployments_config/environments/%s/variables/%s", workspace, repoSlug, environmentUUID, variable.UUID)
+
+	resp, err := c.Put(ctx, path, variable)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*DeploymentVariable](resp)
+}
+
+// DeleteDeploymentVariable removes a variable, given by UUID, from a
+// deployment environment
+func (c *Client) DeleteDeploymentVariable(ctx context.Context, workspace, repoSlug, environmentUUID, variableUUID string) error {
+	path := fmt.Sprintf("/repositories/%s/%s/deployments_config/environments/%s/variables/%s", workspace, repoSlug, environmentUUID, variableUUID)
+
+	_, err := c.Delete(ctx, path)
+	return err
+}
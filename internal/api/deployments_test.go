@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListEnvironments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/team/app/environments" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			fmt.Fprint(w, `{"next": "https://api.bitbucket.org/next", "values": [{"uuid": "{1}", "name": "Test", "environment_type": {"name": "Test"}}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"values": [{"uuid": "{2}", "name": "Production", "environment_type": {"name": "Production"}}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	environments, err := client.ListEnvironments(context.Background(), "team", "app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(environments) != 2 || environments[1].Name != "Production" || environments[1].EnvironmentType.Name != "Production" {
+		t.Errorf("unexpected environments %+v", environments)
+	}
+}
+
+func TestDeploymentVariables(t *testing.T) {
+	const base = "/repositories/team/app/deployments_config/environments/{env}/variables"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET " + base:
+			fmt.Fprint(w, `{"values": [{"uuid": "{v1}", "key": "API_URL", "value": "https://api.example.com", "secured": false}, {"uuid": "{v2}", "key": "TOKEN", "secured": true}]}`)
+		case "POST " + base, "PUT " + base + "/{v1}":
+			var v DeploymentVariable
+			if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+			if v.Key != "API_URL" || v.Value != "https://new.example.com" {
+				t.Errorf("unexpected variable %+v", v)
+			}
+			v.UUID = "{v1}"
+			json.NewEncoder(w).Encode(v)
+		case "DELETE " + base + "/{v2}":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	variables, err := client.ListDeploymentVariables(ctx, "team", "app", "{env}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(variables) != 2 || !variables[1].Secured || variables[1].Value != "" {
+		t.Errorf("unexpected variables %+v", variables)
+	}
+
+	v := &DeploymentVariable{Key: "API_URL", Value: "https://new.example.com"}
+	if created, err := client.CreateDeploymentVariable(ctx, "team", "app", "{env}", v); err != nil || created.UUID != "{v1}" {
+		t.Errorf("CreateDeploymentVariable() = %+v, %v", created, err)
+	}
+	v.UUID = "{v1}"
+	if _, err := client.UpdateDeploymentVariable(ctx, "team", "app", "{env}", v); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := client.DeleteDeploymentVariable(ctx, "team", "app", "{env}", "{v2}"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+// DiffOptions are options for diffs between commits
+type DiffOptions struct {
+	// TwoDot compares the source with the destination itself, instead of
+	// with their merge base
+	TwoDot bool
+	// Path limits the diff to a file or directory
+	Path string
+}
+
+// query returns the query parameters of opts
+func (opts *DiffOptions) query() url.Values {
+	query := url.Values{}
+	if opts != nil {
+		if opts.TwoDot {
+			query.Set("topic", "false")
+		}
+		if opts.Path != "" {
+			query.Set("path", opts.Path)
+		}
+	}
+	return query
+}
+
+// GetDiff returns the diff of spec: a commit, or a range written
+// SOURCE..DESTINATION, Bitbucket's order, which is the reverse of git's.
+// Revisions may be commit hashes, branches or tags. The changes in a range
+// are those made on the source since its merge base with the destination,
+// or with opts.TwoDot, the difference between them.
+func (c *Client) GetDiff(ctx context.Context, workspace, repoSlug, spec string, opts *DiffOptions) (string, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/diff/%s", workspace, repoSlug, url.PathEscape(spec))
+
+	resp, err := c.Do(ctx, &Request{
+		Method: http.MethodGet,
+		Path:   path,
+		Query:  opts.query(),
+		Headers: map[string]string{
+			"Accept": "text/plain",
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(resp.Body), nil
+}
+
+// GetDiffStat returns the per-file summary of the diff of spec, which is
+// given as to GetDiff, following the pages of results
+func (c *Client) GetDiffStat(ctx context.Context, workspace, repoSlug, spec string, opts *DiffOptions) ([]DiffStat, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/diffstat/%s", workspace, repoSlug, url.PathEscape(spec))
+
+	var stats []DiffStat
+	for page := 1; ; page++ {
+		query := opts.query()
+		query.Set("page", strconv.Itoa(page))
+		query.Set("pagelen", "500")
+
+		resp, err := c.Get(ctx, path, query)
+		if err != nil {
+			return nil, err
+		}
+		result, err := ParseResponse[*Paginated[DiffStat]](resp)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, result.Values...)
+		if result.Next == "" {
+			return stats, nil
+		}
+	}
+}
+
+// Divergence is how far two revisions have moved apart since their merge base
+type Divergence struct {
+	MergeBase string `json:"merge_base"`
+	// Ahead is the number of commits on the head that aren't on the base,
+	// and Behind the number on the base that aren't on the head. Each
+	// stops at the limit counted to; AheadMore and BehindMore are set when
+	// there are more.
+	Ahead      int  `json:"ahead"`
+	AheadMore  bool `json:"ahead_truncated,omitempty"`
+	Behind     int  `json:"behind"`
+	BehindMore bool `json:"behind_truncated,omitempty"`
+}
+
+// GetMergeBase returns the best common ancestor of two revisions, which
+// may be commit hashes, branches or tags
+func (c *Client) GetMergeBase(ctx context.Context, workspace, repoSlug, rev1, rev2 string) (*CommitFull, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/merge-base/%s", workspace, repoSlug, url.PathEscape(rev1+".."+rev2))
+
+	resp, err := c.Get(ctx, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*CommitFull](resp)
+}
+
+// CountCommitsBetween counts the commits on include that aren't on
+// exclude, stopping at limit, and reports whether there are more
+func (c *Client) CountCommitsBetween(ctx context.Context, workspace, repoSlug, include, exclude string, limit int) (int, bool, error) {
+	count := 0
+	for page := 1; ; page++ {
+		result, err := c.ListCommits(ctx, workspace, repoSlug, &CommitListOptions{
+			Include: []string{include},
+			Exclude: []string{exclude},
+			Page:    page,
+			Limit:   100,
+		})
+		if err != nil {
+			return 0, false, err
+		}
+		count += len(result.Values)
+		if count > limit {
+			return limit, true, nil
+		}
+		if result.Next == "" || len(result.Values) == 0 {
+			return count, false, nil
+		}
+	}
+}
+
+// GetDivergence finds the merge base of head and base and counts the
+// commits each has that the other doesn't, up to limit on each side
+func (c *Client) GetDivergence(ctx context.Context, workspace, repoSlug, head, base string, limit int) (*Divergence, error) {
+	var (
+		d                                 Divergence
+		wg                                sync.WaitGroup
+		mergeBaseErr, aheadErr, behindErr error
+	)
+	wg.Go(func() {
+		var commit *CommitFull
+		commit, mergeBaseErr = c.GetMergeBase(ctx, workspace, repoSlug, head, base)
+		if mergeBaseErr == nil {
+			d.MergeBase = commit.Hash
+		}
+	})
+	wg.Go(func() {
+		d.Ahead, d.AheadMore, aheadErr = c.CountCommitsBetween(ctx, workspace, repoSlug, head, base, limit)
+	})
+	wg.Go(func() {
+		d.Behind, d.BehindMore, behindErr = c.CountCommitsBetween(ctx, workspace, repoSlug, base, head, limit)
+	})
+	wg.Wait()
+
+	for _, err := range []error{mergeBaseErr, aheadErr, behindErr} {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &d, nil
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetDiff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/repositories/team/app/diff/feature%2Fx..main" {
+			t.Errorf("unexpected path %q", r.URL.EscapedPath())
+		}
+		if got := r.URL.Query().Get("topic"); got != "false" {
+			t.Errorf("topic = %q, want false", got)
+		}
+		if got := r.Header.Get("Accept"); got != "text/plain" {
+			t.Errorf("Accept = %q, want text/plain", got)
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "diff --git a/main.go b/main.go\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	diff, err := client.GetDiff(context.Background(), "team", "app", "feature/x..main", &DiffOptions{TwoDot: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff != "diff --git a/main.go b/main.go\n" {
+		t.Errorf("unexpected diff %q", diff)
+	}
+}
+
+func TestGetDiffStat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/repositories/team/app/diffstat/feature..main" {
+			t.Errorf("unexpected path %q", r.URL.EscapedPath())
+		}
+		if r.URL.Query().Has("topic") {
+			t.Errorf("expected no topic parameter, got %q", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			fmt.Fprint(w, `{"next": "https://api.bitbucket.org/next", "values": [{"status": "modified", "lines_added": 3, "new": {"path": "a.go"}}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"values": [{"status": "added", "lines_added": 5, "new": {"path": "b.go"}}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	stats, err := client.GetDiffStat(context.Background(), "team", "app", "feature..main", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stats) != 2 || stats[0].New.Path != "a.go" || stats[1].LinesAdded != 5 {
+		t.Errorf("unexpected diffstat %+v", stats)
+	}
+}
+
+func TestGetMergeBase(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/repositories/team/app/merge-base/feature%2Fx..main" {
+			t.Errorf("unexpected path %q", r.URL.EscapedPath())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"hash": "abc123", "message": "Base"}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	commit, err := client.GetMergeBase(context.Background(), "team", "app", "feature/x", "main")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if commit.Hash != "abc123" {
+		t.Errorf("unexpected merge base %+v", commit)
+	}
+}
+
+func TestGetDivergence(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/repositories/team/app/merge-base/feature..main" {
+			fmt.Fprint(w, `{"hash": "abc123"}`)
+			return
+		}
+		query := r.URL.Query()
+		switch query.Get("include") + " " + query.Get("exclude") {
+		case "feature main":
+			// Ahead: two full pages, so counting stops at the limit
+			fmt.Fprint(w, `{"next": "https://api.bitbucket.org/next", "values": [{"hash": "a"}, {"hash": "b"}, {"hash": "c"}]}`)
+		case "main feature":
+			fmt.Fprint(w, `{"values": [{"hash": "d"}]}`)
+		default:
+			t.Errorf("unexpected query %q", r.URL.RawQuery)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	d, err := client.GetDivergence(context.Background(), "team", "app", "feature", "main", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Divergence{MergeBase: "abc123", Ahead: 5, AheadMore: true, Behind: 1}
+	if *d != want {
+		t.Errorf("GetDivergence() = %+v, want %+v", *d, want)
+	}
+}
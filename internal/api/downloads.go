@@ -0,0 +1,32 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// UploadDownload adds a file named name, with the content read from r, to
+// a repository's Downloads, replacing any file of the same name
+func (c *Client) UploadDownload(ctx context.Context, workspace, repoSlug, name string, r io.Reader) error {
+	path := fmt.Sprintf("/repositories/%s/%s/downloads", workspace, repoSlug)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("files", name)
+	if err != nil {
+		return fmt.Errorf("could not build multipart body: %w", err)
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return fmt.Errorf("could not read %s: %w", name, err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("could not build multipart body: %w", err)
+	}
+
+	_, err = c.doMultipart(ctx, http.MethodPost, path, body, writer.FormDataContentType())
+	return err
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUploadDownload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repositories/team/app/downloads" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		file, header, err := r.FormFile("files")
+		if err != nil {
+			t.Fatalf("no file in request: %v", err)
+		}
+		content, _ := io.ReadAll(file)
+		if header.Filename != "app-linux-amd64" || string(content) != "binary" {
+			t.Errorf("unexpected file %s: %q", header.Filename, content)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	if err := client.UploadDownload(context.Background(), "team", "app", "app-linux-amd64", strings.NewReader("binary")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
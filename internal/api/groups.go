@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Group is a workspace group. Groups are only in version 1.0 of the API,
+// whose users have the same fields as those of version 2.0.
+type Group struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+	// Permission is the workspace permission members get: read, write,
+	// admin, or "" for none
+	Permission string `json:"permission"`
+	// AutoAdd is set when new workspace members join the group
+	AutoAdd bool   `json:"auto_add"`
+	Members []User `json:"members"`
+}
+
+// GroupPrivilege is the permission a group has been granted on a repository
+type GroupPrivilege struct {
+	Repo      string `json:"repo"` // as WORKSPACE/REPO
+	Privilege string `json:"privilege"`
+	Group     Group  `json:"group"`
+}
+
+// v1URL returns the URL of path in version 1.0 of the API, which is next
+// to version 2.0 on the same host
+func (c *Client) v1URL(path string) string {
+	return strings.TrimSuffix(c.baseURL, "/2.0") + "/1.0" + path
+}
+
+// groupsURL returns the URL of a workspace's groups, or of the group or
+// member resource named by parts under them
+func (c *Client) groupsURL(workspace string, parts ...string) string {
+	path := "/groups/" + url.PathEscape(workspace)
+	for _, p := range parts {
+		path += "/" + url.PathEscape(p)
+	}
+	return c.v1URL(path)
+}
+
+// ListGroups lists the groups of a workspace, with their members
+func (c *Client) ListGroups(ctx context.Context, workspace string) ([]Group, error) {
+	resp, err := c.Get(ctx, c.groupsURL(workspace), nil)
+	if err != nil {
+		return nil, err
+	}
+	return ParseResponse[[]Group](resp)
+}
+
+// ListGroupMembers lists the members of a workspace group
+func (c *Client) ListGroupMembers(ctx context.Context, workspace, groupSlug string) ([]User, error) {
+	resp, err := c.Get(ctx, c.groupsURL(workspace, groupSlug, "members"), nil)
+	if err != nil {
+		return nil, err
+	}
+	return ParseResponse[[]User](resp)
+}
+
+// AddGroupMember adds a user, given by UUID or account ID, to a workspace
+// group
+func (c *Client) AddGroupMember(ctx context.Context, workspace, groupSlug, userID string) error {
+	_, err := c.Put(ctx, c.groupsURL(workspace, groupSlug, "members", userID), struct{}{})
+	return err
+}
+
+// RemoveGroupMember removes a user, given by UUID or account ID, from a
+// workspace group
+func (c *Client) RemoveGroupMember(ctx context.Context, workspace, groupSlug, userID string) error {
+	_, err := c.Delete(ctx, c.groupsURL(workspace, groupSlug, "members", userID))
+	return err
+}
+
+// ListGroupPrivileges lists the permissions granted to the groups of a
+// workspace on its repositories
+func (c *Client) ListGroupPrivileges(ctx context.Context, workspace string) ([]GroupPrivilege, error) {
+	path := fmt.Sprintf("/group-privileges/%s", url.PathEscape(workspace))
+
+	resp, err := c.Get(ctx, c.v1URL(path), nil)
+	if err != nil {
+		return nil, err
+	}
+	return ParseResponse[[]GroupPrivilege](resp)
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListGroups(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1.0/groups/workspace" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[
+			{"name": "Developers", "slug": "developers", "permission": "write", "auto_add": true,
+			 "members": [{"uuid": "{1}", "display_name": "Alice"}]},
+			{"name": "Auditors", "slug": "auditors", "permission": null, "members": []}
+		]`)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL + "/2.0"))
+	groups, err := client.ListGroups(context.Background(), "workspace")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+	if groups[0].Slug != "developers" || groups[0].Permission != "write" || !groups[0].AutoAdd {
+		t.Errorf("unexpected first group %+v", groups[0])
+	}
+	if len(groups[0].Members) != 1 || groups[0].Members[0].DisplayName != "Alice" {
+		t.Errorf("unexpected members %+v", groups[0].Members)
+	}
+	if groups[1].Permission != "" {
+		t.Errorf("expected no permission, got %q", groups[1].Permission)
+	}
+}
+
+func TestListGroupPrivileges(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1.0/group-privileges/workspace" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[{"repo": "workspace/api", "privilege": "admin", "group": {"slug": "developers"}}]`)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL + "/2.0"))
+	privileges, err := client.ListGroupPrivileges(context.Background(), "workspace")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(privileges) != 1 || privileges[0].Repo != "workspace/api" || privileges[0].Privilege != "admin" || privileges[0].Group.Slug != "developers" {
+		t.Errorf("unexpected privileges %+v", privileges)
+	}
+}
+
+func TestGroupMembership(t *testing.T) {
+	tests := []struct {
+		name       string
+		call       func(c *Client) error
+		wantMethod string
+		wantPath   string
+		wantBody   string
+	}{
+		{
+			name: "add member",
+			call: func(c *Client) error {
+				return c.AddGroupMember(context.Background(), "workspace", "developers", "{abc}")
+			},
+			wantMethod: http.MethodPut,
+			wantPath:   "/1.0/groups/workspace/developers/members/%7Babc%7D",
+			wantBody:   `{}`,
+		},
+		{
+			name: "remove member",
+			call: func(c *Client) error {
+				return c.RemoveGroupMember(context.Background(), "workspace", "developers", "557058:abc")
+			},
+			wantMethod: http.MethodDelete,
+			wantPath:   "/1.0/groups/workspace/developers/members/557058:abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tt.wantMethod {
+					t.Errorf("expected method %s, got %s", tt.wantMethod, r.Method)
+				}
+				if r.URL.EscapedPath() != tt.wantPath {
+					t.Errorf("expected path %q, got %q", tt.wantPath, r.URL.EscapedPath())
+				}
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != tt.wantBody {
+					t.Errorf("expected body %q, got %q", tt.wantBody, body)
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL + "/2.0"))
+			if err := tt.call(client); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// MaxAnnotationsPerRequest is how many annotations Bitbucket accepts in
+// one request, and MaxAnnotationsPerReport how many a report can have
+const (
+	MaxAnnotationsPerRequest = 100
+	MaxAnnotationsPerReport  = 1000
+)
+
+// Report is a Code Insights report on a commit, such as the results of a
+// scanner or a test run, shown on the pull requests containing it
+type Report struct {
+	UUID       string       `json:"uuid,omitempty"`
+	ExternalID string       `json:"external_id,omitempty"`
+	Title      string       `json:"title"`
+	Details    string       `json:"details,omitempty"`
+	ReportType string       `json:"report_type,omitempty"` // SECURITY, COVERAGE, TEST, BUG
+	Reporter   string       `json:"reporter,omitempty"`
+	Link       string       `json:"link,omitempty"`
+	LogoURL    string       `json:"logo_url,omitempty"`
+	Result     string       `json:"result,omitempty"` // PASSED, FAILED, PENDING
+	Data       []ReportData `json:"data,omitempty"`
+	CreatedOn  *time.Time   `json:"created_on,omitempty"`
+	UpdatedOn  *time.Time   `json:"updated_on,omitempty"`
+}
+
+// ReportData is a value shown on a report. Type is one of BOOLEAN, DATE,
+// DURATION (in milliseconds), LINK, NUMBER, PERCENTAGE or TEXT.
+type ReportData struct {
+	Title string `json:"title"`
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
+
+// Annotation is a finding of a report on a line of a file
+type Annotation struct {
+	UUID           string `json:"uuid,omitempty"`
+	ExternalID     string `json:"external_id"`
+	AnnotationType string `json:"annotation_type"` // VULNERABILITY, CODE_SMELL, BUG
+	Summary        string `json:"summary"`
+	Details        string `json:"details,omitempty"`
+	Path           string `json:"path,omitempty"`
+	Line           int    `json:"line,omitempty"`
+	Severity       string `json:"severity,omitempty"` // CRITICAL, HIGH, MEDIUM, LOW
+	Result         string `json:"result,omitempty"`   // PASSED, FAILED, SKIPPED, IGNORED
+	Link           string `json:"link,omitempty"`
+}
+
+// CreateReport creates the report reportID on a commit, or replaces it.
+// Annotations of a replaced report are kept; delete the report first to
+// start afresh.
+func (c *Client) CreateReport(ctx context.Context, workspace, repoSlug, commit, reportID string, report *Report) (*Report, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s/reports/%s", workspace, repoSlug, commit, url.PathEscape(reportID))
+
+	resp, err := c.Put(ctx, path, report)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*Report](resp)
+}
+
+// DeleteReport deletes the report reportID of a commit, with its
+// annotations. Deleting a report that doesn't exist is not an error.
+func (c *Client) DeleteReport(ctx context.Context, workspace, repoSlug, commit, reportID string) error {
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s/reports/%s", workspace, repoSlug, commit, url.PathEscape(reportID))
+
+	_, err := c.Delete(ctx, path)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// CreateAnnotations adds annotations to the report reportID of a commit,
+// in batches of MaxAnnotationsPerRequest
+func (c *Client) CreateAnnotations(ctx context.Context, workspace, repoSlug, commit, reportID string, annotations []Annotation) error {
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s/reports/%s/annotations", workspace, repoSlug, commit, url.PathEscape(reportID))
+
+	for start := 0; start < len(annotations); start += MaxAnnotationsPerRequest {
+		end := min(start+MaxAnnotationsPerRequest, len(annotations))
+		if _, err := c.Post(ctx, path, annotations[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListReports lists the reports on a commit, following the pages of results
+func (c *Client) ListReports(ctx context.Context, workspace, repoSlug, commit string) ([]Report, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s/reports", workspace, repoSlug, commit)
+
+	var reports []Report
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("pagelen", "100")
+
+		resp, err := c.Get(ctx, path, query)
+		if err != nil {
+			return nil, err
+		}
+		result, err := ParseResponse[*Paginated[Report]](resp)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, result.Values...)
+		if result.Next == "" {
+			return reports, nil
+		}
+	}
+}
+
+// GetReport gets the report reportID of a commit
+func (c *Client) GetReport(ctx context.Context, workspace, repoSlug, commit, reportID string) (*Report, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s/reports/%s", workspace, repoSlug, commit, url.PathEscape(reportID))
+
+	resp, err := c.Get(ctx, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*Report](resp)
+}
+
+// ListAnnotations lists the annotations of the report reportID of a commit,
+// following the pages of results
+func (c *Client) ListAnnotations(ctx context.Context, workspace, repoSlug, commit, reportID string) ([]Annotation, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s/reports/%s/annotations", workspace, repoSlug, commit, url.PathEscape(reportID))
+
+	var annotations []Annotation
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("pagelen", "100")
+
+		resp, err := c.Get(ctx, path, query)
+		if err != nil {
+			return nil, err
+		}
+		result, err := ParseResponse[*Paginated[Annotation]](resp)
+		if err != nil {
+			return nil, err
+		}
+		annotations = append(annotations, result.Values...)
+		if result.Next == "" {
+			return annotations, nil
+		}
+	}
+}
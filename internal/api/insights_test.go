@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateReport(t *testing.T) {
+	var got Report
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("expected PUT, got %s", r.Method)
+		}
+		if r.URL.Path != "/repositories/team/api/commit/abc123/reports/bb-sarif" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"uuid": "{report-1}", "title": "Scan", "result": "FAILED"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+	report, err := client.CreateReport(context.Background(), "team", "api", "abc123", "bb-sarif", &Report{
+		Title:      "Scan",
+		ReportType: "SECURITY",
+		Result:     "FAILED",
+		Data:       []ReportData{{Title: "Findings", Type: "NUMBER", Value: 3}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.UUID != "{report-1}" {
+		t.Errorf("expected the created report, got %+v", report)
+	}
+	if got.ReportType != "SECURITY" || len(got.Data) != 1 || got.Data[0].Title != "Findings" {
+		t.Errorf("unexpected request body %+v", got)
+	}
+}
+
+func TestDeleteReport_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"type": "error", "error": {"message": "Report not found"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+	if err := client.DeleteReport(context.Background(), "team", "api", "abc123", "bb-sarif"); err != nil {
+		t.Errorf("expected a missing report to be ignored, got %v", err)
+	}
+}
+
+func TestCreateAnnotations_Batches(t *testing.T) {
+	var batches []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/team/api/commit/abc123/reports/bb-sarif/annotations" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		var annotations []Annotation
+		json.NewDecoder(r.Body).Decode(&annotations)
+		batches = append(batches, len(annotations))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	annotations := make([]Annotation, 250)
+	for i := range annotations {
+		annotations[i] = Annotation{ExternalID: fmt.Sprintf("a%d", i), AnnotationType: "BUG", Summary: "x"}
+	}
+
+	client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+	if err := client.CreateAnnotations(context.Background(), "team", "api", "abc123", "bb-sarif", annotations); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(batches) != "[100 100 50]" {
+		t.Errorf("expected batches of 100, got %v", batches)
+	}
+}
+
+func TestListReports(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/team/api/commit/abc123/reports" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			fmt.Fprint(w, `{"next": "next", "values": [{"external_id": "coverage", "title": "Coverage", "data": [{"title": "Coverage", "type": "PERCENTAGE", "value": 82.5}]}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"values": [{"external_id": "snyk", "title": "Snyk"}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+	reports, err := client.ListReports(context.Background(), "team", "api", "abc123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reports) != 2 || reports[0].ExternalID != "coverage" || reports[0].Data[0].Value != 82.5 || reports[1].ExternalID != "snyk" {
+		t.Errorf("unexpected reports %+v", reports)
+	}
+}
+
+func TestListAnnotations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/team/api/commit/abc123/reports/bb-sarif/annotations" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"values": [{"external_id": "a1", "summary": "SQL injection", "severity": "HIGH", "path": "db.go", "line": 12}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+	annotations, err := client.ListAnnotations(context.Background(), "team", "api", "abc123", "bb-sarif")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(annotations) != 1 || annotations[0].Severity != "HIGH" || annotations[0].Line != 12 {
+		t.Errorf("unexpected annotations %+v", annotations)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -63,7 +64,7 @@ type IssueListOptions struct {
 	Kind     string // Filter by kind
 	Priority string // Filter by priority
 	Assignee string // Filter by assignee
-	Q        string // Search query
+	Q        string // Search query, combined with the other filters using AND
 	Sort     string // Sort field
 	Page     int    // Page number
 	Limit    int    // Number of items per page (pagelen)
@@ -115,7 +116,8 @@ type issueUpdateRequest struct {
 	} `json:"assignee,omitempty"`
 }
 
-// issueCommentRequest is the API request body for creating an issue comment
+// issueCommentRequest is the API request body for creating or updating an
+// issue comment
 type issueCommentRequest struct {
 	Content struct {
 		Raw string `json:"raw"`
@@ -129,35 +131,14 @@ func (c *Client) ListIssues(ctx context.Context, workspace, repoSlug string, opt
 	query := url.Values{}
 	if opts != nil {
 		// Build query filter using Bitbucket query language
-		var q string
-		if opts.Q != "" {
-			q = opts.Q
-		} else {
-			var filters []string
-			if opts.State != "" {
-				filters = append(filters, fmt.Sprintf("state=\"%s\"", opts.State))
-			}
-			if opts.Kind != "" {
-				filters = append(filters, fmt.Sprintf("kind=\"%s\"", opts.Kind))
-			}
-			if opts.Priority != "" {
-				filters = append(filters, fmt.Sprintf("priority=\"%s\"", opts.Priority))
-			}
-			if opts.Assignee != "" {
-				filters = append(filters, fmt.Sprintf("assignee.username=\"%s\"", opts.Assignee))
-			}
-			if len(filters) > 0 {
-				for i, f := range filters {
-					if i == 0 {
-						q = f
-					} else {
-						q += " AND " + f
-					}
-				}
-			}
-		}
-		if q != "" {
-			query.Set("q", q)
+		q := new(Query).
+			Eq("state", opts.State).
+			Eq("kind", opts.Kind).
+			Eq("priority", opts.Priority).
+			Eq("assignee.username", opts.Assignee).
+			Raw(opts.Q)
+		if !q.Empty() {
+			query.Set("q", q.String())
 		}
 
 		if opts.Sort != "" {
@@ -179,6 +160,77 @@ func (c *Client) ListIssues(ctx context.Context, workspace, repoSlug string, opt
 	return ParseResponse[*Paginated[Issue]](resp)
 }
 
+// issuePageConcurrency is how many pages of issues ListAllIssues reads at
+// once
+const issuePageConcurrency = 4
+
+// ListAllIssues lists every issue of a repository matching opts, ignoring
+// its Page and Limit. The first page gives the number of pages, and the
+// rest are then read concurrently.
+func (c *Client) ListAllIssues(ctx context.Context, workspace, repoSlug string, opts *IssueListOptions) ([]Issue, error) {
+	var pageOpts IssueListOptions
+	if opts != nil {
+		pageOpts = *opts
+	}
+	pageOpts.Page = 1
+	pageOpts.Limit = 50
+
+	first, err := c.ListIssues(ctx, workspace, repoSlug, &pageOpts)
+	if err != nil {
+		return nil, err
+	}
+	if first.Next == "" {
+		return first.Values, nil
+	}
+	if first.Size == 0 || len(first.Values) == 0 {
+		// The size wasn't reported, so follow the pages one at a time
+		issues := first.Values
+		for result := first; result.Next != ""; {
+			pageOpts.Page++
+			if result, err = c.ListIssues(ctx, workspace, repoSlug, &pageOpts); err != nil {
+				return nil, err
+			}
+			issues = append(issues, result.Values...)
+		}
+		return issues, nil
+	}
+
+	pageLen := len(first.Values)
+	pages := make([][]Issue, (first.Size+pageLen-1)/pageLen)
+	pages[0] = first.Values
+	errs := make([]error, len(pages))
+
+	var wg sync.WaitGroup
+	limiter := make(chan struct{}, issuePageConcurrency)
+	for i := 1; i < len(pages); i++ {
+		wg.Go(func() {
+			limiter <- struct{}{}
+			defer func() { <-limiter }()
+
+			o := pageOpts
+			o.Page = i + 1
+			result, err := c.ListIssues(ctx, workspace, repoSlug, &o)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			pages[i] = result.Values
+		})
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	var issues []Issue
+	for _, page := range pages {
+		issues = append(issues, page...)
+	}
+	return issues, nil
+}
+
 // GetIssue gets a single issue by ID
 func (c *Client) GetIssue(ctx context.Context, workspace, repoSlug string, issueID int) (*Issue, error) {
 	path := fmt.Sprintf("/repositories/%s/%s/issues/%d", workspace, repoSlug, issueID)
@@ -290,3 +342,52 @@ func (c *Client) CreateIssueComment(ctx context.Context, workspace, repoSlug str
 
 	return ParseResponse[*IssueComment](resp)
 }
+
+// ListAllIssueComments lists every comment on an issue, following the pages
+// of results
+func (c *Client) ListAllIssueComments(ctx context.Context, workspace, repoSlug string, issueID int) ([]IssueComment, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/issues/%d/comments", workspace, repoSlug, issueID)
+
+	var comments []IssueComment
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("pagelen", "100")
+
+		resp, err := c.Get(ctx, path, query)
+		if err != nil {
+			return nil, err
+		}
+		result, err := ParseResponse[*Paginated[IssueComment]](resp)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, result.Values...)
+		if result.Next == "" {
+			return comments, nil
+		}
+	}
+}
+
+// UpdateIssueComment replaces the text of a comment on an issue
+func (c *Client) UpdateIssueComment(ctx context.Context, workspace, repoSlug string, issueID, commentID int, body string) (*IssueComment, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/issues/%d/comments/%d", workspace, repoSlug, issueID, commentID)
+
+	reqBody := issueCommentRequest{}
+	reqBody.Content.Raw = body
+
+	resp, err := c.Put(ctx, path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*IssueComment](resp)
+}
+
+// DeleteIssueComment deletes a comment on an issue
+func (c *Client) DeleteIssueComment(ctx context.Context, workspace, repoSlug string, issueID, commentID int) error {
+	path := fmt.Sprintf("/repositories/%s/%s/issues/%d/comments/%d", workspace, repoSlug, issueID, commentID)
+
+	_, err := c.Delete(ctx, path)
+	return err
+}
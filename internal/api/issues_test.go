@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -1579,3 +1580,103 @@ func TestListIssuesPagination(t *testing.T) {
 		t.Errorf("expected 2 values, got %d", len(result.Values))
 	}
 }
+
+func TestListAllIssues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("q"); got != `state="open"` {
+			t.Errorf("expected q state=\"open\", got %q", got)
+		}
+		page := r.URL.Query().Get("page")
+		next := ""
+		if page != "3" {
+			next = `"next": "https://api.bitbucket.org/next",`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"size": 5, %s "values": [{"id": %s1}, {"id": %s2}]}`, next, page, page)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	issues, err := client.ListAllIssues(context.Background(), "workspace", "repo", &IssueListOptions{State: "open", Page: 7})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ids []int
+	for _, issue := range issues {
+		ids = append(ids, issue.ID)
+	}
+	want := []int{11, 12, 21, 22, 31, 32}
+	if fmt.Sprint(ids) != fmt.Sprint(want) {
+		t.Errorf("expected issues %v in page order, got %v", want, ids)
+	}
+}
+
+func TestUpdateIssueComment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("expected PUT request, got %s", r.Method)
+		}
+		if r.URL.Path != "/repositories/workspace/repo/issues/7/comments/42" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		if content, _ := body["content"].(map[string]interface{}); content["raw"] != "Updated text" {
+			t.Errorf("expected raw content %q, got %v", "Updated text", body["content"])
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": 42, "content": {"raw": "Updated text"}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	comment, err := client.UpdateIssueComment(context.Background(), "workspace", "repo", 7, 42, "Updated text")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if comment.ID != 42 || comment.Content.Raw != "Updated text" {
+		t.Errorf("unexpected comment %+v", comment)
+	}
+}
+
+func TestDeleteIssueComment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("expected DELETE request, got %s", r.Method)
+		}
+		if r.URL.Path != "/repositories/workspace/repo/issues/7/comments/42" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	if err := client.DeleteIssueComment(context.Background(), "workspace", "repo", 7, 42); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestListAllIssueComments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			fmt.Fprint(w, `{"next": "https://api.bitbucket.org/next", "values": [{"id": 1}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"values": [{"id": 2}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	comments, err := client.ListAllIssueComments(context.Background(), "workspace", "repo", 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 2 || comments[0].ID != 1 || comments[1].ID != 2 {
+		t.Errorf("unexpected comments %+v", comments)
+	}
+}
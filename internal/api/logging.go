@@ -0,0 +1,65 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/redact"
+)
+
+// WithLogger logs the requests the client makes, its retries and its use of
+// the response cache to logger
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// log returns the client's logger, or one that discards everything
+func (c *Client) log() *slog.Logger {
+	if c.logger == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return c.logger
+}
+
+// NewLoggingTransport returns a transport that makes requests with base and
+// logs them to logger, for HTTP clients other than a Client's
+func NewLoggingTransport(base http.RoundTripper, logger *slog.Logger) http.RoundTripper {
+	return &logTransport{base: base, logger: logger}
+}
+
+// logTransport logs each round trip, with its outcome and duration
+type logTransport struct {
+	base   http.RoundTripper
+	logger *slog.Logger
+}
+
+func (t *logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	level := slog.LevelInfo
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", redact.URL(req.URL)),
+		slog.Int64("duration_ms", time.Since(start).Milliseconds()),
+	}
+	if err != nil {
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.String("error", redact.Text(err.Error())))
+	} else {
+		if resp.StatusCode >= 400 {
+			level = slog.LevelWarn
+		}
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		// Bitbucket support can look a request up by its ID
+		if id := resp.Header.Get("X-Request-Id"); id != "" {
+			attrs = append(attrs, slog.String("request_id", id))
+		}
+	}
+	t.logger.LogAttrs(req.Context(), level, "api request", attrs...)
+
+	return resp, err
+}
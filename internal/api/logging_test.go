@@ -0,0 +1,89 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWithLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "abc123")
+		w.Write([]byte(`{"username": "alice"}`))
+	}))
+	defer server.Close()
+
+	var log bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&log, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	cache := &memoryCache{responses: map[string]*CachedResponse{}}
+	client := NewClient(WithBaseURL(server.URL), WithLogger(logger), WithResponseCache(cache, CacheFallback, nil))
+
+	ctx := context.Background()
+	if _, err := client.GetCurrentUser(ctx); err != nil {
+		t.Fatal(err)
+	}
+	client.Get(ctx, "/missing", nil)
+
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(log.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("log line %q is not JSON: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	want := []struct {
+		level, msg string
+		status     float64
+	}{
+		{"INFO", "api request", 200},
+		{"DEBUG", "response cached", 0},
+		{"WARN", "api request", 404},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d log entries, want %d:\n%s", len(entries), len(want), log.String())
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e["level"] != w.level || e["msg"] != w.msg {
+			t.Errorf("entry %d = %s %q, want %s %q", i, e["level"], e["msg"], w.level, w.msg)
+		}
+		if w.status != 0 && e["status"] != w.status {
+			t.Errorf("entry %d status = %v, want %v", i, e["status"], w.status)
+		}
+	}
+	if entries[0]["request_id"] != "abc123" {
+		t.Errorf("request_id = %v, want abc123", entries[0]["request_id"])
+	}
+}
+
+func TestLoggingTransport_RedactsSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var log bytes.Buffer
+	client := &http.Client{Transport: NewLoggingTransport(http.DefaultTransport, slog.New(slog.NewJSONHandler(&log, nil)))}
+	resp, err := client.Get(server.URL + "/download?access_token=s3cret&page=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if strings.Contains(log.String(), "s3cret") {
+		t.Errorf("log holds the token: %s", log.String())
+	}
+	if !strings.Contains(log.String(), "page=2") {
+		t.Errorf("log lost a harmless query value: %s", log.String())
+	}
+}
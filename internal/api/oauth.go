@@ -0,0 +1,235 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/redact"
+)
+
+const (
+	// OAuthTokenURL is the Bitbucket Cloud OAuth 2.0 token endpoint
+	OAuthTokenURL = "https://bitbucket.org/site/oauth2/access_token"
+
+	// tokenExpiryMargin renews access tokens this long before they expire,
+	// so that they don't expire in flight
+	tokenExpiryMargin = time.Minute
+)
+
+// OAuthToken is an OAuth 2.0 token as returned by the token endpoint and
+// kept in the credential store. The client credentials are kept with it so
+// that it can be refreshed.
+type OAuthToken struct {
+	AccessToken  string    `json:"access_token"`
+	TokenType    string    `json:"token_type,omitempty"`
+	ExpiresIn    int       `json:"expires_in,omitempty"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	Scopes       string    `json:"scopes,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at,omitzero"`
+	ClientID     string    `json:"client_id,omitempty"`
+	ClientSecret string    `json:"client_secret,omitempty"`
+}
+
+// SetExpiry sets ExpiresAt from ExpiresIn, counting from now.
+func (t *OAuthToken) SetExpiry() {
+	if t.ExpiresIn > 0 {
+		t.ExpiresAt = time.Now().Add(time.Duration(t.ExpiresIn) * time.Second)
+	}
+}
+
+// Expired reports whether the access token has expired or is about to.
+// Tokens whose expiry is unknown are assumed to be valid.
+func (t *OAuthToken) Expired() bool {
+	return !t.ExpiresAt.IsZero() && time.Now().Add(tokenExpiryMargin).After(t.ExpiresAt)
+}
+
+// TokenSource supplies the bearer token of a client and renews it when the
+// server rejects it.
+type TokenSource interface {
+	// Token returns the token to send
+	Token(ctx context.Context) (string, error)
+	// Refresh returns a new token to replace stale, which was rejected
+	Refresh(ctx context.Context, stale string) (string, error)
+}
+
+// OAuthTokenSource supplies an OAuth access token, refreshing it when it
+// expires. Refreshes are serialised, so concurrent requests that find the
+// token expired share a single refresh.
+type OAuthTokenSource struct {
+	// TokenURL is the token endpoint; OAuthTokenURL if empty
+	TokenURL string
+	// HTTPClient makes the refresh requests; a client with DefaultTimeout if nil
+	HTTPClient *http.Client
+	// OnRefresh is called with each new token, e.g. to store it. If it
+	// fails, the failure is logged and the new token is still used for the
+	// rest of the run.
+	OnRefresh func(*OAuthToken) error
+	// Logger records refresh failures; entries are discarded if nil
+	Logger *slog.Logger
+
+	mu    sync.Mutex
+	token OAuthToken
+}
+
+// NewOAuthTokenSource creates a token source starting from token.
+func NewOAuthTokenSource(token OAuthToken, onRefresh func(*OAuthToken) error) *OAuthTokenSource {
+	return &OAuthTokenSource{token: token, OnRefresh: onRefresh}
+}
+
+// Token returns the access token, refreshing it first if it has expired.
+func (s *OAuthTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token.Expired() && s.token.RefreshToken != "" {
+		if err := s.refreshLocked(ctx); err != nil {
+			return "", err
+		}
+	}
+	return s.token.AccessToken, nil
+}
+
+// Refresh renews the access token after stale was rejected. If another
+// request renewed it in the meantime, the new token is returned as is.
+func (s *OAuthTokenSource) Refresh(ctx context.Context, stale string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token.AccessToken != stale {
+		return s.token.AccessToken, nil
+	}
+	if s.token.RefreshToken == "" {
+		return "", fmt.Errorf("the access token was rejected and has no refresh token. Run 'bb auth login' to re-authenticate")
+	}
+	if err := s.refreshLocked(ctx); err != nil {
+		return "", err
+	}
+	return s.token.AccessToken, nil
+}
+
+func (s *OAuthTokenSource) refreshLocked(ctx context.Context) error {
+	if s.token.ClientID == "" || s.token.ClientSecret == "" {
+		return fmt.Errorf("cannot refresh the OAuth token without the OAuth consumer; set BB_OAUTH_CLIENT_ID and BB_OAUTH_CLIENT_SECRET or run 'bb auth login'")
+	}
+
+	tokenURL := s.TokenURL
+	if tokenURL == "" {
+		tokenURL = OAuthTokenURL
+	}
+	httpClient := s.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	data := url.Values{}
+	data.Set("grant_type", "refresh_token")
+	data.Set("refresh_token", s.token.RefreshToken)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(s.token.ClientID, s.token.ClientSecret)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not refresh the OAuth token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not refresh the OAuth token: status %d. Run 'bb auth login' to re-authenticate", resp.StatusCode)
+	}
+
+	var fresh OAuthToken
+	if err := json.NewDecoder(resp.Body).Decode(&fresh); err != nil {
+		return fmt.Errorf("could not parse the refreshed OAuth token: %w", err)
+	}
+	if fresh.AccessToken == "" {
+		return fmt.Errorf("could not refresh the OAuth token: no access token in the response")
+	}
+	if fresh.RefreshToken == "" {
+		fresh.RefreshToken = s.token.RefreshToken
+	}
+	fresh.ClientID = s.token.ClientID
+	fresh.ClientSecret = s.token.ClientSecret
+	fresh.SetExpiry()
+
+	s.token = fresh
+	if s.OnRefresh != nil {
+		if err := s.OnRefresh(&fresh); err != nil && s.Logger != nil {
+			s.Logger.Warn("could not store the refreshed token; the next run may need 'bb auth login'", "error", redact.Text(err.Error()))
+		}
+	}
+	return nil
+}
+
+// WithTokenSource authenticates requests with bearer tokens from ts. A
+// request rejected with 401 Unauthorized is retried once with a renewed
+// token.
+func WithTokenSource(ts TokenSource) ClientOption {
+	return func(c *Client) {
+		c.tokenSource = ts
+	}
+}
+
+// authTransport sets the Authorization header of each request from a token
+// source, renewing the token when the server rejects it
+type authTransport struct {
+	base   http.RoundTripper
+	source TokenSource
+	logger *slog.Logger
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(withBearer(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	// A request whose body can't be replayed can't be retried
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	fresh, err := t.source.Refresh(req.Context(), token)
+	if err != nil {
+		t.logger.Warn("token refresh failed", "error", redact.Text(err.Error()))
+		resp.Body.Close()
+		return nil, err
+	}
+	if fresh == token {
+		return resp, nil
+	}
+	resp.Body.Close()
+	t.logger.Info("retrying with a renewed token", "method", req.Method, "url", redact.URL(req.URL))
+
+	retry := withBearer(req, fresh)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		retry.Body = body
+	}
+	return t.base.RoundTrip(retry)
+}
+
+// withBearer returns a copy of req authenticated with token.
+func withBearer(req *http.Request, token string) *http.Request {
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "Bearer "+token)
+	return r
+}
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTokenServer returns a token endpoint that issues "fresh-token" and
+// counts the refreshes it performs.
+func newTokenServer(t *testing.T, refreshes *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(refreshes, 1)
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("could not parse form: %v", err)
+		}
+		if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "refresh-token" {
+			t.Errorf("unexpected refresh request: %v", r.Form)
+		}
+		if id, secret, ok := r.BasicAuth(); !ok || id != "client-id" || secret != "client-secret" {
+			t.Errorf("expected client credentials, got %q %q", id, secret)
+		}
+		// Give concurrent requests time to pile up behind the refresh
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token": "fresh-token", "expires_in": 7200, "token_type": "bearer"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestTokenSource(tokenURL string, token OAuthToken, stored *OAuthToken) *OAuthTokenSource {
+	token.RefreshToken = "refresh-token"
+	token.ClientID = "client-id"
+	token.ClientSecret = "client-secret"
+	ts := NewOAuthTokenSource(token, func(fresh *OAuthToken) error {
+		*stored = *fresh
+		return nil
+	})
+	ts.TokenURL = tokenURL
+	return ts
+}
+
+func TestOAuthTokenSource_RefreshesOnUnauthorizedOnce(t *testing.T) {
+	var refreshes int32
+	tokenServer := newTokenServer(t, &refreshes)
+
+	var apiCalls int32
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&apiCalls, 1)
+		if r.Header.Get("Authorization") != "Bearer fresh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": {"message": "Access token expired"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"username": "alice"}`))
+	}))
+	defer apiServer.Close()
+
+	var stored OAuthToken
+	ts := newTestTokenSource(tokenServer.URL, OAuthToken{AccessToken: "stale-token"}, &stored)
+	client := NewClient(WithBaseURL(apiServer.URL), WithTokenSource(ts))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			user, err := client.GetCurrentUser(context.Background())
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if user.Username != "alice" {
+				t.Errorf("expected alice, got %q", user.Username)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if refreshes != 1 {
+		t.Errorf("expected 1 refresh, got %d", refreshes)
+	}
+	if stored.AccessToken != "fresh-token" {
+		t.Errorf("expected the refreshed token to be stored, got %q", stored.AccessToken)
+	}
+	if stored.RefreshToken != "refresh-token" || stored.ClientID != "client-id" {
+		t.Errorf("expected the refresh token and client to be kept, got %+v", stored)
+	}
+	if stored.ExpiresAt.IsZero() {
+		t.Error("expected ExpiresAt to be set")
+	}
+}
+
+func TestOAuthTokenSource_RefreshesExpiredTokenBeforeRequest(t *testing.T) {
+	var refreshes int32
+	tokenServer := newTokenServer(t, &refreshes)
+
+	var auth string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte(`{}`))
+	}))
+	defer apiServer.Close()
+
+	var stored OAuthToken
+	expired := OAuthToken{AccessToken: "stale-token", ExpiresAt: time.Now().Add(-time.Hour)}
+	ts := newTestTokenSource(tokenServer.URL, expired, &stored)
+	client := NewClient(WithBaseURL(apiServer.URL), WithTokenSource(ts))
+
+	if _, err := client.Get(context.Background(), "/user", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if auth != "Bearer fresh-token" {
+		t.Errorf("expected the refreshed token to be sent, got %q", auth)
+	}
+	if refreshes != 1 {
+		t.Errorf("expected 1 refresh, got %d", refreshes)
+	}
+}
+
+func TestOAuthTokenSource_LogsStoreFailure(t *testing.T) {
+	var refreshes int32
+	tokenServer := newTokenServer(t, &refreshes)
+
+	var auth string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte(`{}`))
+	}))
+	defer apiServer.Close()
+
+	var logged bytes.Buffer
+	expired := OAuthToken{AccessToken: "stale-token", ExpiresAt: time.Now().Add(-time.Hour)}
+	ts := newTestTokenSource(tokenServer.URL, expired, &OAuthToken{})
+	ts.OnRefresh = func(*OAuthToken) error { return errors.New("keyring locked") }
+	ts.Logger = slog.New(slog.NewTextHandler(&logged, nil))
+	client := NewClient(WithBaseURL(apiServer.URL), WithTokenSource(ts))
+
+	if _, err := client.Get(context.Background(), "/user", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if auth != "Bearer fresh-token" {
+		t.Errorf("expected the refreshed token to be used though it wasn't stored, got %q", auth)
+	}
+	if !strings.Contains(logged.String(), "could not store the refreshed token") || !strings.Contains(logged.String(), "keyring locked") {
+		t.Errorf("expected the store failure to be logged, got %q", logged.String())
+	}
+}
+
+func TestOAuthTokenSource_ReplaysRequestBody(t *testing.T) {
+	var refreshes int32
+	tokenServer := newTokenServer(t, &refreshes)
+
+	var bodies []string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if r.Header.Get("Authorization") != "Bearer fresh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{}`))
+	}))
+	defer apiServer.Close()
+
+	var stored OAuthToken
+	ts := newTestTokenSource(tokenServer.URL, OAuthToken{AccessToken: "stale-token"}, &stored)
+	client := NewClient(WithBaseURL(apiServer.URL), WithTokenSource(ts))
+
+	if _, err := client.Post(context.Background(), "/things", map[string]string{"name": "x"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bodies) != 2 || bodies[0] != bodies[1] || bodies[1] == "" {
+		t.Errorf("expected the body to be sent twice, got %q", bodies)
+	}
+}
+
+func TestOAuthTokenSource_NoRefreshToken(t *testing.T) {
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer apiServer.Close()
+
+	ts := NewOAuthTokenSource(OAuthToken{AccessToken: "stale-token"}, nil)
+	client := NewClient(WithBaseURL(apiServer.URL), WithTokenSource(ts))
+
+	if _, err := client.Get(context.Background(), "/user", nil); err == nil {
+		t.Error("expected an error when the token can't be refreshed")
+	}
+}
+
+func TestOAuthToken_JSONKeepsLegacyFields(t *testing.T) {
+	var token OAuthToken
+	data := `{"access_token": "a", "token_type": "bearer", "expires_in": 7200, "refresh_token": "r", "scopes": "account"}`
+	if err := json.Unmarshal([]byte(data), &token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token.AccessToken != "a" || token.RefreshToken != "r" || token.ExpiresIn != 7200 {
+		t.Errorf("unexpected token: %+v", token)
+	}
+	if token.Expired() {
+		t.Error("a token without a known expiry should not be treated as expired")
+	}
+}
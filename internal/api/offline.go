@@ -0,0 +1,190 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/redact"
+)
+
+// MaxCachedResponse is the largest response body kept in a ResponseCache.
+// Larger ones, such as big diffs and logs, are only ever fetched.
+const MaxCachedResponse = 1 << 20
+
+// ErrOffline is returned for requests that can't be made offline: changes,
+// and reads whose response isn't cached
+var ErrOffline = errors.New("not available offline")
+
+// CachedResponse is a response to a GET request kept to be served offline
+type CachedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	FetchedAt  time.Time   `json:"fetched_at"`
+}
+
+// ResponseCache stores the responses to GET requests, by request
+type ResponseCache interface {
+	Get(req *http.Request) (*CachedResponse, bool)
+	Put(req *http.Request, resp *CachedResponse)
+}
+
+// CacheMode is when a client serves responses from its ResponseCache
+type CacheMode int
+
+const (
+	// CacheFallback serves cached responses when the server can't be reached
+	CacheFallback CacheMode = iota
+	// CacheOnly serves every request from the cache, never the network
+	CacheOnly
+)
+
+// CacheHook is called when a response is served from the cache instead of
+// the network, with the error that made it fall back, nil when offline
+type CacheHook func(resp *CachedResponse, err error)
+
+// WithResponseCache keeps the JSON responses to GET requests in cache, and
+// serves them from it in mode. hook, if not nil, is told of each response
+// served from the cache.
+func WithResponseCache(cache ResponseCache, mode CacheMode, hook CacheHook) ClientOption {
+	return func(c *Client) {
+		c.cache = &cacheTransport{cache: cache, mode: mode, hook: hook}
+	}
+}
+
+// cacheTransport answers requests from a ResponseCache when offline, or
+// when the network fails, and fills it otherwise
+type cacheTransport struct {
+	base   http.RoundTripper
+	cache  ResponseCache
+	mode   CacheMode
+	hook   CacheHook
+	hooks  []RequestHook
+	logger *slog.Logger
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.mode == CacheOnly {
+		if req.Method != http.MethodGet {
+			return nil, fmt.Errorf("%w: cannot %s %s", ErrOffline, req.Method, req.URL.Path)
+		}
+		if resp, ok := t.serve(req, nil); ok {
+			return resp, nil
+		}
+		t.logger.Info("not in the response cache", "url", redact.URL(req.URL))
+		return nil, fmt.Errorf("%w: %s has not been fetched before", ErrOffline, req.URL.Path)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if req.Method != http.MethodGet {
+		return resp, err
+	}
+	if err != nil {
+		if unreachable(err) {
+			if cached, ok := t.serve(req, err); ok {
+				return cached, nil
+			}
+		}
+		return resp, err
+	}
+	if resp.StatusCode == http.StatusOK && isJSON(resp.Header) {
+		t.store(req, resp)
+	}
+	return resp, nil
+}
+
+// serve returns the cached response to req, if there is one
+func (t *cacheTransport) serve(req *http.Request, cause error) (*http.Response, bool) {
+	start := time.Now()
+	cached, ok := t.cache.Get(req)
+	if !ok {
+		return nil, false
+	}
+	if cause != nil {
+		t.logger.Warn("serving cached response, the server can't be reached",
+			"url", redact.URL(req.URL), "fetched_at", cached.FetchedAt, "error", redact.Text(cause.Error()))
+	} else {
+		t.logger.Info("serving cached response offline", "url", redact.URL(req.URL), "fetched_at", cached.FetchedAt)
+	}
+	if t.hook != nil {
+		t.hook(cached, cause)
+	}
+	info := RequestInfo{
+		Method:     req.Method,
+		URL:        req.URL,
+		StatusCode: cached.StatusCode,
+		Header:     cached.Header,
+		Duration:   time.Since(start),
+		Cached:     true,
+	}
+	for _, hook := range t.hooks {
+		hook(info)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", cached.StatusCode, http.StatusText(cached.StatusCode)),
+		StatusCode:    cached.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        cached.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       req,
+	}, true
+}
+
+// store caches resp, leaving its body to be read again. Bodies too large to
+// cache are passed on as they are read.
+func (t *cacheTransport) store(req *http.Request, resp *http.Response) {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxCachedResponse+1))
+	if err != nil || len(body) > MaxCachedResponse {
+		if err == nil {
+			t.logger.Debug("response too large to cache", "url", redact.URL(req.URL))
+		}
+		resp.Body = readCloser{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// Only the headers that describe the content are worth keeping
+	header := http.Header{}
+	for _, key := range []string{"Content-Type", "Link"} {
+		if v := resp.Header.Values(key); len(v) > 0 {
+			header[key] = v
+		}
+	}
+	t.logger.Debug("response cached", "url", redact.URL(req.URL), "bytes", len(body))
+	t.cache.Put(req, &CachedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     header,
+		Body:       body,
+		FetchedAt:  time.Now(),
+	})
+}
+
+// readCloser reads from one reader and closes another
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// unreachable reports whether err means the server couldn't be reached at
+// all, as opposed to it answering with an error
+func unreachable(err error) bool {
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	return errors.As(err, &opErr) || errors.As(err, &dnsErr)
+}
+
+func isJSON(header http.Header) bool {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryCache is a ResponseCache kept in memory
+type memoryCache struct {
+	mu        sync.Mutex
+	responses map[string]*CachedResponse
+}
+
+func (m *memoryCache) Get(req *http.Request) (*CachedResponse, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	resp, ok := m.responses[req.URL.String()]
+	return resp, ok
+}
+
+func (m *memoryCache) Put(req *http.Request, resp *CachedResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responses[req.URL.String()] = resp
+}
+
+func TestResponseCache(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write([]byte(`{"username": "alice"}`))
+	}))
+	cache := &memoryCache{responses: map[string]*CachedResponse{}}
+	var served []error
+	hook := func(resp *CachedResponse, err error) { served = append(served, err) }
+	ctx := context.Background()
+
+	// Online, responses are fetched and cached
+	online := NewClient(WithBaseURL(server.URL), WithResponseCache(cache, CacheFallback, hook))
+	if user, err := online.GetCurrentUser(ctx); err != nil || user.Username != "alice" {
+		t.Fatalf("GetCurrentUser() = %+v, %v", user, err)
+	}
+	if len(cache.responses) != 1 || calls != 1 || len(served) != 0 {
+		t.Fatalf("expected one cached response, got %d after %d calls", len(cache.responses), calls)
+	}
+	for _, resp := range cache.responses {
+		if time.Since(resp.FetchedAt) > time.Minute || resp.Header.Get("Content-Type") == "" {
+			t.Errorf("cached response = %+v", resp)
+		}
+	}
+
+	// Offline, they are served from the cache, and nothing else is
+	offline := NewClient(WithBaseURL(server.URL), WithResponseCache(cache, CacheOnly, hook))
+	if user, err := offline.GetCurrentUser(ctx); err != nil || user.Username != "alice" {
+		t.Fatalf("offline GetCurrentUser() = %+v, %v", user, err)
+	}
+	if calls != 1 || len(served) != 1 || served[0] != nil {
+		t.Errorf("expected the response to be served from the cache, got %d calls, served %v", calls, served)
+	}
+	if _, err := offline.Get(ctx, "/repositories/team", nil); !errors.Is(err, ErrOffline) {
+		t.Errorf("uncached Get() error = %v, want ErrOffline", err)
+	}
+	if _, err := offline.Post(ctx, "/user", nil); !errors.Is(err, ErrOffline) {
+		t.Errorf("offline Post() error = %v, want ErrOffline", err)
+	}
+
+	// When the server can't be reached, cached responses are served
+	server.Close()
+	if user, err := online.GetCurrentUser(ctx); err != nil || user.Username != "alice" {
+		t.Fatalf("GetCurrentUser() with the server down = %+v, %v", user, err)
+	}
+	if len(served) != 2 || served[1] == nil {
+		t.Errorf("expected a fallback with its cause, got %v", served)
+	}
+	if _, err := online.Get(ctx, "/repositories/team", nil); err == nil || errors.Is(err, ErrOffline) {
+		t.Errorf("uncached Get() with the server down error = %v, want the network error", err)
+	}
+}
+
+func TestResponseCache_SkipsUncacheable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/diff":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("+added"))
+		case "/missing":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"message": "Not found"}}`))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(make([]byte, MaxCachedResponse+10))
+		}
+	}))
+	defer server.Close()
+	cache := &memoryCache{responses: map[string]*CachedResponse{}}
+	client := NewClient(WithBaseURL(server.URL), WithResponseCache(cache, CacheFallback, nil))
+	ctx := context.Background()
+
+	if resp, err := client.Get(ctx, "/diff", nil); err != nil || string(resp.Body) != "+added" {
+		t.Errorf("Get(/diff) = %v, %v", resp, err)
+	}
+	client.Get(ctx, "/missing", nil)
+	// A body too large to cache is still read whole
+	if resp, err := client.Get(ctx, "/large", nil); err != nil || len(resp.Body) != MaxCachedResponse+10 {
+		t.Errorf("Get(/large) read %d bytes, %v", len(resp.Body), err)
+	}
+	if len(cache.responses) != 0 {
+		t.Errorf("expected nothing to be cached, got %d responses", len(cache.responses))
+	}
+}
+
+func TestResponseCache_ReportsCachedRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"username": "alice"}`))
+	}))
+	defer server.Close()
+	cache := &memoryCache{responses: map[string]*CachedResponse{}}
+	var infos []RequestInfo
+	record := WithRequestHook(func(info RequestInfo) { infos = append(infos, info) })
+	ctx := context.Background()
+
+	online := NewClient(WithBaseURL(server.URL), WithResponseCache(cache, CacheFallback, nil), record)
+	if _, err := online.GetCurrentUser(ctx); err != nil {
+		t.Fatal(err)
+	}
+	offline := NewClient(WithBaseURL(server.URL), WithResponseCache(cache, CacheOnly, nil), record)
+	if _, err := offline.GetCurrentUser(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(infos) != 2 {
+		t.Fatalf("expected 2 requests reported, got %d", len(infos))
+	}
+	if infos[0].Cached {
+		t.Error("expected the fetched response not to be reported as cached")
+	}
+	if !infos[1].Cached || infos[1].StatusCode != http.StatusOK || infos[1].URL.Path != "/user" {
+		t.Errorf("cached request reported as %+v", infos[1])
+	}
+}
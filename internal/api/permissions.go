@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// RepositoryUserPermission is the permission a user has been granted
+// explicitly on a repository
+type RepositoryUserPermission struct {
+	Permission string `json:"permission"` // read, write, admin, or none
+	User       *User  `json:"user"`
+}
+
+// ListRepositoryUserPermissions lists every user granted a permission
+// explicitly on a repository, following the pages of results. Permissions
+// users have through groups or the workspace aren't included.
+func (c *Client) ListRepositoryUserPermissions(ctx context.Context, workspace, repoSlug string) ([]RepositoryUserPermission, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/permissions-config/users", workspace, repoSlug)
+
+	var permissions []RepositoryUserPermission
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("pagelen", "100")
+
+		resp, err := c.Get(ctx, path, query)
+		if err != nil {
+			return nil, err
+		}
+		result, err := ParseResponse[*Paginated[RepositoryUserPermission]](resp)
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, result.Values...)
+		if result.Next == "" {
+			return permissions, nil
+		}
+	}
+}
+
+// repositoryPermissionRequest is the API request body for granting a
+// permission on a repository
+type repositoryPermissionRequest struct {
+	Permission string `json:"permission"`
+}
+
+// GrantRepositoryUserPermission grants a user, given by UUID or account ID,
+// permission on a repository, replacing any permission granted before
+func (c *Client) GrantRepositoryUserPermission(ctx context.Context, workspace, repoSlug, userID, permission string) error {
+	path := fmt.Sprintf("/repositories/%s/%s/permissions-config/users/%s", workspace, repoSlug, url.PathEscape(userID))
+
+	_, err := c.Put(ctx, path, repositoryPermissionRequest{Permission: permission})
+	return err
+}
+
+// RevokeRepositoryUserPermission removes the permission granted explicitly
+// to a user, given by UUID or account ID, on a repository
+func (c *Client) RevokeRepositoryUserPermission(ctx context.Context, workspace, repoSlug, userID string) error {
+	path := fmt.Sprintf("/repositories/%s/%s/permissions-config/users/%s", workspace, repoSlug, url.PathEscape(userID))
+
+	_, err := c.Delete(ctx, path)
+	return err
+}
+
+// GrantRepositoryGroupPermission grants a workspace group permission on a
+// repository, replacing any permission granted before
+func (c *Client) GrantRepositoryGroupPermission(ctx context.Context, workspace, repoSlug, groupSlug, permission string) error {
+	path := fmt.Sprintf("/repositories/%s/%s/permissions-config/groups/%s", workspace, repoSlug, url.PathEscape(groupSlug))
+
+	_, err := c.Put(ctx, path, repositoryPermissionRequest{Permission: permission})
+	return err
+}
+
+// RevokeRepositoryGroupPermission removes the permission granted to a
+// workspace group on a repository
+func (c *Client) RevokeRepositoryGroupPermission(ctx context.Context, workspace, repoSlug, groupSlug string) error {
+	path := fmt.Sprintf("/repositories/%s/%s/permissions-config/groups/%s", workspace, repoSlug, url.PathEscape(groupSlug))
+
+	_, err := c.Delete(ctx, path)
+	return err
+}
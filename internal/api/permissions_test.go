@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestListRepositoryUserPermissions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/workspace/repo/permissions-config/users" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			fmt.Fprint(w, `{"next": "https://api.bitbucket.org/next", "values": [
+				{"permission": "admin", "user": {"uuid": "{1}", "display_name": "Alice"}}
+			]}`)
+			return
+		}
+		fmt.Fprint(w, `{"values": [{"permission": "read", "user": {"uuid": "{2}", "display_name": "Bob"}}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	permissions, err := client.ListRepositoryUserPermissions(context.Background(), "workspace", "repo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(permissions) != 2 {
+		t.Fatalf("expected 2 permissions, got %d", len(permissions))
+	}
+	if permissions[0].Permission != "admin" || permissions[0].User.DisplayName != "Alice" {
+		t.Errorf("unexpected first permission %+v", permissions[0])
+	}
+	if permissions[1].Permission != "read" || permissions[1].User.UUID != "{2}" {
+		t.Errorf("unexpected second permission %+v", permissions[1])
+	}
+}
+
+func TestRepositoryPermissionGrants(t *testing.T) {
+	tests := []struct {
+		name       string
+		call       func(c *Client) error
+		wantMethod string
+		wantPath   string
+		wantBody   string
+	}{
+		{
+			name: "grant user",
+			call: func(c *Client) error {
+				return c.GrantRepositoryUserPermission(context.Background(), "workspace", "repo", "{abc}", "write")
+			},
+			wantMethod: http.MethodPut,
+			wantPath:   "/repositories/workspace/repo/permissions-config/users/%7Babc%7D",
+			wantBody:   `{"permission":"write"}`,
+		},
+		{
+			name: "revoke user",
+			call: func(c *Client) error {
+				return c.RevokeRepositoryUserPermission(context.Background(), "workspace", "repo", "557058:abc")
+			},
+			wantMethod: http.MethodDelete,
+			wantPath:   "/repositories/workspace/repo/permissions-config/users/557058:abc",
+		},
+		{
+			name: "grant group",
+			call: func(c *Client) error {
+				return c.GrantRepositoryGroupPermission(context.Background(), "workspace", "repo", "developers", "admin")
+			},
+			wantMethod: http.MethodPut,
+			wantPath:   "/repositories/workspace/repo/permissions-config/groups/developers",
+			wantBody:   `{"permission":"admin"}`,
+		},
+		{
+			name: "revoke group",
+			call: func(c *Client) error {
+				return c.RevokeRepositoryGroupPermission(context.Background(), "workspace", "repo", "developers")
+			},
+			wantMethod: http.MethodDelete,
+			wantPath:   "/repositories/workspace/repo/permissions-config/groups/developers",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tt.wantMethod {
+					t.Errorf("expected %s method, got %s", tt.wantMethod, r.Method)
+				}
+				if r.URL.EscapedPath() != tt.wantPath {
+					t.Errorf("expected path %q, got %q", tt.wantPath, r.URL.EscapedPath())
+				}
+				body, _ := io.ReadAll(r.Body)
+				if got := strings.TrimSpace(string(body)); got != tt.wantBody {
+					t.Errorf("expected body %q, got %q", tt.wantBody, got)
+				}
+				if r.Method == http.MethodDelete {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"permission": "write"}`))
+			}))
+			defer server.Close()
+
+			if err := tt.call(NewClient(WithBaseURL(server.URL))); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -2,9 +2,13 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -67,17 +71,36 @@ type PipelineStateResult struct {
 type PipelineLinks struct {
 	Self  *Link `json:"self,omitempty"`
 	Steps *Link `json:"steps,omitempty"`
+	HTML  *Link `json:"html,omitempty"`
 }
 
 // PipelineStep represents a step in a pipeline
 type PipelineStep struct {
-	Type        string             `json:"type"`
-	UUID        string             `json:"uuid"`
-	Name        string             `json:"name,omitempty"`
-	StartedOn   *time.Time         `json:"started_on,omitempty"`
-	CompletedOn *time.Time         `json:"completed_on,omitempty"`
-	State       *PipelineStepState `json:"state,omitempty"`
-	Image       *PipelineImage     `json:"image,omitempty"`
+	Type              string              `json:"type"`
+	UUID              string              `json:"uuid"`
+	Name              string              `json:"name,omitempty"`
+	StartedOn         *time.Time          `json:"started_on,omitempty"`
+	CompletedOn       *time.Time          `json:"completed_on,omitempty"`
+	DurationInSeconds int                 `json:"duration_in_seconds,omitempty"`
+	State             *PipelineStepState  `json:"state,omitempty"`
+	Image             *PipelineImage      `json:"image,omitempty"`
+	Trigger           *PipelineTrigger    `json:"trigger,omitempty"` // pipeline_step_trigger_automatic, pipeline_step_trigger_manual
+	Stage             *PipelineStage      `json:"stage,omitempty"`
+	Caches            []PipelineStepCache `json:"caches,omitempty"`
+}
+
+// PipelineStage is a stage of a pipeline, which groups steps that deploy
+// together
+type PipelineStage struct {
+	UUID string `json:"uuid,omitempty"`
+	Name string `json:"name"`
+}
+
+// PipelineStepCache is a cache a step declares, and whether an existing
+// copy of it was restored when the step ran
+type PipelineStepCache struct {
+	Name string `json:"name"`
+	Hit  bool   `json:"hit"`
 }
 
 // PipelineStepState represents the state of a pipeline step
@@ -94,13 +117,26 @@ type PipelineImage struct {
 
 // PipelineListOptions are options for listing pipelines
 type PipelineListOptions struct {
-	Status string // Filter by status
-	Sort   string // Sort field
+	Status      string // Filter by status
+	Branch      string // Filter by the branch the pipeline ran on
+	CreatorUUID string // Filter by the UUID of the user who started the pipeline
+	Sort        string // Sort field
+	Page        int    // Page number
+	Limit       int    // Number of items per page (pagelen)
+}
+
+// PipelineVariable is a variable passed to a pipeline run. The values of
+// secured variables are masked in logs and can't be read back.
+type PipelineVariable struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Secured bool   `json:"secured,omitempty"`
 }
 
 // PipelineRunOptions are options for triggering a new pipeline run
 type PipelineRunOptions struct {
-	Target *PipelineTarget `json:"target"`
+	Target    *PipelineTarget    `json:"target"`
+	Variables []PipelineVariable `json:"variables,omitempty"`
 }
 
 // ListPipelines lists pipelines for a repository
@@ -112,9 +148,21 @@ func (c *Client) ListPipelines(ctx context.Context, workspace, repoSlug string,
 		if opts.Status != "" {
 			query.Set("status", opts.Status)
 		}
+		if opts.Branch != "" {
+			query.Set("target.branch", opts.Branch)
+		}
+		if opts.CreatorUUID != "" {
+			query.Set("creator.uuid", opts.CreatorUUID)
+		}
 		if opts.Sort != "" {
 			query.Set("sort", opts.Sort)
 		}
+		if opts.Page > 0 {
+			query.Set("page", strconv.Itoa(opts.Page))
+		}
+		if opts.Limit > 0 {
+			query.Set("pagelen", strconv.Itoa(opts.Limit))
+		}
 	}
 
 	resp, err := c.Get(ctx, path, query)
@@ -157,7 +205,8 @@ func (c *Client) StopPipeline(ctx context.Context, workspace, repoSlug, pipeline
 	return err
 }
 
-// ListPipelineSteps lists steps for a pipeline
+// ListPipelineSteps lists the steps of a pipeline, all of them: the pages
+// of a pipeline with many steps are followed and returned as one
 func (c *Client) ListPipelineSteps(ctx context.Context, workspace, repoSlug, pipelineUUID string) (*Paginated[PipelineStep], error) {
 	path := fmt.Sprintf("/repositories/%s/%s/pipelines/%s/steps", workspace, repoSlug, pipelineUUID)
 
@@ -165,24 +214,110 @@ func (c *Client) ListPipelineSteps(ctx context.Context, workspace, repoSlug, pip
 	if err != nil {
 		return nil, err
 	}
+	steps, err := ParseResponse[*Paginated[PipelineStep]](resp)
+	if err != nil {
+		return nil, err
+	}
 
-	return ParseResponse[*Paginated[PipelineStep]](resp)
+	for next := steps.Next; next != ""; {
+		resp, err := c.Get(ctx, next, nil)
+		if err != nil {
+			return nil, err
+		}
+		page, err := ParseResponse[*Paginated[PipelineStep]](resp)
+		if err != nil {
+			return nil, err
+		}
+		steps.Values = append(steps.Values, page.Values...)
+		next = page.Next
+	}
+	steps.Next = ""
+	return steps, nil
 }
 
 // GetPipelineStepLog gets the log for a pipeline step
 func (c *Client) GetPipelineStepLog(ctx context.Context, workspace, repoSlug, pipelineUUID, stepUUID string) (string, error) {
+	return c.GetPipelineStepLogFrom(ctx, workspace, repoSlug, pipelineUUID, stepUUID, 0)
+}
+
+// GetPipelineStepLogFrom gets the log for a pipeline step from byte offset
+// on. See OpenPipelineStepLog.
+func (c *Client) GetPipelineStepLogFrom(ctx context.Context, workspace, repoSlug, pipelineUUID, stepUUID string, offset int64) (string, error) {
+	body, err := c.OpenPipelineStepLog(ctx, workspace, repoSlug, pipelineUUID, stepUUID, offset)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	log, err := io.ReadAll(body)
+	if err != nil {
+		return "", fmt.Errorf("could not read log: %w", err)
+	}
+	return string(log), nil
+}
+
+// OpenPipelineStepLog streams the log for a pipeline step from byte offset
+// on, so large logs needn't be held in memory. The caller must close it.
+// A Range request is used so only the bytes after offset are transferred,
+// and the log is empty when there is nothing past offset yet. Servers that
+// ignore the Range header send the whole log, which is cut at offset.
+func (c *Client) OpenPipelineStepLog(ctx context.Context, workspace, repoSlug, pipelineUUID, stepUUID string, offset int64) (io.ReadCloser, error) {
 	path := fmt.Sprintf("/repositories/%s/%s/pipelines/%s/steps/%s/log", workspace, repoSlug, pipelineUUID, stepUUID)
 
-	resp, err := c.Do(ctx, &Request{
-		Method: http.MethodGet,
-		Path:   path,
-		Headers: map[string]string{
-			"Accept": "text/plain",
-		},
+	headers := map[string]string{
+		"Accept": "text/plain",
+	}
+	if offset > 0 {
+		headers["Range"] = fmt.Sprintf("bytes=%d-", offset)
+	}
+
+	resp, err := c.DoStream(ctx, &Request{
+		Method:  http.MethodGet,
+		Path:    path,
+		Headers: headers,
 	})
 	if err != nil {
-		return "", err
+		var apiErr *APIError
+		if offset > 0 && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			return io.NopCloser(strings.NewReader("")), nil
+		}
+		return nil, err
+	}
+
+	if offset > 0 && resp.StatusCode != http.StatusPartialContent {
+		if _, err := io.CopyN(io.Discard, resp.Body, offset); err != nil && err != io.EOF {
+			resp.Body.Close()
+			return nil, fmt.Errorf("could not read log: %w", err)
+		}
+	}
+	return resp.Body, nil
+}
+
+// PipelinesConfig is the Pipelines configuration of a repository
+type PipelinesConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+// GetPipelinesConfig gets the Pipelines configuration of a repository
+func (c *Client) GetPipelinesConfig(ctx context.Context, workspace, repoSlug string) (*PipelinesConfig, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/pipelines_config", workspace, repoSlug)
+
+	resp, err := c.Get(ctx, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*PipelinesConfig](resp)
+}
+
+// UpdatePipelinesConfig turns Pipelines on or off for a repository
+func (c *Client) UpdatePipelinesConfig(ctx context.Context, workspace, repoSlug string, enabled bool) (*PipelinesConfig, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/pipelines_config", workspace, repoSlug)
+
+	resp, err := c.Put(ctx, path, &PipelinesConfig{Enabled: enabled})
+	if err != nil {
+		return nil, err
 	}
 
-	return string(resp.Body), nil
+	return ParseResponse[*PipelinesConfig](resp)
 }
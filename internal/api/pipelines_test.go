@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -100,6 +101,27 @@ func TestListPipelines(t *testing.T) {
 			statusCode: http.StatusOK,
 			wantCount:  0,
 		},
+		{
+			name:        "list with branch and creator filters",
+			workspace:   "myworkspace",
+			repoSlug:    "myrepo",
+			opts:        &PipelineListOptions{Branch: "feature/login", CreatorUUID: "{user-1}"},
+			expectedURL: "/repositories/myworkspace/myrepo/pipelines",
+			expectedQuery: map[string]string{
+				"target.branch": "feature/login",
+				"creator.uuid":  "{user-1}",
+			},
+			response: `{
+				"size": 1,
+				"page": 1,
+				"pagelen": 10,
+				"values": [
+					{"uuid": "{pipeline-1}", "build_number": 3}
+				]
+			}`,
+			statusCode: http.StatusOK,
+			wantCount:  1,
+		},
 		{
 			name:        "handles 401 unauthorized",
 			workspace:   "myworkspace",
@@ -779,6 +801,31 @@ func TestListPipelineSteps(t *testing.T) {
 	}
 }
 
+func TestListPipelineSteps_FollowsPages(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			w.Write([]byte(`{"values": [{"uuid": "{step-3}"}]}`))
+			return
+		}
+		fmt.Fprintf(w, `{"next": %q, "values": [{"uuid": "{step-1}"}, {"uuid": "{step-2}"}]}`, server.URL+r.URL.Path+"?page=2")
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	result, err := client.ListPipelineSteps(context.Background(), "myworkspace", "myrepo", "{pipeline-uuid}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Values) != 3 || result.Values[2].UUID != "{step-3}" {
+		t.Errorf("expected the steps of both pages, got %+v", result.Values)
+	}
+	if result.Next != "" {
+		t.Errorf("expected no next page, got %q", result.Next)
+	}
+}
+
 func TestGetPipelineStepLog(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -1373,3 +1420,190 @@ func TestRunPipelineRequestBody(t *testing.T) {
 		t.Errorf("expected selector pattern 'deploy-to-prod', got %v", selector["pattern"])
 	}
 }
+
+func TestGetPipelineStepLogFrom(t *testing.T) {
+	const log = "line 1\nline 2\nline 3\n"
+
+	tests := []struct {
+		name        string
+		offset      int64
+		honourRange bool
+		wantRange   string
+		wantLog     string
+	}{
+		{name: "whole log", offset: 0, honourRange: true, wantRange: "", wantLog: log},
+		{name: "partial content", offset: 7, honourRange: true, wantRange: "bytes=7-", wantLog: "line 2\nline 3\n"},
+		{name: "range ignored", offset: 7, honourRange: false, wantRange: "bytes=7-", wantLog: "line 2\nline 3\n"},
+		{name: "nothing new", offset: int64(len(log)), honourRange: true, wantRange: "bytes=21-", wantLog: ""},
+		{name: "nothing new, range ignored", offset: int64(len(log)), honourRange: false, wantRange: "bytes=21-", wantLog: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRange string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotRange = r.Header.Get("Range")
+				w.Header().Set("Content-Type", "text/plain")
+				if gotRange == "" || !tt.honourRange {
+					w.Write([]byte(log))
+					return
+				}
+				if tt.offset >= int64(len(log)) {
+					w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+					return
+				}
+				w.WriteHeader(http.StatusPartialContent)
+				w.Write([]byte(log[tt.offset:]))
+			}))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL))
+			got, err := client.GetPipelineStepLogFrom(context.Background(), "workspace", "repo", "{pipeline}", "{step}", tt.offset)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotRange != tt.wantRange {
+				t.Errorf("Range header = %q, want %q", gotRange, tt.wantRange)
+			}
+			if got != tt.wantLog {
+				t.Errorf("log = %q, want %q", got, tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestRunPipelineVariables(t *testing.T) {
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"uuid": "{uuid}", "build_number": 1}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+
+	run := func(vars []PipelineVariable) map[string]interface{} {
+		t.Helper()
+		opts := &PipelineRunOptions{
+			Target:    &PipelineTarget{Type: "pipeline_ref_target", RefType: "branch", RefName: "main"},
+			Variables: vars,
+		}
+		if _, err := client.RunPipeline(context.Background(), "workspace", "repo", opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(receivedBody, &body); err != nil {
+			t.Fatalf("failed to parse request body: %v", err)
+		}
+		return body
+	}
+
+	body := run([]PipelineVariable{
+		{Key: "ENV", Value: "staging"},
+		{Key: "TOKEN", Value: "s3cret", Secured: true},
+	})
+	vars, ok := body["variables"].([]interface{})
+	if !ok || len(vars) != 2 {
+		t.Fatalf("expected 2 variables, got %v", body["variables"])
+	}
+	first := vars[0].(map[string]interface{})
+	if first["key"] != "ENV" || first["value"] != "staging" {
+		t.Errorf("unexpected first variable: %v", first)
+	}
+	if _, ok := first["secured"]; ok {
+		t.Errorf("expected secured to be omitted for a plain variable, got %v", first)
+	}
+	second := vars[1].(map[string]interface{})
+	if second["key"] != "TOKEN" || second["secured"] != true {
+		t.Errorf("unexpected second variable: %v", second)
+	}
+
+	body = run(nil)
+	if _, ok := body["variables"]; ok {
+		t.Errorf("expected variables to be omitted, got %v", body["variables"])
+	}
+}
+
+func TestPipelineStepDetailsParsing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"values": [{
+				"uuid": "{step-uuid}",
+				"name": "Deploy to staging",
+				"duration_in_seconds": 95,
+				"trigger": {"type": "pipeline_step_trigger_manual"},
+				"stage": {"uuid": "{stage-uuid}", "name": "Staging"},
+				"caches": [{"name": "node", "hit": true}, {"name": "pip", "hit": false}]
+			}]
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	result, err := client.ListPipelineSteps(context.Background(), "myworkspace", "myrepo", "{pipeline-uuid}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Values) != 1 {
+		t.Fatalf("expected 1 step, got %d", len(result.Values))
+	}
+
+	step := result.Values[0]
+	if step.DurationInSeconds != 95 {
+		t.Errorf("expected duration 95, got %d", step.DurationInSeconds)
+	}
+	if step.Trigger == nil || step.Trigger.Type != "pipeline_step_trigger_manual" {
+		t.Errorf("expected manual trigger, got %+v", step.Trigger)
+	}
+	if step.Stage == nil || step.Stage.Name != "Staging" {
+		t.Errorf("expected stage Staging, got %+v", step.Stage)
+	}
+	if len(step.Caches) != 2 || step.Caches[0] != (PipelineStepCache{Name: "node", Hit: true}) || step.Caches[1].Hit {
+		t.Errorf("unexpected caches: %+v", step.Caches)
+	}
+}
+
+func TestUpdatePipelinesConfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/myworkspace/myrepo/pipelines_config" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`{"enabled": false}`))
+		case http.MethodPut:
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+			if body["enabled"] != true {
+				t.Errorf("enabled = %v, want true", body["enabled"])
+			}
+			w.Write([]byte(`{"enabled": true}`))
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	config, err := client.GetPipelinesConfig(context.Background(), "myworkspace", "myrepo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Enabled {
+		t.Error("expected Pipelines to be disabled")
+	}
+
+	config, err = client.UpdatePipelinesConfig(context.Background(), "myworkspace", "myrepo", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !config.Enabled {
+		t.Error("expected Pipelines to be enabled")
+	}
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ProxyConfig selects the proxies requests are sent through. Each empty
+// field falls back to its environment variable: HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY, or their lowercase forms.
+type ProxyConfig struct {
+	// HTTPProxy is the proxy for http:// URLs
+	HTTPProxy string
+	// HTTPSProxy is the proxy for https:// URLs
+	HTTPSProxy string
+	// NoProxy is a comma-separated list of hosts, domains, IPs and CIDR
+	// ranges that are reached directly, or "*" for all of them
+	NoProxy string
+}
+
+// ValidateProxyURL checks that value can be used as a proxy URL.
+func ValidateProxyURL(value string) error {
+	_, err := parseProxyURL(value)
+	return err
+}
+
+// WithProxy sends requests through the proxies in cfg.
+func WithProxy(cfg ProxyConfig) ClientOption {
+	return func(c *Client) {
+		c.proxy = &cfg
+	}
+}
+
+// ProxyFunc returns the function choosing the proxy of each request, for
+// use as http.Transport.Proxy.
+func (p ProxyConfig) ProxyFunc() func(*http.Request) (*url.URL, error) {
+	httpProxy := firstNonEmpty(p.HTTPProxy, getenvAny("HTTP_PROXY", "http_proxy"))
+	httpsProxy := firstNonEmpty(p.HTTPSProxy, getenvAny("HTTPS_PROXY", "https_proxy"))
+	noProxy := firstNonEmpty(p.NoProxy, getenvAny("NO_PROXY", "no_proxy"))
+
+	return func(req *http.Request) (*url.URL, error) {
+		proxy := httpProxy
+		if req.URL.Scheme == "https" {
+			proxy = httpsProxy
+		}
+		if proxy == "" || bypassProxy(req.URL, noProxy) {
+			return nil, nil
+		}
+		return parseProxyURL(proxy)
+	}
+}
+
+// parseProxyURL parses a proxy URL, assuming http:// when it has no scheme
+// as curl and git do.
+func parseProxyURL(value string) (*url.URL, error) {
+	if !strings.Contains(value, "://") {
+		value = "http://" + value
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL: %s", value)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL: %s (unsupported scheme %q)", value, u.Scheme)
+	}
+	return u, nil
+}
+
+// bypassProxy reports whether u should be reached directly according to
+// noProxy. Like Go's own handling of NO_PROXY, localhost and loopback
+// addresses are never proxied.
+func bypassProxy(u *url.URL, noProxy string) bool {
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	if ip != nil && ip.IsLoopback() {
+		return true
+	}
+
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			if p != port {
+				continue
+			}
+			entry = h
+		}
+		if entryIP := net.ParseIP(entry); entryIP != nil {
+			if ip != nil && entryIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		// "example.com" and ".example.com" both match example.com and its
+		// subdomains
+		domain := strings.TrimPrefix(entry, ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func getenvAny(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
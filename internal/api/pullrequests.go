@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -131,18 +132,31 @@ type PRComment struct {
 	Parent *struct {
 		ID int64 `json:"id"`
 	} `json:"parent,omitempty"`
+	Deleted    bool               `json:"deleted,omitempty"`
+	Resolution *CommentResolution `json:"resolution,omitempty"`
 	Links struct {
 		Self Link `json:"self"`
 		HTML Link `json:"html"`
 	} `json:"links"`
 }
 
+// CommentResolution records who resolved a comment thread, and when
+type CommentResolution struct {
+	User      User      `json:"user"`
+	CreatedOn time.Time `json:"created_on"`
+}
+
 // PRListOptions are options for listing pull requests
 type PRListOptions struct {
-	State  PRState // Filter by state (OPEN, MERGED, DECLINED)
-	Author string  // Filter by author username
-	Page   int     // Page number
-	Limit  int     // Number of items per page (pagelen)
+	State             PRState   // Filter by state (OPEN, MERGED, DECLINED)
+	States            []PRState // Filter by any of several states, instead of State
+	Author            string  // Filter by author username
+	Reviewer          string  // Filter by reviewer UUID
+	SourceBranch      string  // Filter by source branch name
+	DestinationBranch string  // Filter by destination branch name
+	Query             string  // Raw BBQL filter, combined with the others using AND
+	Page              int     // Page number
+	Limit             int     // Number of items per page (pagelen)
 }
 
 // PRCreateOptions are options for creating a pull request
@@ -195,9 +209,18 @@ func (c *Client) ListPullRequests(ctx context.Context, workspace, repoSlug strin
 		if opts.State != "" {
 			query.Set("state", string(opts.State))
 		}
-		if opts.Author != "" {
-			// Use q parameter for author filtering
-			query.Set("q", fmt.Sprintf("author.username=\"%s\"", opts.Author))
+		for _, state := range opts.States {
+			query.Add("state", string(state))
+		}
+		// Use q parameter for author, reviewer, branch and raw filtering
+		q := new(Query).
+			Eq("author.username", opts.Author).
+			Eq("reviewers.uuid", opts.Reviewer).
+			Eq("source.branch.name", opts.SourceBranch).
+			Eq("destination.branch.name", opts.DestinationBranch).
+			Raw(opts.Query)
+		if !q.Empty() {
+			query.Set("q", q.String())
 		}
 		if opts.Page > 0 {
 			query.Set("page", strconv.Itoa(opts.Page))
@@ -215,6 +238,76 @@ func (c *Client) ListPullRequests(ctx context.Context, workspace, repoSlug strin
 	return ParseResponse[*Paginated[PullRequest]](resp)
 }
 
+// prPageConcurrency is how many pages of pull requests ListAllPullRequests
+// reads at once
+const prPageConcurrency = 4
+
+// ListAllPullRequests lists every pull request matching opts, reading the
+// pages after the first concurrently
+func (c *Client) ListAllPullRequests(ctx context.Context, workspace, repoSlug string, opts *PRListOptions) ([]PullRequest, error) {
+	var pageOpts PRListOptions
+	if opts != nil {
+		pageOpts = *opts
+	}
+	pageOpts.Page = 1
+	pageOpts.Limit = 50
+
+	first, err := c.ListPullRequests(ctx, workspace, repoSlug, &pageOpts)
+	if err != nil {
+		return nil, err
+	}
+	if first.Next == "" {
+		return first.Values, nil
+	}
+	if first.Size == 0 || len(first.Values) == 0 {
+		// The size wasn't reported, so follow the pages one at a time
+		prs := first.Values
+		for result := first; result.Next != ""; {
+			pageOpts.Page++
+			if result, err = c.ListPullRequests(ctx, workspace, repoSlug, &pageOpts); err != nil {
+				return nil, err
+			}
+			prs = append(prs, result.Values...)
+		}
+		return prs, nil
+	}
+
+	pageLen := len(first.Values)
+	pages := make([][]PullRequest, (first.Size+pageLen-1)/pageLen)
+	pages[0] = first.Values
+	errs := make([]error, len(pages))
+
+	var wg sync.WaitGroup
+	limiter := make(chan struct{}, prPageConcurrency)
+	for i := 1; i < len(pages); i++ {
+		wg.Go(func() {
+			limiter <- struct{}{}
+			defer func() { <-limiter }()
+
+			o := pageOpts
+			o.Page = i + 1
+			result, err := c.ListPullRequests(ctx, workspace, repoSlug, &o)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			pages[i] = result.Values
+		})
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	var prs []PullRequest
+	for _, page := range pages {
+		prs = append(prs, page...)
+	}
+	return prs, nil
+}
+
 // GetPullRequest retrieves a single pull request
 func (c *Client) GetPullRequest(ctx context.Context, workspace, repoSlug string, prID int64) (*PullRequest, error) {
 	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%d", workspace, repoSlug, prID)
@@ -357,6 +450,35 @@ func (c *Client) GetPullRequestDiff(ctx context.Context, workspace, repoSlug str
 	return string(resp.Body), nil
 }
 
+// DiffStat summarizes the changes to one file in a diff
+type DiffStat struct {
+	Status       string `json:"status"` // added, removed, modified, renamed
+	LinesAdded   int    `json:"lines_added"`
+	LinesRemoved int    `json:"lines_removed"`
+	Old          *struct {
+		Path string `json:"path"`
+	} `json:"old,omitempty"`
+	New *struct {
+		Path string `json:"path"`
+	} `json:"new,omitempty"`
+}
+
+// GetPullRequestDiffStat retrieves the per-file summary of a pull request's
+// diff
+func (c *Client) GetPullRequestDiffStat(ctx context.Context, workspace, repoSlug string, prID int64) (*Paginated[DiffStat], error) {
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%d/diffstat", workspace, repoSlug, prID)
+
+	query := url.Values{}
+	query.Set("pagelen", "500")
+
+	resp, err := c.Get(ctx, path, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*Paginated[DiffStat]](resp)
+}
+
 // ListPRComments lists comments on a pull request
 func (c *Client) ListPRComments(ctx context.Context, workspace, repoSlug string, prID int64) (*Paginated[PRComment], error) {
 	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%d/comments", workspace, repoSlug, prID)
@@ -369,12 +491,39 @@ func (c *Client) ListPRComments(ctx context.Context, workspace, repoSlug string,
 	return ParseResponse[*Paginated[PRComment]](resp)
 }
 
+// ListAllPRComments lists every comment on a pull request, following the
+// pages of results
+func (c *Client) ListAllPRComments(ctx context.Context, workspace, repoSlug string, prID int64) ([]PRComment, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%d/comments", workspace, repoSlug, prID)
+
+	var comments []PRComment
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("pagelen", "100")
+
+		resp, err := c.Get(ctx, path, query)
+		if err != nil {
+			return nil, err
+		}
+		result, err := ParseResponse[*Paginated[PRComment]](resp)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, result.Values...)
+		if result.Next == "" {
+			return comments, nil
+		}
+	}
+}
+
 // AddPRCommentOptions are options for adding a comment to a pull request
 type AddPRCommentOptions struct {
 	Content string `json:"-"`      // The comment text
 	ParentID int64 `json:"-"`      // Optional: ID of parent comment for replies
 	Path     string `json:"-"`     // Optional: file path for inline comments
 	Line     int    `json:"-"`     // Optional: line number for inline comments
+	FromLine int    `json:"-"`     // Optional: line number in the old version, for inline comments on removed lines
 }
 
 // addPRCommentRequest is the actual API request body for adding a comment
@@ -386,7 +535,8 @@ type addPRCommentRequest struct {
 		ID int64 `json:"id"`
 	} `json:"parent,omitempty"`
 	Inline *struct {
-		To   int    `json:"to"`
+		From int    `json:"from,omitempty"`
+		To   int    `json:"to,omitempty"`
 		Path string `json:"path"`
 	} `json:"inline,omitempty"`
 }
@@ -406,9 +556,10 @@ func (c *Client) AddPRComment(ctx context.Context, workspace, repoSlug string, p
 
 	if opts.Path != "" {
 		reqBody.Inline = &struct {
-			To   int    `json:"to"`
+			From int    `json:"from,omitempty"`
+			To   int    `json:"to,omitempty"`
 			Path string `json:"path"`
-		}{To: opts.Line, Path: opts.Path}
+		}{From: opts.FromLine, To: opts.Line, Path: opts.Path}
 	}
 
 	resp, err := c.Post(ctx, path, reqBody)
@@ -419,6 +570,40 @@ func (c *Client) AddPRComment(ctx context.Context, workspace, repoSlug string, p
 	return ParseResponse[*PRComment](resp)
 }
 
+// GetPRComment gets a comment on a pull request
+func (c *Client) GetPRComment(ctx context.Context, workspace, repoSlug string, prID, commentID int64) (*PRComment, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%d/comments/%d", workspace, repoSlug, prID, commentID)
+
+	resp, err := c.Get(ctx, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*PRComment](resp)
+}
+
+// ResolvePRComment resolves the thread a comment on a pull request starts.
+// Replies can't be resolved on their own.
+func (c *Client) ResolvePRComment(ctx context.Context, workspace, repoSlug string, prID, commentID int64) (*CommentResolution, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%d/comments/%d/resolve", workspace, repoSlug, prID, commentID)
+
+	resp, err := c.Post(ctx, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*CommentResolution](resp)
+}
+
+// ReopenPRComment reopens the resolved thread a comment on a pull request
+// starts
+func (c *Client) ReopenPRComment(ctx context.Context, workspace, repoSlug string, prID, commentID int64) error {
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%d/comments/%d/resolve", workspace, repoSlug, prID, commentID)
+
+	_, err := c.Delete(ctx, path)
+	return err
+}
+
 // UpdatePullRequest updates an existing pull request
 func (c *Client) UpdatePullRequest(ctx context.Context, workspace, repoSlug string, prID int64, opts *PRCreateOptions) (*PullRequest, error) {
 	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%d", workspace, repoSlug, prID)
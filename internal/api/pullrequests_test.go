@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -80,6 +81,34 @@ func TestListPullRequests(t *testing.T) {
 			statusCode: http.StatusOK,
 			wantCount:  1,
 		},
+		{
+			name: "list with reviewer and branch filters",
+			opts: &PRListOptions{Reviewer: "{user-uuid}", SourceBranch: "feature", DestinationBranch: "main"},
+			expectedURL: "/repositories/myworkspace/myrepo/pullrequests",
+			expectedQuery: map[string]string{"q": `reviewers.uuid="{user-uuid}" AND source.branch.name="feature" AND destination.branch.name="main"`},
+			response: `{
+				"size": 1,
+				"page": 1,
+				"pagelen": 10,
+				"values": [{"id": 4, "title": "Review PR", "state": "OPEN"}]
+			}`,
+			statusCode: http.StatusOK,
+			wantCount:  1,
+		},
+		{
+			name: "list with raw query and destination branch",
+			opts: &PRListOptions{DestinationBranch: "main", Query: `title ~ "fix" OR title ~ "bug"`},
+			expectedURL: "/repositories/myworkspace/myrepo/pullrequests",
+			expectedQuery: map[string]string{"q": `destination.branch.name="main" AND (title ~ "fix" OR title ~ "bug")`},
+			response: `{
+				"size": 1,
+				"page": 1,
+				"pagelen": 10,
+				"values": [{"id": 5, "title": "Fix bug", "state": "OPEN"}]
+			}`,
+			statusCode: http.StatusOK,
+			wantCount:  1,
+		},
 		{
 			name: "handles 401 unauthorized",
 			opts: nil,
@@ -1071,6 +1100,35 @@ func TestListPRComments(t *testing.T) {
 	}
 }
 
+func TestListAllPRComments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			w.Write([]byte(`{"next": "https://api.bitbucket.org/next", "values": [
+				{"id": 1, "content": {"raw": "Thread"}, "resolution": {"user": {"display_name": "Carol"}, "created_on": "2024-01-03T00:00:00Z"}}
+			]}`))
+			return
+		}
+		w.Write([]byte(`{"values": [{"id": 2, "content": {"raw": ""}, "parent": {"id": 1}, "deleted": true}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	comments, err := client.ListAllPRComments(context.Background(), "workspace", "repo", 800)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 2 {
+		t.Fatalf("expected 2 comments, got %d", len(comments))
+	}
+	if r := comments[0].Resolution; r == nil || r.User.DisplayName != "Carol" {
+		t.Errorf("expected a resolution by Carol, got %+v", r)
+	}
+	if !comments[1].Deleted || comments[1].Parent == nil || comments[1].Parent.ID != 1 {
+		t.Errorf("unexpected reply: %+v", comments[1])
+	}
+}
+
 func TestAddPRComment(t *testing.T) {
 	var receivedBody []byte
 
@@ -1172,3 +1230,104 @@ func TestGetPullRequestStatuses(t *testing.T) {
 		t.Errorf("expected second status state 'INPROGRESS', got %q", statuses.Values[1].State)
 	}
 }
+
+func TestGetPullRequestDiffStat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/workspace/repo/pullrequests/1/diffstat" {
+			http.Error(w, "wrong endpoint", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"values": [
+				{"status": "modified", "lines_added": 10, "lines_removed": 2, "old": {"path": "main.go"}, "new": {"path": "main.go"}},
+				{"status": "added", "lines_added": 5, "lines_removed": 0, "new": {"path": "parser.go"}}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	diffStat, err := client.GetPullRequestDiffStat(context.Background(), "workspace", "repo", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(diffStat.Values) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(diffStat.Values))
+	}
+	if diffStat.Values[0].LinesAdded != 10 || diffStat.Values[0].LinesRemoved != 2 {
+		t.Errorf("unexpected line counts: %+v", diffStat.Values[0])
+	}
+	if diffStat.Values[1].Old != nil || diffStat.Values[1].New.Path != "parser.go" {
+		t.Errorf("unexpected paths for an added file: %+v", diffStat.Values[1])
+	}
+}
+
+func TestResolvePRComment(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"user": {"display_name": "Carol"}, "created_on": "2024-01-09T00:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	resolution, err := client.ResolvePRComment(context.Background(), "workspace", "repo", 7, 42)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolution.User.DisplayName != "Carol" || !resolution.CreatedOn.Equal(time.Date(2024, 1, 9, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected resolution %+v", resolution)
+	}
+	if err := client.ReopenPRComment(context.Background(), "workspace", "repo", 7, 42); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"POST /repositories/workspace/repo/pullrequests/7/comments/42/resolve",
+		"DELETE /repositories/workspace/repo/pullrequests/7/comments/42/resolve",
+	}
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected requests:\n%s", strings.Join(requests, "\n"))
+	}
+}
+
+func TestListAllPullRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("state"); got != "MERGED" {
+			t.Errorf("expected state MERGED, got %q", got)
+		}
+		page := r.URL.Query().Get("page")
+		next := ""
+		if page != "3" {
+			next = `"next": "https://api.bitbucket.org/next",`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"size": 6, %s "values": [{"id": %s1}, {"id": %s2}]}`, next, page, page)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	prs, err := client.ListAllPullRequests(context.Background(), "workspace", "repo", &PRListOptions{State: PRStateMerged})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ids []int64
+	for _, pr := range prs {
+		ids = append(ids, pr.ID)
+	}
+	want := []int64{11, 12, 21, 22, 31, 32}
+	if fmt.Sprint(ids) != fmt.Sprint(want) {
+		t.Errorf("expected pull requests %v in page order, got %v", want, ids)
+	}
+}
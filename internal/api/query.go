@@ -0,0 +1,93 @@
+package api
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Query builds a filter in the Bitbucket query language (BBQL), as taken by
+// the q parameter of list endpoints. Its conditions are joined with AND.
+type Query struct {
+	clauses []queryClause
+}
+
+type queryClause struct {
+	expr string
+	raw  bool
+}
+
+// Eq adds the condition that field equals value. An empty value adds
+// nothing, so optional filters can be added without checking them first.
+func (q *Query) Eq(field, value string) *Query {
+	if value != "" {
+		q.clauses = append(q.clauses, queryClause{expr: field + "=" + QuoteQueryValue(value)})
+	}
+	return q
+}
+
+// Raw adds expr, a BBQL expression written by the user, unchanged. It is
+// parenthesized when there are other conditions, so an OR in it doesn't
+// escape them.
+func (q *Query) Raw(expr string) *Query {
+	if expr = strings.TrimSpace(expr); expr != "" {
+		q.clauses = append(q.clauses, queryClause{expr: expr, raw: true})
+	}
+	return q
+}
+
+// Empty reports whether the query has no conditions
+func (q *Query) Empty() bool {
+	return len(q.clauses) == 0
+}
+
+// String returns the query in BBQL, or "" if it has no conditions
+func (q *Query) String() string {
+	if len(q.clauses) == 1 {
+		return q.clauses[0].expr
+	}
+	parts := make([]string, len(q.clauses))
+	for i, c := range q.clauses {
+		if c.raw {
+			parts[i] = "(" + c.expr + ")"
+		} else {
+			parts[i] = c.expr
+		}
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// QuoteQueryValue returns s as a BBQL string literal, with backslashes and
+// double quotes escaped
+func QuoteQueryValue(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// mePattern matches a comparison of a user field with "me" or "@me", which
+// stand for the current user in saved filters
+var mePattern = regexp.MustCompile(`\b((?:[A-Za-z_]+\.)*(username|nickname|uuid|account_id))(\s*!?=\s*)"@?me"`)
+
+// ReferencesMe reports whether query compares a user field with "me"
+func ReferencesMe(query string) bool {
+	return mePattern.MatchString(query)
+}
+
+// ExpandMe replaces "me" or "@me" compared with a user field in query, as
+// in assignee.username="me", with the value of that field for user
+func ExpandMe(query string, user *User) string {
+	return mePattern.ReplaceAllStringFunc(query, func(m string) string {
+		sub := mePattern.FindStringSubmatch(m)
+		var value string
+		switch sub[2] {
+		case "username":
+			value = user.Username
+		case "nickname":
+			value = user.Nickname
+		case "uuid":
+			value = user.UUID
+		case "account_id":
+			value = user.AccountID
+		}
+		return sub[1] + sub[3] + QuoteQueryValue(value)
+	})
+}
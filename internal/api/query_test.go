@@ -0,0 +1,77 @@
+package api
+
+import "testing"
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *Query
+		want  string
+	}{
+		{
+			name:  "empty",
+			query: new(Query),
+			want:  "",
+		},
+		{
+			name:  "skips empty values",
+			query: new(Query).Eq("state", "OPEN").Eq("author.username", ""),
+			want:  `state="OPEN"`,
+		},
+		{
+			name:  "joins conditions with AND",
+			query: new(Query).Eq("state", "OPEN").Eq("destination.branch.name", "main"),
+			want:  `state="OPEN" AND destination.branch.name="main"`,
+		},
+		{
+			name:  "escapes quotes and backslashes",
+			query: new(Query).Eq("title", `say "hi" \o/`),
+			want:  `title="say \"hi\" \\o/"`,
+		},
+		{
+			name:  "raw expression alone is unchanged",
+			query: new(Query).Raw(` state="OPEN" OR state="MERGED" `),
+			want:  `state="OPEN" OR state="MERGED"`,
+		},
+		{
+			name:  "raw expression is parenthesized among others",
+			query: new(Query).Eq("author.username", "alice").Raw(`state="OPEN" OR state="MERGED"`),
+			want:  `author.username="alice" AND (state="OPEN" OR state="MERGED")`,
+		},
+		{
+			name:  "blank raw expression adds nothing",
+			query: new(Query).Raw("  "),
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandMe(t *testing.T) {
+	user := &User{Username: "alice", Nickname: "ali", UUID: "{a-1}", AccountID: "557058"}
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`kind="bug" AND assignee.username="me"`, `kind="bug" AND assignee.username="alice"`},
+		{`reviewers.nickname = "@me" OR author.uuid!="me"`, `reviewers.nickname = "ali" OR author.uuid!="{a-1}"`},
+		{`author.account_id="me"`, `author.account_id="557058"`},
+		{`title="me"`, `title="me"`},
+		{`assignee.username="meg"`, `assignee.username="meg"`},
+	}
+	for _, tt := range tests {
+		if got := ExpandMe(tt.query, user); got != tt.want {
+			t.Errorf("ExpandMe(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+		if got, want := ReferencesMe(tt.query), tt.query != tt.want; got != want {
+			t.Errorf("ReferencesMe(%q) = %v, want %v", tt.query, got, want)
+		}
+	}
+}
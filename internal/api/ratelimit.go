@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit is the rate limit state a response reported. Bitbucket Cloud
+// reports the limit of the resource a request counted against, and whether
+// it is nearly used up; Bitbucket Data Center reports how many requests
+// remain in its token bucket.
+type RateLimit struct {
+	// Resource names the limit the request counted against, if the host
+	// has more than one
+	Resource string `json:"resource,omitempty"`
+	// Limit is how many requests are allowed per Interval
+	Limit int `json:"limit,omitempty"`
+	// Remaining is how many requests are left, or -1 if not reported
+	Remaining int `json:"remaining"`
+	// Interval is the period Limit applies to, if reported
+	Interval time.Duration `json:"interval,omitempty"`
+	// NearLimit is set when less than a fifth of the limit remains
+	NearLimit bool `json:"near_limit,omitempty"`
+	// Limited is set when the request was refused for exceeding the limit
+	Limited bool `json:"limited,omitempty"`
+	// RetryAfter is how long to wait before retrying a refused request
+	RetryAfter time.Duration `json:"retry_after,omitempty"`
+	// ObservedAt is when the response was received
+	ObservedAt time.Time `json:"observed_at"`
+}
+
+// ParseRateLimit reads the rate limit headers of a response with status
+// statusCode, received at now. It reports false for responses without any.
+func ParseRateLimit(statusCode int, header http.Header, now time.Time) (RateLimit, bool) {
+	rl := RateLimit{
+		Resource:   header.Get("X-RateLimit-Resource"),
+		Remaining:  -1,
+		Limited:    statusCode == http.StatusTooManyRequests,
+		ObservedAt: now,
+	}
+	found := rl.Limited
+
+	if n, err := strconv.Atoi(header.Get("X-RateLimit-Limit")); err == nil {
+		rl.Limit = n
+		found = true
+	}
+	if n, err := strconv.Atoi(header.Get("X-RateLimit-Remaining")); err == nil {
+		rl.Remaining = n
+		found = true
+	}
+	if n, err := strconv.Atoi(header.Get("X-RateLimit-Interval-Seconds")); err == nil {
+		rl.Interval = time.Duration(n) * time.Second
+	}
+	if near, err := strconv.ParseBool(header.Get("X-RateLimit-NearLimit")); err == nil {
+		rl.NearLimit = near
+		found = true
+	}
+	if n, err := strconv.Atoi(header.Get("Retry-After")); err == nil && rl.Limited {
+		rl.RetryAfter = time.Duration(n) * time.Second
+	}
+
+	if rl.Limit > 0 && rl.Remaining >= 0 && rl.Remaining*5 < rl.Limit {
+		rl.NearLimit = true
+	}
+	return rl, found
+}
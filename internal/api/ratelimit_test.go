@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	if _, ok := ParseRateLimit(http.StatusOK, http.Header{}, now); ok {
+		t.Error("expected no rate limit without headers")
+	}
+
+	cloud := http.Header{}
+	cloud.Set("X-RateLimit-Limit", "1000")
+	cloud.Set("X-RateLimit-Resource", "api-repo")
+	cloud.Set("X-RateLimit-NearLimit", "true")
+	rl, ok := ParseRateLimit(http.StatusOK, cloud, now)
+	if !ok || rl.Limit != 1000 || rl.Resource != "api-repo" || !rl.NearLimit || rl.Remaining != -1 {
+		t.Errorf("unexpected Cloud rate limit %+v", rl)
+	}
+
+	server := http.Header{}
+	server.Set("X-RateLimit-Limit", "60")
+	server.Set("X-RateLimit-Remaining", "10")
+	server.Set("X-RateLimit-Interval-Seconds", "1")
+	rl, ok = ParseRateLimit(http.StatusOK, server, now)
+	if !ok || rl.Remaining != 10 || rl.Interval != time.Second || !rl.NearLimit {
+		t.Errorf("unexpected Data Center rate limit %+v", rl)
+	}
+
+	refused := http.Header{}
+	refused.Set("Retry-After", "30")
+	rl, ok = ParseRateLimit(http.StatusTooManyRequests, refused, now)
+	if !ok || !rl.Limited || rl.RetryAfter != 30*time.Second {
+		t.Errorf("unexpected refused rate limit %+v", rl)
+	}
+}
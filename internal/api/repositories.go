@@ -3,7 +3,10 @@ package api
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"time"
 )
@@ -26,6 +29,7 @@ type RepositoryFull struct {
 	Workspace   *Workspace        `json:"workspace"`
 	MainBranch  *MainBranch       `json:"mainbranch,omitempty"`
 	Parent      *ParentRepository `json:"parent,omitempty"`
+	HasIssues   bool              `json:"has_issues"`
 	Links       RepositoryLinks   `json:"links"`
 }
 
@@ -109,6 +113,20 @@ type repositoryCreateRequest struct {
 	HasWiki   bool `json:"has_wiki,omitempty"`
 }
 
+// RepositoryUpdateOptions are options for updating a repository. Only the
+// fields set are changed.
+type RepositoryUpdateOptions struct {
+	ProjectKey string // Key of the project to move the repository into
+}
+
+// repositoryUpdateRequest is the actual API request body for updating a
+// repository
+type repositoryUpdateRequest struct {
+	Project *struct {
+		Key string `json:"key"`
+	} `json:"project,omitempty"`
+}
+
 // forkRepositoryRequest is the API request body for forking a repository
 type forkRepositoryRequest struct {
 	Name      string `json:"name,omitempty"`
@@ -190,6 +208,25 @@ func (c *Client) CreateRepository(ctx context.Context, workspace string, opts *R
 	return ParseResponse[*RepositoryFull](resp)
 }
 
+// UpdateRepository updates a repository
+func (c *Client) UpdateRepository(ctx context.Context, workspace, repoSlug string, opts *RepositoryUpdateOptions) (*RepositoryFull, error) {
+	path := fmt.Sprintf("/repositories/%s/%s", workspace, repoSlug)
+
+	reqBody := repositoryUpdateRequest{}
+	if opts.ProjectKey != "" {
+		reqBody.Project = &struct {
+			Key string `json:"key"`
+		}{Key: opts.ProjectKey}
+	}
+
+	resp, err := c.Put(ctx, path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*RepositoryFull](resp)
+}
+
 // DeleteRepository deletes a repository
 func (c *Client) DeleteRepository(ctx context.Context, workspace, repoSlug string) error {
 	path := fmt.Sprintf("/repositories/%s/%s", workspace, repoSlug)
@@ -219,3 +256,26 @@ func (c *Client) ForkRepository(ctx context.Context, workspace, repoSlug string,
 
 	return ParseResponse[*RepositoryFull](resp)
 }
+
+// ArchiveFormats are the formats a repository archive can be downloaded in
+var ArchiveFormats = []string{"tar.gz", "zip"}
+
+// OpenRepositoryArchive streams an archive of the files of a repository at
+// ref, a branch, tag or commit, in one of ArchiveFormats. The caller must
+// close it.
+func (c *Client) OpenRepositoryArchive(ctx context.Context, workspace, repoSlug, ref, format string) (io.ReadCloser, error) {
+	if !slices.Contains(ArchiveFormats, format) {
+		return nil, fmt.Errorf("unsupported archive format %q", format)
+	}
+	path := fmt.Sprintf("%s/%s/%s/get/%s.%s", c.webBaseURL(), workspace, repoSlug, url.PathEscape(ref), format)
+
+	resp, err := c.DoStream(ctx, &Request{
+		Method:  http.MethodGet,
+		Path:    path,
+		Headers: map[string]string{"Accept": "*/*"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
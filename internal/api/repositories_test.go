@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -450,6 +451,33 @@ func TestCreateRepository(t *testing.T) {
 	}
 }
 
+func TestUpdateRepository(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("expected PUT method, got %s", r.Method)
+		}
+		if r.URL.Path != "/repositories/myworkspace/myrepo" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if got := strings.TrimSpace(string(body)); got != `{"project":{"key":"NEW"}}` {
+			t.Errorf("unexpected body %s", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"full_name": "myworkspace/myrepo", "project": {"key": "NEW", "name": "New"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	repo, err := client.UpdateRepository(context.Background(), "myworkspace", "myrepo", &RepositoryUpdateOptions{ProjectKey: "NEW"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.Project == nil || repo.Project.Key != "NEW" {
+		t.Errorf("expected project NEW, got %+v", repo.Project)
+	}
+}
+
 func TestDeleteRepository(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -1052,3 +1080,47 @@ func TestCreateRepositoryRequiredFields(t *testing.T) {
 		t.Error("expected is_private to be present in body")
 	}
 }
+
+func TestOpenRepositoryArchive(t *testing.T) {
+	var gotPath, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotAuth = r.Header.Get("Authorization")
+		if strings.Contains(gotPath, "missing") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-tar")
+		w.Write([]byte("archive"))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithToken("secret"))
+
+	body, err := client.OpenRepositoryArchive(context.Background(), "workspace", "repo", "feature/x", "tar.gz")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	body.Close()
+
+	if gotPath != "/workspace/repo/get/feature%2Fx.tar.gz" {
+		t.Errorf("path = %q", gotPath)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Errorf("Authorization = %q, want the client's token", gotAuth)
+	}
+	if string(data) != "archive" {
+		t.Errorf("body = %q", data)
+	}
+
+	if _, err := client.OpenRepositoryArchive(context.Background(), "workspace", "repo", "main", "rar"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+
+	_, err = client.OpenRepositoryArchive(context.Background(), "workspace", "repo", "missing", "zip")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected a 404 API error, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+const (
+	// ScopesHeader lists the scopes granted to an OAuth or access token
+	ScopesHeader = "X-OAuth-Scopes"
+
+	// AcceptedScopesHeader lists the scopes that would have allowed a
+	// request that was refused
+	AcceptedScopesHeader = "X-Accepted-OAuth-Scopes"
+)
+
+// RequiredScopes are the scopes bb needs for its everyday commands.
+var RequiredScopes = []string{
+	"account",
+	"repository",
+	"repository:write",
+	"pullrequest",
+	"pullrequest:write",
+	"issue",
+	"issue:write",
+	"pipeline",
+	"snippet",
+}
+
+// impliedScopes lists the scopes that Bitbucket grants along with a scope.
+var impliedScopes = map[string][]string{
+	"repository:admin":  {"repository:write", "repository"},
+	"repository:write":  {"repository"},
+	"pullrequest":       {"repository"},
+	"pullrequest:write": {"pullrequest", "repository:write", "repository"},
+	"issue:write":       {"issue"},
+	"pipeline:write":    {"pipeline"},
+	"snippet:write":     {"snippet"},
+	"project:admin":     {"project"},
+	"account:write":     {"account"},
+}
+
+// GetScopes returns the scopes granted to the client's token. It returns
+// nil if the server doesn't report them, as is the case for Atlassian API
+// tokens, which act with all of the user's permissions.
+func (c *Client) GetScopes(ctx context.Context) ([]string, error) {
+	resp, err := c.Get(ctx, "/user", nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseScopes(resp.Headers.Get(ScopesHeader)), nil
+}
+
+// MissingScopes returns the scopes in required that granted doesn't cover,
+// taking implied scopes into account.
+func MissingScopes(granted, required []string) []string {
+	have := make(map[string]bool)
+	for _, s := range granted {
+		have[s] = true
+		for _, implied := range impliedScopes[s] {
+			have[implied] = true
+		}
+	}
+
+	var missing []string
+	for _, s := range required {
+		if !have[s] {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
+
+// parseScopes splits a scopes header such as "account, repository:write".
+func parseScopes(header string) []string {
+	return strings.FieldsFunc(header, func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+// likelyScope guesses the scope a refused request needed, preferring the
+// scope the server reports.
+func likelyScope(req *http.Request, resp *http.Response) string {
+	if accepted := parseScopes(resp.Header.Get(AcceptedScopesHeader)); len(accepted) > 0 {
+		return accepted[0]
+	}
+
+	path := req.URL.Path
+	var scope string
+	switch {
+	case strings.Contains(path, "/pullrequests"):
+		scope = "pullrequest"
+	case strings.Contains(path, "/issues"):
+		scope = "issue"
+	case strings.Contains(path, "/pipelines"):
+		scope = "pipeline"
+	case strings.Contains(path, "/hooks"):
+		return "webhook"
+	case strings.HasPrefix(path, "/snippets") || strings.Contains(path, "/snippets/"):
+		scope = "snippet"
+	case strings.Contains(path, "/repositories"):
+		scope = "repository"
+	case strings.Contains(path, "/projects"):
+		scope = "project"
+	case strings.Contains(path, "/user") || strings.Contains(path, "/workspaces"):
+		scope = "account"
+	default:
+		return ""
+	}
+
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		if scope == "repository" && req.Method == http.MethodDelete {
+			return "repository:delete"
+		}
+		scope += ":write"
+	}
+	return scope
+}
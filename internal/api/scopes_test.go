@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMissingScopes(t *testing.T) {
+	tests := []struct {
+		name     string
+		granted  []string
+		required []string
+		want     []string
+	}{
+		{
+			name:     "all granted",
+			granted:  []string{"account", "repository"},
+			required: []string{"account", "repository"},
+			want:     nil,
+		},
+		{
+			name:     "write implies read",
+			granted:  []string{"pullrequest:write"},
+			required: []string{"pullrequest", "repository", "repository:write"},
+			want:     nil,
+		},
+		{
+			name:     "read does not imply write",
+			granted:  []string{"account", "pipeline"},
+			required: []string{"account", "pipeline:write", "issue"},
+			want:     []string{"pipeline:write", "issue"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MissingScopes(tt.granted, tt.required); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MissingScopes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetScopes_ParsesHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(ScopesHeader, "account, repository:write pullrequest")
+		w.Write([]byte(`{"username": "alice"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	scopes, err := client.GetScopes(context.Background())
+	if err != nil {
+		t.Fatalf("GetScopes() returned error: %v", err)
+	}
+	want := []string{"account", "repository:write", "pullrequest"}
+	if !reflect.DeepEqual(scopes, want) {
+		t.Errorf("GetScopes() = %v, want %v", scopes, want)
+	}
+}
+
+func TestClientDo_ForbiddenHintsScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		accepted string
+		want     string
+	}{
+		{"reported by server", http.MethodGet, "/repositories/ws/repo", "repository:admin", "repository:admin"},
+		{"pull request write", http.MethodPost, "/repositories/ws/repo/pullrequests", "", "pullrequest:write"},
+		{"pipeline read", http.MethodGet, "/repositories/ws/repo/pipelines/", "", "pipeline"},
+		{"repository delete", http.MethodDelete, "/repositories/ws/repo", "", "repository:delete"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.accepted != "" {
+					w.Header().Set(AcceptedScopesHeader, tt.accepted)
+				}
+				w.WriteHeader(http.StatusForbidden)
+			}))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL))
+			_, err := client.Do(context.Background(), &Request{Method: tt.method, Path: tt.path})
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected *APIError, got %T", err)
+			}
+			if apiErr.Scope != tt.want {
+				t.Errorf("Scope = %q, want %q", apiErr.Scope, tt.want)
+			}
+			if !strings.Contains(apiErr.Error(), tt.want) {
+				t.Errorf("Error() = %q, should mention %q", apiErr.Error(), tt.want)
+			}
+		})
+	}
+}
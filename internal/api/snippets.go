@@ -199,7 +199,9 @@ func (c *Client) doMultipart(ctx context.Context, method, path string, body *byt
 	httpReq.Header.Set("Accept", "application/json")
 	httpReq.Header.Set("Content-Type", contentType)
 
-	if c.token != "" {
+	if c.username != "" && c.apiToken != "" {
+		httpReq.SetBasicAuth(c.username, c.apiToken)
+	} else if c.token != "" {
 		httpReq.Header.Set("Authorization", "Bearer "+c.token)
 	}
 
@@ -228,6 +230,9 @@ func (c *Client) doMultipart(ctx context.Context, method, path string, body *byt
 			StatusCode: httpResp.StatusCode,
 			Message:    http.StatusText(httpResp.StatusCode),
 		}
+		if httpResp.StatusCode == http.StatusForbidden {
+			apiErr.Scope = likelyScope(httpReq, httpResp)
+		}
 
 		// Try to parse error response
 		var errResp struct {
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+)
+
+// SourceEntry is a file or directory in a repository at a commit
+type SourceEntry struct {
+	// Type is commit_file or commit_directory
+	Type   string `json:"type"`
+	Path   string `json:"path"`
+	Size   int64  `json:"size,omitempty"`
+	Commit struct {
+		Hash string `json:"hash"`
+	} `json:"commit"`
+}
+
+// srcPath returns the API path of path in a repository at ref, escaping
+// each part so branches and files with slashes or spaces in their names
+// work
+func srcPath(workspace, repoSlug, ref, path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return fmt.Sprintf("/repositories/%s/%s/src/%s/%s", workspace, repoSlug, url.PathEscape(ref), strings.Join(parts, "/"))
+}
+
+// GetSourceEntry returns what path is in a repository at ref, a branch, tag
+// or commit
+func (c *Client) GetSourceEntry(ctx context.Context, workspace, repoSlug, ref, path string) (*SourceEntry, error) {
+	query := url.Values{}
+	query.Set("format", "meta")
+
+	resp, err := c.Get(ctx, srcPath(workspace, repoSlug, ref, path), query)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*SourceEntry](resp)
+}
+
+// OpenFile streams the content of the file at path in a repository at ref,
+// a branch, tag or commit. The caller must close it.
+func (c *Client) OpenFile(ctx context.Context, workspace, repoSlug, ref, path string) (io.ReadCloser, error) {
+	resp, err := c.DoStream(ctx, &Request{
+		Method:  http.MethodGet,
+		Path:    srcPath(workspace, repoSlug, ref, path),
+		Headers: map[string]string{"Accept": "*/*"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// CommitFilesOptions describes a commit made with CommitFiles
+type CommitFilesOptions struct {
+	Message string
+	// Author is the commit's author, as "Name <email>". It defaults to
+	// the authenticated user.
+	Author string
+	// Branch is the branch to commit to, which is created if it doesn't
+	// exist. It defaults to the main branch.
+	Branch string
+	// Parents are the commits to make the commit on. They default to the
+	// head of Branch.
+	Parents []string
+	// Files are the contents of the files to add or change, by path
+	Files map[string][]byte
+	// Delete are the paths of files to delete
+	Delete []string
+}
+
+// CommitFiles makes a commit that adds, changes and deletes files in a
+// repository, without git, returning the new commit's hash
+func (c *Client) CommitFiles(ctx context.Context, workspace, repoSlug string, opts *CommitFilesOptions) (string, error) {
+	apiPath := fmt.Sprintf("/repositories/%s/%s/src", workspace, repoSlug)
+
+	body, contentType, err := buildCommitFilesBody(opts)
+	if err != nil {
+		return "", fmt.Errorf("could not build multipart body: %w", err)
+	}
+
+	resp, err := c.doMultipart(ctx, http.MethodPost, apiPath, body, contentType)
+	if err != nil {
+		return "", err
+	}
+
+	// The new commit is only given by the Location header of the response
+	location := resp.Headers.Get("Location")
+	if _, hash, ok := strings.Cut(location, "/commit/"); ok {
+		return strings.Trim(hash, "/"), nil
+	}
+	return "", nil
+}
+
+// buildCommitFilesBody creates the multipart form body of a commit. Each
+// file is a part named by its path; deleted files are listed in "files"
+// parts without content.
+func buildCommitFilesBody(opts *CommitFilesOptions) (*bytes.Buffer, string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	// Fields as name, value pairs, since parents and files repeat
+	fields := [][2]string{
+		{"message", opts.Message},
+		{"author", opts.Author},
+		{"branch", opts.Branch},
+	}
+	for _, parent := range opts.Parents {
+		fields = append(fields, [2]string{"parents", parent})
+	}
+	for _, p := range opts.Delete {
+		fields = append(fields, [2]string{"files", "/" + strings.TrimPrefix(p, "/")})
+	}
+	for _, f := range fields {
+		if f[1] == "" {
+			continue
+		}
+		if err := writer.WriteField(f[0], f[1]); err != nil {
+			return nil, "", err
+		}
+	}
+
+	paths := make([]string, 0, len(opts.Files))
+	for p := range opts.Files {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+	for _, p := range paths {
+		part, err := writer.CreateFormFile("/"+strings.TrimPrefix(p, "/"), path.Base(p))
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := part.Write(opts.Files[p]); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return body, writer.FormDataContentType(), nil
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetSourceEntry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/repositories/team/app/src/feature%2Fx/docs/my%20notes.md" {
+			t.Errorf("unexpected path %q", r.URL.EscapedPath())
+		}
+		if got := r.URL.Query().Get("format"); got != "meta" {
+			t.Errorf("format = %q, want meta", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"type": "commit_file", "path": "docs/my notes.md", "size": 42, "commit": {"hash": "abc123"}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	entry, err := client.GetSourceEntry(context.Background(), "team", "app", "feature/x", "/docs/my notes.md")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.Type != "commit_file" || entry.Size != 42 || entry.Commit.Hash != "abc123" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+}
+
+func TestOpenFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/team/app/src/main/go.mod" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		fmt.Fprint(w, "module example.com/app\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	body, err := client.OpenFile(context.Background(), "team", "app", "main", "go.mod")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer body.Close()
+	content, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(content) != "module example.com/app\n" {
+		t.Errorf("unexpected content %q", content)
+	}
+}
+
+func TestCommitFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repositories/team/app/src" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("failed to parse form: %v", err)
+		}
+		form := r.MultipartForm
+		if got := form.Value["message"]; len(got) != 1 || got[0] != "Update config" {
+			t.Errorf("message = %q", got)
+		}
+		if got := form.Value["branch"]; len(got) != 1 || got[0] != "main" {
+			t.Errorf("branch = %q", got)
+		}
+		if _, ok := form.Value["author"]; ok {
+			t.Error("expected no author field when none is given")
+		}
+		if got := form.Value["files"]; len(got) != 1 || got[0] != "/old.yml" {
+			t.Errorf("files = %q", got)
+		}
+		headers := form.File["/config/app.yml"]
+		if len(headers) != 1 {
+			t.Fatalf("expected the file part, got %v", form.File)
+		}
+		f, _ := headers[0].Open()
+		content, _ := io.ReadAll(f)
+		if string(content) != "debug: true\n" {
+			t.Errorf("unexpected content %q", content)
+		}
+		w.Header().Set("Location", "https://api.bitbucket.org/2.0/repositories/team/app/commit/abc123")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	hash, err := client.CommitFiles(context.Background(), "team", "app", &CommitFilesOptions{
+		Message: "Update config",
+		Branch:  "main",
+		Files:   map[string][]byte{"config/app.yml": []byte("debug: true\n")},
+		Delete:  []string{"old.yml"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hash != "abc123" {
+		t.Errorf("hash = %q, want abc123", hash)
+	}
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// SSHKey represents an SSH public key added to a Bitbucket account
+type SSHKey struct {
+	UUID      string    `json:"uuid"`
+	Key       string    `json:"key"`
+	Label     string    `json:"label"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedOn time.Time `json:"created_on"`
+}
+
+// ListSSHKeys lists the SSH keys of a user, identified by UUID or account ID.
+func (c *Client) ListSSHKeys(ctx context.Context, user string) (*Paginated[SSHKey], error) {
+	path := fmt.Sprintf("/users/%s/ssh-keys", url.PathEscape(user))
+
+	resp, err := c.Get(ctx, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*Paginated[SSHKey]](resp)
+}
+
+// AddSSHKey adds an SSH public key, in authorized_keys format, to a user's
+// account. The token needs the account:write scope.
+func (c *Client) AddSSHKey(ctx context.Context, user, key, label string) (*SSHKey, error) {
+	path := fmt.Sprintf("/users/%s/ssh-keys", url.PathEscape(user))
+
+	body := map[string]string{
+		"key":   key,
+		"label": label,
+	}
+
+	resp, err := c.Post(ctx, path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*SSHKey](resp)
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListSSHKeys(t *testing.T) {
+	var receivedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"values": [
+				{"uuid": "{key-1}", "key": "ssh-ed25519 AAAAC3Nza alice@laptop", "label": "laptop", "created_on": "2024-01-01T00:00:00Z"}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+	result, err := client.ListSSHKeys(context.Background(), "{user-uuid}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if receivedPath != "/users/{user-uuid}/ssh-keys" {
+		t.Errorf("expected path /users/{user-uuid}/ssh-keys, got %q", receivedPath)
+	}
+	if len(result.Values) != 1 || result.Values[0].Label != "laptop" {
+		t.Errorf("unexpected keys: %+v", result.Values)
+	}
+}
+
+func TestAddSSHKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		response   string
+		statusCode int
+		wantErr    bool
+	}{
+		{
+			name:       "adds key",
+			response:   `{"uuid": "{key-2}", "key": "ssh-ed25519 AAAAC3Nza", "label": "bb CLI"}`,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "key already in use",
+			response:   `{"type": "error", "error": {"message": "Someone has already added that SSH key"}}`,
+			statusCode: http.StatusBadRequest,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receivedMethod string
+			var receivedBody map[string]string
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				receivedMethod = r.Method
+				json.NewDecoder(r.Body).Decode(&receivedBody)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+			key, err := client.AddSSHKey(context.Background(), "{user-uuid}", "ssh-ed25519 AAAAC3Nza", "bb CLI")
+
+			if receivedMethod != http.MethodPost {
+				t.Errorf("expected POST, got %s", receivedMethod)
+			}
+			if receivedBody["key"] != "ssh-ed25519 AAAAC3Nza" || receivedBody["label"] != "bb CLI" {
+				t.Errorf("unexpected request body: %v", receivedBody)
+			}
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if key.UUID != "{key-2}" {
+				t.Errorf("expected UUID {key-2}, got %q", key.UUID)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// CommitStatusOptions are options for setting a build status on a commit
+type CommitStatusOptions struct {
+	Key         string `json:"key"`
+	State       string `json:"state"` // SUCCESSFUL, FAILED, INPROGRESS, STOPPED
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
+}
+
+// CreateCommitStatus sets the build status identified by opts.Key on a
+// commit, replacing any status with the same key
+func (c *Client) CreateCommitStatus(ctx context.Context, workspace, repoSlug, commit string, opts *CommitStatusOptions) (*CommitStatus, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s/statuses/build", workspace, repoSlug, commit)
+
+	resp, err := c.Post(ctx, path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*CommitStatus](resp)
+}
+
+// ListCommitStatuses lists the build statuses reported on a commit
+func (c *Client) ListCommitStatuses(ctx context.Context, workspace, repoSlug, commit string) (*Paginated[CommitStatus], error) {
+	path := fmt.Sprintf("/repositories/%s/%s/commit/%s/statuses", workspace, repoSlug, commit)
+
+	query := url.Values{}
+	query.Set("pagelen", "100")
+
+	resp, err := c.Get(ctx, path, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*Paginated[CommitStatus]](resp)
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateCommitStatus(t *testing.T) {
+	var got CommitStatusOptions
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if r.URL.Path != "/repositories/team/api/commit/abc123/statuses/build" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"key": "bb-junit", "state": "FAILED", "name": "Tests"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+	status, err := client.CreateCommitStatus(context.Background(), "team", "api", "abc123", &CommitStatusOptions{
+		Key:   "bb-junit",
+		State: "FAILED",
+		Name:  "Tests",
+		URL:   "https://ci.example.com/builds/1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Key != "bb-junit" || status.State != "FAILED" {
+		t.Errorf("unexpected status %+v", status)
+	}
+	if got.URL != "https://ci.example.com/builds/1" || got.Name != "Tests" {
+		t.Errorf("unexpected request body %+v", got)
+	}
+}
+
+func TestListCommitStatuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/team/api/commit/abc123/statuses" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("pagelen"); got != "100" {
+			t.Errorf("pagelen = %q, want 100", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"values": [
+			{"key": "ci/test", "state": "SUCCESSFUL"},
+			{"key": "ci/lint", "state": "INPROGRESS"}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+	result, err := client.ListCommitStatuses(context.Background(), "team", "api", "abc123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Values) != 2 || result.Values[1].Key != "ci/lint" || result.Values[1].State != "INPROGRESS" {
+		t.Errorf("unexpected statuses %+v", result.Values)
+	}
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Tag represents a git tag
+type Tag struct {
+	Name    string      `json:"name"`
+	Message string      `json:"message,omitempty"`
+	Target  *BranchHead `json:"target"`
+	Links   struct {
+		HTML Link `json:"html"`
+	} `json:"links"`
+}
+
+// TagListOptions are options for listing tags
+type TagListOptions struct {
+	Sort  string // Sort field: name, -target.date, etc.
+	Query string // Filter query (Bitbucket query language)
+	Page  int    // Page number
+	Limit int    // Number of items per page (pagelen)
+}
+
+// TagCreateOptions are options for creating a tag
+type TagCreateOptions struct {
+	Name string `json:"name"`
+	// Message makes the tag an annotated tag
+	Message string `json:"message,omitempty"`
+	Target  struct {
+		Hash string `json:"hash"`
+	} `json:"target"`
+}
+
+// ListTags lists tags for a repository
+func (c *Client) ListTags(ctx context.Context, workspace, repoSlug string, opts *TagListOptions) (*Paginated[Tag], error) {
+	path := fmt.Sprintf("/repositories/%s/%s/refs/tags", workspace, repoSlug)
+
+	query := url.Values{}
+	if opts != nil {
+		if opts.Sort != "" {
+			query.Set("sort", opts.Sort)
+		}
+		if opts.Query != "" {
+			query.Set("q", opts.Query)
+		}
+		if opts.Page > 0 {
+			query.Set("page", strconv.Itoa(opts.Page))
+		}
+		if opts.Limit > 0 {
+			query.Set("pagelen", strconv.Itoa(opts.Limit))
+		}
+	}
+
+	resp, err := c.Get(ctx, path, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*Paginated[Tag]](resp)
+}
+
+// CreateTag creates a new tag
+func (c *Client) CreateTag(ctx context.Context, workspace, repoSlug string, opts *TagCreateOptions) (*Tag, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/refs/tags", workspace, repoSlug)
+
+	resp, err := c.Post(ctx, path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*Tag](resp)
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/team/app/refs/tags" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("sort"); got != "-target.date" {
+			t.Errorf("sort = %q, want -target.date", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"values": [{"name": "v1.0.0", "target": {"hash": "abc123"}}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	tags, err := client.ListTags(context.Background(), "team", "app", &TagListOptions{Sort: "-target.date"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tags.Values) != 1 || tags.Values[0].Name != "v1.0.0" || tags.Values[0].Target.Hash != "abc123" {
+		t.Errorf("unexpected tags %+v", tags.Values)
+	}
+}
+
+func TestCreateTag(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repositories/team/app/refs/tags" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("invalid body: %v", err)
+		}
+		if body["name"] != "v1.1.0" || body["message"] != "Release v1.1.0" || body["target"].(map[string]any)["hash"] != "def456" {
+			t.Errorf("unexpected body %v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"name": "v1.1.0", "target": {"hash": "def456"}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	opts := &TagCreateOptions{Name: "v1.1.0", Message: "Release v1.1.0"}
+	opts.Target.Hash = "def456"
+	tag, err := client.CreateTag(context.Background(), "team", "app", opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tag.Name != "v1.1.0" {
+		t.Errorf("unexpected tag %+v", tag)
+	}
+}
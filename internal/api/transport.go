@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/http"
+	"time"
+)
+
+// Connection pool defaults. The standard library keeps only two idle
+// connections per host, so commands that fan requests out, such as bulk
+// edits and reading pages concurrently, would otherwise keep reconnecting.
+const (
+	DefaultMaxIdleConnsPerHost = 16
+	DefaultIdleConnTimeout     = 90 * time.Second
+)
+
+// TransportConfig tunes the connection pool requests are sent through.
+// Zero fields take the defaults above.
+type TransportConfig struct {
+	// MaxIdleConnsPerHost is how many idle connections to a host are kept
+	// open for reuse
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept open
+	IdleConnTimeout time.Duration
+	// DisableHTTP2 restricts connections to HTTP/1.1, e.g. for proxies that
+	// mishandle HTTP/2
+	DisableHTTP2 bool
+}
+
+// WithTransportConfig tunes the client's connection pool with cfg.
+func WithTransportConfig(cfg TransportConfig) ClientOption {
+	return func(c *Client) {
+		c.transportConfig = &cfg
+	}
+}
+
+// Apply sets the connection pool settings of cfg on transport.
+func (cfg TransportConfig) Apply(transport *http.Transport) {
+	transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	if transport.MaxIdleConnsPerHost <= 0 {
+		transport.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	}
+	if transport.MaxIdleConns > 0 && transport.MaxIdleConns < transport.MaxIdleConnsPerHost {
+		transport.MaxIdleConns = transport.MaxIdleConnsPerHost
+	}
+
+	transport.IdleConnTimeout = cfg.IdleConnTimeout
+	if transport.IdleConnTimeout <= 0 {
+		transport.IdleConnTimeout = DefaultIdleConnTimeout
+	}
+
+	if cfg.DisableHTTP2 {
+		protocols := new(http.Protocols)
+		protocols.SetHTTP1(true)
+		transport.Protocols = protocols
+		transport.ForceAttemptHTTP2 = false
+	}
+}
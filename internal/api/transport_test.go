@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTransportConfig_Apply(t *testing.T) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	TransportConfig{}.Apply(transport)
+	if transport.MaxIdleConnsPerHost != DefaultMaxIdleConnsPerHost {
+		t.Errorf("MaxIdleConnsPerHost = %d, want %d", transport.MaxIdleConnsPerHost, DefaultMaxIdleConnsPerHost)
+	}
+	if transport.IdleConnTimeout != DefaultIdleConnTimeout {
+		t.Errorf("IdleConnTimeout = %v, want %v", transport.IdleConnTimeout, DefaultIdleConnTimeout)
+	}
+
+	transport = http.DefaultTransport.(*http.Transport).Clone()
+	TransportConfig{MaxIdleConnsPerHost: 200, IdleConnTimeout: 5 * time.Second}.Apply(transport)
+	if transport.MaxIdleConnsPerHost != 200 {
+		t.Errorf("MaxIdleConnsPerHost = %d, want %d", transport.MaxIdleConnsPerHost, 200)
+	}
+	if transport.MaxIdleConns < 200 {
+		t.Errorf("MaxIdleConns = %d, want at least the per-host limit", transport.MaxIdleConns)
+	}
+	if transport.IdleConnTimeout != 5*time.Second {
+		t.Errorf("IdleConnTimeout = %v, want %v", transport.IdleConnTimeout, 5*time.Second)
+	}
+}
+
+func TestWithTransportConfig_DisableHTTP2(t *testing.T) {
+	var proto string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proto = r.Proto
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"username": "alice"}`))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	insecure := WithTLSConfig(&tls.Config{InsecureSkipVerify: true})
+
+	client := NewClient(WithBaseURL(server.URL), insecure, WithTransportConfig(TransportConfig{}))
+	if _, err := client.GetCurrentUser(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if proto != "HTTP/2.0" {
+		t.Errorf("request sent over %s, want HTTP/2.0", proto)
+	}
+
+	client = NewClient(WithBaseURL(server.URL), insecure, WithTransportConfig(TransportConfig{DisableHTTP2: true}))
+	if _, err := client.GetCurrentUser(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if proto != "HTTP/1.1" {
+		t.Errorf("request sent over %s, want HTTP/1.1", proto)
+	}
+}
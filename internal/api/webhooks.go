@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Webhook represents a webhook of a repository
+type Webhook struct {
+	UUID        string    `json:"uuid"`
+	URL         string    `json:"url"`
+	Description string    `json:"description"`
+	Active      bool      `json:"active"`
+	Events      []string  `json:"events"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// WebhookCreateOptions are options for creating a webhook
+type WebhookCreateOptions struct {
+	Description string   `json:"description"`
+	URL         string   `json:"url"`
+	Active      bool     `json:"active"`
+	Events      []string `json:"events"`
+	// Secret signs each delivery, in the X-Hub-Signature header, with
+	// HMAC-SHA256
+	Secret string `json:"secret,omitempty"`
+}
+
+// HookEvent describes an event webhooks can subscribe to
+type HookEvent struct {
+	Event       string `json:"event"`
+	Category    string `json:"category"`
+	Label       string `json:"label"`
+	Description string `json:"description"`
+}
+
+// ListHookEvents lists the events webhooks on subjectType, e.g.
+// "repository" or "workspace", can subscribe to
+func (c *Client) ListHookEvents(ctx context.Context, subjectType string) ([]HookEvent, error) {
+	path := fmt.Sprintf("/hook_events/%s", url.PathEscape(subjectType))
+
+	var events []HookEvent
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("pagelen", "100")
+
+		resp, err := c.Get(ctx, path, query)
+		if err != nil {
+			return nil, err
+		}
+		result, err := ParseResponse[*Paginated[HookEvent]](resp)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, result.Values...)
+		if result.Next == "" {
+			return events, nil
+		}
+	}
+}
+
+// CreateRepositoryWebhook adds a webhook to a repository. The token needs
+// the webhook scope.
+func (c *Client) CreateRepositoryWebhook(ctx context.Context, workspace, repoSlug string, opts *WebhookCreateOptions) (*Webhook, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/hooks", workspace, repoSlug)
+
+	resp, err := c.Post(ctx, path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseResponse[*Webhook](resp)
+}
+
+// DeleteRepositoryWebhook removes the webhook with the given UUID from a
+// repository
+func (c *Client) DeleteRepositoryWebhook(ctx context.Context, workspace, repoSlug, uuid string) error {
+	path := fmt.Sprintf("/repositories/%s/%s/hooks/%s", workspace, repoSlug, url.PathEscape(uuid))
+
+	_, err := c.Delete(ctx, path)
+	return err
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListHookEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/hook_events/repository" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			w.Write([]byte(`{"values": [{"event": "repo:push"}], "next": "page2"}`))
+			return
+		}
+		w.Write([]byte(`{"values": [{"event": "pullrequest:created"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+	events, err := client.ListHookEvents(context.Background(), "repository")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 || events[0].Event != "repo:push" || events[1].Event != "pullrequest:created" {
+		t.Errorf("expected the events of both pages, got %+v", events)
+	}
+}
+
+func TestCreateAndDeleteRepositoryWebhook(t *testing.T) {
+	var created map[string]any
+	var deletedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			if r.URL.Path != "/repositories/team/api/hooks" {
+				t.Errorf("unexpected path %q", r.URL.Path)
+			}
+			json.NewDecoder(r.Body).Decode(&created)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"uuid": "{hook-1}", "url": "https://relay.example.com/abc", "active": true, "events": ["repo:push"]}`))
+		case http.MethodDelete:
+			deletedPath = r.URL.EscapedPath()
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+	hook, err := client.CreateRepositoryWebhook(context.Background(), "team", "api", &WebhookCreateOptions{
+		Description: "test",
+		URL:         "https://relay.example.com/abc",
+		Active:      true,
+		Events:      []string{"repo:push"},
+		Secret:      "s3cret",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hook.UUID != "{hook-1}" {
+		t.Errorf("UUID = %q, want {hook-1}", hook.UUID)
+	}
+	if created["secret"] != "s3cret" || created["url"] != "https://relay.example.com/abc" {
+		t.Errorf("unexpected request body: %v", created)
+	}
+
+	if err := client.DeleteRepositoryWebhook(context.Background(), "team", "api", hook.UUID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deletedPath != "/repositories/team/api/hooks/%7Bhook-1%7D" {
+		t.Errorf("deleted path = %q", deletedPath)
+	}
+}
@@ -4,18 +4,19 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
+	"strings"
+
+	"github.com/rbansal42/bitbucket-cli/internal/config"
 )
 
-// Open opens the given URL in the default browser
+// Open opens the given URL in the user's browser. The browser is taken from
+// BB_BROWSER, the browser config key or BROWSER, in that order, falling back
+// to the platform default.
 func Open(url string) error {
-	// Check for BB_BROWSER environment variable
-	if browser := os.Getenv("BB_BROWSER"); browser != "" {
-		return exec.Command(browser, url).Start()
-	}
-
-	// Check for BROWSER environment variable
-	if browser := os.Getenv("BROWSER"); browser != "" {
-		return exec.Command(browser, url).Start()
+	if browser := Command(); browser != "" {
+		// Browsers may be configured with arguments, e.g. "firefox --new-tab"
+		args := strings.Fields(browser)
+		return exec.Command(args[0], append(args[1:], url)...).Start()
 	}
 
 	// Use platform-specific command
@@ -26,10 +27,24 @@ func Open(url string) error {
 	case "linux":
 		cmd = exec.Command("xdg-open", url)
 	case "windows":
-		cmd = exec.Command("cmd", "/c", "start", url)
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
 	default:
 		cmd = exec.Command("xdg-open", url)
 	}
 
 	return cmd.Start()
 }
+
+// Command returns the configured browser command, or an empty string to use
+// the system default.
+func Command() string {
+	// Check BB_BROWSER and config
+	if resolver, err := config.Resolve(); err == nil {
+		if browser := resolver.Get("browser").Value; browser != "" {
+			return browser
+		}
+	}
+
+	// Check standard environment variable
+	return os.Getenv("BROWSER")
+}
@@ -0,0 +1,222 @@
+// Package bugreport writes diagnostic reports when bb fails in a way that
+// points to a bug in bb itself, so users can report it with the details a
+// maintainer needs and without their secrets.
+package bugreport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/redact"
+)
+
+const (
+	// newIssueURL is where bugs in bb are reported
+	newIssueURL = "https://github.com/rbansal42/bitbucket-cli/issues/new"
+
+	// maxCalls is how many of the latest API calls a report includes
+	maxCalls = 20
+
+	// maxIssueStackLines bounds the stack trace put in an issue, to keep
+	// its URL within what browsers accept
+	maxIssueStackLines = 40
+)
+
+// Report describes a failure of bb
+type Report struct {
+	Time      time.Time
+	Version   string
+	BuildDate string
+	GoVersion string
+	Platform  string
+	// Args are the command-line arguments, with secrets redacted
+	Args  []string
+	Error string
+	// Stack is the stack trace of a panic, if bb panicked
+	Stack    string
+	APICalls []Call
+}
+
+// Call is an API request made before the failure
+type Call struct {
+	Method   string
+	URL      string
+	Status   int
+	Duration time.Duration
+	Err      string
+}
+
+// New creates a report of err, and of the panic with stack trace stack if
+// there was one. args are the command-line arguments, which are redacted.
+func New(version, buildDate string, args []string, err error, stack []byte, calls []Call) *Report {
+	return &Report{
+		Time:      time.Now(),
+		Version:   version,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		Args:      redact.Args(args),
+		Error:     redact.Text(err.Error()),
+		Stack:     redact.Text(string(stack)),
+		APICalls:  calls,
+	}
+}
+
+// Unexpected reports whether err points to a bug in bb rather than to a
+// problem with the input, the network or the server: an API response bb
+// couldn't parse.
+func Unexpected(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr)
+}
+
+// Recorder keeps the latest API calls, for a report to include
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// ClientOption returns an API client option that records the client's
+// requests
+func (r *Recorder) ClientOption() api.ClientOption {
+	return api.WithRequestHook(r.Record)
+}
+
+// Record is an api.RequestHook that records info, dropping the oldest call
+// once maxCalls are kept
+func (r *Recorder) Record(info api.RequestInfo) {
+	call := Call{Method: info.Method, Status: info.StatusCode, Duration: info.Duration}
+	if info.URL != nil {
+		call.URL = redact.URL(info.URL)
+	}
+	if info.Err != nil {
+		call.Err = redact.Text(info.Err.Error())
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+	if len(r.calls) > maxCalls {
+		r.calls = r.calls[len(r.calls)-maxCalls:]
+	}
+}
+
+// Calls returns the recorded calls, oldest first
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// Text formats the report for a file a user can read and attach to an
+// issue
+func (r *Report) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "bb bug report, %s\n\n", r.Time.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Version:  %s (%s)\n", r.Version, r.BuildDate)
+	fmt.Fprintf(&b, "Platform: %s, %s\n", r.Platform, r.GoVersion)
+	fmt.Fprintf(&b, "Command:  bb %s\n", strings.Join(r.Args, " "))
+	fmt.Fprintf(&b, "Error:    %s\n", r.Error)
+
+	if r.Stack != "" {
+		fmt.Fprintf(&b, "\nStack trace:\n%s\n", strings.TrimRight(r.Stack, "\n"))
+	}
+
+	fmt.Fprintf(&b, "\nRecent API calls (%d):\n", len(r.APICalls))
+	for _, c := range r.APICalls {
+		status := "ERR"
+		if c.Status != 0 {
+			status = fmt.Sprintf("%d", c.Status)
+		}
+		fmt.Fprintf(&b, "  %-6s %s %6dms  %s", c.Method, status, c.Duration.Milliseconds(), c.URL)
+		if c.Err != "" {
+			fmt.Fprintf(&b, "  (%s)", c.Err)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Save writes the report to a new file in the crash directory of the
+// cache, and returns its path
+func Save(r *Report) (string, error) {
+	dir, err := config.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "crash")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("could not create crash report directory: %w", err)
+	}
+	f, err := os.CreateTemp(dir, "bb-crash-"+r.Time.Format("20060102-150405")+"-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("could not create crash report: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(r.Text()); err != nil {
+		return "", fmt.Errorf("could not write crash report: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// IssueURL returns the address of a new GitHub issue prefilled with the
+// report. Only the command name, not its arguments, and the top of the
+// stack trace go in the issue; the saved report holds the rest.
+func IssueURL(r *Report) string {
+	title := "bb " + strings.Join(commandWords(r.Args), " ") + ": " + firstLine(r.Error)
+
+	var body strings.Builder
+	body.WriteString("### What happened\n\n<!-- What were you doing when bb failed? -->\n\n")
+	body.WriteString("### Diagnostics\n\n")
+	fmt.Fprintf(&body, "- Version: %s (%s)\n", r.Version, r.BuildDate)
+	fmt.Fprintf(&body, "- Platform: %s, %s\n", r.Platform, r.GoVersion)
+	fmt.Fprintf(&body, "- Command: `bb %s`\n", strings.Join(commandWords(r.Args), " "))
+	fmt.Fprintf(&body, "- Error: `%s`\n", firstLine(r.Error))
+	if r.Stack != "" {
+		lines := strings.Split(strings.TrimRight(r.Stack, "\n"), "\n")
+		if len(lines) > maxIssueStackLines {
+			lines = append(lines[:maxIssueStackLines], "...")
+		}
+		fmt.Fprintf(&body, "\n<details><summary>Stack trace</summary>\n\n```\n%s\n```\n</details>\n", strings.Join(lines, "\n"))
+	}
+	body.WriteString("\n<!-- Attach the saved report if it helps; check it for anything private first. -->\n")
+
+	q := url.Values{}
+	q.Set("title", title)
+	q.Set("labels", "bug")
+	q.Set("body", body.String())
+	return newIssueURL + "?" + q.Encode()
+}
+
+// commandWords returns the leading words of args naming the command run,
+// e.g. "pr list", leaving out flags and arguments that may be private such
+// as repository names
+func commandWords(args []string) []string {
+	var words []string
+	for _, arg := range args {
+		if arg == "" || strings.IndexFunc(arg, func(r rune) bool { return (r < 'a' || r > 'z') && r != '-' }) >= 0 || strings.HasPrefix(arg, "-") {
+			break
+		}
+		words = append(words, arg)
+		if len(words) == 3 {
+			break
+		}
+	}
+	return words
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
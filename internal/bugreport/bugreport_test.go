@@ -0,0 +1,79 @@
+package bugreport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+func TestRecorder(t *testing.T) {
+	var r Recorder
+	for i := range maxCalls + 5 {
+		u, _ := url.Parse(fmt.Sprintf("https://api.example.com/repositories?page=%d&access_token=secret", i))
+		r.Record(api.RequestInfo{Method: "GET", URL: u, StatusCode: 200, Duration: time.Millisecond})
+	}
+
+	calls := r.Calls()
+	if len(calls) != maxCalls {
+		t.Fatalf("kept %d calls, want %d", len(calls), maxCalls)
+	}
+	if !strings.Contains(calls[0].URL, "page=5") {
+		t.Errorf("oldest call = %q, want page 5", calls[0].URL)
+	}
+	if strings.Contains(calls[0].URL, "secret") {
+		t.Errorf("call URL not redacted: %q", calls[0].URL)
+	}
+}
+
+func TestUnexpected(t *testing.T) {
+	var v struct{ ID int }
+	decodeErr := json.Unmarshal([]byte(`{"ID": "not a number"}`), &v)
+
+	if !Unexpected(fmt.Errorf("failed to list pull requests: %w", decodeErr)) {
+		t.Error("expected a decode error to be unexpected")
+	}
+	if Unexpected(errors.New("repository not found")) || Unexpected(nil) {
+		t.Error("expected an ordinary error not to be unexpected")
+	}
+}
+
+func TestSaveAndIssueURL(t *testing.T) {
+	t.Setenv("BB_CACHE_DIR", t.TempDir())
+
+	report := New("1.2.0", "2024-05-01", []string{"pr", "list", "-R", "private/repo"},
+		errors.New("bb crashed unexpectedly: runtime error: index out of range"),
+		[]byte("goroutine 1 [running]:\nmain.main()"), nil)
+
+	path, err := Save(report)
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Version:  1.2.0", "Command:  bb pr list -R private/repo", "goroutine 1 [running]"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("report is missing %q:\n%s", want, data)
+		}
+	}
+
+	u, err := url.Parse(IssueURL(report))
+	if err != nil {
+		t.Fatalf("IssueURL() is not a URL: %v", err)
+	}
+	q := u.Query()
+	if got := q.Get("title"); got != "bb pr list: bb crashed unexpectedly: runtime error: index out of range" {
+		t.Errorf("title = %q", got)
+	}
+	if body := q.Get("body"); strings.Contains(body, "private/repo") || !strings.Contains(body, "main.main()") {
+		t.Errorf("unexpected issue body:\n%s", body)
+	}
+}
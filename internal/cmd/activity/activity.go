@@ -0,0 +1,220 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// workspaceRepoLimit is how many of a workspace's most recently updated
+// repositories the workspace feed covers
+const workspaceRepoLimit = 10
+
+// ActivityOptions holds the options for the activity command
+type ActivityOptions struct {
+	Repo      string
+	Workspace string
+	Since     string
+	Limit     int
+	JSON      bool
+	Format    string
+	Watch     time.Duration
+	Streams   *iostreams.IOStreams
+}
+
+// NewCmdActivity creates the activity command
+func NewCmdActivity(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &ActivityOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "activity",
+		Short: "Show recent activity in a repository or workspace",
+		Long: `Show a feed of recent activity in a repository: commits pushed, pull
+requests opened, updated, merged and declined, approvals and comments on
+them, and pipeline results, oldest first.
+
+With --workspace, the feed covers the workspace's 10 most recently updated
+repositories. Each source contributes at most its latest 50 events per
+repository, so a long --since on a busy repository shows the most recent
+part of it.
+
+--since takes a duration, such as 2h, 3d or 1w, or a date, such as
+2024-05-01.`,
+		Example: `  # Show the last day of activity in the current repository
+  bb activity
+
+  # Show the last week of activity in a workspace
+  bb activity --workspace myworkspace --since 1w
+
+  # Keep the feed open, refreshing every 30 seconds
+  bb activity --watch=30s
+
+  # Output as JSON
+  bb activity --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Watch > 0 {
+				return cmdutil.Watch(cmd.Context(), opts.Streams, opts.Watch, func() error {
+					return runActivity(cmd.Context(), opts)
+				})
+			}
+			return runActivity(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+	cmd.Flags().StringVarP(&opts.Workspace, "workspace", "w", "", "Show activity across a workspace's repositories")
+	cmd.MarkFlagsMutuallyExclusive("repo", "workspace")
+	cmd.Flags().StringVar(&opts.Since, "since", "24h", "Show activity since a duration ago or a date")
+	cmd.Flags().IntVarP(&opts.Limit, "limit", "L", 50, "Maximum number of events to show")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
+	cmdutil.AddWatchFlag(cmd, &opts.Watch)
+
+	return cmd
+}
+
+func runActivity(ctx context.Context, opts *ActivityOptions) error {
+	since, err := parseSince(opts.Since, time.Now())
+	if err != nil {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, err)
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	repos, err := feedRepositories(ctx, client, opts, since)
+	if err != nil {
+		return err
+	}
+
+	progress := opts.Streams.StartProgress("Fetching activity")
+	events, errs := collect(ctx, client, repos, since)
+	progress.Stop()
+	if len(errs) == len(repos)*len(sources) {
+		return fmt.Errorf("failed to read activity: %w", errs[0])
+	}
+	for _, err := range errs {
+		opts.Streams.Warning("%s", err)
+	}
+
+	if len(events) > opts.Limit {
+		events = events[len(events)-opts.Limit:]
+	}
+
+	if opts.JSON || opts.Format != "" {
+		return cmdutil.PrintFormatted(opts.Streams, opts.Format, events)
+	}
+
+	if len(events) == 0 {
+		opts.Streams.Info("No activity in %s since %s", strings.Join(repos, ", "), cmdutil.FormatTime(opts.Streams, since))
+		return nil
+	}
+
+	table := cmdutil.NewTablePrinter(opts.Streams)
+	if len(repos) > 1 {
+		table.AddHeader("TIME", "REPOSITORY", "TYPE", "ACTOR", "EVENT")
+	} else {
+		table.AddHeader("TIME", "TYPE", "ACTOR", "EVENT")
+	}
+	for _, e := range events {
+		fields := []string{
+			opts.Streams.Style(iostreams.RoleMuted, cmdutil.FormatTime(opts.Streams, e.Time)),
+			e.Type,
+			e.Actor,
+			styleSummary(opts.Streams, e),
+		}
+		if len(repos) > 1 {
+			fields = slices.Insert(fields, 1, e.Repo)
+		}
+		table.AddRow(fields...)
+	}
+	return table.Render()
+}
+
+// feedRepositories returns the repositories the feed covers, as
+// WORKSPACE/REPO: the selected one, or the most recently updated ones of
+// the workspace given with --workspace
+func feedRepositories(ctx context.Context, client *api.Client, opts *ActivityOptions, since time.Time) ([]string, error) {
+	if opts.Workspace == "" {
+		workspace, repoSlug, err := cmdutil.ParseRepository(opts.Repo)
+		if err != nil {
+			return nil, err
+		}
+		return []string{workspace + "/" + repoSlug}, nil
+	}
+
+	workspace, err := cmdutil.ParseWorkspace(opts.Workspace)
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.ListRepositories(ctx, workspace, &api.RepositoryListOptions{
+		Sort:  "-updated_on",
+		Query: fmt.Sprintf("updated_on >= %s", since.UTC().Format(time.RFC3339)),
+		Limit: workspaceRepoLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %w", err)
+	}
+	repos := make([]string, 0, len(result.Values))
+	for _, r := range result.Values {
+		repos = append(repos, r.FullName)
+	}
+	if len(repos) == 0 {
+		return nil, fmt.Errorf("no repositories in %s were updated since %s", workspace, since.Format(time.DateOnly))
+	}
+	return repos, nil
+}
+
+// collect reads the events of every source in every repository since
+// since, oldest first. Sources that fail are reported in errs rather than
+// failing the feed.
+func collect(ctx context.Context, client *api.Client, repos []string, since time.Time) (events []event, errs []error) {
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, repo := range repos {
+		workspace, repoSlug, _ := strings.Cut(repo, "/")
+		for _, s := range sources {
+			wg.Go(func() {
+				found, err := s.fetch(ctx, client, workspace, repoSlug)
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					errs = append(errs, fmt.Errorf("could not read %s of %s: %w", s.name, repo, err))
+					return
+				}
+				for _, e := range found {
+					if !e.Time.Before(since) {
+						e.Repo = repo
+						events = append(events, e)
+					}
+				}
+			})
+		}
+	}
+	wg.Wait()
+
+	slices.SortStableFunc(events, func(a, b event) int {
+		return a.Time.Compare(b.Time)
+	})
+	return events, errs
+}
+
+// parseSince parses --since, a duration before now, which may be given in
+// days (d) or weeks (w), or a date
+func parseSince(value string, now time.Time) (time.Time, error) {
+	return cmdutil.ParseTimeFlag("--since", value, now)
+}
@@ -0,0 +1,111 @@
+package activity
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "2h", want: now.Add(-2 * time.Hour)},
+		{value: "3d", want: now.Add(-72 * time.Hour)},
+		{value: "1w", want: now.Add(-7 * 24 * time.Hour)},
+		{value: "2024-05-01T08:00:00Z", want: time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)},
+		{value: "2024-05-01", want: time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local)},
+		{value: "-1d", wantErr: true},
+		{value: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseSince(tt.value, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSince() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("parseSince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPipelineEvent(t *testing.T) {
+	created := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	completed := created.Add(5 * time.Minute)
+	p := api.Pipeline{
+		BuildNumber: 42,
+		CreatedOn:   created,
+		Target:      &api.PipelineTarget{RefName: "main"},
+		State:       &api.PipelineState{Name: "IN_PROGRESS"},
+	}
+
+	if e := pipelineEvent(p, "team", "app"); e.Summary != "started pipeline #42 on main" || !e.Time.Equal(created) {
+		t.Errorf("running pipeline: got %q at %v", e.Summary, e.Time)
+	}
+
+	p.CompletedOn = &completed
+	p.State = &api.PipelineState{Name: "COMPLETED", Result: &api.PipelineStateResult{Name: "FAILED"}}
+	e := pipelineEvent(p, "team", "app")
+	if e.Summary != "pipeline #42 on main failed" || !e.Time.Equal(completed) {
+		t.Errorf("failed pipeline: got %q at %v", e.Summary, e.Time)
+	}
+	if e.URL != "https://bitbucket.org/team/app/pipelines/results/42" {
+		t.Errorf("URL = %q", e.URL)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repositories/team/app/commits":
+			w.Write([]byte(`{"values": [
+				{"hash": "abcdef1234567", "message": "Fix login\n\nDetails", "date": "2024-05-01T10:00:00+00:00", "author": {"raw": "Jane Doe <jane@example.com>"}},
+				{"hash": "0123456789abc", "message": "Old commit", "date": "2024-04-01T10:00:00+00:00", "author": {"raw": "Jane Doe <jane@example.com>"}}
+			]}`))
+		case "/repositories/team/app/pullrequests/activity":
+			w.Write([]byte(`{"values": [
+				{"pull_request": {"id": 7, "title": "Add search"}, "approval": {"date": "2024-05-01T11:00:00+00:00", "user": {"display_name": "Sam"}}},
+				{"pull_request": {"id": 7, "title": "Add search"}, "comment": {"content": {"raw": "Looks good"}, "user": {"display_name": "Kim"}, "created_on": "2024-05-01T09:00:00+00:00"}}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"type": "error", "error": {"message": "Not found"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewClient(api.WithBaseURL(server.URL), api.WithToken("test-token"))
+	since := time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC)
+	events, errs := collect(context.Background(), client, []string{"team/app"}, since)
+
+	if len(errs) != 1 {
+		t.Errorf("got %d errors, want 1 for the pipelines: %v", len(errs), errs)
+	}
+	want := []string{
+		"commented on #7 Add search: Looks good",
+		"abcdef1 Fix login",
+		"approved #7 Add search",
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, e := range events {
+		if e.Summary != want[i] || e.Repo != "team/app" {
+			t.Errorf("event %d = %q in %s, want %q", i, e.Summary, e.Repo, want[i])
+		}
+	}
+	if events[1].Actor != "Jane Doe" {
+		t.Errorf("commit actor = %q, want Jane Doe", events[1].Actor)
+	}
+}
@@ -0,0 +1,206 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// pageSize is how many of the latest events each source reads per
+// repository, the most the pull request activity endpoint allows
+const pageSize = 50
+
+// event is an entry of the feed
+type event struct {
+	Time    time.Time `json:"time"`
+	Repo    string    `json:"repository"`
+	Type    string    `json:"type"`
+	Actor   string    `json:"actor"`
+	Summary string    `json:"summary"`
+	URL     string    `json:"url,omitempty"`
+
+	// role styles the summary on a terminal
+	role iostreams.Role
+}
+
+// source reads one kind of event of a repository
+type source struct {
+	name  string
+	fetch func(ctx context.Context, client *api.Client, workspace, repoSlug string) ([]event, error)
+}
+
+// sources are the kinds of event the feed is made of
+var sources = []source{
+	{"commits", commitEvents},
+	{"pull request activity", pullRequestEvents},
+	{"pipelines", pipelineEvents},
+}
+
+// commitEvents reports the latest commits. Bitbucket has no API for pushes,
+// so they show as the commits they brought.
+func commitEvents(ctx context.Context, client *api.Client, workspace, repoSlug string) ([]event, error) {
+	result, err := client.ListCommits(ctx, workspace, repoSlug, &api.CommitListOptions{Limit: pageSize})
+	if err != nil {
+		return nil, err
+	}
+	events := make([]event, 0, len(result.Values))
+	for _, c := range result.Values {
+		actor := cmdutil.GetUserDisplayName(c.Author.User)
+		if c.Author.User == nil {
+			actor = commitAuthorName(c.Author.Raw)
+		}
+		events = append(events, event{
+			Time:    c.Date,
+			Type:    "commit",
+			Actor:   actor,
+			Summary: fmt.Sprintf("%s %s", shortHash(c.Hash), firstLine(c.Message)),
+			URL:     c.Links.HTML.Href,
+		})
+	}
+	return events, nil
+}
+
+// pullRequestEvents reports updates, approvals, requests for changes and
+// comments on pull requests
+func pullRequestEvents(ctx context.Context, client *api.Client, workspace, repoSlug string) ([]event, error) {
+	result, err := client.ListPRActivity(ctx, workspace, repoSlug, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]event, 0, len(result.Values))
+	for _, a := range result.Values {
+		if e, ok := pullRequestEvent(a); ok {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// pullRequestEvent describes a pull request activity entry
+func pullRequestEvent(a api.PRActivity) (event, bool) {
+	pr := fmt.Sprintf("#%d %s", a.PullRequest.ID, a.PullRequest.Title)
+	e := event{Type: "pr", URL: a.PullRequest.Links.HTML.Href}
+
+	switch {
+	case a.Update != nil:
+		e.Time = a.Update.Date
+		e.Actor = cmdutil.GetUserDisplayName(&a.Update.Author)
+		switch a.Update.State {
+		case api.PRStateMerged:
+			e.Summary, e.role = "merged "+pr, iostreams.RoleAccent
+		case api.PRStateDeclined:
+			e.Summary, e.role = "declined "+pr, iostreams.RoleError
+		default:
+			e.Summary = "updated " + pr
+		}
+	case a.Approval != nil:
+		e.Time = a.Approval.Date
+		e.Actor = cmdutil.GetUserDisplayName(&a.Approval.User)
+		e.Summary, e.role = "approved "+pr, iostreams.RoleSuccess
+	case a.ChangesRequested != nil:
+		e.Time = a.ChangesRequested.Date
+		e.Actor = cmdutil.GetUserDisplayName(&a.ChangesRequested.User)
+		e.Summary, e.role = "requested changes on "+pr, iostreams.RoleWarning
+	case a.Comment != nil:
+		e.Type = "comment"
+		e.Time = a.Comment.CreatedOn
+		e.Actor = cmdutil.GetUserDisplayName(&a.Comment.User)
+		e.Summary = fmt.Sprintf("commented on %s: %s", pr, firstLine(a.Comment.Content.Raw))
+		if a.Comment.Links.HTML.Href != "" {
+			e.URL = a.Comment.Links.HTML.Href
+		}
+	default:
+		return event{}, false
+	}
+	return e, true
+}
+
+// pipelineEvents reports pipelines that started or finished
+func pipelineEvents(ctx context.Context, client *api.Client, workspace, repoSlug string) ([]event, error) {
+	result, err := client.ListPipelines(ctx, workspace, repoSlug, &api.PipelineListOptions{
+		Sort:  "-created_on",
+		Limit: pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	events := make([]event, 0, len(result.Values))
+	for _, p := range result.Values {
+		events = append(events, pipelineEvent(p, workspace, repoSlug))
+	}
+	return events, nil
+}
+
+// pipelineEvent describes a pipeline by its outcome, at the time it
+// finished, or by its start while it runs
+func pipelineEvent(p api.Pipeline, workspace, repoSlug string) event {
+	e := event{
+		Time:  p.CreatedOn,
+		Type:  "pipeline",
+		Actor: cmdutil.GetUserDisplayName(p.Creator),
+		URL:   fmt.Sprintf("https://bitbucket.org/%s/%s/pipelines/results/%d", workspace, repoSlug, p.BuildNumber),
+	}
+	ref := ""
+	if p.Target != nil && p.Target.RefName != "" {
+		ref = " on " + p.Target.RefName
+	}
+
+	result := ""
+	if p.State != nil && p.State.Result != nil {
+		result = p.State.Result.Name
+	}
+	if p.CompletedOn == nil || result == "" {
+		e.Summary, e.role = fmt.Sprintf("started pipeline #%d%s", p.BuildNumber, ref), iostreams.RoleInfo
+		return e
+	}
+
+	e.Time = *p.CompletedOn
+	e.Summary = fmt.Sprintf("pipeline #%d%s %s", p.BuildNumber, ref, strings.ToLower(result))
+	switch result {
+	case "SUCCESSFUL":
+		e.Summary = fmt.Sprintf("pipeline #%d%s passed", p.BuildNumber, ref)
+		e.role = iostreams.RoleSuccess
+	case "FAILED", "ERROR":
+		e.Summary = fmt.Sprintf("pipeline #%d%s failed", p.BuildNumber, ref)
+		e.role = iostreams.RoleError
+	default:
+		e.role = iostreams.RoleMuted
+	}
+	return e
+}
+
+// styleSummary colors the summary of e by what happened
+func styleSummary(streams *iostreams.IOStreams, e event) string {
+	if e.role == "" {
+		return e.Summary
+	}
+	return streams.Style(e.role, e.Summary)
+}
+
+// commitAuthorName returns the name of a "Name <email>" author
+func commitAuthorName(raw string) string {
+	if name, _, ok := strings.Cut(raw, " <"); ok {
+		return name
+	}
+	if raw == "" {
+		return "-"
+	}
+	return raw
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(line)
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
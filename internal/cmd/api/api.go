@@ -12,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
 	"github.com/rbansal42/bitbucket-cli/internal/config"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
@@ -27,6 +28,7 @@ func NewCmdAPI(streams *iostreams.IOStreams) *cobra.Command {
 		silent      bool
 		includeResp bool
 		paginate    bool
+		format      string
 	)
 
 	cmd := &cobra.Command{
@@ -55,7 +57,10 @@ or --input for reading from a file.`,
     --json title="Bug report" --json priority="major"
 
   # Get raw response with headers
-  bb api user --include`,
+  bb api user --include
+
+  # Print the response as YAML
+  bb api repositories/myworkspace/myrepo --format yaml`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			endpoint := args[0]
@@ -70,7 +75,11 @@ or --input for reading from a file.`,
 			if strings.HasPrefix(endpoint, "http") {
 				url = endpoint
 			} else {
-				url = "https://api.bitbucket.org/2.0" + endpoint
+				baseURL, err := cmdutil.APIBaseURL()
+				if err != nil {
+					return err
+				}
+				url = baseURL + endpoint
 			}
 
 			// Get authentication token
@@ -144,8 +153,9 @@ or --input for reading from a file.`,
 			}
 
 			// Execute request
-			client := &http.Client{
-				Timeout: 30 * time.Second,
+			client, err := cmdutil.NewHTTPClient(30 * time.Second)
+			if err != nil {
+				return err
 			}
 
 			resp, err := client.Do(req)
@@ -156,7 +166,7 @@ or --input for reading from a file.`,
 
 			// Handle pagination if requested
 			if paginate && resp.StatusCode == http.StatusOK {
-				return handlePagination(streams, client, req, resp, token, includeResp, silent)
+				return handlePagination(streams, client, req, resp, token, includeResp, silent, format)
 			}
 
 			// Print response headers if requested
@@ -180,7 +190,11 @@ or --input for reading from a file.`,
 				// Pretty-print JSON if possible
 				if strings.Contains(resp.Header.Get("Content-Type"), "application/json") {
 					var prettyJSON bytes.Buffer
-					if err := json.Indent(&prettyJSON, respBody, "", "  "); err == nil {
+					if format == cmdutil.FormatYAML && json.Valid(respBody) {
+						if err := cmdutil.WriteJSONAsYAML(streams.Out, respBody); err != nil {
+							return err
+						}
+					} else if err := json.Indent(&prettyJSON, respBody, "", "  "); err == nil {
 						fmt.Fprintln(streams.Out, prettyJSON.String())
 					} else {
 						fmt.Fprintln(streams.Out, string(respBody))
@@ -207,6 +221,7 @@ or --input for reading from a file.`,
 	cmd.Flags().BoolVarP(&silent, "silent", "s", false, "Do not print response body")
 	cmd.Flags().BoolVarP(&includeResp, "include", "i", false, "Include response headers in output")
 	cmd.Flags().BoolVar(&paginate, "paginate", false, "Automatically fetch all pages of results")
+	cmdutil.AddFormatFlag(cmd, &format)
 
 	return cmd
 }
@@ -227,13 +242,16 @@ func getAuthToken() (string, error) {
 		return "", err
 	}
 
-	user := hosts.GetActiveUser(config.DefaultHost)
+	host, user, err := config.ActiveAccount(hosts)
+	if err != nil {
+		return "", err
+	}
 	if user == "" {
 		return "", fmt.Errorf("no authenticated user found")
 	}
 
 	// Get token from keyring
-	token, err := config.GetToken(config.DefaultHost, user)
+	token, err := config.GetToken(host, user)
 	if err != nil {
 		return "", err
 	}
@@ -242,7 +260,7 @@ func getAuthToken() (string, error) {
 }
 
 // handlePagination handles paginated responses
-func handlePagination(streams *iostreams.IOStreams, client *http.Client, originalReq *http.Request, firstResp *http.Response, token string, includeResp, silent bool) error {
+func handlePagination(streams *iostreams.IOStreams, client *http.Client, originalReq *http.Request, firstResp *http.Response, token string, includeResp, silent bool, format string) error {
 	type paginatedResponse struct {
 		Values []json.RawMessage `json:"values"`
 		Next   string            `json:"next"`
@@ -313,6 +331,14 @@ func handlePagination(streams *iostreams.IOStreams, client *http.Client, origina
 
 	// Print all values
 	if !silent {
+		if format == cmdutil.FormatYAML {
+			result, err := json.Marshal(allValues)
+			if err != nil {
+				return fmt.Errorf("could not encode results: %w", err)
+			}
+			return cmdutil.WriteJSONAsYAML(streams.Out, result)
+		}
+
 		result, err := json.MarshalIndent(allValues, "", "  ")
 		if err != nil {
 			return fmt.Errorf("could not encode results: %w", err)
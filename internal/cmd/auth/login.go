@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -18,6 +19,7 @@ import (
 
 	"github.com/rbansal42/bitbucket-cli/internal/api"
 	"github.com/rbansal42/bitbucket-cli/internal/browser"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
 	"github.com/rbansal42/bitbucket-cli/internal/config"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
@@ -25,20 +27,23 @@ import (
 const (
 	// OAuth endpoints
 	authorizationURL = "https://bitbucket.org/site/oauth2/authorize"
-	tokenURL         = "https://bitbucket.org/site/oauth2/access_token"
+	tokenURL         = api.OAuthTokenURL
 
 	// Default scopes for bb CLI
-	defaultScopes = "account repository repository:write pullrequest pullrequest:write issue issue:write pipeline pipeline:write snippet snippet:write webhook"
+	defaultScopes = "account account:write repository repository:write pullrequest pullrequest:write issue issue:write pipeline pipeline:write snippet snippet:write webhook"
 
 	// Callback path for OAuth redirect
 	callbackPath = "/callback"
 )
 
 type loginOptions struct {
-	streams   *iostreams.IOStreams
-	withToken bool
-	hostname  string
-	scopes    string
+	streams         *iostreams.IOStreams
+	withToken       bool
+	hostname        string
+	apiURL          string
+	scopes          string
+	insecureStorage bool
+	skipSSHKey      bool
 }
 
 // NewCmdLogin creates the login command
@@ -57,7 +62,17 @@ You can choose between:
   - API Token: Simple setup, good for CI/CD and automation
   - OAuth: More secure, supports token refresh
 
-Alternatively, use --with-token to read a token directly from stdin.`,
+Alternatively, use --with-token to read a token directly from stdin.
+
+Tokens are stored in the OS keyring. Where none is available, such as on
+headless servers and in containers, login fails unless --insecure-storage is
+given, which stores the token in an obfuscated file in the config directory.
+The file's key is kept next to it, so anyone who can read your files can
+read the token.
+
+When git uses SSH and can't connect to Bitbucket, interactive login offers to
+generate an ed25519 key, add it to your account and add the host to
+~/.ssh/config. Use --skip-ssh-key to skip this.`,
 		Example: `  # Interactive login (recommended)
   $ bb auth login
 
@@ -65,7 +80,10 @@ Alternatively, use --with-token to read a token directly from stdin.`,
   $ echo "your_token" | bb auth login --with-token
 
   # Login with a token from a file
-  $ bb auth login --with-token < token.txt`,
+  $ bb auth login --with-token < token.txt
+
+  # Login to a Bitbucket Data Center server
+  $ bb auth login --hostname bitbucket.example.com --with-token < token.txt`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runLogin(opts)
 		},
@@ -73,12 +91,19 @@ Alternatively, use --with-token to read a token directly from stdin.`,
 
 	cmd.Flags().BoolVar(&opts.withToken, "with-token", false, "Read token from stdin")
 	cmd.Flags().StringVar(&opts.hostname, "hostname", config.DefaultHost, "Bitbucket hostname")
+	cmd.Flags().StringVar(&opts.apiURL, "api-url", "", "REST API base URL of the host (default https://<hostname>/rest/api/1.0 for hosts other than bitbucket.org)")
 	cmd.Flags().StringVar(&opts.scopes, "scopes", defaultScopes, "OAuth scopes to request")
+	cmd.Flags().BoolVar(&opts.insecureStorage, "insecure-storage", false, "Store the token in an obfuscated, not encrypted, file if no OS keyring is available")
+	cmd.Flags().BoolVar(&opts.skipSSHKey, "skip-ssh-key", false, "Don't offer to generate and upload an SSH key")
 
 	return cmd
 }
 
 func runLogin(opts *loginOptions) error {
+	if opts.hostname != config.DefaultHost && !opts.withToken {
+		return fmt.Errorf("only --with-token login is supported for %s; OAuth and Atlassian API tokens work with bitbucket.org only", opts.hostname)
+	}
+
 	// If --with-token flag is set, read token from stdin
 	if opts.withToken {
 		return loginWithTokenFromStdin(opts)
@@ -89,43 +114,55 @@ func runLogin(opts *loginOptions) error {
 }
 
 func interactiveLogin(opts *loginOptions) error {
-	reader := bufio.NewReader(os.Stdin)
+	if !opts.streams.CanPrompt() {
+		return fmt.Errorf("interactive login requires a terminal; use --with-token to read a token from stdin")
+	}
 
 	fmt.Fprintln(opts.streams.Out, "")
 	fmt.Fprintln(opts.streams.Out, "Welcome to bb CLI! Let's get you authenticated with Bitbucket.")
 	fmt.Fprintln(opts.streams.Out, "")
-	fmt.Fprintln(opts.streams.Out, "How would you like to authenticate?")
-	fmt.Fprintln(opts.streams.Out, "")
-	fmt.Fprintln(opts.streams.Out, "  [1] API Token (simple, good for CI/CD)")
-	fmt.Fprintln(opts.streams.Out, "  [2] OAuth (more secure, supports token refresh)")
-	fmt.Fprintln(opts.streams.Out, "")
-	fmt.Fprint(opts.streams.Out, "Enter choice [1/2]: ")
 
-	choice, err := reader.ReadString('\n')
+	choice, err := opts.streams.PromptSelect("How would you like to authenticate?", []string{
+		"API Token (simple, good for CI/CD)",
+		"OAuth (more secure, supports token refresh)",
+	}, 0)
 	if err != nil {
-		return fmt.Errorf("failed to read input: %w", err)
+		return err
 	}
-	choice = strings.TrimSpace(choice)
 
 	var loginErr error
 	switch choice {
-	case "1":
-		loginErr = interactiveAPITokenLogin(opts, reader)
-	case "2":
-		loginErr = interactiveOAuthLogin(opts, reader)
-	default:
-		return fmt.Errorf("invalid choice: %s (enter 1 or 2)", choice)
+	case 0:
+		loginErr = interactiveAPITokenLogin(opts)
+	case 1:
+		loginErr = interactiveOAuthLogin(opts)
 	}
 
 	if loginErr != nil {
 		return loginErr
 	}
 
+	offerSSHKey(opts)
+
 	// After successful login, ask about default workspace
-	return promptForDefaultWorkspace(opts, reader)
+	return promptForDefaultWorkspace(opts)
 }
 
-func interactiveAPITokenLogin(opts *loginOptions, reader *bufio.Reader) error {
+// promptOpenBrowser offers to open url, opening it unless the user declines.
+func promptOpenBrowser(opts *loginOptions, url string) {
+	fmt.Fprintln(opts.streams.Out, "")
+	open, err := opts.streams.PromptConfirm("Open browser now?", true)
+	if err != nil || !open {
+		return
+	}
+
+	if err := browser.Open(url); err != nil {
+		opts.streams.Warning("Failed to open browser: %v", err)
+		fmt.Fprintf(opts.streams.Out, "Please open manually: %s\n", url)
+	}
+}
+
+func interactiveAPITokenLogin(opts *loginOptions) error {
 	const apiTokenURL = "https://id.atlassian.com/manage-profile/security/api-tokens"
 
 	fmt.Fprintln(opts.streams.Out, "")
@@ -137,28 +174,15 @@ func interactiveAPITokenLogin(opts *loginOptions, reader *bufio.Reader) error {
 	fmt.Fprintln(opts.streams.Out, "  2. Click 'Create API token'")
 	fmt.Fprintln(opts.streams.Out, "  3. Enter a label (e.g., 'bb-cli')")
 	fmt.Fprintln(opts.streams.Out, "  4. Click 'Create' and copy the token")
-	fmt.Fprintln(opts.streams.Out, "")
-	fmt.Fprint(opts.streams.Out, "Press Enter to open browser (or 'n' to skip): ")
 
-	skipBrowser, _ := reader.ReadString('\n')
-	skipBrowser = strings.TrimSpace(strings.ToLower(skipBrowser))
-
-	if skipBrowser != "n" && skipBrowser != "no" {
-		if err := browser.Open(apiTokenURL); err != nil {
-			opts.streams.Warning("Failed to open browser: %v", err)
-			fmt.Fprintf(opts.streams.Out, "Please open manually: %s\n", apiTokenURL)
-		}
-	}
+	promptOpenBrowser(opts, apiTokenURL)
 
 	// Get email for Basic Auth
 	fmt.Fprintln(opts.streams.Out, "")
-	fmt.Fprint(opts.streams.Out, "Enter your Atlassian account email: ")
-
-	email, err := reader.ReadString('\n')
+	email, err := opts.streams.PromptText("Enter your Atlassian account email", "")
 	if err != nil {
-		return fmt.Errorf("failed to read email: %w", err)
+		return err
 	}
-	email = strings.TrimSpace(email)
 
 	if email == "" {
 		return fmt.Errorf("email cannot be empty")
@@ -167,13 +191,10 @@ func interactiveAPITokenLogin(opts *loginOptions, reader *bufio.Reader) error {
 	// Token entry loop with retry on invalid token
 	for {
 		fmt.Fprintln(opts.streams.Out, "")
-		fmt.Fprint(opts.streams.Out, "Paste your API token: ")
-
-		token, err := reader.ReadString('\n')
+		token, err := opts.streams.PromptSecret("Paste your API token")
 		if err != nil {
-			return fmt.Errorf("failed to read token: %w", err)
+			return err
 		}
-		token = strings.TrimSpace(token)
 
 		if token == "" {
 			return fmt.Errorf("token cannot be empty")
@@ -194,19 +215,16 @@ func interactiveAPITokenLogin(opts *loginOptions, reader *bufio.Reader) error {
 		fmt.Fprintln(opts.streams.Out, "  - The API token was copied correctly (no extra spaces)")
 		fmt.Fprintln(opts.streams.Out, "  - The API token has not been revoked")
 		fmt.Fprintln(opts.streams.Out, "")
-		fmt.Fprint(opts.streams.Out, "Try again? [Y/n]: ")
 
-		retry, _ := reader.ReadString('\n')
-		retry = strings.TrimSpace(strings.ToLower(retry))
-
-		if retry == "n" || retry == "no" {
+		retry, err := opts.streams.PromptConfirm("Try again?", true)
+		if err != nil || !retry {
 			return fmt.Errorf("authentication cancelled")
 		}
 		// Loop continues for retry
 	}
 }
 
-func interactiveOAuthLogin(opts *loginOptions, reader *bufio.Reader) error {
+func interactiveOAuthLogin(opts *loginOptions) error {
 	// Check if OAuth credentials are already configured
 	clientID := os.Getenv("BB_OAUTH_CLIENT_ID")
 	clientSecret := os.Getenv("BB_OAUTH_CLIENT_SECRET")
@@ -224,13 +242,11 @@ func interactiveOAuthLogin(opts *loginOptions, reader *bufio.Reader) error {
 	fmt.Fprintln(opts.streams.Out, "")
 	fmt.Fprintln(opts.streams.Out, "OAuth requires a one-time setup of an OAuth consumer in Bitbucket.")
 	fmt.Fprintln(opts.streams.Out, "")
-	fmt.Fprint(opts.streams.Out, "Enter your workspace name (e.g., 'myteam'): ")
 
-	workspace, err := reader.ReadString('\n')
+	workspace, err := opts.streams.PromptText("Enter your workspace name (e.g., 'myteam')", "")
 	if err != nil {
-		return fmt.Errorf("failed to read input: %w", err)
+		return err
 	}
-	workspace = strings.TrimSpace(workspace)
 
 	if workspace == "" {
 		return fmt.Errorf("workspace name is required")
@@ -251,37 +267,23 @@ func interactiveOAuthLogin(opts *loginOptions, reader *bufio.Reader) error {
 	fmt.Fprintln(opts.streams.Out, "  4. Select permissions (Account, Repositories, Pull requests, etc.)")
 	fmt.Fprintln(opts.streams.Out, "  5. Click 'Save'")
 	fmt.Fprintln(opts.streams.Out, "  6. Copy the 'Key' and 'Secret'")
-	fmt.Fprintln(opts.streams.Out, "")
-	fmt.Fprint(opts.streams.Out, "Press Enter to open browser (or 'n' to skip): ")
 
-	skipBrowser, _ := reader.ReadString('\n')
-	skipBrowser = strings.TrimSpace(strings.ToLower(skipBrowser))
-
-	if skipBrowser != "n" && skipBrowser != "no" {
-		if err := browser.Open(oauthURL); err != nil {
-			opts.streams.Warning("Failed to open browser: %v", err)
-			fmt.Fprintf(opts.streams.Out, "Please open manually: %s\n", oauthURL)
-		}
-	}
+	promptOpenBrowser(opts, oauthURL)
 
 	fmt.Fprintln(opts.streams.Out, "")
-	fmt.Fprint(opts.streams.Out, "Paste your OAuth Key (Client ID): ")
-	clientID, err = reader.ReadString('\n')
+	clientID, err = opts.streams.PromptText("Paste your OAuth Key (Client ID)", "")
 	if err != nil {
-		return fmt.Errorf("failed to read client ID: %w", err)
+		return err
 	}
-	clientID = strings.TrimSpace(clientID)
 
 	if clientID == "" {
 		return fmt.Errorf("client ID cannot be empty")
 	}
 
-	fmt.Fprint(opts.streams.Out, "Paste your OAuth Secret (Client Secret): ")
-	clientSecret, err = reader.ReadString('\n')
+	clientSecret, err = opts.streams.PromptSecret("Paste your OAuth Secret (Client Secret)")
 	if err != nil {
-		return fmt.Errorf("failed to read client secret: %w", err)
+		return err
 	}
-	clientSecret = strings.TrimSpace(clientSecret)
 
 	if clientSecret == "" {
 		return fmt.Errorf("client secret cannot be empty")
@@ -318,7 +320,10 @@ func validateAndSaveToken(opts *loginOptions, token string) error {
 	opts.streams.Info("Validating token...")
 
 	// Validate token by making an API request (Bearer token)
-	client := api.NewClient(api.WithToken(token))
+	client, err := opts.newClient(api.WithToken(token))
+	if err != nil {
+		return err
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -328,23 +333,16 @@ func validateAndSaveToken(opts *loginOptions, token string) error {
 	}
 
 	// Store token in keyring
-	if err := config.SetToken(opts.hostname, user.Username, token); err != nil {
+	if err := opts.storeToken(user.Username, token); err != nil {
 		return fmt.Errorf("failed to store token: %w", err)
 	}
 
-	// Update hosts config
-	hosts, err := config.LoadHostsConfig()
-	if err != nil {
-		return fmt.Errorf("failed to load hosts config: %w", err)
-	}
-
-	hosts.SetActiveUser(opts.hostname, user.Username)
-
-	if err := config.SaveHostsConfig(hosts); err != nil {
-		return fmt.Errorf("failed to save hosts config: %w", err)
+	if err := saveHost(opts, user.Username, config.AuthMethodToken); err != nil {
+		return err
 	}
 
 	opts.streams.Success("Logged in as: %s (%s)", user.DisplayName, user.Username)
+	warnMissingScopes(opts.streams, client)
 	return nil
 }
 
@@ -352,7 +350,10 @@ func validateAndSaveAPIToken(opts *loginOptions, email, apiToken string) error {
 	opts.streams.Info("Validating credentials...")
 
 	// Validate using Basic Auth (email:api_token)
-	client := api.NewClient(api.WithBasicAuth(email, apiToken))
+	client, err := opts.newClient(api.WithBasicAuth(email, apiToken))
+	if err != nil {
+		return err
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -363,27 +364,20 @@ func validateAndSaveAPIToken(opts *loginOptions, email, apiToken string) error {
 
 	// Store credentials - we store as "email:token" format for Basic Auth
 	credentials := email + ":" + apiToken
-	if err := config.SetToken(opts.hostname, user.Username, "basic:"+credentials); err != nil {
+	if err := opts.storeToken(user.Username, "basic:"+credentials); err != nil {
 		return fmt.Errorf("failed to store credentials: %w", err)
 	}
 
-	// Update hosts config
-	hosts, err := config.LoadHostsConfig()
-	if err != nil {
-		return fmt.Errorf("failed to load hosts config: %w", err)
-	}
-
-	hosts.SetActiveUser(opts.hostname, user.Username)
-
-	if err := config.SaveHostsConfig(hosts); err != nil {
-		return fmt.Errorf("failed to save hosts config: %w", err)
+	if err := saveHost(opts, user.Username, config.AuthMethodAPIToken); err != nil {
+		return err
 	}
 
 	opts.streams.Success("Logged in as: %s (%s)", user.DisplayName, email)
+	warnMissingScopes(opts.streams, client)
 	return nil
 }
 
-func promptForDefaultWorkspace(opts *loginOptions, reader *bufio.Reader) error {
+func promptForDefaultWorkspace(opts *loginOptions) error {
 	// Check current default workspace
 	currentDefault, _ := config.GetDefaultWorkspace()
 	if currentDefault != "" {
@@ -393,15 +387,10 @@ func promptForDefaultWorkspace(opts *loginOptions, reader *bufio.Reader) error {
 	}
 
 	fmt.Fprintln(opts.streams.Out, "")
-	fmt.Fprint(opts.streams.Out, "Would you like to set a default workspace? [y/N]: ")
 
-	answer, err := reader.ReadString('\n')
-	if err != nil {
-		return nil // Don't fail login if this fails
-	}
-	answer = strings.TrimSpace(strings.ToLower(answer))
-
-	if answer != "y" && answer != "yes" {
+	// Don't fail login if the prompt fails
+	setDefault, err := opts.streams.PromptConfirm("Would you like to set a default workspace?", false)
+	if err != nil || !setDefault {
 		fmt.Fprintln(opts.streams.Out, "You can set a default workspace later with: bb workspace set-default <workspace>")
 		return nil
 	}
@@ -433,32 +422,21 @@ func promptForDefaultWorkspace(opts *loginOptions, reader *bufio.Reader) error {
 		return nil
 	}
 
-	fmt.Fprintln(opts.streams.Out, "")
-	fmt.Fprintln(opts.streams.Out, "Available workspaces:")
+	names := make([]string, len(workspaces), len(workspaces)+1)
 	for i, membership := range workspaces {
-		fmt.Fprintf(opts.streams.Out, "  [%d] %s (%s)\n", i+1, membership.Workspace.Name, membership.Workspace.Slug)
-	}
-	fmt.Fprintln(opts.streams.Out, "")
-	fmt.Fprint(opts.streams.Out, "Enter number to select (or press Enter to skip): ")
-
-	selection, err := reader.ReadString('\n')
-	if err != nil {
-		return nil
+		names[i] = fmt.Sprintf("%s (%s)", membership.Workspace.Name, membership.Workspace.Slug)
 	}
-	selection = strings.TrimSpace(selection)
+	names = append(names, "Skip, don't set a default workspace")
 
-	if selection == "" {
+	fmt.Fprintln(opts.streams.Out, "")
+	// No default, so Enter doesn't pick a workspace for the user
+	idx, err := opts.streams.PromptSelect("Available workspaces:", names, -1)
+	if err != nil || idx == len(workspaces) {
 		fmt.Fprintln(opts.streams.Out, "You can set a default workspace later with: bb workspace set-default <workspace>")
 		return nil
 	}
 
-	var idx int
-	if _, err := fmt.Sscanf(selection, "%d", &idx); err != nil || idx < 1 || idx > len(workspaces) {
-		opts.streams.Warning("Invalid selection")
-		return nil
-	}
-
-	selectedWorkspace := workspaces[idx-1].Workspace.Slug
+	selectedWorkspace := workspaces[idx].Workspace.Slug
 	if err := config.SetDefaultWorkspace(selectedWorkspace); err != nil {
 		opts.streams.Warning("Failed to set default workspace: %v", err)
 		return nil
@@ -479,7 +457,7 @@ func getAuthenticatedClient(hostname string) (*api.Client, error) {
 		return nil, fmt.Errorf("not logged in")
 	}
 
-	tokenData, _, err := config.GetTokenFromEnvOrKeyring(hostname, user)
+	tokenData, source, err := config.GetTokenFromEnvOrKeyring(hostname, user)
 	if err != nil {
 		return nil, err
 	}
@@ -491,16 +469,27 @@ func getAuthenticatedClient(hostname string) (*api.Client, error) {
 		if len(parts) != 2 {
 			return nil, fmt.Errorf("invalid credentials format")
 		}
-		return api.NewClient(api.WithBasicAuth(parts[0], parts[1])), nil
+		return newHostClient(hosts, hostname, api.WithBasicAuth(parts[0], parts[1]))
 	}
 
 	// Try to parse as JSON (OAuth token)
-	var tokenResp oauthTokenResponse
+	var tokenResp api.OAuthToken
 	if err := json.Unmarshal([]byte(tokenData), &tokenResp); err == nil && tokenResp.AccessToken != "" {
-		return api.NewClient(api.WithToken(tokenResp.AccessToken)), nil
+		ts := cmdutil.NewOAuthTokenSource(hostname, user, source, tokenResp)
+		return newHostClient(hosts, hostname, api.WithTokenSource(ts))
 	}
 
-	return api.NewClient(api.WithToken(tokenData)), nil
+	return newHostClient(hosts, hostname, api.WithToken(tokenData))
+}
+
+// newHostClient creates an API client for hostname with the given
+// authentication and the host's network settings.
+func newHostClient(hosts config.HostsConfig, hostname string, auth api.ClientOption) (*api.Client, error) {
+	networkOpts, err := cmdutil.HostClientOptions(hosts, hostname)
+	if err != nil {
+		return nil, err
+	}
+	return api.NewClient(append([]api.ClientOption{api.WithBaseURL(hosts.APIURL(hostname)), auth}, networkOpts...)...), nil
 }
 
 func performOAuthFlow(opts *loginOptions, clientID, clientSecret string) error {
@@ -626,7 +615,10 @@ func performOAuthFlow(opts *loginOptions, clientID, clientSecret string) error {
 	}
 
 	// Validate token and get user info
-	client := api.NewClient(api.WithToken(tokenResp.AccessToken))
+	client, err := opts.newClient(api.WithToken(tokenResp.AccessToken))
+	if err != nil {
+		return err
+	}
 	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -635,41 +627,106 @@ func performOAuthFlow(opts *loginOptions, clientID, clientSecret string) error {
 		return fmt.Errorf("failed to get user info: %w", err)
 	}
 
-	// Store tokens in keyring (as JSON with refresh token)
+	// Store tokens in keyring (as JSON with refresh token), along with the
+	// consumer so that the token can be refreshed
+	tokenResp.ClientID = clientID
+	tokenResp.ClientSecret = clientSecret
+	tokenResp.SetExpiry()
 	tokenData, err := json.Marshal(tokenResp)
 	if err != nil {
 		return fmt.Errorf("failed to marshal token: %w", err)
 	}
 
-	if err := config.SetToken(opts.hostname, user.Username, string(tokenData)); err != nil {
+	if err := opts.storeToken(user.Username, string(tokenData)); err != nil {
 		return fmt.Errorf("failed to store token: %w", err)
 	}
 
-	// Update hosts config
+	if err := saveHost(opts, user.Username, config.AuthMethodOAuth); err != nil {
+		return err
+	}
+
+	opts.streams.Success("Logged in as: %s (%s)", user.DisplayName, user.Username)
+	warnMissingScopes(opts.streams, client)
+	return nil
+}
+
+// newClient creates an API client for the host being logged in to.
+func (opts *loginOptions) newClient(auth api.ClientOption) (*api.Client, error) {
+	hosts, err := config.LoadHostsConfig()
+	if err != nil {
+		hosts = make(config.HostsConfig)
+	}
+	baseURL := opts.apiURL
+	if baseURL == "" {
+		baseURL = hosts.APIURL(opts.hostname)
+	}
+	networkOpts, err := cmdutil.HostClientOptions(hosts, opts.hostname)
+	if err != nil {
+		return nil, err
+	}
+	return api.NewClient(append([]api.ClientOption{api.WithBaseURL(baseURL), auth}, networkOpts...)...), nil
+}
+
+// storeToken stores token in the credential store. If the OS keyring is
+// unavailable, the token is stored in an obfuscated file instead, but only
+// with --insecure-storage.
+func (opts *loginOptions) storeToken(user, token string) error {
+	err := config.SetToken(opts.hostname, user, token)
+	if !errors.Is(err, config.ErrKeyringUnavailable) {
+		return err
+	}
+	if !opts.insecureStorage {
+		return fmt.Errorf("%w\nUse --insecure-storage to store the token in an obfuscated file instead, or choose another store with 'bb config set credential_store'", err)
+	}
+
+	if err := config.SetFallbackToken(opts.hostname, user, token); err != nil {
+		return err
+	}
+	opts.streams.Warning("No OS keyring is available, so the token was stored in %s in the config directory", config.FallbackCredentialsFileName)
+	opts.streams.Warning("The file's key is stored next to it: anyone who can read your config directory can read the token")
+	return nil
+}
+
+// warnMissingScopes warns when the token lacks scopes that bb needs. Tokens
+// whose scopes the server doesn't report are not checked.
+func warnMissingScopes(streams *iostreams.IOStreams, client *api.Client) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	granted, err := client.GetScopes(ctx)
+	if err != nil || granted == nil {
+		return
+	}
+	if missing := api.MissingScopes(granted, api.RequiredScopes); len(missing) > 0 {
+		streams.Warning("The token is missing scopes that bb needs: %s. Commands that use them will fail until you log in with a token that has them", strings.Join(missing, ", "))
+	}
+}
+
+// saveHost records user as the active user of the host in hosts.yml, along
+// with how they logged in and the host's API URL if one was given.
+func saveHost(opts *loginOptions, user, method string) error {
 	hosts, err := config.LoadHostsConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load hosts config: %w", err)
 	}
 
-	hosts.SetActiveUser(opts.hostname, user.Username)
+	hosts.SetActiveUser(opts.hostname, user)
+	hosts[opts.hostname].AuthMethod = method
+	if opts.apiURL != "" {
+		hosts[opts.hostname].APIURL = opts.apiURL
+	}
 
 	if err := config.SaveHostsConfig(hosts); err != nil {
 		return fmt.Errorf("failed to save hosts config: %w", err)
 	}
 
-	opts.streams.Success("Logged in as: %s (%s)", user.DisplayName, user.Username)
+	if store, err := config.ActiveCredentialStore(); err == nil && store.Name() == config.CredentialStorePlaintext {
+		opts.streams.Warning("Token stored unencrypted in %s; use only where nothing else can read it", config.PlaintextCredentialsFileName)
+	}
 	return nil
 }
 
-type oauthTokenResponse struct {
-	AccessToken  string `json:"access_token"`
-	TokenType    string `json:"token_type"`
-	ExpiresIn    int    `json:"expires_in"`
-	RefreshToken string `json:"refresh_token"`
-	Scopes       string `json:"scopes"`
-}
-
-func exchangeCodeForToken(clientID, clientSecret, code, redirectURI string) (*oauthTokenResponse, error) {
+func exchangeCodeForToken(clientID, clientSecret, code, redirectURI string) (*api.OAuthToken, error) {
 	data := url.Values{}
 	data.Set("grant_type", "authorization_code")
 	data.Set("code", code)
@@ -683,7 +740,16 @@ func exchangeCodeForToken(clientID, clientSecret, code, redirectURI string) (*oa
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.SetBasicAuth(clientID, clientSecret)
 
-	resp, err := http.DefaultClient.Do(req)
+	hosts, err := config.LoadHostsConfig()
+	if err != nil {
+		return nil, err
+	}
+	httpClient, err := cmdutil.NewHTTPClientFor(hosts, config.DefaultHost, api.DefaultTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -693,7 +759,7 @@ func exchangeCodeForToken(clientID, clientSecret, code, redirectURI string) (*oa
 		return nil, fmt.Errorf("token exchange failed with status %d", resp.StatusCode)
 	}
 
-	var tokenResp oauthTokenResponse
+	var tokenResp api.OAuthToken
 	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
 		return nil, err
 	}
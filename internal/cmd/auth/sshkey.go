@@ -0,0 +1,252 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+)
+
+// defaultSSHKeyTitle labels keys uploaded by bb
+const defaultSSHKeyTitle = "bb CLI"
+
+// offerSSHKey checks that git can reach the host over SSH and, if it can't,
+// offers to generate an ed25519 key, add it to the account and point the
+// SSH config at it. Failures are reported as warnings; login has already
+// succeeded by the time this runs.
+func offerSSHKey(opts *loginOptions) {
+	if opts.skipSSHKey || !opts.streams.CanPrompt() || !wantsSSH(opts.hostname) {
+		return
+	}
+	if _, err := exec.LookPath("ssh"); err != nil {
+		return
+	}
+	if hasSSHAccess(opts.hostname) {
+		return
+	}
+
+	sshDir, err := sshDir()
+	if err != nil {
+		return
+	}
+	keyPath := filepath.Join(sshDir, "id_ed25519")
+	pubPath := keyPath + ".pub"
+	_, statErr := os.Stat(pubPath)
+	haveKey := statErr == nil
+
+	fmt.Fprintln(opts.streams.Out, "")
+	question := "Generate a new SSH key and add it to your Bitbucket account?"
+	if haveKey {
+		question = fmt.Sprintf("Add your SSH public key %s to your Bitbucket account?", pubPath)
+	}
+	ok, err := opts.streams.PromptConfirm("git can't connect to "+opts.hostname+" over SSH. "+question, true)
+	if err != nil || !ok {
+		fmt.Fprintln(opts.streams.Out, "You can add an SSH key later at "+sshKeysURL(opts.hostname))
+		return
+	}
+
+	if !haveKey {
+		if err := generateSSHKey(opts, keyPath); err != nil {
+			opts.streams.Warning("Could not generate an SSH key: %v", err)
+			return
+		}
+	}
+
+	if err := uploadSSHKey(opts, pubPath); err != nil {
+		opts.streams.Warning("Could not add the SSH key to your account: %v", err)
+		return
+	}
+
+	added, err := addSSHConfigHost(filepath.Join(sshDir, "config"), opts.hostname, keyPath)
+	if err != nil {
+		opts.streams.Warning("Could not update the SSH config: %v", err)
+		return
+	}
+	if added {
+		opts.streams.Success("Added %s to %s", opts.hostname, filepath.Join(sshDir, "config"))
+	}
+}
+
+// wantsSSH reports whether git is configured to talk to host over SSH.
+func wantsSSH(host string) bool {
+	if hosts, err := config.LoadHostsConfig(); err == nil {
+		if protocol := hosts.GetGitProtocol(host); protocol != "" {
+			return protocol == "ssh"
+		}
+	}
+	resolver, err := config.Resolve()
+	if err != nil {
+		return true
+	}
+	return resolver.Get("git_protocol").Value != "https"
+}
+
+// sshKeysURL returns the page of host's website that manages the SSH keys
+// of the logged-in account.
+func sshKeysURL(host string) string {
+	if host == config.DefaultHost {
+		return "https://bitbucket.org/account/settings/ssh-keys/"
+	}
+	return "https://" + host + "/plugins/servlet/ssh/account/keys"
+}
+
+// hasSSHAccess reports whether an SSH key is accepted by host. Bitbucket
+// closes the session straight away, exiting with status 0, when it is. A
+// host not yet in known_hosts is added, as BatchMode would otherwise refuse
+// it and the check would wrongly fail.
+func hasSSHAccess(host string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "ssh", "-T",
+		"-o", "BatchMode=yes",
+		"-o", "StrictHostKeyChecking=accept-new",
+		"-o", "ConnectTimeout=10",
+		"git@"+host)
+	return cmd.Run() == nil
+}
+
+func sshDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".ssh"), nil
+}
+
+func generateSSHKey(opts *loginOptions, keyPath string) error {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		return fmt.Errorf("ssh-keygen is not installed")
+	}
+	if err := os.MkdirAll(filepath.Dir(keyPath), 0700); err != nil {
+		return err
+	}
+
+	comment := "bb"
+	if name, err := os.Hostname(); err == nil {
+		comment = "bb@" + name
+	}
+
+	// ssh-keygen asks for the passphrase itself, on the terminal, twice to
+	// confirm it, so that it never appears on a command line
+	cmd := exec.Command("ssh-keygen", "-t", "ed25519", "-C", comment, "-f", keyPath, "-q")
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = opts.streams.Out
+	cmd.Stderr = opts.streams.ErrOut
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ssh-keygen failed: %w", err)
+	}
+
+	opts.streams.Success("Generated SSH key %s", keyPath)
+	return nil
+}
+
+func uploadSSHKey(opts *loginOptions, pubPath string) error {
+	data, err := os.ReadFile(pubPath)
+	if err != nil {
+		return err
+	}
+	key := strings.TrimSpace(string(data))
+
+	client, err := getAuthenticatedClient(opts.hostname)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	user, err := client.GetCurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	existing, err := client.ListSSHKeys(ctx, user.UUID)
+	if err != nil {
+		return err
+	}
+	for _, k := range existing.Values {
+		if sameSSHKey(k.Key, key) {
+			opts.streams.Info("The key %s is already on your account", pubPath)
+			return nil
+		}
+	}
+
+	title, err := opts.streams.PromptText("Title for the SSH key", defaultSSHKeyTitle)
+	if err != nil {
+		return err
+	}
+	if title == "" {
+		title = defaultSSHKeyTitle
+	}
+
+	if _, err := client.AddSSHKey(ctx, user.UUID, key, title); err != nil {
+		return err
+	}
+
+	opts.streams.Success("Added SSH key %q to your account", title)
+	return nil
+}
+
+// sameSSHKey compares two authorized_keys lines, ignoring their comments.
+func sameSSHKey(a, b string) bool {
+	fa, fb := strings.Fields(a), strings.Fields(b)
+	return len(fa) >= 2 && len(fb) >= 2 && fa[0] == fb[0] && fa[1] == fb[1]
+}
+
+// addSSHConfigHost appends a Host block for host using keyPath to the SSH
+// config at path, unless the config already has one. It reports whether
+// the config was changed.
+func addSSHConfigHost(path, host, keyPath string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	if sshConfigHasHost(string(data), host) {
+		return false, nil
+	}
+
+	var b strings.Builder
+	if len(data) > 0 {
+		b.Write(data)
+		if !strings.HasSuffix(string(data), "\n") {
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "Host %s\n", host)
+	b.WriteString("  AddKeysToAgent yes\n")
+	fmt.Fprintf(&b, "  IdentityFile %s\n", keyPath)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// sshConfigHasHost reports whether an SSH config has a Host line naming
+// host exactly.
+func sshConfigHasHost(content, host string) bool {
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "Host") {
+			continue
+		}
+		for _, pattern := range fields[1:] {
+			if strings.EqualFold(pattern, host) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSSHConfigHasHost(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		host    string
+		want    bool
+	}{
+		{"empty config", "", "bitbucket.org", false},
+		{"exact host", "Host bitbucket.org\n  IdentityFile ~/.ssh/id_ed25519\n", "bitbucket.org", true},
+		{"one of several patterns", "Host github.com bitbucket.org\n", "bitbucket.org", true},
+		{"case-insensitive keyword and host", "host Bitbucket.org\n", "bitbucket.org", true},
+		{"indented", "  Host bitbucket.org\n", "bitbucket.org", true},
+		{"other host", "Host github.com\n", "bitbucket.org", false},
+		{"wildcard is not an exact match", "Host *.org\n", "bitbucket.org", false},
+		{"HostName is not Host", "Host work\n  HostName bitbucket.org\n", "bitbucket.org", false},
+		{"Host without a pattern", "Host\n", "bitbucket.org", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sshConfigHasHost(tt.content, tt.host); got != tt.want {
+				t.Errorf("sshConfigHasHost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddSSHConfigHost(t *testing.T) {
+	block := "Host bitbucket.org\n  AddKeysToAgent yes\n  IdentityFile /keys/id_ed25519\n"
+
+	tests := []struct {
+		name      string
+		existing  *string
+		wantAdded bool
+		want      string
+	}{
+		{
+			name:      "no config",
+			wantAdded: true,
+			want:      block,
+		},
+		{
+			name:      "appends after existing config",
+			existing:  strPtr("Host github.com\n  User git\n"),
+			wantAdded: true,
+			want:      "Host github.com\n  User git\n\n" + block,
+		},
+		{
+			name:      "adds missing final newline",
+			existing:  strPtr("Host github.com"),
+			wantAdded: true,
+			want:      "Host github.com\n\n" + block,
+		},
+		{
+			name:      "leaves a config that has the host",
+			existing:  strPtr("Host bitbucket.org\n  User git\n"),
+			wantAdded: false,
+			want:      "Host bitbucket.org\n  User git\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".ssh", "config")
+			if tt.existing != nil {
+				if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(*tt.existing), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			added, err := addSSHConfigHost(path, "bitbucket.org", "/keys/id_ed25519")
+			if err != nil {
+				t.Fatalf("addSSHConfigHost() returned error: %v", err)
+			}
+			if added != tt.wantAdded {
+				t.Errorf("addSSHConfigHost() = %v, want %v", added, tt.wantAdded)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("config = %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameSSHKey(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{"identical", "ssh-ed25519 AAAAC3Nz jane@laptop", "ssh-ed25519 AAAAC3Nz jane@laptop", true},
+		{"different comments", "ssh-ed25519 AAAAC3Nz jane@laptop", "ssh-ed25519 AAAAC3Nz bb CLI", true},
+		{"no comment", "ssh-ed25519 AAAAC3Nz", "ssh-ed25519 AAAAC3Nz jane@laptop", true},
+		{"different key", "ssh-ed25519 AAAAC3Nz", "ssh-ed25519 AAAAB3Nz", false},
+		{"different type", "ssh-ed25519 AAAAC3Nz", "ssh-rsa AAAAC3Nz", false},
+		{"malformed", "ssh-ed25519", "ssh-ed25519", false},
+		{"empty", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameSSHKey(tt.a, tt.b); got != tt.want {
+				t.Errorf("sameSSHKey(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSSHKeysURL(t *testing.T) {
+	if got, want := sshKeysURL("bitbucket.org"), "https://bitbucket.org/account/settings/ssh-keys/"; got != want {
+		t.Errorf("sshKeysURL(bitbucket.org) = %q, want %q", got, want)
+	}
+	if got, want := sshKeysURL("bitbucket.example.com"), "https://bitbucket.example.com/plugins/servlet/ssh/account/keys"; got != want {
+		t.Errorf("sshKeysURL(bitbucket.example.com) = %q, want %q", got, want)
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
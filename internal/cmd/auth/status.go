@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
 	"github.com/rbansal42/bitbucket-cli/internal/config"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
@@ -17,6 +18,9 @@ import (
 type statusOptions struct {
 	streams  *iostreams.IOStreams
 	hostname string
+	// useProfile is set when --hostname was not given, so the active
+	// profile's account is shown
+	useProfile bool
 }
 
 // NewCmdStatus creates the status command
@@ -35,6 +39,7 @@ including the logged-in user and token status.`,
 		Example: `  # Check authentication status
   $ bb auth status`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.useProfile = !cmd.Flags().Changed("hostname")
 			return runStatus(opts)
 		},
 	}
@@ -51,6 +56,17 @@ func runStatus(opts *statusOptions) error {
 	}
 
 	user := hosts.GetActiveUser(opts.hostname)
+	var profile string
+	if opts.useProfile {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("could not load config: %w", err)
+		}
+		profile = cfg.ActiveProfileName()
+		if opts.hostname, user, err = config.ActiveAccount(hosts); err != nil {
+			return err
+		}
+	}
 	if user == "" {
 		opts.streams.Info("%s", opts.hostname)
 		opts.streams.Error("Not logged in to %s", opts.hostname)
@@ -68,6 +84,7 @@ func runStatus(opts *statusOptions) error {
 
 	// Create API client based on token type
 	var client *api.Client
+	var clientErr error
 	var displayToken string
 
 	if strings.HasPrefix(tokenData, "basic:") {
@@ -79,17 +96,28 @@ func runStatus(opts *statusOptions) error {
 			opts.streams.Error("Invalid stored credentials format for %s", user)
 			return nil
 		}
-		client = api.NewClient(api.WithBasicAuth(parts[0], parts[1]))
+		client, clientErr = newHostClient(hosts, opts.hostname, api.WithBasicAuth(parts[0], parts[1]))
 		displayToken = parts[1] // Show API token portion
 	} else {
 		// Try to parse as JSON (OAuth token) or use as plain token
-		var tokenResp oauthTokenResponse
-		if err := json.Unmarshal([]byte(tokenData), &tokenResp); err == nil && tokenResp.AccessToken != "" {
+		var tokenResp api.OAuthToken
+		if err := json.Unmarshal([]byte(tokenData), &tokenResp); err == nil && tokenResp.AccessToken != "" && source != config.CredentialSourceEnv {
+			ts := cmdutil.NewOAuthTokenSource(opts.hostname, user, source, tokenResp)
+			client, clientErr = newHostClient(hosts, opts.hostname, api.WithTokenSource(ts))
 			displayToken = tokenResp.AccessToken
 		} else {
-			displayToken = tokenData
+			if tokenResp.AccessToken != "" {
+				displayToken = tokenResp.AccessToken
+			} else {
+				displayToken = tokenData
+			}
+			client, clientErr = newHostClient(hosts, opts.hostname, api.WithToken(displayToken))
 		}
-		client = api.NewClient(api.WithToken(displayToken))
+	}
+	if clientErr != nil {
+		opts.streams.Info("%s", opts.hostname)
+		opts.streams.Error("%v", clientErr)
+		return nil
 	}
 
 	// Validate token by making an API request
@@ -107,8 +135,27 @@ func runStatus(opts *statusOptions) error {
 	// Print status
 	opts.streams.Info("%s", opts.hostname)
 	opts.streams.Success("Logged in to %s account %s (%s)", opts.hostname, apiUser.Username, source)
+	if source == config.CredentialSourceFallback {
+		opts.streams.Warning("The token is stored in an obfuscated file whose key is next to it, because no OS keyring was available")
+	}
 	opts.streams.Info("  - Active account: true")
+	if profile != "" {
+		opts.streams.Info("  - Profile: %s", profile)
+	}
 	opts.streams.Info("  - Git operations protocol: %s", hosts.GetGitProtocol(opts.hostname))
+	if method := hosts.GetAuthMethod(opts.hostname); method != "" {
+		opts.streams.Info("  - Auth method: %s", method)
+	}
+	if opts.hostname != config.DefaultHost {
+		opts.streams.Info("  - API URL: %s", hosts.APIURL(opts.hostname))
+	}
+
+	if scopes, err := client.GetScopes(ctx); err == nil && scopes != nil {
+		opts.streams.Info("  - Token scopes: %s", strings.Join(scopes, ", "))
+		if missing := api.MissingScopes(scopes, api.RequiredScopes); len(missing) > 0 {
+			opts.streams.Warning("Missing scopes: %s", strings.Join(missing, ", "))
+		}
+	}
 
 	// Mask token for display
 	maskedToken := maskToken(displayToken)
@@ -1,11 +1,15 @@
 package auth
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
 	"github.com/rbansal42/bitbucket-cli/internal/config"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
@@ -55,15 +59,25 @@ func runToken(opts *tokenOptions) error {
 	}
 
 	// Get token
-	tokenData, _, err := config.GetTokenFromEnvOrKeyring(opts.hostname, user)
+	tokenData, source, err := config.GetTokenFromEnvOrKeyring(opts.hostname, user)
 	if err != nil {
 		return fmt.Errorf("failed to get token: %w", err)
 	}
 
-	// Try to parse as JSON (OAuth token) or use as plain token
-	var tokenResp oauthTokenResponse
+	// Try to parse as JSON (OAuth token) or use as plain token. An expired
+	// OAuth token is refreshed first.
+	var tokenResp api.OAuthToken
 	if err := json.Unmarshal([]byte(tokenData), &tokenResp); err == nil && tokenResp.AccessToken != "" {
-		fmt.Println(tokenResp.AccessToken)
+		token := tokenResp.AccessToken
+		if source != config.CredentialSourceEnv {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			token, err = cmdutil.NewOAuthTokenSource(opts.hostname, user, source, tokenResp).Token(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		fmt.Println(token)
 	} else {
 		fmt.Println(tokenData)
 	}
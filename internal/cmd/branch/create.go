@@ -10,6 +10,7 @@ import (
 
 	"github.com/rbansal42/bitbucket-cli/internal/api"
 	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
 
@@ -18,6 +19,8 @@ type CreateOptions struct {
 	BranchName string
 	Repo       string
 	Target     string
+	Type       string
+	Checkout   bool
 	JSON       bool
 	Streams    *iostreams.IOStreams
 }
@@ -29,18 +32,32 @@ func NewCmdCreate(streams *iostreams.IOStreams) *cobra.Command {
 	}
 
 	cmd := &cobra.Command{
-		Use:   "create <branch-name>",
+		Use:   "create {<branch-name> | --type <type> <description>}",
 		Short: "Create a new branch",
 		Long: `Create a new branch in a Bitbucket repository.
 
-You must specify the target branch, tag, or commit to branch from using --target.
-By default, this command detects the repository from your git remote.`,
-		Example: `  # Create a branch from main
-  bb branch create feature-branch --target main
+The new branch starts from the branch, tag, or commit given with --target,
+or from the repository's default branch.
+By default, this command detects the repository from your git remote.
+
+With --type, the branch follows the repository's branching model: it is
+named after the description with the prefix of that type of branch, such
+as feature/, and starts from the development branch, or the production
+branch for hotfixes. The branch is then also fetched and checked out when
+run in a clone of the repository, unless --checkout=false is given.`,
+		Example: `  # Create a branch from the default branch
+  bb branch create feature-branch
+
+  # Create a branch from develop
+  bb branch create feature-branch --target develop
 
   # Create a branch from a specific commit
   bb branch create hotfix-branch --target abc1234
 
+  # Create feature/fix-login-on-safari from the development branch and
+  # check it out
+  bb branch create --type feature "Fix login on Safari"
+
   # Create a branch in a specific repository
   bb branch create feature-branch --target main --repo myworkspace/myrepo
 
@@ -49,15 +66,20 @@ By default, this command detects the repository from your git remote.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.BranchName = args[0]
+			if opts.Type != "" && !cmd.Flags().Changed("checkout") {
+				opts.Checkout = true
+			}
 			return runCreate(cmd.Context(), opts)
 		},
 	}
 
 	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format (detects from git remote if not specified)")
-	cmd.Flags().StringVarP(&opts.Target, "target", "t", "", "Branch, tag, or commit to branch from (required)")
+	cmd.Flags().StringVarP(&opts.Target, "target", "t", "", "Branch, tag, or commit to branch from (default: the default branch)")
+	cmd.Flags().StringVar(&opts.Type, "type", "", "Name the branch after the description with the prefix of this branching model type: {feature|bugfix|release|hotfix}")
+	cmd.Flags().BoolVar(&opts.Checkout, "checkout", false, "Fetch the new branch and check it out (default with --type)")
 	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
 
-	cmd.MarkFlagRequired("target")
+	_ = cmd.RegisterFlagCompletionFunc("type", cobra.FixedCompletions(branchTypes, cobra.ShellCompDirectiveNoFileComp))
 
 	return cmd
 }
@@ -79,9 +101,30 @@ func runCreate(ctx context.Context, opts *CreateOptions) error {
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
+	target := opts.Target
+	if opts.Type != "" {
+		model, err := client.GetBranchingModel(ctx, workspace, repoSlug)
+		if err != nil {
+			return fmt.Errorf("failed to get branching model: %w", err)
+		}
+		opts.BranchName, err = modelBranchName(model, opts.Type, opts.BranchName)
+		if err != nil {
+			return err
+		}
+		if target == "" {
+			target = modelSourceBranch(model, opts.Type)
+		}
+	}
+	if target == "" {
+		target, err = cmdutil.DefaultBranch(ctx, client, workspace, repoSlug)
+		if err != nil {
+			return fmt.Errorf("could not determine the default branch, use --target: %w", err)
+		}
+	}
+
 	// Try to resolve target as a branch first to get the commit hash
-	commitHash := opts.Target
-	branch, err := client.GetBranch(ctx, workspace, repoSlug, opts.Target)
+	commitHash := target
+	branch, err := client.GetBranch(ctx, workspace, repoSlug, target)
 	if err == nil && branch.Target != nil {
 		// Target is a branch, use its commit hash
 		commitHash = branch.Target.Hash
@@ -101,15 +144,52 @@ func runCreate(ctx context.Context, opts *CreateOptions) error {
 		return fmt.Errorf("failed to create branch: %w", err)
 	}
 
+	checkedOut := false
+	if opts.Checkout {
+		checkedOut, err = checkoutNewBranch(opts.Streams, workspace, repoSlug, opts.BranchName)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Output results
 	if opts.JSON {
 		return outputCreateJSON(opts.Streams, newBranch)
 	}
 
-	opts.Streams.Success("Created branch %s in %s/%s", opts.BranchName, workspace, repoSlug)
+	opts.Streams.Success("Created branch %s in %s/%s from %s", opts.BranchName, workspace, repoSlug, target)
+	if checkedOut {
+		opts.Streams.Success("Switched to branch '%s'", opts.BranchName)
+	}
 	return nil
 }
 
+// checkoutNewBranch fetches a branch just created on Bitbucket into the
+// clone of its repository and checks it out, tracking the remote branch.
+// It reports whether the branch was checked out, which it isn't outside a
+// clone of the repository.
+func checkoutNewBranch(streams *iostreams.IOStreams, workspace, repoSlug, branch string) (bool, error) {
+	remote := cmdutil.LocalRemoteFor(workspace, repoSlug)
+	if remote == "" {
+		streams.Warning("Not in a clone of %s/%s, so %s was not checked out", workspace, repoSlug, branch)
+		return false, nil
+	}
+
+	// git may ask for credentials on the terminal, so no spinner runs
+	// over it
+	streams.Info("Fetching %s...", branch)
+	if err := git.Fetch(remote, branch+":"+branch); err != nil {
+		return false, fmt.Errorf("created %s, but failed to fetch it: %w", branch, err)
+	}
+	if err := git.SetUpstream(branch, remote); err != nil {
+		streams.Warning("Could not set upstream tracking: %v", err)
+	}
+	if err := git.Checkout(branch); err != nil {
+		return false, fmt.Errorf("created %s, but failed to check it out: %w", branch, err)
+	}
+	return true, nil
+}
+
 func outputCreateJSON(streams *iostreams.IOStreams, branch *api.BranchFull) error {
 	output := map[string]interface{}{
 		"name": branch.Name,
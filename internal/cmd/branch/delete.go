@@ -44,7 +44,8 @@ By default, this command detects the repository from your git remote.`,
 
   # Delete a branch in a specific repository
   bb branch delete feature-branch --repo myworkspace/myrepo`,
-		Args: cobra.ExactArgs(1),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: cmdutil.CompleteBranches,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.BranchName = args[0]
 			return runDelete(cmd.Context(), opts)
@@ -64,6 +65,20 @@ func runDelete(ctx context.Context, opts *DeleteOptions) error {
 		return err
 	}
 
+	// Get API client
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	// Deleting the default branch would leave the repository without one
+	checkCtx, checkCancel := context.WithTimeout(ctx, 30*time.Second)
+	defaultBranch, err := cmdutil.DefaultBranch(checkCtx, client, workspace, repoSlug)
+	checkCancel()
+	if err == nil && defaultBranch == opts.BranchName {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("cannot delete %s, the default branch of %s/%s", opts.BranchName, workspace, repoSlug))
+	}
+
 	// If not forced, prompt for confirmation
 	if !opts.Force {
 		// Require TTY for interactive confirmation
@@ -85,12 +100,6 @@ func runDelete(ctx context.Context, opts *DeleteOptions) error {
 		}
 	}
 
-	// Get API client
-	client, err := cmdutil.GetAPIClient()
-	if err != nil {
-		return err
-	}
-
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
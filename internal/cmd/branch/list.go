@@ -3,7 +3,8 @@ package branch
 import (
 	"context"
 	"fmt"
-	"text/tabwriter"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -13,12 +14,22 @@ import (
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
 
+// divergenceConcurrency is how many branches are compared with the base at
+// once, and divergenceLimit the most commits counted on each side
+const (
+	divergenceConcurrency = 8
+	divergenceLimit       = 1000
+)
+
 // ListOptions holds the options for the list command
 type ListOptions struct {
-	Repo    string
-	Limit   int
-	JSON    bool
-	Streams *iostreams.IOStreams
+	Repo        string
+	Limit       int
+	AheadBehind bool
+	Base        string
+	JSON        bool
+	Format      string
+	Streams     *iostreams.IOStreams
 }
 
 // NewCmdList creates the branch list command
@@ -33,7 +44,12 @@ func NewCmdList(streams *iostreams.IOStreams) *cobra.Command {
 		Long: `List branches in a Bitbucket repository.
 
 By default, this command detects the repository from your git remote.
-Use the --repo flag to specify a different repository.`,
+Use the --repo flag to specify a different repository.
+
+With --ahead-behind, each branch is compared with the repository's main
+branch, or the branch given with --base, showing how many commits it has
+that the base doesn't (ahead) and the base has that it doesn't (behind).
+Counts stop at 1000. This takes two requests per branch.`,
 		Example: `  # List branches in the current repository
   bb branch list
 
@@ -43,17 +59,32 @@ Use the --repo flag to specify a different repository.`,
   # Limit the number of branches shown
   bb branch list --limit 10
 
+  # Show how far each branch has diverged from the main branch
+  bb branch list --ahead-behind
+
+  # Compare each branch with develop instead
+  bb branch list --ahead-behind --base develop
+
   # Output as JSON
-  bb branch list --json`,
+  bb branch list --json
+
+  # Output as YAML
+  bb branch list --format yaml`,
 		Aliases: []string{"ls"},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Base != "" && !opts.AheadBehind {
+				return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("--base requires --ahead-behind"))
+			}
 			return runList(cmd.Context(), opts)
 		},
 	}
 
 	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format (detects from git remote if not specified)")
 	cmd.Flags().IntVarP(&opts.Limit, "limit", "l", 30, "Maximum number of branches to list")
+	cmd.Flags().BoolVar(&opts.AheadBehind, "ahead-behind", false, "Show how many commits each branch is ahead of and behind the base")
+	cmd.Flags().StringVar(&opts.Base, "base", "", "Branch to compare with for --ahead-behind (default: the main branch)")
 	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
 
 	return cmd
 }
@@ -81,7 +112,9 @@ func runList(ctx context.Context, opts *ListOptions) error {
 	}
 
 	// Fetch branches
+	progress := opts.Streams.StartProgress("Fetching branches")
 	result, err := client.ListBranches(ctx, workspace, repoSlug, listOpts)
+	progress.Stop()
 	if err != nil {
 		return fmt.Errorf("failed to list branches: %w", err)
 	}
@@ -91,16 +124,86 @@ func runList(ctx context.Context, opts *ListOptions) error {
 		return nil
 	}
 
+	var divergences map[string]*api.Divergence
+	if opts.AheadBehind {
+		base := opts.Base
+		if base == "" {
+			repo, err := client.GetRepository(ctx, workspace, repoSlug)
+			if err != nil {
+				return fmt.Errorf("failed to get repository: %w", err)
+			}
+			if repo.MainBranch == nil || repo.MainBranch.Name == "" {
+				return fmt.Errorf("%s/%s has no main branch to compare with; use --base", workspace, repoSlug)
+			}
+			base = repo.MainBranch.Name
+		}
+		progress := opts.Streams.StartProgress("Comparing branches with " + base)
+		divergences = compareBranches(ctx, client, workspace, repoSlug, base, result.Values)
+		progress.Stop()
+	}
+
+	if err := opts.Streams.StartPager(); err != nil {
+		opts.Streams.Warning("%s", err)
+	}
+	defer opts.Streams.StopPager()
+
 	// Output results
-	if opts.JSON {
-		return outputListJSON(opts.Streams, result.Values)
+	if opts.JSON || opts.Format != "" {
+		return outputListStructured(opts.Streams, opts.Format, result.Values, divergences)
 	}
 
-	return outputTable(opts.Streams, result.Values)
+	return outputTable(opts.Streams, result.Values, divergences)
 }
 
-func outputListJSON(streams *iostreams.IOStreams, branches []api.BranchFull) error {
-	// Create simplified JSON output
+// compareBranches counts the commits each branch is ahead of and behind
+// base. The base itself, and branches that couldn't be compared, are left
+// out.
+func compareBranches(ctx context.Context, client *api.Client, workspace, repoSlug, base string, branches []api.BranchFull) map[string]*api.Divergence {
+	var (
+		wg          sync.WaitGroup
+		mu          sync.Mutex
+		limiter     = make(chan struct{}, divergenceConcurrency)
+		divergences = make(map[string]*api.Divergence)
+	)
+	for _, branch := range branches {
+		if branch.Name == base {
+			continue
+		}
+		wg.Go(func() {
+			limiter <- struct{}{}
+			defer func() { <-limiter }()
+
+			var d api.Divergence
+			var err error
+			d.Ahead, d.AheadMore, err = client.CountCommitsBetween(ctx, workspace, repoSlug, branch.Name, base, divergenceLimit)
+			if err != nil {
+				return
+			}
+			d.Behind, d.BehindMore, err = client.CountCommitsBetween(ctx, workspace, repoSlug, base, branch.Name, divergenceLimit)
+			if err != nil {
+				return
+			}
+
+			mu.Lock()
+			divergences[branch.Name] = &d
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+	return divergences
+}
+
+// formatCount formats a commit count, marking one that stopped at the limit
+func formatCount(n int, more bool) string {
+	s := strconv.Itoa(n)
+	if more {
+		s += "+"
+	}
+	return s
+}
+
+func outputListStructured(streams *iostreams.IOStreams, format string, branches []api.BranchFull, divergences map[string]*api.Divergence) error {
+	// Create simplified output
 	output := make([]map[string]interface{}, len(branches))
 	for i, branch := range branches {
 		item := map[string]interface{}{
@@ -110,18 +213,28 @@ func outputListJSON(streams *iostreams.IOStreams, branches []api.BranchFull) err
 			item["commit"] = branch.Target.Hash
 			item["message"] = branch.Target.Message
 		}
+		if d, ok := divergences[branch.Name]; ok {
+			item["ahead"] = d.Ahead
+			item["behind"] = d.Behind
+			if d.AheadMore || d.BehindMore {
+				item["truncated"] = true
+			}
+		}
 		output[i] = item
 	}
 
-	return cmdutil.PrintJSON(streams, output)
+	return cmdutil.PrintFormatted(streams, format, output)
 }
 
-func outputTable(streams *iostreams.IOStreams, branches []api.BranchFull) error {
-	w := tabwriter.NewWriter(streams.Out, 0, 0, 2, ' ', 0)
+func outputTable(streams *iostreams.IOStreams, branches []api.BranchFull, divergences map[string]*api.Divergence) error {
+	table := cmdutil.NewTablePrinter(streams)
 
 	// Print header
-	header := "NAME\tCOMMIT\tMESSAGE"
-	cmdutil.PrintTableHeader(streams, w, header)
+	if divergences != nil {
+		table.AddHeader("NAME", "COMMIT", "AHEAD", "BEHIND", "MESSAGE")
+	} else {
+		table.AddHeader("NAME", "COMMIT", "MESSAGE")
+	}
 
 	// Print rows
 	for _, branch := range branches {
@@ -137,11 +250,20 @@ func outputTable(streams *iostreams.IOStreams, branches []api.BranchFull) error
 				commit = branch.Target.Hash
 			}
 			// Truncate message to 50 chars and replace newlines
-			message = cmdutil.TruncateString(branch.Target.Message, 50)
+			message = branch.Target.Message
 		}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\n", name, commit, message)
+		if divergences == nil {
+			table.AddRow(name, commit, message)
+			continue
+		}
+		ahead, behind := "-", "-"
+		if d, ok := divergences[branch.Name]; ok {
+			ahead = streams.Style(iostreams.RoleAddition, formatCount(d.Ahead, d.AheadMore))
+			behind = streams.Style(iostreams.RoleDeletion, formatCount(d.Behind, d.BehindMore))
+		}
+		table.AddRow(name, commit, ahead, behind, message)
 	}
 
-	return w.Flush()
+	return table.Render()
 }
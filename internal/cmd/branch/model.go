@@ -0,0 +1,64 @@
+package branch
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+// branchTypes are the kinds of branch a branching model can have
+var branchTypes = []string{"feature", "bugfix", "release", "hotfix"}
+
+// maxBranchSlugLen bounds the part of a branch name made from its
+// description, so long descriptions don't make unwieldy names
+const maxBranchSlugLen = 50
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9._]+`)
+
+// branchSlug turns a description such as "Fix login on Safari" into the
+// part of a branch name after its prefix, "fix-login-on-safari"
+func branchSlug(description string) string {
+	slug := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(description), "-"), "-.")
+	if len(slug) > maxBranchSlugLen {
+		slug = strings.TrimRight(slug[:maxBranchSlugLen], "-.")
+	}
+	return slug
+}
+
+// modelBranchName returns the name of a branch of kind described by
+// description, with the prefix the branching model gives that kind
+func modelBranchName(model *api.BranchingModel, kind, description string) (string, error) {
+	var enabled []string
+	for _, t := range model.BranchTypes {
+		enabled = append(enabled, t.Kind)
+		if !strings.EqualFold(t.Kind, kind) {
+			continue
+		}
+		slug := branchSlug(description)
+		if slug == "" {
+			return "", fmt.Errorf("description %q has nothing to name the branch after", description)
+		}
+		return t.Prefix + slug, nil
+	}
+	if len(enabled) == 0 {
+		return "", fmt.Errorf("the branching model of the repository has no branch types enabled")
+	}
+	return "", fmt.Errorf("branch type %q is not enabled in the branching model; use one of: %s", kind, strings.Join(enabled, ", "))
+}
+
+// modelSourceBranch returns the branch that branches of kind start from in
+// the branching model: the production branch for hotfixes if the model has
+// one, and the development branch otherwise. It returns "" when that branch
+// doesn't exist, in which case Bitbucket uses the main branch.
+func modelSourceBranch(model *api.BranchingModel, kind string) string {
+	source := model.Development
+	if strings.EqualFold(kind, "hotfix") && model.Production != nil {
+		source = model.Production
+	}
+	if source == nil || source.Branch == nil {
+		return ""
+	}
+	return source.Branch.Name
+}
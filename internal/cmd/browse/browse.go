@@ -1,15 +1,14 @@
 package browse
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"os/exec"
-	"runtime"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
-	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
 	"github.com/rbansal42/bitbucket-cli/internal/git"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
@@ -100,14 +99,15 @@ Use flags to open specific sections like issues, pull requests, or settings.`,
 				// Path specified
 				path := args[0]
 				ref := branch
-				if ref == "" {
-					// Try to detect current branch, fall back to "main"
-					if currentBranch, err := git.GetCurrentBranch(); err == nil {
+				if ref == "" && repo == "" {
+					// Try to detect current branch
+					if currentBranch, err := git.CurrentBranch(); err == nil {
 						ref = currentBranch
-					} else {
-						ref = "main"
 					}
 				}
+				if ref == "" {
+					ref = defaultBranch(workspace, repoName)
+				}
 				url = fmt.Sprintf("%s/src/%s/%s", baseURL, ref, path)
 			case branch != "":
 				url = fmt.Sprintf("%s/src/%s", baseURL, branch)
@@ -121,18 +121,12 @@ Use flags to open specific sections like issues, pull requests, or settings.`,
 				return nil
 			}
 
-			// Get configured browser or use system default
-			browser := getBrowser()
-			if err := openBrowser(browser, url); err != nil {
-				return fmt.Errorf("could not open browser: %w", err)
-			}
-
-			streams.Success("Opened %s in your browser", url)
-			return nil
+			return cmdutil.OpenInBrowser(streams, url)
 		},
 	}
 
 	cmd.Flags().StringVarP(&branch, "branch", "b", "", "Open a specific branch")
+	_ = cmd.RegisterFlagCompletionFunc("branch", cmdutil.CompleteBranches)
 	cmd.Flags().StringVarP(&commit, "commit", "c", "", "Open a specific commit")
 	cmd.Flags().BoolVarP(&noBrowser, "no-browser", "n", false, "Print the URL instead of opening browser")
 	cmd.Flags().StringVarP(&repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
@@ -151,47 +145,22 @@ Use flags to open specific sections like issues, pull requests, or settings.`,
 func detectRepository() (string, error) {
 	remote, err := git.GetDefaultRemote()
 	if err != nil {
-		return "", fmt.Errorf("not in a git repository or no Bitbucket remote found")
+		return "", err
 	}
 
 	return remote.Workspace + "/" + remote.RepoSlug, nil
 }
 
-// getBrowser returns the configured browser or empty string for system default
-func getBrowser() string {
-	// Check environment variable
-	if browser := os.Getenv("BB_BROWSER"); browser != "" {
-		return browser
-	}
+// defaultBranch returns the default branch of the repository, or "main" if
+// it can't be determined
+func defaultBranch(workspace, repoSlug string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	// Check config
-	cfg, err := config.LoadConfig()
-	if err == nil && cfg.Browser != "" {
-		return cfg.Browser
+	// Without credentials only the local checkout is consulted
+	client, _ := cmdutil.GetAPIClient()
+	if branch, err := cmdutil.DefaultBranch(ctx, client, workspace, repoSlug); err == nil {
+		return branch
 	}
-
-	return ""
-}
-
-// openBrowser opens a URL in the browser
-func openBrowser(browser, url string) error {
-	var cmd *exec.Cmd
-
-	if browser != "" {
-		cmd = exec.Command(browser, url)
-	} else {
-		// Use system default
-		switch runtime.GOOS {
-		case "darwin":
-			cmd = exec.Command("open", url)
-		case "linux":
-			cmd = exec.Command("xdg-open", url)
-		case "windows":
-			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
-		default:
-			return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
-		}
-	}
-
-	return cmd.Start()
+	return "main"
 }
@@ -0,0 +1,53 @@
+package commit
+
+import (
+	"regexp"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// NewCmdCommit creates the commit command and its subcommands
+func NewCmdCommit(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "commit <command>",
+		Short: "Work with commits",
+		Long: `Work with the commits of a repository, such as the build statuses
+reported on them by CI systems.`,
+		Example: `  # Report a passing build from an external CI system
+  bb commit status set 1a2b3c4 --state SUCCESSFUL --key ci/test \
+    --url https://ci.example.com/builds/42`,
+	}
+
+	cmd.AddCommand(NewCmdView(streams))
+	cmd.AddCommand(NewCmdStatus(streams))
+
+	return cmd
+}
+
+var commitHashRE = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// shortHashRE matches abbreviated hashes Bitbucket can resolve itself
+var shortHashRE = regexp.MustCompile(`^[0-9a-f]{7,39}$`)
+
+// resolveCommit returns the hash of rev. Full hashes are used as given, and
+// other revisions are looked up in the local repository. Abbreviated hashes
+// that aren't found locally are passed on for Bitbucket to resolve, so
+// commands work outside a clone.
+func resolveCommit(rev string) (string, error) {
+	if commitHashRE.MatchString(rev) {
+		return rev, nil
+	}
+	hash, err := git.RevParse(rev)
+	if err != nil && shortHashRE.MatchString(rev) {
+		return rev, nil
+	}
+	return hash, err
+}
+
+// shortCommit abbreviates a commit hash for messages
+func shortCommit(commit string) string {
+	return commit[:min(len(commit), 12)]
+}
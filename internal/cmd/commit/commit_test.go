@@ -0,0 +1,40 @@
+package commit
+
+import "testing"
+
+func TestParseState(t *testing.T) {
+	for _, s := range []string{"SUCCESSFUL", "failed", "InProgress", "stopped"} {
+		if _, err := parseState(s); err != nil {
+			t.Errorf("parseState(%q) error = %v", s, err)
+		}
+	}
+	if got, _ := parseState("inprogress"); got != "INPROGRESS" {
+		t.Errorf("parseState(inprogress) = %q", got)
+	}
+	if _, err := parseState("passed"); err == nil {
+		t.Error("expected an error for an unknown state")
+	}
+}
+
+func TestResolveCommit(t *testing.T) {
+	// Run outside any repository, where only hashes can be resolved
+	t.Chdir(t.TempDir())
+
+	full := "4f2c1a9b8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b"
+	tests := []struct {
+		rev     string
+		want    string
+		wantErr bool
+	}{
+		{rev: full, want: full},
+		{rev: "4f2c1a9", want: "4f2c1a9"},
+		{rev: "HEAD", wantErr: true},
+		{rev: "4f2c", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := resolveCommit(tt.rev)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("resolveCommit(%q) = %q, %v", tt.rev, got, err)
+		}
+	}
+}
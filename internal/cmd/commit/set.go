@@ -0,0 +1,115 @@
+package commit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// StatusSetOptions holds the options for the status set command
+type StatusSetOptions struct {
+	Repo        string
+	Commit      string
+	State       string
+	Key         string
+	URL         string
+	Name        string
+	Description string
+	Streams     *iostreams.IOStreams
+}
+
+// NewCmdStatusSet creates the commit status set command
+func NewCmdStatusSet(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &StatusSetOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "set <commit> --state <state> --key <key> --url <url>",
+		Short: "Report a build status on a commit",
+		Long: `Report the status of a build run outside Bitbucket Pipelines on a commit,
+so it shows up on the commit and on pull requests containing it, and counts
+towards merge checks.
+
+The state is SUCCESSFUL, FAILED, INPROGRESS or STOPPED. The key identifies
+the check: reporting a status with the same key again replaces it, so a
+build reports INPROGRESS when it starts and its result when it finishes.
+The URL is where the status links to, such as the build's page.
+
+The commit can be a full or abbreviated hash, or a revision such as HEAD
+when run in a clone of the repository.`,
+		Example: `  # Report that a build has started
+  bb commit status set 1a2b3c4 --state INPROGRESS --key ci/test \
+    --url https://ci.example.com/builds/42
+
+  # Report its result, with a name and description shown on pull requests
+  bb commit status set 1a2b3c4 --state FAILED --key ci/test \
+    --url https://ci.example.com/builds/42 --name "Unit tests" \
+    --description "3 of 120 tests failed"
+
+  # Report on the current commit from a Jenkins job
+  bb commit status set HEAD --state SUCCESSFUL --key jenkins/$JOB_NAME --url "$BUILD_URL"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Commit = args[0]
+			state, err := parseState(opts.State)
+			if err != nil {
+				return cmdutil.NewExitError(cmdutil.ExitUsage, err)
+			}
+			opts.State = state
+			return runStatusSet(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+	cmd.Flags().StringVarP(&opts.State, "state", "s", "", "State of the build: SUCCESSFUL, FAILED, INPROGRESS or STOPPED (required)")
+	cmd.Flags().StringVarP(&opts.Key, "key", "k", "", "Key identifying the check, e.g. ci/test (required)")
+	cmd.Flags().StringVarP(&opts.URL, "url", "u", "", "URL the status links to, such as the build's page (required)")
+	cmd.Flags().StringVarP(&opts.Name, "name", "n", "", "Name shown for the status (default: the key)")
+	cmd.Flags().StringVarP(&opts.Description, "description", "d", "", "Description of the build's result")
+	_ = cmd.MarkFlagRequired("state")
+	_ = cmd.MarkFlagRequired("key")
+	_ = cmd.MarkFlagRequired("url")
+
+	_ = cmd.RegisterFlagCompletionFunc("state", cobra.FixedCompletions(commitStates, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+func runStatusSet(opts *StatusSetOptions) error {
+	commit, err := resolveCommit(opts.Commit)
+	if err != nil {
+		return err
+	}
+
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.Repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	status, err := client.CreateCommitStatus(ctx, workspace, repoSlug, commit, &api.CommitStatusOptions{
+		Key:         opts.Key,
+		State:       opts.State,
+		Name:        opts.Name,
+		Description: opts.Description,
+		URL:         opts.URL,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set build status: %w", err)
+	}
+
+	opts.Streams.Success("Set build status %s to %s on commit %s", status.Key, status.State, shortCommit(commit))
+	return nil
+}
@@ -0,0 +1,66 @@
+package commit
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// commitStates are the states a build status can be in
+var commitStates = []string{"SUCCESSFUL", "FAILED", "INPROGRESS", "STOPPED"}
+
+// NewCmdStatus creates the commit status command and its subcommands
+func NewCmdStatus(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status <command>",
+		Short: "Work with the build statuses of commits",
+		Long: `Report and follow the build statuses of commits.
+
+Build statuses are the checks shown on commits and on the pull requests
+containing them. Bitbucket Pipelines reports its own, and other CI systems,
+such as Jenkins or GitLab runners, can report theirs with 'bb commit status
+set'. Each status has a key identifying the check, and reporting a status
+with the same key again replaces it. 'bb commit status wait' waits for the
+statuses of a commit to finish.`,
+		Example: `  # Report that a build has started
+  bb commit status set 1a2b3c4 --state INPROGRESS --key ci/test \
+    --url https://ci.example.com/builds/42
+
+  # Wait for the tests of a commit to pass
+  bb commit status wait 1a2b3c4 --key ci/test`,
+	}
+
+	cmd.AddCommand(NewCmdStatusSet(streams))
+	cmd.AddCommand(NewCmdStatusWait(streams))
+
+	return cmd
+}
+
+// parseState returns the build status state named by s, in any case
+func parseState(s string) (string, error) {
+	state := strings.ToUpper(s)
+	if !slices.Contains(commitStates, state) {
+		return "", fmt.Errorf("invalid state %q: use one of %s", s, strings.Join(commitStates, ", "))
+	}
+	return state, nil
+}
+
+// formatState formats the state of a build status with optional color
+func formatState(streams *iostreams.IOStreams, state string) string {
+	switch state {
+	case "SUCCESSFUL":
+		return streams.Style(iostreams.RoleSuccess, streams.Symbol("✓")+"pass")
+	case "FAILED":
+		return streams.Style(iostreams.RoleError, streams.Symbol("✗")+"fail")
+	case "INPROGRESS":
+		return streams.Style(iostreams.RoleWarning, streams.Symbol("○")+"running")
+	case "STOPPED":
+		return streams.Style(iostreams.RoleMuted, streams.Symbol("◌")+"stopped")
+	default:
+		return state
+	}
+}
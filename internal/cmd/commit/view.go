@@ -0,0 +1,165 @@
+package commit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// ViewOptions holds the options for the view command
+type ViewOptions struct {
+	Repo    string
+	Commit  string
+	JSON    bool
+	Format  string
+	Streams *iostreams.IOStreams
+}
+
+// commitView is a commit with the checks and reports on it
+type commitView struct {
+	Commit   *api.CommitFull    `json:"commit"`
+	Statuses []api.CommitStatus `json:"statuses"`
+	Reports  []api.Report       `json:"reports"`
+}
+
+// NewCmdView creates the commit view command
+func NewCmdView(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &ViewOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "view [<commit>]",
+		Short: "Show a commit with its build statuses and reports",
+		Long: `Show a commit of a repository: its author, date, parents and message,
+the build statuses reported on it, and its Code Insights reports, such as
+test coverage or the vulnerabilities a scanner found.
+
+The commit defaults to the current one, HEAD. Use 'bb insights view' to see
+a report in detail.`,
+		Example: `  # Show the current commit
+  bb commit view
+
+  # Show a commit of another repository
+  bb commit view 1a2b3c4 -R myworkspace/myrepo
+
+  # Show a report listed on it
+  bb insights view 1a2b3c4 coverage`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Commit = "HEAD"
+			if len(args) > 0 {
+				opts.Commit = args[0]
+			}
+			return runView(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
+
+	return cmd
+}
+
+func runView(ctx context.Context, opts *ViewOptions) error {
+	commit, err := resolveCommit(opts.Commit)
+	if err != nil {
+		return err
+	}
+
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.Repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	view, err := fetchCommitView(ctx, client, workspace, repoSlug, commit)
+	if err != nil {
+		return err
+	}
+
+	if opts.JSON || opts.Format != "" {
+		return cmdutil.PrintFormatted(opts.Streams, opts.Format, view)
+	}
+	displayCommit(opts.Streams, view)
+	return nil
+}
+
+// fetchCommitView fetches a commit with its statuses and reports,
+// concurrently. The statuses and reports are left out if they can't be
+// fetched.
+func fetchCommitView(ctx context.Context, client *api.Client, workspace, repoSlug, commit string) (*commitView, error) {
+	view := &commitView{}
+	var commitErr error
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		view.Commit, commitErr = client.GetCommit(ctx, workspace, repoSlug, commit)
+	})
+	wg.Go(func() {
+		if result, err := client.ListCommitStatuses(ctx, workspace, repoSlug, commit); err == nil {
+			view.Statuses = result.Values
+		}
+	})
+	wg.Go(func() {
+		if reports, err := client.ListReports(ctx, workspace, repoSlug, commit); err == nil {
+			view.Reports = reports
+		}
+	})
+	wg.Wait()
+
+	if commitErr != nil {
+		return nil, fmt.Errorf("failed to get commit %s: %w", shortCommit(commit), commitErr)
+	}
+	return view, nil
+}
+
+func displayCommit(streams *iostreams.IOStreams, view *commitView) {
+	out := streams.Out
+	c := view.Commit
+
+	fmt.Fprintln(out, streams.Style(iostreams.RoleWarning, "commit "+c.Hash))
+	fmt.Fprintf(out, "Author: %s\n", c.Author.Raw)
+	fmt.Fprintf(out, "Date:   %s\n", cmdutil.FormatTime(streams, c.Date))
+	if len(c.Parents) > 1 {
+		parents := make([]string, len(c.Parents))
+		for i, p := range c.Parents {
+			parents[i] = shortCommit(p.Hash)
+		}
+		fmt.Fprintf(out, "Merge:  %s\n", strings.Join(parents, " "))
+	}
+
+	fmt.Fprintln(out)
+	for _, line := range strings.Split(strings.TrimRight(c.Message, "\n"), "\n") {
+		fmt.Fprintf(out, "    %s\n", line)
+	}
+
+	if len(view.Statuses) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Checks:")
+		for _, s := range view.Statuses {
+			fmt.Fprintf(out, "  %s  %s\n", formatState(streams, s.State), statusName(s))
+		}
+	}
+	if len(view.Reports) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Reports:")
+		for _, r := range view.Reports {
+			fmt.Fprintf(out, "  %s (%s): %s\n", r.Title, r.ExternalID, cmdutil.SummarizeReport(streams, r))
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package commit
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+func TestCommitView(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repositories/team/api/commit/abc123":
+			w.Write([]byte(`{"hash": "abc123def456", "message": "Fix login\n\nDetails", "author": {"raw": "Ann <ann@example.com>"},
+				"date": "2026-01-02T03:04:05Z", "parents": [{"hash": "1111111aaaaaaaa"}, {"hash": "2222222bbbbbbbb"}]}`))
+		case "/repositories/team/api/commit/abc123/statuses":
+			w.Write([]byte(`{"values": [{"key": "ci/test", "name": "Tests", "state": "SUCCESSFUL"}]}`))
+		case "/repositories/team/api/commit/abc123/reports":
+			w.Write([]byte(`{"values": [{"external_id": "coverage", "title": "Coverage", "result": "PASSED",
+				"data": [{"title": "Lines", "type": "PERCENTAGE", "value": 81.5}]}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client := api.NewClient(api.WithBaseURL(server.URL))
+
+	view, err := fetchCommitView(context.Background(), client, "team", "api", "abc123")
+	if err != nil {
+		t.Fatalf("fetchCommitView() error = %v", err)
+	}
+	if len(view.Statuses) != 1 || len(view.Reports) != 1 {
+		t.Fatalf("view = %+v", view)
+	}
+
+	var out bytes.Buffer
+	displayCommit(&iostreams.IOStreams{Out: &out, ErrOut: &out}, view)
+	for _, want := range []string{
+		"commit abc123def456",
+		"Merge:  1111111aaaaa 2222222bbbbb",
+		"    Details",
+		"Tests",
+		"Coverage (coverage): passed, Lines 81.5%",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	if _, err := fetchCommitView(context.Background(), client, "team", "api", "missing"); err == nil {
+		t.Error("expected an error for a missing commit")
+	}
+}
@@ -0,0 +1,231 @@
+package commit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// StatusWaitOptions holds the options for the status wait command
+type StatusWaitOptions struct {
+	Repo     string
+	Commit   string
+	Keys     []string
+	Timeout  time.Duration
+	Interval time.Duration
+	Streams  *iostreams.IOStreams
+}
+
+// NewCmdStatusWait creates the commit status wait command
+func NewCmdStatusWait(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &StatusWaitOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "wait <commit>",
+		Short: "Wait for the build statuses of a commit to finish",
+		Long: `Wait until the build statuses of a commit have finished, printing each
+as it changes, so scripts can gate on the checks of a commit.
+
+With --key, only the statuses with those keys are waited for, including
+ones not reported yet. Otherwise every status on the commit is waited for,
+once at least one has been reported. As a CI system may report a status
+only when its build starts, give the keys of the checks that matter when
+gating on a commit.
+
+Exits with status 0 if all the statuses succeeded, 8 if any failed or was
+stopped, and 9 if they hadn't finished within --timeout.`,
+		Example: `  # Wait for all the checks of a commit
+  bb commit status wait 1a2b3c4
+
+  # Wait only for the tests and the build, for at most an hour
+  bb commit status wait 1a2b3c4 --key ci/test --key ci/build --timeout 1h
+
+  # Merge a pull request once the checks of its head commit pass
+  bb commit status wait "$SHA" --key ci/test && bb pr merge 42`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Commit = args[0]
+			if opts.Interval <= 0 {
+				return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("--interval must be positive"))
+			}
+			return runStatusWait(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+	cmd.Flags().StringSliceVarP(&opts.Keys, "key", "k", nil, "Key of a status to wait for; can be repeated (default: all)")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 30*time.Minute, "How long to wait before giving up, or 0 to wait indefinitely")
+	cmd.Flags().DurationVar(&opts.Interval, "interval", 10*time.Second, "How often to check the statuses")
+
+	return cmd
+}
+
+func runStatusWait(ctx context.Context, opts *StatusWaitOptions) error {
+	commit, err := resolveCommit(opts.Commit)
+	if err != nil {
+		return err
+	}
+
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.Repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	return waitForStatuses(ctx, opts, client, workspace, repoSlug, commit)
+}
+
+// waitForStatuses polls the statuses of commit until those waited for have
+// finished, the timeout passes or the user interrupts
+func waitForStatuses(ctx context.Context, opts *StatusWaitOptions, client *api.Client, workspace, repoSlug, commit string) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	opts.Streams.Info("Waiting for the checks of commit %s...", shortCommit(commit))
+
+	seen := map[string]string{}
+	var progress statusProgress
+	for {
+		statuses, err := pollStatuses(ctx, client, workspace, repoSlug, commit)
+		if ctx.Err() != nil {
+			return waitStoppedError(ctx, opts.Timeout, commit, progress)
+		}
+		if err != nil {
+			return err
+		}
+
+		progress = evaluateStatuses(statuses, opts.Keys)
+		reportStatusChanges(opts.Streams, progress.statuses, seen)
+		if progress.done() {
+			return statusResultError(opts.Streams, commit, progress)
+		}
+
+		select {
+		case <-ctx.Done():
+			return waitStoppedError(ctx, opts.Timeout, commit, progress)
+		case <-time.After(opts.Interval):
+		}
+	}
+}
+
+func pollStatuses(ctx context.Context, client *api.Client, workspace, repoSlug, commit string) ([]api.CommitStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	result, err := client.ListCommitStatuses(ctx, workspace, repoSlug, commit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get build statuses: %w", err)
+	}
+	return result.Values, nil
+}
+
+// statusProgress is how far the statuses waited for have got
+type statusProgress struct {
+	// statuses are those waited for that have been reported
+	statuses []api.CommitStatus
+	// pending are the keys of statuses still running or not reported yet
+	pending []string
+	// failed are the statuses that failed or were stopped
+	failed []api.CommitStatus
+}
+
+// done reports whether all the statuses waited for have finished. Without
+// keys, at least one status must have been reported.
+func (p statusProgress) done() bool {
+	return len(p.pending) == 0 && len(p.statuses) > 0
+}
+
+// evaluateStatuses sums up the statuses with keys, or all statuses if no
+// keys are given
+func evaluateStatuses(statuses []api.CommitStatus, keys []string) statusProgress {
+	var p statusProgress
+	for _, s := range statuses {
+		if len(keys) > 0 && !slices.Contains(keys, s.Key) {
+			continue
+		}
+		p.statuses = append(p.statuses, s)
+		switch s.State {
+		case "INPROGRESS":
+			p.pending = append(p.pending, s.Key)
+		case "FAILED", "STOPPED":
+			p.failed = append(p.failed, s)
+		}
+	}
+	for _, key := range keys {
+		if !slices.ContainsFunc(p.statuses, func(s api.CommitStatus) bool { return s.Key == key }) {
+			p.pending = append(p.pending, key)
+		}
+	}
+	return p
+}
+
+// reportStatusChanges prints the statuses whose state changed since they
+// were last seen, recorded in seen by key
+func reportStatusChanges(streams *iostreams.IOStreams, statuses []api.CommitStatus, seen map[string]string) {
+	for _, s := range statuses {
+		if seen[s.Key] == s.State {
+			continue
+		}
+		seen[s.Key] = s.State
+		streams.Info("  %s  %s", formatState(streams, s.State), statusName(s))
+	}
+}
+
+// statusResultError reports how the statuses waited for finished, returning
+// an error exiting with cmdutil.ExitChecksFailed if any didn't succeed
+func statusResultError(streams *iostreams.IOStreams, commit string, p statusProgress) error {
+	if len(p.failed) == 0 {
+		streams.Success("All %d checks of commit %s passed", len(p.statuses), shortCommit(commit))
+		return nil
+	}
+
+	names := make([]string, len(p.failed))
+	for i, s := range p.failed {
+		names[i] = fmt.Sprintf("%s (%s)", statusName(s), s.State)
+	}
+	return cmdutil.NewExitError(cmdutil.ExitChecksFailed,
+		fmt.Errorf("%d of %d checks of commit %s did not pass: %s", len(p.failed), len(p.statuses), shortCommit(commit), strings.Join(names, ", ")))
+}
+
+// waitStoppedError is returned when waiting ends before the statuses have
+// finished, exiting with cmdutil.ExitTimeout if the timeout passed
+func waitStoppedError(ctx context.Context, timeout time.Duration, commit string, p statusProgress) error {
+	waitingFor := "any checks to be reported"
+	if len(p.pending) > 0 {
+		waitingFor = strings.Join(p.pending, ", ")
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return cmdutil.NewExitError(cmdutil.ExitTimeout,
+			fmt.Errorf("timed out after %s waiting for the checks of commit %s; still waiting for %s", timeout, shortCommit(commit), waitingFor))
+	}
+	return fmt.Errorf("stopped waiting for the checks of commit %s; still waiting for %s", shortCommit(commit), waitingFor)
+}
+
+// statusName returns the name a status is shown with, which is its key if
+// it has none
+func statusName(s api.CommitStatus) string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Key
+}
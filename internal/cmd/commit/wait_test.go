@@ -0,0 +1,89 @@
+package commit
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+func TestEvaluateStatuses(t *testing.T) {
+	statuses := []api.CommitStatus{
+		{Key: "ci/test", State: "SUCCESSFUL"},
+		{Key: "ci/lint", State: "INPROGRESS"},
+		{Key: "ci/deploy", State: "STOPPED"},
+	}
+
+	all := evaluateStatuses(statuses, nil)
+	if len(all.statuses) != 3 || !slices.Equal(all.pending, []string{"ci/lint"}) || len(all.failed) != 1 || all.done() {
+		t.Errorf("all statuses: %+v", all)
+	}
+
+	selected := evaluateStatuses(statuses, []string{"ci/test", "ci/build"})
+	if len(selected.statuses) != 1 || !slices.Equal(selected.pending, []string{"ci/build"}) || selected.done() {
+		t.Errorf("selected statuses: %+v", selected)
+	}
+
+	if p := evaluateStatuses(statuses, []string{"ci/test"}); !p.done() || len(p.failed) != 0 {
+		t.Errorf("expected ci/test to be done: %+v", p)
+	}
+	if p := evaluateStatuses(nil, nil); p.done() {
+		t.Error("expected a commit without statuses not to be done")
+	}
+}
+
+func TestWaitForStatuses(t *testing.T) {
+	responses := []string{
+		`{"values": []}`,
+		`{"values": [{"key": "ci/test", "state": "INPROGRESS"}]}`,
+		`{"values": [{"key": "ci/test", "state": "INPROGRESS"}, {"key": "ci/lint", "name": "Lint", "state": "FAILED"}]}`,
+		`{"values": [{"key": "ci/test", "state": "SUCCESSFUL"}, {"key": "ci/lint", "name": "Lint", "state": "FAILED"}]}`,
+	}
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := min(int(calls.Add(1)), len(responses)) - 1
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(responses[n]))
+	}))
+	defer server.Close()
+	client := api.NewClient(api.WithBaseURL(server.URL))
+
+	var out bytes.Buffer
+	opts := &StatusWaitOptions{
+		Interval: time.Millisecond,
+		Streams:  &iostreams.IOStreams{Out: &out, ErrOut: &out},
+	}
+
+	// Waiting only for ci/test succeeds once it passes
+	opts.Keys = []string{"ci/test"}
+	if err := waitForStatuses(context.Background(), opts, client, "team", "api", "abc123"); err != nil {
+		t.Fatalf("waitForStatuses() error = %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "○ running  ci/test") || !strings.Contains(out.String(), "✓ pass  ci/test") {
+		t.Errorf("expected the transitions of ci/test, got:\n%s", out.String())
+	}
+
+	// Waiting for all of them fails, as Lint failed
+	opts.Keys = nil
+	err := waitForStatuses(context.Background(), opts, client, "team", "api", "abc123")
+	if cmdutil.ExitCode(err) != cmdutil.ExitChecksFailed || !strings.Contains(err.Error(), "Lint (FAILED)") {
+		t.Errorf("waitForStatuses() error = %v, want failed checks", err)
+	}
+
+	// A check that is never reported times out
+	opts.Keys = []string{"ci/build"}
+	opts.Timeout = 20 * time.Millisecond
+	err = waitForStatuses(context.Background(), opts, client, "team", "api", "abc123")
+	if cmdutil.ExitCode(err) != cmdutil.ExitTimeout || !strings.Contains(err.Error(), "still waiting for ci/build") {
+		t.Errorf("waitForStatuses() error = %v, want a timeout", err)
+	}
+}
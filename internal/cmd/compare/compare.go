@@ -0,0 +1,382 @@
+package compare
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// CompareOptions holds the options for the compare command
+type CompareOptions struct {
+	Spec    string
+	Repo    string
+	Limit   int
+	Patch   bool
+	JSON    bool
+	Streams *iostreams.IOStreams
+}
+
+// comparison is how two revisions of a repository differ
+type comparison struct {
+	Base string `json:"base"`
+	Head string `json:"head"`
+	// TwoDot is set when the files are compared with base itself rather
+	// than with the merge base of base and head
+	TwoDot bool `json:"two_dot"`
+	// Ahead are the commits on head that aren't on base, newest first,
+	// and Behind those on base that aren't on head. Each has at most the
+	// limit's commits; AheadMore and BehindMore are set when there are more.
+	Ahead      []compareCommit `json:"ahead"`
+	AheadMore  bool            `json:"ahead_truncated"`
+	Behind     []compareCommit `json:"behind"`
+	BehindMore bool            `json:"behind_truncated"`
+	Files      []compareFile   `json:"files"`
+}
+
+type compareCommit struct {
+	Hash    string    `json:"hash"`
+	Message string    `json:"message"`
+	Author  string    `json:"author"`
+	Date    time.Time `json:"date"`
+}
+
+type compareFile struct {
+	Path         string `json:"path"`
+	OldPath      string `json:"old_path,omitempty"`
+	Status       string `json:"status"`
+	LinesAdded   int    `json:"lines_added"`
+	LinesRemoved int    `json:"lines_removed"`
+}
+
+// NewCmdCompare creates the compare command
+func NewCmdCompare(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &CompareOptions{
+		Streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "compare [<base>...]<head>",
+		Short: "Compare two branches, tags or commits",
+		Long: `Show how two revisions of a repository differ, without fetching them: the
+commits on each that aren't on the other, and the files changed.
+
+Revisions are compared as git does: with <base>...<head>, the files are
+compared with the merge base of the two, showing what a pull request from
+<head> into <base> would change; with <base>..<head>, they are compared
+with <base> itself. Given only <head>, it is compared with the
+repository's main branch.
+
+With --patch, the full diff is shown instead of the list of files.`,
+		Example: `  # What would merging feature/login into main change?
+  bb compare main...feature/login
+
+  # Compare a branch with the main branch
+  bb compare feature/login
+
+  # Show the full diff between two tags
+  bb compare v1.0..v1.1 --patch
+
+  # Output as JSON
+  bb compare main...feature/login --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Spec = args[0]
+			if opts.Limit < 1 {
+				return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("invalid limit: %d", opts.Limit))
+			}
+			return runCompare(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format (detects from git remote if not specified)")
+	cmd.Flags().IntVarP(&opts.Limit, "limit", "l", 50, "Maximum number of commits to list on each side")
+	cmd.Flags().BoolVarP(&opts.Patch, "patch", "p", false, "Show the full diff instead of the files changed")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmd.MarkFlagsMutuallyExclusive("patch", "json")
+
+	return cmd
+}
+
+// parseCompareSpec splits a spec written as git does, base...head,
+// base..head or head alone, reporting whether it is two-dot. base is ""
+// when the spec gives only head.
+func parseCompareSpec(spec string) (base, head string, twoDot bool, err error) {
+	base, head, found := strings.Cut(spec, "...")
+	if !found {
+		base, head, twoDot = strings.Cut(spec, "..")
+		if !twoDot {
+			base, head = "", spec
+		}
+	}
+	if head == "" || ((found || twoDot) && base == "") || strings.Contains(head, "..") {
+		return "", "", false, cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("invalid comparison %q: use <base>...<head>, <base>..<head> or <head>", spec))
+	}
+	return base, head, twoDot, nil
+}
+
+func runCompare(ctx context.Context, opts *CompareOptions) error {
+	base, head, twoDot, err := parseCompareSpec(opts.Spec)
+	if err != nil {
+		return err
+	}
+
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.Repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	if base == "" {
+		repo, err := client.GetRepository(ctx, workspace, repoSlug)
+		if err != nil {
+			return fmt.Errorf("failed to get repository: %w", err)
+		}
+		if repo.MainBranch == nil || repo.MainBranch.Name == "" {
+			return fmt.Errorf("%s/%s has no main branch to compare with; give <base>...<head>", workspace, repoSlug)
+		}
+		base = repo.MainBranch.Name
+	}
+
+	// Bitbucket writes ranges with the source first, the reverse of git
+	spec := head + ".." + base
+	diffOpts := &api.DiffOptions{TwoDot: twoDot}
+
+	progress := opts.Streams.StartProgress("Comparing")
+	result := &comparison{Base: base, Head: head, TwoDot: twoDot}
+	var (
+		wg                            sync.WaitGroup
+		aheadErr, behindErr, filesErr error
+		diff                          string
+	)
+	wg.Go(func() {
+		result.Ahead, result.AheadMore, aheadErr = listCommitsBetween(ctx, client, workspace, repoSlug, head, base, opts.Limit)
+	})
+	wg.Go(func() {
+		result.Behind, result.BehindMore, behindErr = listCommitsBetween(ctx, client, workspace, repoSlug, base, head, opts.Limit)
+	})
+	wg.Go(func() {
+		if opts.Patch {
+			diff, filesErr = client.GetDiff(ctx, workspace, repoSlug, spec, diffOpts)
+			return
+		}
+		var stats []api.DiffStat
+		stats, filesErr = client.GetDiffStat(ctx, workspace, repoSlug, spec, diffOpts)
+		result.Files = compareFiles(stats)
+	})
+	wg.Wait()
+	progress.Stop()
+
+	for _, err := range []error{aheadErr, behindErr, filesErr} {
+		if err != nil {
+			return fmt.Errorf("failed to compare %s with %s: %w", head, base, err)
+		}
+	}
+
+	if opts.JSON {
+		return cmdutil.PrintJSON(opts.Streams, result)
+	}
+
+	if err := opts.Streams.StartPager(); err != nil {
+		opts.Streams.Warning("%s", err)
+	}
+	defer opts.Streams.StopPager()
+
+	if err := printComparison(opts.Streams, workspace+"/"+repoSlug, result, !opts.Patch); err != nil {
+		return err
+	}
+	if opts.Patch && diff != "" {
+		fmt.Fprintln(opts.Streams.Out)
+		var style func(string) string
+		if opts.Streams.ColorEnabled() {
+			style = func(line string) string {
+				return cmdutil.ColorizeDiffLine(opts.Streams, line)
+			}
+		}
+		if _, err := cmdutil.CopyLines(opts.Streams.Out, strings.NewReader(diff), style); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listCommitsBetween lists up to limit commits on include that aren't on
+// exclude, newest first, reporting whether there are more
+func listCommitsBetween(ctx context.Context, client *api.Client, workspace, repoSlug, include, exclude string, limit int) ([]compareCommit, bool, error) {
+	commits := []compareCommit{}
+	for page := 1; ; page++ {
+		result, err := client.ListCommits(ctx, workspace, repoSlug, &api.CommitListOptions{
+			Include: []string{include},
+			Exclude: []string{exclude},
+			Page:    page,
+			Limit:   min(limit, 100),
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		for _, c := range result.Values {
+			if len(commits) == limit {
+				return commits, true, nil
+			}
+			author := cmdutil.GetUserDisplayName(c.Author.User)
+			if c.Author.User == nil {
+				author = commitAuthorName(c.Author.Raw)
+			}
+			commits = append(commits, compareCommit{
+				Hash:    c.Hash,
+				Message: strings.TrimSpace(c.Message),
+				Author:  author,
+				Date:    c.Date,
+			})
+		}
+		if result.Next == "" || len(result.Values) == 0 {
+			return commits, false, nil
+		}
+		if len(commits) == limit {
+			return commits, true, nil
+		}
+	}
+}
+
+// compareFiles converts the entries of a diffstat to the files changed
+func compareFiles(stats []api.DiffStat) []compareFile {
+	files := make([]compareFile, 0, len(stats))
+	for _, d := range stats {
+		f := compareFile{Status: d.Status, LinesAdded: d.LinesAdded, LinesRemoved: d.LinesRemoved}
+		if d.New != nil {
+			f.Path = d.New.Path
+		}
+		if d.Old != nil {
+			if f.Path == "" {
+				f.Path = d.Old.Path
+			} else if d.Old.Path != f.Path {
+				f.OldPath = d.Old.Path
+			}
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// commitAuthorName returns the name of a "Name <email>" author
+func commitAuthorName(raw string) string {
+	if name, _, ok := strings.Cut(raw, " <"); ok {
+		return name
+	}
+	if raw == "" {
+		return "-"
+	}
+	return raw
+}
+
+// countCommits describes how many commits a side has, such as "3 commits"
+// or "50+ commits" when the list was cut short
+func countCommits(commits []compareCommit, more bool) string {
+	n := fmt.Sprintf("%d", len(commits))
+	if more {
+		n += "+"
+	}
+	if len(commits) == 1 && !more {
+		return n + " commit"
+	}
+	return n + " commits"
+}
+
+func printComparison(streams *iostreams.IOStreams, repo string, c *comparison, files bool) error {
+	out := streams.Out
+	dots := "..."
+	if c.TwoDot {
+		dots = ".."
+	}
+	fmt.Fprintf(out, "Comparing %s%s%s in %s\n", c.Base, dots, c.Head, repo)
+
+	if len(c.Ahead) == 0 && len(c.Behind) == 0 {
+		fmt.Fprintf(out, "\n%s and %s point to the same history\n", c.Head, c.Base)
+	}
+	for _, side := range []struct {
+		commits  []compareCommit
+		more     bool
+		from, to string
+	}{
+		{c.Ahead, c.AheadMore, c.Head, c.Base},
+		{c.Behind, c.BehindMore, c.Base, c.Head},
+	} {
+		if len(side.commits) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "\n%s\n", streams.Style(iostreams.RoleHeader,
+			fmt.Sprintf("%s on %s not on %s", countCommits(side.commits, side.more), side.from, side.to)))
+		table := cmdutil.NewTablePrinter(streams)
+		for _, commit := range side.commits {
+			subject, _, _ := strings.Cut(commit.Message, "\n")
+			table.AddRow(
+				streams.Style(iostreams.RoleWarning, shortHash(commit.Hash)),
+				cmdutil.TruncateString(subject, 60),
+				commit.Author,
+				cmdutil.FormatTime(streams, commit.Date),
+			)
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+	}
+
+	if !files {
+		return nil
+	}
+	if len(c.Files) == 0 {
+		fmt.Fprintf(out, "\nNo files changed\n")
+		return nil
+	}
+
+	fmt.Fprintf(out, "\n%s\n", streams.Style(iostreams.RoleHeader, "Files changed"))
+	table := cmdutil.NewTablePrinter(streams)
+	added, removed := 0, 0
+	for _, f := range c.Files {
+		added += f.LinesAdded
+		removed += f.LinesRemoved
+		path := f.Path
+		if f.OldPath != "" {
+			path = f.OldPath + " → " + f.Path
+		}
+		table.AddRow(
+			f.Status,
+			path,
+			streams.Style(iostreams.RoleAddition, fmt.Sprintf("+%d", f.LinesAdded)),
+			streams.Style(iostreams.RoleDeletion, fmt.Sprintf("-%d", f.LinesRemoved)),
+		)
+	}
+	if err := table.Render(); err != nil {
+		return err
+	}
+
+	noun := "files"
+	if len(c.Files) == 1 {
+		noun = "file"
+	}
+	fmt.Fprintf(out, "\n%d %s changed, %s %s\n", len(c.Files), noun,
+		streams.Style(iostreams.RoleAddition, fmt.Sprintf("+%d", added)),
+		streams.Style(iostreams.RoleDeletion, fmt.Sprintf("-%d", removed)))
+	return nil
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
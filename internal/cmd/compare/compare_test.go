@@ -0,0 +1,94 @@
+package compare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+func TestParseCompareSpec(t *testing.T) {
+	tests := []struct {
+		spec       string
+		wantBase   string
+		wantHead   string
+		wantTwoDot bool
+		wantErr    bool
+	}{
+		{spec: "main...feature/x", wantBase: "main", wantHead: "feature/x"},
+		{spec: "v1.0..v1.1", wantBase: "v1.0", wantHead: "v1.1", wantTwoDot: true},
+		{spec: "feature/x", wantHead: "feature/x"},
+		{spec: "main...", wantErr: true},
+		{spec: "...feature", wantErr: true},
+		{spec: "..feature", wantErr: true},
+		{spec: "a..b..c", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			base, head, twoDot, err := parseCompareSpec(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCompareSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if base != tt.wantBase || head != tt.wantHead || twoDot != tt.wantTwoDot {
+				t.Errorf("parseCompareSpec() = %q, %q, %v", base, head, twoDot)
+			}
+		})
+	}
+}
+
+func TestListCommitsBetween(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("include") != "feature" || query.Get("exclude") != "main" {
+			t.Errorf("unexpected query %q", r.URL.RawQuery)
+		}
+		page := query.Get("page")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"next": "https://api.bitbucket.org/next", "values": [
+			{"hash": "%s1", "message": "First\n\nBody", "author": {"raw": "Jane Doe <jane@example.com>"}},
+			{"hash": "%s2", "message": "Second", "author": {"raw": "x", "user": {"display_name": "Bob"}}}
+		]}`, page, page)
+	}))
+	defer server.Close()
+	client := api.NewClient(api.WithBaseURL(server.URL))
+
+	commits, more, err := listCommitsBetween(context.Background(), client, "ws", "repo", "feature", "main", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commits) != 3 || !more {
+		t.Fatalf("expected 3 commits and more, got %d, %v", len(commits), more)
+	}
+	if commits[0].Author != "Jane Doe" || commits[1].Author != "Bob" || commits[2].Hash != "21" {
+		t.Errorf("unexpected commits %+v", commits)
+	}
+	if got := countCommits(commits, more); got != "3+ commits" {
+		t.Errorf("countCommits() = %q", got)
+	}
+}
+
+func TestCompareFiles(t *testing.T) {
+	path := func(p string) *struct {
+		Path string `json:"path"`
+	} {
+		return &struct {
+			Path string `json:"path"`
+		}{Path: p}
+	}
+	files := compareFiles([]api.DiffStat{
+		{Status: "modified", LinesAdded: 2, Old: path("a.go"), New: path("a.go")},
+		{Status: "renamed", Old: path("old.go"), New: path("new.go")},
+		{Status: "removed", LinesRemoved: 9, Old: path("gone.go")},
+	})
+	want := []compareFile{
+		{Path: "a.go", Status: "modified", LinesAdded: 2},
+		{Path: "new.go", OldPath: "old.go", Status: "renamed"},
+		{Path: "gone.go", Status: "removed", LinesRemoved: 9},
+	}
+	if fmt.Sprint(files) != fmt.Sprint(want) {
+		t.Errorf("compareFiles() = %+v, want %+v", files, want)
+	}
+}
@@ -17,17 +17,35 @@ Configuration is stored in ~/.config/bb/config.yml or the directory
 specified by the BB_CONFIG_DIR environment variable.
 
 Available settings:
-  git_protocol   The protocol to use for git operations (ssh, https)
-  editor         The editor to use for composing text
-  prompt         Whether to enable interactive prompts (enabled, disabled)
-  pager          The pager to use for output
-  browser        The browser to use for opening URLs
-  http_timeout   HTTP request timeout in seconds`,
+  git_protocol       The protocol to use for git operations (ssh, https)
+  editor             The editor to use for composing text
+  prompt             Whether to enable interactive prompts (enabled, disabled)
+  pager              The pager to use for output
+  browser            The browser to use for opening URLs
+  http_timeout       HTTP request timeout in seconds
+  theme              The color theme for terminal output
+  icons              Status icons in table output (none, emoji, nerd)
+  timestamps         How times are shown (relative, absolute, iso)
+  locale             Language of messages (default from LANG)
+  accessible         Output suited to screen readers (true, false)
+  credential_store   Where tokens are stored (keyring, file, pass, plaintext)
+  pr_template        Template file or text for new pull request descriptions
+  issue_template     Template file or text for new issue descriptions
+  http_proxy         Proxy URL for http:// requests
+  https_proxy        Proxy URL for https:// requests
+  no_proxy           Comma-separated hosts reached without a proxy
+  http_idle_conns    Idle connections kept open per host for reuse
+  http_idle_timeout  Seconds an idle connection is kept open
+  http2              Whether to use HTTP/2 (enabled, disabled)
+  update_check       Whether bb version looks for a newer release (enabled, disabled)
+  log_level          Turns on the log file (debug, info, warn, error)
+  fields.<cmd>       Default table columns for a list command, e.g. fields.pr.list`,
 	}
 
 	cmd.AddCommand(NewCmdConfigGet(streams))
 	cmd.AddCommand(NewCmdConfigSet(streams))
 	cmd.AddCommand(NewCmdConfigList(streams))
+	cmd.AddCommand(NewCmdConfigDoctor(streams))
 
 	return cmd
 }
@@ -0,0 +1,291 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	coreconfig "github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// doctorTimeout bounds each network check
+const doctorTimeout = 10 * time.Second
+
+// doctorReport collects the outcome of each check
+type doctorReport struct {
+	streams  *iostreams.IOStreams
+	problems int
+}
+
+func (r *doctorReport) ok(format string, a ...interface{}) {
+	fmt.Fprintln(r.streams.Out, r.streams.Style(iostreams.RoleSuccess, r.streams.StatusPrefix(iostreams.RoleSuccess)+fmt.Sprintf(format, a...)))
+}
+
+func (r *doctorReport) warn(fix, format string, a ...interface{}) {
+	fmt.Fprintln(r.streams.Out, r.streams.Style(iostreams.RoleWarning, r.streams.StatusPrefix(iostreams.RoleWarning)+fmt.Sprintf(format, a...)))
+	r.printFix(fix)
+}
+
+func (r *doctorReport) fail(fix, format string, a ...interface{}) {
+	r.problems++
+	fmt.Fprintln(r.streams.Out, r.streams.Style(iostreams.RoleError, r.streams.StatusPrefix(iostreams.RoleError)+fmt.Sprintf(format, a...)))
+	r.printFix(fix)
+}
+
+func (r *doctorReport) printFix(fix string) {
+	if fix != "" {
+		fmt.Fprintln(r.streams.Out, r.streams.Style(iostreams.RoleMuted, "  → "+fix))
+	}
+}
+
+// NewCmdConfigDoctor creates the config doctor command
+func NewCmdConfigDoctor(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check configuration, credentials and connectivity",
+		Long: `Check that bb is set up correctly and suggest fixes for any problems.
+
+The following are checked:
+  - config.yml and hosts.yml parse and contain only known settings
+  - configuration values are valid
+  - the stored credentials for each host are accepted by the API
+  - git and ssh are installed
+  - the API of each host can be reached
+
+Exits with a non-zero status if any problem is found.`,
+		Example: `  # Check the setup
+  bb config doctor`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDoctor(streams)
+		},
+	}
+
+	return cmd
+}
+
+func runDoctor(streams *iostreams.IOStreams) error {
+	r := &doctorReport{streams: streams}
+
+	cfg := checkConfigFile(r)
+	hosts := checkHostsFile(r)
+	if cfg != nil {
+		checkConfigValues(r, cfg)
+	}
+	if hosts != nil {
+		checkCredentials(r, hosts)
+	}
+	checkTools(r, cfg)
+	if hosts != nil {
+		checkReachability(r, hosts)
+	}
+
+	if r.problems > 0 {
+		return fmt.Errorf("%d problem(s) found", r.problems)
+	}
+	streams.Success("No problems found")
+	return nil
+}
+
+// decodeStrict decodes the YAML file at path into v, rejecting unknown
+// keys. It reports whether the file exists.
+func decodeStrict(path string, v interface{}) (bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return true, err
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return true, err
+	}
+	return true, nil
+}
+
+func checkConfigFile(r *doctorReport) *coreconfig.Config {
+	dir, err := coreconfig.ConfigDir()
+	if err != nil {
+		r.fail("Set BB_CONFIG_DIR to a writable directory", "Could not find the config directory: %v", err)
+		return nil
+	}
+	path := filepath.Join(dir, coreconfig.ConfigFileName)
+
+	var cfg coreconfig.Config
+	exists, err := decodeStrict(path, &cfg)
+	switch {
+	case err != nil:
+		r.fail(fmt.Sprintf("Fix or remove the offending lines in %s", path), "%s is invalid: %v", path, err)
+		// Fall back to a lenient load so the remaining checks can run
+		loaded, loadErr := coreconfig.LoadConfig()
+		if loadErr != nil {
+			return nil
+		}
+		return loaded
+	case !exists:
+		r.ok("No config file; using defaults")
+		loaded, _ := coreconfig.LoadConfig()
+		return loaded
+	}
+	r.ok("%s is valid", path)
+	return &cfg
+}
+
+func checkHostsFile(r *doctorReport) coreconfig.HostsConfig {
+	dir, err := coreconfig.ConfigDir()
+	if err != nil {
+		return nil
+	}
+	path := filepath.Join(dir, coreconfig.HostsFileName)
+
+	var hosts coreconfig.HostsConfig
+	exists, err := decodeStrict(path, &hosts)
+	switch {
+	case err != nil:
+		r.fail(fmt.Sprintf("Fix or remove the offending lines in %s", path), "%s is invalid: %v", path, err)
+		return nil
+	case !exists:
+		r.fail("Run 'bb auth login' to authenticate", "Not logged in to any host")
+		return nil
+	}
+	r.ok("%s is valid", path)
+
+	for host, hc := range hosts {
+		if hc == nil || hc.APIURL == "" {
+			continue
+		}
+		if u, err := url.Parse(hc.APIURL); err != nil || u.Scheme == "" || u.Host == "" {
+			r.fail(fmt.Sprintf("Set api_url for %s to a full URL such as https://%s/rest/api/1.0", host, host),
+				"api_url for %s is not a valid URL: %s", host, hc.APIURL)
+		}
+	}
+	return hosts
+}
+
+// doctorKeys are the config keys whose values can be checked by setting
+// them again
+var doctorKeys = []string{"git_protocol", "prompt", "http_timeout", "theme", "icons", "timestamps", "credential_store"}
+
+func checkConfigValues(r *doctorReport, cfg *coreconfig.Config) {
+	valid := true
+	for _, key := range doctorKeys {
+		value, err := getConfigValue(cfg, key)
+		if err != nil || value == "" || value == "0" {
+			continue
+		}
+		scratch := *cfg
+		if err := setConfigValue(&scratch, key, value); err != nil {
+			valid = false
+			r.fail(fmt.Sprintf("Run 'bb config set %s <value>' with a valid value", key), "%v", err)
+		}
+	}
+
+	if cfg.CurrentProfile != "" {
+		if _, ok := cfg.Profiles[cfg.CurrentProfile]; !ok {
+			valid = false
+			r.fail("Run 'bb context use <name>' or 'bb context use --none'", "Current profile %q does not exist", cfg.CurrentProfile)
+		}
+	}
+
+	if valid {
+		r.ok("Configuration values are valid")
+	}
+}
+
+func checkCredentials(r *doctorReport, hosts coreconfig.HostsConfig) {
+	names := make([]string, 0, len(hosts))
+	for host := range hosts {
+		names = append(names, host)
+	}
+	sort.Strings(names)
+
+	for _, host := range names {
+		user := hosts.GetActiveUser(host)
+		login := "bb auth login"
+		if host != coreconfig.DefaultHost {
+			login += " --hostname " + host
+		}
+		if user == "" {
+			r.warn(fmt.Sprintf("Run '%s' to authenticate", login), "No active account for %s", host)
+			continue
+		}
+
+		client, err := cmdutil.GetAPIClientFor(hosts, host, user)
+		if err != nil {
+			r.fail(fmt.Sprintf("Run '%s' to store new credentials", login), "Credentials for %s on %s: %v", user, host, err)
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+		_, err = client.GetCurrentUser(ctx)
+		cancel()
+		if err != nil {
+			r.fail(fmt.Sprintf("Run '%s' to re-authenticate", login), "Credentials for %s on %s were rejected: %v", user, host, err)
+			continue
+		}
+		r.ok("Logged in to %s as %s", host, user)
+	}
+}
+
+func checkTools(r *doctorReport, cfg *coreconfig.Config) {
+	if version, err := git.Version(); err != nil {
+		r.fail("Install git from https://git-scm.com/downloads", "git is not installed")
+	} else {
+		r.ok("%s", version)
+	}
+
+	if _, err := exec.LookPath("ssh"); err != nil {
+		fix := "Install an SSH client, or use HTTPS with 'bb config set git_protocol https'"
+		if cfg != nil && cfg.GitProtocol == "ssh" {
+			r.fail(fix, "ssh is not installed, but git_protocol is ssh")
+		} else {
+			r.warn(fix, "ssh is not installed")
+		}
+	} else {
+		r.ok("ssh is installed")
+	}
+}
+
+func checkReachability(r *doctorReport, hosts coreconfig.HostsConfig) {
+	names := make([]string, 0, len(hosts))
+	for host := range hosts {
+		names = append(names, host)
+	}
+	sort.Strings(names)
+
+	for _, host := range names {
+		apiURL := hosts.APIURL(host)
+		client, err := cmdutil.NewHTTPClientFor(hosts, host, doctorTimeout)
+		if err != nil {
+			r.fail("Fix ca_bundle in "+coreconfig.HostsFileName, "%v", err)
+			continue
+		}
+		if hostConfig := hosts[host]; hostConfig != nil && hostConfig.InsecureSkipTLS {
+			r.warn("Set ca_bundle to trust the host's CA instead", "TLS certificate verification is disabled for %s", host)
+		}
+		resp, err := client.Get(apiURL)
+		if err != nil {
+			r.fail("Check your network connection and proxy settings (https_proxy, no_proxy)", "Could not reach %s: %v", apiURL, err)
+			continue
+		}
+		resp.Body.Close()
+		r.ok("%s is reachable", apiURL)
+	}
+}
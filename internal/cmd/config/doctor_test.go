@@ -0,0 +1,181 @@
+package config
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	coreconfig "github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+func newTestReport() (*doctorReport, *bytes.Buffer) {
+	var out bytes.Buffer
+	return &doctorReport{streams: &iostreams.IOStreams{Out: &out, ErrOut: &out}}, &out
+}
+
+func TestDecodeStrict(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    *string
+		wantExists bool
+		wantErr    bool
+	}{
+		{name: "missing file", content: nil, wantExists: false},
+		{name: "empty file", content: strPtr(""), wantExists: true},
+		{name: "known keys", content: strPtr("git_protocol: ssh\ntheme: high-contrast\n"), wantExists: true},
+		{name: "unknown key", content: strPtr("git_protocl: ssh\n"), wantExists: true, wantErr: true},
+		{name: "malformed YAML", content: strPtr("git_protocol: [ssh\n"), wantExists: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yml")
+			if tt.content != nil {
+				if err := os.WriteFile(path, []byte(*tt.content), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var cfg coreconfig.Config
+			exists, err := decodeStrict(path, &cfg)
+			if exists != tt.wantExists {
+				t.Errorf("decodeStrict() exists = %v, want %v", exists, tt.wantExists)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodeStrict() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckConfigValues(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          coreconfig.Config
+		wantProblems int
+		wantOutput   string
+	}{
+		{
+			name:       "valid",
+			cfg:        coreconfig.Config{GitProtocol: "ssh", Theme: "high-contrast"},
+			wantOutput: "Configuration values are valid",
+		},
+		{
+			name:         "bad value",
+			cfg:          coreconfig.Config{GitProtocol: "ftp"},
+			wantProblems: 1,
+			wantOutput:   "bb config set git_protocol <value>",
+		},
+		{
+			name:         "missing profile",
+			cfg:          coreconfig.Config{CurrentProfile: "work"},
+			wantProblems: 1,
+			wantOutput:   `Current profile "work" does not exist`,
+		},
+		{
+			name:       "existing profile",
+			cfg:        coreconfig.Config{CurrentProfile: "work", Profiles: map[string]*coreconfig.Profile{"work": {}}},
+			wantOutput: "Configuration values are valid",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, out := newTestReport()
+			checkConfigValues(r, &tt.cfg)
+			if r.problems != tt.wantProblems {
+				t.Errorf("problems = %d, want %d\n%s", r.problems, tt.wantProblems, out)
+			}
+			if !strings.Contains(out.String(), tt.wantOutput) {
+				t.Errorf("output = %q, want it to contain %q", out, tt.wantOutput)
+			}
+		})
+	}
+}
+
+// newDoctorServer serves the current user to requests with token
+func newDoctorServer(t *testing.T, token string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"type": "error", "error": {"message": "Unauthorized"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"username": "alice", "display_name": "Alice"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCheckCredentials(t *testing.T) {
+	server := newDoctorServer(t, "good-token")
+
+	tests := []struct {
+		name         string
+		user         string
+		token        string
+		wantProblems int
+		wantOutput   string
+	}{
+		{name: "accepted", user: "alice", token: "good-token", wantOutput: "Logged in to bitbucket.example.com as alice"},
+		{name: "rejected", user: "alice", token: "bad-token", wantProblems: 1, wantOutput: "were rejected"},
+		{name: "no active account", user: "", wantOutput: "No active account for bitbucket.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BB_CONFIG_DIR", t.TempDir())
+			t.Setenv(coreconfig.CacheDirEnvVar, t.TempDir())
+			t.Setenv("BB_TOKEN", tt.token)
+			hosts := coreconfig.HostsConfig{
+				"bitbucket.example.com": {User: tt.user, APIURL: server.URL},
+			}
+
+			r, out := newTestReport()
+			checkCredentials(r, hosts)
+			if r.problems != tt.wantProblems {
+				t.Errorf("problems = %d, want %d\n%s", r.problems, tt.wantProblems, out)
+			}
+			if !strings.Contains(out.String(), tt.wantOutput) {
+				t.Errorf("output = %q, want it to contain %q", out, tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestCheckReachability(t *testing.T) {
+	up := newDoctorServer(t, "good-token")
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	tests := []struct {
+		name         string
+		host         *coreconfig.HostConfig
+		wantProblems int
+		wantOutput   string
+	}{
+		{name: "reachable", host: &coreconfig.HostConfig{APIURL: up.URL}, wantOutput: up.URL + " is reachable"},
+		{name: "unreachable", host: &coreconfig.HostConfig{APIURL: down.URL}, wantProblems: 1, wantOutput: "Could not reach " + down.URL},
+		{name: "missing CA bundle", host: &coreconfig.HostConfig{APIURL: up.URL, CABundle: "/nonexistent/ca.pem"}, wantProblems: 1, wantOutput: "Fix ca_bundle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, out := newTestReport()
+			checkReachability(r, coreconfig.HostsConfig{"bitbucket.example.com": tt.host})
+			if r.problems != tt.wantProblems {
+				t.Errorf("problems = %d, want %d\n%s", r.problems, tt.wantProblems, out)
+			}
+			if !strings.Contains(out.String(), tt.wantOutput) {
+				t.Errorf("output = %q, want it to contain %q", out, tt.wantOutput)
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
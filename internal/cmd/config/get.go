@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -21,12 +22,34 @@ func NewCmdConfigGet(streams *iostreams.IOStreams) *cobra.Command {
 		Long: `Print the value of a configuration key.
 
 Available keys:
-  git_protocol   The protocol to use for git operations
-  editor         The editor to use for composing text
-  prompt         Whether to enable interactive prompts
-  pager          The pager to use for output
-  browser        The browser to use for opening URLs
-  http_timeout   HTTP request timeout in seconds`,
+  git_protocol       The protocol to use for git operations
+  editor             The editor to use for composing text
+  prompt             Whether to enable interactive prompts
+  pager              The pager to use for output
+  browser            The browser to use for opening URLs
+  http_timeout       HTTP request timeout in seconds
+  theme              The color theme for terminal output
+  icons              Status icons in table output
+  timestamps         How times are shown
+  locale             Language of messages
+  accessible         Whether output is suited to screen readers
+  credential_store   Where tokens are stored
+  pr_template        Template for new pull request descriptions
+  issue_template     Template for new issue descriptions
+  http_proxy         Proxy for http:// requests
+  https_proxy        Proxy for https:// requests
+  no_proxy           Hosts reached without a proxy
+  http_idle_conns    Idle connections kept open per host
+  http_idle_timeout  Seconds an idle connection is kept open
+  http2              Whether HTTP/2 is used
+  update_check       Whether bb version looks for a newer release
+  log_level          The least severe entries written to the log file
+  fields.<cmd>       Default table columns for a list command
+
+Keys read with --host, from hosts.yml:
+  git_protocol       The protocol to use for git operations with the host
+  ca_bundle          PEM file of CA certificates to trust for the host
+  insecure_skip_tls  Whether TLS certificate verification is skipped`,
 		Example: `  # Get the git protocol setting
   bb config get git_protocol
 
@@ -36,6 +59,19 @@ Available keys:
 		RunE: func(cmd *cobra.Command, args []string) error {
 			key := strings.ToLower(args[0])
 
+			if host != "" {
+				hosts, err := coreconfig.LoadHostsConfig()
+				if err != nil {
+					return fmt.Errorf("could not load hosts config: %w", err)
+				}
+				value, err := getHostValue(hosts[host], key)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(streams.Out, value)
+				return nil
+			}
+
 			// Load config
 			cfg, err := coreconfig.LoadConfig()
 			if err != nil {
@@ -54,20 +90,42 @@ Available keys:
 	}
 
 	cmd.Flags().StringVarP(&host, "host", "h", "", "Get per-host configuration")
+	// -h is taken by --host, so --help has no shorthand
+	cmd.Flags().Bool("help", false, "Show help for command")
 
 	return cmd
 }
 
 // getConfigValue returns the value of a config key
 func getConfigValue(cfg *coreconfig.Config, key string) (string, error) {
+	if strings.HasPrefix(key, coreconfig.FieldsKeyPrefix) {
+		return cfg.Fields[strings.TrimPrefix(key, coreconfig.FieldsKeyPrefix)], nil
+	}
+
 	// Map config keys to struct fields
 	keyMap := map[string]string{
-		"git_protocol": "GitProtocol",
-		"editor":       "Editor",
-		"prompt":       "Prompt",
-		"pager":        "Pager",
-		"browser":      "Browser",
-		"http_timeout": "HTTPTimeout",
+		"git_protocol":      "GitProtocol",
+		"editor":            "Editor",
+		"prompt":            "Prompt",
+		"pager":             "Pager",
+		"browser":           "Browser",
+		"http_timeout":      "HTTPTimeout",
+		"theme":             "Theme",
+		"icons":             "Icons",
+		"timestamps":        "Timestamps",
+		"locale":            "Locale",
+		"accessible":        "Accessible",
+		"credential_store":  "CredentialStore",
+		"pr_template":       "PRTemplate",
+		"issue_template":    "IssueTemplate",
+		"http_proxy":        "HTTPProxy",
+		"https_proxy":       "HTTPSProxy",
+		"no_proxy":          "NoProxy",
+		"http_idle_conns":   "HTTPIdleConns",
+		"http_idle_timeout": "HTTPIdleTimeout",
+		"http2":             "HTTP2",
+		"update_check":      "UpdateCheck",
+		"log_level":         "LogLevel",
 	}
 
 	fieldName, ok := keyMap[key]
@@ -93,3 +151,20 @@ func getConfigValue(cfg *coreconfig.Config, key string) (string, error) {
 		return fmt.Sprintf("%v", field.Interface()), nil
 	}
 }
+
+// getHostValue returns the value of a per-host config key
+func getHostValue(hostConfig *coreconfig.HostConfig, key string) (string, error) {
+	if hostConfig == nil {
+		hostConfig = &coreconfig.HostConfig{}
+	}
+
+	switch key {
+	case "git_protocol":
+		return hostConfig.GitProtocol, nil
+	case "ca_bundle":
+		return hostConfig.CABundle, nil
+	case "insecure_skip_tls":
+		return strconv.FormatBool(hostConfig.InsecureSkipTLS), nil
+	}
+	return "", fmt.Errorf("unknown host configuration key: %s", key)
+}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"path/filepath"
+	"sort"
 
 	"github.com/spf13/cobra"
 
@@ -11,14 +13,25 @@ import (
 
 // NewCmdConfigList creates the config list command
 func NewCmdConfigList(streams *iostreams.IOStreams) *cobra.Command {
+	var showSource bool
+
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "Print a list of configuration keys and values",
 		Long: `Print a list of configuration keys and values.
 
-Shows the current configuration settings from the config file.`,
+Shows the current configuration settings from the config file.
+
+With --show-source, shows the effective value of every setting instead,
+along with where it came from. Settings are resolved in this order, highest
+precedence first: command-line flags, BB_* environment variables, the
+repository's .bb.yml, the active profile, the config file, and the built-in
+defaults.`,
 		Example: `  # List all configuration settings
-  bb config list`,
+  bb config list
+
+  # Show effective settings and where they come from
+  bb config list --show-source`,
 		Aliases: []string{"ls"},
 		Args:    cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -28,6 +41,15 @@ Shows the current configuration settings from the config file.`,
 				return fmt.Errorf("could not load config: %w", err)
 			}
 
+			if showSource {
+				repo, err := coreconfig.LoadCurrentRepoConfig()
+				if err != nil {
+					return err
+				}
+				printResolved(streams, coreconfig.NewResolver(cfg, repo))
+				return nil
+			}
+
 			// Print configuration values
 			printConfig(streams, cfg)
 
@@ -35,6 +57,8 @@ Shows the current configuration settings from the config file.`,
 		},
 	}
 
+	cmd.Flags().BoolVar(&showSource, "show-source", false, "Show effective values and where they come from")
+
 	return cmd
 }
 
@@ -51,6 +75,22 @@ func printConfig(streams *iostreams.IOStreams, cfg *coreconfig.Config) {
 		{"pager", cfg.Pager},
 		{"browser", cfg.Browser},
 		{"http_timeout", cfg.HTTPTimeout},
+		{"theme", cfg.Theme},
+		{"icons", cfg.Icons},
+		{"timestamps", cfg.Timestamps},
+		{"locale", cfg.Locale},
+		{"accessible", cfg.Accessible},
+		{"credential_store", cfg.CredentialStore},
+		{"pr_template", cfg.PRTemplate},
+		{"issue_template", cfg.IssueTemplate},
+		{"http_proxy", cfg.HTTPProxy},
+		{"https_proxy", cfg.HTTPSProxy},
+		{"no_proxy", cfg.NoProxy},
+		{"http_idle_conns", cfg.HTTPIdleConns},
+		{"http_idle_timeout", cfg.HTTPIdleTimeout},
+		{"http2", cfg.HTTP2},
+		{"update_check", cfg.UpdateCheck},
+		{"log_level", cfg.LogLevel},
 	}
 
 	for _, s := range settings {
@@ -59,6 +99,34 @@ func printConfig(streams *iostreams.IOStreams, cfg *coreconfig.Config) {
 			fmt.Fprintf(streams.Out, "%s=%s\n", s.key, value)
 		}
 	}
+
+	commands := make([]string, 0, len(cfg.Fields))
+	for command := range cfg.Fields {
+		commands = append(commands, command)
+	}
+	sort.Strings(commands)
+	for _, command := range commands {
+		fmt.Fprintf(streams.Out, "%s%s=%s\n", coreconfig.FieldsKeyPrefix, command, cfg.Fields[command])
+	}
+}
+
+// printResolved prints the effective value of every setting with its source
+func printResolved(streams *iostreams.IOStreams, resolver *coreconfig.Resolver) {
+	for _, s := range resolver.All() {
+		source := s.Source
+		switch s.Source {
+		case coreconfig.SourceEnv:
+			source = "environment " + s.Env
+		case coreconfig.SourceRepo:
+			source = coreconfig.RepoConfigFileName
+		case coreconfig.SourceConfig:
+			if dir, err := coreconfig.ConfigDir(); err == nil {
+				source = filepath.Join(dir, coreconfig.ConfigFileName)
+			}
+		}
+		fmt.Fprintf(streams.Out, "%s=%s %s\n", s.Key, s.Value,
+			streams.Style(iostreams.RoleMuted, "(from: "+source+")"))
+	}
 }
 
 // formatValue formats a config value for display
@@ -72,6 +140,9 @@ func formatValue(v interface{}) string {
 		}
 		return fmt.Sprintf("%d", val)
 	case bool:
+		if !val {
+			return ""
+		}
 		return fmt.Sprintf("%t", val)
 	default:
 		return fmt.Sprintf("%v", val)
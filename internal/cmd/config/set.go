@@ -2,13 +2,18 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/rbansal42/bitbucket-cli/internal/api"
 	coreconfig "github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/i18n"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+	"github.com/rbansal42/bitbucket-cli/internal/logging"
 )
 
 // NewCmdConfigSet creates the config set command
@@ -21,12 +26,34 @@ func NewCmdConfigSet(streams *iostreams.IOStreams) *cobra.Command {
 		Long: `Update configuration with a value for the given key.
 
 Available keys:
-  git_protocol   The protocol to use for git operations (ssh, https)
-  editor         The editor to use for composing text
-  prompt         Whether to enable interactive prompts (enabled, disabled)
-  pager          The pager to use for output
-  browser        The browser to use for opening URLs
-  http_timeout   HTTP request timeout in seconds`,
+  git_protocol       The protocol to use for git operations (ssh, https)
+  editor             The editor to use for composing text
+  prompt             Whether to enable interactive prompts (enabled, disabled)
+  pager              The pager to use for output
+  browser            The browser to use for opening URLs
+  http_timeout       HTTP request timeout in seconds
+  theme              The color theme (default, colorblind, high-contrast, monochrome)
+  icons              Status icons in table output (none, emoji, nerd)
+  timestamps         How times are shown (relative, absolute, iso)
+  locale             Language of messages, e.g. en (default from LANG)
+  accessible         Output suited to screen readers (true, false)
+  credential_store   Where tokens are stored (keyring, file, pass, plaintext)
+  pr_template        Template file or text for new pull request descriptions
+  issue_template     Template file or text for new issue descriptions
+  http_proxy         Proxy URL for http:// requests, instead of HTTP_PROXY
+  https_proxy        Proxy URL for https:// requests, instead of HTTPS_PROXY
+  no_proxy           Comma-separated hosts reached directly, instead of NO_PROXY
+  http_idle_conns    Idle connections kept open per host for reuse
+  http_idle_timeout  Seconds an idle connection is kept open
+  http2              Whether to use HTTP/2 when the server offers it (enabled, disabled)
+  update_check       Whether bb version looks for a newer release (enabled, disabled)
+  log_level          Write a log file of this level and above (debug, info, warn, error)
+  fields.<cmd>       Default table columns for a list command, e.g. fields.pr.list
+
+Keys set with --host, stored in hosts.yml:
+  git_protocol       The protocol to use for git operations with the host
+  ca_bundle          PEM file of CA certificates to trust for the host
+  insecure_skip_tls  Skip TLS certificate verification for the host (true, false)`,
 		Example: `  # Set the git protocol to HTTPS
   bb config set git_protocol https
 
@@ -37,12 +64,47 @@ Available keys:
   bb config set prompt disabled
 
   # Set HTTP timeout to 60 seconds
-  bb config set http_timeout 60`,
+  bb config set http_timeout 60
+
+  # Use a color theme without red/green pairs
+  bb config set theme colorblind
+
+  # Show emoji next to PR, pipeline and issue states
+  bb config set icons emoji
+
+  # Write output a screen reader can follow
+  bb config set accessible true
+
+  # Keep tokens in an encrypted file instead of the OS keyring
+  bb config set credential_store file
+
+  # Start new pull request descriptions from a file
+  bb config set pr_template ~/templates/pull-request.md
+
+  # Send bb's requests through a proxy, except for internal hosts
+  bb config set https_proxy http://proxy.example.com:3128
+  bb config set no_proxy .internal.example.com,10.0.0.0/8
+
+  # Keep more connections open for bulk operations
+  bb config set http_idle_conns 32
+
+  # Log API requests and retries to a file, to look into a problem
+  bb config set log_level debug
+
+  # Choose the columns shown by "bb pr list"
+  bb config set fields.pr.list id,title,author,updated
+
+  # Trust a private CA for a Bitbucket Data Center server
+  bb config set -h bitbucket.mycompany.com ca_bundle ~/certs/mycompany-ca.pem`,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			key := strings.ToLower(args[0])
 			value := args[1]
 
+			if host != "" {
+				return setHostConfig(streams, host, key, value)
+			}
+
 			// Load config
 			cfg, err := coreconfig.LoadConfig()
 			if err != nil {
@@ -60,17 +122,26 @@ Available keys:
 			}
 
 			streams.Success("Set %s to %s", key, value)
+			if key == "credential_store" {
+				streams.Info("Tokens already stored are not moved; run 'bb auth login' again to store them in %s", value)
+			}
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&host, "host", "h", "", "Set per-host configuration")
+	// -h is taken by --host, so --help has no shorthand
+	cmd.Flags().Bool("help", false, "Show help for command")
 
 	return cmd
 }
 
 // setConfigValue sets a config value with validation
 func setConfigValue(cfg *coreconfig.Config, key, value string) error {
+	if strings.HasPrefix(key, coreconfig.FieldsKeyPrefix) {
+		return setFieldsValue(cfg, strings.TrimPrefix(key, coreconfig.FieldsKeyPrefix), value)
+	}
+
 	switch key {
 	case "git_protocol":
 		if value != "ssh" && value != "https" {
@@ -103,9 +174,190 @@ func setConfigValue(cfg *coreconfig.Config, key, value string) error {
 		}
 		cfg.HTTPTimeout = timeout
 
+	case "http_idle_conns", "http_idle_timeout":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %s (must be a number)", key, value)
+		}
+		if n < 1 {
+			return fmt.Errorf("%s must be at least 1", key)
+		}
+		if key == "http_idle_conns" {
+			cfg.HTTPIdleConns = n
+		} else {
+			cfg.HTTPIdleTimeout = n
+		}
+
+	case "http2":
+		if value != "enabled" && value != "disabled" {
+			return fmt.Errorf("invalid http2 value: %s (must be 'enabled' or 'disabled')", value)
+		}
+		cfg.HTTP2 = value
+
+	case "update_check":
+		if value != "enabled" && value != "disabled" {
+			return fmt.Errorf("invalid update_check value: %s (must be 'enabled' or 'disabled')", value)
+		}
+		cfg.UpdateCheck = value
+
+	case "theme":
+		if !iostreams.IsValidTheme(value) {
+			return fmt.Errorf("invalid theme: %s (must be one of: %s)", value, strings.Join(iostreams.ThemeNames(), ", "))
+		}
+		cfg.Theme = value
+
+	case "icons":
+		if !iostreams.IsValidIconSet(value) {
+			return fmt.Errorf("invalid icons value: %s (must be one of: %s)", value, strings.Join(iostreams.IconSetNames(), ", "))
+		}
+		cfg.Icons = value
+
+	case "timestamps":
+		if !iostreams.IsValidTimestampStyle(value) {
+			return fmt.Errorf("invalid timestamps value: %s (must be one of: %s)", value, strings.Join(iostreams.TimestampStyleNames(), ", "))
+		}
+		cfg.Timestamps = value
+
+	case "locale":
+		if !i18n.IsSupported(value) {
+			return fmt.Errorf("invalid locale: %s (must be one of: %s)", value, strings.Join(i18n.Languages(), ", "))
+		}
+		cfg.Locale = value
+
+	case "accessible":
+		accessible, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid accessible value: %s (must be 'true' or 'false')", value)
+		}
+		cfg.Accessible = accessible
+
+	case "credential_store":
+		if !coreconfig.IsValidCredentialStore(value) {
+			return fmt.Errorf("invalid credential_store: %s (must be one of: %s)", value, strings.Join(coreconfig.CredentialStoreNames(), ", "))
+		}
+		cfg.CredentialStore = value
+
+	case "pr_template":
+		cfg.PRTemplate = value
+
+	case "issue_template":
+		cfg.IssueTemplate = value
+
+	case "http_proxy", "https_proxy":
+		if value != "" {
+			if err := api.ValidateProxyURL(value); err != nil {
+				return fmt.Errorf("invalid %s: %w", key, err)
+			}
+		}
+		if key == "http_proxy" {
+			cfg.HTTPProxy = value
+		} else {
+			cfg.HTTPSProxy = value
+		}
+
+	case "no_proxy":
+		cfg.NoProxy = value
+
+	case "log_level":
+		if !logging.IsValidLevel(value) {
+			return fmt.Errorf("invalid log_level: %s (must be one of: %s)", value, strings.Join(logging.Levels(), ", "))
+		}
+		cfg.LogLevel = strings.ToLower(value)
+
 	default:
 		return fmt.Errorf("unknown configuration key: %s", key)
 	}
 
 	return nil
 }
+
+// setHostConfig sets a per-host key in the hosts config
+func setHostConfig(streams *iostreams.IOStreams, host, key, value string) error {
+	hosts, err := coreconfig.LoadHostsConfig()
+	if err != nil {
+		return fmt.Errorf("could not load hosts config: %w", err)
+	}
+
+	hostConfig := hosts[host]
+	if hostConfig == nil {
+		hostConfig = &coreconfig.HostConfig{}
+		hosts[host] = hostConfig
+	}
+
+	if err := setHostValue(hostConfig, key, value); err != nil {
+		return err
+	}
+
+	if err := coreconfig.SaveHostsConfig(hosts); err != nil {
+		return fmt.Errorf("could not save hosts config: %w", err)
+	}
+
+	streams.Success("Set %s to %s for %s", key, value, host)
+	if hostConfig.InsecureSkipTLS && key == "insecure_skip_tls" {
+		streams.Warning("TLS certificates of %s will not be verified; prefer ca_bundle for servers with a private CA", host)
+	}
+	return nil
+}
+
+// setHostValue sets a per-host config value with validation
+func setHostValue(hostConfig *coreconfig.HostConfig, key, value string) error {
+	switch key {
+	case "git_protocol":
+		if value != "ssh" && value != "https" {
+			return fmt.Errorf("invalid git_protocol: %s (must be 'ssh' or 'https')", value)
+		}
+		hostConfig.GitProtocol = value
+
+	case "ca_bundle":
+		if value != "" {
+			path := value
+			if strings.HasPrefix(path, "~/") {
+				if home, err := os.UserHomeDir(); err == nil {
+					path = filepath.Join(home, path[2:])
+				}
+			}
+			if _, err := os.Stat(path); err != nil {
+				return fmt.Errorf("invalid ca_bundle: %w", err)
+			}
+		}
+		hostConfig.CABundle = value
+
+	case "insecure_skip_tls":
+		skip, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid insecure_skip_tls: %s (must be 'true' or 'false')", value)
+		}
+		hostConfig.InsecureSkipTLS = skip
+
+	default:
+		return fmt.Errorf("unknown host configuration key: %s", key)
+	}
+
+	return nil
+}
+
+// setFieldsValue sets the default table columns for command. Column names
+// are checked when the command runs, since only it knows its columns. An
+// empty value restores the command's defaults.
+func setFieldsValue(cfg *coreconfig.Config, command, value string) error {
+	if command == "" {
+		return fmt.Errorf("missing command in %s<command> key, e.g. fields.pr.list", coreconfig.FieldsKeyPrefix)
+	}
+
+	var fields []string
+	for _, f := range strings.Split(value, ",") {
+		if f = strings.ToLower(strings.TrimSpace(f)); f != "" {
+			fields = append(fields, f)
+		}
+	}
+
+	if len(fields) == 0 {
+		delete(cfg.Fields, command)
+		return nil
+	}
+	if cfg.Fields == nil {
+		cfg.Fields = make(map[string]string)
+	}
+	cfg.Fields[command] = strings.Join(fields, ",")
+	return nil
+}
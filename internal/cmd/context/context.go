@@ -0,0 +1,41 @@
+// Package context implements the bb context commands, which manage named
+// profiles of account and default settings.
+package context
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// NewCmdContext creates the context command and its subcommands
+func NewCmdContext(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "context <command>",
+		Short: "Manage profiles for switching between accounts",
+		Long: `Manage named profiles, also called contexts.
+
+A profile combines a host, a user, a default workspace and a default
+repository. Switching profiles switches all of them at once, which is handy
+when you work with more than one Bitbucket account.
+
+The current profile is saved in config. Set the BB_PROFILE environment
+variable to use a different profile for a single shell or command.`,
+		Example: `  # Create a profile for your work account
+  bb context create work --user alice-work --workspace acme
+
+  # Switch to it
+  bb context use work
+
+  # Run one command with another profile
+  BB_PROFILE=personal bb repo list`,
+		Aliases: []string{"profile"},
+	}
+
+	cmd.AddCommand(NewCmdCreate(streams))
+	cmd.AddCommand(NewCmdUse(streams))
+	cmd.AddCommand(NewCmdList(streams))
+	cmd.AddCommand(NewCmdDelete(streams))
+
+	return cmd
+}
@@ -0,0 +1,207 @@
+package context
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// setupConfig gives the test its own config directory, with alice logged in
+// to bitbucket.org
+func setupConfig(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("BB_CONFIG_DIR", dir)
+	t.Setenv(config.ProfileEnvVar, "")
+	hosts := "bitbucket.org:\n  user: alice\n  users:\n    alice: {}\n"
+	if err := os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte(hosts), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// runContext runs bb context with args and returns what it printed
+func runContext(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	cmd := NewCmdContext(&iostreams.IOStreams{Out: &out, ErrOut: &out})
+	cmd.SetArgs(args)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func currentProfile(t *testing.T) string {
+	t.Helper()
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg.CurrentProfile
+}
+
+func TestCreate(t *testing.T) {
+	setupConfig(t)
+
+	if _, err := runContext(t, "create", "personal", "--workspace", "alice"); err != nil {
+		t.Fatalf("create error = %v", err)
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := cfg.Profiles["personal"]
+	if p == nil || p.Host != config.DefaultHost || p.User != "alice" || p.Workspace != "alice" {
+		t.Errorf("profile = %+v, want alice on %s with workspace alice", p, config.DefaultHost)
+	}
+	if cfg.CurrentProfile != "" {
+		t.Errorf("create without --use switched to %q", cfg.CurrentProfile)
+	}
+
+	if _, err := runContext(t, "create", "personal"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("creating an existing profile error = %v", err)
+	}
+
+	out, err := runContext(t, "create", "work", "--user", "alice-work", "--repo", "acme/api", "--use")
+	if err != nil {
+		t.Fatalf("create --use error = %v", err)
+	}
+	if !strings.Contains(out, "alice-work has not logged in to bitbucket.org") {
+		t.Errorf("output = %q, want a warning that alice-work is not logged in", out)
+	}
+	if got := currentProfile(t); got != "work" {
+		t.Errorf("current profile = %q, want work", got)
+	}
+
+	if _, err := runContext(t, "create", "bad", "--repo", "acme"); err == nil {
+		t.Error("create with an invalid --repo should fail")
+	}
+}
+
+func TestUse(t *testing.T) {
+	setupConfig(t)
+	for _, name := range []string{"personal", "work"} {
+		if _, err := runContext(t, "create", name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Switching between profiles
+	for _, name := range []string{"work", "personal"} {
+		out, err := runContext(t, "use", name)
+		if err != nil {
+			t.Fatalf("use %s error = %v", name, err)
+		}
+		if got := currentProfile(t); got != name {
+			t.Errorf("current profile after use %s = %q", name, got)
+		}
+		if !strings.Contains(out, "Switched to profile "+name) {
+			t.Errorf("output = %q", out)
+		}
+	}
+
+	// A missing profile leaves the current one alone
+	_, err := runContext(t, "use", "missing")
+	if code := cmdutil.ExitCode(err); code != cmdutil.ExitNotFound {
+		t.Errorf("use missing: exit code = %d (error %v), want %d", code, err, cmdutil.ExitNotFound)
+	}
+	if got := currentProfile(t); got != "personal" {
+		t.Errorf("current profile after using a missing one = %q, want personal", got)
+	}
+
+	// BB_PROFILE wins over the switch, which is pointed out
+	t.Setenv(config.ProfileEnvVar, "work")
+	out, err := runContext(t, "use", "personal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "BB_PROFILE is set to work") {
+		t.Errorf("output = %q, want a warning about BB_PROFILE", out)
+	}
+	t.Setenv(config.ProfileEnvVar, "")
+
+	if _, err := runContext(t, "use", "--none"); err != nil {
+		t.Fatalf("use --none error = %v", err)
+	}
+	if got := currentProfile(t); got != "" {
+		t.Errorf("current profile after use --none = %q", got)
+	}
+
+	for _, args := range [][]string{{"use"}, {"use", "work", "--none"}} {
+		_, err := runContext(t, args...)
+		if code := cmdutil.ExitCode(err); code != cmdutil.ExitUsage {
+			t.Errorf("%q: exit code = %d (error %v), want %d", args, code, err, cmdutil.ExitUsage)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	setupConfig(t)
+
+	out, err := runContext(t, "list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "No profiles configured") {
+		t.Errorf("list with no profiles = %q", out)
+	}
+
+	if _, err := runContext(t, "create", "personal", "--workspace", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runContext(t, "create", "work", "--repo", "acme/api", "--use"); err != nil {
+		t.Fatal(err)
+	}
+	out, err = runContext(t, "list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "*\twork\tbitbucket.org\talice\t-\tacme/api\n"
+	if !strings.Contains(out, want) || !strings.Contains(out, "\tpersonal\tbitbucket.org\talice\talice\t-\n") {
+		t.Errorf("list = %q, want work marked as in use", out)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	setupConfig(t)
+	for _, name := range []string{"personal", "work"} {
+		if _, err := runContext(t, "create", name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := runContext(t, "use", "work"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Deleting another profile keeps the current one
+	if _, err := runContext(t, "delete", "personal"); err != nil {
+		t.Fatalf("delete error = %v", err)
+	}
+	if got := currentProfile(t); got != "work" {
+		t.Errorf("current profile after deleting another = %q, want work", got)
+	}
+
+	// Deleting the current profile stops using profiles
+	if _, err := runContext(t, "delete", "work"); err != nil {
+		t.Fatalf("delete error = %v", err)
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.CurrentProfile != "" || len(cfg.Profiles) != 0 {
+		t.Errorf("after deleting every profile, current = %q and profiles = %v", cfg.CurrentProfile, cfg.Profiles)
+	}
+
+	_, err = runContext(t, "delete", "work")
+	if code := cmdutil.ExitCode(err); code != cmdutil.ExitNotFound {
+		t.Errorf("delete missing: exit code = %d (error %v), want %d", code, err, cmdutil.ExitNotFound)
+	}
+}
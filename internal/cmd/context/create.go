@@ -0,0 +1,114 @@
+package context
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+type createOptions struct {
+	streams   *iostreams.IOStreams
+	name      string
+	host      string
+	user      string
+	workspace string
+	repo      string
+	use       bool
+	force     bool
+}
+
+// NewCmdCreate creates the context create command
+func NewCmdCreate(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &createOptions{
+		streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a profile",
+		Long: `Create a named profile.
+
+The user must already be logged in on the host with 'bb auth login'. If
+--user is not given, the host's active user is used.`,
+		Example: `  # Create a profile using the currently logged in user
+  bb context create personal --workspace alice
+
+  # Create a profile for another account and switch to it
+  bb context create work --user alice-work --workspace acme --repo acme/api --use`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name = args[0]
+			return runCreate(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.host, "host", config.DefaultHost, "Bitbucket hostname")
+	cmd.Flags().StringVarP(&opts.user, "user", "u", "", "User to authenticate as (default: the host's active user)")
+	cmd.Flags().StringVarP(&opts.workspace, "workspace", "w", "", "Default workspace")
+	cmd.Flags().StringVarP(&opts.repo, "repo", "R", "", "Default repository in WORKSPACE/REPO format")
+	cmd.Flags().BoolVar(&opts.use, "use", false, "Switch to the profile after creating it")
+	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Replace an existing profile with the same name")
+
+	return cmd
+}
+
+func runCreate(opts *createOptions) error {
+	if strings.TrimSpace(opts.name) == "" {
+		return fmt.Errorf("profile name cannot be empty")
+	}
+	if opts.repo != "" {
+		parts := strings.SplitN(opts.repo, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("invalid repository format: %s (expected workspace/repo)", opts.repo)
+		}
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("could not load config: %w", err)
+	}
+	if _, exists := cfg.Profiles[opts.name]; exists && !opts.force {
+		return fmt.Errorf("profile %q already exists. Use --force to replace it", opts.name)
+	}
+
+	hosts, err := config.LoadHostsConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load hosts config: %w", err)
+	}
+	user := opts.user
+	if user == "" {
+		user = hosts.GetActiveUser(opts.host)
+		if user == "" {
+			return fmt.Errorf("not logged in to %s. Run 'bb auth login' first or pass --user", opts.host)
+		}
+	} else if host, ok := hosts[opts.host]; !ok || host.Users[user] == nil {
+		opts.streams.Warning("%s has not logged in to %s; run 'bb auth login' before using this profile", user, opts.host)
+	}
+
+	if cfg.Profiles == nil {
+		cfg.Profiles = make(map[string]*config.Profile)
+	}
+	cfg.Profiles[opts.name] = &config.Profile{
+		Host:      opts.host,
+		User:      user,
+		Workspace: opts.workspace,
+		Repo:      opts.repo,
+	}
+	if opts.use {
+		cfg.CurrentProfile = opts.name
+	}
+
+	if err := config.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("could not save config: %w", err)
+	}
+
+	opts.streams.Success("Created profile %s for %s on %s", opts.name, user, opts.host)
+	if opts.use {
+		opts.streams.Success("Switched to profile %s", opts.name)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package context
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// NewCmdDelete creates the context delete command
+func NewCmdDelete(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a profile",
+		Long: `Delete a profile. Credentials stay in the keyring; use 'bb auth logout'
+to remove them.`,
+		Example: `  # Delete the old-client profile
+  bb context delete old-client`,
+		Aliases: []string{"rm"},
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDelete(streams, args[0])
+		},
+	}
+
+	return cmd
+}
+
+func runDelete(streams *iostreams.IOStreams, name string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("could not load config: %w", err)
+	}
+	if _, ok := cfg.Profiles[name]; !ok {
+		return cmdutil.NewExitError(cmdutil.ExitNotFound, fmt.Errorf("profile %q does not exist", name))
+	}
+
+	delete(cfg.Profiles, name)
+	if cfg.CurrentProfile == name {
+		cfg.CurrentProfile = ""
+	}
+
+	if err := config.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("could not save config: %w", err)
+	}
+
+	streams.Success("Deleted profile %s", name)
+	return nil
+}
@@ -0,0 +1,66 @@
+package context
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// NewCmdList creates the context list command
+func NewCmdList(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List profiles",
+		Long: `List the configured profiles. The profile in use is marked with an
+asterisk.`,
+		Example: `  # List profiles
+  bb context list`,
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(streams)
+		},
+	}
+
+	return cmd
+}
+
+func runList(streams *iostreams.IOStreams) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("could not load config: %w", err)
+	}
+
+	names := cfg.ProfileNames()
+	if len(names) == 0 {
+		streams.Info("No profiles configured")
+		streams.Info("Use 'bb context create <name>' to create one")
+		return nil
+	}
+
+	active := cfg.ActiveProfileName()
+	table := cmdutil.NewTablePrinter(streams)
+	table.AddHeader("", "NAME", "HOST", "USER", "WORKSPACE", "REPO")
+	for _, name := range names {
+		p := cfg.Profiles[name]
+		marker := ""
+		if name == active {
+			marker = "*"
+			name = streams.Style(iostreams.RoleSuccess, name)
+		}
+		table.AddRow(marker, name, p.Host, p.User, valueOrDash(p.Workspace), valueOrDash(p.Repo))
+	}
+
+	return table.Render()
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
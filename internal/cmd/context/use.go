@@ -0,0 +1,87 @@
+package context
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+type useOptions struct {
+	streams *iostreams.IOStreams
+	name    string
+	none    bool
+}
+
+// NewCmdUse creates the context use command
+func NewCmdUse(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &useOptions{
+		streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "use [<name>]",
+		Short: "Switch to a profile",
+		Long: `Switch the current profile.
+
+With --none, stop using profiles and go back to the host's active user
+and the default_workspace setting.`,
+		Example: `  # Switch to the personal profile
+  bb context use personal
+
+  # Stop using profiles
+  bb context use --none`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				opts.name = args[0]
+			}
+			if opts.name == "" && !opts.none {
+				return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("specify a profile name or --none"))
+			}
+			if opts.name != "" && opts.none {
+				return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("cannot use a profile name with --none"))
+			}
+			return runUse(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.none, "none", false, "Stop using profiles")
+
+	return cmd
+}
+
+func runUse(opts *useOptions) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("could not load config: %w", err)
+	}
+
+	if opts.none {
+		cfg.CurrentProfile = ""
+	} else {
+		if _, ok := cfg.Profiles[opts.name]; !ok {
+			return cmdutil.NewExitError(cmdutil.ExitNotFound,
+				fmt.Errorf("profile %q does not exist. Run 'bb context list' to see the available profiles", opts.name))
+		}
+		cfg.CurrentProfile = opts.name
+	}
+
+	if err := config.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("could not save config: %w", err)
+	}
+
+	if opts.none {
+		opts.streams.Success("No longer using a profile")
+	} else {
+		opts.streams.Success("Switched to profile %s", opts.name)
+	}
+	if env := os.Getenv(config.ProfileEnvVar); env != "" {
+		opts.streams.Warning("%s is set to %s and overrides the current profile", config.ProfileEnvVar, env)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// NewCmdEnvironment creates the environment command and its subcommands
+func NewCmdEnvironment(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "environment <command>",
+		Short: "Manage deployment environments",
+		Long: `List the deployment environments of a repository, such as test, staging
+and production, and manage the variables set for the pipeline steps that
+deploy to each of them.
+
+Environments are given by name, slug or UUID.`,
+		Example: `  # List the deployment environments of the current repository
+  bb environment list
+
+  # List the variables of the production environment
+  bb environment variable list --environment production
+
+  # Set a secured variable for staging
+  bb environment variable set DEPLOY_TOKEN --environment staging --secured`,
+		Aliases: []string{"environments", "env"},
+	}
+
+	cmd.AddCommand(NewCmdList(streams))
+	cmd.AddCommand(NewCmdVariable(streams))
+
+	return cmd
+}
+
+// findEnvironment finds the environment of a repository named, or with the
+// slug or UUID, name
+func findEnvironment(ctx context.Context, client *api.Client, workspace, repoSlug, name string) (*api.Environment, error) {
+	environments, err := client.ListEnvironments(ctx, workspace, repoSlug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+
+	names := make([]string, 0, len(environments))
+	for i, env := range environments {
+		if strings.EqualFold(env.Name, name) || strings.EqualFold(env.Slug, name) || strings.EqualFold(strings.Trim(env.UUID, "{}"), strings.Trim(name, "{}")) {
+			return &environments[i], nil
+		}
+		names = append(names, env.Name)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%s/%s has no deployment environments", workspace, repoSlug)
+	}
+	return nil, fmt.Errorf("no environment %q in %s/%s (available: %s)", name, workspace, repoSlug, strings.Join(names, ", "))
+}
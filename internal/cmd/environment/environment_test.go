@@ -0,0 +1,60 @@
+package environment
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+func TestFindEnvironment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"values": [
+			{"uuid": "{aaa}", "name": "Staging", "slug": "staging"},
+			{"uuid": "{bbb}", "name": "Production EU", "slug": "production-eu"}
+		]}`))
+	}))
+	defer server.Close()
+	client := api.NewClient(api.WithBaseURL(server.URL))
+
+	for _, name := range []string{"Production EU", "production eu", "production-eu", "bbb", "{bbb}"} {
+		env, err := findEnvironment(context.Background(), client, "team", "app", name)
+		if err != nil {
+			t.Errorf("findEnvironment(%q) error: %v", name, err)
+			continue
+		}
+		if env.UUID != "{bbb}" {
+			t.Errorf("findEnvironment(%q) = %s, want {bbb}", name, env.UUID)
+		}
+	}
+
+	_, err := findEnvironment(context.Background(), client, "team", "app", "test")
+	if err == nil || !strings.Contains(err.Error(), "available: Staging, Production EU") {
+		t.Errorf("expected an error naming the environments, got %v", err)
+	}
+}
+
+func TestVariableValue(t *testing.T) {
+	opts := &variableOptions{
+		streams: &iostreams.IOStreams{In: strings.NewReader("s3cret\n"), Out: &bytes.Buffer{}},
+		key:     "TOKEN",
+	}
+	value, err := variableValue(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value != "s3cret" {
+		t.Errorf("value = %q, want s3cret", value)
+	}
+
+	opts.value, opts.hasValue = "", true
+	if value, _ := variableValue(opts); value != "" {
+		t.Errorf("expected an empty argument to be kept, got %q", value)
+	}
+}
@@ -0,0 +1,90 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// ListOptions holds the options for the list command
+type ListOptions struct {
+	Repo    string
+	JSON    bool
+	Format  string
+	Streams *iostreams.IOStreams
+}
+
+// NewCmdList creates the environment list command
+func NewCmdList(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &ListOptions{
+		Streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the deployment environments of a repository",
+		Long: `List the deployment environments of a repository, in the order Bitbucket
+shows them, with their type: Test, Staging or Production.`,
+		Example: `  # List the environments of the current repository
+  bb environment list
+
+  # List the environments of another repository as JSON
+  bb environment list -R myworkspace/myrepo --json`,
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format (detects from git remote if not specified)")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
+
+	return cmd
+}
+
+func runList(ctx context.Context, opts *ListOptions) error {
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.Repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	environments, err := client.ListEnvironments(ctx, workspace, repoSlug)
+	if err != nil {
+		return fmt.Errorf("failed to list environments: %w", err)
+	}
+
+	if opts.JSON || opts.Format != "" {
+		if environments == nil {
+			environments = []api.Environment{}
+		}
+		return cmdutil.PrintFormatted(opts.Streams, opts.Format, environments)
+	}
+
+	if len(environments) == 0 {
+		opts.Streams.Info("No deployment environments in %s/%s", workspace, repoSlug)
+		return nil
+	}
+
+	table := cmdutil.NewTablePrinter(opts.Streams)
+	table.AddHeader("NAME", "TYPE", "UUID")
+	for _, env := range environments {
+		table.AddRow(env.Name, env.EnvironmentType.Name, opts.Streams.Style(iostreams.RoleMuted, env.UUID))
+	}
+	return table.Render()
+}
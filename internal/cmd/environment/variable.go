@@ -0,0 +1,342 @@
+package environment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// variableNamePattern matches the names Bitbucket accepts for deployment
+// variables
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type variableOptions struct {
+	streams     *iostreams.IOStreams
+	repo        string
+	environment string
+	key         string
+	value       string
+	hasValue    bool
+	secured     bool
+	yes         bool
+	json        bool
+}
+
+// NewCmdVariable creates the variable command and its subcommands
+func NewCmdVariable(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "variable <command>",
+		Short: "Manage the variables of a deployment environment",
+		Long: `List, set and delete the variables of a deployment environment. They are
+set for the pipeline steps that deploy to the environment, alongside the
+repository's and workspace's variables, which they override.
+
+Secured variables are masked in pipeline logs, and their values can't be
+read back.`,
+		Example: `  # List the variables of the production environment
+  bb environment variable list --environment production
+
+  # Set a variable
+  bb environment variable set API_URL https://api.example.com -e staging
+
+  # Delete a variable
+  bb environment variable delete API_URL -e staging`,
+		Aliases: []string{"variables", "var"},
+	}
+
+	cmd.AddCommand(newCmdVariableList(streams))
+	cmd.AddCommand(newCmdVariableSet(streams))
+	cmd.AddCommand(newCmdVariableDelete(streams))
+
+	return cmd
+}
+
+func newCmdVariableList(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &variableOptions{
+		streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "list --environment <name>",
+		Short: "List the variables of a deployment environment",
+		Long: `List the variables of a deployment environment. The values of secured
+variables are not shown.`,
+		Example: `  # List the variables of the production environment
+  bb environment variable list --environment production
+
+  # Output as JSON
+  bb environment variable list -e production --json`,
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runVariableList(cmd.Context(), opts)
+		},
+	}
+
+	addVariableFlags(cmd, opts)
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func newCmdVariableSet(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &variableOptions{
+		streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "set <name> [<value>] --environment <name>",
+		Short: "Set a variable of a deployment environment",
+		Long: `Add a variable to a deployment environment, or replace the value of one
+it already has.
+
+Without a value argument, the value is read from standard input, or asked
+for when it is a terminal, so secrets needn't appear in the shell's
+history. With --secured, the value is masked in pipeline logs and can't be
+read back; a secured variable stays secured when its value is replaced.`,
+		Example: `  # Set a variable
+  bb environment variable set API_URL https://api.example.com -e staging
+
+  # Set a secured variable, typing its value at a prompt
+  bb environment variable set DEPLOY_TOKEN -e production --secured
+
+  # Set a secured variable from a file
+  bb environment variable set SSH_KEY -e production --secured < deploy_key`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.key = args[0]
+			if len(args) > 1 {
+				opts.value, opts.hasValue = args[1], true
+			}
+			if !variableNamePattern.MatchString(opts.key) {
+				return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("invalid variable name %q: use letters, digits and underscores, not starting with a digit", opts.key))
+			}
+			return runVariableSet(cmd.Context(), opts)
+		},
+	}
+
+	addVariableFlags(cmd, opts)
+	cmd.Flags().BoolVar(&opts.secured, "secured", false, "Mask the value in logs and keep it from being read back")
+
+	return cmd
+}
+
+func newCmdVariableDelete(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &variableOptions{
+		streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "delete <name> --environment <name>",
+		Short: "Delete a variable of a deployment environment",
+		Long: `Delete a variable of a deployment environment. You will be prompted to
+confirm unless --yes is given.`,
+		Example: `  # Delete a variable
+  bb environment variable delete API_URL -e staging
+
+  # Delete a variable without confirmation
+  bb environment variable delete API_URL -e staging --yes`,
+		Aliases: []string{"rm"},
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.key = args[0]
+			return runVariableDelete(cmd.Context(), opts)
+		},
+	}
+
+	addVariableFlags(cmd, opts)
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+func addVariableFlags(cmd *cobra.Command, opts *variableOptions) {
+	cmd.Flags().StringVarP(&opts.environment, "environment", "e", "", "Name, slug or UUID of the deployment environment (required)")
+	cmd.Flags().StringVarP(&opts.repo, "repo", "R", "", "Repository in WORKSPACE/REPO format (detects from git remote if not specified)")
+	_ = cmd.MarkFlagRequired("environment")
+}
+
+// variableContext is what every variable command needs: the client, the
+// repository and the environment
+type variableContext struct {
+	client    *api.Client
+	workspace string
+	repoSlug  string
+	env       *api.Environment
+}
+
+func resolveVariableContext(ctx context.Context, opts *variableOptions) (*variableContext, error) {
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.repo)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := findEnvironment(ctx, client, workspace, repoSlug, opts.environment)
+	if err != nil {
+		return nil, err
+	}
+	return &variableContext{client: client, workspace: workspace, repoSlug: repoSlug, env: env}, nil
+}
+
+func runVariableList(ctx context.Context, opts *variableOptions) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	vc, err := resolveVariableContext(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	variables, err := vc.client.ListDeploymentVariables(ctx, vc.workspace, vc.repoSlug, vc.env.UUID)
+	if err != nil {
+		return fmt.Errorf("failed to list variables: %w", err)
+	}
+
+	if opts.json {
+		if variables == nil {
+			variables = []api.DeploymentVariable{}
+		}
+		return cmdutil.PrintJSON(opts.streams, variables)
+	}
+
+	if len(variables) == 0 {
+		opts.streams.Info("No variables in environment %s", vc.env.Name)
+		return nil
+	}
+
+	table := cmdutil.NewTablePrinter(opts.streams)
+	table.AddHeader("NAME", "VALUE", "SECURED")
+	for _, v := range variables {
+		value, secured := v.Value, "no"
+		if v.Secured {
+			value, secured = opts.streams.Style(iostreams.RoleMuted, "••••••••"), "yes"
+		}
+		table.AddRow(v.Key, value, secured)
+	}
+	return table.Render()
+}
+
+func runVariableSet(ctx context.Context, opts *variableOptions) error {
+	value, err := variableValue(opts)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	vc, err := resolveVariableContext(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	variables, err := vc.client.ListDeploymentVariables(ctx, vc.workspace, vc.repoSlug, vc.env.UUID)
+	if err != nil {
+		return fmt.Errorf("failed to list variables: %w", err)
+	}
+
+	variable := &api.DeploymentVariable{Key: opts.key, Value: value, Secured: opts.secured}
+	if existing := findVariable(variables, opts.key); existing != nil {
+		variable.UUID = existing.UUID
+		variable.Secured = variable.Secured || existing.Secured
+		if _, err := vc.client.UpdateDeploymentVariable(ctx, vc.workspace, vc.repoSlug, vc.env.UUID, variable); err != nil {
+			return fmt.Errorf("failed to update variable: %w", err)
+		}
+		opts.streams.Success("Updated variable %s of environment %s", opts.key, vc.env.Name)
+		return nil
+	}
+
+	if _, err := vc.client.CreateDeploymentVariable(ctx, vc.workspace, vc.repoSlug, vc.env.UUID, variable); err != nil {
+		return fmt.Errorf("failed to create variable: %w", err)
+	}
+	opts.streams.Success("Added variable %s to environment %s", opts.key, vc.env.Name)
+	return nil
+}
+
+func runVariableDelete(ctx context.Context, opts *variableOptions) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	vc, err := resolveVariableContext(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	variables, err := vc.client.ListDeploymentVariables(ctx, vc.workspace, vc.repoSlug, vc.env.UUID)
+	if err != nil {
+		return fmt.Errorf("failed to list variables: %w", err)
+	}
+	variable := findVariable(variables, opts.key)
+	if variable == nil {
+		return cmdutil.NewExitError(cmdutil.ExitNotFound, fmt.Errorf("no variable %s in environment %s", opts.key, vc.env.Name))
+	}
+
+	if !opts.yes {
+		confirmed, err := opts.streams.PromptConfirm(fmt.Sprintf("Are you sure you want to delete variable %s of environment %s?", opts.key, vc.env.Name), false)
+		if errors.Is(err, iostreams.ErrNoPrompt) {
+			return fmt.Errorf("cannot confirm deletion: stdin is not a terminal\nUse --yes flag to skip confirmation in non-interactive mode")
+		}
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return fmt.Errorf("deletion cancelled")
+		}
+	}
+
+	if err := vc.client.DeleteDeploymentVariable(ctx, vc.workspace, vc.repoSlug, vc.env.UUID, variable.UUID); err != nil {
+		return fmt.Errorf("failed to delete variable: %w", err)
+	}
+	opts.streams.Success("Deleted variable %s of environment %s", opts.key, vc.env.Name)
+	return nil
+}
+
+// variableValue returns the value to set: the argument, or else what is
+// typed at a prompt on a terminal, or else standard input without its
+// trailing newline
+func variableValue(opts *variableOptions) (string, error) {
+	if opts.hasValue {
+		return opts.value, nil
+	}
+	if opts.streams.CanPrompt() {
+		message := "Value of " + opts.key
+		if opts.secured {
+			return opts.streams.PromptSecret(message)
+		}
+		return opts.streams.PromptText(message, "")
+	}
+	if opts.streams.IsStdinTTY() {
+		return "", cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("give the value as an argument or on standard input"))
+	}
+	value, err := io.ReadAll(opts.streams.In)
+	if err != nil {
+		return "", fmt.Errorf("failed to read standard input: %w", err)
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(value), "\n"), "\r"), nil
+}
+
+// findVariable finds the variable named key, which Bitbucket matches
+// exactly
+func findVariable(variables []api.DeploymentVariable, key string) *api.DeploymentVariable {
+	for i := range variables {
+		if variables[i].Key == key {
+			return &variables[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package extension
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// prefix starts the executable name of every extension
+const prefix = "bb-"
+
+// Reserved are the names of bb's own commands, which extensions can't
+// take. The root command fills it in.
+var Reserved []string
+
+// NewCmdExtension creates the extension command and its subcommands
+func NewCmdExtension(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "extension <command>",
+		Short: "Manage bb extensions",
+		Long: `Install and manage extensions, which add commands to bb.
+
+An extension is an executable named bb-<name>, which runs as "bb <name>".
+Extensions are installed from repositories with the executable at their
+root, into the extensions directory under bb's config directory.
+Executables named bb-<name> on PATH work as extensions too.
+
+bb passes the repository and host selected by --repo and --hostname to
+extensions in BB_REPO and BB_HOST. Extensions written in Go can reuse bb's
+authentication and configuration with the
+github.com/rbansal42/bitbucket-cli/pkg/extension package.`,
+		Example: `  # Install an extension from Bitbucket
+  bb extension install workspace/bb-standup
+
+  # Run it
+  bb standup
+
+  # Install an extension being developed in the current directory
+  bb extension install .`,
+		Aliases: []string{"extensions", "ext"},
+	}
+
+	cmd.AddCommand(NewCmdInstall(streams))
+	cmd.AddCommand(NewCmdList(streams))
+	cmd.AddCommand(NewCmdUpgrade(streams))
+	cmd.AddCommand(NewCmdRemove(streams))
+
+	return cmd
+}
+
+// Extension is an installed extension, or one found on PATH
+type Extension struct {
+	// Name is the command the extension adds, without the bb- prefix
+	Name string
+	// Path is the extension's executable
+	Path string
+	// Dir is the directory it is installed in, empty for one on PATH
+	Dir string
+	// Local is set for an extension installed from a local directory,
+	// which Dir links to
+	Local bool
+}
+
+// Dir returns the directory extensions are installed in
+func Dir() (string, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "extensions"), nil
+}
+
+// Find returns the executable of the extension adding the command name,
+// preferring installed extensions to those on PATH
+func Find(name string) (string, bool) {
+	if !validName(name) {
+		return "", false
+	}
+	if dir, err := Dir(); err == nil {
+		path := filepath.Join(dir, prefix+name, prefix+name)
+		if isExecutable(path) {
+			return path, true
+		}
+	}
+	path, err := exec.LookPath(prefix + name)
+	if err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// Run runs the extension executable at path with args, connected to
+// streams. When the extension fails, the returned error makes bb exit with
+// the extension's exit code.
+func Run(path string, args []string, streams *iostreams.IOStreams) error {
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = streams.In
+	cmd.Stdout = streams.Out
+	cmd.Stderr = streams.ErrOut
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			code := exitErr.ExitCode()
+			if code <= 0 {
+				// Killed by a signal
+				code = cmdutil.ExitFailure
+			}
+			return cmdutil.NewExitError(code, err)
+		}
+		return fmt.Errorf("failed to run extension %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// installed returns the extensions in the extensions directory, sorted by
+// name
+func installed() ([]Extension, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read extensions directory: %w", err)
+	}
+
+	var exts []Extension
+	for _, entry := range entries {
+		name, ok := strings.CutPrefix(entry.Name(), prefix)
+		if !ok || !validName(name) {
+			continue
+		}
+		extDir := filepath.Join(dir, entry.Name())
+		exts = append(exts, Extension{
+			Name:  name,
+			Path:  filepath.Join(extDir, entry.Name()),
+			Dir:   extDir,
+			Local: entry.Type()&fs.ModeSymlink != 0,
+		})
+	}
+	return exts, nil
+}
+
+// onPath returns the extensions on PATH, skipping those shadowed by
+// earlier PATH entries or by the names in skip
+func onPath(skip []string) []Extension {
+	var exts []Extension
+	seen := slices.Clone(skip)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutPrefix(entry.Name(), prefix)
+			if !ok || !validName(name) || slices.Contains(seen, name) {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			seen = append(seen, name)
+			exts = append(exts, Extension{Name: name, Path: path})
+		}
+	}
+	return exts
+}
+
+// lookupInstalled returns the installed extension called name, which may
+// carry the bb- prefix
+func lookupInstalled(name string) (*Extension, error) {
+	name = strings.TrimPrefix(name, prefix)
+	exts, err := installed()
+	if err != nil {
+		return nil, err
+	}
+	for i := range exts {
+		if exts[i].Name == name {
+			return &exts[i], nil
+		}
+	}
+	if path, ok := Find(name); ok {
+		return nil, fmt.Errorf("extension %s was not installed by bb; it is at %s", name, path)
+	}
+	return nil, fmt.Errorf("no extension %s is installed", name)
+}
+
+// validName reports whether name, without the bb- prefix, can name an
+// extension's command
+func validName(name string) bool {
+	return name != "" && !strings.HasPrefix(name, "-") && !strings.ContainsAny(name, `/\ `)
+}
+
+// isExecutable reports whether path is a file anyone may execute
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0o111 != 0
+}
@@ -0,0 +1,162 @@
+package extension
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// writeExtension writes an executable script named bb-<name> in dir
+func writeExtension(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("extensions are shell scripts in these tests")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, prefix+name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFind(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	pathDir := t.TempDir()
+	t.Setenv("PATH", pathDir)
+
+	onPathExt := writeExtension(t, pathDir, "standup", "exit 0")
+	if path, ok := Find("standup"); !ok || path != onPathExt {
+		t.Errorf("Find() = %q, %v; want the one on PATH", path, ok)
+	}
+
+	dir, err := Dir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	installedExt := writeExtension(t, filepath.Join(dir, "bb-standup"), "standup", "exit 0")
+	if path, ok := Find("standup"); !ok || path != installedExt {
+		t.Errorf("Find() = %q, %v; want the installed one", path, ok)
+	}
+
+	if _, ok := Find("missing"); ok {
+		t.Error("expected no extension to be found")
+	}
+	if _, ok := Find("../standup"); ok {
+		t.Error("expected a name with a path to be refused")
+	}
+}
+
+func TestRun(t *testing.T) {
+	path := writeExtension(t, t.TempDir(), "echo", `echo "$@"; exit 3`)
+
+	var out bytes.Buffer
+	streams := &iostreams.IOStreams{In: strings.NewReader(""), Out: &out, ErrOut: &out}
+	err := Run(path, []string{"a", "b"}, streams)
+	if got := cmdutil.ExitCode(err); got != 3 {
+		t.Errorf("exit code = %d, want 3 (error %v)", got, err)
+	}
+	if out.String() != "a b\n" {
+		t.Errorf("output = %q, want %q", out.String(), "a b\n")
+	}
+}
+
+func TestInstallLocalAndRemove(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv("PATH", "")
+	dir, err := Dir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := filepath.Join(t.TempDir(), "bb-standup")
+	writeExtension(t, src, "standup", "exit 0")
+	name, err := installLocal(dir, src)
+	if err != nil || name != "standup" {
+		t.Fatalf("installLocal() = %q, %v", name, err)
+	}
+	if _, err := installLocal(dir, src); err == nil {
+		t.Error("expected installing twice to fail")
+	}
+
+	exts, err := installed()
+	if err != nil || len(exts) != 1 || !exts[0].Local {
+		t.Fatalf("installed() = %+v, %v; want one local extension", exts, err)
+	}
+	if source, version := describe(exts[0]); source != src || version != "local" {
+		t.Errorf("describe() = %q, %q", source, version)
+	}
+
+	var out bytes.Buffer
+	streams := &iostreams.IOStreams{Out: &out, ErrOut: &out}
+	if err := runRemove(&RemoveOptions{Name: "bb-standup", Streams: streams}); err != nil {
+		t.Fatalf("runRemove() error = %v", err)
+	}
+	if _, ok := Find("standup"); ok {
+		t.Error("expected the extension to be gone")
+	}
+	if _, err := os.Stat(filepath.Join(src, "bb-standup")); err != nil {
+		t.Errorf("expected the linked directory to be kept: %v", err)
+	}
+}
+
+func TestInstallLocal_Invalid(t *testing.T) {
+	Reserved = []string{"pr"}
+	t.Cleanup(func() { Reserved = nil })
+	dir := t.TempDir()
+
+	for _, tt := range []struct {
+		name, dirName, executable string
+	}{
+		{"no bb- prefix", "standup", "standup"},
+		{"no executable", "bb-standup", "other"},
+		{"built-in name", "bb-pr", "pr"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			src := filepath.Join(t.TempDir(), tt.dirName)
+			writeExtension(t, src, tt.executable, "exit 0")
+			if _, err := installLocal(dir, src); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestIsLocalSource(t *testing.T) {
+	for source, want := range map[string]bool{
+		".":                        true,
+		"./bb-standup":             true,
+		"../bb-standup":            true,
+		"/src/bb-standup":          true,
+		"workspace/bb-standup":     false,
+		"git@host:team/bb-standup": false,
+		"https://host/bb-standup":  false,
+	} {
+		if got := isLocalSource(source); got != want {
+			t.Errorf("isLocalSource(%q) = %v, want %v", source, got, want)
+		}
+	}
+}
+
+func TestLookupInstalled_NotInstalledByBB(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	pathDir := t.TempDir()
+	t.Setenv("PATH", pathDir)
+	writeExtension(t, pathDir, "standup", "exit 0")
+
+	_, err := lookupInstalled("standup")
+	if err == nil || !strings.Contains(err.Error(), "not installed by bb") {
+		t.Errorf("lookupInstalled() error = %v", err)
+	}
+	if _, err := lookupInstalled("missing"); err == nil {
+		t.Errorf("lookupInstalled(missing) error = %v", err)
+	}
+}
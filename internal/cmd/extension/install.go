@@ -0,0 +1,218 @@
+package extension
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// InstallOptions holds the options for the install command
+type InstallOptions struct {
+	Source   string
+	Protocol string
+	Streams  *iostreams.IOStreams
+}
+
+// NewCmdInstall creates the extension install command
+func NewCmdInstall(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &InstallOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "install <repository>",
+		Short: "Install an extension",
+		Long: `Install an extension from a repository, given as WORKSPACE/REPO on Bitbucket,
+as a git URL, or as a local directory.
+
+The repository's name must start with bb-, and it must have an executable
+of the same name at its root. A local directory is linked rather than
+copied, so changes to an extension being developed apply right away.`,
+		Example: `  # Install an extension from Bitbucket
+  bb extension install workspace/bb-standup
+
+  # Install from another git host
+  bb extension install https://example.com/team/bb-standup.git
+
+  # Install the extension in the current directory
+  bb extension install .`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Source = args[0]
+			return runInstall(cmd.Context(), opts)
+		},
+	}
+
+	cmdutil.AddProtocolFlag(cmd, &opts.Protocol)
+
+	return cmd
+}
+
+func runInstall(ctx context.Context, opts *InstallOptions) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+
+	if isLocalSource(opts.Source) {
+		src, err := filepath.Abs(opts.Source)
+		if err != nil {
+			return err
+		}
+		name, err := installLocal(dir, src)
+		if err != nil {
+			return err
+		}
+		opts.Streams.Success("Installed extension %s from %s", name, src)
+		return nil
+	}
+
+	cloneURL, name, err := resolveSource(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if err := checkName(name); err != nil {
+		return err
+	}
+	dest, err := destination(dir, name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create extensions directory: %w", err)
+	}
+	if err := git.CloneWithOptions(cloneURL, dest, git.CloneOptions{Stderr: opts.Streams.ErrOut}); err != nil {
+		return err
+	}
+	if !isExecutable(filepath.Join(dest, prefix+name)) {
+		os.RemoveAll(dest)
+		return fmt.Errorf("%s has no executable named %s%s at its root", cloneURL, prefix, name)
+	}
+
+	opts.Streams.Success("Installed extension %s; run it with bb %s", name, name)
+	return nil
+}
+
+// installLocal links the extension in the local directory src into dir and
+// returns its name
+func installLocal(dir, src string) (string, error) {
+	name, ok := strings.CutPrefix(filepath.Base(src), prefix)
+	if !ok {
+		return "", fmt.Errorf("the directory of an extension must be named bb-<name>, not %s", filepath.Base(src))
+	}
+	if err := checkName(name); err != nil {
+		return "", err
+	}
+	if !isExecutable(filepath.Join(src, prefix+name)) {
+		return "", fmt.Errorf("%s has no executable named %s%s", src, prefix, name)
+	}
+	dest, err := destination(dir, name)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create extensions directory: %w", err)
+	}
+	if err := os.Symlink(src, dest); err != nil {
+		return "", fmt.Errorf("failed to link extension: %w", err)
+	}
+	return name, nil
+}
+
+// resolveSource returns the URL to clone an extension from and its name
+func resolveSource(ctx context.Context, opts *InstallOptions) (string, string, error) {
+	if isURL(opts.Source) {
+		repoName := strings.TrimSuffix(path.Base(strings.TrimRight(opts.Source, "/")), ".git")
+		if i := strings.LastIndex(repoName, ":"); i >= 0 {
+			repoName = repoName[i+1:]
+		}
+		name, ok := strings.CutPrefix(repoName, prefix)
+		if !ok {
+			return "", "", fmt.Errorf("the repository of an extension must be named bb-<name>, not %s", repoName)
+		}
+		return opts.Source, name, nil
+	}
+
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.Source)
+	if err != nil {
+		return "", "", err
+	}
+	name, ok := strings.CutPrefix(repoSlug, prefix)
+	if !ok {
+		return "", "", fmt.Errorf("the repository of an extension must be named bb-<name>, not %s", repoSlug)
+	}
+	protocol, err := cmdutil.GitProtocol(opts.Protocol)
+	if err != nil {
+		return "", "", err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return "", "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	repo, err := client.GetRepository(ctx, workspace, repoSlug)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get repository: %w", err)
+	}
+	cloneURL := cmdutil.CloneURL(repo.Links, protocol)
+	if cloneURL == "" {
+		return "", "", fmt.Errorf("no clone URL found for repository")
+	}
+	return cloneURL, name, nil
+}
+
+// checkName refuses names that can't be run as a bb command
+func checkName(name string) error {
+	if !validName(name) {
+		return fmt.Errorf("invalid extension name %q", prefix+name)
+	}
+	if slices.Contains(Reserved, name) {
+		return fmt.Errorf("an extension can't be named %s%s: bb %s is a built-in command", prefix, name, name)
+	}
+	return nil
+}
+
+// destination returns the directory to install the extension name in,
+// which must not exist yet
+func destination(dir, name string) (string, error) {
+	dest := filepath.Join(dir, prefix+name)
+	if _, err := os.Lstat(dest); !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("extension %s is already installed; update it with bb extension upgrade %s", name, name)
+	}
+	return dest, nil
+}
+
+// isLocalSource reports whether source names a local directory rather than
+// a repository
+func isLocalSource(source string) bool {
+	if source == "." || source == ".." || filepath.IsAbs(source) {
+		return true
+	}
+	for _, p := range []string{"./", "../", "." + string(filepath.Separator), ".." + string(filepath.Separator)} {
+		if strings.HasPrefix(source, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// isURL reports whether source is a git URL, including scp-like SSH URLs
+func isURL(source string) bool {
+	return strings.Contains(source, "://") || strings.HasPrefix(source, "git@")
+}
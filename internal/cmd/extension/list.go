@@ -0,0 +1,96 @@
+package extension
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// ListOptions holds the options for the list command
+type ListOptions struct {
+	Streams *iostreams.IOStreams
+}
+
+// NewCmdList creates the extension list command
+func NewCmdList(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &ListOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List extensions",
+		Long: `List the installed extensions, with where they were installed from and the
+commit they are at, and the bb-<name> executables found on PATH.`,
+		Example: `  # List extensions
+  bb extension list`,
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(opts)
+		},
+	}
+
+	return cmd
+}
+
+func runList(opts *ListOptions) error {
+	exts, err := installed()
+	if err != nil {
+		return err
+	}
+	names := make([]string, len(exts))
+	for i, ext := range exts {
+		names[i] = ext.Name
+	}
+	exts = append(exts, onPath(names)...)
+
+	if len(exts) == 0 {
+		opts.Streams.Info("No extensions installed")
+		opts.Streams.Info("Use 'bb extension install <repository>' to install one")
+		return nil
+	}
+
+	table := cmdutil.NewTablePrinter(opts.Streams)
+	table.AddHeader("NAME", "SOURCE", "VERSION")
+	for _, ext := range exts {
+		source, version := describe(ext)
+		table.AddRow("bb "+ext.Name, source, version)
+	}
+	return table.Render()
+}
+
+// describe returns where ext comes from, and the commit it is at if it
+// was cloned
+func describe(ext Extension) (source, version string) {
+	switch {
+	case ext.Dir == "":
+		return ext.Path, "-"
+	case ext.Local:
+		target, err := os.Readlink(ext.Dir)
+		if err != nil {
+			target = ext.Dir
+		}
+		return target, "local"
+	}
+
+	source, err := git.RemoteURLOf(ext.Dir, "origin")
+	if err != nil {
+		source = ext.Dir
+	}
+	version = "-"
+	if hash, err := git.HeadCommit(ext.Dir); err == nil {
+		version = shortHash(hash)
+	}
+	return source, version
+}
+
+// shortHash abbreviates a commit hash as git does by default
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,51 @@
+package extension
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// RemoveOptions holds the options for the remove command
+type RemoveOptions struct {
+	Name    string
+	Streams *iostreams.IOStreams
+}
+
+// NewCmdRemove creates the extension remove command
+func NewCmdRemove(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &RemoveOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove an installed extension",
+		Long: `Remove an installed extension. The directory of an extension installed from
+a local directory is only unlinked, not deleted.`,
+		Example: `  # Remove an extension
+  bb extension remove standup`,
+		Aliases: []string{"rm"},
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Name = args[0]
+			return runRemove(opts)
+		},
+	}
+
+	return cmd
+}
+
+func runRemove(opts *RemoveOptions) error {
+	ext, err := lookupInstalled(opts.Name)
+	if err != nil {
+		return err
+	}
+	// RemoveAll removes a link itself rather than what it points to
+	if err := os.RemoveAll(ext.Dir); err != nil {
+		return fmt.Errorf("failed to remove extension: %w", err)
+	}
+	opts.Streams.Success("Removed extension %s", ext.Name)
+	return nil
+}
@@ -0,0 +1,110 @@
+package extension
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// UpgradeOptions holds the options for the upgrade command
+type UpgradeOptions struct {
+	Name    string
+	All     bool
+	Streams *iostreams.IOStreams
+}
+
+// NewCmdUpgrade creates the extension upgrade command
+func NewCmdUpgrade(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &UpgradeOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "upgrade [<name>]",
+		Short: "Upgrade installed extensions",
+		Long: `Upgrade an installed extension, or all of them with --all, by pulling the
+latest commits of the repository it was installed from.
+
+Extensions installed from a local directory are left alone.`,
+		Example: `  # Upgrade one extension
+  bb extension upgrade standup
+
+  # Upgrade all extensions
+  bb extension upgrade --all`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				opts.Name = args[0]
+			}
+			if (opts.Name == "") == !opts.All {
+				return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("specify an extension to upgrade, or --all"))
+			}
+			return runUpgrade(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.All, "all", false, "Upgrade all installed extensions")
+
+	return cmd
+}
+
+func runUpgrade(opts *UpgradeOptions) error {
+	var exts []Extension
+	if opts.All {
+		var err error
+		if exts, err = installed(); err != nil {
+			return err
+		}
+		if len(exts) == 0 {
+			opts.Streams.Info("No extensions installed")
+			return nil
+		}
+	} else {
+		ext, err := lookupInstalled(opts.Name)
+		if err != nil {
+			return err
+		}
+		exts = []Extension{*ext}
+	}
+
+	failed := 0
+	for _, ext := range exts {
+		if err := upgrade(opts.Streams, ext); err != nil {
+			opts.Streams.Error("failed to upgrade %s: %s", ext.Name, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d extensions failed to upgrade", failed, len(exts))
+	}
+	return nil
+}
+
+// upgrade fast-forwards the clone of ext and reports the change
+func upgrade(streams *iostreams.IOStreams, ext Extension) error {
+	if ext.Local {
+		streams.Info("Skipping %s: it was installed from a local directory", ext.Name)
+		return nil
+	}
+
+	before, err := git.HeadCommit(ext.Dir)
+	if err != nil {
+		return err
+	}
+	if err := git.PullFastForward(ext.Dir); err != nil {
+		return err
+	}
+	after, err := git.HeadCommit(ext.Dir)
+	if err != nil {
+		return err
+	}
+
+	if before == after {
+		streams.Info("%s is already up to date at %s", ext.Name, shortHash(after))
+		return nil
+	}
+	streams.Success("Upgraded %s from %s to %s", ext.Name, shortHash(before), shortHash(after))
+	return nil
+}
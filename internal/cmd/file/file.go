@@ -0,0 +1,31 @@
+package file
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// NewCmdFile creates the file command and its subcommands
+func NewCmdFile(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "file <command>",
+		Short: "Work with the files of a repository",
+		Long: `Read the files of a Bitbucket repository at any branch, tag or commit,
+and commit changes to them, without cloning or checking it out.`,
+		Example: `  # Show a file on the main branch
+  bb file view README.md
+
+  # Show a file as it was at a tag
+  bb file view src/main.go --ref v1.2.0
+
+  # Commit a change to a file
+  bb file push app.yml config/app.yml -m "Turn on debug logging"`,
+		Aliases: []string{"files"},
+	}
+
+	cmd.AddCommand(NewCmdView(streams))
+	cmd.AddCommand(NewCmdPush(streams))
+
+	return cmd
+}
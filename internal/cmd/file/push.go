@@ -0,0 +1,153 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// PushOptions holds the options for the push command
+type PushOptions struct {
+	Local    string
+	RepoPath string
+	Branch   string
+	Message  string
+	Author   string
+	Repo     string
+	Streams  *iostreams.IOStreams
+}
+
+// NewCmdPush creates the file push command
+func NewCmdPush(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &PushOptions{
+		Streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "push <local-file> <repo-path>",
+		Short: "Commit a local file to a repository without git",
+		Long: `Commit the content of a local file to a path in a repository, through
+Bitbucket's API, without cloning it or using git. The file is added if it
+doesn't exist and replaced if it does, in a new commit on the branch.
+
+The commit is made on --branch, or else on the main branch. A branch that
+doesn't exist is created from the main branch. The message defaults to
+"Update <repo-path>" and the author to you; give --author as
+"Name <email>" to commit as someone else, such as a bot.
+
+Use - as the local file to read the content from standard input. A
+repository path ending in / is a directory, to which the local file's name
+is added.`,
+		Example: `  # Update a config file on the main branch
+  bb file push app.yml config/app.yml -m "Turn on debug logging"
+
+  # Commit to another branch, creating it if needed
+  bb file push app.yml config/app.yml --branch config/debug
+
+  # Commit generated content as a bot
+  ./generate-version | bb file push - VERSION -m "Bump version" --author "Release Bot <bot@example.com>"`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Local, opts.RepoPath = args[0], args[1]
+			if strings.HasSuffix(opts.RepoPath, "/") {
+				if opts.Local == "-" {
+					return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("give the file name in the repository path when reading standard input"))
+				}
+				opts.RepoPath += path.Base(opts.Local)
+			}
+			opts.RepoPath = strings.TrimPrefix(opts.RepoPath, "/")
+			if opts.RepoPath == "" {
+				return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("the repository path must not be empty"))
+			}
+			return runPush(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Branch, "branch", "b", "", "Branch to commit to (default: the main branch)")
+	cmd.Flags().StringVarP(&opts.Message, "message", "m", "", `Commit message (default: "Update <repo-path>")`)
+	cmd.Flags().StringVar(&opts.Author, "author", "", `Commit author as "Name <email>" (default: you)`)
+	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format (detects from git remote if not specified)")
+
+	return cmd
+}
+
+func runPush(ctx context.Context, opts *PushOptions) error {
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.Repo)
+	if err != nil {
+		return err
+	}
+
+	content, err := readLocalFile(opts.Streams, opts.Local)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	message := opts.Message
+	if message == "" {
+		message = "Update " + opts.RepoPath
+	}
+
+	progress := opts.Streams.StartProgress("Committing " + opts.RepoPath)
+	hash, err := client.CommitFiles(ctx, workspace, repoSlug, &api.CommitFilesOptions{
+		Message: message,
+		Author:  opts.Author,
+		Branch:  opts.Branch,
+		Files:   map[string][]byte{opts.RepoPath: content},
+	})
+	progress.Stop()
+	if err != nil {
+		return fmt.Errorf("failed to commit %s: %w", opts.RepoPath, err)
+	}
+
+	branch := opts.Branch
+	if branch == "" {
+		branch = "the main branch"
+	}
+	if hash == "" {
+		opts.Streams.Success("Committed %s to %s of %s/%s", opts.RepoPath, branch, workspace, repoSlug)
+		return nil
+	}
+	opts.Streams.Success("Committed %s to %s of %s/%s in %s", opts.RepoPath, branch, workspace, repoSlug, shortHash(hash))
+	return nil
+}
+
+// readLocalFile reads the file at path, or standard input when it is -
+func readLocalFile(streams *iostreams.IOStreams, path string) ([]byte, error) {
+	if path == "-" {
+		content, err := io.ReadAll(streams.In)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read standard input: %w", err)
+		}
+		return content, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return content, nil
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,148 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// binarySniffLen is how much of a file is checked for NUL bytes to tell
+// whether it is binary, as git does
+const binarySniffLen = 8000
+
+// ViewOptions holds the options for the view command
+type ViewOptions struct {
+	Path    string
+	Ref     string
+	Repo    string
+	Plain   bool
+	Streams *iostreams.IOStreams
+}
+
+// NewCmdView creates the file view command
+func NewCmdView(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &ViewOptions{
+		Streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "view <path>",
+		Short: "Show the content of a file in a repository",
+		Long: `Show the content of a file in a repository, fetched from Bitbucket, at
+a branch, tag or commit given with --ref, or else on the main branch.
+
+The file is streamed as it is read, so large files can be shown or piped
+to other commands. On a terminal, it is shown in the pager, with the
+syntax of common languages highlighted unless --plain is given, and
+binary files are refused; redirect the output to save one.`,
+		Example: `  # Show a file on the main branch
+  bb file view README.md
+
+  # Show a file on another branch
+  bb file view src/app.py --ref feature/login
+
+  # Show a file of another repository as it was at a commit
+  bb file view go.mod --ref 4f2c1a9 -R myworkspace/myrepo
+
+  # Save a file without cloning the repository
+  bb file view assets/logo.png > logo.png`,
+		Aliases: []string{"cat"},
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Path = args[0]
+			return runView(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Ref, "ref", "r", "", "Branch, tag or commit to show the file at (default: the main branch)")
+	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format (detects from git remote if not specified)")
+	cmd.Flags().BoolVar(&opts.Plain, "plain", false, "Show the file without syntax highlighting")
+
+	return cmd
+}
+
+func runView(ctx context.Context, opts *ViewOptions) error {
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.Repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	entry, err := findFile(ctx, client, workspace, repoSlug, opts.Ref, opts.Path)
+	if err != nil {
+		return err
+	}
+
+	// Read the file at the commit it was found at, in case the branch moves
+	body, err := client.OpenFile(ctx, workspace, repoSlug, entry.Commit.Hash, entry.Path)
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", opts.Path, err)
+	}
+	defer body.Close()
+
+	r := bufio.NewReaderSize(body, 64*1024)
+	if opts.Streams.IsStdoutTTY() {
+		head, _ := r.Peek(binarySniffLen)
+		if bytes.IndexByte(head, 0) >= 0 {
+			return fmt.Errorf("%s is a binary file; redirect the output to save it", entry.Path)
+		}
+	}
+
+	if err := opts.Streams.StartPager(); err != nil {
+		opts.Streams.Warning("%s", err)
+	}
+	defer opts.Streams.StopPager()
+
+	var style func(string) string
+	if !opts.Plain {
+		style = cmdutil.NewHighlighter(opts.Streams, entry.Path)
+	}
+	if _, err := cmdutil.CopyLines(opts.Streams.Out, r, style); err != nil {
+		return fmt.Errorf("failed to read %s: %w", entry.Path, err)
+	}
+	return nil
+}
+
+// findFile looks up path in a repository at ref, or the main branch when
+// ref is empty, failing when it is a directory
+func findFile(ctx context.Context, client *api.Client, workspace, repoSlug, ref, path string) (*api.SourceEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if ref == "" {
+		repo, err := client.GetRepository(ctx, workspace, repoSlug)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get repository: %w", err)
+		}
+		if repo.MainBranch == nil || repo.MainBranch.Name == "" {
+			return nil, fmt.Errorf("repository %s/%s has no main branch; give the ref with --ref", workspace, repoSlug)
+		}
+		ref = repo.MainBranch.Name
+	}
+
+	entry, err := client.GetSourceEntry(ctx, workspace, repoSlug, ref, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s at %s: %w", path, ref, err)
+	}
+	if entry.Type == "commit_directory" {
+		return nil, fmt.Errorf("%s is a directory at %s; give the path of a file", path, ref)
+	}
+	return entry, nil
+}
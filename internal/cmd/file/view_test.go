@@ -0,0 +1,43 @@
+package file
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+func TestFindFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repositories/team/app":
+			w.Write([]byte(`{"full_name": "team/app", "mainbranch": {"name": "trunk"}}`))
+		case "/repositories/team/app/src/trunk/README.md":
+			w.Write([]byte(`{"type": "commit_file", "path": "README.md", "commit": {"hash": "abc123"}}`))
+		case "/repositories/team/app/src/v1/docs":
+			w.Write([]byte(`{"type": "commit_directory", "path": "docs", "commit": {"hash": "def456"}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := api.NewClient(api.WithBaseURL(server.URL))
+
+	entry, err := findFile(context.Background(), client, "team", "app", "", "README.md")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.Commit.Hash != "abc123" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+
+	_, err = findFile(context.Background(), client, "team", "app", "v1", "docs")
+	if err == nil || !strings.Contains(err.Error(), "is a directory") {
+		t.Errorf("expected a directory error, got %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package filter
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// NewCmdDelete creates the filter delete command
+func NewCmdDelete(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a saved filter",
+		Example: `  # Delete the my-bugs filter
+  bb filter delete my-bugs`,
+		Aliases: []string{"rm"},
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDelete(streams, args[0])
+		},
+	}
+
+	return cmd
+}
+
+func runDelete(streams *iostreams.IOStreams, name string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("could not load config: %w", err)
+	}
+	if !cfg.DeleteFilter(name) {
+		return cmdutil.NewExitError(cmdutil.ExitNotFound, fmt.Errorf("filter %q does not exist", name))
+	}
+
+	if err := config.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("could not save config: %w", err)
+	}
+
+	streams.Success("Deleted filter %s", name)
+	return nil
+}
@@ -0,0 +1,42 @@
+// Package filter implements the bb filter commands, which manage the named
+// queries applied by the --filter flag of issue and pull request lists.
+package filter
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// NewCmdFilter creates the filter command and its subcommands
+func NewCmdFilter(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "filter <command>",
+		Short: "Manage saved filters for issue and pull request lists",
+		Long: `Manage saved filters.
+
+A saved filter is a named query in the Bitbucket query language (BBQL),
+kept under the filters key of the config file. Apply one with the --filter
+flag of 'bb issue list' or 'bb pr list'; other filter flags are combined
+with it using AND.
+
+In a saved filter, "me" or "@me" compared with a username, nickname, uuid
+or account_id field stands for the current user, as in
+assignee.username="me".`,
+		Example: `  # Save a filter for your open bugs
+  bb filter save my-bugs 'kind="bug" AND assignee.username="me"'
+
+  # Use it
+  bb issue list --filter my-bugs
+
+  # List saved filters
+  bb filter list`,
+		Aliases: []string{"filters"},
+	}
+
+	cmd.AddCommand(NewCmdList(streams))
+	cmd.AddCommand(NewCmdSave(streams))
+	cmd.AddCommand(NewCmdDelete(streams))
+
+	return cmd
+}
@@ -0,0 +1,58 @@
+package filter
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+func TestSaveListDelete(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	var buf bytes.Buffer
+	streams := &iostreams.IOStreams{Out: &buf, ErrOut: &buf}
+
+	save := func(name, query string, force bool) error {
+		return runSave(&saveOptions{streams: streams, name: name, query: query, force: force})
+	}
+	if err := save("my-bugs", ` kind="bug" `, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := save("my-bugs", `kind="bug" AND assignee.username="me"`, false); err == nil {
+		t.Error("expected saving over an existing filter without --force to fail")
+	}
+	if err := save("my-bugs", `kind="bug" AND assignee.username="me"`, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := save("bad name", `kind="bug"`, false); err == nil {
+		t.Error("expected an invalid name to be rejected")
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query, _ := cfg.Filter("my-bugs"); query != `kind="bug" AND assignee.username="me"` {
+		t.Errorf("unexpected saved query %q", query)
+	}
+
+	buf.Reset()
+	if err := runList(streams); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "my-bugs") || !strings.Contains(buf.String(), `assignee.username="me"`) {
+		t.Errorf("unexpected list output: %s", buf.String())
+	}
+
+	if err := runDelete(streams, "my-bugs"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var exitErr *cmdutil.ExitError
+	if err := runDelete(streams, "my-bugs"); !errors.As(err, &exitErr) || exitErr.Code != cmdutil.ExitNotFound {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package filter
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// NewCmdList creates the filter list command
+func NewCmdList(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List saved filters",
+		Long:  `List the saved filters and their queries.`,
+		Example: `  # List saved filters
+  bb filter list`,
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(streams)
+		},
+	}
+
+	return cmd
+}
+
+func runList(streams *iostreams.IOStreams) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("could not load config: %w", err)
+	}
+
+	names := cfg.FilterNames()
+	if len(names) == 0 {
+		streams.Info("No saved filters")
+		streams.Info("Use 'bb filter save <name> <query>' to save one")
+		return nil
+	}
+
+	table := cmdutil.NewTablePrinter(streams)
+	table.AddHeader("NAME", "QUERY")
+	for _, name := range names {
+		table.AddRow(name, cfg.Filters[name])
+	}
+	return table.Render()
+}
@@ -0,0 +1,79 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+type saveOptions struct {
+	streams *iostreams.IOStreams
+	name    string
+	query   string
+	force   bool
+}
+
+// NewCmdSave creates the filter save command
+func NewCmdSave(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &saveOptions{streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "save <name> <query>",
+		Short: "Save a filter",
+		Long: `Save a query in the Bitbucket query language (BBQL) as a named filter.
+
+Names may contain letters, digits, dashes and underscores. The query is
+not checked until it is used, as issue and pull request lists accept
+different fields.`,
+		Example: `  # Save a filter for pull requests waiting for your review
+  bb filter save needs-review 'state="OPEN" AND reviewers.uuid="me"'
+
+  # Replace an existing filter
+  bb filter save my-bugs 'kind="bug" AND state="open"' --force`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name, opts.query = args[0], args[1]
+			return runSave(opts)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Replace a filter of the same name")
+
+	return cmd
+}
+
+func runSave(opts *saveOptions) error {
+	if err := config.ValidateFilterName(opts.name); err != nil {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, err)
+	}
+	query := strings.TrimSpace(opts.query)
+	if query == "" {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("the query must not be empty"))
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("could not load config: %w", err)
+	}
+	_, exists := cfg.Filter(opts.name)
+	if exists && !opts.force {
+		return fmt.Errorf("filter %q already exists. Use --force to replace it", opts.name)
+	}
+
+	cfg.SaveFilter(opts.name, query)
+	if err := config.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("could not save config: %w", err)
+	}
+
+	if exists {
+		opts.streams.Success("Replaced filter %s", opts.name)
+	} else {
+		opts.streams.Success("Saved filter %s", opts.name)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package group
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// NewCmdGroup creates the group command and its subcommands
+func NewCmdGroup(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "group <command>",
+		Short: "Manage workspace groups",
+		Long: `List the groups of a workspace and manage who is in them, so access to
+repositories can be managed for a group at a time. Give a group access
+to a repository with 'bb repo access grant <group> --group'.
+
+The workspace is given with --workspace, or else is the default
+workspace or that of the current repository. Managing groups requires
+admin access to the workspace.`,
+		Example: `  # List the groups of a workspace
+  bb group list --workspace myworkspace
+
+  # List the members of a group
+  bb group members developers
+
+  # Add a member to a group
+  bb group add-member developers jsmith`,
+		Aliases: []string{"groups"},
+	}
+
+	cmd.AddCommand(NewCmdList(streams))
+	cmd.AddCommand(NewCmdMembers(streams))
+	cmd.AddCommand(NewCmdAddMember(streams))
+	cmd.AddCommand(NewCmdRemoveMember(streams))
+
+	return cmd
+}
+
+// groupWorkspace returns the workspace whose groups to manage: the given
+// one, the default workspace, or that of the current repository
+func groupWorkspace(workspace string) string {
+	if workspace != "" {
+		return workspace
+	}
+	if workspace, err := config.GetDefaultWorkspace(); err == nil && workspace != "" {
+		return workspace
+	}
+	if workspace, _, err := cmdutil.ParseRepository(""); err == nil {
+		return workspace
+	}
+	return ""
+}
@@ -0,0 +1,168 @@
+package group
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// ListOptions holds the options for the list command
+type ListOptions struct {
+	Workspace string
+	JSON      bool
+	Format    string
+	Streams   *iostreams.IOStreams
+}
+
+// groupRepository is a permission a group has on a repository
+type groupRepository struct {
+	Repository string `json:"repository"`
+	Permission string `json:"permission"`
+}
+
+// NewCmdList creates the group list command
+func NewCmdList(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &ListOptions{
+		Streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the groups of a workspace",
+		Long: `List the groups of a workspace with their number of members, the
+workspace permission they give, and the repositories they have been
+granted access to.`,
+		Example: `  # List the groups of the default workspace
+  bb group list
+
+  # List the groups of another workspace
+  bb group list --workspace myworkspace
+
+  # Output as JSON
+  bb group list --json`,
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Workspace, "workspace", "w", "", "Workspace whose groups to list")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
+
+	return cmd
+}
+
+func runList(ctx context.Context, opts *ListOptions) error {
+	workspace := groupWorkspace(opts.Workspace)
+	if workspace == "" {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("no workspace given; use --workspace"))
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	progress := opts.Streams.StartProgress("Fetching groups")
+	groups, err := client.ListGroups(ctx, workspace)
+	if err != nil {
+		progress.Stop()
+		return fmt.Errorf("failed to list groups: %w", err)
+	}
+	privileges, privilegesErr := client.ListGroupPrivileges(ctx, workspace)
+	progress.Stop()
+	if privilegesErr != nil {
+		opts.Streams.Warning("Could not read the groups' repository permissions: %v", privilegesErr)
+	}
+	repos := groupRepositories(privileges)
+
+	slices.SortFunc(groups, func(a, b api.Group) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	if opts.JSON || opts.Format != "" {
+		output := make([]map[string]interface{}, len(groups))
+		for i, g := range groups {
+			groupRepos := repos[g.Slug]
+			if groupRepos == nil {
+				groupRepos = []groupRepository{}
+			}
+			output[i] = map[string]interface{}{
+				"name":         g.Name,
+				"slug":         g.Slug,
+				"permission":   g.Permission,
+				"auto_add":     g.AutoAdd,
+				"members":      len(g.Members),
+				"repositories": groupRepos,
+			}
+		}
+		return cmdutil.PrintFormatted(opts.Streams, opts.Format, output)
+	}
+
+	if len(groups) == 0 {
+		opts.Streams.Info("No groups found in workspace %s", workspace)
+		return nil
+	}
+
+	table := cmdutil.NewTablePrinter(opts.Streams)
+	table.AddHeader("NAME", "SLUG", "MEMBERS", "PERMISSION", "REPOSITORIES")
+	for _, g := range groups {
+		permission := g.Permission
+		if permission == "" {
+			permission = opts.Streams.Style(iostreams.RoleMuted, "none")
+		}
+		reposSummary := "-"
+		if privilegesErr == nil {
+			reposSummary = summarizeRepositories(repos[g.Slug])
+		}
+		table.AddRow(g.Name, g.Slug, fmt.Sprintf("%d", len(g.Members)), permission, reposSummary)
+	}
+	return table.Render()
+}
+
+// groupRepositories arranges the repository permissions of a workspace's
+// groups by group slug, each sorted by repository
+func groupRepositories(privileges []api.GroupPrivilege) map[string][]groupRepository {
+	repos := map[string][]groupRepository{}
+	for _, p := range privileges {
+		repos[p.Group.Slug] = append(repos[p.Group.Slug], groupRepository{Repository: p.Repo, Permission: p.Privilege})
+	}
+	for _, r := range repos {
+		slices.SortFunc(r, func(a, b groupRepository) int {
+			return strings.Compare(a.Repository, b.Repository)
+		})
+	}
+	return repos
+}
+
+// summarizeRepositories summarizes a group's repository permissions as the
+// number of repositories with each permission, such as "2 admin, 5 write"
+func summarizeRepositories(repos []groupRepository) string {
+	if len(repos) == 0 {
+		return "-"
+	}
+	counts := map[string]int{}
+	for _, r := range repos {
+		counts[r.Permission]++
+	}
+	var parts []string
+	for _, permission := range []string{"admin", "write", "read"} {
+		if counts[permission] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[permission], permission))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,37 @@
+package group
+
+import (
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+func TestGroupRepositories(t *testing.T) {
+	privileges := []api.GroupPrivilege{
+		{Repo: "ws/web", Privilege: "write", Group: api.Group{Slug: "developers"}},
+		{Repo: "ws/api", Privilege: "admin", Group: api.Group{Slug: "developers"}},
+		{Repo: "ws/api", Privilege: "read", Group: api.Group{Slug: "auditors"}},
+		{Repo: "ws/cli", Privilege: "write", Group: api.Group{Slug: "developers"}},
+	}
+
+	repos := groupRepositories(privileges)
+	developers := repos["developers"]
+	if len(developers) != 3 {
+		t.Fatalf("expected 3 repositories for developers, got %d", len(developers))
+	}
+	for i, want := range []string{"ws/api", "ws/cli", "ws/web"} {
+		if developers[i].Repository != want {
+			t.Errorf("repository %d = %q, want %q", i, developers[i].Repository, want)
+		}
+	}
+
+	if got := summarizeRepositories(developers); got != "1 admin, 2 write" {
+		t.Errorf("summarizeRepositories(developers) = %q", got)
+	}
+	if got := summarizeRepositories(repos["auditors"]); got != "1 read" {
+		t.Errorf("summarizeRepositories(auditors) = %q", got)
+	}
+	if got := summarizeRepositories(repos["nobody"]); got != "-" {
+		t.Errorf("summarizeRepositories(nil) = %q", got)
+	}
+}
@@ -0,0 +1,115 @@
+package group
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+type memberOptions struct {
+	streams   *iostreams.IOStreams
+	group     string
+	user      string
+	workspace string
+	remove    bool
+}
+
+// NewCmdAddMember creates the group add-member command
+func NewCmdAddMember(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &memberOptions{
+		streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "add-member <group> <user>",
+		Short: "Add a user to a group",
+		Long: `Add a user to a workspace group, giving them the group's access.
+
+Users are given by UUID, account ID, or the nickname, username or display
+name of a member of the workspace; "me" stands for you.`,
+		Example: `  # Add a member to a group
+  bb group add-member developers jsmith
+
+  # Add a user by account ID
+  bb group add-member contractors 557058:0c6f4ad0-8c8a-4d0e-9a3c-8d1e0b9c7a10 -w myworkspace`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.group, opts.user = args[0], args[1]
+			return runMember(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.workspace, "workspace", "w", "", "Workspace the group belongs to")
+
+	return cmd
+}
+
+// NewCmdRemoveMember creates the group remove-member command
+func NewCmdRemoveMember(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &memberOptions{
+		streams: streams,
+		remove:  true,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "remove-member <group> <user>",
+		Short: "Remove a user from a group",
+		Long: `Remove a user from a workspace group, taking away the access they have
+through it. Access they have through other groups or explicit repository
+grants is unaffected.
+
+Users are given as for 'bb group add-member'.`,
+		Example: `  # Remove a member from a group
+  bb group remove-member developers jsmith`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.group, opts.user = args[0], args[1]
+			return runMember(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.workspace, "workspace", "w", "", "Workspace the group belongs to")
+
+	return cmd
+}
+
+// runMember adds opts.user to opts.group, or removes them when opts.remove
+// is set
+func runMember(ctx context.Context, opts *memberOptions) error {
+	workspace := groupWorkspace(opts.workspace)
+	if workspace == "" {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("no workspace given; use --workspace"))
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	userID, name, err := cmdutil.ResolveWorkspaceUser(ctx, client, workspace, opts.user)
+	if err != nil {
+		return err
+	}
+
+	if opts.remove {
+		if err := client.RemoveGroupMember(ctx, workspace, opts.group, userID); err != nil {
+			return fmt.Errorf("failed to remove %s from group %s: %w", name, opts.group, err)
+		}
+		opts.streams.Success("Removed %s from group %s", name, opts.group)
+		return nil
+	}
+
+	if err := client.AddGroupMember(ctx, workspace, opts.group, userID); err != nil {
+		return fmt.Errorf("failed to add %s to group %s: %w", name, opts.group, err)
+	}
+	opts.streams.Success("Added %s to group %s", name, opts.group)
+	return nil
+}
@@ -0,0 +1,109 @@
+package group
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// MembersOptions holds the options for the members command
+type MembersOptions struct {
+	Group     string
+	Workspace string
+	JSON      bool
+	Format    string
+	Streams   *iostreams.IOStreams
+}
+
+// NewCmdMembers creates the group members command
+func NewCmdMembers(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &MembersOptions{
+		Streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "members <group>",
+		Short: "List the members of a group",
+		Long:  `List the members of a workspace group, given by slug, sorted by name.`,
+		Example: `  # List the members of a group
+  bb group members developers
+
+  # Get the UUIDs of a group's members
+  bb group members developers --json | jq -r '.[].uuid'`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Group = args[0]
+			return runMembers(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Workspace, "workspace", "w", "", "Workspace the group belongs to")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
+
+	return cmd
+}
+
+func runMembers(ctx context.Context, opts *MembersOptions) error {
+	workspace := groupWorkspace(opts.Workspace)
+	if workspace == "" {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("no workspace given; use --workspace"))
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	progress := opts.Streams.StartProgress("Fetching members")
+	members, err := client.ListGroupMembers(ctx, workspace, opts.Group)
+	progress.Stop()
+	if err != nil {
+		return fmt.Errorf("failed to list members of group %s: %w", opts.Group, err)
+	}
+
+	slices.SortFunc(members, func(a, b api.User) int {
+		return strings.Compare(strings.ToLower(cmdutil.GetUserDisplayName(&a)), strings.ToLower(cmdutil.GetUserDisplayName(&b)))
+	})
+
+	if opts.JSON || opts.Format != "" {
+		output := make([]map[string]interface{}, len(members))
+		for i, m := range members {
+			output[i] = map[string]interface{}{
+				"display_name": m.DisplayName,
+				"nickname":     m.Nickname,
+				"username":     m.Username,
+				"uuid":         m.UUID,
+				"account_id":   m.AccountID,
+			}
+		}
+		return cmdutil.PrintFormatted(opts.Streams, opts.Format, output)
+	}
+
+	if len(members) == 0 {
+		opts.Streams.Info("Group %s has no members", opts.Group)
+		return nil
+	}
+
+	table := cmdutil.NewTablePrinter(opts.Streams)
+	table.AddHeader("NAME", "NICKNAME", "UUID")
+	for _, m := range members {
+		nickname := m.Nickname
+		if nickname == "" {
+			nickname = m.Username
+		}
+		table.AddRow(cmdutil.GetUserDisplayName(&m), nickname, m.UUID)
+	}
+	return table.Render()
+}
@@ -0,0 +1,44 @@
+package hooks
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// NewCmdHooks creates the hooks command and its subcommands
+func NewCmdHooks(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hooks <command>",
+		Short: "Manage git hooks that enforce repository rules",
+		Long: `Install git hooks that check pushes and commit messages against the rules
+set under hooks in the repository's .bb.yml, so everyone working on it
+follows them:
+
+  hooks:
+    # Refuse pushes to these branches, by name or pattern
+    protected_branches: [main, release/*]
+    # Warn before pushing to a branch whose latest pipeline failed
+    warn_failed_pipeline: true
+    commit_message:
+      # Require a Jira issue key, optionally of these projects
+      jira_key: true
+      jira_projects: [PROJ]
+      # Require messages to match a regular expression
+      pattern: '^(feat|fix|docs|chore)'
+
+The hooks read .bb.yml each time they run, so changes to the rules apply
+without reinstalling them. Use git's --no-verify to bypass them.`,
+		Example: `  # Install the hooks in the current repository
+  bb hooks install
+
+  # Remove them again
+  bb hooks uninstall`,
+	}
+
+	cmd.AddCommand(NewCmdInstall(streams))
+	cmd.AddCommand(NewCmdUninstall(streams))
+	cmd.AddCommand(NewCmdRun(streams))
+
+	return cmd
+}
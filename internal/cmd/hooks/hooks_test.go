@@ -0,0 +1,167 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+)
+
+func TestInstallAndUninstallHooks(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "hooks")
+
+	if err := installHooks(dir, "/opt/bb's/bb", false); err != nil {
+		t.Fatalf("installHooks() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "pre-push"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := string(data)
+	if !strings.Contains(script, managedMarker) || !strings.Contains(script, `hooks run pre-push "$@"`) {
+		t.Errorf("unexpected pre-push hook:\n%s", script)
+	}
+	if !strings.Contains(script, `bb='/opt/bb'\''s/bb'`) {
+		t.Errorf("expected the binary's path to be quoted:\n%s", script)
+	}
+
+	// Reinstalling replaces bb's own hooks without --force
+	if err := installHooks(dir, "/usr/bin/bb", false); err != nil {
+		t.Fatalf("reinstalling: %v", err)
+	}
+
+	removed, err := uninstallHooks(dir)
+	if err != nil || removed != 2 {
+		t.Fatalf("uninstallHooks() = %d, %v; want 2 removed", removed, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "commit-msg")); !os.IsNotExist(err) {
+		t.Error("expected the commit-msg hook to be removed")
+	}
+}
+
+func TestInstallHooks_ExistingHook(t *testing.T) {
+	dir := t.TempDir()
+	own := filepath.Join(dir, "commit-msg")
+	if err := os.WriteFile(own, []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := installHooks(dir, "/usr/bin/bb", false); err == nil {
+		t.Fatal("expected an error for a hook bb didn't install")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pre-push")); !os.IsNotExist(err) {
+		t.Error("expected no hooks to be written when one conflicts")
+	}
+
+	if err := installHooks(dir, "/usr/bin/bb", true); err != nil {
+		t.Fatalf("installHooks(force) error = %v", err)
+	}
+	if managed, _ := hookState(own); !managed {
+		t.Error("expected the hook to be replaced with --force")
+	}
+	if data, err := os.ReadFile(own + backupSuffix); err != nil || !strings.Contains(string(data), "exit 0") {
+		t.Errorf("expected the replaced hook to be saved, got %q, %v", data, err)
+	}
+
+	if _, err := uninstallHooks(dir); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(own); err != nil || !strings.Contains(string(data), "exit 0") {
+		t.Errorf("expected the replaced hook to be restored, got %q, %v", data, err)
+	}
+}
+
+func TestCheckProtectedBranches(t *testing.T) {
+	updates, err := parsePushUpdates(strings.NewReader(
+		"refs/heads/feature 1111111111111111111111111111111111111111 refs/heads/feature 0000000000000000000000000000000000000000\n" +
+			"refs/tags/v1 2222222222222222222222222222222222222222 refs/tags/v1 0000000000000000000000000000000000000000\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 2 {
+		t.Fatalf("got %d updates, want 2", len(updates))
+	}
+
+	protected := []string{"main", "release/*"}
+	if err := checkProtectedBranches(protected, updates); err != nil {
+		t.Errorf("unexpected error for an unprotected branch: %v", err)
+	}
+
+	for _, branch := range []string{"main", "release/1.0"} {
+		u := []pushUpdate{{localRef: "refs/heads/x", localHash: "1111", remoteRef: "refs/heads/" + branch}}
+		if err := checkProtectedBranches(protected, u); err == nil {
+			t.Errorf("expected a push to %s to be refused", branch)
+		}
+	}
+
+	// Deleting a protected branch is refused too
+	del := []pushUpdate{{localRef: "(delete)", localHash: zeroHash, remoteRef: "refs/heads/main"}}
+	if err := checkProtectedBranches(protected, del); err == nil {
+		t.Error("expected deleting main to be refused")
+	}
+}
+
+func TestCheckCommitMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   config.RepoCommitMessageConfig
+		message string
+		wantErr bool
+	}{
+		{"no rules", config.RepoCommitMessageConfig{}, "anything", false},
+		{"any Jira key", config.RepoCommitMessageConfig{JiraKey: true}, "ABC-12 Fix login", false},
+		{"missing Jira key", config.RepoCommitMessageConfig{JiraKey: true}, "Fix login", true},
+		{"key only in a comment", config.RepoCommitMessageConfig{JiraKey: true}, "Fix login\n# ABC-12", true},
+		{"key of a listed project", config.RepoCommitMessageConfig{JiraProjects: []string{"PROJ", "OPS"}}, "Fix login\n\nFixes OPS-7", false},
+		{"key of another project", config.RepoCommitMessageConfig{JiraProjects: []string{"PROJ"}}, "ABC-12 Fix login", true},
+		{"merge commit", config.RepoCommitMessageConfig{JiraKey: true}, "Merge branch 'main' into feature", false},
+		{"fixup commit", config.RepoCommitMessageConfig{JiraKey: true}, "fixup! Fix login", false},
+		{"pattern matches", config.RepoCommitMessageConfig{Pattern: `^(feat|fix): `}, "fix: login", false},
+		{"pattern doesn't match", config.RepoCommitMessageConfig{Pattern: `^(feat|fix): `}, "Fix login", true},
+		{"diff after scissors", config.RepoCommitMessageConfig{JiraKey: true}, "Fix login\n" + scissors + "\n+PROJ-1", true},
+		{"empty message", config.RepoCommitMessageConfig{JiraKey: true}, "# only comments\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkCommitMessage(tt.rules, tt.message)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkCommitMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLatestCompleted(t *testing.T) {
+	pipeline := func(number int, branch, state, result string) api.Pipeline {
+		p := api.Pipeline{
+			BuildNumber: number,
+			Target:      &api.PipelineTarget{RefName: branch},
+			State:       &api.PipelineState{Name: state},
+		}
+		if result != "" {
+			p.State.Result = &api.PipelineStateResult{Name: result}
+		}
+		return p
+	}
+	pipelines := []api.Pipeline{
+		pipeline(5, "main", "IN_PROGRESS", ""),
+		pipeline(4, "feature", "COMPLETED", "SUCCESSFUL"),
+		pipeline(3, "main", "COMPLETED", "FAILED"),
+		pipeline(2, "main", "COMPLETED", "SUCCESSFUL"),
+	}
+
+	p := latestCompleted(pipelines, "main")
+	if p == nil || p.BuildNumber != 3 || !failed(p) {
+		t.Errorf("latestCompleted(main) = %+v, want the failed #3", p)
+	}
+	if p := latestCompleted(pipelines, "feature"); p == nil || failed(p) {
+		t.Errorf("latestCompleted(feature) = %+v, want the successful #4", p)
+	}
+	if p := latestCompleted(pipelines, "other"); p != nil {
+		t.Errorf("latestCompleted(other) = %+v, want nil", p)
+	}
+}
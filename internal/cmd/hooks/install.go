@@ -0,0 +1,174 @@
+package hooks
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// managedHooks are the git hooks bb installs
+var managedHooks = []string{"pre-push", "commit-msg"}
+
+// managedMarker identifies the hooks bb installed, so they can be replaced
+// and removed without touching hooks of other tools
+const managedMarker = "# Managed by bb"
+
+// backupSuffix is appended to the name of a hook replaced with --force
+const backupSuffix = ".bak"
+
+// InstallOptions holds the options for the install command
+type InstallOptions struct {
+	Force   bool
+	Streams *iostreams.IOStreams
+}
+
+// NewCmdInstall creates the hooks install command
+func NewCmdInstall(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &InstallOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install git hooks in the current repository",
+		Long: `Install pre-push and commit-msg hooks in the current repository that check
+the rules set under hooks in .bb.yml.
+
+Hooks go where git runs them from: .git/hooks, or the directory set by
+core.hooksPath. A hook that bb didn't install is left alone unless --force
+is given, which saves it with a .bak suffix first.`,
+		Example: `  # Install the hooks
+  bb hooks install
+
+  # Replace hooks installed by another tool
+  bb hooks install --force`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInstall(opts)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.Force, "force", "f", false, "Replace existing hooks, saving them with a .bak suffix")
+
+	return cmd
+}
+
+func runInstall(opts *InstallOptions) error {
+	dir, err := git.HooksDir()
+	if err != nil {
+		return err
+	}
+
+	// The hook falls back to bb on PATH should this binary move
+	exe, err := os.Executable()
+	if err != nil {
+		exe = "bb"
+	}
+
+	if err := installHooks(dir, exe, opts.Force); err != nil {
+		return err
+	}
+	opts.Streams.Success("Installed %s hooks in %s", strings.Join(managedHooks, " and "), dir)
+
+	repoConfig, err := config.LoadCurrentRepoConfig()
+	if err != nil {
+		opts.Streams.Warning("%s", err)
+		return nil
+	}
+	if rules := describeRules(repoConfig.Hooks); len(rules) > 0 {
+		opts.Streams.Info("Checking, as set in %s:", config.RepoConfigFileName)
+		for _, rule := range rules {
+			opts.Streams.Info("  - %s", rule)
+		}
+	} else {
+		opts.Streams.Info("No rules are set under hooks in %s yet; see bb hooks --help", config.RepoConfigFileName)
+	}
+	return nil
+}
+
+// installHooks writes the managed hooks to dir, running the bb binary at
+// exe. Hooks bb didn't write are replaced only with force, after saving
+// them.
+func installHooks(dir, exe string, force bool) error {
+	if !force {
+		for _, name := range managedHooks {
+			if managed, exists := hookState(filepath.Join(dir, name)); exists && !managed {
+				return fmt.Errorf("a %s hook not installed by bb already exists in %s; use --force to replace it", name, dir)
+			}
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	for _, name := range managedHooks {
+		path := filepath.Join(dir, name)
+		if managed, exists := hookState(path); exists && !managed {
+			if err := os.Rename(path, path+backupSuffix); err != nil {
+				return fmt.Errorf("failed to save existing %s hook: %w", name, err)
+			}
+		}
+		if err := os.WriteFile(path, []byte(hookScript(name, exe)), 0o755); err != nil {
+			return fmt.Errorf("failed to write %s hook: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// hookState reports whether the hook at path exists and whether bb
+// installed it
+func hookState(path string) (managed, exists bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, !errors.Is(err, fs.ErrNotExist)
+	}
+	return bytes.Contains(data, []byte(managedMarker)), true
+}
+
+// hookScript is the shell script of hook name. It runs the checks with the
+// bb at exe, or else the one on PATH, and lets git go ahead if there is
+// none rather than blocking every commit.
+func hookScript(name, exe string) string {
+	return fmt.Sprintf(`#!/bin/sh
+%s: checks the rules under hooks in .bb.yml.
+# Remove with "bb hooks uninstall".
+bb=%s
+[ -x "$bb" ] || bb=$(command -v bb) || exit 0
+exec "$bb" hooks run %s "$@"
+`, managedMarker, shellQuote(filepath.ToSlash(exe)), name)
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// describeRules lists the rules of cfg in words
+func describeRules(cfg config.RepoHooksConfig) []string {
+	var rules []string
+	if len(cfg.ProtectedBranches) > 0 {
+		rules = append(rules, "pushes to "+strings.Join(cfg.ProtectedBranches, ", ")+" are refused")
+	}
+	if cfg.WarnFailedPipeline {
+		rules = append(rules, "pushes to a branch whose latest pipeline failed are warned about")
+	}
+	msg := cfg.CommitMessage
+	switch {
+	case len(msg.JiraProjects) > 0:
+		rules = append(rules, "commit messages must mention a Jira issue of "+strings.Join(msg.JiraProjects, ", "))
+	case msg.JiraKey:
+		rules = append(rules, "commit messages must mention a Jira issue")
+	}
+	if msg.Pattern != "" {
+		rules = append(rules, "commit messages must match "+msg.Pattern)
+	}
+	return rules
+}
@@ -0,0 +1,260 @@
+package hooks
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// pipelineCheckTimeout bounds the pipeline check, so a slow network
+// doesn't hold up a push for long
+const pipelineCheckTimeout = 10 * time.Second
+
+// zeroHash is the object name git gives for a ref that doesn't exist
+const zeroHash = "0000000000000000000000000000000000000000"
+
+// RunOptions holds the options for the run command
+type RunOptions struct {
+	Hook    string
+	Args    []string
+	Streams *iostreams.IOStreams
+}
+
+// NewCmdRun creates the hooks run command, which the installed hooks call
+func NewCmdRun(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &RunOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:    "run <hook> [<args>...]",
+		Short:  "Run the checks of a git hook",
+		Hidden: true,
+		Args:   cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Hook = args[0]
+			opts.Args = args[1:]
+			return runHook(cmd.Context(), opts)
+		},
+	}
+
+	return cmd
+}
+
+func runHook(ctx context.Context, opts *RunOptions) error {
+	repoConfig, err := config.LoadCurrentRepoConfig()
+	if err != nil {
+		return err
+	}
+	rules := repoConfig.Hooks
+
+	switch opts.Hook {
+	case "pre-push":
+		// git passes the remote's name and URL, and the refs being pushed
+		// on stdin
+		if len(opts.Args) < 2 {
+			return fmt.Errorf("pre-push hook expects the remote name and URL")
+		}
+		updates, err := parsePushUpdates(opts.Streams.In)
+		if err != nil {
+			return err
+		}
+		if err := checkProtectedBranches(rules.ProtectedBranches, updates); err != nil {
+			return err
+		}
+		if rules.WarnFailedPipeline {
+			warnFailedPipelines(ctx, opts.Streams, opts.Args[1], updates)
+		}
+		return nil
+	case "commit-msg":
+		if len(opts.Args) < 1 {
+			return fmt.Errorf("commit-msg hook expects the message file")
+		}
+		data, err := os.ReadFile(opts.Args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read commit message: %w", err)
+		}
+		return checkCommitMessage(rules.CommitMessage, string(data))
+	}
+	return fmt.Errorf("unknown hook %q", opts.Hook)
+}
+
+// pushUpdate is a ref a push updates
+type pushUpdate struct {
+	localRef  string
+	localHash string
+	remoteRef string
+}
+
+// deletes reports whether the push deletes the remote ref
+func (u pushUpdate) deletes() bool {
+	return u.localHash == zeroHash
+}
+
+// parsePushUpdates reads the refs being pushed, which git gives the
+// pre-push hook as lines of "<local ref> <local hash> <remote ref> <remote
+// hash>"
+func parsePushUpdates(r io.Reader) ([]pushUpdate, error) {
+	var updates []pushUpdate
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 {
+			continue
+		}
+		updates = append(updates, pushUpdate{localRef: fields[0], localHash: fields[1], remoteRef: fields[2]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read refs being pushed: %w", err)
+	}
+	return updates, nil
+}
+
+// checkProtectedBranches refuses pushes, including deletions, of branches
+// matching protected
+func checkProtectedBranches(protected []string, updates []pushUpdate) error {
+	for _, u := range updates {
+		branch, ok := strings.CutPrefix(u.remoteRef, "refs/heads/")
+		if !ok {
+			continue
+		}
+		for _, pattern := range protected {
+			if matched, _ := path.Match(pattern, branch); matched {
+				return fmt.Errorf("pushing to %s is not allowed: it is a protected branch in %s\nPush to another branch and open a pull request, or bypass the check with git push --no-verify",
+					branch, config.RepoConfigFileName)
+			}
+		}
+	}
+	return nil
+}
+
+// warnFailedPipelines warns about branches being pushed to whose latest
+// completed pipeline failed. The check is advisory, so it is skipped when
+// the remote isn't on Bitbucket or the pipelines can't be read.
+func warnFailedPipelines(ctx context.Context, streams *iostreams.IOStreams, remoteURL string, updates []pushUpdate) {
+	remote, err := git.ParseBitbucketURL(remoteURL)
+	if err != nil {
+		return
+	}
+	var branches []string
+	for _, u := range updates {
+		if branch, ok := strings.CutPrefix(u.remoteRef, "refs/heads/"); ok && !u.deletes() {
+			branches = append(branches, branch)
+		}
+	}
+	if len(branches) == 0 {
+		return
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, pipelineCheckTimeout)
+	defer cancel()
+
+	result, err := client.ListPipelines(ctx, remote.Workspace, remote.RepoSlug, &api.PipelineListOptions{Sort: "-created_on"})
+	if err != nil {
+		return
+	}
+	for _, branch := range branches {
+		if p := latestCompleted(result.Values, branch); p != nil && failed(p) {
+			streams.Warning("The latest pipeline on %s, #%d, failed; see bb pipeline view %d", branch, p.BuildNumber, p.BuildNumber)
+		}
+	}
+}
+
+// latestCompleted returns the first completed pipeline of branch in
+// pipelines, which are sorted newest first
+func latestCompleted(pipelines []api.Pipeline, branch string) *api.Pipeline {
+	for i, p := range pipelines {
+		if p.Target == nil || p.Target.RefName != branch || p.State == nil || p.State.Name != "COMPLETED" {
+			continue
+		}
+		return &pipelines[i]
+	}
+	return nil
+}
+
+func failed(p *api.Pipeline) bool {
+	if p.State.Result == nil {
+		return false
+	}
+	return p.State.Result.Name == "FAILED" || p.State.Result.Name == "ERROR"
+}
+
+// generatedPrefixes start the messages git writes itself, which the
+// commit message rules don't apply to
+var generatedPrefixes = []string{"Merge ", "Revert ", "fixup! ", "squash! ", "amend! "}
+
+// scissors marks the start of the part of a message git discards, which
+// "git commit --verbose" fills with the diff
+const scissors = "# ------------------------ >8 ------------------------"
+
+// checkCommitMessage checks a commit message, as written by the user, with
+// comments still in it, against rules
+func checkCommitMessage(rules config.RepoCommitMessageConfig, raw string) error {
+	message := cleanMessage(raw)
+	if message == "" {
+		return nil
+	}
+	for _, prefix := range generatedPrefixes {
+		if strings.HasPrefix(message, prefix) {
+			return nil
+		}
+	}
+
+	if rules.JiraKey || len(rules.JiraProjects) > 0 {
+		keyPattern := `[A-Z][A-Z0-9_]+`
+		example := "PROJ-123"
+		if len(rules.JiraProjects) > 0 {
+			quoted := make([]string, len(rules.JiraProjects))
+			for i, project := range rules.JiraProjects {
+				quoted[i] = regexp.QuoteMeta(project)
+			}
+			keyPattern = "(?:" + strings.Join(quoted, "|") + ")"
+			example = rules.JiraProjects[0] + "-123"
+		}
+		if !regexp.MustCompile(`\b` + keyPattern + `-[1-9][0-9]*\b`).MatchString(message) {
+			return fmt.Errorf("commit message must mention a Jira issue, such as %s\nBypass the check with git commit --no-verify", example)
+		}
+	}
+
+	if rules.Pattern != "" {
+		re, err := regexp.Compile(rules.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid hooks.commit_message.pattern in %s: %w", config.RepoConfigFileName, err)
+		}
+		if !re.MatchString(message) {
+			return fmt.Errorf("commit message must match %s, as set in %s\nBypass the check with git commit --no-verify", rules.Pattern, config.RepoConfigFileName)
+		}
+	}
+	return nil
+}
+
+// cleanMessage removes the comment lines, and anything after the scissors
+// line, that git strips from a message
+func cleanMessage(raw string) string {
+	if i := strings.Index(raw, scissors); i >= 0 {
+		raw = raw[:i]
+	}
+	var lines []string
+	for line := range strings.SplitSeq(raw, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
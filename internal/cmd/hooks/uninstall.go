@@ -0,0 +1,79 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// UninstallOptions holds the options for the uninstall command
+type UninstallOptions struct {
+	Streams *iostreams.IOStreams
+}
+
+// NewCmdUninstall creates the hooks uninstall command
+func NewCmdUninstall(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &UninstallOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "uninstall",
+		Short: "Remove the git hooks installed by bb",
+		Long: `Remove the hooks "bb hooks install" added to the current repository.
+
+Hooks that bb didn't install are left alone, and hooks that --force
+replaced are put back.`,
+		Example: `  # Remove the hooks
+  bb hooks uninstall`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUninstall(opts)
+		},
+	}
+
+	return cmd
+}
+
+func runUninstall(opts *UninstallOptions) error {
+	dir, err := git.HooksDir()
+	if err != nil {
+		return err
+	}
+
+	removed, err := uninstallHooks(dir)
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		opts.Streams.Info("No hooks installed by bb in %s", dir)
+		return nil
+	}
+	opts.Streams.Success("Removed %d hooks from %s", removed, dir)
+	return nil
+}
+
+// uninstallHooks removes the managed hooks in dir, restoring those they
+// replaced, and returns how many were removed
+func uninstallHooks(dir string) (int, error) {
+	removed := 0
+	for _, name := range managedHooks {
+		path := filepath.Join(dir, name)
+		if managed, _ := hookState(path); !managed {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return removed, fmt.Errorf("failed to remove %s hook: %w", name, err)
+		}
+		removed++
+		if _, err := os.Stat(path + backupSuffix); err == nil {
+			if err := os.Rename(path+backupSuffix, path); err != nil {
+				return removed, fmt.Errorf("failed to restore %s hook: %w", name, err)
+			}
+		}
+	}
+	return removed, nil
+}
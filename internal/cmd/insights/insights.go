@@ -0,0 +1,74 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// NewCmdInsights creates the insights command and its subcommands
+func NewCmdInsights(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "insights <command>",
+		Short: "Publish and view Code Insights reports",
+		Long: `Publish Code Insights reports on commits.
+
+Reports show the results of tools run outside Bitbucket Pipelines, such
+as security scanners, on the pull requests containing a commit, with
+annotations on the lines of the diff they are about. 'bb insights view'
+shows a report with its annotations.`,
+		Example: `  # Annotate the current commit with a scanner's findings
+  bb insights upload --sarif results.sarif
+
+  # Report the results of a test run on the current commit
+  bb insights test-report --junit "reports/*.xml"
+
+  # Show a report of a commit with its annotations
+  bb insights view 1a2b3c4 bb-junit`,
+	}
+
+	cmd.AddCommand(NewCmdUpload(streams))
+	cmd.AddCommand(NewCmdTestReport(streams))
+	cmd.AddCommand(NewCmdView(streams))
+
+	return cmd
+}
+
+var commitHashRE = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// resolveCommit returns the full hash of rev, which is used as given if it
+// is one and otherwise looked up in the local repository
+func resolveCommit(rev string) (string, error) {
+	if commitHashRE.MatchString(rev) {
+		return rev, nil
+	}
+	return git.RevParse(rev)
+}
+
+// publishReport creates the report reportID on a commit with annotations,
+// replacing any earlier report with that ID
+func publishReport(ctx context.Context, client *api.Client, workspace, repoSlug, commit, reportID string, report *api.Report, annotations []api.Annotation) error {
+	// Replacing a report keeps its annotations, so findings fixed since the
+	// last upload would linger
+	if err := client.DeleteReport(ctx, workspace, repoSlug, commit, reportID); err != nil {
+		return fmt.Errorf("failed to remove previous report: %w", err)
+	}
+	if _, err := client.CreateReport(ctx, workspace, repoSlug, commit, reportID, report); err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	if err := client.CreateAnnotations(ctx, workspace, repoSlug, commit, reportID, annotations); err != nil {
+		return fmt.Errorf("failed to add annotations: %w", err)
+	}
+	return nil
+}
+
+// shortCommit abbreviates a commit hash for messages
+func shortCommit(commit string) string {
+	return commit[:min(len(commit), 12)]
+}
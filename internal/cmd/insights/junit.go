@@ -0,0 +1,211 @@
+package insights
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+// junitSuite is a <testsuite> element, or the <testsuites> element some
+// tools wrap them in. Suites may be nested.
+type junitSuite struct {
+	Name   string       `xml:"name,attr"`
+	Suites []junitSuite `xml:"testsuite"`
+	Cases  []junitCase  `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string         `xml:"name,attr"`
+	Classname string         `xml:"classname,attr"`
+	Time      string         `xml:"time,attr"`
+	File      string         `xml:"file,attr"`
+	Line      string         `xml:"line,attr"`
+	Failures  []junitProblem `xml:"failure"`
+	Errors    []junitProblem `xml:"error"`
+	Skipped   *junitProblem  `xml:"skipped"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// testFailure is a test that failed or errored
+type testFailure struct {
+	name    string
+	file    string
+	line    int
+	message string
+	output  string
+}
+
+// testResults sums up the test cases of JUnit reports
+type testResults struct {
+	passed   int
+	failed   int
+	skipped  int
+	duration time.Duration
+	failures []testFailure
+}
+
+// total is the number of tests run or skipped
+func (r *testResults) total() int {
+	return r.passed + r.failed + r.skipped
+}
+
+// junitFiles expands the glob patterns in patterns to the files they
+// match. Patterns are expanded here as well as by the shell so they can be
+// quoted, and work the same on every platform.
+func junitFiles(patterns []string) ([]string, error) {
+	var files []string
+	for _, pattern := range patterns {
+		if !strings.ContainsAny(pattern, "*?[") {
+			files = append(files, pattern)
+			continue
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %s", pattern)
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// addFile adds the test cases of the JUnit report at path
+func (r *testResults) addFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var root junitSuite
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return fmt.Errorf("failed to parse %s as JUnit XML: %w", path, err)
+	}
+	r.addSuite(root)
+	return nil
+}
+
+func (r *testResults) addSuite(suite junitSuite) {
+	for _, s := range suite.Suites {
+		r.addSuite(s)
+	}
+	for _, c := range suite.Cases {
+		if seconds, err := strconv.ParseFloat(strings.ReplaceAll(c.Time, ",", ""), 64); err == nil {
+			r.duration += time.Duration(seconds * float64(time.Second))
+		}
+
+		problems := slices.Concat(c.Failures, c.Errors)
+		switch {
+		case len(problems) > 0:
+			r.failed++
+			line, _ := strconv.Atoi(c.Line)
+			r.failures = append(r.failures, testFailure{
+				name:    testName(suite.Name, c),
+				file:    c.File,
+				line:    line,
+				message: strings.TrimSpace(problems[0].Message),
+				output:  strings.TrimSpace(problems[0].Text),
+			})
+		case c.Skipped != nil:
+			r.skipped++
+		default:
+			r.passed++
+		}
+	}
+}
+
+// testName names a test case by its class, or else its suite, and its name
+func testName(suite string, c junitCase) string {
+	prefix := c.Classname
+	if prefix == "" {
+		prefix = suite
+	}
+	if prefix == "" || strings.HasPrefix(c.Name, prefix) {
+		return c.Name
+	}
+	return prefix + "." + c.Name
+}
+
+// summary describes the results in a sentence, e.g. "12 tests: 11 passed,
+// 1 failed"
+func (r *testResults) summary() string {
+	parts := []string{fmt.Sprintf("%d passed", r.passed)}
+	if r.failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", r.failed))
+	}
+	if r.skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", r.skipped))
+	}
+	noun := "tests"
+	if r.total() == 1 {
+		noun = "test"
+	}
+	return fmt.Sprintf("%d %s: %s", r.total(), noun, strings.Join(parts, ", "))
+}
+
+// report builds the Code Insights report of the results
+func (r *testResults) report(title string) *api.Report {
+	result := "PASSED"
+	if r.failed > 0 {
+		result = "FAILED"
+	}
+	return &api.Report{
+		Title:      title,
+		Details:    r.summary(),
+		ReportType: "TEST",
+		Reporter:   "bb",
+		Result:     result,
+		Data: []api.ReportData{
+			{Title: "Passed", Type: "NUMBER", Value: r.passed},
+			{Title: "Failed", Type: "NUMBER", Value: r.failed},
+			{Title: "Skipped", Type: "NUMBER", Value: r.skipped},
+			{Title: "Duration", Type: "DURATION", Value: r.duration.Milliseconds()},
+		},
+	}
+}
+
+// annotations returns an annotation for each failed test, on its file and
+// line when the report gives them. Paths are made relative to root.
+func (r *testResults) annotations(root string) []api.Annotation {
+	seen := make(map[string]bool, len(r.failures))
+	var annotations []api.Annotation
+	for _, f := range r.failures {
+		summary := f.name + " failed"
+		if f.message != "" {
+			summary += ": " + f.message
+		}
+		a := api.Annotation{
+			AnnotationType: "BUG",
+			Summary:        truncate(summary, maxSummary),
+			Details:        truncate(f.output, maxDetails),
+			Severity:       "HIGH",
+			Result:         "FAILED",
+		}
+		if f.file != "" {
+			a.Path = repoPath(f.file, "", root)
+			a.Line = f.line
+		}
+
+		sum := sha256.Sum256([]byte(f.name + "\x00" + a.Path))
+		a.ExternalID = hex.EncodeToString(sum[:])[:32]
+		if seen[a.ExternalID] {
+			continue
+		}
+		seen[a.ExternalID] = true
+		annotations = append(annotations, a)
+	}
+	return annotations
+}
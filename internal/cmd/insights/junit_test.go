@@ -0,0 +1,100 @@
+package insights
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testJUnit = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="auth" tests="3">
+    <testcase classname="auth.LoginTest" name="testValid" time="0.5"/>
+    <testcase classname="auth.LoginTest" name="testExpired" time="1.25" file="/repo/src/auth/login_test.go" line="42">
+      <failure message="expected 401, got 200" type="AssertionError">login_test.go:42: expected 401</failure>
+    </testcase>
+    <testcase classname="auth.LoginTest" name="testSlow">
+      <skipped message="too slow"/>
+    </testcase>
+  </testsuite>
+  <testsuite name="db">
+    <testsuite name="db/migrate">
+      <testcase name="TestMigrate" time="0.25">
+        <error message="connection refused"/>
+      </testcase>
+    </testsuite>
+  </testsuite>
+</testsuites>`
+
+func TestTestResults(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.xml"), []byte(testJUnit), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	single := `<testsuite name="util"><testcase classname="util" name="TestTrim" time="1"/></testsuite>`
+	if err := os.WriteFile(filepath.Join(dir, "b.xml"), []byte(single), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := junitFiles([]string{filepath.Join(dir, "*.xml")})
+	if err != nil {
+		t.Fatalf("junitFiles() error = %v", err)
+	}
+	var results testResults
+	for _, file := range files {
+		if err := results.addFile(file); err != nil {
+			t.Fatalf("addFile(%s) error = %v", file, err)
+		}
+	}
+
+	if results.passed != 2 || results.failed != 2 || results.skipped != 1 {
+		t.Errorf("passed, failed, skipped = %d, %d, %d, want 2, 2, 1", results.passed, results.failed, results.skipped)
+	}
+	if results.duration != 3*time.Second {
+		t.Errorf("duration = %s, want 3s", results.duration)
+	}
+	if got := results.summary(); got != "5 tests: 2 passed, 2 failed, 1 skipped" {
+		t.Errorf("summary() = %q", got)
+	}
+
+	report := results.report("Tests")
+	if report.Result != "FAILED" || report.ReportType != "TEST" {
+		t.Errorf("report = %+v, want a failed TEST report", report)
+	}
+
+	annotations := results.annotations("/repo")
+	if len(annotations) != 2 {
+		t.Fatalf("got %d annotations, want 2", len(annotations))
+	}
+	login := annotations[0]
+	if login.Summary != "auth.LoginTest.testExpired failed: expected 401, got 200" {
+		t.Errorf("summary = %q", login.Summary)
+	}
+	if login.Path != "src/auth/login_test.go" || login.Line != 42 {
+		t.Errorf("location = %s:%d, want src/auth/login_test.go:42", login.Path, login.Line)
+	}
+	if login.Details != "login_test.go:42: expected 401" {
+		t.Errorf("details = %q", login.Details)
+	}
+	migrate := annotations[1]
+	if migrate.Summary != "db/migrate.TestMigrate failed: connection refused" || migrate.Path != "" {
+		t.Errorf("annotation = %+v", migrate)
+	}
+}
+
+func TestJUnitFiles_NoMatch(t *testing.T) {
+	if _, err := junitFiles([]string{filepath.Join(t.TempDir(), "*.xml")}); err == nil {
+		t.Error("junitFiles() succeeded for a pattern matching nothing")
+	}
+}
+
+func TestTestResults_Passed(t *testing.T) {
+	results := testResults{passed: 1}
+	if got := results.summary(); got != "1 test: 1 passed" {
+		t.Errorf("summary() = %q", got)
+	}
+	if report := results.report("Tests"); report.Result != "PASSED" {
+		t.Errorf("result = %s, want PASSED", report.Result)
+	}
+}
@@ -0,0 +1,325 @@
+package insights
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+// Limits Bitbucket puts on the text of an annotation
+const (
+	maxSummary = 450
+	maxDetails = 2000
+)
+
+// severities are the severities of annotations, most severe first
+var severities = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"}
+
+// sarifLog is the part of a SARIF 2.1.0 log the uploader reads
+type sarifLog struct {
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Name  string      `json:"name"`
+			Rules []sarifRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	OriginalURIBaseIDs map[string]struct {
+		URI string `json:"uri"`
+	} `json:"originalUriBaseIds"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	FullDescription  sarifMessage `json:"fullDescription"`
+	HelpURI          string       `json:"helpUri"`
+	Default          struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+	Properties sarifProperties `json:"properties"`
+}
+
+type sarifProperties struct {
+	SecuritySeverity string   `json:"security-severity"`
+	Tags             []string `json:"tags"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string       `json:"ruleId"`
+	RuleIndex *int         `json:"ruleIndex"`
+	Level     string       `json:"level"`
+	Kind      string       `json:"kind"`
+	Message   sarifMessage `json:"message"`
+	Locations []struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct {
+				URI       string `json:"uri"`
+				URIBaseID string `json:"uriBaseId"`
+			} `json:"artifactLocation"`
+			Region struct {
+				StartLine int `json:"startLine"`
+			} `json:"region"`
+		} `json:"physicalLocation"`
+	} `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	BaselineState       string            `json:"baselineState"`
+	Suppressions        []json.RawMessage `json:"suppressions"`
+	Properties          sarifProperties   `json:"properties"`
+}
+
+// readSARIF reads a SARIF log from path
+func readSARIF(path string) (*sarifLog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		return nil, fmt.Errorf("failed to parse %s as SARIF: %w", path, err)
+	}
+	if len(log.Runs) == 0 && log.Version == "" {
+		return nil, fmt.Errorf("%s is not a SARIF log", path)
+	}
+	return &log, nil
+}
+
+// findings are the annotations converted from SARIF logs, with the tools
+// that reported them
+type findings struct {
+	tools       []string
+	annotations []api.Annotation
+}
+
+// add converts the results of log to annotations. Paths are made relative
+// to root, the repository's directory, so they match the files of the
+// commit.
+func (f *findings) add(log *sarifLog, root string) {
+	seen := make(map[string]bool, len(f.annotations))
+	for _, a := range f.annotations {
+		seen[a.ExternalID] = true
+	}
+
+	for _, run := range log.Runs {
+		tool := run.Tool.Driver.Name
+		if tool != "" && !slices.Contains(f.tools, tool) {
+			f.tools = append(f.tools, tool)
+		}
+		rules := make(map[string]*sarifRule, len(run.Tool.Driver.Rules))
+		for i := range run.Tool.Driver.Rules {
+			rules[run.Tool.Driver.Rules[i].ID] = &run.Tool.Driver.Rules[i]
+		}
+
+		for _, result := range run.Results {
+			if !reported(result) {
+				continue
+			}
+			rule := rules[result.RuleID]
+			if rule == nil && result.RuleIndex != nil && *result.RuleIndex >= 0 && *result.RuleIndex < len(run.Tool.Driver.Rules) {
+				rule = &run.Tool.Driver.Rules[*result.RuleIndex]
+			}
+			a := annotate(result, rule)
+			if len(result.Locations) > 0 {
+				loc := result.Locations[0].PhysicalLocation
+				base := ""
+				if b, ok := run.OriginalURIBaseIDs[loc.ArtifactLocation.URIBaseID]; ok {
+					base = b.URI
+				}
+				a.Path = repoPath(loc.ArtifactLocation.URI, base, root)
+				a.Line = loc.Region.StartLine
+			}
+			a.ExternalID = externalID(tool, result, a)
+			if seen[a.ExternalID] {
+				continue
+			}
+			seen[a.ExternalID] = true
+			f.annotations = append(f.annotations, a)
+		}
+	}
+}
+
+// reported reports whether a result is a problem to annotate, rather than
+// a passing check, a suppressed finding or one fixed since the baseline
+func reported(result sarifResult) bool {
+	if result.Kind != "" && result.Kind != "fail" {
+		return false
+	}
+	if len(result.Suppressions) > 0 || result.BaselineState == "absent" {
+		return false
+	}
+	return result.Level != "none"
+}
+
+// annotate converts a result, and the rule it violates if known, to an
+// annotation without a location
+func annotate(result sarifResult, rule *sarifRule) api.Annotation {
+	level := result.Level
+	securitySeverity := result.Properties.SecuritySeverity
+	var tags []string
+	a := api.Annotation{Result: "FAILED"}
+	if rule != nil {
+		if level == "" {
+			level = rule.Default.Level
+		}
+		if securitySeverity == "" {
+			securitySeverity = rule.Properties.SecuritySeverity
+		}
+		tags = rule.Properties.Tags
+		a.Details = firstNonEmpty(rule.FullDescription.Text, rule.ShortDescription.Text)
+		a.Link = rule.HelpURI
+	}
+	if level == "" {
+		level = "warning"
+	}
+
+	summary := result.Message.Text
+	if result.RuleID != "" {
+		summary = result.RuleID + ": " + summary
+	}
+	a.Summary = truncate(summary, maxSummary)
+	a.Details = truncate(a.Details, maxDetails)
+
+	a.Severity = levelSeverity(level)
+	if score, err := strconv.ParseFloat(securitySeverity, 64); err == nil {
+		a.Severity = scoreSeverity(score)
+	}
+
+	switch {
+	case securitySeverity != "" || slices.Contains(tags, "security"):
+		a.AnnotationType = "VULNERABILITY"
+	case level == "error":
+		a.AnnotationType = "BUG"
+	default:
+		a.AnnotationType = "CODE_SMELL"
+	}
+	return a
+}
+
+// levelSeverity maps a SARIF level to an annotation severity
+func levelSeverity(level string) string {
+	switch level {
+	case "error":
+		return "HIGH"
+	case "warning":
+		return "MEDIUM"
+	}
+	return "LOW"
+}
+
+// scoreSeverity maps a CVSS score, which scanners give as a rule's
+// security-severity, to an annotation severity
+func scoreSeverity(score float64) string {
+	switch {
+	case score >= 9:
+		return "CRITICAL"
+	case score >= 7:
+		return "HIGH"
+	case score >= 4:
+		return "MEDIUM"
+	}
+	return "LOW"
+}
+
+// repoPath returns the path of an artifact relative to the repository at
+// root. uri may be relative to base, one of the run's originalUriBaseIds.
+func repoPath(uri, base, root string) string {
+	if base != "" && !strings.Contains(uri, "://") && !strings.HasPrefix(uri, "/") {
+		uri = strings.TrimSuffix(base, "/") + "/" + uri
+	}
+	if u, err := url.Parse(uri); err == nil && u.Scheme == "file" {
+		uri = u.Path
+	} else if unescaped, err := url.PathUnescape(uri); err == nil {
+		uri = unescaped
+	}
+
+	if filepath.IsAbs(filepath.FromSlash(uri)) && root != "" {
+		if rel, err := filepath.Rel(root, filepath.FromSlash(uri)); err == nil && !strings.HasPrefix(rel, "..") {
+			uri = filepath.ToSlash(rel)
+		}
+	}
+	return strings.TrimPrefix(path.Clean("/"+uri), "/")
+}
+
+// externalID identifies an annotation within its report, using the
+// result's fingerprint when the tool gives one so the ID is stable across
+// runs
+func externalID(tool string, result sarifResult, a api.Annotation) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00", tool, result.RuleID, a.Path)
+	if len(result.PartialFingerprints) > 0 {
+		keys := make([]string, 0, len(result.PartialFingerprints))
+		for k := range result.PartialFingerprints {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			fmt.Fprintf(h, "%s=%s\x00", k, result.PartialFingerprints[k])
+		}
+	} else {
+		fmt.Fprintf(h, "%d\x00%s", a.Line, result.Message.Text)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// mostSevere orders annotations by severity, most severe first, and keeps
+// the first limit of them
+func mostSevere(annotations []api.Annotation, limit int) []api.Annotation {
+	slices.SortStableFunc(annotations, func(a, b api.Annotation) int {
+		return slices.Index(severities, a.Severity) - slices.Index(severities, b.Severity)
+	})
+	if len(annotations) > limit {
+		annotations = annotations[:limit]
+	}
+	return annotations
+}
+
+// countSeverities counts annotations by severity
+func countSeverities(annotations []api.Annotation) map[string]int {
+	counts := make(map[string]int, len(severities))
+	for _, a := range annotations {
+		counts[a.Severity]++
+	}
+	return counts
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// truncate shortens s to at most limit bytes, on a rune boundary
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	cut := limit - len("...")
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
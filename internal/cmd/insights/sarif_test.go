@@ -0,0 +1,162 @@
+package insights
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+const testSARIF = `{
+  "version": "2.1.0",
+  "runs": [{
+    "tool": {"driver": {"name": "Semgrep", "rules": [
+      {"id": "sql-injection", "fullDescription": {"text": "Untrusted input in a query"},
+       "helpUri": "https://example.com/sqli", "properties": {"security-severity": "9.1"}},
+      {"id": "unused-var", "defaultConfiguration": {"level": "note"}}
+    ]}},
+    "originalUriBaseIds": {"SRCROOT": {"uri": "file:///work/repo/"}},
+    "results": [
+      {"ruleId": "sql-injection", "level": "error", "message": {"text": "Query built from request"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "app/db.go", "uriBaseId": "SRCROOT"}, "region": {"startLine": 42}}}]},
+      {"ruleId": "unused-var", "message": {"text": "x is unused"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "file:///work/repo/app/main.go"}, "region": {"startLine": 7}}}]},
+      {"ruleId": "unused-var", "message": {"text": "y is unused"}, "suppressions": [{"kind": "inSource"}],
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "app/main.go"}, "region": {"startLine": 8}}}]},
+      {"ruleId": "unused-var", "kind": "pass", "message": {"text": "checked"}}
+    ]
+  }]
+}`
+
+func readTestSARIF(t *testing.T) *sarifLog {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "results.sarif")
+	if err := os.WriteFile(path, []byte(testSARIF), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	log, err := readSARIF(path)
+	if err != nil {
+		t.Fatalf("readSARIF() error = %v", err)
+	}
+	return log
+}
+
+func TestFindingsAdd(t *testing.T) {
+	var f findings
+	f.add(readTestSARIF(t), "/work/repo")
+
+	if len(f.tools) != 1 || f.tools[0] != "Semgrep" {
+		t.Errorf("tools = %v, want [Semgrep]", f.tools)
+	}
+	if len(f.annotations) != 2 {
+		t.Fatalf("got %d annotations, want 2: %+v", len(f.annotations), f.annotations)
+	}
+
+	sqli := f.annotations[0]
+	want := api.Annotation{
+		ExternalID:     sqli.ExternalID,
+		AnnotationType: "VULNERABILITY",
+		Summary:        "sql-injection: Query built from request",
+		Details:        "Untrusted input in a query",
+		Path:           "app/db.go",
+		Line:           42,
+		Severity:       "CRITICAL",
+		Result:         "FAILED",
+		Link:           "https://example.com/sqli",
+	}
+	if sqli != want {
+		t.Errorf("annotation = %+v, want %+v", sqli, want)
+	}
+
+	unused := f.annotations[1]
+	if unused.Path != "app/main.go" || unused.Line != 7 {
+		t.Errorf("location = %s:%d, want app/main.go:7", unused.Path, unused.Line)
+	}
+	if unused.Severity != "LOW" || unused.AnnotationType != "CODE_SMELL" {
+		t.Errorf("severity = %s, type = %s, want LOW CODE_SMELL", unused.Severity, unused.AnnotationType)
+	}
+	if sqli.ExternalID == "" || sqli.ExternalID == unused.ExternalID {
+		t.Errorf("external IDs %q and %q should be distinct", sqli.ExternalID, unused.ExternalID)
+	}
+
+	// Uploading the same log twice doesn't duplicate annotations
+	f.add(readTestSARIF(t), "/work/repo")
+	if len(f.annotations) != 2 {
+		t.Errorf("got %d annotations after adding the log again, want 2", len(f.annotations))
+	}
+}
+
+func TestReadSARIF_NotSARIF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := os.WriteFile(path, []byte(`{"tests": 3}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readSARIF(path); err == nil {
+		t.Error("readSARIF() succeeded on a file that isn't SARIF")
+	}
+}
+
+func TestRepoPath(t *testing.T) {
+	tests := []struct {
+		uri, base, root, want string
+	}{
+		{"src/main.go", "", "/repo", "src/main.go"},
+		{"./src/main.go", "", "/repo", "src/main.go"},
+		{"file:///repo/src/main.go", "", "/repo", "src/main.go"},
+		{"/repo/src/my%20file.go", "", "/repo", "src/my file.go"},
+		{"main.go", "file:///repo/src/", "/repo", "src/main.go"},
+	}
+	for _, tt := range tests {
+		if got := repoPath(tt.uri, tt.base, tt.root); got != tt.want {
+			t.Errorf("repoPath(%q, %q, %q) = %q, want %q", tt.uri, tt.base, tt.root, got, tt.want)
+		}
+	}
+}
+
+func TestMostSevere(t *testing.T) {
+	annotations := []api.Annotation{
+		{ExternalID: "a", Severity: "LOW"},
+		{ExternalID: "b", Severity: "CRITICAL"},
+		{ExternalID: "c", Severity: "MEDIUM"},
+		{ExternalID: "d", Severity: "HIGH"},
+	}
+	got := mostSevere(annotations, 2)
+	if len(got) != 2 || got[0].ExternalID != "b" || got[1].ExternalID != "d" {
+		t.Errorf("mostSevere() = %+v, want b and d", got)
+	}
+}
+
+func TestSARIFReport(t *testing.T) {
+	annotations := []api.Annotation{
+		{Severity: "MEDIUM", AnnotationType: "CODE_SMELL"},
+		{Severity: "LOW", AnnotationType: "CODE_SMELL"},
+	}
+
+	report := sarifReport(annotations, []string{"golangci-lint"}, "HIGH")
+	if report.Result != "PASSED" || report.ReportType != "BUG" || report.Details != "2 findings" {
+		t.Errorf("report = %+v, want a passed BUG report of 2 findings", report)
+	}
+	if report := sarifReport(annotations, []string{"golangci-lint"}, "MEDIUM"); report.Result != "FAILED" {
+		t.Errorf("result = %s with --fail-on medium, want FAILED", report.Result)
+	}
+	if report := sarifReport(annotations, []string{"golangci-lint"}, "NONE"); report.Result != "PASSED" {
+		t.Errorf("result = %s with --fail-on none, want PASSED", report.Result)
+	}
+
+	data, _ := json.Marshal(report.Data)
+	want := `[{"title":"Findings","type":"NUMBER","value":2},{"title":"Critical","type":"NUMBER","value":0},{"title":"High","type":"NUMBER","value":0},{"title":"Medium","type":"NUMBER","value":1},{"title":"Low","type":"NUMBER","value":1}]`
+	if string(data) != want {
+		t.Errorf("data = %s, want %s", data, want)
+	}
+}
+
+func TestSARIFReportID(t *testing.T) {
+	if got := sarifReportID([]string{"Semgrep OSS", "Trivy"}); got != "bb-sarif-semgrep-oss-trivy" {
+		t.Errorf("sarifReportID() = %q", got)
+	}
+	if got := sarifReportID(nil); got != "bb-sarif" {
+		t.Errorf("sarifReportID(nil) = %q, want bb-sarif", got)
+	}
+}
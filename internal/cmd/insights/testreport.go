@@ -0,0 +1,141 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// TestReportOptions holds the options for the test-report command
+type TestReportOptions struct {
+	Repo     string
+	JUnit    []string
+	Commit   string
+	ReportID string
+	Title    string
+	Link     string
+	Status   bool
+	Streams  *iostreams.IOStreams
+}
+
+// NewCmdTestReport creates the insights test-report command
+func NewCmdTestReport(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &TestReportOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "test-report --junit <files>",
+		Short: "Publish JUnit test results as a Code Insights report",
+		Long: `Sum up the JUnit XML reports of a test run into a Code Insights report on
+a commit, for builds run outside Bitbucket Pipelines.
+
+The report shows how many tests passed, failed and were skipped, and
+fails if any failed. Each failed test is annotated with its failure
+message and output, on its file and line if the report gives them.
+--junit takes files or glob patterns, which may be quoted, and can be
+repeated.
+
+With --status, a build status is also set on the commit, passing or
+failing with the tests, so the results count towards merge checks. It
+links to --link if given, such as the build's page, and otherwise to the
+commit.`,
+		Example: `  # Report the results of a test run on the current commit
+  bb insights test-report --junit "reports/*.xml"
+
+  # Also set a build status linking to the CI build
+  bb insights test-report --junit build/test-results/*.xml --commit 1a2b3c4 \
+    --status --link https://ci.example.com/builds/42`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTestReport(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+	cmd.Flags().StringSliceVar(&opts.JUnit, "junit", nil, "JUnit XML files or glob patterns; can be repeated (required)")
+	cmd.Flags().StringVarP(&opts.Commit, "commit", "c", "HEAD", "Commit to report on")
+	cmd.Flags().StringVar(&opts.ReportID, "report-id", "bb-junit", "ID of the report, and key of the build status")
+	cmd.Flags().StringVar(&opts.Title, "title", "Tests", "Title of the report and build status")
+	cmd.Flags().StringVar(&opts.Link, "link", "", "URL of the build that ran the tests")
+	cmd.Flags().BoolVar(&opts.Status, "status", false, "Also set a build status on the commit")
+	_ = cmd.MarkFlagRequired("junit")
+
+	return cmd
+}
+
+func runTestReport(opts *TestReportOptions) error {
+	commit, err := resolveCommit(opts.Commit)
+	if err != nil {
+		return err
+	}
+
+	files, err := junitFiles(opts.JUnit)
+	if err != nil {
+		return err
+	}
+	var results testResults
+	for _, file := range files {
+		if err := results.addFile(file); err != nil {
+			return err
+		}
+	}
+	if results.total() == 0 {
+		opts.Streams.Warning("No test cases found in %d files", len(files))
+	}
+
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.Repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	root, _ := git.GetRepoRoot()
+	report := results.report(opts.Title)
+	report.Link = opts.Link
+	annotations := results.annotations(root)
+	if len(annotations) > api.MaxAnnotationsPerReport {
+		opts.Streams.Warning("Only the first %d of %d failed tests were annotated", api.MaxAnnotationsPerReport, len(annotations))
+		annotations = annotations[:api.MaxAnnotationsPerReport]
+	}
+
+	if err := publishReport(ctx, client, workspace, repoSlug, commit, opts.ReportID, report, annotations); err != nil {
+		return err
+	}
+	opts.Streams.Success("Uploaded report %s to commit %s: %s", opts.ReportID, shortCommit(commit), report.Details)
+
+	if opts.Status {
+		state := "SUCCESSFUL"
+		if report.Result == "FAILED" {
+			state = "FAILED"
+		}
+		link := opts.Link
+		if link == "" {
+			link = fmt.Sprintf("https://bitbucket.org/%s/%s/commits/%s", workspace, repoSlug, commit)
+		}
+		_, err := client.CreateCommitStatus(ctx, workspace, repoSlug, commit, &api.CommitStatusOptions{
+			Key:         opts.ReportID,
+			State:       state,
+			Name:        opts.Title,
+			Description: report.Details,
+			URL:         link,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set build status: %w", err)
+		}
+		opts.Streams.Success("Set build status %s to %s", opts.ReportID, state)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// UploadOptions holds the options for the upload command
+type UploadOptions struct {
+	Repo     string
+	SARIF    []string
+	Commit   string
+	ReportID string
+	Title    string
+	Link     string
+	FailOn   string
+	Streams  *iostreams.IOStreams
+}
+
+// NewCmdUpload creates the insights upload command
+func NewCmdUpload(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &UploadOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "upload --sarif <file>",
+		Short: "Upload SARIF findings as a Code Insights report",
+		Long: `Convert the findings of SARIF logs, the format written by most security
+scanners and linters, into a Code Insights report on a commit.
+
+Each finding becomes an annotation on its file and line, shown on the
+diffs of pull requests containing the commit. Severities come from the
+rule's security-severity score where the tool gives one, and otherwise
+from the finding's level: error is high, warning medium, and note low.
+Suppressed findings and those fixed since the scanner's baseline are left
+out. A report has at most 1000 annotations; beyond that, the most severe
+findings are kept.
+
+The report fails if any finding is of the --fail-on severity or higher.
+It is replaced on every upload, so running the scanner again updates it.
+Its ID is derived from the tools' names unless --report-id is given, so
+reports of different scanners can sit side by side.`,
+		Example: `  # Annotate the current commit with a scanner's findings
+  bb insights upload --sarif results.sarif
+
+  # Upload the findings of two tools to a commit, failing only on critical ones
+  bb insights upload --sarif semgrep.sarif --sarif trivy.sarif --commit 1a2b3c4 --fail-on critical`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUpload(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+	cmd.Flags().StringSliceVar(&opts.SARIF, "sarif", nil, "SARIF file to upload; can be repeated (required)")
+	cmd.Flags().StringVarP(&opts.Commit, "commit", "c", "HEAD", "Commit to report on")
+	cmd.Flags().StringVar(&opts.ReportID, "report-id", "", "ID of the report (default derived from the tools' names)")
+	cmd.Flags().StringVar(&opts.Title, "title", "", "Title of the report (default the tools' names)")
+	cmd.Flags().StringVar(&opts.Link, "link", "", "URL of the full results, e.g. of the build that produced them")
+	cmd.Flags().StringVar(&opts.FailOn, "fail-on", "high", "Lowest severity that fails the report: critical, high, medium, low, or none")
+	_ = cmd.MarkFlagRequired("sarif")
+
+	return cmd
+}
+
+func runUpload(opts *UploadOptions) error {
+	failOn := strings.ToUpper(opts.FailOn)
+	if failOn != "NONE" && !slices.Contains(severities, failOn) {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("invalid --fail-on %q: must be one of critical, high, medium, low, none", opts.FailOn))
+	}
+
+	commit, err := resolveCommit(opts.Commit)
+	if err != nil {
+		return err
+	}
+
+	// Scanners often write absolute paths, which are made relative to the
+	// repository the command is run in
+	root, _ := git.GetRepoRoot()
+
+	var f findings
+	for _, path := range opts.SARIF {
+		log, err := readSARIF(path)
+		if err != nil {
+			return err
+		}
+		f.add(log, root)
+	}
+
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.Repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	reportID := opts.ReportID
+	if reportID == "" {
+		reportID = sarifReportID(f.tools)
+	}
+	tools := f.tools
+	if len(tools) == 0 {
+		tools = []string{"SARIF"}
+	}
+	report := sarifReport(f.annotations, tools, failOn)
+	if opts.Title != "" {
+		report.Title = opts.Title
+	}
+	report.Link = opts.Link
+
+	total := len(f.annotations)
+	annotations := mostSevere(f.annotations, api.MaxAnnotationsPerReport)
+
+	if err := publishReport(ctx, client, workspace, repoSlug, commit, reportID, report, annotations); err != nil {
+		return err
+	}
+
+	if len(annotations) < total {
+		opts.Streams.Warning("Only the %d most severe of %d findings were annotated", len(annotations), total)
+	}
+	opts.Streams.Success("Uploaded report %s to commit %s: %s", reportID, shortCommit(commit), strings.ToLower(report.Details))
+	if report.Result == "FAILED" {
+		opts.Streams.Info("The report failed: it has findings of %s severity or higher", strings.ToLower(failOn))
+	}
+	return nil
+}
+
+// sarifReport builds the report of a SARIF upload, which fails if there
+// are annotations of severity failOn or higher
+func sarifReport(annotations []api.Annotation, tools []string, failOn string) *api.Report {
+	counts := countSeverities(annotations)
+
+	report := &api.Report{
+		Title:      strings.Join(tools, ", "),
+		ReportType: "BUG",
+		Reporter:   "bb",
+		Result:     "PASSED",
+		Data:       []api.ReportData{{Title: "Findings", Type: "NUMBER", Value: len(annotations)}},
+	}
+	for _, severity := range severities {
+		report.Data = append(report.Data, api.ReportData{
+			Title: strings.ToUpper(severity[:1]) + strings.ToLower(severity[1:]),
+			Type:  "NUMBER",
+			Value: counts[severity],
+		})
+	}
+
+	switch len(annotations) {
+	case 0:
+		report.Details = "No findings"
+	case 1:
+		report.Details = "1 finding"
+	default:
+		report.Details = fmt.Sprintf("%d findings", len(annotations))
+	}
+
+	for _, a := range annotations {
+		if a.AnnotationType == "VULNERABILITY" {
+			report.ReportType = "SECURITY"
+			break
+		}
+	}
+	if failOn != "NONE" {
+		for _, severity := range severities[:slices.Index(severities, failOn)+1] {
+			if counts[severity] > 0 {
+				report.Result = "FAILED"
+			}
+		}
+	}
+	return report
+}
+
+var nonIDChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// sarifReportID derives a report ID from the names of the tools whose
+// findings it holds
+func sarifReportID(tools []string) string {
+	slug := strings.Trim(nonIDChars.ReplaceAllString(strings.ToLower(strings.Join(tools, "-")), "-"), "-")
+	if len(slug) > 40 {
+		slug = strings.TrimRight(slug[:40], "-")
+	}
+	if slug == "" {
+		return "bb-sarif"
+	}
+	return "bb-sarif-" + slug
+}
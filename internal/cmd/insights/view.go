@@ -0,0 +1,204 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// ViewOptions holds the options for the view command
+type ViewOptions struct {
+	Repo     string
+	Commit   string
+	ReportID string
+	JSON     bool
+	Format   string
+	Streams  *iostreams.IOStreams
+}
+
+// reportView is a report with its annotations
+type reportView struct {
+	Report      *api.Report      `json:"report"`
+	Annotations []api.Annotation `json:"annotations"`
+}
+
+// NewCmdView creates the insights view command
+func NewCmdView(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &ViewOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "view <commit> <report-id>",
+		Short: "Show a Code Insights report of a commit",
+		Long: `Show a Code Insights report of a commit in detail: its result, every
+value it reports, such as coverage or the number of vulnerabilities, and its
+annotations, most severe first.
+
+The reports of a commit, with their IDs, are listed by 'bb commit view' and
+'bb pr view'.`,
+		Example: `  # Show the coverage report of a commit
+  bb insights view 1a2b3c4 coverage
+
+  # Show the findings of a scanner as JSON
+  bb insights view HEAD semgrep --json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Commit = args[0]
+			opts.ReportID = args[1]
+			return runView(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
+
+	return cmd
+}
+
+func runView(ctx context.Context, opts *ViewOptions) error {
+	// Outside a clone, short hashes can't be resolved but Bitbucket takes
+	// them as they are
+	commit, err := resolveCommit(opts.Commit)
+	if err != nil {
+		commit = opts.Commit
+	}
+
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.Repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	report, err := client.GetReport(ctx, workspace, repoSlug, commit, opts.ReportID)
+	if err != nil {
+		return fmt.Errorf("failed to get report %s of commit %s: %w", opts.ReportID, shortCommit(commit), err)
+	}
+	annotations, err := client.ListAnnotations(ctx, workspace, repoSlug, commit, opts.ReportID)
+	if err != nil {
+		return fmt.Errorf("failed to get annotations: %w", err)
+	}
+	sortBySeverity(annotations)
+
+	view := &reportView{Report: report, Annotations: annotations}
+	if opts.JSON || opts.Format != "" {
+		return cmdutil.PrintFormatted(opts.Streams, opts.Format, view)
+	}
+	displayReport(opts.Streams, view)
+	return nil
+}
+
+// sortBySeverity orders annotations most severe first, with those without
+// a severity last
+func sortBySeverity(annotations []api.Annotation) {
+	rank := func(a api.Annotation) int {
+		if i := slices.Index(severities, a.Severity); i >= 0 {
+			return i
+		}
+		return len(severities)
+	}
+	slices.SortStableFunc(annotations, func(a, b api.Annotation) int {
+		return rank(a) - rank(b)
+	})
+}
+
+func displayReport(streams *iostreams.IOStreams, view *reportView) {
+	out := streams.Out
+	r := view.Report
+
+	fmt.Fprintln(out, streams.Style(iostreams.RoleHeader, r.Title))
+	fmt.Fprintf(out, "ID:       %s\n", r.ExternalID)
+	if r.ReportType != "" {
+		fmt.Fprintf(out, "Type:     %s\n", strings.ToLower(r.ReportType))
+	}
+	if r.Reporter != "" {
+		fmt.Fprintf(out, "Reporter: %s\n", r.Reporter)
+	}
+	if r.Result != "" {
+		fmt.Fprintf(out, "Result:   %s\n", cmdutil.FormatReportResult(streams, r.Result))
+	}
+	if r.UpdatedOn != nil {
+		fmt.Fprintf(out, "Updated:  %s\n", cmdutil.FormatTime(streams, *r.UpdatedOn))
+	}
+	if r.Link != "" {
+		fmt.Fprintf(out, "Link:     %s\n", r.Link)
+	}
+	if r.Details != "" {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, r.Details)
+	}
+
+	if len(r.Data) > 0 {
+		fmt.Fprintln(out)
+		width := 0
+		for _, d := range r.Data {
+			width = max(width, len(d.Title))
+		}
+		for _, d := range r.Data {
+			fmt.Fprintf(out, "  %-*s  %s\n", width, d.Title, cmdutil.FormatReportData(d))
+		}
+	}
+
+	fmt.Fprintln(out)
+	if len(view.Annotations) == 0 {
+		fmt.Fprintln(out, "No annotations")
+		return
+	}
+	fmt.Fprintf(out, "Annotations: %s\n", countAnnotations(view.Annotations))
+	fmt.Fprintln(out)
+
+	table := cmdutil.NewTablePrinter(streams)
+	table.AddHeader("SEVERITY", "TYPE", "LOCATION", "SUMMARY")
+	for _, a := range view.Annotations {
+		table.AddRow(
+			strings.ToLower(firstNonEmpty(a.Severity, "-")),
+			strings.ToLower(strings.ReplaceAll(a.AnnotationType, "_", " ")),
+			annotationLocation(a),
+			a.Summary,
+		)
+	}
+	table.Render()
+}
+
+// countAnnotations describes how many annotations there are of each
+// severity, e.g. "12 (2 critical, 10 low)"
+func countAnnotations(annotations []api.Annotation) string {
+	counts := countSeverities(annotations)
+	var parts []string
+	for _, s := range severities {
+		if counts[s] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[s], strings.ToLower(s)))
+		}
+	}
+	if len(parts) == 0 {
+		return strconv.Itoa(len(annotations))
+	}
+	return fmt.Sprintf("%d (%s)", len(annotations), strings.Join(parts, ", "))
+}
+
+// annotationLocation is the path:line an annotation is on
+func annotationLocation(a api.Annotation) string {
+	switch {
+	case a.Path == "":
+		return "-"
+	case a.Line > 0:
+		return fmt.Sprintf("%s:%d", a.Path, a.Line)
+	default:
+		return a.Path
+	}
+}
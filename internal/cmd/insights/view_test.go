@@ -0,0 +1,49 @@
+package insights
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+func TestSortBySeverity(t *testing.T) {
+	annotations := []api.Annotation{
+		{ExternalID: "a", Severity: "LOW"},
+		{ExternalID: "b"},
+		{ExternalID: "c", Severity: "CRITICAL"},
+		{ExternalID: "d", Severity: "LOW"},
+		{ExternalID: "e", Severity: "HIGH"},
+	}
+	sortBySeverity(annotations)
+
+	var ids []string
+	for _, a := range annotations {
+		ids = append(ids, a.ExternalID)
+	}
+	if want := []string{"c", "e", "a", "d", "b"}; !slices.Equal(ids, want) {
+		t.Errorf("sortBySeverity() = %v, want %v", ids, want)
+	}
+	if got := countAnnotations(annotations); got != "5 (1 critical, 1 high, 2 low)" {
+		t.Errorf("countAnnotations() = %q", got)
+	}
+	if got := countAnnotations([]api.Annotation{{}, {}}); got != "2" {
+		t.Errorf("countAnnotations() without severities = %q", got)
+	}
+}
+
+func TestAnnotationLocation(t *testing.T) {
+	tests := []struct {
+		annotation api.Annotation
+		want       string
+	}{
+		{api.Annotation{Path: "src/main.go", Line: 12}, "src/main.go:12"},
+		{api.Annotation{Path: "go.mod"}, "go.mod"},
+		{api.Annotation{}, "-"},
+	}
+	for _, tt := range tests {
+		if got := annotationLocation(tt.annotation); got != tt.want {
+			t.Errorf("annotationLocation(%+v) = %q, want %q", tt.annotation, got, tt.want)
+		}
+	}
+}
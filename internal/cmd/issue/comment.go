@@ -2,10 +2,15 @@ package issue
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/rbansal42/bitbucket-cli/internal/api"
 	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
@@ -24,13 +29,24 @@ func NewCmdComment(streams *iostreams.IOStreams) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "comment <issue-id>",
-		Short: "Add a comment to an issue",
-		Long:  `Add a comment to an issue.`,
+		Short: "Add, edit or delete comments on an issue",
+		Long: `Add a comment to an issue.
+
+If --body is not provided, your editor is opened to write the comment.
+
+Use 'bb issue comment edit' and 'bb issue comment delete' to change or
+remove a comment.`,
 		Example: `  # Add a comment to issue #123
   bb issue comment 123 --body "This is a comment"
 
   # Add a comment to an issue in a specific repository
-  bb issue comment 123 --repo workspace/repo --body "Working on this"`,
+  bb issue comment 123 --repo workspace/repo --body "Working on this"
+
+  # Fix a typo in your last comment on issue #123
+  bb issue comment edit 123 --last
+
+  # Delete comment 456 on issue #123
+  bb issue comment delete 123 456`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runComment(opts, args)
@@ -40,6 +56,9 @@ func NewCmdComment(streams *iostreams.IOStreams) *cobra.Command {
 	cmd.Flags().StringVarP(&opts.body, "body", "b", "", "Comment body text")
 	cmd.Flags().StringVar(&opts.repo, "repo", "", "Repository in WORKSPACE/REPO format")
 
+	cmd.AddCommand(newCmdCommentEdit(streams))
+	cmd.AddCommand(newCmdCommentDelete(streams))
+
 	return cmd
 }
 
@@ -54,9 +73,19 @@ func runComment(opts *commentOptions, args []string) error {
 		return err
 	}
 
-	// If no body provided, require --body flag
+	// If no body provided, open editor when interactive
 	if opts.body == "" {
-		return fmt.Errorf("comment body required, use --body flag")
+		if !opts.streams.CanPrompt() {
+			return fmt.Errorf("comment body required, use --body flag")
+		}
+		content, err := cmdutil.OpenEditor("")
+		if errors.Is(err, cmdutil.ErrEditorAborted) {
+			return fmt.Errorf("comment body is required")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get comment: %w", err)
+		}
+		opts.body = content.Body
 	}
 
 	client, err := cmdutil.GetAPIClient()
@@ -75,3 +104,249 @@ func runComment(opts *commentOptions, args []string) error {
 	opts.streams.Success("Added comment to issue #%d", issueID)
 	return nil
 }
+
+type commentTargetOptions struct {
+	streams   *iostreams.IOStreams
+	repo      string
+	issueID   int
+	commentID int
+	last      bool
+	body      string
+	yes       bool
+}
+
+func newCmdCommentEdit(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &commentTargetOptions{
+		streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "edit <issue-id> {<comment-id> | --last}",
+		Short: "Edit a comment on an issue",
+		Long: `Replace the text of a comment on an issue, given by ID, or with --last,
+your most recent comment on it.
+
+If --body is not provided, your editor is opened with the comment's
+current text.`,
+		Example: `  # Edit your last comment on issue #123 in your editor
+  bb issue comment edit 123 --last
+
+  # Replace the text of comment 456
+  bb issue comment edit 123 456 --body "Fixed in v1.2"`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.parseArgs(args); err != nil {
+				return err
+			}
+			return runCommentEdit(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.last, "last", false, "Edit your most recent comment on the issue")
+	cmd.Flags().StringVarP(&opts.body, "body", "b", "", "New comment body text")
+	cmd.Flags().StringVar(&opts.repo, "repo", "", "Repository in WORKSPACE/REPO format")
+
+	return cmd
+}
+
+func newCmdCommentDelete(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &commentTargetOptions{
+		streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "delete <issue-id> {<comment-id> | --last}",
+		Short: "Delete a comment on an issue",
+		Long: `Delete a comment on an issue, given by ID, or with --last, your most
+recent comment on it.
+
+You will be prompted to confirm deletion unless the --yes flag is provided.`,
+		Example: `  # Delete your last comment on issue #123
+  bb issue comment delete 123 --last
+
+  # Delete comment 456 without confirmation prompt
+  bb issue comment delete 123 456 --yes`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.parseArgs(args); err != nil {
+				return err
+			}
+			return runCommentDelete(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.last, "last", false, "Delete your most recent comment on the issue")
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Skip confirmation prompt")
+	cmd.Flags().StringVar(&opts.repo, "repo", "", "Repository in WORKSPACE/REPO format")
+
+	return cmd
+}
+
+// parseArgs reads the issue ID and, unless --last is given, the comment ID
+// from args
+func (opts *commentTargetOptions) parseArgs(args []string) error {
+	issueID, err := parseIssueID(args)
+	if err != nil {
+		return err
+	}
+	opts.issueID = issueID
+
+	switch {
+	case opts.last && len(args) == 2:
+		return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("give a comment ID or --last, not both"))
+	case !opts.last && len(args) == 1:
+		return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("comment ID required, or use --last for your most recent comment"))
+	case len(args) == 2:
+		commentID, err := strconv.Atoi(args[1])
+		if err != nil || commentID <= 0 {
+			return fmt.Errorf("invalid comment ID: %s", args[1])
+		}
+		opts.commentID = commentID
+	}
+	return nil
+}
+
+// findComment fetches the comment opts targets: the one with opts.commentID,
+// or with opts.last, the current user's most recent comment on the issue
+func findComment(ctx context.Context, client *api.Client, workspace, repoSlug string, opts *commentTargetOptions) (*api.IssueComment, error) {
+	comments, err := client.ListAllIssueComments(ctx, workspace, repoSlug, opts.issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	if !opts.last {
+		for i := range comments {
+			if comments[i].ID == opts.commentID {
+				return &comments[i], nil
+			}
+		}
+		return nil, cmdutil.NewExitError(cmdutil.ExitNotFound, fmt.Errorf("issue #%d has no comment %d", opts.issueID, opts.commentID))
+	}
+
+	me, err := client.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+	comment := lastCommentBy(comments, me.UUID)
+	if comment == nil {
+		return nil, cmdutil.NewExitError(cmdutil.ExitNotFound, fmt.Errorf("you have not commented on issue #%d", opts.issueID))
+	}
+	return comment, nil
+}
+
+// lastCommentBy returns the most recent comment with text by the user with
+// the given UUID, or nil if they have none. Comments without text are
+// those Bitbucket adds for changes to the issue.
+func lastCommentBy(comments []api.IssueComment, uuid string) *api.IssueComment {
+	var last *api.IssueComment
+	for i := range comments {
+		c := &comments[i]
+		if c.User == nil || c.User.UUID != uuid || c.Content == nil || c.Content.Raw == "" {
+			continue
+		}
+		if last == nil || c.CreatedOn.After(last.CreatedOn) || (c.CreatedOn.Equal(last.CreatedOn) && c.ID > last.ID) {
+			last = c
+		}
+	}
+	return last
+}
+
+func runCommentEdit(ctx context.Context, opts *commentTargetOptions) error {
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.repo)
+	if err != nil {
+		return err
+	}
+	if opts.body == "" && !opts.streams.CanPrompt() {
+		return fmt.Errorf("comment body required, use --body flag")
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	commentID := opts.commentID
+	if opts.last || opts.body == "" {
+		comment, err := findComment(ctx, client, workspace, repoSlug, opts)
+		if err != nil {
+			return err
+		}
+		commentID = comment.ID
+
+		if opts.body == "" {
+			current := ""
+			if comment.Content != nil {
+				current = comment.Content.Raw
+			}
+			content, err := cmdutil.OpenEditor(current)
+			if errors.Is(err, cmdutil.ErrEditorAborted) {
+				return fmt.Errorf("comment body is required")
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get comment: %w", err)
+			}
+			if content.Body == current {
+				opts.streams.Info("Comment %d is unchanged", commentID)
+				return nil
+			}
+			opts.body = content.Body
+		}
+	}
+
+	if _, err := client.UpdateIssueComment(ctx, workspace, repoSlug, opts.issueID, commentID, opts.body); err != nil {
+		return fmt.Errorf("failed to edit comment: %w", err)
+	}
+
+	opts.streams.Success("Edited comment %d on issue #%d", commentID, opts.issueID)
+	return nil
+}
+
+func runCommentDelete(ctx context.Context, opts *commentTargetOptions) error {
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	commentID := opts.commentID
+	if opts.last {
+		comment, err := findComment(ctx, client, workspace, repoSlug, opts)
+		if err != nil {
+			return err
+		}
+		commentID = comment.ID
+		if !opts.yes {
+			fmt.Fprintf(opts.streams.ErrOut, "Comment %d: %s\n", commentID, cmdutil.TruncateString(strings.Join(strings.Fields(comment.Content.Raw), " "), 60))
+		}
+	}
+
+	if !opts.yes {
+		confirmed, err := opts.streams.PromptConfirm(fmt.Sprintf("Are you sure you want to delete comment %d on issue #%d?", commentID, opts.issueID), false)
+		if errors.Is(err, iostreams.ErrNoPrompt) {
+			return fmt.Errorf("cannot confirm deletion: stdin is not a terminal\nUse --yes flag to skip confirmation in non-interactive mode")
+		}
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return fmt.Errorf("deletion cancelled")
+		}
+	}
+
+	if err := client.DeleteIssueComment(ctx, workspace, repoSlug, opts.issueID, commentID); err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	opts.streams.Success("Deleted comment %d on issue #%d", commentID, opts.issueID)
+	return nil
+}
@@ -0,0 +1,63 @@
+package issue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+func TestLastCommentBy(t *testing.T) {
+	at := func(day int) time.Time {
+		return time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC)
+	}
+	me := &api.User{UUID: "{me}"}
+	other := &api.User{UUID: "{other}"}
+	comments := []api.IssueComment{
+		{ID: 1, User: me, Content: &api.Content{Raw: "first"}, CreatedOn: at(1)},
+		{ID: 2, User: me, Content: &api.Content{Raw: "second"}, CreatedOn: at(3)},
+		{ID: 3, User: other, Content: &api.Content{Raw: "reply"}, CreatedOn: at(4)},
+		// Bitbucket's record of a state change has no text
+		{ID: 4, User: me, Content: &api.Content{Raw: ""}, CreatedOn: at(5)},
+		{ID: 5, User: me, Content: &api.Content{Raw: "earlier, listed later"}, CreatedOn: at(2)},
+	}
+
+	if got := lastCommentBy(comments, "{me}"); got == nil || got.ID != 2 {
+		t.Errorf("lastCommentBy(me) = %+v, want comment 2", got)
+	}
+	if got := lastCommentBy(comments, "{other}"); got == nil || got.ID != 3 {
+		t.Errorf("lastCommentBy(other) = %+v, want comment 3", got)
+	}
+	if got := lastCommentBy(comments, "{nobody}"); got != nil {
+		t.Errorf("lastCommentBy(nobody) = %+v, want nil", got)
+	}
+}
+
+func TestCommentTargetParseArgs(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		last          bool
+		wantCommentID int
+		wantErr       bool
+	}{
+		{name: "comment ID", args: []string{"12", "34"}, wantCommentID: 34},
+		{name: "last", args: []string{"12"}, last: true},
+		{name: "neither", args: []string{"12"}, wantErr: true},
+		{name: "both", args: []string{"12", "34"}, last: true, wantErr: true},
+		{name: "invalid comment ID", args: []string{"12", "abc"}, wantErr: true},
+		{name: "invalid issue ID", args: []string{"abc"}, last: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &commentTargetOptions{last: tt.last}
+			err := opts.parseArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (opts.issueID != 12 || opts.commentID != tt.wantCommentID) {
+				t.Errorf("parseArgs() = issue %d, comment %d", opts.issueID, opts.commentID)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package issue
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 
 	"github.com/rbansal42/bitbucket-cli/internal/api"
 	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
 
@@ -20,6 +22,7 @@ type createOptions struct {
 	priority string
 	assignee string
 	repo     string
+	copy     bool
 }
 
 // NewCmdCreate creates the issue create command
@@ -36,7 +39,16 @@ func NewCmdCreate(streams *iostreams.IOStreams) *cobra.Command {
 		Long: `Create a new issue in a Bitbucket repository.
 
 If --title is not provided and stdin is a TTY, you will be prompted
-to enter a title interactively.`,
+to enter a title interactively. If --body is not provided, your editor
+is opened to write the description.
+
+The default kind and priority can be set per repository under issues.kind
+and issues.priority in .bb.yml.
+
+The description starts from, in order of preference, BB_ISSUE_TEMPLATE, the
+template named by issues.template in .bb.yml, .bitbucket/ISSUE_TEMPLATE.md in
+the repository, or the issue_template config key. BB_ISSUE_TEMPLATE and
+issue_template can be a file path or the template text.`,
 		Example: `  # Create an issue interactively
   bb issue create
 
@@ -52,6 +64,9 @@ to enter a title interactively.`,
   # Create in a specific repository
   bb issue create -t "New feature" --repo workspace/repo`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyRepoDefaults(cmd, opts); err != nil {
+				return err
+			}
 			return runCreate(opts)
 		},
 	}
@@ -62,10 +77,40 @@ to enter a title interactively.`,
 	cmd.Flags().StringVarP(&opts.priority, "priority", "p", "major", "Priority (trivial, minor, major, critical, blocker)")
 	cmd.Flags().StringVarP(&opts.assignee, "assignee", "a", "", "Assignee username")
 	cmd.Flags().StringVar(&opts.repo, "repo", "", "Repository in WORKSPACE/REPO format")
+	cmdutil.AddCopyFlag(cmd, &opts.copy, "created issue")
 
 	return cmd
 }
 
+// applyRepoDefaults uses the kind and priority from .bb.yml unless they were
+// given as flags
+func applyRepoDefaults(cmd *cobra.Command, opts *createOptions) error {
+	repoConfig, err := cmdutil.LoadRepoConfig(opts.repo)
+	if err != nil {
+		return err
+	}
+	if kind := repoConfig.Issues.Kind; kind != "" && !cmd.Flags().Changed("kind") {
+		opts.kind = kind
+	}
+	if priority := repoConfig.Issues.Priority; priority != "" && !cmd.Flags().Changed("priority") {
+		opts.priority = priority
+	}
+	return nil
+}
+
+// descriptionTemplate returns the configured issue template, if any
+func descriptionTemplate(repoFlag string) (string, error) {
+	repoConfig, err := cmdutil.LoadRepoConfig(repoFlag)
+	if err != nil {
+		return "", err
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return "", err
+	}
+	return config.NewResolver(cfg, repoConfig).IssueTemplate()
+}
+
 func runCreate(opts *createOptions) error {
 	// Resolve repository
 	workspace, repoSlug, err := cmdutil.ParseRepository(opts.repo)
@@ -84,10 +129,6 @@ func runCreate(opts *createOptions) error {
 
 	// Interactive mode: prompt for title if not provided
 	if opts.title == "" {
-		if !opts.streams.IsStdinTTY() {
-			return fmt.Errorf("--title flag is required when not running interactively")
-		}
-
 		title, err := promptForTitle(opts.streams)
 		if err != nil {
 			return err
@@ -98,6 +139,30 @@ func runCreate(opts *createOptions) error {
 		opts.title = title
 	}
 
+	// Interactive mode: open editor for body if not provided
+	if opts.body == "" && opts.streams.CanPrompt() {
+		body, err := descriptionTemplate(opts.repo)
+		if err != nil {
+			opts.streams.Warning("%s", err)
+		}
+		if body == "" {
+			body = "\n"
+		}
+		content, err := cmdutil.OpenEditor(cmdutil.EditorTemplate(opts.title, body, editorHint))
+		switch {
+		case errors.Is(err, cmdutil.ErrEditorAborted):
+			return fmt.Errorf("issue creation cancelled")
+		case err != nil:
+			opts.streams.Warning("Could not open editor: %v", err)
+		default:
+			if content.Title() == "" {
+				return fmt.Errorf("title is required")
+			}
+			opts.title = content.Title()
+			opts.body = content.Body
+		}
+	}
+
 	// Validate kind
 	validKinds := map[string]bool{"bug": true, "enhancement": true, "proposal": true, "task": true}
 	if !validKinds[opts.kind] {
@@ -140,9 +205,18 @@ func runCreate(opts *createOptions) error {
 
 	// Print success message and URL
 	opts.streams.Success("Created issue #%d: %s", issue.ID, issue.Title)
-	fmt.Fprintln(opts.streams.Out)
 	if issue.Links != nil && issue.Links.HTML != nil {
+		if !opts.streams.IsQuiet() {
+			fmt.Fprintln(opts.streams.Out)
+		}
 		fmt.Fprintln(opts.streams.Out, issue.Links.HTML.Href)
+		if opts.copy {
+			if err := cmdutil.CopyURL(opts.streams, issue.Links.HTML.Href); err != nil {
+				opts.streams.Warning("%s", err)
+			}
+		}
+	} else if opts.streams.IsQuiet() {
+		fmt.Fprintln(opts.streams.Out, issue.ID)
 	}
 
 	return nil
@@ -2,6 +2,7 @@ package issue
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -65,14 +66,14 @@ func runDelete(opts *deleteOptions, args []string) error {
 
 	// If not auto-confirmed, show warning and prompt
 	if !opts.yes {
-		// Require TTY for interactive confirmation
-		if !opts.streams.IsStdinTTY() {
+		confirmed, err := opts.streams.PromptConfirm(fmt.Sprintf("Are you sure you want to delete issue #%d?", issueID), false)
+		if errors.Is(err, iostreams.ErrNoPrompt) {
 			return fmt.Errorf("cannot confirm deletion: stdin is not a terminal\nUse --yes flag to skip confirmation in non-interactive mode")
 		}
-
-		fmt.Fprintf(opts.streams.Out, "Are you sure you want to delete issue #%d? [y/N] ", issueID)
-
-		if !confirmPrompt(opts.streams.In) {
+		if err != nil {
+			return err
+		}
+		if !confirmed {
 			return fmt.Errorf("deletion cancelled")
 		}
 	}
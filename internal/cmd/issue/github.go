@@ -0,0 +1,156 @@
+package issue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// githubAPIURL is GitHub's REST API, unless GITHUB_API_URL names another,
+// e.g. of a GitHub Enterprise server
+const githubAPIURL = "https://api.github.com"
+
+// githubIssue is an issue read from GitHub. Both the REST API's field names
+// and those of "gh issue list --json" are accepted.
+type githubIssue struct {
+	Number      int             `json:"number"`
+	Title       string          `json:"title"`
+	Body        string          `json:"body"`
+	State       string          `json:"state"`
+	StateReason string          `json:"state_reason"`
+	HTMLURL     string          `json:"html_url"`
+	CreatedAt   time.Time       `json:"created_at"`
+	PullRequest json.RawMessage `json:"pull_request,omitempty"`
+	User        *githubUser     `json:"user"`
+	Labels      []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+
+	// The gh CLI's names for the fields above
+	Author         *githubUser `json:"author"`
+	CreatedAtCamel time.Time   `json:"createdAt"`
+	StateReasonGH  string      `json:"stateReason"`
+}
+
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+// normalize fills in the REST API fields from their gh CLI equivalents and
+// lowercases the state, which gh reports as OPEN or CLOSED
+func (i *githubIssue) normalize(repo string) {
+	if i.User == nil {
+		i.User = i.Author
+	}
+	if i.CreatedAt.IsZero() {
+		i.CreatedAt = i.CreatedAtCamel
+	}
+	if i.StateReason == "" {
+		i.StateReason = i.StateReasonGH
+	}
+	i.State = strings.ToLower(i.State)
+	i.StateReason = strings.ToLower(i.StateReason)
+	if i.HTMLURL == "" {
+		i.HTMLURL = fmt.Sprintf("https://github.com/%s/issues/%d", repo, i.Number)
+	}
+}
+
+// readGitHubIssues reads issues exported from GitHub to a JSON file: an
+// array of issues as returned by the REST API or "gh issue list --json"
+func readGitHubIssues(path, repo string) ([]githubIssue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var issues []githubIssue
+	if err := json.Unmarshal(data, &issues); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: expected a JSON array of GitHub issues: %w", path, err)
+	}
+	for i := range issues {
+		issues[i].normalize(repo)
+	}
+	return issues, nil
+}
+
+// fetchGitHubIssues reads the issues of repo, in OWNER/REPO format, in
+// state ("open", "closed" or "all") from the GitHub API. The token in
+// GH_TOKEN or GITHUB_TOKEN is used if set; without one only public
+// repositories can be read, at a low rate limit.
+func fetchGitHubIssues(ctx context.Context, repo, state string) ([]githubIssue, error) {
+	base := os.Getenv("GITHUB_API_URL")
+	if base == "" {
+		base = githubAPIURL
+	}
+	token := os.Getenv("GH_TOKEN")
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	next := fmt.Sprintf("%s/repos/%s/issues?state=%s&per_page=100&direction=asc", strings.TrimSuffix(base, "/"), repo, state)
+
+	var issues []githubIssue
+	for next != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reach GitHub: %w", err)
+		}
+		var page []githubIssue
+		err = decodeGitHubResponse(resp, &page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for i := range page {
+			page[i].normalize(repo)
+		}
+		issues = append(issues, page...)
+		next = nextLink(resp.Header.Get("Link"))
+	}
+	return issues, nil
+}
+
+func decodeGitHubResponse(resp *http.Response, v any) error {
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			return fmt.Errorf("GitHub repository not found; set GH_TOKEN or GITHUB_TOKEN to read a private one")
+		case http.StatusUnauthorized:
+			return fmt.Errorf("GitHub rejected the token in GH_TOKEN or GITHUB_TOKEN: %s", body.Message)
+		}
+		return fmt.Errorf("GitHub API error (HTTP %d): %s", resp.StatusCode, body.Message)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse GitHub response: %w", err)
+	}
+	return nil
+}
+
+// nextLink returns the rel="next" URL of a GitHub Link header, or ""
+func nextLink(header string) string {
+	for link := range strings.SplitSeq(header, ",") {
+		target, params, ok := strings.Cut(strings.TrimSpace(link), ";")
+		if ok && strings.Contains(params, `rel="next"`) {
+			return strings.Trim(strings.TrimSpace(target), "<>")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,489 @@
+package issue
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+type importOptions struct {
+	streams    *iostreams.IOStreams
+	repo       string
+	fromGitHub string
+	file       string
+	state      string
+	mapLabels  []string
+	limit      int
+	dryRun     bool
+	kind       string
+	priority   string
+}
+
+// labelRule sets an issue field from a GitHub label
+type labelRule struct {
+	field string // kind or priority
+	value string
+}
+
+// defaultLabelRules map common GitHub labels, after labelName has stripped
+// prefixes such as "type:" and "priority:"
+var defaultLabelRules = map[string]labelRule{
+	"bug":         {"kind", "bug"},
+	"enhancement": {"kind", "enhancement"},
+	"feature":     {"kind", "enhancement"},
+	"proposal":    {"kind", "proposal"},
+	"task":        {"kind", "task"},
+	"chore":       {"kind", "task"},
+	"trivial":     {"priority", "trivial"},
+	"minor":       {"priority", "minor"},
+	"low":         {"priority", "minor"},
+	"major":       {"priority", "major"},
+	"medium":      {"priority", "major"},
+	"critical":    {"priority", "critical"},
+	"high":        {"priority", "critical"},
+	"urgent":      {"priority", "critical"},
+	"blocker":     {"priority", "blocker"},
+}
+
+var (
+	validKinds      = []string{"bug", "enhancement", "proposal", "task"}
+	validPriorities = []string{"trivial", "minor", "major", "critical", "blocker"}
+	githubRepoRE    = regexp.MustCompile(`^[\w.-]+/[\w.-]+$`)
+)
+
+// NewCmdImport creates the issue import command
+func NewCmdImport(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &importOptions{
+		streams:  streams,
+		kind:     "bug",
+		priority: "major",
+	}
+
+	cmd := &cobra.Command{
+		Use:   "import --from-github <owner/repo>",
+		Short: "Import issues from GitHub",
+		Long: `Recreate the issues of a GitHub repository in a Bitbucket repository's
+issue tracker.
+
+Issues are read from the GitHub API, using the token in GH_TOKEN or
+GITHUB_TOKEN if set; public repositories can be read without one. Set
+GITHUB_API_URL to read from a GitHub Enterprise server. Alternatively,
+--file reads issues exported as a JSON array, either from the API or by
+"gh issue list --json number,title,body,state,stateReason,labels,author,createdAt,url".
+Pull requests are skipped.
+
+Each issue's kind and priority are taken from its labels. Common labels
+such as bug, enhancement, feature, task, low, high and critical are mapped
+by default, ignoring case and prefixes such as "type:" or "priority:".
+--map-label adds or overrides mappings, as LABEL=kind:VALUE or
+LABEL=priority:VALUE. Issues without a mapped label get the kind and
+priority in .bb.yml, or bug and major.
+
+Imported issues keep their title and description, which notes the GitHub
+issue they came from. Closed issues are resolved, or marked wontfix or
+duplicate if closed as such. Issues imported before are skipped, so an
+interrupted import can be run again.
+
+Use --dry-run to see what would be imported, and which labels have no
+mapping, without creating anything.`,
+		Example: `  # Preview an import of open issues
+  bb issue import --from-github octo-org/octo-repo --dry-run
+
+  # Import every issue, mapping custom labels
+  bb issue import --from-github octo-org/octo-repo --state all \
+    --map-label needs-design=kind:proposal --map-label P0=priority:blocker
+
+  # Import from an export made with gh
+  gh issue list -R octo-org/octo-repo --state all --limit 1000 \
+    --json number,title,body,state,stateReason,labels,author,createdAt,url > issues.json
+  bb issue import --from-github octo-org/octo-repo --file issues.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoConfig, err := cmdutil.LoadRepoConfig(opts.repo)
+			if err != nil {
+				return err
+			}
+			if repoConfig.Issues.Kind != "" {
+				opts.kind = repoConfig.Issues.Kind
+			}
+			if repoConfig.Issues.Priority != "" {
+				opts.priority = repoConfig.Issues.Priority
+			}
+			return runImport(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.fromGitHub, "from-github", "", "GitHub repository to import from, in OWNER/REPO format (required)")
+	cmd.Flags().StringVar(&opts.file, "file", "", "Read issues from a JSON export instead of the GitHub API")
+	cmd.Flags().StringVarP(&opts.state, "state", "s", "open", "Issues to import: open, closed, or all")
+	cmd.Flags().StringArrayVar(&opts.mapLabels, "map-label", nil, "Map a label to a kind or priority, as LABEL=kind:VALUE or LABEL=priority:VALUE")
+	cmd.Flags().IntVarP(&opts.limit, "limit", "L", 0, "Maximum number of issues to import (default all)")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Show what would be imported without creating issues")
+	cmd.Flags().StringVar(&opts.repo, "repo", "", "Repository in WORKSPACE/REPO format")
+	_ = cmd.MarkFlagRequired("from-github")
+
+	return cmd
+}
+
+// importPlan is how a GitHub issue is recreated in Bitbucket
+type importPlan struct {
+	source   githubIssue
+	kind     string
+	priority string
+	state    string
+	unmapped []string
+	existing int // ID of an earlier import of the issue, or 0
+}
+
+func runImport(ctx context.Context, opts *importOptions) error {
+	if !githubRepoRE.MatchString(opts.fromGitHub) {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("invalid --from-github %q: expected OWNER/REPO", opts.fromGitHub))
+	}
+	switch opts.state {
+	case "open", "closed", "all":
+	default:
+		return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("invalid --state %q: must be one of open, closed, all", opts.state))
+	}
+	rules, err := parseLabelRules(opts.mapLabels)
+	if err != nil {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, err)
+	}
+	if !slices.Contains(validKinds, opts.kind) {
+		return fmt.Errorf("invalid issues.kind %q in .bb.yml: must be one of %s", opts.kind, strings.Join(validKinds, ", "))
+	}
+	if !slices.Contains(validPriorities, opts.priority) {
+		return fmt.Errorf("invalid issues.priority %q in .bb.yml: must be one of %s", opts.priority, strings.Join(validPriorities, ", "))
+	}
+
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	var issues []githubIssue
+	if opts.file != "" {
+		issues, err = readGitHubIssues(opts.file, opts.fromGitHub)
+	} else {
+		opts.streams.Info("Reading issues of %s from GitHub...", opts.fromGitHub)
+		issues, err = fetchGitHubIssues(ctx, opts.fromGitHub, opts.state)
+	}
+	if err != nil {
+		return err
+	}
+	issues = selectIssues(issues, opts.state, opts.limit)
+	if len(issues) == 0 {
+		opts.streams.Info("No %s issues to import from %s", opts.state, opts.fromGitHub)
+		return nil
+	}
+
+	imported, err := importedIssues(ctx, client, workspace, repoSlug, opts.fromGitHub)
+	if err != nil {
+		return fmt.Errorf("failed to look up issues imported before: %w", err)
+	}
+
+	plans := make([]importPlan, len(issues))
+	for i, issue := range issues {
+		plans[i] = planImport(issue, rules, opts.kind, opts.priority)
+		plans[i].existing = imported[issue.Number]
+	}
+
+	if opts.dryRun {
+		return printImportReport(opts.streams, plans, opts.fromGitHub, workspace, repoSlug)
+	}
+
+	var created, skipped, failed int
+	for _, plan := range plans {
+		ref := fmt.Sprintf("%s#%d", opts.fromGitHub, plan.source.Number)
+		if plan.existing != 0 {
+			skipped++
+			continue
+		}
+		issue, err := createImportedIssue(ctx, client, workspace, repoSlug, opts.fromGitHub, plan)
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("import interrupted after %d issues", created)
+			}
+			opts.streams.Warning("Could not import %s: %v", ref, err)
+			failed++
+			continue
+		}
+		created++
+		opts.streams.Success("Imported %s as #%d", ref, issue.ID)
+	}
+
+	if skipped > 0 {
+		opts.streams.Info("Skipped %d issues imported before", skipped)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to import %d of %d issues", failed, created+failed)
+	}
+	opts.streams.Success("Imported %d issues from %s to %s/%s", created, opts.fromGitHub, workspace, repoSlug)
+	return nil
+}
+
+// parseLabelRules parses --map-label values, keyed by lowercased label
+func parseLabelRules(values []string) (map[string]labelRule, error) {
+	rules := make(map[string]labelRule, len(values))
+	for _, v := range values {
+		label, target, ok := strings.Cut(v, "=")
+		field, value, ok2 := strings.Cut(target, ":")
+		label = strings.ToLower(strings.TrimSpace(label))
+		field = strings.ToLower(strings.TrimSpace(field))
+		value = strings.ToLower(strings.TrimSpace(value))
+		if !ok || !ok2 || label == "" {
+			return nil, fmt.Errorf("invalid --map-label %q: expected LABEL=kind:VALUE or LABEL=priority:VALUE", v)
+		}
+		switch field {
+		case "kind":
+			if !slices.Contains(validKinds, value) {
+				return nil, fmt.Errorf("invalid kind %q in --map-label: must be one of %s", value, strings.Join(validKinds, ", "))
+			}
+		case "priority":
+			if !slices.Contains(validPriorities, value) {
+				return nil, fmt.Errorf("invalid priority %q in --map-label: must be one of %s", value, strings.Join(validPriorities, ", "))
+			}
+		default:
+			return nil, fmt.Errorf("invalid --map-label %q: can map to kind or priority, not %q", v, field)
+		}
+		rules[label] = labelRule{field: field, value: value}
+	}
+	return rules, nil
+}
+
+// labelName returns a label lowercased and without a prefix naming what it
+// classifies, so "Type: Bug" and "priority/high" become "bug" and "high"
+func labelName(label string) string {
+	name := strings.ToLower(strings.TrimSpace(label))
+	for _, prefix := range []string{"type", "kind", "priority"} {
+		for _, sep := range []string{":", "/", "-"} {
+			if rest, ok := strings.CutPrefix(name, prefix+sep); ok {
+				return strings.TrimSpace(rest)
+			}
+		}
+	}
+	return name
+}
+
+// lookupLabel finds the rule for a label: a --map-label rule for the label
+// as given, then for its name, then a default rule for its name
+func lookupLabel(label string, rules map[string]labelRule) (labelRule, bool) {
+	name := labelName(label)
+	if rule, ok := rules[strings.ToLower(strings.TrimSpace(label))]; ok {
+		return rule, true
+	}
+	if rule, ok := rules[name]; ok {
+		return rule, true
+	}
+	rule, ok := defaultLabelRules[name]
+	return rule, ok
+}
+
+// planImport maps a GitHub issue's labels and state to Bitbucket's. The
+// first label mapping to each field wins; kind and priority are used when
+// none does.
+func planImport(issue githubIssue, rules map[string]labelRule, kind, priority string) importPlan {
+	plan := importPlan{source: issue, state: "new"}
+	for _, label := range issue.Labels {
+		rule, ok := lookupLabel(label.Name, rules)
+		switch {
+		case !ok:
+			plan.unmapped = append(plan.unmapped, label.Name)
+		case rule.field == "kind" && plan.kind == "":
+			plan.kind = rule.value
+		case rule.field == "priority" && plan.priority == "":
+			plan.priority = rule.value
+		}
+	}
+	if plan.kind == "" {
+		plan.kind = kind
+	}
+	if plan.priority == "" {
+		plan.priority = priority
+	}
+
+	if issue.State == "closed" {
+		switch issue.StateReason {
+		case "not_planned":
+			plan.state = "wontfix"
+		case "duplicate":
+			plan.state = "duplicate"
+		default:
+			plan.state = "resolved"
+		}
+	}
+	return plan
+}
+
+// selectIssues drops pull requests and issues not in state, orders the
+// rest by number so they are created in the order they were opened, and
+// keeps the first limit of them
+func selectIssues(issues []githubIssue, state string, limit int) []githubIssue {
+	var selected []githubIssue
+	for _, issue := range issues {
+		if len(issue.PullRequest) > 0 && string(issue.PullRequest) != "null" {
+			continue
+		}
+		if state != "all" && issue.State != state {
+			continue
+		}
+		selected = append(selected, issue)
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Number < selected[j].Number
+	})
+	if limit > 0 && len(selected) > limit {
+		selected = selected[:limit]
+	}
+	return selected
+}
+
+// importedContent is the description of an imported issue: the original
+// one, followed by where it came from
+func importedContent(repo string, issue githubIssue) string {
+	var b strings.Builder
+	if body := strings.TrimSpace(issue.Body); body != "" {
+		b.WriteString(body)
+		b.WriteString("\n\n---\n\n")
+	}
+	fmt.Fprintf(&b, "_%s [%s#%d](%s)", importMarker, repo, issue.Number, issue.HTMLURL)
+	if issue.User != nil && issue.User.Login != "" {
+		fmt.Fprintf(&b, ", opened by @%s", issue.User.Login)
+	}
+	if !issue.CreatedAt.IsZero() {
+		fmt.Fprintf(&b, " on %s", issue.CreatedAt.Format("2006-01-02"))
+	}
+	b.WriteString("._")
+	if len(issue.Labels) > 0 {
+		names := make([]string, len(issue.Labels))
+		for i, label := range issue.Labels {
+			names[i] = "`" + label.Name + "`"
+		}
+		fmt.Fprintf(&b, "\n\nLabels: %s", strings.Join(names, ", "))
+	}
+	return b.String()
+}
+
+// importMarker precedes the reference to the GitHub issue in the
+// description of an imported issue, by which earlier imports are found
+const importMarker = "Imported from GitHub issue"
+
+// importedIssues returns the IDs of the issues imported from repo before,
+// keyed by the number of the GitHub issue
+func importedIssues(ctx context.Context, client *api.Client, workspace, repoSlug, repo string) (map[int]int, error) {
+	ref := regexp.MustCompile(regexp.QuoteMeta(importMarker+" ["+repo+"#") + `(\d+)\]`)
+	listOpts := &api.IssueListOptions{
+		Q:     fmt.Sprintf(`content.raw ~ "%s [%s#"`, importMarker, repo),
+		Limit: 100,
+	}
+
+	imported := make(map[int]int)
+	for listOpts.Page = 1; ; listOpts.Page++ {
+		result, err := client.ListIssues(ctx, workspace, repoSlug, listOpts)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range result.Values {
+			if issue.Content == nil {
+				continue
+			}
+			if m := ref.FindStringSubmatch(issue.Content.Raw); m != nil {
+				number, _ := strconv.Atoi(m[1])
+				imported[number] = issue.ID
+			}
+		}
+		if result.Next == "" {
+			return imported, nil
+		}
+	}
+}
+
+// createImportedIssue creates the issue planned, then sets its state, as
+// issues can't be created in any state but new
+func createImportedIssue(ctx context.Context, client *api.Client, workspace, repoSlug, repo string, plan importPlan) (*api.Issue, error) {
+	issue, err := client.CreateIssue(ctx, workspace, repoSlug, &api.IssueCreateOptions{
+		Title:    plan.source.Title,
+		Content:  &api.Content{Raw: importedContent(repo, plan.source)},
+		Kind:     plan.kind,
+		Priority: plan.priority,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if plan.state != "new" {
+		state := plan.state
+		if _, err := client.UpdateIssue(ctx, workspace, repoSlug, issue.ID, &api.IssueUpdateOptions{State: &state}); err != nil {
+			return nil, fmt.Errorf("created as #%d, but failed to set its state to %s: %w", issue.ID, state, err)
+		}
+	}
+	return issue, nil
+}
+
+// printImportReport shows what an import would do, and which labels
+// weren't mapped to a kind or priority
+func printImportReport(streams *iostreams.IOStreams, plans []importPlan, repo, workspace, repoSlug string) error {
+	unmapped := make(map[string]int)
+	pending := 0
+	table := cmdutil.NewTablePrinter(streams)
+	table.AddHeader("GITHUB", "TITLE", "KIND", "PRIORITY", "STATE", "NOTE")
+	for _, plan := range plans {
+		note := ""
+		if plan.existing != 0 {
+			note = fmt.Sprintf("skipped, imported as #%d", plan.existing)
+		} else {
+			pending++
+			for _, label := range plan.unmapped {
+				unmapped[label]++
+			}
+			if len(plan.unmapped) > 0 {
+				note = "unmapped: " + strings.Join(plan.unmapped, ", ")
+			}
+		}
+		table.AddRow(
+			fmt.Sprintf("#%d", plan.source.Number),
+			plan.source.Title,
+			plan.kind,
+			plan.priority,
+			plan.state,
+			streams.Style(iostreams.RoleMuted, note),
+		)
+	}
+
+	streams.Info("Dry run: would import %d of %d issues from %s to %s/%s", pending, len(plans), repo, workspace, repoSlug)
+	if err := table.Render(); err != nil {
+		return err
+	}
+
+	if len(unmapped) > 0 {
+		labels := make([]string, 0, len(unmapped))
+		for label := range unmapped {
+			labels = append(labels, label)
+		}
+		sort.Strings(labels)
+		for i, label := range labels {
+			labels[i] = fmt.Sprintf("%s (%d)", label, unmapped[label])
+		}
+		streams.Info("Labels without a mapping: %s", strings.Join(labels, ", "))
+		streams.Info("Map them with --map-label LABEL=kind:VALUE or LABEL=priority:VALUE")
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package issue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func labels(names ...string) []struct {
+	Name string `json:"name"`
+} {
+	out := make([]struct {
+		Name string `json:"name"`
+	}, len(names))
+	for i, name := range names {
+		out[i].Name = name
+	}
+	return out
+}
+
+func TestPlanImport(t *testing.T) {
+	rules, err := parseLabelRules([]string{"needs-design=kind:proposal", "P0=priority:blocker"})
+	if err != nil {
+		t.Fatalf("parseLabelRules() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		issue        githubIssue
+		wantKind     string
+		wantPriority string
+		wantState    string
+		wantUnmapped []string
+	}{
+		{
+			name:         "no labels uses defaults",
+			issue:        githubIssue{State: "open"},
+			wantKind:     "task",
+			wantPriority: "minor",
+			wantState:    "new",
+		},
+		{
+			name:         "default mappings ignore case and prefixes",
+			issue:        githubIssue{State: "open", Labels: labels("Type: Feature", "priority/high")},
+			wantKind:     "enhancement",
+			wantPriority: "critical",
+			wantState:    "new",
+		},
+		{
+			name:         "custom rules override defaults",
+			issue:        githubIssue{State: "open", Labels: labels("needs-design", "p0", "bug")},
+			wantKind:     "proposal",
+			wantPriority: "blocker",
+			wantState:    "new",
+		},
+		{
+			name:         "unmapped labels are reported",
+			issue:        githubIssue{State: "open", Labels: labels("help wanted", "bug", "good first issue")},
+			wantKind:     "bug",
+			wantPriority: "minor",
+			wantState:    "new",
+			wantUnmapped: []string{"help wanted", "good first issue"},
+		},
+		{
+			name:         "completed issues are resolved",
+			issue:        githubIssue{State: "closed", StateReason: "completed"},
+			wantKind:     "task",
+			wantPriority: "minor",
+			wantState:    "resolved",
+		},
+		{
+			name:         "issues not planned are wontfix",
+			issue:        githubIssue{State: "closed", StateReason: "not_planned"},
+			wantKind:     "task",
+			wantPriority: "minor",
+			wantState:    "wontfix",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := planImport(tt.issue, rules, "task", "minor")
+			if plan.kind != tt.wantKind {
+				t.Errorf("kind = %q, want %q", plan.kind, tt.wantKind)
+			}
+			if plan.priority != tt.wantPriority {
+				t.Errorf("priority = %q, want %q", plan.priority, tt.wantPriority)
+			}
+			if plan.state != tt.wantState {
+				t.Errorf("state = %q, want %q", plan.state, tt.wantState)
+			}
+			if !slices.Equal(plan.unmapped, tt.wantUnmapped) {
+				t.Errorf("unmapped = %q, want %q", plan.unmapped, tt.wantUnmapped)
+			}
+		})
+	}
+}
+
+func TestParseLabelRules_Invalid(t *testing.T) {
+	for _, value := range []string{"bug", "bug=kind", "bug=kind:defect", "bug=priority:none", "bug=state:open", "=kind:bug"} {
+		if _, err := parseLabelRules([]string{value}); err == nil {
+			t.Errorf("parseLabelRules(%q) succeeded, want error", value)
+		}
+	}
+}
+
+func TestSelectIssues(t *testing.T) {
+	issues := []githubIssue{
+		{Number: 3, State: "open"},
+		{Number: 1, State: "closed"},
+		{Number: 2, State: "open", PullRequest: json.RawMessage(`{"url": "x"}`)},
+		{Number: 4, State: "open", PullRequest: json.RawMessage(`null`)},
+		{Number: 5, State: "open"},
+	}
+
+	numbers := func(issues []githubIssue) []int {
+		var out []int
+		for _, issue := range issues {
+			out = append(out, issue.Number)
+		}
+		return out
+	}
+
+	if got := numbers(selectIssues(issues, "open", 0)); !slices.Equal(got, []int{3, 4, 5}) {
+		t.Errorf("open issues = %v, want [3 4 5]", got)
+	}
+	if got := numbers(selectIssues(issues, "all", 2)); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("first two issues = %v, want [1 3]", got)
+	}
+}
+
+func TestImportedContent(t *testing.T) {
+	issue := githubIssue{
+		Number:    7,
+		Body:      "It crashes.",
+		HTMLURL:   "https://github.com/octo/repo/issues/7",
+		CreatedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		User:      &githubUser{Login: "mona"},
+		Labels:    labels("bug"),
+	}
+
+	got := importedContent("octo/repo", issue)
+	want := "It crashes.\n\n---\n\n_Imported from GitHub issue [octo/repo#7](https://github.com/octo/repo/issues/7), opened by @mona on 2024-03-01._\n\nLabels: `bug`"
+	if got != want {
+		t.Errorf("importedContent() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFetchGitHubIssues(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octo/repo/issues" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `[{"number": 2, "title": "Second", "state": "closed", "state_reason": "not_planned"}]`)
+			return
+		}
+		if got := r.URL.Query().Get("state"); got != "all" {
+			t.Errorf("state = %q, want all", got)
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%s/repos/octo/repo/issues?state=all&page=2>; rel="next", <%s/x>; rel="last"`, server.URL, server.URL))
+		fmt.Fprint(w, `[{"number": 1, "title": "First", "state": "open", "html_url": "https://github.com/octo/repo/issues/1", "user": {"login": "mona"}}]`)
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_API_URL", server.URL)
+	t.Setenv("GH_TOKEN", "secret")
+
+	issues, err := fetchGitHubIssues(context.Background(), "octo/repo", "all")
+	if err != nil {
+		t.Fatalf("fetchGitHubIssues() error = %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2", len(issues))
+	}
+	if issues[0].User == nil || issues[0].User.Login != "mona" {
+		t.Errorf("issues[0].User = %+v", issues[0].User)
+	}
+	if issues[1].HTMLURL != "https://github.com/octo/repo/issues/2" {
+		t.Errorf("issues[1].HTMLURL = %q", issues[1].HTMLURL)
+	}
+}
+
+func TestFetchGitHubIssues_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message": "Not Found"}`)
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_API_URL", server.URL)
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+
+	_, err := fetchGitHubIssues(context.Background(), "octo/private", "open")
+	if err == nil || !strings.Contains(err.Error(), "GH_TOKEN") {
+		t.Errorf("error = %v, want a hint to set GH_TOKEN", err)
+	}
+}
+
+func TestReadGitHubIssues_GHExport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "issues.json")
+	export := `[{"number": 4, "title": "Export", "state": "CLOSED", "stateReason": "NOT_PLANNED",
+		"author": {"login": "hubot"}, "createdAt": "2024-01-02T03:04:05Z",
+		"url": "https://github.com/octo/repo/issues/4", "labels": [{"name": "enhancement"}]}]`
+	if err := os.WriteFile(path, []byte(export), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := readGitHubIssues(path, "octo/repo")
+	if err != nil {
+		t.Fatalf("readGitHubIssues() error = %v", err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("got %d issues, want 1", len(issues))
+	}
+	issue := issues[0]
+	if issue.State != "closed" || issue.StateReason != "not_planned" {
+		t.Errorf("state = %q (%q), want closed (not_planned)", issue.State, issue.StateReason)
+	}
+	if issue.User == nil || issue.User.Login != "hubot" {
+		t.Errorf("User = %+v, want hubot", issue.User)
+	}
+	if issue.CreatedAt.IsZero() {
+		t.Error("CreatedAt not read from createdAt")
+	}
+	if issue.HTMLURL != "https://github.com/octo/repo/issues/4" {
+		t.Errorf("HTMLURL = %q", issue.HTMLURL)
+	}
+}
@@ -41,6 +41,8 @@ their lifecycle.`,
 	cmd.AddCommand(NewCmdClose(streams))
 	cmd.AddCommand(NewCmdReopen(streams))
 	cmd.AddCommand(NewCmdDelete(streams))
+	cmd.AddCommand(NewCmdImport(streams))
+	cmd.AddCommand(NewCmdStats(streams))
 
 	return cmd
 }
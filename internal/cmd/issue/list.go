@@ -3,7 +3,7 @@ package issue
 import (
 	"context"
 	"fmt"
-	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -18,9 +18,14 @@ type ListOptions struct {
 	Kind     string
 	Priority string
 	Assignee string
+	Filter   string
 	Limit    int
 	JSON     bool
+	Format   string
 	Repo     string
+	Fields   []string
+	Watch    time.Duration
+	Web      bool
 	Streams  *iostreams.IOStreams
 }
 
@@ -36,7 +41,8 @@ func NewCmdList(streams *iostreams.IOStreams) *cobra.Command {
 		Long: `List issues in a Bitbucket repository.
 
 By default, this shows all issues. Use flags to filter by state, kind,
-priority, or assignee.`,
+priority, or assignee, or --filter to apply a query saved with
+'bb filter save'. The flags are combined with a saved filter using AND.`,
 		Example: `  # List all issues
   bb issue list
 
@@ -52,16 +58,47 @@ priority, or assignee.`,
   # List issues assigned to a user
   bb issue list --assignee johndoe
 
+  # Apply a saved filter
+  bb issue list --filter my-bugs
+
   # Limit results
   bb issue list --limit 10
 
   # Output as JSON
   bb issue list --json
 
+  # Output as YAML
+  bb issue list --format yaml
+
   # List issues in a specific repository
-  bb issue list --repo workspace/repo`,
+  bb issue list --repo workspace/repo
+
+  # Choose and order the table columns
+  bb issue list --fields id,title,reporter,votes
+
+  # Refresh the list every 30 seconds
+  bb issue list --watch=30s
+
+  # Open the issues page in the browser
+  bb issue list --web`,
 		Aliases: []string{"ls"},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Web {
+				workspace, repoSlug, err := cmdutil.ParseRepository(opts.Repo)
+				if err != nil {
+					return err
+				}
+				url, err := cmdutil.RepoWebURL(cmd.Context(), workspace, repoSlug, "issues")
+				if err != nil {
+					return err
+				}
+				return cmdutil.OpenInBrowser(opts.Streams, url)
+			}
+			if opts.Watch > 0 {
+				return cmdutil.Watch(cmd.Context(), opts.Streams, opts.Watch, func() error {
+					return runList(cmd.Context(), opts)
+				})
+			}
 			return runList(cmd.Context(), opts)
 		},
 	}
@@ -70,9 +107,14 @@ priority, or assignee.`,
 	cmd.Flags().StringVarP(&opts.Kind, "kind", "k", "", "Filter by kind (bug, enhancement, proposal, task)")
 	cmd.Flags().StringVarP(&opts.Priority, "priority", "p", "", "Filter by priority (trivial, minor, major, critical, blocker)")
 	cmd.Flags().StringVarP(&opts.Assignee, "assignee", "a", "", "Filter by assignee username")
+	cmdutil.AddFilterFlag(cmd, &opts.Filter)
 	cmd.Flags().IntVarP(&opts.Limit, "limit", "l", 30, "Maximum number of issues to list")
 	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
+	cmdutil.AddFieldsFlag(cmd, &opts.Fields, listColumns(streams))
+	cmdutil.AddWatchFlag(cmd, &opts.Watch)
 	cmd.Flags().StringVar(&opts.Repo, "repo", "", "Repository in WORKSPACE/REPO format")
+	cmdutil.AddWebFlag(cmd, &opts.Web, "issues page")
 
 	return cmd
 }
@@ -90,6 +132,11 @@ func runList(ctx context.Context, opts *ListOptions) error {
 		return err
 	}
 
+	columns, err := cmdutil.SelectColumns("issue list", opts.Fields, listColumns(opts.Streams), defaultListFields)
+	if err != nil {
+		return err
+	}
+
 	// Build list options
 	listOpts := &api.IssueListOptions{
 		State:    opts.State,
@@ -98,9 +145,16 @@ func runList(ctx context.Context, opts *ListOptions) error {
 		Assignee: opts.Assignee,
 		Limit:    opts.Limit,
 	}
+	if opts.Filter != "" {
+		if listOpts.Q, err = cmdutil.SavedFilter(ctx, client, opts.Filter); err != nil {
+			return err
+		}
+	}
 
 	// Fetch issues
+	progress := opts.Streams.StartProgress("Fetching issues")
 	result, err := client.ListIssues(ctx, workspace, repoSlug, listOpts)
+	progress.Stop()
 	if err != nil {
 		return fmt.Errorf("failed to list issues: %w", err)
 	}
@@ -110,16 +164,21 @@ func runList(ctx context.Context, opts *ListOptions) error {
 		return nil
 	}
 
+	if err := opts.Streams.StartPager(); err != nil {
+		opts.Streams.Warning("%s", err)
+	}
+	defer opts.Streams.StopPager()
+
 	// Output results
-	if opts.JSON {
-		return outputListJSON(opts.Streams, result.Values)
+	if opts.JSON || opts.Format != "" {
+		return outputListStructured(opts.Streams, opts.Format, result.Values)
 	}
 
-	return outputIssueTable(opts.Streams, result.Values)
+	return cmdutil.PrintColumns(opts.Streams, columns, result.Values)
 }
 
-func outputListJSON(streams *iostreams.IOStreams, issues []api.Issue) error {
-	// Create simplified JSON output
+func outputListStructured(streams *iostreams.IOStreams, format string, issues []api.Issue) error {
+	// Create simplified output
 	output := make([]map[string]interface{}, len(issues))
 	for i, issue := range issues {
 		output[i] = map[string]interface{}{
@@ -139,29 +198,45 @@ func outputListJSON(streams *iostreams.IOStreams, issues []api.Issue) error {
 		}
 	}
 
-	return cmdutil.PrintJSON(streams, output)
+	return cmdutil.PrintFormatted(streams, format, output)
 }
 
-func outputIssueTable(streams *iostreams.IOStreams, issues []api.Issue) error {
-	w := tabwriter.NewWriter(streams.Out, 0, 0, 2, ' ', 0)
-
-	// Print header
-	header := "#\tTITLE\tSTATE\tKIND\tPRIORITY\tASSIGNEE\tUPDATED"
-	cmdutil.PrintTableHeader(streams, w, header)
-
-	// Print rows
-	for _, issue := range issues {
-		id := fmt.Sprintf("%d", issue.ID)
-		title := cmdutil.TruncateString(issue.Title, 40)
-		state := formatIssueState(streams, issue.State)
-		kind := formatIssueKind(streams, issue.Kind)
-		priority := formatIssuePriority(streams, issue.Priority)
-		assignee := cmdutil.TruncateString(cmdutil.GetUserDisplayName(issue.Assignee), 15)
-		updated := cmdutil.TimeAgo(issue.UpdatedOn)
-
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
-			id, title, state, kind, priority, assignee, updated)
+// defaultListFields are the columns shown when neither --fields nor the
+// fields.issue.list config key is set
+var defaultListFields = []string{"id", "title", "state", "kind", "priority", "assignee", "updated"}
+
+// listColumns returns the columns available to issue list --fields
+func listColumns(streams *iostreams.IOStreams) []cmdutil.Column[api.Issue] {
+	return []cmdutil.Column[api.Issue]{
+		{Name: "id", Header: "#", Value: func(issue api.Issue) string {
+			return fmt.Sprintf("%d", issue.ID)
+		}},
+		{Name: "title", Header: "TITLE", Value: func(issue api.Issue) string {
+			return issue.Title
+		}},
+		{Name: "state", Header: "STATE", Value: func(issue api.Issue) string {
+			return formatIssueState(streams, issue.State)
+		}},
+		{Name: "kind", Header: "KIND", Value: func(issue api.Issue) string {
+			return formatIssueKind(streams, issue.Kind)
+		}},
+		{Name: "priority", Header: "PRIORITY", Value: func(issue api.Issue) string {
+			return formatIssuePriority(streams, issue.Priority)
+		}},
+		{Name: "assignee", Header: "ASSIGNEE", Value: func(issue api.Issue) string {
+			return cmdutil.GetUserDisplayName(issue.Assignee)
+		}},
+		{Name: "reporter", Header: "REPORTER", Value: func(issue api.Issue) string {
+			return cmdutil.GetUserDisplayName(issue.Reporter)
+		}},
+		{Name: "votes", Header: "VOTES", Value: func(issue api.Issue) string {
+			return fmt.Sprintf("%d", issue.Votes)
+		}},
+		{Name: "created", Header: "CREATED", Value: func(issue api.Issue) string {
+			return cmdutil.FormatTime(streams, issue.CreatedOn)
+		}},
+		{Name: "updated", Header: "UPDATED", Value: func(issue api.Issue) string {
+			return cmdutil.FormatTime(streams, issue.UpdatedOn)
+		}},
 	}
-
-	return w.Flush()
 }
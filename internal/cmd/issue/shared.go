@@ -1,12 +1,10 @@
 package issue
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"os"
 	"strconv"
 	"strings"
 
@@ -41,15 +39,15 @@ func formatIssueState(streams *iostreams.IOStreams, state string) string {
 
 	switch strings.ToLower(state) {
 	case "new":
-		return iostreams.Cyan + state + iostreams.Reset
+		return streams.Style(iostreams.RoleInfo, state)
 	case "open":
-		return iostreams.Green + state + iostreams.Reset
+		return streams.Style(iostreams.RoleSuccess, state)
 	case "resolved", "closed":
-		return iostreams.Magenta + state + iostreams.Reset
+		return streams.Style(iostreams.RoleAccent, state)
 	case "on hold":
-		return iostreams.Yellow + state + iostreams.Reset
+		return streams.Style(iostreams.RoleWarning, state)
 	case "invalid", "duplicate", "wontfix":
-		return iostreams.Red + state + iostreams.Reset
+		return streams.Style(iostreams.RoleError, state)
 	default:
 		return state
 	}
@@ -63,13 +61,13 @@ func formatIssuePriority(streams *iostreams.IOStreams, priority string) string {
 
 	switch strings.ToLower(priority) {
 	case "blocker", "critical":
-		return iostreams.BoldRed + priority + iostreams.Reset
+		return streams.Style(iostreams.RoleHeader, streams.Style(iostreams.RoleError, priority))
 	case "major":
-		return iostreams.Red + priority + iostreams.Reset
+		return streams.Style(iostreams.RoleError, priority)
 	case "minor":
-		return iostreams.Yellow + priority + iostreams.Reset
+		return streams.Style(iostreams.RoleWarning, priority)
 	case "trivial":
-		return iostreams.Green + priority + iostreams.Reset
+		return streams.Style(iostreams.RoleSuccess, priority)
 	default:
 		return priority
 	}
@@ -77,45 +75,30 @@ func formatIssuePriority(streams *iostreams.IOStreams, priority string) string {
 
 // formatIssueKind formats issue kind with color
 func formatIssueKind(streams *iostreams.IOStreams, kind string) string {
-	if !streams.ColorEnabled() {
-		return kind
-	}
-
 	switch strings.ToLower(kind) {
 	case "bug":
-		return iostreams.Red + kind + iostreams.Reset
+		return streams.Style(iostreams.RoleError, streams.Icon(iostreams.IconBug, kind))
 	case "enhancement":
-		return iostreams.Blue + kind + iostreams.Reset
+		return streams.Style(iostreams.RoleInfo, streams.Icon(iostreams.IconEnhancement, kind))
 	case "proposal":
-		return iostreams.Cyan + kind + iostreams.Reset
+		return streams.Style(iostreams.RoleInfo, streams.Icon(iostreams.IconProposal, kind))
 	case "task":
-		return iostreams.Green + kind + iostreams.Reset
+		return streams.Style(iostreams.RoleSuccess, streams.Icon(iostreams.IconTask, kind))
 	default:
 		return kind
 	}
 }
 
-// confirmPrompt prompts the user with a yes/no question and returns true if they confirm
-func confirmPrompt(reader io.Reader) bool {
-	scanner := bufio.NewScanner(reader)
-	if scanner.Scan() {
-		input := strings.TrimSpace(strings.ToLower(scanner.Text()))
-		return input == "y" || input == "yes"
-	}
-	return false
-}
+// editorHint is shown at the bottom of the editor template
+const editorHint = "HTML comments are ignored. Save an empty title and body to cancel."
 
 // promptForTitle prompts the user to enter a title
 func promptForTitle(streams *iostreams.IOStreams) (string, error) {
-	fmt.Fprint(streams.Out, "Title: ")
-
-	reader := bufio.NewReader(os.Stdin)
-	title, err := reader.ReadString('\n')
-	if err != nil {
-		return "", err
+	title, err := streams.PromptText("Title", "")
+	if errors.Is(err, iostreams.ErrNoPrompt) {
+		return "", fmt.Errorf("--title flag is required when not running interactively")
 	}
-
-	return strings.TrimSpace(title), nil
+	return title, err
 }
 
 // resolveUserUUID resolves a username to a UUID
@@ -0,0 +1,297 @@
+package issue
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// topAssigneeCount is how many assignees the report lists
+const topAssigneeCount = 10
+
+// openStates are the issue states that count as open; the others count as
+// closed
+var openStates = []string{"new", "open", "on hold"}
+
+// statsGroupings are the fields issue stats --by can break issues down by
+var statsGroupings = []string{"kind", "priority", "assignee"}
+
+// ageBuckets are the ranges the ages of open issues are counted in, each
+// up to its bound
+var ageBuckets = []struct {
+	Label string
+	Max   time.Duration
+}{
+	{"< 1 week", 7 * 24 * time.Hour},
+	{"1-4 weeks", 28 * 24 * time.Hour},
+	{"1-3 months", 90 * 24 * time.Hour},
+	{"3-12 months", 365 * 24 * time.Hour},
+	{"> 1 year", 0},
+}
+
+// StatsOptions holds the options for the stats command
+type StatsOptions struct {
+	Since   string
+	By      string
+	JSON    bool
+	Format  string
+	Repo    string
+	Streams *iostreams.IOStreams
+}
+
+// issueStats is the report of issue stats
+type issueStats struct {
+	Repository string    `json:"repository"`
+	Since      time.Time `json:"since"`
+	// Opened and Closed are the issues created and closed since Since.
+	// An issue counts as closed when it is in a closed state and was last
+	// updated since Since, as the API doesn't record when it was closed.
+	Opened    int          `json:"opened"`
+	Closed    int          `json:"closed"`
+	Open      int          `json:"open"`
+	By        string       `json:"by"`
+	Groups    []statsGroup `json:"groups"`
+	Ages      []ageCount   `json:"ages"`
+	Assignees []statsGroup `json:"top_assignees"`
+}
+
+// statsGroup counts the issues with one value of the field issues are
+// broken down by
+type statsGroup struct {
+	Name   string `json:"name"`
+	Opened int    `json:"opened"`
+	Closed int    `json:"closed"`
+	Open   int    `json:"open"`
+}
+
+// ageCount is the number of open issues in an age range
+type ageCount struct {
+	Age   string `json:"age"`
+	Count int    `json:"count"`
+}
+
+// NewCmdStats creates the issue stats command
+func NewCmdStats(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &StatsOptions{
+		Streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show issue statistics for a repository",
+		Long: `Show how many issues were opened and closed in a period, broken down by
+kind, priority or assignee, how long the open issues have been open, and
+who has the most open issues assigned.
+
+An issue counts as closed in the period when it is resolved, invalid, a
+duplicate, won't be fixed or closed, and was last updated in the period,
+as Bitbucket doesn't record when an issue was closed.
+
+--since takes a duration, such as 90d or 12w, or a date, such as
+2024-05-01.`,
+		Example: `  # Show the last 90 days of issues
+  bb issue stats
+
+  # Break the last 30 days down by priority
+  bb issue stats --since 30d --by priority
+
+  # Output as JSON
+  bb issue stats --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !slices.Contains(statsGroupings, opts.By) {
+				return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("invalid --by value %q: use kind, priority or assignee", opts.By))
+			}
+			return runStats(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Since, "since", "90d", "Count issues opened and closed since a duration ago or a date")
+	cmd.Flags().StringVar(&opts.By, "by", "kind", "Break issues down by {kind|priority|assignee}")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
+	cmd.Flags().StringVar(&opts.Repo, "repo", "", "Repository in WORKSPACE/REPO format")
+
+	_ = cmd.RegisterFlagCompletionFunc("by", cobra.FixedCompletions(statsGroupings, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+func runStats(ctx context.Context, opts *StatsOptions) error {
+	now := time.Now()
+	since, err := cmdutil.ParseTimeFlag("--since", opts.Since, now)
+	if err != nil {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, err)
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.Repo)
+	if err != nil {
+		return err
+	}
+
+	// The issues updated in the period include those opened and closed in
+	// it; the open ones are needed for their ages
+	q := fmt.Sprintf("updated_on >= %s", since.UTC().Format(time.RFC3339))
+	for _, state := range openStates {
+		q += fmt.Sprintf(" OR state = %s", api.QuoteQueryValue(state))
+	}
+
+	progress := opts.Streams.StartProgress("Fetching issues")
+	issues, err := client.ListAllIssues(ctx, workspace, repoSlug, &api.IssueListOptions{Q: q})
+	progress.Stop()
+	if err != nil {
+		return fmt.Errorf("failed to list issues: %w", err)
+	}
+
+	stats := computeStats(issues, opts.By, since, now)
+	stats.Repository = workspace + "/" + repoSlug
+
+	if opts.JSON || opts.Format != "" {
+		return cmdutil.PrintFormatted(opts.Streams, opts.Format, stats)
+	}
+	return printStats(opts.Streams, stats)
+}
+
+// computeStats aggregates issues into the report of the period from since
+// to now, breaking them down by the field by
+func computeStats(issues []api.Issue, by string, since, now time.Time) *issueStats {
+	stats := &issueStats{Since: since, By: by, Ages: make([]ageCount, len(ageBuckets))}
+	for i, bucket := range ageBuckets {
+		stats.Ages[i].Age = bucket.Label
+	}
+
+	groups := map[string]*statsGroup{}
+	assignees := map[string]*statsGroup{}
+	count := func(counts map[string]*statsGroup, name string) *statsGroup {
+		if counts[name] == nil {
+			counts[name] = &statsGroup{Name: name}
+		}
+		return counts[name]
+	}
+
+	for _, issue := range issues {
+		open := slices.Contains(openStates, issue.State)
+		opened := !issue.CreatedOn.Before(since)
+		closed := !open && !issue.UpdatedOn.Before(since)
+		if !open && !opened && !closed {
+			continue
+		}
+
+		assignee := cmdutil.GetUserDisplayName(issue.Assignee)
+		name := assignee
+		switch by {
+		case "kind":
+			name = issue.Kind
+		case "priority":
+			name = issue.Priority
+		}
+		group := count(groups, name)
+
+		if opened {
+			stats.Opened++
+			group.Opened++
+		}
+		if closed {
+			stats.Closed++
+			group.Closed++
+		}
+		if open {
+			stats.Open++
+			group.Open++
+			stats.Ages[ageBucket(now.Sub(issue.CreatedOn))].Count++
+			if issue.Assignee != nil {
+				count(assignees, assignee).Open++
+			}
+		}
+	}
+
+	stats.Groups = sortedGroups(groups, func(a, b statsGroup) int {
+		return cmp.Compare(b.Opened+b.Open, a.Opened+a.Open)
+	})
+	stats.Assignees = sortedGroups(assignees, func(a, b statsGroup) int {
+		return cmp.Compare(b.Open, a.Open)
+	})
+	if len(stats.Assignees) > topAssigneeCount {
+		stats.Assignees = stats.Assignees[:topAssigneeCount]
+	}
+	return stats
+}
+
+// sortedGroups returns the groups of counts ordered by compare, then by name
+func sortedGroups(counts map[string]*statsGroup, compare func(a, b statsGroup) int) []statsGroup {
+	groups := make([]statsGroup, 0, len(counts))
+	for _, g := range counts {
+		groups = append(groups, *g)
+	}
+	slices.SortFunc(groups, func(a, b statsGroup) int {
+		return cmp.Or(compare(a, b), cmp.Compare(a.Name, b.Name))
+	})
+	return groups
+}
+
+// ageBucket returns the index of the age range age falls in
+func ageBucket(age time.Duration) int {
+	for i, bucket := range ageBuckets {
+		if age < bucket.Max {
+			return i
+		}
+	}
+	return len(ageBuckets) - 1
+}
+
+func printStats(streams *iostreams.IOStreams, stats *issueStats) error {
+	fmt.Fprintln(streams.Out, streams.Style(iostreams.RoleHeader,
+		fmt.Sprintf("Issues in %s since %s", stats.Repository, stats.Since.Format("January 2, 2006"))))
+	fmt.Fprintf(streams.Out, "Opened: %d  Closed: %d  Open now: %d\n", stats.Opened, stats.Closed, stats.Open)
+
+	if len(stats.Groups) > 0 {
+		fmt.Fprintln(streams.Out)
+		table := cmdutil.NewTablePrinter(streams)
+		table.AddHeader(strings.ToUpper(stats.By), "OPENED", "CLOSED", "OPEN")
+		for _, g := range stats.Groups {
+			table.AddRow(g.Name, fmt.Sprintf("%d", g.Opened), fmt.Sprintf("%d", g.Closed), fmt.Sprintf("%d", g.Open))
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+	}
+
+	if stats.Open > 0 {
+		fmt.Fprintln(streams.Out)
+		table := cmdutil.NewTablePrinter(streams)
+		table.AddHeader("AGE OF OPEN ISSUES", "COUNT")
+		for _, a := range stats.Ages {
+			table.AddRow(a.Age, fmt.Sprintf("%d", a.Count))
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+	}
+
+	if len(stats.Assignees) > 0 {
+		fmt.Fprintln(streams.Out)
+		table := cmdutil.NewTablePrinter(streams)
+		table.AddHeader("TOP ASSIGNEES", "OPEN")
+		for _, g := range stats.Assignees {
+			table.AddRow(g.Name, fmt.Sprintf("%d", g.Open))
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package issue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+func TestComputeStats(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	since := now.AddDate(0, 0, -90)
+	alice := &api.User{DisplayName: "Alice"}
+	bob := &api.User{DisplayName: "Bob"}
+
+	issues := []api.Issue{
+		// Opened in the period and still open
+		{ID: 1, State: "new", Kind: "bug", Assignee: alice, CreatedOn: now.AddDate(0, 0, -2), UpdatedOn: now},
+		// Opened and closed in the period
+		{ID: 2, State: "resolved", Kind: "bug", Assignee: bob, CreatedOn: now.AddDate(0, 0, -20), UpdatedOn: now.AddDate(0, 0, -1)},
+		// Opened before the period and closed in it
+		{ID: 3, State: "closed", Kind: "task", CreatedOn: now.AddDate(-1, 0, 0), UpdatedOn: now.AddDate(0, 0, -5)},
+		// Open since before the period
+		{ID: 4, State: "on hold", Kind: "task", Assignee: alice, CreatedOn: now.AddDate(-2, 0, 0), UpdatedOn: now.AddDate(-1, 0, 0)},
+		// Closed before the period
+		{ID: 5, State: "wontfix", Kind: "proposal", CreatedOn: now.AddDate(-1, 0, 0), UpdatedOn: now.AddDate(0, -6, 0)},
+	}
+
+	stats := computeStats(issues, "kind", since, now)
+
+	if stats.Opened != 2 || stats.Closed != 2 || stats.Open != 2 {
+		t.Errorf("opened, closed, open = %d, %d, %d, want 2, 2, 2", stats.Opened, stats.Closed, stats.Open)
+	}
+
+	wantGroups := []statsGroup{
+		{Name: "bug", Opened: 2, Closed: 1, Open: 1},
+		{Name: "task", Opened: 0, Closed: 1, Open: 1},
+	}
+	if len(stats.Groups) != len(wantGroups) {
+		t.Fatalf("groups = %+v, want %+v", stats.Groups, wantGroups)
+	}
+	for i, want := range wantGroups {
+		if stats.Groups[i] != want {
+			t.Errorf("groups[%d] = %+v, want %+v", i, stats.Groups[i], want)
+		}
+	}
+
+	wantAges := []int{1, 0, 0, 0, 1}
+	for i, want := range wantAges {
+		if stats.Ages[i].Count != want {
+			t.Errorf("ages[%s] = %d, want %d", stats.Ages[i].Age, stats.Ages[i].Count, want)
+		}
+	}
+
+	if len(stats.Assignees) != 1 || stats.Assignees[0] != (statsGroup{Name: "Alice", Open: 2}) {
+		t.Errorf("top assignees = %+v, want only Alice with 2 open", stats.Assignees)
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/rbansal42/bitbucket-cli/internal/api"
-	"github.com/rbansal42/bitbucket-cli/internal/browser"
 	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
@@ -17,8 +16,11 @@ type viewOptions struct {
 	streams  *iostreams.IOStreams
 	repo     string
 	web      bool
+	copy     bool
 	comments bool
 	jsonOut  bool
+	format   string
+	raw      bool
 }
 
 // NewCmdView creates the issue view command
@@ -46,6 +48,12 @@ content, and other metadata. Use --comments to also show comments.`,
   # Output as JSON
   bb issue view 123 --json
 
+  # Output as YAML
+  bb issue view 123 --format yaml
+
+  # Show content as written, without markdown rendering
+  bb issue view 123 --raw
+
   # View issue in a specific repository
   bb issue view 123 --repo workspace/repo`,
 		Args: cobra.ExactArgs(1),
@@ -54,9 +62,12 @@ content, and other metadata. Use --comments to also show comments.`,
 		},
 	}
 
-	cmd.Flags().BoolVarP(&opts.web, "web", "w", false, "Open the issue in a web browser")
+	cmdutil.AddWebFlag(cmd, &opts.web, "issue")
+	cmdutil.AddCopyFlag(cmd, &opts.copy, "issue")
 	cmd.Flags().BoolVarP(&opts.comments, "comments", "c", false, "Show issue comments")
 	cmd.Flags().BoolVar(&opts.jsonOut, "json", false, "Output in JSON format")
+	cmd.Flags().BoolVar(&opts.raw, "raw", false, "Show content and comments without markdown rendering")
+	cmdutil.AddFormatFlag(cmd, &opts.format)
 	cmd.Flags().StringVar(&opts.repo, "repo", "", "Repository in WORKSPACE/REPO format")
 
 	return cmd
@@ -90,37 +101,39 @@ func runView(opts *viewOptions, args []string) error {
 		return fmt.Errorf("failed to get issue: %w", err)
 	}
 
-	// Handle --web flag
-	if opts.web {
-		if issue.Links != nil && issue.Links.HTML != nil {
-			if err := browser.Open(issue.Links.HTML.Href); err != nil {
-				return fmt.Errorf("could not open browser: %w", err)
-			}
-			opts.streams.Success("Opened %s in your browser", issue.Links.HTML.Href)
-			return nil
+	// Handle --copy and --web flags
+	var url string
+	if issue.Links != nil && issue.Links.HTML != nil {
+		url = issue.Links.HTML.Href
+	}
+	if opts.copy {
+		if err := cmdutil.CopyURL(opts.streams, url); err != nil {
+			return err
 		}
-		return fmt.Errorf("no URL available for this issue")
+	}
+	if opts.web {
+		return cmdutil.OpenInBrowser(opts.streams, url)
 	}
 
 	// Fetch comments if requested
 	var comments []api.IssueComment
-	if opts.comments || opts.jsonOut {
+	if opts.comments || opts.jsonOut || opts.format != "" {
 		commentsResult, err := client.ListIssueComments(ctx, workspace, repoSlug, issueID)
 		if err == nil {
 			comments = commentsResult.Values
 		}
 	}
 
-	// Handle --json flag
-	if opts.jsonOut {
-		return outputViewJSON(opts.streams, issue, comments)
+	// Handle --json and --format flags
+	if opts.jsonOut || opts.format != "" {
+		return outputViewStructured(opts.streams, opts.format, issue, comments)
 	}
 
 	// Display formatted output
-	return displayIssue(opts.streams, issue, comments, opts.comments)
+	return displayIssue(opts.streams, issue, comments, opts.comments, opts.raw)
 }
 
-func outputViewJSON(streams *iostreams.IOStreams, issue *api.Issue, comments []api.IssueComment) error {
+func outputViewStructured(streams *iostreams.IOStreams, format string, issue *api.Issue, comments []api.IssueComment) error {
 	output := map[string]interface{}{
 		"id":         issue.ID,
 		"title":      issue.Title,
@@ -158,10 +171,17 @@ func outputViewJSON(streams *iostreams.IOStreams, issue *api.Issue, comments []a
 		output["comments"] = commentList
 	}
 
-	return cmdutil.PrintJSON(streams, output)
+	return cmdutil.PrintFormatted(streams, format, output)
 }
 
-func displayIssue(streams *iostreams.IOStreams, issue *api.Issue, comments []api.IssueComment, showComments bool) error {
+func displayIssue(streams *iostreams.IOStreams, issue *api.Issue, comments []api.IssueComment, showComments, raw bool) error {
+	render := func(text string) string {
+		if raw {
+			return text
+		}
+		return cmdutil.RenderMarkdown(streams, text)
+	}
+
 	// Title with ID
 	fmt.Fprintf(streams.Out, "#%d: %s\n", issue.ID, issue.Title)
 	fmt.Fprintln(streams.Out)
@@ -186,13 +206,13 @@ func displayIssue(streams *iostreams.IOStreams, issue *api.Issue, comments []api
 	// Content/Description
 	if issue.Content != nil && issue.Content.Raw != "" {
 		fmt.Fprintln(streams.Out, "Description:")
-		fmt.Fprintln(streams.Out, issue.Content.Raw)
+		fmt.Fprintln(streams.Out, render(issue.Content.Raw))
 		fmt.Fprintln(streams.Out)
 	}
 
 	// Timestamps
-	fmt.Fprintf(streams.Out, "Created:  %s\n", cmdutil.TimeAgo(issue.CreatedOn))
-	fmt.Fprintf(streams.Out, "Updated:  %s\n", cmdutil.TimeAgo(issue.UpdatedOn))
+	fmt.Fprintf(streams.Out, "Created:  %s\n", cmdutil.FormatTime(streams, issue.CreatedOn))
+	fmt.Fprintf(streams.Out, "Updated:  %s\n", cmdutil.FormatTime(streams, issue.UpdatedOn))
 
 	// URL
 	if issue.Links != nil && issue.Links.HTML != nil {
@@ -208,16 +228,12 @@ func displayIssue(streams *iostreams.IOStreams, issue *api.Issue, comments []api
 
 		for _, comment := range comments {
 			author := cmdutil.GetUserDisplayName(comment.User)
-			timestamp := cmdutil.TimeAgo(comment.CreatedOn)
+			timestamp := cmdutil.FormatTime(streams, comment.CreatedOn)
 
-			if streams.ColorEnabled() {
-				fmt.Fprintf(streams.Out, "%s%s%s commented %s:\n", iostreams.Bold, author, iostreams.Reset, timestamp)
-			} else {
-				fmt.Fprintf(streams.Out, "%s commented %s:\n", author, timestamp)
-			}
+			fmt.Fprintf(streams.Out, "%s commented %s:\n", streams.Style(iostreams.RoleHeader, author), timestamp)
 
 			if comment.Content != nil && comment.Content.Raw != "" {
-				fmt.Fprintln(streams.Out, comment.Content.Raw)
+				fmt.Fprintln(streams.Out, render(comment.Content.Raw))
 			}
 			fmt.Fprintln(streams.Out)
 		}
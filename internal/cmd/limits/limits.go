@@ -0,0 +1,226 @@
+package limits
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// LimitsOptions holds the options for the limits command
+type LimitsOptions struct {
+	Workspace string
+	NoMinutes bool
+	JSON      bool
+	Format    string
+	Streams   *iostreams.IOStreams
+}
+
+// NewCmdLimits creates the limits command
+func NewCmdLimits(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &LimitsOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "limits",
+		Short: "Show API rate limits and pipeline build minutes used",
+		Long: `Show the API rate limit state of each host, and the pipeline build minutes
+a workspace has used this month.
+
+Rate limits are read from the headers of API responses, which bb records
+as commands run; a request is made to refresh those of the active host.
+Bitbucket Cloud reports the limit a request counted against and whether it
+is nearly used up, and Bitbucket Data Center how many requests remain.
+
+Build minutes are added up from the pipelines run since the start of the
+month in the workspace's repositories updated this month. They are the time
+steps took, before any size multiplier your plan applies. The workspace is
+given with --workspace, or else is the default workspace or that of the
+current repository.`,
+		Example: `  # Show rate limits and build minutes
+  bb limits
+
+  # Show build minutes of another workspace
+  bb limits --workspace myworkspace
+
+  # Show rate limits only
+  bb limits --no-minutes
+
+  # Output as JSON, e.g. for monitoring
+  bb limits --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLimits(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Workspace, "workspace", "w", "", "Workspace to show build minutes of")
+	cmd.Flags().BoolVar(&opts.NoMinutes, "no-minutes", false, "Show rate limits only")
+	cmd.MarkFlagsMutuallyExclusive("workspace", "no-minutes")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
+
+	return cmd
+}
+
+// hostRateLimit is a rate limit reported by a host
+type hostRateLimit struct {
+	Host string `json:"host"`
+	api.RateLimit
+}
+
+// limitsOutput is the structured output of the command
+type limitsOutput struct {
+	RateLimits   []hostRateLimit `json:"rate_limits"`
+	BuildMinutes *minutesUsage   `json:"build_minutes,omitempty"`
+}
+
+func runLimits(ctx context.Context, opts *LimitsOptions) error {
+	hosts, err := config.LoadHostsConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load hosts config: %w", err)
+	}
+	active, _, err := config.ActiveAccount(hosts)
+	if err != nil {
+		return err
+	}
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	// Any request refreshes the active host's rate limits
+	progress := opts.Streams.StartProgress("Checking rate limits")
+	_, probeErr := client.Get(ctx, "/user", nil)
+	progress.Stop()
+	if probeErr != nil {
+		opts.Streams.Warning("could not refresh the rate limits of %s: %s", active, probeErr)
+	}
+
+	var output limitsOutput
+	for _, host := range hostNames(hosts, active) {
+		for _, rl := range cmdutil.LoadRateLimits(host) {
+			output.RateLimits = append(output.RateLimits, hostRateLimit{Host: host, RateLimit: rl})
+		}
+	}
+
+	if !opts.NoMinutes {
+		if workspace := minutesWorkspace(opts.Workspace); workspace != "" {
+			progress := opts.Streams.StartProgress("Adding up build minutes")
+			usage, err := buildMinutes(ctx, client, workspace, monthStart(time.Now()))
+			progress.Stop()
+			if err != nil {
+				return err
+			}
+			output.BuildMinutes = usage
+		} else {
+			opts.Streams.Warning("no workspace to show build minutes of; use --workspace")
+		}
+	}
+
+	if opts.JSON || opts.Format != "" {
+		return cmdutil.PrintFormatted(opts.Streams, opts.Format, output)
+	}
+	if err := printRateLimits(opts.Streams, output.RateLimits); err != nil {
+		return err
+	}
+	if output.BuildMinutes != nil {
+		fmt.Fprintln(opts.Streams.Out)
+		return printMinutes(opts.Streams, output.BuildMinutes)
+	}
+	return nil
+}
+
+// hostNames lists the hosts in hosts.yml, and the active one, sorted
+func hostNames(hosts config.HostsConfig, active string) []string {
+	names := []string{active}
+	for host := range hosts {
+		if host != active {
+			names = append(names, host)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// minutesWorkspace returns the workspace to add up build minutes of: the
+// given one, the default workspace, or that of the current repository
+func minutesWorkspace(workspace string) string {
+	if workspace != "" {
+		return workspace
+	}
+	if workspace, err := config.GetDefaultWorkspace(); err == nil && workspace != "" {
+		return workspace
+	}
+	if workspace, _, err := cmdutil.ParseRepository(""); err == nil {
+		return workspace
+	}
+	return ""
+}
+
+func printRateLimits(streams *iostreams.IOStreams, limits []hostRateLimit) error {
+	fmt.Fprintln(streams.Out, streams.Style(iostreams.RoleHeader, "API rate limits"))
+	if len(limits) == 0 {
+		fmt.Fprintln(streams.Out, "No rate limits reported by any host in the last day")
+		return nil
+	}
+
+	table := cmdutil.NewTablePrinter(streams)
+	table.AddHeader("HOST", "RESOURCE", "LIMIT", "REMAINING", "STATUS", "OBSERVED")
+	for _, l := range limits {
+		table.AddRow(
+			l.Host,
+			valueOrDash(l.Resource),
+			formatLimit(l.RateLimit),
+			formatCount(l.Remaining),
+			formatStatus(streams, l.RateLimit),
+			cmdutil.FormatTime(streams, l.ObservedAt),
+		)
+	}
+	return table.Render()
+}
+
+// formatLimit shows the limit with the period it applies to, which is an
+// hour on Bitbucket Cloud unless a host says otherwise
+func formatLimit(rl api.RateLimit) string {
+	if rl.Limit <= 0 {
+		return "-"
+	}
+	if rl.Interval > 0 {
+		return fmt.Sprintf("%d/%s", rl.Limit, rl.Interval)
+	}
+	return fmt.Sprintf("%d/hour", rl.Limit)
+}
+
+func formatStatus(streams *iostreams.IOStreams, rl api.RateLimit) string {
+	switch {
+	case rl.Limited && rl.RetryAfter > 0:
+		return streams.Style(iostreams.RoleError, fmt.Sprintf("limited, retry after %s", rl.RetryAfter))
+	case rl.Limited:
+		return streams.Style(iostreams.RoleError, "limited")
+	case rl.NearLimit:
+		return streams.Style(iostreams.RoleWarning, "near limit")
+	}
+	return streams.Style(iostreams.RoleSuccess, "ok")
+}
+
+func formatCount(n int) string {
+	if n < 0 {
+		return "-"
+	}
+	return strconv.Itoa(n)
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
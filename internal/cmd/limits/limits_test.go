@@ -0,0 +1,73 @@
+package limits
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+func TestMonthStart(t *testing.T) {
+	now := time.Date(2024, 5, 17, 23, 30, 0, 0, time.FixedZone("UTC-5", -5*3600))
+	if got, want := monthStart(now), time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("monthStart() = %v, want %v", got, want)
+	}
+}
+
+func TestFormatLimit(t *testing.T) {
+	tests := []struct {
+		rl   api.RateLimit
+		want string
+	}{
+		{api.RateLimit{Remaining: -1}, "-"},
+		{api.RateLimit{Limit: 1000, Remaining: -1}, "1000/hour"},
+		{api.RateLimit{Limit: 60, Interval: time.Second}, "60/1s"},
+	}
+	for _, tt := range tests {
+		if got := formatLimit(tt.rl); got != tt.want {
+			t.Errorf("formatLimit(%+v) = %q, want %q", tt.rl, got, tt.want)
+		}
+	}
+}
+
+func TestBuildMinutes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repositories/team":
+			w.Write([]byte(`{"values": [{"slug": "app"}, {"slug": "docs"}, {"slug": "broken"}]}`))
+		case "/repositories/team/app/pipelines":
+			w.Write([]byte(`{"values": [
+				{"build_number": 3, "created_on": "2024-05-03T10:00:00Z", "build_seconds_used": 600},
+				{"build_number": 2, "created_on": "2024-05-02T10:00:00Z", "build_seconds_used": 300},
+				{"build_number": 1, "created_on": "2024-04-30T10:00:00Z", "build_seconds_used": 6000}
+			], "next": "https://api.example.com/next"}`))
+		case "/repositories/team/docs/pipelines":
+			w.Write([]byte(`{"values": []}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"type": "error", "error": {"message": "Not found"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewClient(api.WithBaseURL(server.URL), api.WithToken("test-token"))
+	since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	usage, err := buildMinutes(context.Background(), client, "team", since)
+	if err != nil {
+		t.Fatalf("buildMinutes() error = %v", err)
+	}
+
+	if usage.Minutes != 15 {
+		t.Errorf("Minutes = %v, want 15", usage.Minutes)
+	}
+	if len(usage.Repositories) != 1 || usage.Repositories[0].Repository != "team/app" || usage.Repositories[0].Pipelines != 2 {
+		t.Errorf("unexpected repositories %+v", usage.Repositories)
+	}
+	if !usage.Partial {
+		t.Error("expected the usage to be partial, as a repository's pipelines couldn't be read")
+	}
+}
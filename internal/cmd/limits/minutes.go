@@ -0,0 +1,162 @@
+package limits
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+const (
+	// maxRepoPages and maxPipelinePages bound the pages read, of 100 items
+	// each, so a huge workspace doesn't take forever
+	maxRepoPages     = 10
+	maxPipelinePages = 10
+
+	// minutesConcurrency is how many repositories are read at once
+	minutesConcurrency = 8
+)
+
+// minutesUsage is the build time a workspace used in a period
+type minutesUsage struct {
+	Workspace    string      `json:"workspace"`
+	Since        time.Time   `json:"since"`
+	Minutes      float64     `json:"minutes"`
+	Repositories []repoUsage `json:"repositories"`
+	// Partial is set when some pipelines couldn't be read or the
+	// workspace was too large to read in full
+	Partial bool `json:"partial,omitempty"`
+}
+
+// repoUsage is the build time a repository used
+type repoUsage struct {
+	Repository string  `json:"repository"`
+	Pipelines  int     `json:"pipelines"`
+	Minutes    float64 `json:"minutes"`
+}
+
+// monthStart returns the start of the month of now, in UTC, when Bitbucket
+// Cloud resets build minutes
+func monthStart(now time.Time) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// buildMinutes adds up the build time of the pipelines run in workspace
+// since since
+func buildMinutes(ctx context.Context, client *api.Client, workspace string, since time.Time) (*minutesUsage, error) {
+	usage := &minutesUsage{Workspace: workspace, Since: since}
+
+	var repos []string
+	for page := 1; ; page++ {
+		result, err := client.ListRepositories(ctx, workspace, &api.RepositoryListOptions{
+			Query: fmt.Sprintf("updated_on >= %s", since.Format(time.RFC3339)),
+			Page:  page,
+			Limit: 100,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories: %w", err)
+		}
+		for _, r := range result.Values {
+			repos = append(repos, r.Slug)
+		}
+		if result.Next == "" {
+			break
+		}
+		if page == maxRepoPages {
+			usage.Partial = true
+			break
+		}
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		limiter = make(chan struct{}, minutesConcurrency)
+	)
+	for _, repo := range repos {
+		wg.Go(func() {
+			limiter <- struct{}{}
+			defer func() { <-limiter }()
+
+			ru, complete, err := repoMinutes(ctx, client, workspace, repo, since)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil || !complete {
+				usage.Partial = true
+			}
+			if ru.Pipelines > 0 {
+				usage.Repositories = append(usage.Repositories, ru)
+				usage.Minutes += ru.Minutes
+			}
+		})
+	}
+	wg.Wait()
+
+	slices.SortFunc(usage.Repositories, func(a, b repoUsage) int {
+		return cmp.Or(cmp.Compare(b.Minutes, a.Minutes), strings.Compare(a.Repository, b.Repository))
+	})
+	return usage, nil
+}
+
+// repoMinutes adds up the build time of the pipelines of a repository
+// created since since, and reports whether it read all of them
+func repoMinutes(ctx context.Context, client *api.Client, workspace, repoSlug string, since time.Time) (repoUsage, bool, error) {
+	ru := repoUsage{Repository: workspace + "/" + repoSlug}
+	seconds := 0
+	for page := 1; page <= maxPipelinePages; page++ {
+		result, err := client.ListPipelines(ctx, workspace, repoSlug, &api.PipelineListOptions{
+			Sort:  "-created_on",
+			Page:  page,
+			Limit: 100,
+		})
+		if err != nil {
+			return ru, false, err
+		}
+		for _, p := range result.Values {
+			if p.CreatedOn.Before(since) {
+				ru.Minutes = float64(seconds) / 60
+				return ru, true, nil
+			}
+			ru.Pipelines++
+			seconds += p.BuildSecondsUsed
+		}
+		if result.Next == "" {
+			ru.Minutes = float64(seconds) / 60
+			return ru, true, nil
+		}
+	}
+	ru.Minutes = float64(seconds) / 60
+	return ru, false, nil
+}
+
+func printMinutes(streams *iostreams.IOStreams, usage *minutesUsage) error {
+	fmt.Fprintln(streams.Out, streams.Style(iostreams.RoleHeader,
+		fmt.Sprintf("Build minutes used by %s since %s", usage.Workspace, usage.Since.Format("January 2"))))
+	if len(usage.Repositories) > 0 {
+		table := cmdutil.NewTablePrinter(streams)
+		table.AddHeader("REPOSITORY", "PIPELINES", "MINUTES")
+		for _, ru := range usage.Repositories {
+			table.AddRow(ru.Repository, fmt.Sprintf("%d", ru.Pipelines), formatMinutes(ru.Minutes))
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(streams.Out, "Total: %s minutes\n", formatMinutes(usage.Minutes))
+	if usage.Partial {
+		fmt.Fprintln(streams.Out, streams.Style(iostreams.RoleMuted, "Some pipelines could not be read, so the total may be low"))
+	}
+	return nil
+}
+
+func formatMinutes(minutes float64) string {
+	return fmt.Sprintf("%.0f", minutes)
+}
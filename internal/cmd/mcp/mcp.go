@@ -0,0 +1,26 @@
+package mcp
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// NewCmdMCP creates the mcp command and its subcommands
+func NewCmdMCP(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mcp <command>",
+		Short: "Serve bb to AI assistants over the Model Context Protocol",
+		Long: `Let AI assistants work with Bitbucket through bb.
+
+"bb mcp serve" runs a Model Context Protocol (MCP) server that offers
+pull requests, issues and pipelines as tools. Requests are made with the
+credentials bb is signed in with, so no separate token is needed.`,
+		Example: `  # Serve the tools over stdio, as started by an MCP client
+  bb mcp serve`,
+	}
+
+	cmd.AddCommand(NewCmdServe(streams))
+
+	return cmd
+}
@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// ServeOptions holds the options for the serve command
+type ServeOptions struct {
+	Repo     string
+	ReadOnly bool
+	Version  string
+	Streams  *iostreams.IOStreams
+}
+
+// NewCmdServe creates the mcp serve command
+func NewCmdServe(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &ServeOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run an MCP server over stdio",
+		Long: `Run a Model Context Protocol server that reads requests from stdin and
+writes responses to stdout, for MCP clients such as AI assistants and
+editors to start.
+
+The server offers these tools:
+  list_pull_requests, view_pull_request, create_pull_request
+  list_issues, view_issue, create_issue
+  list_pipelines, view_pipeline, run_pipeline
+
+Tools take the repository to work on in WORKSPACE/REPO format. Without
+one they use --repo, or else the repository of the directory the server
+was started in. Use --read-only to offer only the tools that don't change
+anything.`,
+		Example: `  # Configure an MCP client to start the server, e.g. in its JSON config:
+  #   {"command": "bb", "args": ["mcp", "serve"]}
+  bb mcp serve
+
+  # Offer only the tools that read, for one repository
+  bb mcp serve --read-only --repo myworkspace/myrepo`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Version = cmd.Root().Annotations["version"]
+			return runServe(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository tools use when a call doesn't name one")
+	cmd.Flags().BoolVar(&opts.ReadOnly, "read-only", false, "Offer only tools that don't create or change anything")
+
+	return cmd
+}
+
+func runServe(ctx context.Context, opts *ServeOptions) error {
+	// Fail at startup rather than on every call when not signed in
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	ts := &toolset{client: client, repo: opts.Repo}
+	srv := &server{version: opts.Version, tools: ts.tools(opts.ReadOnly)}
+
+	// stdout carries the protocol, so anything for the user goes to stderr
+	fmt.Fprintf(opts.Streams.ErrOut, "bb MCP server running on stdio with %d tools\n", len(srv.tools))
+	return srv.serve(ctx, opts.Streams.In, opts.Streams.Out)
+}
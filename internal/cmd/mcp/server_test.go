@@ -0,0 +1,131 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+// exchange sends each message to srv and returns the decoded responses
+func exchange(t *testing.T, srv *server, messages ...string) []map[string]any {
+	t.Helper()
+	var out strings.Builder
+	if err := srv.serve(context.Background(), strings.NewReader(strings.Join(messages, "\n")), &out); err != nil {
+		t.Fatalf("serve() error: %v", err)
+	}
+
+	var responses []map[string]any
+	scanner := bufio.NewScanner(strings.NewReader(out.String()))
+	for scanner.Scan() {
+		var resp map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid response %q: %v", scanner.Text(), err)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func TestServer_Initialize(t *testing.T) {
+	srv := &server{version: "1.2.3"}
+	responses := exchange(t, srv,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"ping"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"resources/list"}`,
+		`not json`,
+	)
+
+	// The notification gets no response
+	if len(responses) != 4 {
+		t.Fatalf("got %d responses, want 4: %v", len(responses), responses)
+	}
+
+	result := responses[0]["result"].(map[string]any)
+	if result["protocolVersion"] != "2025-03-26" {
+		t.Errorf("protocolVersion = %v, want the client's supported version", result["protocolVersion"])
+	}
+	if info := result["serverInfo"].(map[string]any); info["version"] != "1.2.3" {
+		t.Errorf("serverInfo.version = %v, want 1.2.3", info["version"])
+	}
+
+	if responses[1]["id"] != float64(2) || responses[1]["error"] != nil {
+		t.Errorf("unexpected ping response: %v", responses[1])
+	}
+
+	for i, code := range map[int]float64{2: codeMethodNotFound, 3: codeParseError} {
+		rpcErr, ok := responses[i]["error"].(map[string]any)
+		if !ok || rpcErr["code"] != code {
+			t.Errorf("response %d: error = %v, want code %v", i, responses[i]["error"], code)
+		}
+	}
+}
+
+func TestServer_ToolsListReadOnly(t *testing.T) {
+	ts := &toolset{}
+	srv := &server{tools: ts.tools(true)}
+	responses := exchange(t, srv, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+
+	tools := responses[0]["result"].(map[string]any)["tools"].([]any)
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.(map[string]any)["name"].(string))
+	}
+	got := strings.Join(names, ",")
+	if strings.Contains(got, "create_") || strings.Contains(got, "run_pipeline") {
+		t.Errorf("read-only tools include ones that make changes: %s", got)
+	}
+	if !strings.Contains(got, "list_pull_requests") || !strings.Contains(got, "view_pipeline") {
+		t.Errorf("read-only tools are missing ones that only read: %s", got)
+	}
+}
+
+func TestServer_ToolsCall(t *testing.T) {
+	bitbucket := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/team/api/pullrequests" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.URL.Query().Get("state"); got != "MERGED" {
+			t.Errorf("state = %q, want MERGED", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"values":[{"id":7,"title":"Fix parser","state":"MERGED"}]}`))
+	}))
+	defer bitbucket.Close()
+
+	ts := &toolset{client: api.NewClient(api.WithBaseURL(bitbucket.URL)), repo: "team/api"}
+	srv := &server{tools: ts.tools(false)}
+	responses := exchange(t, srv,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"list_pull_requests","arguments":{"state":"MERGED"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"list_pull_requests","arguments":{"stat":"MERGED"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"view_issue","arguments":{"number":1}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"delete_everything"}}`,
+	)
+
+	result := responses[0]["result"].(map[string]any)
+	if result["isError"] == true {
+		t.Fatalf("unexpected tool error: %v", result)
+	}
+	text := result["content"].([]any)[0].(map[string]any)["text"].(string)
+	if !strings.Contains(text, `"title": "Fix parser"`) {
+		t.Errorf("result text = %s, want the pull request", text)
+	}
+
+	// Bad arguments and API failures are reported to the model as tool
+	// errors, an unknown tool as a protocol error
+	for _, i := range []int{1, 2} {
+		if result := responses[i]["result"].(map[string]any); result["isError"] != true {
+			t.Errorf("response %d: expected a tool error, got %v", i, result)
+		}
+	}
+	if responses[3]["error"] == nil {
+		t.Errorf("expected an error for an unknown tool, got %v", responses[3])
+	}
+}
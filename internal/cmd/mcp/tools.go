@@ -0,0 +1,453 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+)
+
+// Bounds on the number of results list tools return
+const (
+	defaultListLimit = 30
+	maxListLimit     = 50
+)
+
+// toolset builds the tools, which run against client. repo is the
+// repository used when a call doesn't name one; empty means the one bb was
+// started in.
+type toolset struct {
+	client *api.Client
+	repo   string
+}
+
+// tools returns the tools offered, leaving out those that change anything
+// when readOnly is set
+func (ts *toolset) tools(readOnly bool) []tool {
+	all := []tool{
+		newTool("list_pull_requests", "List the pull requests of a repository.", true,
+			objectSchema(nil, map[string]any{
+				"repo":   repoProperty,
+				"state":  enumProperty("Pull request state (default OPEN)", "OPEN", "MERGED", "DECLINED", "SUPERSEDED"),
+				"author": stringProperty("Only pull requests by this username"),
+				"limit":  limitProperty,
+			}), ts.listPullRequests),
+		newTool("view_pull_request", "Show a pull request with its description and the status of its checks.", true,
+			objectSchema([]string{"number"}, map[string]any{
+				"repo":   repoProperty,
+				"number": integerProperty("Pull request number"),
+			}), ts.viewPullRequest),
+		newTool("create_pull_request", "Open a pull request from a branch that has been pushed.", false,
+			objectSchema([]string{"title"}, map[string]any{
+				"repo":                repoProperty,
+				"title":               stringProperty("Title"),
+				"description":         stringProperty("Description, in Markdown"),
+				"source":              stringProperty("Branch to merge (default the current branch)"),
+				"destination":         stringProperty("Branch to merge into (default the repository's main branch)"),
+				"close_source_branch": booleanProperty("Delete the source branch once merged"),
+			}), ts.createPullRequest),
+		newTool("list_issues", "List the issues of a repository's issue tracker.", true,
+			objectSchema(nil, map[string]any{
+				"repo":     repoProperty,
+				"state":    enumProperty("Issue state", "new", "open", "resolved", "on hold", "invalid", "duplicate", "wontfix", "closed"),
+				"kind":     enumProperty("Issue kind", "bug", "enhancement", "proposal", "task"),
+				"priority": enumProperty("Issue priority", "trivial", "minor", "major", "critical", "blocker"),
+				"assignee": stringProperty("Only issues assigned to this username"),
+				"limit":    limitProperty,
+			}), ts.listIssues),
+		newTool("view_issue", "Show an issue with its description.", true,
+			objectSchema([]string{"number"}, map[string]any{
+				"repo":   repoProperty,
+				"number": integerProperty("Issue number"),
+			}), ts.viewIssue),
+		newTool("create_issue", "Create an issue in a repository's issue tracker.", false,
+			objectSchema([]string{"title"}, map[string]any{
+				"repo":     repoProperty,
+				"title":    stringProperty("Title"),
+				"content":  stringProperty("Description, in Markdown"),
+				"kind":     enumProperty("Issue kind (default bug)", "bug", "enhancement", "proposal", "task"),
+				"priority": enumProperty("Issue priority (default major)", "trivial", "minor", "major", "critical", "blocker"),
+			}), ts.createIssue),
+		newTool("list_pipelines", "List the most recent pipeline runs of a repository.", true,
+			objectSchema(nil, map[string]any{
+				"repo":   repoProperty,
+				"status": stringProperty("Only runs in this state: PENDING, IN_PROGRESS, COMPLETED, FAILED, STOPPED or EXPIRED"),
+				"limit":  limitProperty,
+			}), ts.listPipelines),
+		newTool("view_pipeline", "Show a pipeline run and its steps.", true,
+			objectSchema([]string{"pipeline"}, map[string]any{
+				"repo":     repoProperty,
+				"pipeline": stringProperty("Build number or UUID of the run"),
+			}), ts.viewPipeline),
+		newTool("run_pipeline", "Start a pipeline run for a branch.", false,
+			objectSchema([]string{"branch"}, map[string]any{
+				"repo":   repoProperty,
+				"branch": stringProperty("Branch to build"),
+				"commit": stringProperty("Commit to build instead of the branch's latest"),
+				"custom": stringProperty("Name of a custom pipeline to run"),
+			}), ts.runPipeline),
+	}
+
+	if !readOnly {
+		return all
+	}
+	var tools []tool
+	for _, t := range all {
+		if t.Annotations["readOnlyHint"] == true {
+			tools = append(tools, t)
+		}
+	}
+	return tools
+}
+
+// repository returns the repository a call operates on
+func (ts *toolset) repository(repo string) (workspace, repoSlug string, err error) {
+	if repo == "" {
+		repo = ts.repo
+	}
+	return cmdutil.ParseRepository(repo)
+}
+
+type listPullRequestsArgs struct {
+	Repo   string `json:"repo"`
+	State  string `json:"state"`
+	Author string `json:"author"`
+	Limit  int    `json:"limit"`
+}
+
+func (ts *toolset) listPullRequests(ctx context.Context, args listPullRequestsArgs) (any, error) {
+	workspace, repoSlug, err := ts.repository(args.Repo)
+	if err != nil {
+		return nil, err
+	}
+	state := api.PRStateOpen
+	if args.State != "" {
+		state = api.PRState(args.State)
+	}
+	result, err := ts.client.ListPullRequests(ctx, workspace, repoSlug, &api.PRListOptions{
+		State:  state,
+		Author: args.Author,
+		Limit:  listLimit(args.Limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+	prs := make([]api.PullRequestJSON, 0, len(result.Values))
+	for i := range result.Values {
+		prs = append(prs, api.PullRequestJSON{PullRequest: &result.Values[i]})
+	}
+	return prs, nil
+}
+
+type viewPullRequestArgs struct {
+	Repo   string `json:"repo"`
+	Number int64  `json:"number"`
+}
+
+func (ts *toolset) viewPullRequest(ctx context.Context, args viewPullRequestArgs) (any, error) {
+	workspace, repoSlug, err := ts.repository(args.Repo)
+	if err != nil {
+		return nil, err
+	}
+	pr, err := ts.client.GetPullRequest(ctx, workspace, repoSlug, args.Number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request: %w", err)
+	}
+
+	view := map[string]any{"pull_request": api.PullRequestJSON{PullRequest: pr}}
+	if statuses, err := ts.client.GetPullRequestStatuses(ctx, workspace, repoSlug, args.Number); err == nil {
+		checks := make([]map[string]any, 0, len(statuses.Values))
+		for _, s := range statuses.Values {
+			checks = append(checks, map[string]any{
+				"name":  s.Name,
+				"state": s.State,
+				"url":   s.URL,
+			})
+		}
+		view["checks"] = checks
+	}
+	return view, nil
+}
+
+type createPullRequestArgs struct {
+	Repo              string `json:"repo"`
+	Title             string `json:"title"`
+	Description       string `json:"description"`
+	Source            string `json:"source"`
+	Destination       string `json:"destination"`
+	CloseSourceBranch bool   `json:"close_source_branch"`
+}
+
+func (ts *toolset) createPullRequest(ctx context.Context, args createPullRequestArgs) (any, error) {
+	workspace, repoSlug, err := ts.repository(args.Repo)
+	if err != nil {
+		return nil, err
+	}
+	if args.Title == "" {
+		return nil, fmt.Errorf("a title is required")
+	}
+	if args.Source == "" {
+		args.Source, err = git.CurrentBranch()
+		if err != nil {
+			return nil, fmt.Errorf("no source branch given, and the current branch could not be determined: %w", err)
+		}
+	}
+	if args.Destination == "" {
+		args.Destination, err = cmdutil.DefaultBranch(ctx, ts.client, workspace, repoSlug)
+		if err != nil {
+			return nil, fmt.Errorf("no destination branch given, and the default branch could not be determined: %w", err)
+		}
+	}
+
+	pr, err := ts.client.CreatePullRequest(ctx, workspace, repoSlug, &api.PRCreateOptions{
+		Title:             args.Title,
+		Description:       args.Description,
+		SourceBranch:      args.Source,
+		DestinationBranch: args.Destination,
+		CloseSourceBranch: args.CloseSourceBranch,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+	return api.PullRequestJSON{PullRequest: pr}, nil
+}
+
+type listIssuesArgs struct {
+	Repo     string `json:"repo"`
+	State    string `json:"state"`
+	Kind     string `json:"kind"`
+	Priority string `json:"priority"`
+	Assignee string `json:"assignee"`
+	Limit    int    `json:"limit"`
+}
+
+func (ts *toolset) listIssues(ctx context.Context, args listIssuesArgs) (any, error) {
+	workspace, repoSlug, err := ts.repository(args.Repo)
+	if err != nil {
+		return nil, err
+	}
+	result, err := ts.client.ListIssues(ctx, workspace, repoSlug, &api.IssueListOptions{
+		State:    args.State,
+		Kind:     args.Kind,
+		Priority: args.Priority,
+		Assignee: args.Assignee,
+		Sort:     "-updated_on",
+		Limit:    listLimit(args.Limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+	return result.Values, nil
+}
+
+type viewIssueArgs struct {
+	Repo   string `json:"repo"`
+	Number int    `json:"number"`
+}
+
+func (ts *toolset) viewIssue(ctx context.Context, args viewIssueArgs) (any, error) {
+	workspace, repoSlug, err := ts.repository(args.Repo)
+	if err != nil {
+		return nil, err
+	}
+	issue, err := ts.client.GetIssue(ctx, workspace, repoSlug, args.Number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue: %w", err)
+	}
+	return issue, nil
+}
+
+type createIssueArgs struct {
+	Repo     string `json:"repo"`
+	Title    string `json:"title"`
+	Content  string `json:"content"`
+	Kind     string `json:"kind"`
+	Priority string `json:"priority"`
+}
+
+func (ts *toolset) createIssue(ctx context.Context, args createIssueArgs) (any, error) {
+	workspace, repoSlug, err := ts.repository(args.Repo)
+	if err != nil {
+		return nil, err
+	}
+	if args.Title == "" {
+		return nil, fmt.Errorf("a title is required")
+	}
+	opts := &api.IssueCreateOptions{
+		Title:    args.Title,
+		Kind:     args.Kind,
+		Priority: args.Priority,
+	}
+	if args.Content != "" {
+		opts.Content = &api.Content{Raw: args.Content}
+	}
+	issue, err := ts.client.CreateIssue(ctx, workspace, repoSlug, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create issue: %w", err)
+	}
+	return issue, nil
+}
+
+type listPipelinesArgs struct {
+	Repo   string `json:"repo"`
+	Status string `json:"status"`
+	Limit  int    `json:"limit"`
+}
+
+func (ts *toolset) listPipelines(ctx context.Context, args listPipelinesArgs) (any, error) {
+	workspace, repoSlug, err := ts.repository(args.Repo)
+	if err != nil {
+		return nil, err
+	}
+	result, err := ts.client.ListPipelines(ctx, workspace, repoSlug, &api.PipelineListOptions{
+		Status: args.Status,
+		Sort:   "-created_on",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pipelines: %w", err)
+	}
+	pipelines := result.Values
+	if limit := listLimit(args.Limit); len(pipelines) > limit {
+		pipelines = pipelines[:limit]
+	}
+	return pipelines, nil
+}
+
+type viewPipelineArgs struct {
+	Repo     string          `json:"repo"`
+	Pipeline json.RawMessage `json:"pipeline"`
+}
+
+func (ts *toolset) viewPipeline(ctx context.Context, args viewPipelineArgs) (any, error) {
+	workspace, repoSlug, err := ts.repository(args.Repo)
+	if err != nil {
+		return nil, err
+	}
+	// Build numbers are accepted as numbers or strings
+	var identifier string
+	if err := json.Unmarshal(args.Pipeline, &identifier); err != nil {
+		identifier = string(bytes.TrimSpace(args.Pipeline))
+	}
+	if identifier == "" {
+		return nil, fmt.Errorf("a pipeline build number or UUID is required")
+	}
+
+	pipelineUUID, err := cmdutil.ResolvePipelineUUID(ctx, ts.client, workspace, repoSlug, identifier)
+	if err != nil {
+		return nil, err
+	}
+	pipeline, err := ts.client.GetPipeline(ctx, workspace, repoSlug, pipelineUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pipeline: %w", err)
+	}
+	steps, err := ts.client.ListPipelineSteps(ctx, workspace, repoSlug, pipelineUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pipeline steps: %w", err)
+	}
+	return map[string]any{"pipeline": pipeline, "steps": steps.Values}, nil
+}
+
+type runPipelineArgs struct {
+	Repo   string `json:"repo"`
+	Branch string `json:"branch"`
+	Commit string `json:"commit"`
+	Custom string `json:"custom"`
+}
+
+func (ts *toolset) runPipeline(ctx context.Context, args runPipelineArgs) (any, error) {
+	workspace, repoSlug, err := ts.repository(args.Repo)
+	if err != nil {
+		return nil, err
+	}
+	if args.Branch == "" {
+		return nil, fmt.Errorf("a branch is required")
+	}
+
+	target := &api.PipelineTarget{
+		Type:    "pipeline_ref_target",
+		RefType: "branch",
+		RefName: args.Branch,
+	}
+	if args.Commit != "" {
+		target.Commit = &api.PipelineCommit{Type: "commit", Hash: args.Commit}
+	}
+	if args.Custom != "" {
+		target.Selector = &api.PipelineSelector{Type: "custom", Pattern: args.Custom}
+	}
+
+	pipeline, err := ts.client.RunPipeline(ctx, workspace, repoSlug, &api.PipelineRunOptions{Target: target})
+	if err != nil {
+		return nil, fmt.Errorf("failed to run pipeline: %w", err)
+	}
+	return pipeline, nil
+}
+
+// newTool creates a tool whose arguments are decoded into A. Unknown
+// arguments are rejected, so a misspelt filter isn't silently ignored.
+func newTool[A any](name, description string, readOnly bool, schema map[string]any, run func(context.Context, A) (any, error)) tool {
+	return tool{
+		Name:        name,
+		Description: description,
+		InputSchema: schema,
+		Annotations: map[string]any{"readOnlyHint": readOnly},
+		call: func(ctx context.Context, raw json.RawMessage) (any, error) {
+			var args A
+			dec := json.NewDecoder(bytes.NewReader(raw))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&args); err != nil {
+				return nil, fmt.Errorf("invalid arguments: %w", err)
+			}
+			return run(ctx, args)
+		},
+	}
+}
+
+// listLimit returns the number of results a list tool returns for limit
+func listLimit(limit int) int {
+	if limit <= 0 {
+		return defaultListLimit
+	}
+	return min(limit, maxListLimit)
+}
+
+var (
+	repoProperty  = stringProperty("Repository in WORKSPACE/REPO format (default the current repository)")
+	limitProperty = map[string]any{
+		"type":        "integer",
+		"description": fmt.Sprintf("Maximum number of results (default %d, at most %d)", defaultListLimit, maxListLimit),
+		"minimum":     1,
+		"maximum":     maxListLimit,
+	}
+)
+
+func objectSchema(required []string, properties map[string]any) map[string]any {
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func stringProperty(description string) map[string]any {
+	return map[string]any{"type": "string", "description": description}
+}
+
+func integerProperty(description string) map[string]any {
+	return map[string]any{"type": "integer", "description": description}
+}
+
+func booleanProperty(description string) map[string]any {
+	return map[string]any{"type": "boolean", "description": description}
+}
+
+func enumProperty(description string, values ...string) map[string]any {
+	return map[string]any{"type": "string", "description": description, "enum": values}
+}
@@ -0,0 +1,107 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+type enableOptions struct {
+	streams *iostreams.IOStreams
+	repo    string
+	enable  bool
+}
+
+// NewCmdEnable creates the enable command
+func NewCmdEnable(streams *iostreams.IOStreams) *cobra.Command {
+	return newCmdSetEnabled(streams, true)
+}
+
+// NewCmdDisable creates the disable command
+func NewCmdDisable(streams *iostreams.IOStreams) *cobra.Command {
+	return newCmdSetEnabled(streams, false)
+}
+
+// newCmdSetEnabled creates the enable command, or the disable command when
+// enable is false
+func newCmdSetEnabled(streams *iostreams.IOStreams, enable bool) *cobra.Command {
+	opts := &enableOptions{
+		streams: streams,
+		enable:  enable,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "enable",
+		Short: "Turn on Pipelines for a repository",
+		Long: `Turn on Bitbucket Pipelines for a repository, so pushes run the pipelines
+of its bitbucket-pipelines.yml. Doing so requires admin access to the
+repository. Nothing changes if Pipelines is already on.`,
+		Example: `  # Turn on Pipelines for the current repository
+  bb pipeline enable
+
+  # Turn on Pipelines for a new repository
+  bb repo create service -w myworkspace && bb pipeline enable -R myworkspace/service`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSetEnabled(cmd.Context(), opts)
+		},
+	}
+	if !enable {
+		cmd.Use = "disable"
+		cmd.Short = "Turn off Pipelines for a repository"
+		cmd.Long = `Turn off Bitbucket Pipelines for a repository, so pushes no longer run
+pipelines. Pipelines already running are not stopped, and the history of
+past runs is kept. Doing so requires admin access to the repository.
+Nothing changes if Pipelines is already off.`
+		cmd.Example = `  # Turn off Pipelines for the current repository
+  bb pipeline disable
+
+  # Turn off Pipelines for another repository
+  bb pipeline disable -R myworkspace/archived-service`
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+
+	return cmd
+}
+
+func runSetEnabled(ctx context.Context, opts *enableOptions) error {
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	state := "off"
+	if opts.enable {
+		state = "on"
+	}
+	repo := workspace + "/" + repoSlug
+
+	config, err := client.GetPipelinesConfig(ctx, workspace, repoSlug)
+	if err != nil {
+		return fmt.Errorf("failed to get the Pipelines configuration: %w", err)
+	}
+	if config.Enabled == opts.enable {
+		opts.streams.Info("Pipelines is already %s for %s", state, repo)
+		return nil
+	}
+
+	if _, err := client.UpdatePipelinesConfig(ctx, workspace, repoSlug, opts.enable); err != nil {
+		return fmt.Errorf("failed to turn %s Pipelines: %w", state, err)
+	}
+	opts.streams.Success("Turned %s Pipelines for %s", state, repo)
+	return nil
+}
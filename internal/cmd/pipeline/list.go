@@ -3,24 +3,36 @@ package pipeline
 import (
 	"context"
 	"fmt"
-	"text/tabwriter"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/rbansal42/bitbucket-cli/internal/api"
 	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
 
 // ListOptions holds the options for the list command
 type ListOptions struct {
-	Status  string
-	Branch  string
-	Limit   int
-	JSON    bool
-	Repo    string
-	Streams *iostreams.IOStreams
+	Status        string
+	Branch        string
+	CurrentBranch bool
+	Trigger       string
+	Creator       string
+	TargetCommit  string
+	Since         string
+	Until         string
+	Limit         int
+	JSON          bool
+	Format        string
+	Repo          string
+	Fields        []string
+	Watch         time.Duration
+	Web           bool
+	Streams       *iostreams.IOStreams
 }
 
 // NewCmdList creates the pipeline list command
@@ -35,7 +47,14 @@ func NewCmdList(streams *iostreams.IOStreams) *cobra.Command {
 		Long: `List pipelines in a Bitbucket repository.
 
 By default, this shows the most recent pipelines. Use the --status flag to filter
-by pipeline status (PENDING, IN_PROGRESS, COMPLETED, FAILED, etc.).`,
+by pipeline status (PENDING, IN_PROGRESS, COMPLETED, FAILED, etc.).
+
+Pipelines can also be filtered by the branch they ran on, what triggered
+them (push, pr, manual or schedule), who started them ("me" for yourself),
+the commit they ran on, and when they started. --since and --until take a
+duration before now, such as 2h, 3d or 1w, or a date, such as 2024-05-01.
+The trigger, commit and date filters are applied to the most recent
+pipelines, up to 1000 of them.`,
 		Example: `  # List recent pipelines
   bb pipeline list
 
@@ -45,25 +64,82 @@ by pipeline status (PENDING, IN_PROGRESS, COMPLETED, FAILED, etc.).`,
   # List pipelines for a specific branch
   bb pipeline list --branch main
 
+  # List pipelines for the branch you are on
+  bb pipeline list --current-branch
+
+  # List pipelines you started manually this week
+  bb pipeline list --creator me --trigger manual --since 1w
+
+  # List pipelines that ran on a commit
+  bb pipeline list --target-commit abc1234
+
+  # List scheduled pipelines that ran in May 2024
+  bb pipeline list --trigger schedule --since 2024-05-01 --until 2024-06-01
+
   # List with a specific limit
   bb pipeline list --limit 10
 
   # Output as JSON
   bb pipeline list --json
 
+  # Output as YAML
+  bb pipeline list --format yaml
+
   # List pipelines for a specific repository
-  bb pipeline list --repo workspace/repo`,
+  bb pipeline list --repo workspace/repo
+
+  # Keep watching pipelines, refreshing every 10 seconds
+  bb pipeline list --watch
+
+  # Open the pipelines page in the browser
+  bb pipeline list --web`,
 		Aliases: []string{"ls"},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.CurrentBranch {
+				branch, err := git.CurrentBranch()
+				if err != nil {
+					return fmt.Errorf("could not determine current branch: %w", err)
+				}
+				opts.Branch = git.RemoteBranchName(branch)
+			}
+			if opts.Web {
+				workspace, repoSlug, err := cmdutil.ParseRepository(opts.Repo)
+				if err != nil {
+					return err
+				}
+				url, err := cmdutil.RepoWebURL(cmd.Context(), workspace, repoSlug, "pipelines")
+				if err != nil {
+					return err
+				}
+				return cmdutil.OpenInBrowser(opts.Streams, url)
+			}
+			if opts.Watch > 0 {
+				return cmdutil.Watch(cmd.Context(), opts.Streams, opts.Watch, func() error {
+					return runList(cmd.Context(), opts)
+				})
+			}
 			return runList(cmd.Context(), opts)
 		},
 	}
 
 	cmd.Flags().StringVarP(&opts.Status, "status", "s", "", "Filter by status: PENDING, IN_PROGRESS, COMPLETED, FAILED, STOPPED, EXPIRED")
 	cmd.Flags().StringVarP(&opts.Branch, "branch", "b", "", "Filter by branch name")
+	_ = cmd.RegisterFlagCompletionFunc("branch", cmdutil.CompleteBranches)
+	cmd.Flags().BoolVar(&opts.CurrentBranch, "current-branch", false, "Filter by the current git branch")
+	cmd.MarkFlagsMutuallyExclusive("branch", "current-branch")
+	cmd.Flags().StringVar(&opts.Trigger, "trigger", "", "Filter by trigger: push, pr, manual, schedule")
+	_ = cmd.RegisterFlagCompletionFunc("trigger", cobra.FixedCompletions(pipelineTriggers, cobra.ShellCompDirectiveNoFileComp))
+	cmd.Flags().StringVar(&opts.Creator, "creator", "", "Filter by the user who started the pipeline (\"me\" for yourself)")
+	cmd.Flags().StringVar(&opts.TargetCommit, "target-commit", "", "Filter by the commit the pipeline ran on")
+	cmd.Flags().StringVar(&opts.Since, "since", "", "Only pipelines started since a duration ago or a date")
+	cmd.Flags().StringVar(&opts.Until, "until", "", "Only pipelines started before a duration ago or a date")
 	cmd.Flags().IntVarP(&opts.Limit, "limit", "l", 30, "Maximum number of pipelines to list")
 	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
+	cmdutil.AddFieldsFlag(cmd, &opts.Fields, listColumns(streams))
+	cmdutil.AddWatchFlag(cmd, &opts.Watch)
 	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+	cmdutil.AddWebFlag(cmd, &opts.Web, "pipelines page")
 
 	return cmd
 }
@@ -81,37 +157,43 @@ func runList(ctx context.Context, opts *ListOptions) error {
 		return err
 	}
 
+	columns, err := cmdutil.SelectColumns("pipeline list", opts.Fields, listColumns(opts.Streams), defaultListFields)
+	if err != nil {
+		return err
+	}
+
+	filter, err := newPipelineFilter(opts, time.Now())
+	if err != nil {
+		return err
+	}
+
 	// Build list options
 	listOpts := &api.PipelineListOptions{
-		Sort: "-created_on", // Sort by newest first
+		Status: opts.Status,
+		Branch: opts.Branch,
+		Sort:   "-created_on", // Sort by newest first
+		Limit:  min(opts.Limit, maxPipelinePageLen),
 	}
-
-	if opts.Status != "" {
-		listOpts.Status = opts.Status
+	if filter.local() {
+		listOpts.Limit = maxPipelinePageLen
+	}
+	if opts.Creator != "" {
+		listOpts.CreatorUUID, err = resolveCreator(ctx, client, workspace, opts.Creator)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Fetch pipelines
-	result, err := client.ListPipelines(ctx, workspace, repoSlug, listOpts)
+	progress := opts.Streams.StartProgress("Fetching pipelines")
+	pipelines, err := fetchPipelines(ctx, client, workspace, repoSlug, listOpts, filter, opts.Limit)
+	progress.Stop()
 	if err != nil {
 		return fmt.Errorf("failed to list pipelines: %w", err)
 	}
 
-	// Filter by branch if specified (client-side filter since API may not support it directly)
-	var pipelines []api.Pipeline
-	for _, p := range result.Values {
-		if opts.Branch != "" {
-			if p.Target == nil || p.Target.RefName != opts.Branch {
-				continue
-			}
-		}
-		pipelines = append(pipelines, p)
-		if len(pipelines) >= opts.Limit {
-			break
-		}
-	}
-
 	if len(pipelines) == 0 {
-		if opts.Status != "" || opts.Branch != "" {
+		if opts.Status != "" || opts.Branch != "" || opts.Creator != "" || filter.local() {
 			opts.Streams.Info("No pipelines found matching the specified filters in %s/%s", workspace, repoSlug)
 		} else {
 			opts.Streams.Info("No pipelines found in %s/%s", workspace, repoSlug)
@@ -119,16 +201,152 @@ func runList(ctx context.Context, opts *ListOptions) error {
 		return nil
 	}
 
+	if err := opts.Streams.StartPager(); err != nil {
+		opts.Streams.Warning("%s", err)
+	}
+	defer opts.Streams.StopPager()
+
 	// Output results
-	if opts.JSON {
-		return outputListJSON(opts.Streams, pipelines)
+	if opts.JSON || opts.Format != "" {
+		return outputListStructured(opts.Streams, opts.Format, pipelines)
 	}
 
-	return outputListTable(opts.Streams, pipelines)
+	return cmdutil.PrintColumns(opts.Streams, columns, pipelines)
 }
 
-func outputListJSON(streams *iostreams.IOStreams, pipelines []api.Pipeline) error {
-	// Create simplified JSON output
+const (
+	// maxPipelinePageLen is the largest page of pipelines the API returns
+	maxPipelinePageLen = 100
+
+	// pipelineScanLimit bounds how many pipelines are read to find those
+	// matching the filters applied locally
+	pipelineScanLimit = 1000
+)
+
+// pipelineTriggers are the values of --trigger, as shown in the TRIGGER
+// column
+var pipelineTriggers = []string{"push", "pr", "manual", "schedule"}
+
+// pipelineFilter holds the filters the API doesn't apply, which are checked
+// against each pipeline it returns
+type pipelineFilter struct {
+	trigger string
+	commit  string
+	since   time.Time
+	until   time.Time
+}
+
+// newPipelineFilter validates the local filters of opts
+func newPipelineFilter(opts *ListOptions, now time.Time) (pipelineFilter, error) {
+	f := pipelineFilter{
+		trigger: strings.ToLower(opts.Trigger),
+		commit:  strings.ToLower(opts.TargetCommit),
+	}
+	if f.trigger != "" && !slices.Contains(pipelineTriggers, f.trigger) {
+		return f, fmt.Errorf("invalid trigger %q: must be one of %s", opts.Trigger, strings.Join(pipelineTriggers, ", "))
+	}
+	var err error
+	if opts.Since != "" {
+		if f.since, err = cmdutil.ParseTimeFlag("--since", opts.Since, now); err != nil {
+			return f, err
+		}
+	}
+	if opts.Until != "" {
+		if f.until, err = cmdutil.ParseTimeFlag("--until", opts.Until, now); err != nil {
+			return f, err
+		}
+	}
+	if !f.since.IsZero() && !f.until.IsZero() && !f.since.Before(f.until) {
+		return f, fmt.Errorf("--since must be before --until")
+	}
+	return f, nil
+}
+
+// local reports whether any filter has to be applied locally
+func (f pipelineFilter) local() bool {
+	return f.trigger != "" || f.commit != "" || !f.since.IsZero() || !f.until.IsZero()
+}
+
+// matches reports whether p passes the filter. A commit matches by prefix,
+// so abbreviated hashes work.
+func (f pipelineFilter) matches(p api.Pipeline) bool {
+	if f.trigger != "" && getTriggerType(p.Trigger) != f.trigger {
+		return false
+	}
+	if f.commit != "" && (p.Target == nil || p.Target.Commit == nil || !strings.HasPrefix(strings.ToLower(p.Target.Commit.Hash), f.commit)) {
+		return false
+	}
+	if !f.since.IsZero() && p.CreatedOn.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && !p.CreatedOn.Before(f.until) {
+		return false
+	}
+	return true
+}
+
+// fetchPipelines reads pipelines newest first, page by page, until limit of
+// them pass filter, they run out, or they start before the --since time
+func fetchPipelines(ctx context.Context, client *api.Client, workspace, repoSlug string, listOpts *api.PipelineListOptions, filter pipelineFilter, limit int) ([]api.Pipeline, error) {
+	var pipelines []api.Pipeline
+	scanned := 0
+	for page := 1; ; page++ {
+		listOpts.Page = page
+		result, err := client.ListPipelines(ctx, workspace, repoSlug, listOpts)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range result.Values {
+			if !filter.since.IsZero() && p.CreatedOn.Before(filter.since) {
+				return pipelines, nil
+			}
+			if !filter.matches(p) {
+				continue
+			}
+			pipelines = append(pipelines, p)
+			if len(pipelines) >= limit {
+				return pipelines, nil
+			}
+		}
+		scanned += len(result.Values)
+		if result.Next == "" || len(result.Values) == 0 || scanned >= pipelineScanLimit {
+			return pipelines, nil
+		}
+	}
+}
+
+// resolveCreator returns the UUID of the user --creator names: "me" or
+// "@me" for the current user, a UUID, or the nickname, username or display
+// name of a member of workspace
+func resolveCreator(ctx context.Context, client *api.Client, workspace, creator string) (string, error) {
+	switch {
+	case creator == "me" || creator == "@me":
+		user, err := client.GetCurrentUser(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get current user: %w", err)
+		}
+		return user.UUID, nil
+	case strings.HasPrefix(creator, "{"):
+		return creator, nil
+	}
+
+	members, err := client.ListWorkspaceMembers(ctx, workspace, &api.WorkspaceMemberListOptions{Limit: 100})
+	if err != nil {
+		return "", fmt.Errorf("failed to list workspace members: %w", err)
+	}
+	for _, m := range members.Values {
+		if m.User == nil {
+			continue
+		}
+		if strings.EqualFold(m.User.Nickname, creator) || strings.EqualFold(m.User.Username, creator) || strings.EqualFold(m.User.DisplayName, creator) {
+			return m.User.UUID, nil
+		}
+	}
+	return "", cmdutil.NewExitError(cmdutil.ExitNotFound, fmt.Errorf("no member of %s named %q", workspace, creator))
+}
+
+func outputListStructured(streams *iostreams.IOStreams, format string, pipelines []api.Pipeline) error {
+	// Create simplified output
 	output := make([]map[string]interface{}, len(pipelines))
 	for i, p := range pipelines {
 		state := ""
@@ -168,39 +386,47 @@ func outputListJSON(streams *iostreams.IOStreams, pipelines []api.Pipeline) erro
 		}
 	}
 
-	return cmdutil.PrintJSON(streams, output)
+	return cmdutil.PrintFormatted(streams, format, output)
 }
 
-func outputListTable(streams *iostreams.IOStreams, pipelines []api.Pipeline) error {
-	w := tabwriter.NewWriter(streams.Out, 0, 0, 2, ' ', 0)
-
-	// Print header
-	header := "#\tSTATUS\tBRANCH\tCOMMIT\tTRIGGER\tDURATION\tSTARTED"
-	cmdutil.PrintTableHeader(streams, w, header)
-
-	// Print rows
-	for _, p := range pipelines {
-		buildNum := fmt.Sprintf("%d", p.BuildNumber)
-		status := formatPipelineState(streams, p.State)
-
-		branch := "-"
-		commit := "-"
-		if p.Target != nil {
-			branch = cmdutil.TruncateString(p.Target.RefName, 25)
-			if p.Target.Commit != nil {
-				commit = getCommitShort(p.Target.Commit.Hash)
+// defaultListFields are the columns shown when neither --fields nor the
+// fields.pipeline.list config key is set
+var defaultListFields = []string{"id", "status", "branch", "commit", "trigger", "duration", "started"}
+
+// listColumns returns the columns available to pipeline list --fields
+func listColumns(streams *iostreams.IOStreams) []cmdutil.Column[api.Pipeline] {
+	return []cmdutil.Column[api.Pipeline]{
+		{Name: "id", Header: "#", Value: func(p api.Pipeline) string {
+			return fmt.Sprintf("%d", p.BuildNumber)
+		}},
+		{Name: "status", Header: "STATUS", Value: func(p api.Pipeline) string {
+			return formatPipelineState(streams, p.State)
+		}},
+		{Name: "branch", Header: "BRANCH", Value: func(p api.Pipeline) string {
+			if p.Target == nil {
+				return "-"
 			}
-		}
-
-		trigger := getTriggerType(p.Trigger)
-		duration := formatDuration(p.BuildSecondsUsed)
-		started := cmdutil.TimeAgo(p.CreatedOn)
-
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
-			buildNum, status, branch, commit, trigger, duration, started)
+			return p.Target.RefName
+		}},
+		{Name: "commit", Header: "COMMIT", Value: func(p api.Pipeline) string {
+			if p.Target == nil || p.Target.Commit == nil {
+				return "-"
+			}
+			return getCommitShort(p.Target.Commit.Hash)
+		}},
+		{Name: "trigger", Header: "TRIGGER", Value: func(p api.Pipeline) string {
+			return getTriggerType(p.Trigger)
+		}},
+		{Name: "creator", Header: "CREATOR", Value: func(p api.Pipeline) string {
+			return cmdutil.GetUserDisplayName(p.Creator)
+		}},
+		{Name: "duration", Header: "DURATION", Value: func(p api.Pipeline) string {
+			return formatDuration(p.BuildSecondsUsed)
+		}},
+		{Name: "started", Header: "STARTED", Value: func(p api.Pipeline) string {
+			return cmdutil.FormatTime(streams, p.CreatedOn)
+		}},
 	}
-
-	return w.Flush()
 }
 
 // calculateDuration calculates the duration from created to completed time
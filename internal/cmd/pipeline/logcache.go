@@ -0,0 +1,96 @@
+package pipeline
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+)
+
+// stepLogCache keeps the part of a step's log downloaded so far in the
+// cache directory, so that viewing the log again only fetches what was
+// added since. Step logs only grow, so what is kept is always the start of
+// the log. The cache is a convenience: failing to use it isn't an error.
+type stepLogCache struct {
+	path string
+}
+
+// newStepLogCache returns the cache of a step's log, or nil if there is no
+// cache directory
+func newStepLogCache(workspace, repoSlug, pipelineUUID, stepUUID string) *stepLogCache {
+	path, err := config.CacheFile("pipeline-logs/" + workspace + "/" + repoSlug + "/" + pipelineUUID + "/" + stepUUID + ".log")
+	if err != nil {
+		return nil
+	}
+	return &stepLogCache{path: path}
+}
+
+// size returns how many bytes of the log are kept
+func (c *stepLogCache) size() int64 {
+	if c == nil {
+		return 0
+	}
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// print writes the first n bytes of the kept log to w
+func (c *stepLogCache) print(w io.Writer, n int64) error {
+	if c == nil || n == 0 {
+		return nil
+	}
+	f, err := os.Open(c.path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	_, err = cmdutil.CopyLines(w, io.LimitReader(f, n), nil)
+	return err
+}
+
+// keep returns a reader of r, the log from offset on, that adds what is
+// read to the kept log. Call the returned function when done reading.
+func (c *stepLogCache) keep(r io.Reader, offset int64) (io.Reader, func()) {
+	if c == nil {
+		return r, func() {}
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return r, func() {}
+	}
+	f, err := os.OpenFile(c.path, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return r, func() {}
+	}
+	// Drop anything past offset, which another bb may have added, so the
+	// kept log stays the start of the log
+	if f.Truncate(offset) != nil {
+		f.Close()
+		return r, func() {}
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return r, func() {}
+	}
+	return io.TeeReader(r, &cacheWriter{f: f}), func() { f.Close() }
+}
+
+// cacheWriter writes to a cache file until a write fails, after which it
+// discards what it is given, so a full disk doesn't stop the log being shown
+type cacheWriter struct {
+	f      *os.File
+	failed bool
+}
+
+func (w *cacheWriter) Write(p []byte) (int, error) {
+	if !w.failed {
+		if _, err := w.f.Write(p); err != nil {
+			w.failed = true
+		}
+	}
+	return len(p), nil
+}
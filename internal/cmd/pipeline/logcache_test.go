@@ -0,0 +1,51 @@
+package pipeline
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+)
+
+func TestStepLogCache(t *testing.T) {
+	t.Setenv(config.CacheDirEnvVar, t.TempDir())
+	cache := newStepLogCache("team", "repo", "{pipeline}", "{step}")
+
+	if cache.size() != 0 {
+		t.Fatalf("size() of a new cache = %d, want 0", cache.size())
+	}
+
+	// A first view keeps what it reads
+	fetched, done := cache.keep(strings.NewReader("line 1\nline 2\n"), 0)
+	var out bytes.Buffer
+	out.ReadFrom(fetched)
+	done()
+	if cache.size() != 14 {
+		t.Fatalf("size() after the first view = %d, want 14", cache.size())
+	}
+
+	// A later one prints it and adds what was fetched after it
+	out.Reset()
+	if err := cache.print(&out, cache.size()); err != nil {
+		t.Fatal(err)
+	}
+	fetched, done = cache.keep(strings.NewReader("line 3\n"), 14)
+	out.ReadFrom(fetched)
+	done()
+	if out.String() != "line 1\nline 2\nline 3\n" {
+		t.Errorf("log = %q", out.String())
+	}
+
+	// Anything kept past the offset fetched from is dropped
+	fetched, done = cache.keep(strings.NewReader("line 2b\n"), 7)
+	out.ReadFrom(fetched)
+	done()
+	out.Reset()
+	if err := cache.print(&out, cache.size()); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "line 1\nline 2b\n" {
+		t.Errorf("kept log = %q, want the start of the log up to the offset and what followed", out.String())
+	}
+}
@@ -1,9 +1,16 @@
 package pipeline
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -18,8 +25,13 @@ type LogsOptions struct {
 	Streams *iostreams.IOStreams
 	Repo    string
 	Step    string // Step UUID or step number (1-indexed)
+	Follow  bool
+	CI      string
 }
 
+// logFollowInterval is how often --follow checks for new log output
+const logFollowInterval = 3 * time.Second
+
 // NewCmdLogs creates the logs command
 func NewCmdLogs(streams *iostreams.IOStreams) *cobra.Command {
 	opts := &LogsOptions{
@@ -31,34 +43,64 @@ func NewCmdLogs(streams *iostreams.IOStreams) *cobra.Command {
 		Short: "View pipeline step logs",
 		Long: `View the logs for a pipeline step.
 
-By default, if a step failed, shows that step's logs. Otherwise shows the 
-last step's logs. Use --step to specify a particular step by number or UUID.
+Without --step, you are asked which step to show when run interactively,
+with the first failed step, or else the last step, as the default. When
+not run interactively, that step is shown without asking.
+
+--step selects a step by number, UUID or name. A name may be abbreviated
+as long as it matches only one step, and case is ignored. Step numbers can
+be obtained from 'bb pipeline steps'.
+
+With --follow, the log of a running step is printed as it grows until the
+step finishes. Each check only downloads the part of the log not printed
+yet.
 
-Step numbers can be obtained from 'bb pipeline steps'.`,
+The part of a log downloaded so far is kept in the cache directory, so
+viewing it again only downloads what was added since.
+
+When run in GitHub Actions or TeamCity, or with --ci auto, the log is printed
+in a collapsible group, and a step that failed or was stopped is also
+reported as an annotation.`,
 		Example: `  # View logs for pipeline #42 (auto-selects relevant step)
   bb pipeline logs 42
 
   # View logs for a specific step by number
   bb pipeline logs 42 --step 2
 
+  # View logs for a specific step by name
+  bb pipeline logs 42 --step "unit tests"
+
   # View logs for a specific step by UUID
   bb pipeline logs 42 --step "{step-uuid}"
 
+  # Follow the log of a running step
+  bb pipeline logs 42 --step 2 --follow
+
   # View logs for a specific repository
-  bb pipeline logs 42 --repo workspace/repo`,
+  bb pipeline logs 42 --repo workspace/repo
+
+  # Follow a step from another CI system, annotating it if it fails
+  bb pipeline logs 42 --step 2 --follow --ci auto`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runLogs(cmd.Context(), opts, args[0])
 		},
 	}
 
-	cmd.Flags().StringVarP(&opts.Step, "step", "s", "", "Step UUID or step number (default: first failed step or last step)")
+	cmd.Flags().StringVarP(&opts.Step, "step", "s", "", "Step number, UUID or name (default: ask, or the first failed step or last step)")
 	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+	cmd.Flags().BoolVarP(&opts.Follow, "follow", "f", false, "Print new log output as it arrives until the step finishes")
+	cmdutil.AddCIFlag(cmd, &opts.CI)
 
 	return cmd
 }
 
 func runLogs(ctx context.Context, opts *LogsOptions, pipelineArg string) error {
+	ci, err := cmdutil.NewCI(opts.CI, opts.Streams.Out)
+	if err != nil {
+		return err
+	}
+
 	// Get API client
 	client, err := cmdutil.GetAPIClient()
 	if err != nil {
@@ -72,17 +114,17 @@ func runLogs(ctx context.Context, opts *LogsOptions, pipelineArg string) error {
 	}
 
 	// Resolve pipeline UUID from build number or UUID
-	pipelineUUID, err := resolvePipelineUUID(ctx, client, workspace, repoSlug, pipelineArg)
+	pipelineUUID, err := cmdutil.ResolvePipelineUUID(ctx, client, workspace, repoSlug, pipelineArg)
 	if err != nil {
 		return err
 	}
 
 	// Set timeout for API calls
-	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	reqCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	// Fetch pipeline steps to determine which step to show logs for
-	stepsResult, err := client.ListPipelineSteps(ctx, workspace, repoSlug, pipelineUUID)
+	stepsResult, err := client.ListPipelineSteps(reqCtx, workspace, repoSlug, pipelineUUID)
 	if err != nil {
 		return fmt.Errorf("failed to list pipeline steps: %w", err)
 	}
@@ -96,19 +138,176 @@ func runLogs(ctx context.Context, opts *LogsOptions, pipelineArg string) error {
 	if err != nil {
 		return err
 	}
+	if opts.Step == "" && ci == nil && len(stepsResult.Values) > 1 && opts.Streams.CanPrompt() {
+		stepUUID, err = promptStep(opts.Streams, stepsResult.Values, stepUUID)
+		if err != nil {
+			return err
+		}
+	}
+
+	step := findStep(stepsResult.Values, stepUUID)
+	if ci != nil {
+		group := "Pipeline " + pipelineArg + ": " + stepLabel(step)
+		ci.Group(group)
+		defer func() {
+			ci.EndGroup(group)
+			annotateStep(ci, pipelineArg, step)
+		}()
+	}
+
+	cache := newStepLogCache(workspace, repoSlug, pipelineUUID, stepUUID)
+	if opts.Follow {
+		return followLog(ctx, opts.Streams, client, workspace, repoSlug, pipelineUUID, step, cache)
+	}
 
-	// Fetch the step logs
-	logContent, err := client.GetPipelineStepLog(ctx, workspace, repoSlug, pipelineUUID, stepUUID)
+	// Fetch the part of the step log not kept from an earlier view,
+	// waiting for it to start arriving
+	offset := cache.size()
+	progress := opts.Streams.StartProgress("Fetching step logs")
+	logBody, err := client.OpenPipelineStepLog(reqCtx, workspace, repoSlug, pipelineUUID, stepUUID, offset)
 	if err != nil {
+		progress.Stop()
+		return fmt.Errorf("failed to get step logs: %w", err)
+	}
+	defer logBody.Close()
+	body := bufio.NewReader(logBody)
+	_, err = body.Peek(1)
+	progress.Stop()
+	if err != nil && err != io.EOF {
 		return fmt.Errorf("failed to get step logs: %w", err)
 	}
 
-	// Output raw log content
-	fmt.Fprint(opts.Streams.Out, logContent)
+	if err := opts.Streams.StartPager(); err != nil {
+		opts.Streams.Warning("%s", err)
+	}
+	defer opts.Streams.StopPager()
+
+	// Output raw log content a line at a time, so large logs needn't fit in
+	// memory
+	if err := cache.print(opts.Streams.Out, offset); err != nil {
+		return fmt.Errorf("failed to get step logs: %w", err)
+	}
+	fetched, done := cache.keep(body, offset)
+	defer done()
+	if _, err := cmdutil.CopyLines(opts.Streams.Out, fetched, nil); err != nil {
+		return fmt.Errorf("failed to get step logs: %w", err)
+	}
+
+	return nil
+}
+
+// followLog prints the log of a step as it grows, fetching only the bytes
+// not printed yet, until the step completes or the user interrupts. What
+// cache kept of the log is printed first, and what is fetched is added to
+// it. step is updated with the step's latest state.
+func followLog(ctx context.Context, streams *iostreams.IOStreams, client *api.Client, workspace, repoSlug, pipelineUUID string, step *api.PipelineStep, cache *stepLogCache) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	offset := cache.size()
+	if err := cache.print(streams.Out, offset); err != nil {
+		return fmt.Errorf("failed to get step logs: %w", err)
+	}
+	for {
+		// The state is checked before fetching, so that once the step has
+		// completed the fetch gets the rest of its log
+		latest, err := fetchStep(ctx, client, workspace, repoSlug, pipelineUUID, step.UUID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		*step = *latest
+		completed := step.State != nil && step.State.Name == "COMPLETED"
+
+		n, err := printLogFrom(ctx, streams, client, workspace, repoSlug, pipelineUUID, step.UUID, offset, cache)
+		offset += n
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			// A step that hasn't started yet has no log
+			var apiErr *api.APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || completed {
+				return fmt.Errorf("failed to get step logs: %w", err)
+			}
+		}
+		if completed {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(logFollowInterval):
+		}
+	}
+}
+
+// printLogFrom prints the log of a step from byte offset on, adding it to
+// cache, and returns how many bytes it printed
+func printLogFrom(ctx context.Context, streams *iostreams.IOStreams, client *api.Client, workspace, repoSlug, pipelineUUID, stepUUID string, offset int64, cache *stepLogCache) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
 
+	body, err := client.OpenPipelineStepLog(ctx, workspace, repoSlug, pipelineUUID, stepUUID, offset)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+	fetched, done := cache.keep(body, offset)
+	defer done()
+	return cmdutil.CopyLines(streams.Out, fetched, nil)
+}
+
+// fetchStep returns the current state of a pipeline step
+func fetchStep(ctx context.Context, client *api.Client, workspace, repoSlug, pipelineUUID, stepUUID string) (*api.PipelineStep, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	steps, err := client.ListPipelineSteps(ctx, workspace, repoSlug, pipelineUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pipeline steps: %w", err)
+	}
+	if step := findStep(steps.Values, stepUUID); step != nil {
+		return step, nil
+	}
+	return nil, fmt.Errorf("step %s not found", stepUUID)
+}
+
+// findStep returns the step with the given UUID, or nil
+func findStep(steps []api.PipelineStep, stepUUID string) *api.PipelineStep {
+	for i := range steps {
+		if steps[i].UUID == stepUUID {
+			return &steps[i]
+		}
+	}
 	return nil
 }
 
+// stepLabel names a step in CI output
+func stepLabel(step *api.PipelineStep) string {
+	if step.Name != "" {
+		return step.Name
+	}
+	return "step " + step.UUID
+}
+
+// annotateStep reports a step of pipeline that failed or was stopped as a
+// CI annotation
+func annotateStep(ci *cmdutil.CI, pipeline string, step *api.PipelineStep) {
+	if step.State == nil || step.State.Result == nil {
+		return
+	}
+	message := fmt.Sprintf("%s of pipeline %s finished with %s", stepLabel(step), pipeline, step.State.Result.Name)
+	switch step.State.Result.Name {
+	case "FAILED", "ERROR":
+		ci.Error("Pipeline step failed", message)
+	case "STOPPED":
+		ci.Warning("Pipeline step stopped", message)
+	}
+}
+
 // resolveStepUUID resolves a step selector to a step UUID
 // If no selector is provided, returns the first failed step or the last step
 func resolveStepUUID(steps []api.PipelineStep, selector string) (string, error) {
@@ -134,7 +333,35 @@ func resolveStepUUID(steps []api.PipelineStep, selector string) (string, error)
 	return steps[len(steps)-1].UUID, nil
 }
 
-// resolveStepSelector resolves a step selector (number or UUID) to a step UUID
+// promptStep asks which of steps to show the log of, showing each with its
+// state and duration, and returns its UUID. defaultUUID is preselected.
+func promptStep(streams *iostreams.IOStreams, steps []api.PipelineStep, defaultUUID string) (string, error) {
+	options := make([]string, len(steps))
+	defaultIndex := 0
+	for i := range steps {
+		step := &steps[i]
+		option := stepDisplayName(step, i)
+		if status := stepStatus(step); status != "" {
+			option += "  " + formatStepStatus(streams, step.State)
+		}
+		if d := stepDuration(step); d != "" {
+			option += "  " + d
+		}
+		options[i] = option
+		if step.UUID == defaultUUID {
+			defaultIndex = i
+		}
+	}
+
+	i, err := streams.PromptSelect("Which step?", options, defaultIndex)
+	if err != nil {
+		return "", err
+	}
+	return steps[i].UUID, nil
+}
+
+// resolveStepSelector resolves a step selector (number, UUID or name) to a
+// step UUID. Names are matched ignoring case, exactly or else by prefix.
 func resolveStepSelector(steps []api.PipelineStep, selector string) (string, error) {
 	// Try to parse as step number (1-indexed)
 	if stepNum, err := strconv.Atoi(selector); err == nil {
@@ -154,5 +381,27 @@ func resolveStepSelector(steps []api.PipelineStep, selector string) (string, err
 		}
 	}
 
-	return "", fmt.Errorf("step %q not found", selector)
+	// Try to match as a name, exactly and then by prefix
+	for _, step := range steps {
+		if strings.EqualFold(step.Name, selector) {
+			return step.UUID, nil
+		}
+	}
+	var matches []api.PipelineStep
+	for _, step := range steps {
+		if step.Name != "" && strings.HasPrefix(strings.ToLower(step.Name), strings.ToLower(selector)) {
+			matches = append(matches, step)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0].UUID, nil
+	case 0:
+		return "", fmt.Errorf("step %q not found", selector)
+	}
+	names := make([]string, len(matches))
+	for i, step := range matches {
+		names[i] = fmt.Sprintf("%q", step.Name)
+	}
+	return "", fmt.Errorf("step %q is ambiguous: it matches %s", selector, strings.Join(names, ", "))
 }
@@ -0,0 +1,96 @@
+package pipeline
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// testSteps returns steps named names, with UUIDs {step-1}, {step-2}, ...
+// in order
+func testSteps(names ...string) []api.PipelineStep {
+	steps := make([]api.PipelineStep, len(names))
+	for i, name := range names {
+		steps[i] = api.PipelineStep{UUID: fmt.Sprintf("{step-%d}", i+1), Name: name}
+	}
+	return steps
+}
+
+func TestResolveStepSelector(t *testing.T) {
+	steps := testSteps("Build", "Test unit", "Test integration", "Deploy", "")
+
+	tests := []struct {
+		name     string
+		selector string
+		want     string
+		wantErr  string
+	}{
+		{name: "exact name", selector: "Deploy", want: "{step-4}"},
+		{name: "exact name ignoring case", selector: "build", want: "{step-1}"},
+		{name: "exact name over a prefix", selector: "TEST UNIT", want: "{step-2}"},
+		{name: "unique prefix", selector: "dep", want: "{step-4}"},
+		{name: "unique longer prefix", selector: "test i", want: "{step-3}"},
+		{name: "ambiguous prefix", selector: "test", wantErr: `step "test" is ambiguous: it matches "Test unit", "Test integration"`},
+		{name: "number", selector: "2", want: "{step-2}"},
+		{name: "number of an unnamed step", selector: "5", want: "{step-5}"},
+		{name: "number out of range", selector: "6", wantErr: "step 6 not found (pipeline has 5 steps)"},
+		{name: "zero", selector: "0", wantErr: "step 0 not found"},
+		{name: "UUID", selector: "{step-3}", want: "{step-3}"},
+		{name: "UUID without braces", selector: "step-3", want: "{step-3}"},
+		{name: "unknown name", selector: "lint", wantErr: `step "lint" not found`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveStepSelector(steps, tt.selector)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveStepSelector(%q) error = %v, want %q", tt.selector, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveStepSelector(%q) error = %v", tt.selector, err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveStepSelector(%q) = %s, want %s", tt.selector, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveStepUUID_Default(t *testing.T) {
+	failed := func(name string) *api.PipelineStepState {
+		return &api.PipelineStepState{Name: "COMPLETED", Result: &api.PipelineStateResult{Name: name}}
+	}
+
+	steps := testSteps("Build", "Test", "Deploy")
+	if got, _ := resolveStepUUID(steps, ""); got != "{step-3}" {
+		t.Errorf("without a failed step, resolveStepUUID() = %s, want the last step", got)
+	}
+	steps[1].State = failed("FAILED")
+	steps[2].State = failed("ERROR")
+	if got, _ := resolveStepUUID(steps, ""); got != "{step-2}" {
+		t.Errorf("resolveStepUUID() = %s, want the first failed step", got)
+	}
+	if _, err := resolveStepUUID(nil, ""); err == nil {
+		t.Error("resolveStepUUID() with no steps should fail")
+	}
+}
+
+func TestPromptStep_NoPrompt(t *testing.T) {
+	var out bytes.Buffer
+	streams := &iostreams.IOStreams{In: strings.NewReader("2\n"), Out: &out, ErrOut: &out}
+
+	_, err := promptStep(streams, testSteps("Build", "Test", "Deploy"), "{step-3}")
+	if !errors.Is(err, iostreams.ErrNoPrompt) {
+		t.Errorf("promptStep() without a terminal error = %v, want ErrNoPrompt", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("promptStep() without a terminal wrote %q", out.String())
+	}
+}
@@ -31,7 +31,10 @@ build, test, and deploy your code based on a configuration file in your reposito
   bb pipeline steps 123
 
   # View step logs
-  bb pipeline logs 123 --step 2`,
+  bb pipeline logs 123 --step 2
+
+  # Turn on Pipelines for a repository
+  bb pipeline enable -R myworkspace/myrepo`,
 		Aliases: []string{"pipelines"},
 	}
 
@@ -41,6 +44,8 @@ build, test, and deploy your code based on a configuration file in your reposito
 	cmd.AddCommand(NewCmdStop(streams))
 	cmd.AddCommand(NewCmdSteps(streams))
 	cmd.AddCommand(NewCmdLogs(streams))
+	cmd.AddCommand(NewCmdEnable(streams))
+	cmd.AddCommand(NewCmdDisable(streams))
 
 	return cmd
 }
@@ -19,6 +19,12 @@ type runOptions struct {
 	commit  string
 	custom  string
 	repo    string
+	vars    []string
+	varFile string
+	secured []string
+	wait    bool
+	timeout time.Duration
+	ci      string
 }
 
 // NewCmdRun creates the run command
@@ -34,7 +40,23 @@ func NewCmdRun(streams *iostreams.IOStreams) *cobra.Command {
 
 By default, the pipeline runs on the current branch. You can specify a different
 branch with --branch, a specific commit with --commit, or trigger a custom 
-pipeline defined in bitbucket-pipelines.yml with --custom.`,
+pipeline defined in bitbucket-pipelines.yml with --custom.
+
+Short names for custom pipelines can be defined under pipelines.custom in
+the repository's .bb.yml.
+
+Variables, such as those a custom pipeline declares, are passed with --var
+or read from a file of KEY=VALUE lines with --var-file; --var takes
+precedence. Variables named with --secured are sent as secured variables,
+whose values are masked in the pipeline's logs.
+
+With --wait, the command waits for the pipeline to complete, printing each
+step as it starts and finishes, and exits with status 8 if the pipeline
+didn't succeed, or 9 if it hadn't completed within --timeout. Checks that
+fail with a network or server error are retried, backing off, and waiting
+gives up after 5 of them in a row. When run in GitHub Actions or TeamCity, or with --ci auto,
+failed and stopped steps and a failed pipeline are also reported as
+annotations.`,
 		Example: `  # Run pipeline on current branch
   bb pipeline run
 
@@ -47,6 +69,24 @@ pipeline defined in bitbucket-pipelines.yml with --custom.`,
   # Run a custom pipeline
   bb pipeline run --custom my-custom-pipeline
 
+  # Run a custom pipeline by its short name from .bb.yml
+  bb pipeline run --custom deploy
+
+  # Pass variables to a custom pipeline
+  bb pipeline run --custom deploy --var ENV=staging --var DEBUG=true
+
+  # Read variables from a file and keep one of them secured
+  bb pipeline run --custom deploy --var-file vars.env --secured API_TOKEN
+
+  # Trigger a build from a script and fail if it fails
+  bb pipeline run --branch release --wait
+
+  # Give up if the build takes longer than an hour
+  bb pipeline run --branch release --wait --timeout 1h
+
+  # Wait from a TeamCity build, reporting failed steps as build problems
+  bb pipeline run --branch release --wait --ci teamcity
+
   # Run pipeline for a different repository
   bb pipeline run --repo myworkspace/myrepo`,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -55,25 +95,48 @@ pipeline defined in bitbucket-pipelines.yml with --custom.`,
 	}
 
 	cmd.Flags().StringVarP(&opts.branch, "branch", "b", "", "Branch to run pipeline on (default: current branch or main)")
+	_ = cmd.RegisterFlagCompletionFunc("branch", cmdutil.CompleteBranches)
 	cmd.Flags().StringVar(&opts.commit, "commit", "", "Specific commit hash to run pipeline on")
 	cmd.Flags().StringVar(&opts.custom, "custom", "", "Custom pipeline name (for custom pipelines in bitbucket-pipelines.yml)")
 	cmd.Flags().StringVarP(&opts.repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+	cmd.Flags().StringArrayVar(&opts.vars, "var", nil, "Pipeline variable as KEY=VALUE (can be repeated)")
+	cmd.Flags().StringVar(&opts.varFile, "var-file", "", "Read pipeline variables from a file of KEY=VALUE lines")
+	cmd.Flags().StringSliceVar(&opts.secured, "secured", nil, "Send the named variables as secured variables")
+	cmd.Flags().BoolVar(&opts.wait, "wait", false, "Wait for the pipeline to complete and exit non-zero if it fails")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 0, "With --wait, how long to wait before giving up, or 0 to wait indefinitely")
+	cmdutil.AddCIFlag(cmd, &opts.ci)
 
 	return cmd
 }
 
 func runPipelineRun(opts *runOptions) error {
+	var ci *cmdutil.CI
+	if opts.wait {
+		var err error
+		if ci, err = cmdutil.NewCI(opts.ci, opts.streams.Out); err != nil {
+			return err
+		}
+	}
+
 	// Resolve repository
 	workspace, repoSlug, err := cmdutil.ParseRepository(opts.repo)
 	if err != nil {
 		return err
 	}
 
+	if opts.custom != "" {
+		repoConfig, err := cmdutil.LoadRepoConfig(opts.repo)
+		if err != nil {
+			return err
+		}
+		opts.custom = repoConfig.CustomPipeline(opts.custom)
+	}
+
 	// Determine the branch to use
 	branch := opts.branch
 	if branch == "" {
 		// Try to get current branch from git
-		currentBranch, err := git.GetCurrentBranch()
+		currentBranch, err := git.CurrentBranch()
 		if err != nil {
 			// Fall back to main if we can't detect the current branch
 			branch = "main"
@@ -84,6 +147,10 @@ func runPipelineRun(opts *runOptions) error {
 
 	// Build pipeline run options
 	pipelineOpts := buildPipelineRunOptions(branch, opts.commit, opts.custom)
+	pipelineOpts.Variables, err = buildVariables(opts.varFile, opts.vars, opts.secured)
+	if err != nil {
+		return err
+	}
 
 	// Get authenticated client
 	client, err := cmdutil.GetAPIClient()
@@ -115,8 +182,15 @@ func runPipelineRun(opts *runOptions) error {
 	// Print pipeline URL
 	pipelineURL := fmt.Sprintf("https://bitbucket.org/%s/%s/pipelines/results/%d",
 		workspace, repoSlug, pipeline.BuildNumber)
-	fmt.Fprintf(opts.streams.Out, "  %s\n", pipelineURL)
+	if opts.streams.IsQuiet() {
+		fmt.Fprintln(opts.streams.Out, pipelineURL)
+	} else {
+		fmt.Fprintf(opts.streams.Out, "  %s\n", pipelineURL)
+	}
 
+	if opts.wait {
+		return waitForPipeline(context.Background(), opts.streams, ci, client, workspace, repoSlug, pipeline, runWaitInterval, opts.timeout)
+	}
 	return nil
 }
 
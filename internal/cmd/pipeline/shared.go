@@ -1,7 +1,6 @@
 package pipeline
 
 import (
-	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -56,22 +55,18 @@ func formatPipelineState(streams *iostreams.IOStreams, state *api.PipelineState)
 		displayText = resultName
 	}
 
-	if !streams.ColorEnabled() {
-		return displayText
-	}
-
-	// Apply color based on state
+	// Apply icon and color based on state
 	switch {
 	case resultName == "SUCCESSFUL":
-		return iostreams.Green + displayText + iostreams.Reset
+		return streams.Style(iostreams.RoleSuccess, streams.Icon(iostreams.IconSuccess, displayText))
 	case resultName == "FAILED" || resultName == "ERROR":
-		return iostreams.Red + displayText + iostreams.Reset
+		return streams.Style(iostreams.RoleError, streams.Icon(iostreams.IconFailed, displayText))
 	case resultName == "STOPPED":
-		return iostreams.Yellow + displayText + iostreams.Reset
+		return streams.Style(iostreams.RoleWarning, streams.Icon(iostreams.IconStopped, displayText))
 	case stateName == "IN_PROGRESS":
-		return iostreams.Yellow + displayText + iostreams.Reset
+		return streams.Style(iostreams.RoleWarning, streams.Icon(iostreams.IconRunning, displayText))
 	case stateName == "PENDING":
-		return iostreams.Cyan + displayText + iostreams.Reset
+		return streams.Style(iostreams.RoleInfo, streams.Icon(iostreams.IconPending, displayText))
 	default:
 		return displayText
 	}
@@ -128,34 +123,3 @@ func getTriggerType(trigger *api.PipelineTrigger) string {
 		return t
 	}
 }
-
-// resolvePipelineUUID resolves a build number or UUID to a UUID
-func resolvePipelineUUID(ctx context.Context, client *api.Client, workspace, repoSlug, identifier string) (string, error) {
-	// Check if it's a build number
-	if buildNum, err := strconv.Atoi(identifier); err == nil {
-		// It's a build number, need to find the UUID
-		// List recent pipelines to find matching build number
-		result, err := client.ListPipelines(ctx, workspace, repoSlug, &api.PipelineListOptions{
-			Sort: "-created_on",
-		})
-		if err != nil {
-			return "", fmt.Errorf("failed to list pipelines: %w", err)
-		}
-
-		for _, p := range result.Values {
-			if p.BuildNumber == buildNum {
-				return p.UUID, nil
-			}
-		}
-		return "", fmt.Errorf("pipeline #%d not found", buildNum)
-	}
-
-	// It's already a UUID, clean it up
-	uuid := identifier
-	// Ensure UUID has curly braces
-	if len(uuid) > 0 && uuid[0] != '{' {
-		uuid = "{" + uuid + "}"
-	}
-
-	return uuid, nil
-}
@@ -2,9 +2,7 @@ package pipeline
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -19,6 +17,7 @@ type StepsOptions struct {
 	Streams *iostreams.IOStreams
 	Repo    string
 	JSON    bool
+	Format  string
 }
 
 // NewCmdSteps creates the steps command
@@ -44,6 +43,9 @@ that step's logs.`,
   # Output as JSON
   bb pipeline steps 42 --json
 
+  # Output as YAML
+  bb pipeline steps 42 --format yaml
+
   # List steps for a specific repository
   bb pipeline steps 42 --repo workspace/repo`,
 		Args: cobra.ExactArgs(1),
@@ -53,6 +55,7 @@ that step's logs.`,
 	}
 
 	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
 	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
 
 	return cmd
@@ -72,7 +75,7 @@ func runSteps(ctx context.Context, opts *StepsOptions, pipelineArg string) error
 	}
 
 	// Resolve pipeline UUID from build number or UUID
-	pipelineUUID, err := resolvePipelineUUID(ctx, client, workspace, repoSlug, pipelineArg)
+	pipelineUUID, err := cmdutil.ResolvePipelineUUID(ctx, client, workspace, repoSlug, pipelineArg)
 	if err != nil {
 		return err
 	}
@@ -93,14 +96,14 @@ func runSteps(ctx context.Context, opts *StepsOptions, pipelineArg string) error
 	}
 
 	// Output results
-	if opts.JSON {
-		return outputStepsJSON(opts.Streams, result.Values)
+	if opts.JSON || opts.Format != "" {
+		return outputStepsStructured(opts.Streams, opts.Format, result.Values)
 	}
 
 	return outputStepsTable(opts.Streams, result.Values)
 }
 
-func outputStepsJSON(streams *iostreams.IOStreams, steps []api.PipelineStep) error {
+func outputStepsStructured(streams *iostreams.IOStreams, format string, steps []api.PipelineStep) error {
 	output := make([]map[string]interface{}, len(steps))
 	for i, step := range steps {
 		state := ""
@@ -126,25 +129,14 @@ func outputStepsJSON(streams *iostreams.IOStreams, steps []api.PipelineStep) err
 		}
 	}
 
-	data, err := json.MarshalIndent(output, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
-	}
-
-	fmt.Fprintln(streams.Out, string(data))
-	return nil
+	return cmdutil.PrintFormatted(streams, format, output)
 }
 
 func outputStepsTable(streams *iostreams.IOStreams, steps []api.PipelineStep) error {
-	w := tabwriter.NewWriter(streams.Out, 0, 0, 2, ' ', 0)
+	table := cmdutil.NewTablePrinter(streams)
 
 	// Print header
-	header := "#\tNAME\tSTATUS\tDURATION"
-	if streams.ColorEnabled() {
-		fmt.Fprintln(w, iostreams.Bold+header+iostreams.Reset)
-	} else {
-		fmt.Fprintln(w, header)
-	}
+	table.AddHeader("#", "NAME", "STATUS", "DURATION")
 
 	// Print rows
 	for i, step := range steps {
@@ -153,14 +145,13 @@ func outputStepsTable(streams *iostreams.IOStreams, steps []api.PipelineStep) er
 		if name == "" {
 			name = "(unnamed)"
 		}
-		name = cmdutil.TruncateString(name, 40)
 		status := formatStepStatus(streams, step.State)
 		duration := formatStepDuration(step.StartedOn, step.CompletedOn)
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", stepNum, name, status, duration)
+		table.AddRow(stepNum, name, status, duration)
 	}
 
-	return w.Flush()
+	return table.Render()
 }
 
 // formatStepStatus formats step status with color
@@ -174,21 +165,17 @@ func formatStepStatus(streams *iostreams.IOStreams, state *api.PipelineStepState
 		status = state.Result.Name
 	}
 
-	if !streams.ColorEnabled() {
-		return status
-	}
-
 	switch status {
 	case "SUCCESSFUL":
-		return iostreams.Green + status + iostreams.Reset
+		return streams.Style(iostreams.RoleSuccess, streams.Icon(iostreams.IconSuccess, status))
 	case "FAILED":
-		return iostreams.Red + status + iostreams.Reset
+		return streams.Style(iostreams.RoleError, streams.Icon(iostreams.IconFailed, status))
 	case "IN_PROGRESS", "RUNNING":
-		return iostreams.Cyan + status + iostreams.Reset
+		return streams.Style(iostreams.RoleInfo, streams.Icon(iostreams.IconRunning, status))
 	case "PENDING":
-		return iostreams.Yellow + status + iostreams.Reset
+		return streams.Style(iostreams.RoleWarning, streams.Icon(iostreams.IconPending, status))
 	case "STOPPED":
-		return iostreams.Magenta + status + iostreams.Reset
+		return streams.Style(iostreams.RoleAccent, streams.Icon(iostreams.IconStopped, status))
 	default:
 		return status
 	}
@@ -0,0 +1,120 @@
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// stepNode is an entry of the step tree: a stage with its steps, or a step
+// outside any stage
+type stepNode struct {
+	stage *api.PipelineStage
+	steps []*api.PipelineStep
+}
+
+// buildStepTree groups consecutive steps of the same stage, keeping the
+// order the pipeline runs them in
+func buildStepTree(steps []api.PipelineStep) []stepNode {
+	var nodes []stepNode
+	for i := range steps {
+		step := &steps[i]
+		if step.Stage != nil && len(nodes) > 0 {
+			last := &nodes[len(nodes)-1]
+			if last.stage != nil && sameStage(last.stage, step.Stage) {
+				last.steps = append(last.steps, step)
+				continue
+			}
+		}
+		nodes = append(nodes, stepNode{stage: step.Stage, steps: []*api.PipelineStep{step}})
+	}
+	return nodes
+}
+
+func sameStage(a, b *api.PipelineStage) bool {
+	if a.UUID != "" || b.UUID != "" {
+		return a.UUID == b.UUID
+	}
+	return a.Name == b.Name
+}
+
+// writeStepTree writes the steps of a pipeline as a tree, with the steps of
+// each stage under it. Each step shows its state and duration, and below
+// them its image and the caches it used.
+func writeStepTree(streams *iostreams.IOStreams, steps []api.PipelineStep) {
+	nodes := buildStepTree(steps)
+	for i, node := range nodes {
+		branch, indent := treeBranch(i == len(nodes)-1)
+		if node.stage == nil {
+			writeStepNode(streams, node.steps[0], branch, indent)
+			continue
+		}
+
+		name := node.stage.Name
+		if name == "" {
+			name = "Stage"
+		}
+		fmt.Fprintf(streams.Out, "%s%s\n", branch, streams.Style(iostreams.RoleHeader, name))
+		for j, step := range node.steps {
+			stepBranch, stepIndent := treeBranch(j == len(node.steps)-1)
+			writeStepNode(streams, step, indent+stepBranch, indent+stepIndent)
+		}
+	}
+}
+
+// treeBranch returns the connector of a tree entry, and the indentation of
+// the lines below it
+func treeBranch(last bool) (string, string) {
+	if last {
+		return "└── ", "    "
+	}
+	return "├── ", "│   "
+}
+
+func writeStepNode(streams *iostreams.IOStreams, step *api.PipelineStep, branch, indent string) {
+	name := step.Name
+	if name == "" {
+		name = "Step"
+	}
+	line := fmt.Sprintf("%s%s %s", branch, formatStepState(streams, step.State), name)
+	if d := stepDuration(step); d != "" {
+		line += "  " + streams.Style(iostreams.RoleMuted, d)
+	}
+	if step.Trigger != nil && step.Trigger.Type == "pipeline_step_trigger_manual" && step.StartedOn == nil {
+		line += "  " + streams.Style(iostreams.RoleMuted, "(manual)")
+	}
+	fmt.Fprintln(streams.Out, line)
+
+	var details []string
+	if step.Image != nil && step.Image.Name != "" {
+		details = append(details, "image "+step.Image.Name)
+	}
+	if len(step.Caches) > 0 {
+		caches := make([]string, len(step.Caches))
+		for i, c := range step.Caches {
+			outcome := "miss"
+			if c.Hit {
+				outcome = "hit"
+			}
+			caches[i] = fmt.Sprintf("%s (%s)", c.Name, outcome)
+		}
+		details = append(details, "caches "+strings.Join(caches, ", "))
+	}
+	if len(details) > 0 {
+		fmt.Fprintf(streams.Out, "%s    %s\n", indent, streams.Style(iostreams.RoleMuted, strings.Join(details, " · ")))
+	}
+}
+
+// stepDuration returns how long a step ran, or has been running, or "" if
+// it hasn't started
+func stepDuration(step *api.PipelineStep) string {
+	if step.DurationInSeconds > 0 && step.CompletedOn != nil {
+		return formatDuration(step.DurationInSeconds)
+	}
+	if step.StartedOn == nil {
+		return ""
+	}
+	return formatStepDuration(step.StartedOn, step.CompletedOn)
+}
@@ -0,0 +1,121 @@
+package pipeline
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+// variableNamePattern matches the names Bitbucket accepts for pipeline
+// variables
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// buildVariables collects the variables of a pipeline run: those in the
+// file at varFile, if any, then those of --var, which replace file entries
+// of the same name. The variables named in secured are sent as secured.
+func buildVariables(varFile string, vars, secured []string) ([]api.PipelineVariable, error) {
+	var result []api.PipelineVariable
+	set := func(key, value string) {
+		for i := range result {
+			if result[i].Key == key {
+				result[i].Value = value
+				return
+			}
+		}
+		result = append(result, api.PipelineVariable{Key: key, Value: value})
+	}
+
+	if varFile != "" {
+		fileVars, err := readVariableFile(varFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range fileVars {
+			set(v.Key, v.Value)
+		}
+	}
+
+	for _, v := range vars {
+		key, value, ok := strings.Cut(v, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var %q: expected KEY=VALUE", v)
+		}
+		if !variableNamePattern.MatchString(key) {
+			return nil, fmt.Errorf("invalid variable name %q: use letters, digits and underscores, not starting with a digit", key)
+		}
+		set(key, value)
+	}
+
+	for _, name := range secured {
+		found := false
+		for i := range result {
+			if result[i].Key == name {
+				result[i].Secured = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("--secured %s: no variable named %s is set by --var or --var-file", name, name)
+		}
+	}
+	return result, nil
+}
+
+// readVariableFile reads variables from a dotenv-style file of KEY=VALUE
+// lines. Blank lines and lines starting with # are skipped, an "export "
+// prefix is allowed, and values may be quoted.
+func readVariableFile(path string) ([]api.PipelineVariable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read variables: %w", err)
+	}
+	defer f.Close()
+
+	var vars []api.PipelineVariable
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !variableNamePattern.MatchString(key) {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, n)
+		}
+		value, err := unquoteVariable(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		vars = append(vars, api.PipelineVariable{Key: key, Value: value})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read variables: %w", err)
+	}
+	return vars, nil
+}
+
+// unquoteVariable removes the quotes around a value from a variables file.
+// Double-quoted values may use Go escapes such as \n; single-quoted values
+// are taken literally.
+func unquoteVariable(value string) (string, error) {
+	switch {
+	case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid quoted value %s", value)
+		}
+		return unquoted, nil
+	case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+		return value[1 : len(value)-1], nil
+	}
+	return value, nil
+}
@@ -2,13 +2,11 @@ package pipeline
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/spf13/cobra"
 
 	"github.com/rbansal42/bitbucket-cli/internal/api"
-	"github.com/rbansal42/bitbucket-cli/internal/browser"
 	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
@@ -17,7 +15,9 @@ import (
 type ViewOptions struct {
 	Identifier string // Pipeline build number or UUID
 	Web        bool
+	Copy       bool
 	JSON       bool
+	Format     string
 	Repo       string
 	Streams    *iostreams.IOStreams
 }
@@ -33,7 +33,11 @@ func NewCmdView(streams *iostreams.IOStreams) *cobra.Command {
 		Short: "View a pipeline's details",
 		Long: `Display the details of a specific pipeline run.
 
-You can specify a pipeline by its build number or UUID.`,
+You can specify a pipeline by its build number or UUID.
+
+The pipeline's steps are shown as a tree, with the steps of each stage
+under it. Each step shows its state, how long it ran, the image it ran in,
+and whether the caches it uses were restored (hit) or not (miss).`,
 		Example: `  # View pipeline by build number
   bb pipeline view 123
 
@@ -46,6 +50,9 @@ You can specify a pipeline by its build number or UUID.`,
   # Output as JSON
   bb pipeline view 123 --json
 
+  # Output as YAML
+  bb pipeline view 123 --format yaml
+
   # View pipeline for a specific repository
   bb pipeline view 123 --repo workspace/repo`,
 		Args: cobra.ExactArgs(1),
@@ -55,8 +62,10 @@ You can specify a pipeline by its build number or UUID.`,
 		},
 	}
 
-	cmd.Flags().BoolVarP(&opts.Web, "web", "w", false, "Open the pipeline in a web browser")
+	cmdutil.AddWebFlag(cmd, &opts.Web, "pipeline")
+	cmdutil.AddCopyFlag(cmd, &opts.Copy, "pipeline")
 	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
 	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
 
 	return cmd
@@ -76,7 +85,7 @@ func runView(ctx context.Context, opts *ViewOptions) error {
 	}
 
 	// Resolve pipeline UUID
-	pipelineUUID, err := resolvePipelineUUID(ctx, client, workspace, repoSlug, opts.Identifier)
+	pipelineUUID, err := cmdutil.ResolvePipelineUUID(ctx, client, workspace, repoSlug, opts.Identifier)
 	if err != nil {
 		return err
 	}
@@ -87,14 +96,18 @@ func runView(ctx context.Context, opts *ViewOptions) error {
 		return fmt.Errorf("failed to get pipeline: %w", err)
 	}
 
-	// Handle --web flag
-	if opts.Web {
-		webURL := getPipelineWebURL(workspace, repoSlug, pipeline.BuildNumber)
-		if err := browser.Open(webURL); err != nil {
-			return fmt.Errorf("could not open browser: %w", err)
+	// Handle --copy and --web flags
+	webURL := getPipelineWebURL(workspace, repoSlug, pipeline.BuildNumber)
+	if pipeline.Links != nil && pipeline.Links.HTML != nil && pipeline.Links.HTML.Href != "" {
+		webURL = pipeline.Links.HTML.Href
+	}
+	if opts.Copy {
+		if err := cmdutil.CopyURL(opts.Streams, webURL); err != nil {
+			return err
 		}
-		opts.Streams.Success("Opened %s in your browser", webURL)
-		return nil
+	}
+	if opts.Web {
+		return cmdutil.OpenInBrowser(opts.Streams, webURL)
 	}
 
 	// Fetch steps for summary
@@ -104,9 +117,9 @@ func runView(ctx context.Context, opts *ViewOptions) error {
 		steps = nil
 	}
 
-	// Handle --json flag
-	if opts.JSON {
-		return outputViewJSON(opts.Streams, pipeline, steps)
+	// Handle --json and --format flags
+	if opts.JSON || opts.Format != "" {
+		return outputViewStructured(opts.Streams, opts.Format, pipeline, steps)
 	}
 
 	// Display formatted output
@@ -118,7 +131,7 @@ func getPipelineWebURL(workspace, repoSlug string, buildNumber int) string {
 		workspace, repoSlug, buildNumber)
 }
 
-func outputViewJSON(streams *iostreams.IOStreams, pipeline *api.Pipeline, steps *api.Paginated[api.PipelineStep]) error {
+func outputViewStructured(streams *iostreams.IOStreams, format string, pipeline *api.Pipeline, steps *api.Paginated[api.PipelineStep]) error {
 	output := map[string]interface{}{
 		"build_number":       pipeline.BuildNumber,
 		"uuid":               pipeline.UUID,
@@ -170,18 +183,24 @@ func outputViewJSON(streams *iostreams.IOStreams, pipeline *api.Pipeline, steps
 					stepData["result"] = step.State.Result.Name
 				}
 			}
+			if step.StartedOn != nil {
+				stepData["duration"] = calculateStepDuration(step.StartedOn, step.CompletedOn)
+			}
+			if step.Image != nil {
+				stepData["image"] = step.Image.Name
+			}
+			if step.Stage != nil {
+				stepData["stage"] = step.Stage.Name
+			}
+			if len(step.Caches) > 0 {
+				stepData["caches"] = step.Caches
+			}
 			stepsOutput[i] = stepData
 		}
 		output["steps"] = stepsOutput
 	}
 
-	data, err := json.MarshalIndent(output, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
-	}
-
-	fmt.Fprintln(streams.Out, string(data))
-	return nil
+	return cmdutil.PrintFormatted(streams, format, output)
 }
 
 func displayPipeline(streams *iostreams.IOStreams, pipeline *api.Pipeline, steps *api.Paginated[api.PipelineStep]) error {
@@ -226,23 +245,16 @@ func displayPipeline(streams *iostreams.IOStreams, pipeline *api.Pipeline, steps
 	}
 
 	// Timestamps
-	fmt.Fprintf(streams.Out, "Started:   %s\n", cmdutil.TimeAgo(pipeline.CreatedOn))
+	fmt.Fprintf(streams.Out, "Started:   %s\n", cmdutil.FormatTime(streams, pipeline.CreatedOn))
 	if pipeline.CompletedOn != nil && !pipeline.CompletedOn.IsZero() {
-		fmt.Fprintf(streams.Out, "Completed: %s\n", cmdutil.TimeAgo(*pipeline.CompletedOn))
+		fmt.Fprintf(streams.Out, "Completed: %s\n", cmdutil.FormatTime(streams, *pipeline.CompletedOn))
 	}
 
-	// Steps summary
+	// Steps, grouped by stage
 	if steps != nil && len(steps.Values) > 0 {
 		fmt.Fprintln(streams.Out)
 		fmt.Fprintln(streams.Out, "Steps:")
-		for _, step := range steps.Values {
-			stepStatus := formatStepState(streams, step.State)
-			stepName := step.Name
-			if stepName == "" {
-				stepName = "Step"
-			}
-			fmt.Fprintf(streams.Out, "  %s %s\n", stepStatus, stepName)
-		}
+		writeStepTree(streams, steps.Values)
 	}
 
 	return nil
@@ -261,35 +273,20 @@ func formatStepState(streams *iostreams.IOStreams, state *api.PipelineStepState)
 		resultName = state.Result.Name
 	}
 
-	var icon string
-	var color string
-
 	switch {
 	case resultName == "SUCCESSFUL":
-		icon = "[ok]"
-		color = iostreams.Green
+		return streams.Style(iostreams.RoleSuccess, "[ok]")
 	case resultName == "FAILED" || resultName == "ERROR":
-		icon = "[x]"
-		color = iostreams.Red
+		return streams.Style(iostreams.RoleError, "[x]")
 	case resultName == "STOPPED":
-		icon = "[!]"
-		color = iostreams.Yellow
+		return streams.Style(iostreams.RoleWarning, "[!]")
 	case stateName == "IN_PROGRESS":
-		icon = "[>]"
-		color = iostreams.Yellow
+		return streams.Style(iostreams.RoleWarning, "[>]")
 	case stateName == "PENDING":
-		icon = "[ ]"
-		color = iostreams.Cyan
+		return streams.Style(iostreams.RoleInfo, "[ ]")
 	default:
-		icon = "[?]"
-		color = ""
-	}
-
-	if !streams.ColorEnabled() || color == "" {
-		return icon
+		return "[?]"
 	}
-
-	return color + icon + iostreams.Reset
 }
 
 // capitalize capitalizes the first letter of a string
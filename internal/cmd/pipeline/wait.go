@@ -0,0 +1,210 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+const (
+	// runWaitInterval is how often --wait checks on a pipeline run
+	runWaitInterval = 5 * time.Second
+
+	// maxPollFailures is how many checks in a row may fail with a network
+	// or server error before waiting gives up
+	maxPollFailures = 5
+
+	// maxPollBackoff caps the wait before checking again after failures
+	maxPollBackoff = time.Minute
+)
+
+// waitForPipeline waits until pipeline completes, printing each step as it
+// starts and finishes. It returns an error exiting with
+// cmdutil.ExitChecksFailed if the pipeline didn't succeed, so scripts can
+// gate on the result, and with cmdutil.ExitTimeout if timeout, unless 0,
+// passed first. Checks failing with a network or server error are retried
+// with backoff, so a blip doesn't end a long wait. With ci, failed steps and
+// the pipeline's failure are also reported as CI annotations.
+func waitForPipeline(ctx context.Context, streams *iostreams.IOStreams, ci *cmdutil.CI, client *api.Client, workspace, repoSlug string, pipeline *api.Pipeline, interval, timeout time.Duration) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	streams.Info("Waiting for pipeline #%d to complete...", pipeline.BuildNumber)
+
+	seen := map[string]string{}
+	failures := 0
+	for {
+		// Steps are read before the pipeline, so the transitions of the
+		// last steps are printed before the pipeline is seen to complete
+		steps, err := pollSteps(ctx, client, workspace, repoSlug, pipeline.UUID)
+		var latest *api.Pipeline
+		if err == nil {
+			reportStepTransitions(streams, ci, pipeline, steps, seen)
+			latest, err = pollPipeline(ctx, client, workspace, repoSlug, pipeline.UUID)
+		}
+		if ctx.Err() != nil {
+			return waitStoppedError(ctx, timeout, pipeline)
+		}
+
+		delay := interval
+		switch {
+		case err != nil && !transientError(err):
+			return err
+		case err != nil:
+			failures++
+			if failures >= maxPollFailures {
+				return fmt.Errorf("gave up after %d failed checks in a row: %w", failures, err)
+			}
+			delay = pollBackoff(interval, failures)
+			streams.Warning("Could not check on pipeline #%d, trying again in %s: %v", pipeline.BuildNumber, delay, err)
+		case latest.State != nil && latest.State.Name == "COMPLETED":
+			return pipelineResultError(streams, ci, latest, steps)
+		default:
+			failures = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return waitStoppedError(ctx, timeout, pipeline)
+		case <-time.After(delay):
+		}
+	}
+}
+
+// waitStoppedError is returned when waiting ends before the pipeline has
+// completed, exiting with cmdutil.ExitTimeout if the timeout passed
+func waitStoppedError(ctx context.Context, timeout time.Duration, pipeline *api.Pipeline) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return cmdutil.NewExitError(cmdutil.ExitTimeout,
+			fmt.Errorf("timed out after %s waiting for pipeline #%d, which is still running", timeout, pipeline.BuildNumber))
+	}
+	return fmt.Errorf("stopped waiting for pipeline #%d, which is still running", pipeline.BuildNumber)
+}
+
+// transientError reports whether err, from checking on a pipeline, may pass
+// when tried again: a network or server error rather than a refused request
+func transientError(err error) bool {
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// pollBackoff returns how long to wait after failures checks in a row have
+// failed: interval, doubled for each failure, up to maxPollBackoff
+func pollBackoff(interval time.Duration, failures int) time.Duration {
+	delay := interval << failures
+	if delay > maxPollBackoff {
+		delay = max(interval, maxPollBackoff)
+	}
+	return delay
+}
+
+func pollSteps(ctx context.Context, client *api.Client, workspace, repoSlug, pipelineUUID string) ([]api.PipelineStep, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	steps, err := client.ListPipelineSteps(ctx, workspace, repoSlug, pipelineUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pipeline steps: %w", err)
+	}
+	return steps.Values, nil
+}
+
+func pollPipeline(ctx context.Context, client *api.Client, workspace, repoSlug, pipelineUUID string) (*api.Pipeline, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	pipeline, err := client.GetPipeline(ctx, workspace, repoSlug, pipelineUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pipeline: %w", err)
+	}
+	return pipeline, nil
+}
+
+// reportStepTransitions prints the steps whose status changed since they
+// were last seen, recorded in seen by step UUID. Pending steps are not
+// printed until they start. With ci, steps that finish failed or stopped
+// are annotated.
+func reportStepTransitions(streams *iostreams.IOStreams, ci *cmdutil.CI, pipeline *api.Pipeline, steps []api.PipelineStep, seen map[string]string) {
+	for i := range steps {
+		step := &steps[i]
+		status := stepStatus(step)
+		if status == "" || status == "PENDING" || seen[step.UUID] == status {
+			continue
+		}
+		seen[step.UUID] = status
+
+		line := fmt.Sprintf("  %s  %s", formatStepStatus(streams, step.State), stepDisplayName(step, i))
+		if step.State.Result != nil && step.StartedOn != nil {
+			line += "  " + streams.Style(iostreams.RoleMuted, formatStepDuration(step.StartedOn, step.CompletedOn))
+		}
+		streams.Info("%s", line)
+		if ci != nil {
+			annotateStep(ci, fmt.Sprint(pipeline.BuildNumber), step)
+		}
+	}
+}
+
+// pipelineResultError reports how a completed pipeline finished, returning
+// an error if it didn't succeed, which is also annotated with ci
+func pipelineResultError(streams *iostreams.IOStreams, ci *cmdutil.CI, pipeline *api.Pipeline, steps []api.PipelineStep) error {
+	result := "UNKNOWN"
+	if pipeline.State.Result != nil {
+		result = pipeline.State.Result.Name
+	}
+	duration := formatDuration(pipeline.BuildSecondsUsed)
+	if pipeline.CompletedOn != nil {
+		duration = calculateDuration(pipeline.CreatedOn, pipeline.CompletedOn)
+	}
+
+	if result == "SUCCESSFUL" {
+		streams.Success("Pipeline #%d succeeded in %s", pipeline.BuildNumber, duration)
+		return nil
+	}
+
+	for i := range steps {
+		if s := stepStatus(&steps[i]); s == "FAILED" || s == "ERROR" {
+			streams.Info("View the log of the failed step with 'bb pipeline logs %d --step %d'", pipeline.BuildNumber, i+1)
+			break
+		}
+	}
+	err := fmt.Errorf("pipeline #%d finished with %s after %s", pipeline.BuildNumber, result, duration)
+	if ci != nil {
+		ci.Error("Pipeline failed", err.Error())
+	}
+	return cmdutil.NewExitError(cmdutil.ExitChecksFailed, err)
+}
+
+// stepStatus returns the result of a step, or its state if it has none
+func stepStatus(step *api.PipelineStep) string {
+	if step.State == nil {
+		return ""
+	}
+	if step.State.Result != nil {
+		return step.State.Result.Name
+	}
+	return step.State.Name
+}
+
+// stepDisplayName names the step at index i, which may not have a name
+func stepDisplayName(step *api.PipelineStep, i int) string {
+	if step.Name != "" {
+		return step.Name
+	}
+	return fmt.Sprintf("Step %d", i+1)
+}
@@ -0,0 +1,114 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// newWaitServer serves a pipeline whose checks get the responses in order,
+// the last repeated once they run out. A response is a status code and, for
+// 200, the pipeline's state.
+func newWaitServer(t *testing.T, responses ...waitResponse) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	checks := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/steps") {
+			w.Write([]byte(`{"values": [{"uuid": "{s1}", "name": "Build", "state": {"name": "IN_PROGRESS"}}]}`))
+			return
+		}
+
+		mu.Lock()
+		resp := responses[min(checks, len(responses)-1)]
+		checks++
+		mu.Unlock()
+		if resp.status != http.StatusOK {
+			w.WriteHeader(resp.status)
+			w.Write([]byte(`{"type": "error", "error": {"message": "` + http.StatusText(resp.status) + `"}}`))
+			return
+		}
+		w.Write([]byte(`{"uuid": "{p1}", "build_number": 7, "state": ` + resp.state + `}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+type waitResponse struct {
+	status int
+	state  string
+}
+
+var (
+	running     = waitResponse{http.StatusOK, `{"name": "IN_PROGRESS"}`}
+	succeeded   = waitResponse{http.StatusOK, `{"name": "COMPLETED", "result": {"name": "SUCCESSFUL"}}`}
+	failed      = waitResponse{http.StatusOK, `{"name": "COMPLETED", "result": {"name": "FAILED"}}`}
+	unavailable = waitResponse{status: http.StatusServiceUnavailable}
+	notFound    = waitResponse{status: http.StatusNotFound}
+)
+
+func TestWaitForPipeline(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []waitResponse
+		timeout   time.Duration
+		wantCode  int
+		wantErr   string
+		wantOut   string
+	}{
+		{name: "success", responses: []waitResponse{running, succeeded}, wantOut: "Pipeline #7 succeeded"},
+		{name: "failure", responses: []waitResponse{running, failed}, wantCode: cmdutil.ExitChecksFailed, wantErr: "finished with FAILED"},
+		{name: "timeout", responses: []waitResponse{running}, timeout: 50 * time.Millisecond, wantCode: cmdutil.ExitTimeout, wantErr: "timed out"},
+		{name: "transient error", responses: []waitResponse{unavailable, unavailable, succeeded}, wantOut: "trying again"},
+		{name: "client error", responses: []waitResponse{notFound, succeeded}, wantCode: cmdutil.ExitNotFound, wantErr: "failed to get pipeline"},
+		{name: "repeated errors", responses: []waitResponse{unavailable}, wantCode: cmdutil.ExitFailure, wantErr: "gave up after 5 failed checks"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newWaitServer(t, tt.responses...)
+			client := api.NewClient(api.WithBaseURL(server.URL), api.WithToken("token"))
+			var out bytes.Buffer
+			streams := &iostreams.IOStreams{Out: &out, ErrOut: &out}
+			pipeline := &api.Pipeline{UUID: "{p1}", BuildNumber: 7}
+
+			err := waitForPipeline(context.Background(), streams, nil, client, "team", "repo", pipeline, time.Millisecond, tt.timeout)
+			if code := cmdutil.ExitCode(err); code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d (error: %v)", code, tt.wantCode, err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output = %q, want it to contain %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestPollBackoff(t *testing.T) {
+	tests := []struct {
+		interval time.Duration
+		failures int
+		want     time.Duration
+	}{
+		{interval: 5 * time.Second, failures: 1, want: 10 * time.Second},
+		{interval: 5 * time.Second, failures: 2, want: 20 * time.Second},
+		{interval: 5 * time.Second, failures: 4, want: time.Minute},
+		{interval: 2 * time.Minute, failures: 1, want: 2 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := pollBackoff(tt.interval, tt.failures); got != tt.want {
+			t.Errorf("pollBackoff(%s, %d) = %s, want %s", tt.interval, tt.failures, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,420 @@
+package pr
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// approveSearchConcurrency is how many repositories are searched at once
+const approveSearchConcurrency = 8
+
+type approveOptions struct {
+	streams      *iostreams.IOStreams
+	prNumber     int
+	repo         string
+	search       string
+	author       string
+	workspace    string
+	merge        bool
+	mergeMethod  string
+	deleteBranch bool
+	dryRun       bool
+	yes          bool
+	json         bool
+}
+
+// approveResult is what happened to one pull request approved by pr approve
+type approveResult struct {
+	Repository string `json:"repository"`
+	ID         int64  `json:"id"`
+	Title      string `json:"title"`
+	URL        string `json:"url"`
+	// Status is approved, merged, skipped when the pull request is your
+	// own, failed, or with --dry-run, matched
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// NewCmdApprove creates the approve command
+func NewCmdApprove(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &approveOptions{
+		streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "approve [<number>]",
+		Short: "Approve pull requests, one or many at a time",
+		Long: `Approve a pull request, or with --search, every open pull request in a
+workspace whose title contains the search text, such as the dependency
+updates a bot opens across repositories.
+
+With --search, the matching pull requests are listed and, after you
+confirm, approved one at a time, and with --merge merged once approved.
+A pull request that can't be approved or merged is reported and the rest
+are still handled; the command fails if any of them failed. Your own pull
+requests are skipped, as Bitbucket doesn't let authors approve them.
+
+The workspace is given with --workspace, or else is the default
+workspace or that of the current repository.`,
+		Example: `  # Approve pull request #123
+  bb pr approve 123
+
+  # See which pull requests a search matches
+  bb pr approve --search "Bump lodash" --workspace myworkspace --dry-run
+
+  # Approve and squash-merge a bot's dependency updates
+  bb pr approve --search "Bump" --author renovate-bot --merge --method squash
+
+  # Approve without prompting, with results as JSON
+  bb pr approve --search "chore(deps)" --yes --json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.search == "" {
+				for _, flag := range []string{"author", "workspace", "merge", "method", "delete-branch", "dry-run", "yes", "json"} {
+					if cmd.Flags().Changed(flag) {
+						return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("--%s requires --search", flag))
+					}
+				}
+				if len(args) > 0 {
+					prNum, err := parsePRNumber(args)
+					if err != nil {
+						return err
+					}
+					opts.prNumber = prNum
+				}
+				return runApprove(cmd.Context(), opts)
+			}
+
+			if len(args) > 0 {
+				return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("give a pull request number or --search, not both"))
+			}
+			if !slices.Contains([]string{"merge", "squash", "rebase"}, opts.mergeMethod) {
+				return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("invalid merge method %q: use merge, squash or rebase", opts.mergeMethod))
+			}
+			if cmd.Flags().Changed("method") && !opts.merge {
+				return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("--method requires --merge"))
+			}
+			if opts.deleteBranch && !opts.merge {
+				return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("--delete-branch requires --merge"))
+			}
+			return runApproveSearch(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.search, "search", "s", "", "Approve the open pull requests whose title contains this text")
+	cmd.Flags().StringVar(&opts.author, "author", "", "With --search, only pull requests by the user with this nickname")
+	cmd.Flags().StringVarP(&opts.workspace, "workspace", "w", "", "Workspace to search the repositories of")
+	cmd.Flags().BoolVar(&opts.merge, "merge", false, "Merge each pull request once approved")
+	cmd.Flags().StringVar(&opts.mergeMethod, "method", "merge", "Merge method with --merge: {merge|squash|rebase}")
+	cmd.Flags().BoolVarP(&opts.deleteBranch, "delete-branch", "d", false, "Delete the source branches of merged pull requests")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "List the matching pull requests without approving them")
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Skip confirmation prompt")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output the results in JSON format")
+	cmd.Flags().StringVarP(&opts.repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+	cmd.MarkFlagsMutuallyExclusive("repo", "search")
+	_ = cmd.RegisterFlagCompletionFunc("method", cobra.FixedCompletions([]string{"merge", "squash", "rebase"}, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+// runApprove approves a single pull request
+func runApprove(ctx context.Context, opts *approveOptions) error {
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if opts.prNumber == 0 {
+		prNumber, err := currentBranchPR(ctx, workspace, repoSlug)
+		if err != nil {
+			return err
+		}
+		opts.prNumber = prNumber
+	}
+
+	if _, err := client.ApprovePullRequest(ctx, workspace, repoSlug, int64(opts.prNumber)); err != nil {
+		return fmt.Errorf("failed to approve pull request: %w", err)
+	}
+	opts.streams.Success("Approved pull request #%d", opts.prNumber)
+	return nil
+}
+
+func runApproveSearch(ctx context.Context, opts *approveOptions) error {
+	workspace := approveWorkspace(opts.workspace)
+	if workspace == "" {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("no workspace to search; use --workspace"))
+	}
+	if !opts.yes && !opts.dryRun && !opts.streams.IsStdinTTY() {
+		return fmt.Errorf("cannot confirm approval: stdin is not a terminal\nUse --yes flag to skip confirmation in non-interactive mode")
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	me, err := client.GetCurrentUser(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	progress := opts.streams.StartProgress(fmt.Sprintf("Searching pull requests in %s", workspace))
+	prs, unread, err := searchWorkspacePRs(ctx, client, workspace, approveQuery(opts.search, opts.author))
+	progress.Stop()
+	if err != nil {
+		return err
+	}
+	if len(unread) > 0 {
+		opts.streams.Warning("Could not search %d repositories: %s", len(unread), strings.Join(unread, ", "))
+	}
+
+	if len(prs) == 0 {
+		if opts.json {
+			return cmdutil.PrintJSON(opts.streams, []approveResult{})
+		}
+		return cmdutil.NewExitError(cmdutil.ExitNotFound, fmt.Errorf("no open pull requests in %s match %q", workspace, opts.search))
+	}
+
+	if opts.dryRun {
+		results := make([]approveResult, len(prs))
+		for i, pr := range prs {
+			results[i] = newApproveResult(pr)
+			results[i].Status = "matched"
+		}
+		if opts.json {
+			return cmdutil.PrintJSON(opts.streams, results)
+		}
+		return printApproveMatches(opts.streams, prs)
+	}
+
+	if !opts.yes {
+		if err := printApproveMatches(opts.streams, prs); err != nil {
+			return err
+		}
+		action := "Approve"
+		if opts.merge {
+			action = "Approve and merge"
+		}
+		if !confirm(opts.streams, fmt.Sprintf("%s these %d pull requests?", action, len(prs))) {
+			return fmt.Errorf("approval cancelled")
+		}
+	}
+
+	results := make([]approveResult, 0, len(prs))
+	failed := 0
+	for _, pr := range prs {
+		result := approveAndMerge(ctx, client, pr, me.UUID, opts)
+		results = append(results, result)
+		if result.Status == "failed" {
+			failed++
+		}
+		if opts.json {
+			continue
+		}
+		name := fmt.Sprintf("%s#%d", result.Repository, result.ID)
+		switch result.Status {
+		case "approved":
+			opts.streams.Success("Approved %s", name)
+		case "merged":
+			opts.streams.Success("Approved and merged %s", name)
+		case "skipped":
+			opts.streams.Info("Skipped %s: it is your own pull request", name)
+		default:
+			opts.streams.Error("%s: %s", name, result.Error)
+		}
+	}
+
+	if opts.json {
+		if err := cmdutil.PrintJSON(opts.streams, results); err != nil {
+			return err
+		}
+	} else if len(results) > 1 {
+		opts.streams.Info("Handled %d pull requests, %d failed", len(results), failed)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to handle %d of %d pull requests", failed, len(results))
+	}
+	return nil
+}
+
+// approveWorkspace returns the workspace to search: the given one, the
+// default workspace, or that of the current repository
+func approveWorkspace(workspace string) string {
+	if workspace != "" {
+		return workspace
+	}
+	if workspace, err := config.GetDefaultWorkspace(); err == nil && workspace != "" {
+		return workspace
+	}
+	if workspace, _, err := cmdutil.ParseRepository(""); err == nil {
+		return workspace
+	}
+	return ""
+}
+
+// approveQuery returns the BBQL filter for pull requests whose title
+// contains search, by the user with nickname author if it isn't empty
+func approveQuery(search, author string) string {
+	q := new(api.Query).
+		Raw("title ~ "+api.QuoteQueryValue(search)).
+		Eq("author.nickname", author)
+	return q.String()
+}
+
+// searchWorkspacePRs finds the open pull requests matching query in every
+// repository of workspace, sorted by repository and number. The
+// repositories that couldn't be searched are returned too.
+func searchWorkspacePRs(ctx context.Context, client *api.Client, workspace, query string) ([]api.PullRequest, []string, error) {
+	var repos []string
+	for page := 1; ; page++ {
+		result, err := client.ListRepositories(ctx, workspace, &api.RepositoryListOptions{Sort: "slug", Page: page, Limit: 100})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list repositories: %w", err)
+		}
+		for _, r := range result.Values {
+			repos = append(repos, r.Slug)
+		}
+		if result.Next == "" || len(result.Values) == 0 {
+			break
+		}
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		limiter = make(chan struct{}, approveSearchConcurrency)
+		prs     []api.PullRequest
+		unread  []string
+	)
+	for _, repo := range repos {
+		wg.Go(func() {
+			limiter <- struct{}{}
+			defer func() { <-limiter }()
+
+			var found []api.PullRequest
+			for page := 1; ; page++ {
+				result, err := client.ListPullRequests(ctx, workspace, repo, &api.PRListOptions{
+					State: api.PRStateOpen,
+					Query: query,
+					Page:  page,
+					Limit: 50,
+				})
+				if err != nil {
+					mu.Lock()
+					unread = append(unread, repo)
+					mu.Unlock()
+					return
+				}
+				found = append(found, result.Values...)
+				if result.Next == "" || len(result.Values) == 0 {
+					break
+				}
+			}
+			for i := range found {
+				// Pull requests don't always say which repository they
+				// belong to, so record it for the results
+				if found[i].Destination.Repository == nil {
+					found[i].Destination.Repository = &api.Repository{}
+				}
+				found[i].Destination.Repository.FullName = workspace + "/" + repo
+				found[i].Destination.Repository.Slug = repo
+			}
+
+			mu.Lock()
+			prs = append(prs, found...)
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+
+	if len(repos) > 0 && len(unread) == len(repos) {
+		return nil, nil, fmt.Errorf("failed to search any repository in %s", workspace)
+	}
+
+	slices.SortFunc(prs, func(a, b api.PullRequest) int {
+		return cmp.Or(
+			strings.Compare(a.Destination.Repository.FullName, b.Destination.Repository.FullName),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+	slices.Sort(unread)
+	return prs, unread, nil
+}
+
+func newApproveResult(pr api.PullRequest) approveResult {
+	return approveResult{
+		Repository: pr.Destination.Repository.FullName,
+		ID:         pr.ID,
+		Title:      pr.Title,
+		URL:        pr.Links.HTML.Href,
+	}
+}
+
+// approveAndMerge approves pr, unless its author has the UUID myUUID, and
+// with opts.merge then merges it
+func approveAndMerge(ctx context.Context, client *api.Client, pr api.PullRequest, myUUID string, opts *approveOptions) approveResult {
+	result := newApproveResult(pr)
+	result.Status = "failed"
+
+	if pr.Author.UUID != "" && pr.Author.UUID == myUUID {
+		result.Status = "skipped"
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	workspace, repoSlug, _ := strings.Cut(result.Repository, "/")
+	if _, err := client.ApprovePullRequest(ctx, workspace, repoSlug, pr.ID); err != nil {
+		result.Error = fmt.Sprintf("failed to approve: %v", err)
+		return result
+	}
+	result.Status = "approved"
+
+	if opts.merge {
+		if err := mergePullRequest(ctx, client, workspace, repoSlug, int(pr.ID), opts.mergeMethod, "", opts.deleteBranch); err != nil {
+			result.Status = "failed"
+			result.Error = fmt.Sprintf("approved, but failed to merge: %v", err)
+			return result
+		}
+		result.Status = "merged"
+	}
+	return result
+}
+
+// printApproveMatches lists the pull requests a search matched
+func printApproveMatches(streams *iostreams.IOStreams, prs []api.PullRequest) error {
+	table := cmdutil.NewTablePrinter(streams)
+	table.AddHeader("REPOSITORY", "ID", "TITLE", "AUTHOR", "UPDATED")
+	for _, pr := range prs {
+		table.AddRow(
+			pr.Destination.Repository.FullName,
+			fmt.Sprintf("#%d", pr.ID),
+			cmdutil.TruncateString(pr.Title, 60),
+			cmdutil.GetUserDisplayName(&pr.Author),
+			cmdutil.FormatTime(streams, pr.UpdatedOn),
+		)
+	}
+	return table.Render()
+}
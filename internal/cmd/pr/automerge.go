@@ -0,0 +1,239 @@
+package pr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+const (
+	// autoMergePollInterval is how often --wait checks whether a pull
+	// request is ready to merge
+	autoMergePollInterval = 30 * time.Second
+
+	// autoMergeTimeout bounds the requests made to check and merge one
+	// pull request
+	autoMergeTimeout = 60 * time.Second
+)
+
+// mergeRequirements are what the branch restrictions of a pull request's
+// destination branch require before it can be merged
+type mergeRequirements struct {
+	Approvals     int
+	PassingBuilds int
+	// Known is false when the branch restrictions couldn't be read, which
+	// takes admin access to the repository
+	Known bool
+}
+
+// mergeState says whether a pull request can be merged automatically, and
+// if not, what it is waiting for or why it never will be
+type mergeState struct {
+	Ready   bool
+	Waiting string
+	// Failed is set when a check failed, which cancels an auto-merge
+	Failed string
+	// Closed is set when the pull request was merged or declined meanwhile
+	Closed bool
+	// RequirementsKnown is false when the branch restrictions couldn't be
+	// read, so approvals were not counted
+	RequirementsKnown bool
+}
+
+// fetchMergeRequirements reads the branch restrictions that apply to
+// branch. Restrictions matching branches by type in the branching model
+// are not resolved.
+func fetchMergeRequirements(ctx context.Context, client *api.Client, workspace, repoSlug, branch string) (mergeRequirements, error) {
+	restrictions, err := client.ListBranchRestrictions(ctx, workspace, repoSlug)
+	if err != nil {
+		var apiErr *api.APIError
+		if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusForbidden || apiErr.StatusCode == http.StatusUnauthorized) {
+			return mergeRequirements{}, nil
+		}
+		return mergeRequirements{}, fmt.Errorf("failed to read branch restrictions: %w", err)
+	}
+
+	reqs := mergeRequirements{Known: true}
+	for _, r := range restrictions {
+		if r.BranchMatchKind != "glob" || r.Value == nil {
+			continue
+		}
+		if !matchBranchPattern(r.Pattern, branch) {
+			continue
+		}
+		switch r.Kind {
+		case api.RestrictionRequireApprovals:
+			reqs.Approvals = max(reqs.Approvals, *r.Value)
+		case api.RestrictionRequirePassingBuilds:
+			reqs.PassingBuilds = max(reqs.PassingBuilds, *r.Value)
+		}
+	}
+	return reqs, nil
+}
+
+// evaluateMerge decides whether pr, whose checks are statuses, can be
+// merged under reqs. Every check must have passed, and no reviewer may
+// have requested changes.
+func evaluateMerge(pr *api.PullRequest, statuses []api.CommitStatus, reqs mergeRequirements) mergeState {
+	passed, pending := 0, 0
+	for _, s := range statuses {
+		switch s.State {
+		case "SUCCESSFUL":
+			passed++
+		case "FAILED", "STOPPED":
+			return mergeState{Failed: fmt.Sprintf("check %q %s", checkName(s), stateWord(s.State))}
+		default:
+			pending++
+		}
+	}
+	if pending > 0 {
+		return mergeState{Waiting: fmt.Sprintf("%d of %d checks still running", pending, len(statuses))}
+	}
+	if passed < reqs.PassingBuilds {
+		return mergeState{Waiting: fmt.Sprintf("%d of %d required checks passed", passed, reqs.PassingBuilds)}
+	}
+
+	approvals := 0
+	for _, p := range pr.Participants {
+		if p.State == "changes_requested" {
+			return mergeState{Waiting: fmt.Sprintf("changes requested by %s", cmdutil.GetUserDisplayName(&p.User))}
+		}
+		if p.Approved {
+			approvals++
+		}
+	}
+	if approvals < reqs.Approvals {
+		return mergeState{Waiting: fmt.Sprintf("%d of %d required approvals", approvals, reqs.Approvals)}
+	}
+	return mergeState{Ready: true}
+}
+
+// matchBranchPattern reports whether branch matches a branch restriction
+// pattern, in which "*" matches any characters, slashes included
+func matchBranchPattern(pattern, branch string) bool {
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	ok, _ := regexp.MatchString(expr, branch)
+	return ok
+}
+
+func checkName(s api.CommitStatus) string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Key
+}
+
+func stateWord(state string) string {
+	if state == "STOPPED" {
+		return "was stopped"
+	}
+	return "failed"
+}
+
+// attemptAutoMerge merges the pull request of m if it is ready. It returns
+// the state the pull request is in, and whether the request is settled:
+// merged, cancelled by a failed check, or moot as the pull request closed.
+// An error merging a ready pull request settles the request too, so it isn't
+// retried on every command.
+func attemptAutoMerge(ctx context.Context, streams *iostreams.IOStreams, client *api.Client, m config.AutoMerge) (mergeState, bool, error) {
+	pr, err := client.GetPullRequest(ctx, m.Workspace, m.Repo, m.ID)
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		// The pull request or its repository is gone
+		return mergeState{Closed: true}, true, nil
+	}
+	if err != nil {
+		return mergeState{}, false, fmt.Errorf("failed to get pull request: %w", err)
+	}
+	if pr.State != api.PRStateOpen {
+		return mergeState{Closed: true}, true, nil
+	}
+
+	statuses, err := client.GetPullRequestStatuses(ctx, m.Workspace, m.Repo, m.ID)
+	if err != nil {
+		return mergeState{}, false, fmt.Errorf("failed to get status checks: %w", err)
+	}
+	reqs, err := fetchMergeRequirements(ctx, client, m.Workspace, m.Repo, pr.Destination.Branch.Name)
+	if err != nil {
+		return mergeState{}, false, err
+	}
+
+	state := evaluateMerge(pr, statuses.Values, reqs)
+	state.RequirementsKnown = reqs.Known
+	if state.Failed != "" {
+		return state, true, nil
+	}
+	if !state.Ready {
+		return state, false, nil
+	}
+	if err := completeMerge(ctx, streams, client, m.Workspace, m.Repo, pr, m.Method, m.Message, m.DeleteBranch); err != nil {
+		return state, true, err
+	}
+	return state, true, nil
+}
+
+// processAutoMerges merges the pull requests on the active host recorded
+// by "bb pr merge --auto" that have become ready, and forgets those that
+// merged or never will. Problems are reported as warnings, as this runs
+// after other commands, and go to stderr so they don't mix with the
+// command's output.
+func processAutoMerges(ctx context.Context, streams *iostreams.IOStreams) {
+	merges, err := config.LoadAutoMerges()
+	if err != nil || len(merges) == 0 {
+		return
+	}
+	host := activeHost()
+	stderr := *streams
+	stderr.Out = streams.ErrOut
+
+	var client *api.Client
+	for _, m := range merges {
+		if m.Host != host {
+			continue
+		}
+		if client == nil {
+			if client, err = cmdutil.GetAPIClient(); err != nil {
+				return
+			}
+		}
+
+		attemptCtx, cancel := context.WithTimeout(ctx, autoMergeTimeout)
+		state, settled, err := attemptAutoMerge(attemptCtx, &stderr, client, m)
+		cancel()
+		switch {
+		case err != nil && settled:
+			streams.Warning("Auto-merge of pull request #%d in %s/%s failed: %v", m.ID, m.Workspace, m.Repo, err)
+		case err != nil:
+			continue
+		case state.Failed != "":
+			streams.Warning("Auto-merge of pull request #%d in %s/%s cancelled: %s", m.ID, m.Workspace, m.Repo, state.Failed)
+		}
+		if settled {
+			if _, err := config.RemoveAutoMerge(m); err != nil {
+				streams.Warning("could not update auto-merge requests: %v", err)
+			}
+		}
+	}
+}
+
+// activeHost returns the host commands run against
+func activeHost() string {
+	hosts, err := config.LoadHostsConfig()
+	if err != nil {
+		return config.DefaultHost
+	}
+	host, _, err := config.ActiveAccount(hosts)
+	if err != nil {
+		return config.DefaultHost
+	}
+	return host
+}
@@ -1,15 +1,14 @@
 package pr
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"os/exec"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/rbansal42/bitbucket-cli/internal/api"
 	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
 	"github.com/rbansal42/bitbucket-cli/internal/git"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
@@ -20,6 +19,7 @@ type checkoutOptions struct {
 	prNumber int
 	repo     string
 	force    bool
+	protocol string
 }
 
 // NewCmdCheckout creates the checkout command
@@ -35,16 +35,26 @@ func NewCmdCheckout(streams *iostreams.IOStreams) *cobra.Command {
 
 This command fetches the pull request's source branch from the remote
 and creates a local branch to track it. If the local branch already exists,
-use --force to overwrite it.`,
+use --force to overwrite it.
+
+Pull requests from forks are fetched straight from the fork, over the
+protocol given by --protocol or the git_protocol configured for the host.
+
+The pull request number is recorded in the branch's git config, so
+"bb pr view", "bb pr merge" and "bb pr checks" without a number act on it.`,
 		Example: `  # Check out pull request #123
   bb pr checkout 123
 
   # Force overwrite existing local branch
   bb pr checkout 123 --force
 
+  # Fetch a pull request from a fork over SSH
+  bb pr checkout 123 --protocol ssh
+
   # Check out from a specific repository
   bb pr checkout 123 --repo workspace/repo`,
-		Args: cobra.ExactArgs(1),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: cmdutil.CompleteOpenPRs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
 			opts.prNumber, err = parsePRNumber(args)
@@ -63,11 +73,17 @@ use --force to overwrite it.`,
 
 	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Overwrite existing local branch")
 	cmd.Flags().StringVarP(&opts.repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+	cmdutil.AddProtocolFlag(cmd, &opts.protocol)
 
 	return cmd
 }
 
 func runCheckout(opts *checkoutOptions) error {
+	protocol, err := cmdutil.GitProtocol(opts.protocol)
+	if err != nil {
+		return err
+	}
+
 	// Resolve repository
 	workspace, repoSlug, err := cmdutil.ParseRepository(opts.repo)
 	if err != nil {
@@ -97,42 +113,68 @@ func runCheckout(opts *checkoutOptions) error {
 	}
 
 	// Check if local branch exists
-	localBranchExists := branchExists(sourceBranch)
+	localBranchExists := git.BranchExists(sourceBranch)
 
 	if localBranchExists && !opts.force {
 		return fmt.Errorf("branch '%s' already exists locally. Use --force to overwrite", sourceBranch)
 	}
 
-	// Determine remote name (default to origin)
-	remote, err := git.GetDefaultRemote()
-	if err != nil {
-		return fmt.Errorf("failed to get remote: %w", err)
+	// Fetch from the default remote, or from the fork the pull request
+	// comes from
+	var remote, forkURL string
+	if source := pr.Source.Repository; source != nil && source.FullName != "" &&
+		!strings.EqualFold(source.FullName, workspace+"/"+repoSlug) {
+		forkURL, err = forkCloneURL(ctx, client, source.FullName, protocol)
+		if err != nil {
+			return err
+		}
+		remote = forkURL
+	} else {
+		defaultRemote, err := git.GetDefaultRemote()
+		if err != nil {
+			return fmt.Errorf("failed to get remote: %w", err)
+		}
+		remote = defaultRemote.Name
 	}
 
 	// Fetch the branch
 	if localBranchExists && opts.force {
 		// Delete the existing branch first (if not currently checked out)
-		currentBranch, _ := git.GetCurrentBranch()
+		currentBranch, _ := git.CurrentBranch()
 		if currentBranch == sourceBranch {
 			return fmt.Errorf("cannot overwrite branch '%s' while it is checked out", sourceBranch)
 		}
-		if err := deleteBranch(sourceBranch, true); err != nil {
-			return fmt.Errorf("failed to delete existing branch: %w", err)
+		if err := git.DeleteBranch(sourceBranch, true); err != nil {
+			return err
 		}
 	}
 
 	// Fetch and create tracking branch
 	refspec := fmt.Sprintf("%s:%s", sourceBranch, sourceBranch)
-	if err := git.Fetch(remote.Name, refspec); err != nil {
+	// git may ask for credentials on the terminal, so no spinner runs
+	// over it
+	opts.streams.Info("Fetching %s...", sourceBranch)
+	if err := git.Fetch(remote, refspec); err != nil {
 		return fmt.Errorf("failed to fetch branch: %w", err)
 	}
 
 	// Set up tracking
-	if err := setUpstreamTracking(sourceBranch, remote.Name); err != nil {
+	if forkURL != "" {
+		err = git.SetUpstreamURL(sourceBranch, forkURL)
+	} else {
+		err = git.SetUpstream(sourceBranch, remote)
+	}
+	if err != nil {
 		// Non-fatal, just warn
 		opts.streams.Warning("Could not set upstream tracking: %v", err)
 	}
 
+	// Remember the pull request for commands run on the branch without a
+	// number
+	if err := recordBranchPR(sourceBranch, pr.ID); err != nil {
+		opts.streams.Warning("%v", err)
+	}
+
 	// Checkout the branch
 	if err := git.Checkout(sourceBranch); err != nil {
 		return fmt.Errorf("failed to checkout branch: %w", err)
@@ -142,29 +184,18 @@ func runCheckout(opts *checkoutOptions) error {
 	return nil
 }
 
-// branchExists checks if a local branch exists
-func branchExists(branch string) bool {
-	cmd := exec.Command("git", "rev-parse", "--verify", branch)
-	return cmd.Run() == nil
-}
-
-// deleteBranch deletes a local branch
-func deleteBranch(branch string, force bool) error {
-	flag := "-d"
-	if force {
-		flag = "-D"
-	}
-	cmd := exec.Command("git", "branch", flag, branch)
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("%s", strings.TrimSpace(stderr.String()))
+// forkCloneURL returns the URL to fetch from the repository fullName, in
+// WORKSPACE/REPO format, over protocol
+func forkCloneURL(ctx context.Context, client *api.Client, fullName, protocol string) (string, error) {
+	forkWorkspace, forkSlug, _ := strings.Cut(fullName, "/")
+	fork, err := client.GetRepository(ctx, forkWorkspace, forkSlug)
+	if err != nil {
+		return "", fmt.Errorf("failed to get source repository %s: %w", fullName, err)
 	}
-	return nil
-}
 
-// setUpstreamTracking sets the upstream tracking branch
-func setUpstreamTracking(branch, remote string) error {
-	cmd := exec.Command("git", "branch", "--set-upstream-to="+remote+"/"+branch, branch)
-	return cmd.Run()
+	url := cmdutil.CloneURL(fork.Links, protocol)
+	if url == "" {
+		return "", fmt.Errorf("no clone URL found for %s", fullName)
+	}
+	return url, nil
 }
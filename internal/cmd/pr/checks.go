@@ -2,10 +2,9 @@ package pr
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strconv"
-	"text/tabwriter"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -20,6 +19,8 @@ type ChecksOptions struct {
 	Repo    string
 	PRID    int64
 	JSON    bool
+	Format  string
+	CI      string
 	Streams *iostreams.IOStreams
 }
 
@@ -28,41 +29,67 @@ func NewCmdChecks(streams *iostreams.IOStreams) *cobra.Command {
 	opts := &ChecksOptions{Streams: streams}
 
 	cmd := &cobra.Command{
-		Use:   "checks <number>",
+		Use:   "checks [<number>]",
 		Short: "View status checks for a pull request",
 		Long: `View the status of CI/CD checks for a pull request.
 
 Shows build statuses, pipeline results, and other commit statuses
-associated with the pull request.`,
+associated with the pull request. Without a number, the pull request of
+the current branch is used.
+
+Exits with status 8 if any check failed.
+
+When run in GitHub Actions or TeamCity, or with --ci auto, the checks are
+printed in a collapsible group and each failed or stopped check is also
+reported as an annotation.`,
 		Example: `  # View checks for PR #123
   bb pr checks 123
 
+  # View checks for the current branch's pull request
+  bb pr checks
+
   # View checks with JSON output
   bb pr checks 123 --json
 
+  # Output as YAML
+  bb pr checks 123 --format yaml
+
   # View checks for a specific repository
-  bb pr checks 123 --repo workspace/repo`,
-		Args: cobra.ExactArgs(1),
+  bb pr checks 123 --repo workspace/repo
+
+  # Report failed checks as TeamCity build problems
+  bb pr checks 123 --ci teamcity`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: cmdutil.CompleteOpenPRs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			id, err := strconv.ParseInt(args[0], 10, 64)
-			if err != nil {
-				return fmt.Errorf("invalid pull request number: %s", args[0])
-			}
-			if id <= 0 {
-				return fmt.Errorf("invalid pull request number: must be a positive integer")
+			if len(args) > 0 {
+				id, err := strconv.ParseInt(args[0], 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid pull request number: %s", args[0])
+				}
+				if id <= 0 {
+					return fmt.Errorf("invalid pull request number: must be a positive integer")
+				}
+				opts.PRID = id
 			}
-			opts.PRID = id
 			return runChecks(cmd.Context(), opts)
 		},
 	}
 
 	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
 	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
+	cmdutil.AddCIFlag(cmd, &opts.CI)
 
 	return cmd
 }
 
 func runChecks(ctx context.Context, opts *ChecksOptions) error {
+	ci, err := cmdutil.NewCI(opts.CI, opts.Streams.Out)
+	if err != nil {
+		return err
+	}
+
 	// Parse repository
 	workspace, repoSlug, err := cmdutil.ParseRepository(opts.Repo)
 	if err != nil {
@@ -79,6 +106,15 @@ func runChecks(ctx context.Context, opts *ChecksOptions) error {
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
+	// Without a number, use the current branch's pull request
+	if opts.PRID == 0 {
+		number, err := currentBranchPR(ctx, workspace, repoSlug)
+		if err != nil {
+			return err
+		}
+		opts.PRID = int64(number)
+	}
+
 	// Get statuses
 	result, err := client.GetPullRequestStatuses(ctx, workspace, repoSlug, opts.PRID)
 	if err != nil {
@@ -91,14 +127,37 @@ func runChecks(ctx context.Context, opts *ChecksOptions) error {
 	}
 
 	// Output
-	if opts.JSON {
-		return outputChecksJSON(opts.Streams, result.Values)
+	switch {
+	case opts.JSON || opts.Format != "":
+		err = outputChecksStructured(opts.Streams, opts.Format, result.Values)
+	case ci != nil:
+		err = outputChecksCI(opts.Streams, ci, opts.PRID, result.Values)
+	default:
+		err = outputChecksTable(opts.Streams, result.Values)
+	}
+	if err != nil {
+		return err
 	}
 
-	return outputChecksTable(opts.Streams, result.Values)
+	return checksFailedError(result.Values)
+}
+
+// checksFailedError returns an error exiting with cmdutil.ExitChecksFailed
+// if any check failed, so scripts can gate on the result.
+func checksFailedError(statuses []api.CommitStatus) error {
+	failed := 0
+	for _, s := range statuses {
+		if s.State == "FAILED" {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	return cmdutil.NewExitError(cmdutil.ExitChecksFailed, fmt.Errorf("%d of %d checks failed", failed, len(statuses)))
 }
 
-func outputChecksJSON(streams *iostreams.IOStreams, statuses []api.CommitStatus) error {
+func outputChecksStructured(streams *iostreams.IOStreams, format string, statuses []api.CommitStatus) error {
 	output := make([]map[string]interface{}, len(statuses))
 	for i, s := range statuses {
 		output[i] = map[string]interface{}{
@@ -112,65 +171,72 @@ func outputChecksJSON(streams *iostreams.IOStreams, statuses []api.CommitStatus)
 		}
 	}
 
-	data, err := json.MarshalIndent(output, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
-	}
-
-	fmt.Fprintln(streams.Out, string(data))
-	return nil
+	return cmdutil.PrintFormatted(streams, format, output)
 }
 
 func outputChecksTable(streams *iostreams.IOStreams, statuses []api.CommitStatus) error {
-	w := tabwriter.NewWriter(streams.Out, 0, 0, 2, ' ', 0)
+	table := cmdutil.NewTablePrinter(streams)
 
 	// Header
-	header := "STATUS\tNAME\tDESCRIPTION"
-	if streams.ColorEnabled() {
-		fmt.Fprintln(w, iostreams.Bold+header+iostreams.Reset)
-	} else {
-		fmt.Fprintln(w, header)
-	}
+	table.AddHeader("STATUS", "NAME", "DESCRIPTION")
 
 	// Rows
 	for _, s := range statuses {
-		status := formatCheckStatus(s.State, streams.ColorEnabled())
+		status := formatCheckStatus(streams, s.State)
 		name := s.Name
 		if name == "" {
 			name = s.Key
 		}
-		desc := cmdutil.TruncateString(s.Description, 50)
+		desc := s.Description
+
+		table.AddRow(status, name, desc)
+	}
+
+	return table.Render()
+}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\n", status, name, desc)
+// outputChecksCI prints the checks table in a CI log group, followed by an
+// annotation for each check that failed or was stopped
+func outputChecksCI(streams *iostreams.IOStreams, ci *cmdutil.CI, prID int64, statuses []api.CommitStatus) error {
+	group := fmt.Sprintf("Checks of pull request #%d", prID)
+	ci.Group(group)
+	err := outputChecksTable(streams, statuses)
+	ci.EndGroup(group)
+	if err != nil {
+		return err
 	}
 
-	return w.Flush()
+	for _, s := range statuses {
+		name := s.Name
+		if name == "" {
+			name = s.Key
+		}
+		message := s.Description
+		if s.URL != "" {
+			message = strings.TrimSpace(message + " " + s.URL)
+		}
+		switch s.State {
+		case "FAILED":
+			ci.Error(name+" failed", message)
+		case "STOPPED":
+			ci.Warning(name+" was stopped", message)
+		}
+	}
+	return nil
 }
 
 // formatCheckStatus formats the check status with optional color
-func formatCheckStatus(state string, color bool) string {
+func formatCheckStatus(streams *iostreams.IOStreams, state string) string {
 	// States: SUCCESSFUL, FAILED, INPROGRESS, STOPPED
 	switch state {
 	case "SUCCESSFUL":
-		if color {
-			return iostreams.Green + "✓ pass" + iostreams.Reset
-		}
-		return "✓ pass"
+		return streams.Style(iostreams.RoleSuccess, streams.Symbol("✓")+"pass")
 	case "FAILED":
-		if color {
-			return iostreams.Red + "✗ fail" + iostreams.Reset
-		}
-		return "✗ fail"
+		return streams.Style(iostreams.RoleError, streams.Symbol("✗")+"fail")
 	case "INPROGRESS":
-		if color {
-			return iostreams.Yellow + "○ running" + iostreams.Reset
-		}
-		return "○ running"
+		return streams.Style(iostreams.RoleWarning, streams.Symbol("○")+"running")
 	case "STOPPED":
-		if color {
-			return iostreams.White + "◌ stopped" + iostreams.Reset
-		}
-		return "◌ stopped"
+		return streams.Style(iostreams.RoleMuted, streams.Symbol("◌")+"stopped")
 	default:
 		return state
 	}
@@ -37,7 +37,8 @@ Optionally, you can add a comment explaining why the PR is being closed.`,
 
   # Close a PR in a specific repository
   bb pr close 123 --repo workspace/repo`,
-		Args: cobra.MaximumNArgs(1),
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: cmdutil.CompleteOpenPRs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runClose(opts, args)
 		},
@@ -2,7 +2,10 @@ package pr
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -29,7 +32,10 @@ func NewCmdComment(streams *iostreams.IOStreams) *cobra.Command {
 		Long: `Add a comment to a pull request.
 
 If the comment body is not provided via --body, an editor will be opened
-for you to enter the comment text.`,
+for you to enter the comment text.
+
+Use 'bb pr comment resolve' and 'bb pr comment unresolve' to close and
+reopen comment threads.`,
 		Example: `  # Add a comment to pull request #123 (opens editor)
   bb pr comment 123
 
@@ -37,8 +43,12 @@ for you to enter the comment text.`,
   bb pr comment 123 --body "This looks great!"
 
   # Add a comment to a PR in a specific repository
-  bb pr comment 123 --repo workspace/repo --body "LGTM"`,
-		Args: cobra.MaximumNArgs(1),
+  bb pr comment 123 --repo workspace/repo --body "LGTM"
+
+  # Resolve the thread started by comment 456
+  bb pr comment resolve 123 456`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: cmdutil.CompleteOpenPRs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runComment(opts, args)
 		},
@@ -47,6 +57,9 @@ for you to enter the comment text.`,
 	cmd.Flags().StringVarP(&opts.body, "body", "b", "", "Comment body text")
 	cmd.Flags().StringVarP(&opts.repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
 
+	cmd.AddCommand(newCmdCommentResolve(streams, true))
+	cmd.AddCommand(newCmdCommentResolve(streams, false))
+
 	return cmd
 }
 
@@ -63,14 +76,14 @@ func runComment(opts *commentOptions, args []string) error {
 
 	// If no body provided, open editor
 	if opts.body == "" {
-		body, err := openEditor("")
+		content, err := cmdutil.OpenEditor("")
+		if errors.Is(err, cmdutil.ErrEditorAborted) {
+			return fmt.Errorf("comment body is required")
+		}
 		if err != nil {
 			return fmt.Errorf("failed to get comment: %w", err)
 		}
-		if body == "" {
-			return fmt.Errorf("comment body is required")
-		}
-		opts.body = body
+		opts.body = content.Body
 	}
 
 	client, err := cmdutil.GetAPIClient()
@@ -112,3 +125,119 @@ func runComment(opts *commentOptions, args []string) error {
 
 	return nil
 }
+
+type resolveOptions struct {
+	streams   *iostreams.IOStreams
+	repo      string
+	prNumber  int
+	commentID int64
+	resolve   bool
+}
+
+// newCmdCommentResolve creates the comment resolve command, or when resolve
+// isn't set, the comment unresolve command
+func newCmdCommentResolve(streams *iostreams.IOStreams, resolve bool) *cobra.Command {
+	opts := &resolveOptions{
+		streams: streams,
+		resolve: resolve,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "resolve <number> <comment-id>",
+		Short: "Resolve a comment thread on a pull request",
+		Long: `Resolve the thread a comment on a pull request starts, marking the
+discussion as done.
+
+Comment IDs are shown next to the comments that start threads in
+'bb pr view --comments'. Replies can't be resolved on their own: resolve
+the comment that starts their thread.`,
+		Example: `  # Resolve the thread started by comment 456 on pull request #123
+  bb pr comment resolve 123 456`,
+	}
+	if !resolve {
+		cmd.Use = "unresolve <number> <comment-id>"
+		cmd.Short = "Reopen a resolved comment thread on a pull request"
+		cmd.Long = `Reopen the resolved thread a comment on a pull request starts.
+
+Comment IDs are shown next to the comments that start threads in
+'bb pr view --comments'.`
+		cmd.Example = `  # Reopen the thread started by comment 456 on pull request #123
+  bb pr comment unresolve 123 456`
+	}
+	cmd.Args = cobra.ExactArgs(2)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		prNum, err := parsePRNumber(args)
+		if err != nil {
+			return err
+		}
+		commentID, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || commentID <= 0 {
+			return fmt.Errorf("invalid comment ID: %s", args[1])
+		}
+		opts.prNumber, opts.commentID = prNum, commentID
+		return runCommentResolve(cmd.Context(), opts)
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+
+	return cmd
+}
+
+func runCommentResolve(ctx context.Context, opts *resolveOptions) error {
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	changed, err := setCommentResolution(ctx, client, workspace, repoSlug, int64(opts.prNumber), opts.commentID, opts.resolve)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !changed && opts.resolve:
+		opts.streams.Info("The thread of comment %d is already resolved", opts.commentID)
+	case !changed:
+		opts.streams.Info("The thread of comment %d isn't resolved", opts.commentID)
+	case opts.resolve:
+		opts.streams.Success("Resolved the thread of comment %d on pull request #%d", opts.commentID, opts.prNumber)
+	default:
+		opts.streams.Success("Reopened the thread of comment %d on pull request #%d", opts.commentID, opts.prNumber)
+	}
+	return nil
+}
+
+// setCommentResolution resolves, or when resolve isn't set reopens, the
+// thread comment commentID starts, reporting whether it changed: a thread
+// already in that state is left alone
+func setCommentResolution(ctx context.Context, client *api.Client, workspace, repoSlug string, prID, commentID int64, resolve bool) (bool, error) {
+	comment, err := client.GetPRComment(ctx, workspace, repoSlug, prID, commentID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get comment %d: %w", commentID, err)
+	}
+	if comment.Parent != nil {
+		return false, cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("comment %d is a reply to comment %d; give the comment that starts the thread", commentID, comment.Parent.ID))
+	}
+	if (comment.Resolution != nil) == resolve {
+		return false, nil
+	}
+
+	if resolve {
+		if _, err := client.ResolvePRComment(ctx, workspace, repoSlug, prID, commentID); err != nil {
+			return false, fmt.Errorf("failed to resolve comment %d: %w", commentID, err)
+		}
+		return true, nil
+	}
+	if err := client.ReopenPRComment(ctx, workspace, repoSlug, prID, commentID); err != nil {
+		return false, fmt.Errorf("failed to reopen comment %d: %w", commentID, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,217 @@
+package pr
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// threadContextLines is how many lines of the diff are shown above the
+// line an inline thread is on
+const threadContextLines = 3
+
+// commentThread is a comment and the replies to it, oldest first
+type commentThread struct {
+	comment api.PRComment
+	replies []*commentThread
+}
+
+// buildThreads nests comments under the comments they reply to. Threads
+// and replies are ordered oldest first. A reply whose parent isn't among
+// comments starts a thread of its own.
+func buildThreads(comments []api.PRComment) []*commentThread {
+	sorted := append([]api.PRComment(nil), comments...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CreatedOn.Before(sorted[j].CreatedOn) })
+
+	nodes := make(map[int64]*commentThread, len(sorted))
+	for _, c := range sorted {
+		nodes[c.ID] = &commentThread{comment: c}
+	}
+
+	var threads []*commentThread
+	for _, c := range sorted {
+		node := nodes[c.ID]
+		if c.Parent != nil {
+			if parent, ok := nodes[c.Parent.ID]; ok && parent != node {
+				parent.replies = append(parent.replies, node)
+				continue
+			}
+		}
+		threads = append(threads, node)
+	}
+	return pruneDeleted(threads)
+}
+
+// pruneDeleted drops deleted comments that no remaining reply hangs from
+func pruneDeleted(threads []*commentThread) []*commentThread {
+	var kept []*commentThread
+	for _, t := range threads {
+		t.replies = pruneDeleted(t.replies)
+		if t.comment.Deleted && len(t.replies) == 0 {
+			continue
+		}
+		kept = append(kept, t)
+	}
+	return kept
+}
+
+// displayComments writes comment threads: the general conversation first,
+// then inline threads grouped by file and ordered by line, each below the
+// lines of files it is on
+func displayComments(streams *iostreams.IOStreams, threads []*commentThread, files []diffFile, raw bool) {
+	var general []*commentThread
+	inline := map[string][]*commentThread{}
+	var paths []string
+	for _, t := range threads {
+		if t.comment.Inline == nil {
+			general = append(general, t)
+			continue
+		}
+		p := t.comment.Inline.Path
+		if _, ok := inline[p]; !ok {
+			paths = append(paths, p)
+		}
+		inline[p] = append(inline[p], t)
+	}
+	sort.Strings(paths)
+
+	out := streams.Out
+	if len(general) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, streams.Style(iostreams.RoleHeader, "Conversation"))
+		for _, t := range general {
+			fmt.Fprintln(out)
+			writeThread(streams, t, 1, raw)
+		}
+	}
+
+	for _, p := range paths {
+		fileThreads := inline[p]
+		sort.SliceStable(fileThreads, func(i, j int) bool {
+			return threadLine(fileThreads[i]) < threadLine(fileThreads[j])
+		})
+
+		var file *diffFile
+		for i := range files {
+			if files[i].Path == p || files[i].OldPath == p {
+				file = &files[i]
+			}
+		}
+
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, streams.Style(iostreams.RoleHeader, p))
+		for _, t := range fileThreads {
+			fmt.Fprintln(out)
+			inl := t.comment.Inline
+			location := "File comment"
+			if line := threadLine(t); line > 0 {
+				location = fmt.Sprintf("Line %d", line)
+				if inl.To == 0 {
+					location += " (old)"
+				}
+			}
+			if r := t.comment.Resolution; r != nil {
+				location += " · " + streams.Style(iostreams.RoleSuccess, streams.Symbol("✓")+fmt.Sprintf("Resolved by %s %s",
+					cmdutil.GetUserDisplayName(&r.User), cmdutil.FormatTime(streams, r.CreatedOn)))
+			}
+			fmt.Fprintf(out, "  %s\n", location)
+			if file != nil && (inl.From != 0 || inl.To != 0) {
+				writeThreadContext(streams, file, inl.From, inl.To)
+			}
+			writeThread(streams, t, 1, raw)
+		}
+	}
+}
+
+// threadLine returns the line an inline thread is on, in the new version of
+// the file or else the old one, or 0 for a comment on the whole file
+func threadLine(t *commentThread) int {
+	if t.comment.Inline.To != 0 {
+		return t.comment.Inline.To
+	}
+	return t.comment.Inline.From
+}
+
+// writeThreadContext writes the line of file's diff a thread is on, with
+// the lines above it. Nothing is written if the diff no longer has the
+// line, as when the comment is on an outdated revision.
+func writeThreadContext(streams *iostreams.IOStreams, file *diffFile, from, to int) {
+	i := file.findLine(from, to)
+	if i < 0 {
+		fmt.Fprintf(streams.Out, "    %s\n", streams.Style(iostreams.RoleMuted, "(outdated: the line is no longer in the diff)"))
+		return
+	}
+	for j := max(0, i-threadContextLines); j <= i; j++ {
+		l := file.Lines[j]
+		if l.Kind == diffHunk {
+			continue
+		}
+		text := formatDiffRow(l)
+		switch l.Kind {
+		case diffAdded:
+			text = streams.Style(iostreams.RoleAddition, text)
+		case diffRemoved:
+			text = streams.Style(iostreams.RoleDeletion, text)
+		}
+		marker := " "
+		if j == i {
+			marker = ">"
+		}
+		fmt.Fprintf(streams.Out, "  %s%s\n", marker, text)
+	}
+}
+
+// writeThread writes a comment and its replies, each reply indented below
+// the comment it answers
+func writeThread(streams *iostreams.IOStreams, t *commentThread, depth int, raw bool) {
+	c := t.comment
+	indent := strings.Repeat("  ", depth)
+
+	verb := "commented"
+	if depth > 1 {
+		verb = "replied"
+	}
+	header := fmt.Sprintf("%s %s %s", cmdutil.GetUserDisplayName(&c.User), verb, cmdutil.FormatTime(streams, c.CreatedOn))
+	if depth > 1 {
+		header = "↳ " + header
+	}
+	header = streams.Style(iostreams.RoleHeader, header)
+	if depth == 1 {
+		// The ID to resolve the thread by
+		header += " " + streams.Style(iostreams.RoleMuted, fmt.Sprintf("#%d", c.ID))
+	}
+	fmt.Fprintln(streams.Out, indent+header)
+	if c.Resolution != nil && c.Inline == nil && depth == 1 {
+		fmt.Fprintln(streams.Out, indent+streams.Style(iostreams.RoleSuccess, streams.Symbol("✓")+fmt.Sprintf("Resolved by %s %s",
+			cmdutil.GetUserDisplayName(&c.Resolution.User), cmdutil.FormatTime(streams, c.Resolution.CreatedOn))))
+	}
+
+	body := c.Content.Raw
+	switch {
+	case c.Deleted:
+		body = streams.Style(iostreams.RoleMuted, "(comment deleted)")
+	case !raw:
+		body = cmdutil.RenderMarkdown(streams, body)
+	}
+	writeIndented(streams.Out, indent+"  ", body)
+
+	for _, r := range t.replies {
+		writeThread(streams, r, depth+1, raw)
+	}
+}
+
+// writeIndented writes text with every line indented by indent
+func writeIndented(w io.Writer, indent, text string) {
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		if line == "" {
+			fmt.Fprintln(w)
+			continue
+		}
+		fmt.Fprintln(w, indent+line)
+	}
+}
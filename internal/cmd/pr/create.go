@@ -1,13 +1,10 @@
 package pr
 
 import (
-	"bufio"
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"os/exec"
 	"strings"
 	"time"
 
@@ -16,6 +13,7 @@ import (
 	"github.com/rbansal42/bitbucket-cli/internal/api"
 	"github.com/rbansal42/bitbucket-cli/internal/browser"
 	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
 	"github.com/rbansal42/bitbucket-cli/internal/git"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
@@ -30,7 +28,11 @@ type createOptions struct {
 	fill             bool
 	draft            bool
 	web              bool
+	copy             bool
 	noMaintainerEdit bool
+	push             bool
+	noPush           bool
+	stack            bool
 	repo             string
 }
 
@@ -49,7 +51,26 @@ The current branch will be used as the source branch. By default, the destinatio
 branch is the repository's default branch (usually main or master).
 
 If --title is not provided, you will be prompted to enter a title interactively.
-If --body is not provided, an editor will open for you to write the description.`,
+If --body is not provided, an editor will open for you to write the description.
+
+A .bb.yml file in the repository can set the destination branch, default
+reviewers, a title prefix, a description template and whether the source
+branch is closed on merge.
+
+The description starts from, in order of preference, BB_PR_TEMPLATE, the
+template named in .bb.yml, .bitbucket/PULL_REQUEST_TEMPLATE.md in the
+repository, or the pr_template config key. BB_PR_TEMPLATE and pr_template
+can be a file path or the template text.
+
+If the current branch is not on the remote yet, or has commits that are not,
+you are offered to push it with "git push -u" before the pull request is
+created. Without a terminal to ask on, a branch that is not on the remote
+is an error, and unpushed commits are left out; use --push to push without
+asking, or --no-push to skip this.
+
+With --stack, the pull request targets the branch of the open pull request
+it builds on, found as the nearest ancestor of the current branch among the
+source branches checked out locally. See "bb pr stack".`,
 		Example: `  # Create a pull request interactively
   bb pr create
 
@@ -66,7 +87,19 @@ If --body is not provided, an editor will open for you to write the description.
   bb pr create --title "My PR" --reviewer user1 --reviewer user2
 
   # Create and open in browser
-  bb pr create --title "My PR" --web`,
+  bb pr create --title "My PR" --web
+
+  # Create a PR and copy its URL to the clipboard
+  bb pr create --title "My PR" --copy
+
+  # Create a PR on top of the one for the branch this one was started from
+  bb pr create --stack --fill
+
+  # Create a PR without pushing the current branch first
+  bb pr create --title "My PR" --no-push
+
+  # Push the current branch without asking, e.g. in a script
+  bb pr create --title "My PR" --push`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runCreate(opts)
 		},
@@ -76,10 +109,19 @@ If --body is not provided, an editor will open for you to write the description.
 	cmd.Flags().StringVarP(&opts.body, "body", "b", "", "Body/description of the pull request")
 	cmd.Flags().StringVar(&opts.baseBranch, "base", "", "Base branch (destination). Defaults to repository's default branch")
 	cmd.Flags().StringVar(&opts.headBranch, "head", "", "Head branch (source). Defaults to current branch")
+	_ = cmd.RegisterFlagCompletionFunc("base", cmdutil.CompleteBranches)
+	_ = cmd.RegisterFlagCompletionFunc("head", cmdutil.CompleteBranches)
 	cmd.Flags().StringArrayVarP(&opts.reviewers, "reviewer", "r", nil, "Add reviewer by username (can be repeated)")
+	_ = cmd.RegisterFlagCompletionFunc("reviewer", cmdutil.CompleteMembers)
 	cmd.Flags().BoolVar(&opts.fill, "fill", false, "Auto-fill title and body from commits")
 	cmd.Flags().BoolVarP(&opts.draft, "draft", "d", false, "Create as draft (adds [DRAFT] prefix to title)")
 	cmd.Flags().BoolVarP(&opts.web, "web", "w", false, "Open the created pull request in the browser")
+	cmdutil.AddCopyFlag(cmd, &opts.copy, "created pull request")
+	cmd.Flags().BoolVar(&opts.stack, "stack", false, "Target the pull request this branch builds on instead of the default branch")
+	cmd.MarkFlagsMutuallyExclusive("stack", "base")
+	cmd.Flags().BoolVar(&opts.push, "push", false, "Push the current branch before creating the pull request without asking")
+	cmd.Flags().BoolVar(&opts.noPush, "no-push", false, "Do not push the current branch before creating the pull request")
+	cmd.MarkFlagsMutuallyExclusive("push", "no-push")
 	cmd.Flags().BoolVar(&opts.noMaintainerEdit, "no-maintainer-edit", false, "Disable maintainer edits (not supported by Bitbucket)")
 	cmd.Flags().StringVarP(&opts.repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
 
@@ -93,12 +135,21 @@ func runCreate(opts *createOptions) error {
 		return err
 	}
 
+	repoConfig, err := cmdutil.LoadRepoConfig(opts.repo)
+	if err != nil {
+		return err
+	}
+
 	// Get current branch as head if not specified
+	var localBranch string
 	if opts.headBranch == "" {
-		opts.headBranch, err = git.GetCurrentBranch()
+		localBranch, err = git.CurrentBranch()
 		if err != nil {
 			return fmt.Errorf("could not determine current branch: %w", err)
 		}
+		// The pull request's source is the branch pushed to, which can be
+		// named differently from the local one
+		opts.headBranch = git.RemoteBranchName(localBranch)
 	}
 
 	// Prevent creating PR from main/master
@@ -115,9 +166,25 @@ func runCreate(opts *createOptions) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
+	// Stack on the pull request of the nearest ancestor branch
+	if opts.stack {
+		if localBranch == "" {
+			return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("--stack works on the current branch and cannot be used with --head"))
+		}
+		parent, err := stackParent(ctx, client, workspace, repoSlug, localBranch)
+		if err != nil {
+			return err
+		}
+		opts.baseBranch = parent.Source.Branch.Name
+		opts.streams.Info("Stacking on pull request #%d (%s)", parent.ID, parent.Source.Branch.Name)
+	}
+
 	// Get default branch if base not specified
 	if opts.baseBranch == "" {
-		defaultBranch, err := getDefaultBranch(ctx, client, workspace, repoSlug)
+		opts.baseBranch = repoConfig.PR.DefaultBranch
+	}
+	if opts.baseBranch == "" {
+		defaultBranch, err := cmdutil.DefaultBranch(ctx, client, workspace, repoSlug)
 		if err != nil {
 			opts.streams.Warning("Could not determine default branch, using 'main': %v", err)
 			opts.baseBranch = "main"
@@ -149,6 +216,35 @@ func runCreate(opts *createOptions) error {
 		opts.title = title
 	}
 
+	// Interactive mode: open editor for body if not provided, letting the
+	// user refine the title at the same time
+	if opts.body == "" && opts.streams.CanPrompt() && !opts.fill {
+		body, err := descriptionTemplate(repoConfig)
+		if err != nil {
+			opts.streams.Warning("%s", err)
+		}
+		if body == "" {
+			body = getBodyTemplate(opts)
+		}
+		content, err := cmdutil.OpenEditor(cmdutil.EditorTemplate(opts.title, body, editorHint))
+		switch {
+		case errors.Is(err, cmdutil.ErrEditorAborted):
+			return fmt.Errorf("pull request creation cancelled")
+		case err != nil:
+			opts.streams.Warning("Could not open editor: %v", err)
+		default:
+			if content.Title() == "" {
+				return fmt.Errorf("title is required")
+			}
+			opts.title = content.Title()
+			opts.body = content.Body
+		}
+	}
+
+	if prefix := repoConfig.PR.TitlePrefix; prefix != "" && !strings.HasPrefix(opts.title, prefix) {
+		opts.title = prefix + " " + opts.title
+	}
+
 	// Handle draft
 	if opts.draft {
 		if !strings.HasPrefix(opts.title, "[DRAFT]") && !strings.HasPrefix(opts.title, "[WIP]") {
@@ -156,20 +252,17 @@ func runCreate(opts *createOptions) error {
 		}
 	}
 
-	// Interactive mode: open editor for body if not provided and stdin is TTY
-	if opts.body == "" && opts.streams.IsStdinTTY() && !opts.fill {
-		body, err := openEditor(getBodyTemplate(opts))
-		if err != nil {
-			opts.streams.Warning("Could not open editor: %v", err)
-		} else {
-			opts.body = cleanupBody(body)
+	// Push the current branch, unless --head or --repo point elsewhere
+	if localBranch != "" && opts.repo == "" && !opts.noPush {
+		if err := pushBranch(opts.streams, localBranch, opts.headBranch, opts.push); err != nil {
+			return err
 		}
 	}
 
 	// Display what we're about to do
 	opts.streams.Info("Creating pull request for %s into %s\n", opts.headBranch, opts.baseBranch)
 
-	// Resolve reviewer UUIDs
+	// Resolve reviewer UUIDs, including the repository's default reviewers
 	var reviewerUUIDs []string
 	if len(opts.reviewers) > 0 {
 		reviewerUUIDs, err = resolveReviewers(ctx, client, workspace, opts.reviewers)
@@ -177,6 +270,10 @@ func runCreate(opts *createOptions) error {
 			opts.streams.Warning("Could not resolve some reviewers: %v", err)
 		}
 	}
+	if len(repoConfig.Reviewers) > 0 {
+		defaults, _ := resolveReviewers(ctx, client, workspace, repoConfig.Reviewers)
+		reviewerUUIDs = mergeReviewers(ctx, client, reviewerUUIDs, defaults)
+	}
 
 	// Create the PR
 	createOpts := &api.PRCreateOptions{
@@ -184,7 +281,7 @@ func runCreate(opts *createOptions) error {
 		Description:       opts.body,
 		SourceBranch:      opts.headBranch,
 		DestinationBranch: opts.baseBranch,
-		CloseSourceBranch: false,
+		CloseSourceBranch: repoConfig.PR.CloseSourceBranch,
 		Reviewers:         reviewerUUIDs,
 	}
 
@@ -192,11 +289,24 @@ func runCreate(opts *createOptions) error {
 	if err != nil {
 		return fmt.Errorf("failed to create pull request: %w", err)
 	}
+	if localBranch != "" && opts.repo == "" {
+		if err := recordBranchPR(localBranch, pr.ID); err != nil {
+			opts.streams.Warning("%v", err)
+		}
+	}
 
 	// Print success message
-	fmt.Fprintln(opts.streams.Out)
+	if !opts.streams.IsQuiet() {
+		fmt.Fprintln(opts.streams.Out)
+	}
 	fmt.Fprintln(opts.streams.Out, pr.Links.HTML.Href)
 
+	if opts.copy {
+		if err := cmdutil.CopyURL(opts.streams, pr.Links.HTML.Href); err != nil {
+			opts.streams.Warning("%s", err)
+		}
+	}
+
 	// Open in browser if requested
 	if opts.web {
 		if err := browser.Open(pr.Links.HTML.Href); err != nil {
@@ -207,34 +317,6 @@ func runCreate(opts *createOptions) error {
 	return nil
 }
 
-// getDefaultBranch fetches the repository's default branch
-func getDefaultBranch(ctx context.Context, client *api.Client, workspace, repoSlug string) (string, error) {
-	path := fmt.Sprintf("/repositories/%s/%s", workspace, repoSlug)
-	resp, err := client.Get(ctx, path, nil)
-	if err != nil {
-		return "", err
-	}
-
-	var repo struct {
-		MainBranch struct {
-			Name string `json:"name"`
-		} `json:"mainbranch"`
-	}
-	if err := parseResponseBody(resp.Body, &repo); err != nil {
-		return "", err
-	}
-
-	if repo.MainBranch.Name == "" {
-		return "main", nil
-	}
-	return repo.MainBranch.Name, nil
-}
-
-// parseResponseBody parses JSON response body
-func parseResponseBody(body []byte, v interface{}) error {
-	return json.Unmarshal(body, v)
-}
-
 // findExistingPR checks if there's already an open PR for the given branch
 func findExistingPR(ctx context.Context, client *api.Client, workspace, repoSlug, branch string) (*api.PullRequest, error) {
 	opts := &api.PRListOptions{
@@ -285,49 +367,33 @@ func fillFromCommits(opts *createOptions) {
 
 // getCommitMessages returns commit messages between base and head
 func getCommitMessages(base, head string) ([]string, error) {
-	// Try to get commits
-	cmd := exec.Command("git", "log", "--format=%s", fmt.Sprintf("origin/%s..%s", base, head))
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
+	commits, err := git.CommitSubjects(fmt.Sprintf("origin/%s..%s", base, head))
+	if err != nil {
 		// Fallback: try without origin/ prefix
-		cmd = exec.Command("git", "log", "--format=%s", fmt.Sprintf("%s..%s", base, head))
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
-		if err := cmd.Run(); err != nil {
-			return nil, err
-		}
-	}
-
-	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-	var commits []string
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			commits = append(commits, line)
-		}
+		return git.CommitSubjects(fmt.Sprintf("%s..%s", base, head))
 	}
-
 	return commits, nil
 }
 
 // promptForTitle prompts the user to enter a title
 func promptForTitle(streams *iostreams.IOStreams) (string, error) {
-	if !streams.IsStdinTTY() {
+	title, err := streams.PromptText("Title", "")
+	if errors.Is(err, iostreams.ErrNoPrompt) {
 		return "", fmt.Errorf("--title flag is required when not running interactively")
 	}
+	return title, err
+}
 
-	fmt.Fprint(streams.Out, "Title: ")
+// editorHint is shown at the bottom of the editor template
+const editorHint = "HTML comments are ignored. Save an empty title and body to cancel."
 
-	reader := bufio.NewReader(os.Stdin)
-	title, err := reader.ReadString('\n')
+// descriptionTemplate returns the configured pull request template, if any
+func descriptionTemplate(repoConfig *config.RepoConfig) (string, error) {
+	cfg, err := config.LoadConfig()
 	if err != nil {
 		return "", err
 	}
-
-	return strings.TrimSpace(title), nil
+	return config.NewResolver(cfg, repoConfig).PRTemplate()
 }
 
 // getBodyTemplate returns a template for the PR body
@@ -346,20 +412,6 @@ Branch: %s → %s
 `, opts.headBranch, opts.baseBranch)
 }
 
-// cleanupBody removes comment lines and trims whitespace
-func cleanupBody(body string) string {
-	lines := strings.Split(body, "\n")
-	var result []string
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if strings.HasPrefix(trimmed, "<!--") || strings.HasPrefix(trimmed, "-->") {
-			continue
-		}
-		result = append(result, line)
-	}
-	return strings.TrimSpace(strings.Join(result, "\n"))
-}
-
 // resolveReviewers resolves usernames to UUIDs
 func resolveReviewers(ctx context.Context, client *api.Client, workspace string, usernames []string) ([]string, error) {
 	var uuids []string
@@ -376,6 +428,25 @@ func resolveReviewers(ctx context.Context, client *api.Client, workspace string,
 	return uuids, nil
 }
 
+// mergeReviewers adds the default reviewers to reviewers, skipping
+// duplicates and the current user, who cannot review their own pull request
+func mergeReviewers(ctx context.Context, client *api.Client, reviewers, defaults []string) []string {
+	skip := make(map[string]bool)
+	if me, err := client.GetCurrentUser(ctx); err == nil {
+		skip[me.UUID] = true
+	}
+	for _, uuid := range reviewers {
+		skip[uuid] = true
+	}
+	for _, uuid := range defaults {
+		if !skip[uuid] {
+			reviewers = append(reviewers, uuid)
+			skip[uuid] = true
+		}
+	}
+	return reviewers
+}
+
 // getUserUUID looks up a user's UUID by username
 func getUserUUID(ctx context.Context, client *api.Client, workspace, username string) (string, error) {
 	// First try as workspace member
@@ -421,3 +492,95 @@ func getUserUUID(ctx context.Context, client *api.Client, workspace, username st
 func parseJSONResponse(body []byte, v interface{}) error {
 	return json.Unmarshal(body, v)
 }
+
+// pushBranch pushes localBranch to remoteBranch, setting it as the upstream,
+// when the remote branch is missing or behind. It asks first unless force is
+// set, and doesn't push if it can't ask.
+func pushBranch(streams *iostreams.IOStreams, localBranch, remoteBranch string, force bool) error {
+	remote := pushRemote(localBranch)
+
+	localCommit, err := git.RevParse(localBranch)
+	if err != nil {
+		return err
+	}
+	remoteCommit, err := git.RemoteBranchCommit(remote, remoteBranch)
+	if err != nil {
+		streams.Warning("Could not check whether %s is pushed: %v", localBranch, err)
+		return nil
+	}
+
+	var question, pushed string
+	switch {
+	case remoteCommit == localCommit:
+		return nil
+	case remoteCommit == "":
+		question = fmt.Sprintf("Branch %s is not on %s. Push it?", localBranch, remote)
+		pushed = fmt.Sprintf("Pushed %s to %s/%s", localBranch, remote, remoteBranch)
+	case git.IsAncestor(remoteCommit, localCommit):
+		count, err := git.CountCommits(remoteCommit + ".." + localCommit)
+		if err != nil {
+			return err
+		}
+		question = fmt.Sprintf("Branch %s has %s not on %s. Push?", localBranch, pluralCommits(count), remote)
+		pushed = fmt.Sprintf("Pushed %s from %s to %s/%s", pluralCommits(count), localBranch, remote, remoteBranch)
+	default:
+		// The remote branch has commits the local one doesn't, so a push
+		// would be rejected; the pull request uses the remote branch as is
+		streams.Warning("%s/%s has diverged from %s; not pushing", remote, remoteBranch, localBranch)
+		return nil
+	}
+
+	push := force
+	if !force {
+		var err error
+		push, err = streams.PromptConfirm(question, true)
+		if errors.Is(err, iostreams.ErrNoPrompt) {
+			if remoteCommit == "" {
+				return fmt.Errorf("branch %s is not pushed to %s; use --push or push it first", localBranch, remote)
+			}
+			streams.Warning("%s has commits not on %s; not pushing (use --push to push them)", localBranch, remote)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if !push {
+		if remoteCommit == "" {
+			return fmt.Errorf("branch %s must be pushed to %s before creating a pull request", localBranch, remote)
+		}
+		return nil
+	}
+
+	refspec := localBranch
+	if localBranch != remoteBranch {
+		refspec = localBranch + ":" + remoteBranch
+	}
+	// git may ask for credentials on the terminal, so no spinner runs
+	// over it
+	streams.Info("Pushing %s to %s...", localBranch, remote)
+	if err := git.Push(remote, refspec, true); err != nil {
+		return err
+	}
+	streams.Success("%s", pushed)
+	return nil
+}
+
+// pushRemote returns the remote a branch is pushed to: the one it tracks,
+// or else the repository's default remote
+func pushRemote(branch string) string {
+	if upstream, err := git.UpstreamFor(branch); err == nil && upstream.Remote != "." {
+		return upstream.Remote
+	}
+	if remote, err := git.GetDefaultRemote(); err == nil {
+		return remote.Name
+	}
+	return "origin"
+}
+
+func pluralCommits(count int) string {
+	if count == 1 {
+		return "1 commit"
+	}
+	return fmt.Sprintf("%d commits", count)
+}
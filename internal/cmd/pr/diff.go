@@ -1,12 +1,12 @@
 package pr
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -42,7 +42,8 @@ by default when stdout is a terminal, and disabled when piped.`,
 
   # Pipe diff to a file
   bb pr diff 123 > changes.diff`,
-		Args: cobra.MaximumNArgs(1),
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: cmdutil.CompleteOpenPRs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runDiff(opts, args)
 		},
@@ -81,7 +82,11 @@ func runDiff(opts *diffOptions, args []string) error {
 	// Fetch the diff using the diff link
 	diffURL := pr.Links.Diff.Href
 	if diffURL == "" {
-		diffURL = fmt.Sprintf("https://api.bitbucket.org/2.0/repositories/%s/%s/pullrequests/%d/diff", workspace, repoSlug, prNum)
+		baseURL, err := cmdutil.APIBaseURL()
+		if err != nil {
+			return err
+		}
+		diffURL = fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/diff", baseURL, workspace, repoSlug, prNum)
 	}
 
 	// Make HTTP request for diff (using raw HTTP since it returns text/plain)
@@ -98,7 +103,11 @@ func runDiff(opts *diffOptions, args []string) error {
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Accept", "text/plain")
 
-	resp, err := http.DefaultClient.Do(req)
+	httpClient, err := cmdutil.NewHTTPClient(0)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to fetch diff: %w", err)
 	}
@@ -108,53 +117,34 @@ func runDiff(opts *diffOptions, args []string) error {
 		return fmt.Errorf("failed to fetch diff: %s", resp.Status)
 	}
 
-	// Read diff content
-	diffContent, err := io.ReadAll(resp.Body)
-	if err != nil {
+	// Wait for the diff to start arriving, then stream it a line at a time
+	// so large diffs needn't fit in memory
+	progress := opts.streams.StartProgress("Downloading diff")
+	body := bufio.NewReader(resp.Body)
+	_, err = body.Peek(1)
+	progress.Stop()
+	if err != nil && err != io.EOF {
 		return fmt.Errorf("failed to read diff: %w", err)
 	}
 
-	// Determine if we should colorize
-	useColor := opts.streams.IsStdoutTTY() && !opts.noColor
-
-	if useColor {
-		colorizedDiff := colorizeDiff(string(diffContent))
-		fmt.Fprint(opts.streams.Out, colorizedDiff)
-	} else {
-		fmt.Fprint(opts.streams.Out, string(diffContent))
+	if err := opts.streams.StartPager(); err != nil {
+		opts.streams.Warning("%s", err)
 	}
+	defer opts.streams.StopPager()
 
-	return nil
-}
-
-// colorizeDiff adds ANSI colors to a diff
-func colorizeDiff(diff string) string {
-	var result strings.Builder
-	lines := strings.Split(diff, "\n")
-
-	for _, line := range lines {
-		switch {
-		case strings.HasPrefix(line, "+++ ") || strings.HasPrefix(line, "--- "):
-			// File headers - bold
-			result.WriteString(iostreams.Bold + line + iostreams.Reset + "\n")
-		case strings.HasPrefix(line, "+"):
-			// Additions - green
-			result.WriteString(iostreams.Green + line + iostreams.Reset + "\n")
-		case strings.HasPrefix(line, "-"):
-			// Deletions - red
-			result.WriteString(iostreams.Red + line + iostreams.Reset + "\n")
-		case strings.HasPrefix(line, "@@"):
-			// Hunk headers - cyan
-			result.WriteString(iostreams.Cyan + line + iostreams.Reset + "\n")
-		case strings.HasPrefix(line, "diff "):
-			// Diff headers - bold blue
-			result.WriteString(iostreams.BoldBlue + line + iostreams.Reset + "\n")
-		default:
-			result.WriteString(line + "\n")
+	// Determine if we should colorize
+	var style func(string) string
+	if opts.streams.ColorEnabled() && !opts.noColor {
+		style = func(line string) string {
+			return cmdutil.ColorizeDiffLine(opts.streams, line)
 		}
 	}
 
-	return result.String()
+	if _, err := cmdutil.CopyLines(opts.streams.Out, body, style); err != nil {
+		return fmt.Errorf("failed to read diff: %w", err)
+	}
+
+	return nil
 }
 
 // getTokenForRequest gets the access token for making requests
@@ -164,12 +154,15 @@ func getTokenForRequest() (string, error) {
 		return "", err
 	}
 
-	user := hosts.GetActiveUser(config.DefaultHost)
+	host, user, err := config.ActiveAccount(hosts)
+	if err != nil {
+		return "", err
+	}
 	if user == "" {
 		return "", fmt.Errorf("not logged in")
 	}
 
-	tokenData, _, err := config.GetTokenFromEnvOrKeyring(config.DefaultHost, user)
+	tokenData, _, err := config.GetTokenFromEnvOrKeyring(host, user)
 	if err != nil {
 		return "", err
 	}
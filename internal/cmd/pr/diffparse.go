@@ -0,0 +1,124 @@
+package pr
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// diffLineKind is what a line of a parsed diff is
+type diffLineKind int
+
+const (
+	diffHunk diffLineKind = iota
+	diffContext
+	diffAdded
+	diffRemoved
+)
+
+// diffLine is a line of a file's diff. Text is the line without its +, -
+// or space prefix, or the whole header for hunk headers. Old and New are
+// its line numbers in the old and new versions of the file, 0 where it
+// has none.
+type diffLine struct {
+	Kind diffLineKind
+	Text string
+	Old  int
+	New  int
+}
+
+// diffFile is the diff of one file. Path is the file's new path, or its
+// old one if the file was deleted.
+type diffFile struct {
+	Path    string
+	OldPath string
+	Lines   []diffLine
+}
+
+// findLine returns the index in f.Lines of the line an inline comment
+// anchored at from and to is on: the new line to, or the old line from if
+// to is 0. It returns -1 if the diff doesn't show that line.
+func (f *diffFile) findLine(from, to int) int {
+	for i, l := range f.Lines {
+		switch {
+		case l.Kind == diffHunk:
+		case to != 0 && l.New == to:
+			return i
+		case to == 0 && from != 0 && l.Old == from:
+			return i
+		}
+	}
+	return -1
+}
+
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// parseDiff splits a unified diff, as Bitbucket returns for a pull request,
+// into files. Binary files and changes to file modes have no lines.
+func parseDiff(diff string) []diffFile {
+	var files []diffFile
+	var file *diffFile
+	var oldLine, newLine, oldLeft, newLeft int
+
+	for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		inHunk := file != nil && (oldLeft > 0 || newLeft > 0)
+		switch {
+		case inHunk && strings.HasPrefix(line, "+"):
+			file.Lines = append(file.Lines, diffLine{Kind: diffAdded, Text: line[1:], New: newLine})
+			newLine++
+			newLeft--
+		case inHunk && strings.HasPrefix(line, "-"):
+			file.Lines = append(file.Lines, diffLine{Kind: diffRemoved, Text: line[1:], Old: oldLine})
+			oldLine++
+			oldLeft--
+		case inHunk && (strings.HasPrefix(line, " ") || line == ""):
+			file.Lines = append(file.Lines, diffLine{Kind: diffContext, Text: strings.TrimPrefix(line, " "), Old: oldLine, New: newLine})
+			oldLine++
+			newLine++
+			oldLeft--
+			newLeft--
+		case strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file"
+		case strings.HasPrefix(line, "diff --git "):
+			files = append(files, diffFile{})
+			file = &files[len(files)-1]
+			oldLeft, newLeft = 0, 0
+			if _, b, ok := strings.Cut(line, " b/"); ok {
+				file.Path = b
+				file.OldPath = b
+			}
+		case file == nil:
+			// Anything before the first file header
+		case strings.HasPrefix(line, "--- "):
+			if p := strings.TrimPrefix(line, "--- "); p != "/dev/null" {
+				file.OldPath = strings.TrimPrefix(p, "a/")
+			}
+		case strings.HasPrefix(line, "+++ "):
+			if p := strings.TrimPrefix(line, "+++ "); p != "/dev/null" {
+				file.Path = strings.TrimPrefix(p, "b/")
+			} else {
+				file.Path = file.OldPath
+			}
+		case strings.HasPrefix(line, "@@"):
+			m := hunkHeaderPattern.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			oldLine, _ = strconv.Atoi(m[1])
+			newLine, _ = strconv.Atoi(m[3])
+			oldLeft, newLeft = hunkCount(m[2]), hunkCount(m[4])
+			file.Lines = append(file.Lines, diffLine{Kind: diffHunk, Text: line})
+		}
+	}
+	return files
+}
+
+// hunkCount parses the line count of a hunk header range, which is left
+// out when it is 1
+func hunkCount(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -50,7 +50,8 @@ At least one of --title, --body, or --base must be specified.`,
 
   # Output as JSON
   bb pr edit 123 --title "New title" --json`,
-		Args: cobra.ExactArgs(1),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: cmdutil.CompleteOpenPRs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			id, err := strconv.ParseInt(args[0], 10, 64)
 			if err != nil {
@@ -65,6 +66,7 @@ At least one of --title, --body, or --base must be specified.`,
 	cmd.Flags().StringVarP(&opts.title, "title", "t", "", "New title for the pull request")
 	cmd.Flags().StringVarP(&opts.body, "body", "b", "", "New description for the pull request")
 	cmd.Flags().StringVar(&opts.base, "base", "", "New destination branch")
+	_ = cmd.RegisterFlagCompletionFunc("base", cmdutil.CompleteBranches)
 	cmd.Flags().BoolVar(&opts.jsonOut, "json", false, "Output in JSON format")
 
 	return cmd
@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -15,12 +15,24 @@ import (
 
 // ListOptions holds the options for the list command
 type ListOptions struct {
-	State   string
-	Author  string
-	Limit   int
-	JSON    bool
-	Repo    string
-	Streams *iostreams.IOStreams
+	State           string
+	Author          string
+	Search          string
+	Filter          string
+	Mine            bool
+	ReviewRequested bool
+	Base            string
+	Limit           int
+	JSON            bool
+	Format          string
+	Repo            string
+	Fields          []string
+	Watch           time.Duration
+	Web             bool
+	Streams         *iostreams.IOStreams
+
+	// stateSet is true when --state was given, rather than defaulted
+	stateSet bool
 }
 
 // NewCmdList creates the pr list command
@@ -35,7 +47,14 @@ func NewCmdList(streams *iostreams.IOStreams) *cobra.Command {
 		Long: `List pull requests in a Bitbucket repository.
 
 By default, this shows open pull requests. Use the --state flag to filter
-by state (OPEN, MERGED, DECLINED).`,
+by state (OPEN, MERGED, DECLINED).
+
+Use --search to filter with a query in the Bitbucket query language (BBQL),
+such as 'title ~ "fix" AND reviewers.nickname = "alice"', or --filter to
+apply a query saved with 'bb filter save'. A search or saved filter lists
+pull requests in every state unless the query or --state narrows it. The
+--mine, --review-requested, --base and --author filters are combined with
+them using AND.`,
 		Example: `  # List open pull requests
   bb pr list
 
@@ -45,25 +64,77 @@ by state (OPEN, MERGED, DECLINED).`,
   # List pull requests by a specific author
   bb pr list --author johndoe
 
+  # List your own pull requests, and those waiting for your review
+  bb pr list --mine
+  bb pr list --review-requested
+
+  # List pull requests targeting the release branch
+  bb pr list --base release/2.0
+
+  # Search with a Bitbucket query
+  bb pr list --search 'state="OPEN" AND reviewers.nickname="alice"'
+
+  # Apply a saved filter
+  bb pr list --filter needs-review
+
   # List pull requests with limit
   bb pr list --limit 10
 
   # Output as JSON
   bb pr list --json
 
+  # Output as YAML
+  bb pr list --format yaml
+
   # List PRs for a specific repository
-  bb pr list --repo workspace/repo`,
+  bb pr list --repo workspace/repo
+
+  # Choose and order the table columns
+  bb pr list --fields id,title,author,updated
+
+  # Refresh the list every 30 seconds
+  bb pr list --watch=30s
+
+  # Open the pull requests page in the browser
+  bb pr list --web`,
 		Aliases: []string{"ls"},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.stateSet = cmd.Flags().Changed("state")
+			if opts.Web {
+				workspace, repoSlug, err := cmdutil.ParseRepository(opts.Repo)
+				if err != nil {
+					return err
+				}
+				url, err := cmdutil.RepoWebURL(cmd.Context(), workspace, repoSlug, "pull-requests")
+				if err != nil {
+					return err
+				}
+				return cmdutil.OpenInBrowser(opts.Streams, url)
+			}
+			if opts.Watch > 0 {
+				return cmdutil.Watch(cmd.Context(), opts.Streams, opts.Watch, func() error {
+					return runList(cmd.Context(), opts)
+				})
+			}
 			return runList(cmd.Context(), opts)
 		},
 	}
 
 	cmd.Flags().StringVarP(&opts.State, "state", "s", "OPEN", "Filter by state: OPEN, MERGED, DECLINED")
 	cmd.Flags().StringVarP(&opts.Author, "author", "a", "", "Filter by author username")
+	cmd.Flags().StringVarP(&opts.Search, "search", "S", "", "Filter with a Bitbucket query (BBQL)")
+	cmdutil.AddFilterFlag(cmd, &opts.Filter)
+	cmd.Flags().BoolVar(&opts.Mine, "mine", false, "Filter by pull requests you created")
+	cmd.Flags().BoolVar(&opts.ReviewRequested, "review-requested", false, "Filter by pull requests you are a reviewer of")
+	cmd.Flags().StringVarP(&opts.Base, "base", "B", "", "Filter by destination branch")
 	cmd.Flags().IntVarP(&opts.Limit, "limit", "l", 30, "Maximum number of pull requests to list")
 	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
+	cmdutil.AddFieldsFlag(cmd, &opts.Fields, listColumns(streams))
+	cmdutil.AddWatchFlag(cmd, &opts.Watch)
 	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+	cmdutil.AddWebFlag(cmd, &opts.Web, "pull requests page")
+	cmd.MarkFlagsMutuallyExclusive("mine", "author")
 
 	return cmd
 }
@@ -81,85 +152,158 @@ func runList(ctx context.Context, opts *ListOptions) error {
 		return err
 	}
 
-	// Validate state
-	state := strings.ToUpper(opts.State)
-	if state != "OPEN" && state != "MERGED" && state != "DECLINED" {
-		return fmt.Errorf("invalid state: %s (must be OPEN, MERGED, or DECLINED)", opts.State)
+	columns, err := cmdutil.SelectColumns("pr list", opts.Fields, listColumns(opts.Streams), defaultListFields)
+	if err != nil {
+		return err
 	}
 
-	// Build list options
-	listOpts := &api.PRListOptions{
-		State:  api.PRState(state),
-		Author: opts.Author,
-		Limit:  opts.Limit,
+	listOpts, err := buildListOptions(ctx, client, opts)
+	if err != nil {
+		return err
 	}
 
 	// Fetch pull requests
+	progress := opts.Streams.StartProgress("Fetching pull requests")
 	result, err := client.ListPullRequests(ctx, workspace, repoSlug, listOpts)
+	progress.Stop()
 	if err != nil {
 		return fmt.Errorf("failed to list pull requests: %w", err)
 	}
 
 	if len(result.Values) == 0 {
-		if opts.Author != "" {
-			opts.Streams.Info("No %s pull requests found by %s in %s/%s", strings.ToLower(state), opts.Author, workspace, repoSlug)
-		} else {
-			opts.Streams.Info("No %s pull requests found in %s/%s", strings.ToLower(state), workspace, repoSlug)
+		switch {
+		case listOpts.Query != "" || listOpts.Reviewer != "" || listOpts.DestinationBranch != "":
+			opts.Streams.Info("No pull requests match the filters in %s/%s", workspace, repoSlug)
+		case listOpts.Author != "":
+			opts.Streams.Info("No %s pull requests found by %s in %s/%s", strings.ToLower(string(listOpts.State)), listOpts.Author, workspace, repoSlug)
+		default:
+			opts.Streams.Info("No %s pull requests found in %s/%s", strings.ToLower(string(listOpts.State)), workspace, repoSlug)
 		}
 		return nil
 	}
 
+	if err := opts.Streams.StartPager(); err != nil {
+		opts.Streams.Warning("%s", err)
+	}
+	defer opts.Streams.StopPager()
+
 	// Output results
-	if opts.JSON {
-		return outputListJSON(opts.Streams, result.Values)
+	if opts.JSON || opts.Format != "" {
+		return outputListStructured(opts.Streams, opts.Format, result.Values)
+	}
+
+	return cmdutil.PrintColumns(opts.Streams, columns, result.Values)
+}
+
+// buildListOptions turns the filter flags into list options. --mine and
+// --review-requested look up the current user.
+func buildListOptions(ctx context.Context, client *api.Client, opts *ListOptions) (*api.PRListOptions, error) {
+	listOpts := &api.PRListOptions{
+		Author:            opts.Author,
+		DestinationBranch: opts.Base,
+		Limit:             opts.Limit,
+	}
+
+	query := new(api.Query)
+	if opts.Filter != "" {
+		filter, err := cmdutil.SavedFilter(ctx, client, opts.Filter)
+		if err != nil {
+			return nil, err
+		}
+		query.Raw(filter)
+	}
+	listOpts.Query = query.Raw(opts.Search).String()
+
+	// Validate state
+	state := strings.ToUpper(opts.State)
+	if state != "OPEN" && state != "MERGED" && state != "DECLINED" {
+		return nil, fmt.Errorf("invalid state: %s (must be OPEN, MERGED, or DECLINED)", opts.State)
+	}
+	if listOpts.Query != "" && !opts.stateSet {
+		// Bitbucket lists only open pull requests unless asked for others,
+		// so a search covers every state and the query decides
+		listOpts.States = []api.PRState{api.PRStateOpen, api.PRStateMerged, api.PRStateDeclined}
+	} else {
+		listOpts.State = api.PRState(state)
 	}
 
-	return outputTable(opts.Streams, result.Values)
+	if opts.Mine || opts.ReviewRequested {
+		user, err := client.GetCurrentUser(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current user: %w", err)
+		}
+		if opts.Mine {
+			listOpts.Author = user.Username
+		}
+		if opts.ReviewRequested {
+			listOpts.Reviewer = user.UUID
+		}
+	}
+	return listOpts, nil
 }
 
-func outputListJSON(streams *iostreams.IOStreams, prs []api.PullRequest) error {
-	// Create simplified JSON output
+func outputListStructured(streams *iostreams.IOStreams, format string, prs []api.PullRequest) error {
+	// Create simplified output
 	output := make([]api.PullRequestJSON, len(prs))
 	for i := range prs {
 		output[i] = api.PullRequestJSON{PullRequest: &prs[i]}
 	}
 
-	return cmdutil.PrintJSON(streams, output)
+	return cmdutil.PrintFormatted(streams, format, output)
 }
 
-func outputTable(streams *iostreams.IOStreams, prs []api.PullRequest) error {
-	w := tabwriter.NewWriter(streams.Out, 0, 0, 2, ' ', 0)
-
-	// Print header
-	header := "ID\tTITLE\tBRANCH\tAUTHOR\tSTATUS"
-	cmdutil.PrintTableHeader(streams, w, header)
+// defaultListFields are the columns shown when neither --fields nor the
+// fields.pr.list config key is set
+var defaultListFields = []string{"id", "title", "branch", "author", "status"}
 
-	// Print rows
-	for _, pr := range prs {
-		title := cmdutil.TruncateString(pr.Title, 50)
-		branch := cmdutil.TruncateString(pr.Source.Branch.Name, 30)
-		author := cmdutil.TruncateString(pr.Author.DisplayName, 20)
-		status := formatStatus(streams, string(pr.State))
-
-		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n",
-			pr.ID, title, branch, author, status)
+// listColumns returns the columns available to pr list --fields
+func listColumns(streams *iostreams.IOStreams) []cmdutil.Column[api.PullRequest] {
+	return []cmdutil.Column[api.PullRequest]{
+		{Name: "id", Header: "ID", Value: func(pr api.PullRequest) string {
+			return fmt.Sprintf("%d", pr.ID)
+		}},
+		{Name: "title", Header: "TITLE", Value: func(pr api.PullRequest) string {
+			return pr.Title
+		}},
+		{Name: "branch", Header: "BRANCH", Value: func(pr api.PullRequest) string {
+			return pr.Source.Branch.Name
+		}},
+		{Name: "base", Header: "BASE", Value: func(pr api.PullRequest) string {
+			return pr.Destination.Branch.Name
+		}},
+		{Name: "author", Header: "AUTHOR", Value: func(pr api.PullRequest) string {
+			return pr.Author.DisplayName
+		}},
+		{Name: "status", Header: "STATUS", Value: func(pr api.PullRequest) string {
+			return formatStatus(streams, string(pr.State))
+		}},
+		{Name: "reviewers", Header: "REVIEWERS", Value: func(pr api.PullRequest) string {
+			names := make([]string, len(pr.Reviewers))
+			for i, r := range pr.Reviewers {
+				names[i] = r.DisplayName
+			}
+			return strings.Join(names, ", ")
+		}},
+		{Name: "comments", Header: "COMMENTS", Value: func(pr api.PullRequest) string {
+			return fmt.Sprintf("%d", pr.CommentCount)
+		}},
+		{Name: "created", Header: "CREATED", Value: func(pr api.PullRequest) string {
+			return cmdutil.FormatTime(streams, pr.CreatedOn)
+		}},
+		{Name: "updated", Header: "UPDATED", Value: func(pr api.PullRequest) string {
+			return cmdutil.FormatTime(streams, pr.UpdatedOn)
+		}},
 	}
-
-	return w.Flush()
 }
 
 func formatStatus(streams *iostreams.IOStreams, state string) string {
-	if !streams.ColorEnabled() {
-		return state
-	}
-
 	switch state {
 	case "OPEN":
-		return iostreams.Green + state + iostreams.Reset
+		return streams.Style(iostreams.RoleSuccess, streams.Icon(iostreams.IconOpen, state))
 	case "MERGED":
-		return iostreams.Magenta + state + iostreams.Reset
+		return streams.Style(iostreams.RoleAccent, streams.Icon(iostreams.IconMerged, state))
 	case "DECLINED":
-		return iostreams.Red + state + iostreams.Reset
+		return streams.Style(iostreams.RoleError, streams.Icon(iostreams.IconDeclined, state))
 	default:
 		return state
 	}
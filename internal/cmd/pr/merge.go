@@ -12,7 +12,7 @@ import (
 
 	"github.com/rbansal42/bitbucket-cli/internal/api"
 	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
-	"github.com/rbansal42/bitbucket-cli/internal/git"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
 
@@ -24,14 +24,18 @@ type mergeOptions struct {
 	deleteBranch bool
 	message      string
 	autoMerge    bool
+	disableAuto  bool
+	wait         bool
+	pollInterval time.Duration
 	yes          bool // skip confirmation
 }
 
 // NewCmdMerge creates the merge command
 func NewCmdMerge(streams *iostreams.IOStreams) *cobra.Command {
 	opts := &mergeOptions{
-		streams:     streams,
-		mergeMethod: "merge", // default
+		streams:      streams,
+		mergeMethod:  "merge", // default
+		pollInterval: autoMergePollInterval,
 	}
 
 	cmd := &cobra.Command{
@@ -39,12 +43,25 @@ func NewCmdMerge(streams *iostreams.IOStreams) *cobra.Command {
 		Short: "Merge a pull request",
 		Long: `Merge a pull request via the Bitbucket API.
 
-If no pull request number is provided, the command will try to find a
-pull request associated with the current branch.
+If no pull request number is provided, the pull request of the current
+branch is merged, as for "bb pr view".
+
+Open pull requests stacked on the merged one, targeting its source branch,
+are moved onto the branch it was merged into.
 
 By default, the pull request is merged using a merge commit. Use --squash
 for squash merge or --rebase to attempt a rebase merge (note: Bitbucket
-may not support rebase merge for all repositories).`,
+may not support rebase merge for all repositories).
+
+With --auto, the pull request is merged once every check has passed, it
+has the approvals the destination branch's restrictions require, and no
+reviewer has requested changes. If it is ready it is merged right away;
+otherwise the request is recorded, with the merge options given, and the
+pull request is merged by the next "bb pr" command run once it is ready.
+Use --wait to keep checking until then instead. A failed check cancels the
+auto-merge, as does --disable-auto. Reading branch restrictions takes
+admin access to the repository; without it, only checks and requested
+changes are waited for.`,
 		Example: `  # Merge pull request #123
   bb pr merge 123
 
@@ -60,9 +77,16 @@ may not support rebase merge for all repositories).`,
   # Skip confirmation prompt
   bb pr merge 123 --yes
 
-  # Enable auto-merge when checks pass
-  bb pr merge 123 --auto`,
-		Args: cobra.MaximumNArgs(1),
+  # Merge when checks pass and approvals are in
+  bb pr merge 123 --auto --squash
+
+  # Wait for the pull request to become ready, then merge it
+  bb pr merge 123 --auto --wait
+
+  # Cancel a pending auto-merge
+  bb pr merge 123 --disable-auto`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: cmdutil.CompleteOpenPRs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Get repo from flag
 			if opts.repo == "" {
@@ -87,13 +111,19 @@ may not support rebase merge for all repositories).`,
 			}
 			// "merge" is default, no need to check
 
-			return runMerge(opts)
+			if opts.wait && !opts.autoMerge {
+				return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("--wait requires --auto"))
+			}
+
+			return runMerge(cmd.Context(), opts)
 		},
 	}
 
 	cmd.Flags().BoolVarP(&opts.deleteBranch, "delete-branch", "d", false, "Delete the source branch after merge")
 	cmd.Flags().StringVarP(&opts.message, "message", "m", "", "Custom merge commit message")
-	cmd.Flags().BoolVar(&opts.autoMerge, "auto", false, "Enable auto-merge when checks pass")
+	cmd.Flags().BoolVar(&opts.autoMerge, "auto", false, "Merge once checks pass and required approvals are in")
+	cmd.Flags().BoolVar(&opts.disableAuto, "disable-auto", false, "Cancel a pending auto-merge")
+	cmd.Flags().BoolVar(&opts.wait, "wait", false, "With --auto, wait until the pull request is ready and merge it")
 	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Skip confirmation prompt")
 	cmd.Flags().StringVarP(&opts.repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
 
@@ -101,11 +131,12 @@ may not support rebase merge for all repositories).`,
 	cmd.Flags().Bool("merge", false, "Use merge commit (default)")
 	cmd.Flags().Bool("squash", false, "Use squash merge")
 	cmd.Flags().Bool("rebase", false, "Use rebase merge (if supported)")
+	cmd.MarkFlagsMutuallyExclusive("auto", "disable-auto")
 
 	return cmd
 }
 
-func runMerge(opts *mergeOptions) error {
+func runMerge(ctx context.Context, opts *mergeOptions) error {
 	// Resolve repository
 	workspace, repoSlug, err := cmdutil.ParseRepository(opts.repo)
 	if err != nil {
@@ -118,23 +149,23 @@ func runMerge(opts *mergeOptions) error {
 		return err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	parentCtx := ctx
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	// If no PR number, try to find PR for current branch
 	if opts.prNumber == 0 {
-		currentBranch, err := git.GetCurrentBranch()
-		if err != nil {
-			return fmt.Errorf("could not determine current branch: %w. Please specify a pull request number", err)
-		}
-
-		prNumber, err := findPRForBranch(ctx, workspace, repoSlug, currentBranch)
+		prNumber, err := currentBranchPR(ctx, workspace, repoSlug)
 		if err != nil {
 			return err
 		}
 		opts.prNumber = prNumber
 	}
 
+	if opts.disableAuto {
+		return disableAutoMerge(opts, workspace, repoSlug)
+	}
+
 	// Get PR details
 	pr, err := client.GetPullRequest(ctx, workspace, repoSlug, int64(opts.prNumber))
 	if err != nil {
@@ -163,29 +194,61 @@ func runMerge(opts *mergeOptions) error {
 			opts.streams.Info("  Will delete source branch after merge")
 		}
 
-		if !confirm(opts.streams, "Merge this pull request?") {
+		prompt := "Merge this pull request?"
+		if opts.autoMerge {
+			prompt = "Merge this pull request when it is ready?"
+		}
+		if !confirm(opts.streams, prompt) {
 			return fmt.Errorf("merge cancelled")
 		}
 	}
 
 	// Handle auto-merge
 	if opts.autoMerge {
-		return enableAutoMerge(ctx, client, workspace, repoSlug, opts, mergeMethod)
+		return enableAutoMerge(parentCtx, client, workspace, repoSlug, opts, pr, mergeMethod)
 	}
 
+	return completeMerge(ctx, opts.streams, client, workspace, repoSlug, pr, mergeMethod, opts.message, opts.deleteBranch)
+}
+
+// completeMerge merges pr, moves the pull requests stacked on it onto its
+// destination, and deletes its source branch if asked to
+func completeMerge(ctx context.Context, streams *iostreams.IOStreams, client *api.Client, workspace, repoSlug string, pr *api.PullRequest, mergeMethod, message string, deleteBranch bool) error {
+	// Pull requests stacked on this one target its source branch, which
+	// must outlive the merge until they are moved off it
+	stacked, err := client.ListPullRequests(ctx, workspace, repoSlug, &api.PRListOptions{
+		State:             api.PRStateOpen,
+		DestinationBranch: pr.Source.Branch.Name,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to find stacked pull requests: %w", err)
+	}
+	closeSourceBranch := deleteBranch && len(stacked.Values) == 0
+
 	// Perform the merge
-	opts.streams.Info("Merging pull request #%d...", opts.prNumber)
+	streams.Info("Merging pull request #%d...", pr.ID)
 
-	err = mergePullRequest(ctx, client, workspace, repoSlug, opts.prNumber, mergeMethod, opts.message, opts.deleteBranch)
+	err = mergePullRequest(ctx, client, workspace, repoSlug, int(pr.ID), mergeMethod, message, closeSourceBranch)
 	if err != nil {
 		return fmt.Errorf("failed to merge pull request: %w", err)
 	}
 
-	opts.streams.Success("Pull request #%d merged", opts.prNumber)
+	streams.Success("Pull request #%d merged", pr.ID)
+
+	// Deleting the branch would close the pull requests still targeting it
+	if !retargetStacked(ctx, streams, client, workspace, repoSlug, pr, stacked.Values) && deleteBranch {
+		streams.Warning("Not deleting branch %s, which pull requests still target; delete it once they are moved", pr.Source.Branch.Name)
+		return nil
+	}
 
 	// Delete branch if requested (and not already handled by API)
-	if opts.deleteBranch {
-		opts.streams.Success("Deleted branch %s", pr.Source.Branch.Name)
+	if deleteBranch {
+		if !closeSourceBranch {
+			if err := client.DeleteBranch(ctx, workspace, repoSlug, pr.Source.Branch.Name); err != nil {
+				return fmt.Errorf("failed to delete branch %s: %w", pr.Source.Branch.Name, err)
+			}
+		}
+		streams.Success("Deleted branch %s", pr.Source.Branch.Name)
 	}
 
 	return nil
@@ -231,14 +294,81 @@ func mergePullRequest(ctx context.Context, client *api.Client, workspace, repoSl
 	return err
 }
 
-// enableAutoMerge enables auto-merge for a PR when checks pass
-func enableAutoMerge(ctx context.Context, client *api.Client, workspace, repoSlug string, opts *mergeOptions, mergeMethod string) error {
-	// Note: Bitbucket's auto-merge API may differ from this implementation
-	// This is a simplified version - the actual API endpoint may vary
+// enableAutoMerge records that pr should be merged once it is ready, and
+// merges it if it already is. With --wait, it keeps checking until the pull
+// request is merged or can't be.
+func enableAutoMerge(ctx context.Context, client *api.Client, workspace, repoSlug string, opts *mergeOptions, pr *api.PullRequest, mergeMethod string) error {
+	m := config.AutoMerge{
+		Host:         activeHost(),
+		Workspace:    workspace,
+		Repo:         repoSlug,
+		ID:           pr.ID,
+		Method:       mergeMethod,
+		Message:      opts.message,
+		DeleteBranch: opts.deleteBranch,
+		RequestedAt:  time.Now(),
+	}
+	if err := config.AddAutoMerge(m); err != nil {
+		return fmt.Errorf("failed to record auto-merge: %w", err)
+	}
 
-	opts.streams.Warning("Auto-merge is not directly supported via API. Consider enabling it in the Bitbucket web interface.")
-	opts.streams.Info("Alternatively, you can wait for checks to pass and then run 'bb pr merge %d' again.", opts.prNumber)
+	lastWaiting := ""
+	for {
+		attemptCtx, cancel := context.WithTimeout(ctx, autoMergeTimeout)
+		state, settled, err := attemptAutoMerge(attemptCtx, opts.streams, client, m)
+		cancel()
+		if settled {
+			if _, err := config.RemoveAutoMerge(m); err != nil {
+				opts.streams.Warning("could not update auto-merge requests: %v", err)
+			}
+		}
+		switch {
+		case err != nil:
+			return err
+		case state.Closed:
+			return fmt.Errorf("pull request #%d was closed before it could be merged", pr.ID)
+		case state.Failed != "":
+			return cmdutil.NewExitError(cmdutil.ExitChecksFailed,
+				fmt.Errorf("auto-merge of pull request #%d cancelled: %s", pr.ID, state.Failed))
+		case settled:
+			return nil
+		}
 
+		if state.Waiting != lastWaiting {
+			if lastWaiting == "" && !state.RequirementsKnown {
+				opts.streams.Warning("Could not read the branch restrictions of %s/%s, which takes admin access; only checks and requested changes are waited for", workspace, repoSlug)
+			}
+			opts.streams.Info("Pull request #%d will be merged when it is ready: %s", pr.ID, state.Waiting)
+			lastWaiting = state.Waiting
+		}
+		if !opts.wait {
+			opts.streams.Info("It is merged by the next bb pr command run once it is, or run 'bb pr merge %d --auto --wait' to wait for it.", pr.ID)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(opts.pollInterval):
+		}
+	}
+}
+
+// disableAutoMerge forgets the auto-merge request of the pull request
+func disableAutoMerge(opts *mergeOptions, workspace, repoSlug string) error {
+	removed, err := config.RemoveAutoMerge(config.AutoMerge{
+		Host:      activeHost(),
+		Workspace: workspace,
+		Repo:      repoSlug,
+		ID:        int64(opts.prNumber),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update auto-merge requests: %w", err)
+	}
+	if !removed {
+		return fmt.Errorf("pull request #%d has no pending auto-merge", opts.prNumber)
+	}
+	opts.streams.Success("Auto-merge of pull request #%d cancelled", opts.prNumber)
 	return nil
 }
 
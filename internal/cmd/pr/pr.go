@@ -26,6 +26,13 @@ your changes are merged.`,
   # List pull requests
   bb pr list`,
 		Aliases: []string{"pull-request"},
+		// Pull requests waiting for "bb pr merge --auto" are merged by the
+		// next pr command run once they are ready
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			if cmd.Name() != "merge" {
+				processAutoMerges(cmd.Context(), streams)
+			}
+		},
 	}
 
 	cmd.AddCommand(NewCmdList(streams))
@@ -36,10 +43,14 @@ your changes are merged.`,
 	cmd.AddCommand(NewCmdMerge(streams))
 	cmd.AddCommand(NewCmdClose(streams))
 	cmd.AddCommand(NewCmdReopen(streams))
+	cmd.AddCommand(NewCmdRevert(streams))
 	cmd.AddCommand(NewCmdReview(streams))
+	cmd.AddCommand(NewCmdApprove(streams))
 	cmd.AddCommand(NewCmdDiff(streams))
 	cmd.AddCommand(NewCmdComment(streams))
 	cmd.AddCommand(NewCmdChecks(streams))
+	cmd.AddCommand(NewCmdStatus(streams))
+	cmd.AddCommand(NewCmdStack(streams))
 
 	return cmd
 }
@@ -1,10 +1,24 @@
 package pr
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/rbansal42/bitbucket-cli/internal/api"
 	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
 
 func TestParsePRNumber(t *testing.T) {
@@ -187,12 +201,12 @@ func TestParseRepository(t *testing.T) {
 }
 
 func TestGetEditor(t *testing.T) {
-	// Test that getEditor returns a non-empty string
+	// Test that GetEditor returns a non-empty string
 	// The actual value depends on environment variables
-	editor := getEditor()
+	editor := cmdutil.GetEditor()
 
 	if editor == "" {
-		t.Error("getEditor() returned empty string")
+		t.Error("cmdutil.GetEditor() returned empty string")
 	}
 
 	// Should default to "vi" if no env vars are set
@@ -204,7 +218,7 @@ func TestGetEditorPriority(t *testing.T) {
 	// Note: In a real test, we'd use t.Setenv() which automatically restores
 	// For now, just test that the function returns a non-empty value
 
-	editor := getEditor()
+	editor := cmdutil.GetEditor()
 	if editor == "" {
 		t.Error("expected non-empty editor")
 	}
@@ -307,3 +321,956 @@ func TestParseRepositoryErrorMessages(t *testing.T) {
 		t.Error("expected non-empty error message")
 	}
 }
+
+func TestFetchStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch q := r.URL.Query().Get("q"); {
+		case r.URL.Path == "/user":
+			w.Write([]byte(`{"username": "alice", "uuid": "{alice}"}`))
+		case q == `author.username="alice"`:
+			w.Write([]byte(`{"values": [{"id": 1, "title": "Mine"}, {"id": 2, "title": "Also mine"}]}`))
+		case q == `reviewers.uuid="{alice}"`:
+			w.Write([]byte(`{"values": [{"id": 3, "title": "Theirs"}]}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewClient(api.WithBaseURL(server.URL))
+	status, err := fetchStatus(context.Background(), client, "workspace", "repo", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if status.CurrentBranch != "" || status.CurrentBranchPR != nil {
+		t.Errorf("expected no current branch outside a local clone, got %+v", status)
+	}
+	if len(status.CreatedByYou) != 2 || status.CreatedByYou[0].ID != 1 {
+		t.Errorf("unexpected created by you: %+v", status.CreatedByYou)
+	}
+	if len(status.ReviewRequested) != 1 || status.ReviewRequested[0].ID != 3 {
+		t.Errorf("unexpected review requested: %+v", status.ReviewRequested)
+	}
+}
+
+func TestBuildListOptions(t *testing.T) {
+	userRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userRequests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"username": "alice", "uuid": "{alice}"}`))
+	}))
+	defer server.Close()
+	client := api.NewClient(api.WithBaseURL(server.URL))
+
+	t.Run("search lists every state", func(t *testing.T) {
+		got, err := buildListOptions(context.Background(), client, &ListOptions{State: "OPEN", Search: `title ~ "fix"`, Base: "main"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.State != "" || len(got.States) != 3 {
+			t.Errorf("expected every state, got State=%q States=%v", got.State, got.States)
+		}
+		if got.Query != `title ~ "fix"` || got.DestinationBranch != "main" {
+			t.Errorf("unexpected filters: %+v", got)
+		}
+		if userRequests != 0 {
+			t.Errorf("expected no user lookup, got %d", userRequests)
+		}
+	})
+
+	t.Run("explicit state narrows a search", func(t *testing.T) {
+		got, err := buildListOptions(context.Background(), client, &ListOptions{State: "merged", Search: `title ~ "fix"`, stateSet: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.State != api.PRStateMerged || got.States != nil {
+			t.Errorf("expected MERGED, got State=%q States=%v", got.State, got.States)
+		}
+	})
+
+	t.Run("mine and review requested use the current user", func(t *testing.T) {
+		got, err := buildListOptions(context.Background(), client, &ListOptions{State: "OPEN", Mine: true, ReviewRequested: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Author != "alice" || got.Reviewer != "{alice}" || got.State != api.PRStateOpen {
+			t.Errorf("unexpected filters: %+v", got)
+		}
+	})
+
+	t.Run("saved filter is combined with a search", func(t *testing.T) {
+		t.Setenv("BB_CONFIG_DIR", t.TempDir())
+		cfg := &config.Config{}
+		cfg.SaveFilter("needs-review", `reviewers.uuid="me" OR author.username="me"`)
+		if err := config.SaveConfig(cfg); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := buildListOptions(context.Background(), client, &ListOptions{State: "OPEN", Filter: "needs-review", Search: `title ~ "fix"`})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := `(reviewers.uuid="{alice}" OR author.username="alice") AND (title ~ "fix")`
+		if got.Query != want || len(got.States) != 3 {
+			t.Errorf("unexpected options: %+v, want query %s", got, want)
+		}
+
+		if _, err := buildListOptions(context.Background(), client, &ListOptions{State: "OPEN", Filter: "missing"}); err == nil {
+			t.Error("expected an error for an unknown filter")
+		}
+	})
+
+	t.Run("invalid state", func(t *testing.T) {
+		if _, err := buildListOptions(context.Background(), client, &ListOptions{State: "closed"}); err == nil {
+			t.Error("expected an error for an invalid state")
+		}
+	})
+}
+
+func TestBuildStack(t *testing.T) {
+	pr := func(id int64, source, destination string) api.PullRequest {
+		var p api.PullRequest
+		p.ID = id
+		p.Source.Branch.Name = source
+		p.Destination.Branch.Name = destination
+		return p
+	}
+	fork := pr(5, "parser", "parser")
+	fork.Source.Repository = &api.Repository{FullName: "someone/repo"}
+
+	prs := []api.PullRequest{
+		fork,
+		pr(1, "parser", "main"),
+		pr(2, "use-parser", "parser"),
+		pr(3, "docs", "parser"),
+		pr(4, "unrelated", "main"),
+	}
+
+	stack, err := buildStack(prs, "workspace/repo", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stack.base != "main" {
+		t.Errorf("expected base main, got %q", stack.base)
+	}
+
+	var ids []int64
+	stack.walk(func(node *stackNode) {
+		ids = append(ids, node.pr.ID)
+	})
+	if len(ids) != 4 || ids[0] != 1 || ids[1] != 5 || ids[2] != 2 || ids[3] != 3 {
+		t.Errorf("unexpected stack order: %v", ids)
+	}
+
+	if _, err := buildStack(prs, "workspace/repo", 9); err == nil {
+		t.Error("expected an error for a pull request that is not open")
+	}
+}
+
+func TestFetchPRView(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repositories/workspace/repo/pullrequests/7":
+			w.Write([]byte(`{"id": 7, "title": "Add parser", "state": "OPEN",
+				"source": {"branch": {"name": "parser"}, "commit": {"hash": "abc123"}}, "destination": {"branch": {"name": "main"}}}`))
+		case "/repositories/workspace/repo/commit/abc123/reports":
+			w.Write([]byte(`{"values": [{"external_id": "coverage", "title": "Coverage", "result": "PASSED"}]}`))
+		case "/repositories/workspace/repo/merge-base/parser..main":
+			w.Write([]byte(`{"hash": "0123456789"}`))
+		case "/repositories/workspace/repo/commits":
+			if r.URL.Query().Get("include") == "parser" {
+				w.Write([]byte(`{"values": [{"hash": "a"}, {"hash": "b"}]}`))
+			} else {
+				w.Write([]byte(`{"values": []}`))
+			}
+		case "/repositories/workspace/repo/pullrequests/7/diffstat":
+			w.Write([]byte(`{"values": [{"status": "modified", "lines_added": 3, "lines_removed": 1}]}`))
+		case "/repositories/workspace/repo/pullrequests/7/statuses":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error": {"message": "Forbidden"}}`))
+		case "/repositories/workspace/repo/pullrequests/8":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"message": "Not found"}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewClient(api.WithBaseURL(server.URL))
+	view, err := fetchPRView(context.Background(), client, "workspace", "repo", 7, true, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if view.pr.Title != "Add parser" {
+		t.Errorf("unexpected pull request: %+v", view.pr)
+	}
+	if len(view.diffStat) != 1 || view.diffStat[0].LinesAdded != 3 {
+		t.Errorf("unexpected diffstat: %+v", view.diffStat)
+	}
+	if view.statuses != nil {
+		t.Errorf("expected statuses that failed to fetch to be left out, got %+v", view.statuses)
+	}
+	if d := view.divergence; d == nil || d.Ahead != 2 || d.Behind != 0 || d.MergeBase != "0123456789" {
+		t.Errorf("unexpected divergence: %+v", view.divergence)
+	}
+	if len(view.reports) != 1 || view.reports[0].ExternalID != "coverage" {
+		t.Errorf("unexpected reports: %+v", view.reports)
+	}
+
+	if _, err := fetchPRView(context.Background(), client, "workspace", "repo", 8, false, false); err == nil {
+		t.Error("expected an error when the pull request can't be fetched")
+	}
+}
+
+func TestOutputChecksCI(t *testing.T) {
+	statuses := []api.CommitStatus{
+		{Name: "build", State: "SUCCESSFUL"},
+		{Name: "unit tests", State: "FAILED", Description: "3 failed", URL: "https://ci.example.com/7"},
+		{Key: "lint", State: "STOPPED"},
+	}
+
+	tests := []struct {
+		system string
+		want   []string
+	}{
+		{cmdutil.CIGitHub, []string{
+			"::group::Checks of pull request #7\n",
+			"::endgroup::\n",
+			"::error title=unit tests failed::3 failed https://ci.example.com/7\n",
+			"::warning title=lint was stopped::\n",
+		}},
+		{cmdutil.CITeamCity, []string{
+			"##teamcity[blockOpened name='Checks of pull request #7']\n",
+			"##teamcity[blockClosed name='Checks of pull request #7']\n",
+			"##teamcity[buildProblem description='unit tests failed: 3 failed https://ci.example.com/7']\n",
+			"##teamcity[message text='lint was stopped' status='WARNING']\n",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.system, func(t *testing.T) {
+			var out bytes.Buffer
+			streams := &iostreams.IOStreams{Out: &out, ErrOut: &out}
+			ci, err := cmdutil.NewCI(tt.system, &out)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := outputChecksCI(streams, ci, 7, statuses); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output is missing %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateMerge(t *testing.T) {
+	approved := api.Participant{User: api.User{DisplayName: "Bob"}, Approved: true, State: "approved"}
+	changes := api.Participant{User: api.User{DisplayName: "Carol"}, State: "changes_requested"}
+	passed := api.CommitStatus{Name: "build", State: "SUCCESSFUL"}
+
+	tests := []struct {
+		name         string
+		participants []api.Participant
+		statuses     []api.CommitStatus
+		reqs         mergeRequirements
+		wantReady    bool
+		wantWaiting  string
+		wantFailed   string
+	}{
+		{"ready", []api.Participant{approved}, []api.CommitStatus{passed}, mergeRequirements{Approvals: 1}, true, "", ""},
+		{"no checks or requirements", nil, nil, mergeRequirements{}, true, "", ""},
+		{"running", nil, []api.CommitStatus{passed, {Name: "test", State: "INPROGRESS"}}, mergeRequirements{}, false, "1 of 2 checks still running", ""},
+		{"failed", nil, []api.CommitStatus{{Name: "test", State: "FAILED"}}, mergeRequirements{}, false, "", `check "test" failed`},
+		{"too few builds", nil, []api.CommitStatus{passed}, mergeRequirements{PassingBuilds: 2}, false, "1 of 2 required checks passed", ""},
+		{"too few approvals", []api.Participant{approved}, nil, mergeRequirements{Approvals: 2}, false, "1 of 2 required approvals", ""},
+		{"changes requested", []api.Participant{approved, changes}, nil, mergeRequirements{}, false, "changes requested by Carol", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &api.PullRequest{Participants: tt.participants}
+			got := evaluateMerge(pr, tt.statuses, tt.reqs)
+			if got.Ready != tt.wantReady || got.Waiting != tt.wantWaiting || got.Failed != tt.wantFailed {
+				t.Errorf("evaluateMerge() = %+v, want ready %v, waiting %q, failed %q", got, tt.wantReady, tt.wantWaiting, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestFetchMergeRequirements(t *testing.T) {
+	forbidden := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if forbidden {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"type": "error", "error": {"message": "Forbidden"}}`))
+			return
+		}
+		w.Write([]byte(`{"values": [
+			{"kind": "require_approvals_to_merge", "branch_match_kind": "glob", "pattern": "release/*", "value": 2},
+			{"kind": "require_approvals_to_merge", "branch_match_kind": "glob", "pattern": "main", "value": 1},
+			{"kind": "require_passing_builds_to_merge", "branch_match_kind": "glob", "pattern": "*", "value": 1},
+			{"kind": "require_approvals_to_merge", "branch_match_kind": "branching_model", "branch_type": "production", "value": 3}
+		]}`))
+	}))
+	defer server.Close()
+	client := api.NewClient(api.WithBaseURL(server.URL), api.WithToken("test-token"))
+
+	reqs, err := fetchMergeRequirements(context.Background(), client, "team", "api", "release/1.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (mergeRequirements{Approvals: 2, PassingBuilds: 1, Known: true}); reqs != want {
+		t.Errorf("fetchMergeRequirements() = %+v, want %+v", reqs, want)
+	}
+
+	forbidden = true
+	reqs, err = fetchMergeRequirements(context.Background(), client, "team", "api", "main")
+	if err != nil || reqs.Known {
+		t.Errorf("fetchMergeRequirements() without admin access = %+v, %v, want unknown requirements", reqs, err)
+	}
+}
+
+func TestEnableAutoMerge(t *testing.T) {
+	tests := []struct {
+		name       string
+		wait       bool
+		states     []string // the check's state on each poll
+		wantMerged bool
+		wantRecord bool
+		wantErr    string
+	}{
+		{"ready now", false, []string{"SUCCESSFUL"}, true, false, ""},
+		{"recorded for later", false, []string{"INPROGRESS"}, false, true, ""},
+		{"waits until ready", true, []string{"INPROGRESS", "INPROGRESS", "SUCCESSFUL"}, true, false, ""},
+		{"cancelled by a failed check", true, []string{"INPROGRESS", "FAILED"}, false, false, `check "build" failed`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BB_CONFIG_DIR", t.TempDir())
+			t.Setenv("BB_HOST", "")
+
+			polls, merged := 0, false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.URL.Path == "/repositories/team/api/pullrequests/5":
+					w.Write([]byte(`{"id": 5, "state": "OPEN", "source": {"branch": {"name": "feature"}}, "destination": {"branch": {"name": "main"}}}`))
+				case r.URL.Path == "/repositories/team/api/pullrequests/5/statuses":
+					state := tt.states[min(polls, len(tt.states)-1)]
+					polls++
+					w.Write([]byte(`{"values": [{"name": "build", "state": "` + state + `"}]}`))
+				case r.URL.Path == "/repositories/team/api/branch-restrictions":
+					w.WriteHeader(http.StatusForbidden)
+					w.Write([]byte(`{"type": "error", "error": {"message": "Forbidden"}}`))
+				case r.URL.Path == "/repositories/team/api/pullrequests":
+					w.Write([]byte(`{"values": []}`))
+				case r.URL.Path == "/repositories/team/api/pullrequests/5/merge" && r.Method == http.MethodPost:
+					merged = true
+					w.Write([]byte(`{"id": 5, "state": "MERGED"}`))
+				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+			client := api.NewClient(api.WithBaseURL(server.URL), api.WithToken("test-token"))
+
+			var out bytes.Buffer
+			opts := &mergeOptions{
+				streams:      &iostreams.IOStreams{Out: &out, ErrOut: &out},
+				prNumber:     5,
+				autoMerge:    true,
+				wait:         tt.wait,
+				pollInterval: time.Millisecond,
+			}
+			pr := &api.PullRequest{ID: 5}
+			err := enableAutoMerge(context.Background(), client, "team", "api", opts, pr, "squash")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("enableAutoMerge() error = %v, want %q", err, tt.wantErr)
+				}
+				if code := cmdutil.ExitCode(err); code != cmdutil.ExitChecksFailed {
+					t.Errorf("exit code = %d, want %d", code, cmdutil.ExitChecksFailed)
+				}
+			} else if err != nil {
+				t.Fatalf("enableAutoMerge() error = %v", err)
+			}
+
+			if merged != tt.wantMerged {
+				t.Errorf("merged = %v, want %v\n%s", merged, tt.wantMerged, out.String())
+			}
+			merges, err := config.LoadAutoMerges()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(merges) == 1 && merges[0].ID == 5 && merges[0].Method == "squash"; got != tt.wantRecord || (!tt.wantRecord && len(merges) != 0) {
+				t.Errorf("recorded auto-merges = %+v, want recorded %v", merges, tt.wantRecord)
+			}
+		})
+	}
+}
+
+const reviewTestDiff = `diff --git a/cmd/main.go b/cmd/main.go
+index 1111111..2222222 100644
+--- a/cmd/main.go
++++ b/cmd/main.go
+@@ -1,4 +1,4 @@
+ package main
+ 
+-func old() {}
++func new() {}
+ // end
+diff --git a/README.md b/README.md
+new file mode 100644
+--- /dev/null
++++ b/README.md
+@@ -0,0 +1,2 @@
++# Title
++-- not a header
+diff --git a/logo.png b/logo.png
+Binary files a/logo.png and b/logo.png differ
+`
+
+func TestParseDiff(t *testing.T) {
+	files := parseDiff(reviewTestDiff)
+	if len(files) != 3 {
+		t.Fatalf("expected 3 files, got %d", len(files))
+	}
+
+	main := files[0]
+	if main.Path != "cmd/main.go" || len(main.Lines) != 6 {
+		t.Fatalf("unexpected first file: %+v", main)
+	}
+	if got := main.Lines[4]; got.Kind != diffAdded || got.Text != "func new() {}" || got.New != 3 || got.Old != 0 {
+		t.Errorf("unexpected added line: %+v", got)
+	}
+	if got := main.Lines[3]; got.Kind != diffRemoved || got.Old != 3 {
+		t.Errorf("unexpected removed line: %+v", got)
+	}
+	if got := main.Lines[5]; got.Kind != diffContext || got.Old != 4 || got.New != 4 {
+		t.Errorf("unexpected context line: %+v", got)
+	}
+
+	readme := files[1]
+	if readme.Path != "README.md" || len(readme.Lines) != 3 || readme.Lines[2].Text != "-- not a header" {
+		t.Errorf("unexpected new file: %+v", readme)
+	}
+	if files[2].Path != "logo.png" || len(files[2].Lines) != 0 {
+		t.Errorf("unexpected binary file: %+v", files[2])
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	got := parseKeys([]byte("j\x1b[A\x1b[6~\x1b\r\tü\x7f\x03"))
+	want := []string{"j", keyUp, keyPageDown, keyEsc, keyEnter, keyTab, "ü", keyBackspace, keyCtrlC}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("parseKeys() = %q, want %q", got, want)
+	}
+}
+
+func TestReviewModel(t *testing.T) {
+	press := func(m *reviewModel, keys ...string) {
+		for _, k := range keys {
+			m.handleKey(k)
+		}
+	}
+	newModel := func() *reviewModel {
+		m := newReviewModel(&iostreams.IOStreams{}, "#1 Test", parseDiff(reviewTestDiff))
+		m.view(100, 20)
+		return m
+	}
+
+	t.Run("files are sorted into a tree", func(t *testing.T) {
+		m := newModel()
+		var labels []string
+		for _, r := range m.tree {
+			labels = append(labels, strings.Repeat("  ", r.depth)+r.label)
+		}
+		if got := strings.Join(labels, "|"); got != "README.md|cmd/|  main.go|logo.png" {
+			t.Errorf("unexpected tree: %s", got)
+		}
+		if m.cursor != 1 {
+			t.Errorf("expected the cursor on the first change, got line %d", m.cursor)
+		}
+	})
+
+	t.Run("compose comments and approve", func(t *testing.T) {
+		m := newModel()
+		press(m, "n", "j", "c", "h", "i", keyEnter)
+		press(m, "k", "c", "x", keyBackspace, "o", "k", keyEnter)
+		if len(m.pending) != 2 {
+			t.Fatalf("expected 2 pending comments, got %+v", m.pending)
+		}
+		if c := m.pending[0]; c.Path != "cmd/main.go" || c.New != 3 || c.Body != "hi" {
+			t.Errorf("unexpected comment: %+v", c)
+		}
+		if c := m.pending[1]; c.Old != 3 || c.New != 0 || c.Body != "ok" {
+			t.Errorf("unexpected comment on removed line: %+v", c)
+		}
+		screen := strings.Join(m.view(100, 20), "\n")
+		if !strings.Contains(screen, "2 pending comments") || !strings.Contains(screen, "✎  hi") {
+			t.Errorf("expected pending comments on screen:\n%s", screen)
+		}
+
+		press(m, "d")
+		if len(m.pending) != 1 {
+			t.Errorf("expected d to delete a comment, got %+v", m.pending)
+		}
+		press(m, "s", "a")
+		if !m.done || m.decision != reviewApprove {
+			t.Errorf("expected an approval, got done=%v decision=%v", m.done, m.decision)
+		}
+	})
+
+	t.Run("comment only needs comments", func(t *testing.T) {
+		m := newModel()
+		press(m, "s", "c")
+		if m.done || m.message == "" {
+			t.Errorf("expected a message and no submission, got done=%v", m.done)
+		}
+	})
+
+	t.Run("quitting asks before discarding comments", func(t *testing.T) {
+		m := newModel()
+		press(m, "c", "x", keyEnter, "q", "n")
+		if m.done {
+			t.Fatal("expected n to keep reviewing")
+		}
+		press(m, "q", "y")
+		if !m.done || m.decision != reviewAbandoned {
+			t.Errorf("expected the review abandoned, got done=%v decision=%v", m.done, m.decision)
+		}
+	})
+}
+
+func TestSubmitReview(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, strings.TrimSpace(r.URL.Path+" "+string(body)))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	streams := &iostreams.IOStreams{Out: &buf, ErrOut: &buf}
+	client := api.NewClient(api.WithBaseURL(server.URL))
+	comments := []pendingComment{
+		{Path: "main.go", New: 3, Body: "added"},
+		{Path: "main.go", Old: 2, Body: "removed"},
+	}
+	if err := submitReview(context.Background(), streams, client, "ws", "repo", 7, comments, reviewRequestChanges); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		`/repositories/ws/repo/pullrequests/7/comments {"content":{"raw":"added"},"inline":{"to":3,"path":"main.go"}}`,
+		`/repositories/ws/repo/pullrequests/7/comments {"content":{"raw":"removed"},"inline":{"from":2,"path":"main.go"}}`,
+		`/repositories/ws/repo/pullrequests/7/request-changes`,
+	}
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected requests:\n%s", strings.Join(requests, "\n"))
+	}
+	if !strings.Contains(buf.String(), "Added 2 review comments") || !strings.Contains(buf.String(), "Requested changes") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestCommentThreads(t *testing.T) {
+	at := func(day int) time.Time { return time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC) }
+	comment := func(id, parent int64, day int, body string) api.PRComment {
+		c := api.PRComment{ID: id, CreatedOn: at(day)}
+		c.Content.Raw = body
+		c.User.DisplayName = fmt.Sprintf("user%d", id)
+		if parent != 0 {
+			c.Parent = &struct {
+				ID int64 `json:"id"`
+			}{ID: parent}
+		}
+		return c
+	}
+	inline := func(c api.PRComment, path string, from, to int) api.PRComment {
+		c.Inline = &struct {
+			From int    `json:"from,omitempty"`
+			To   int    `json:"to,omitempty"`
+			Path string `json:"path"`
+		}{From: from, To: to, Path: path}
+		return c
+	}
+
+	deletedWithReply := comment(5, 0, 5, "")
+	deletedWithReply.Deleted = true
+	deletedAlone := comment(7, 0, 7, "")
+	deletedAlone.Deleted = true
+	resolved := inline(comment(3, 0, 3, "Rename this"), "cmd/main.go", 0, 3)
+	resolved.Resolution = &api.CommentResolution{User: api.User{DisplayName: "Carol"}, CreatedOn: at(9)}
+
+	comments := []api.PRComment{
+		comment(2, 1, 2, "Agreed"),
+		comment(1, 0, 1, "Looks good"),
+		resolved,
+		comment(4, 3, 4, "Done"),
+		deletedWithReply,
+		comment(6, 5, 6, "Still relevant"),
+		deletedAlone,
+		comment(8, 99, 8, "Orphan"),
+		inline(comment(9, 0, 9, "Why remove?"), "cmd/main.go", 3, 0),
+	}
+
+	threads := buildThreads(comments)
+	var ids []int64
+	for _, th := range threads {
+		ids = append(ids, th.comment.ID)
+	}
+	if fmt.Sprint(ids) != "[1 3 5 8 9]" {
+		t.Fatalf("unexpected threads: %v", ids)
+	}
+	if len(threads[0].replies) != 1 || threads[0].replies[0].comment.ID != 2 {
+		t.Errorf("expected comment 2 to reply to 1, got %+v", threads[0].replies)
+	}
+
+	var buf bytes.Buffer
+	streams := &iostreams.IOStreams{Out: &buf, ErrOut: &buf}
+	streams.SetTimestampStyle(iostreams.TimestampsISO)
+	displayComments(streams, threads, parseDiff(reviewTestDiff), true)
+	out := buf.String()
+
+	for _, want := range []string{
+		"Conversation\n\n  user1 commented 2024-01-01T00:00:00Z #1\n    Looks good",
+		"    ↳ user2 replied 2024-01-02T00:00:00Z\n",
+		"(comment deleted)",
+		"      Still relevant",
+		"cmd/main.go\n\n  Line 3 · ",
+		"  Line 3 (old)\n",
+		"  >   3      -func old() {}",
+		"  Line 3 · ✓ Resolved by Carol",
+		"  >        3 +func new() {}",
+		"      Done",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "user7") {
+		t.Errorf("expected a deleted comment without replies to be left out:\n%s", out)
+	}
+}
+
+func TestSetCommentResolution(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repositories/ws/repo/pullrequests/7/comments/1":
+			w.Write([]byte(`{"id": 1}`))
+		case "/repositories/ws/repo/pullrequests/7/comments/2":
+			w.Write([]byte(`{"id": 2, "resolution": {"user": {"display_name": "Carol"}}}`))
+		case "/repositories/ws/repo/pullrequests/7/comments/3":
+			w.Write([]byte(`{"id": 3, "parent": {"id": 1}}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+	client := api.NewClient(api.WithBaseURL(server.URL))
+
+	tests := []struct {
+		name        string
+		commentID   int64
+		resolve     bool
+		wantChanged bool
+		wantErr     bool
+		wantRequest string
+	}{
+		{name: "resolve open thread", commentID: 1, resolve: true, wantChanged: true, wantRequest: "POST /repositories/ws/repo/pullrequests/7/comments/1/resolve"},
+		{name: "resolve resolved thread", commentID: 2, resolve: true},
+		{name: "reopen resolved thread", commentID: 2, wantChanged: true, wantRequest: "DELETE /repositories/ws/repo/pullrequests/7/comments/2/resolve"},
+		{name: "reopen open thread", commentID: 1},
+		{name: "resolve reply", commentID: 3, resolve: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = nil
+			changed, err := setCommentResolution(context.Background(), client, "ws", "repo", 7, tt.commentID, tt.resolve)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("setCommentResolution() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("setCommentResolution() = %v, want %v", changed, tt.wantChanged)
+			}
+			last := requests[len(requests)-1]
+			if tt.wantRequest != "" && last != tt.wantRequest {
+				t.Errorf("expected request %q, got %q", tt.wantRequest, last)
+			}
+			if tt.wantRequest == "" && len(requests) != 1 {
+				t.Errorf("expected only the comment to be fetched, got %v", requests)
+			}
+		})
+	}
+}
+
+func TestApproveQuery(t *testing.T) {
+	if got := approveQuery(`Bump "lodash"`, ""); got != `title ~ "Bump \"lodash\""` {
+		t.Errorf("approveQuery() = %s", got)
+	}
+	if got := approveQuery("Bump", "renovate-bot"); got != `(title ~ "Bump") AND author.nickname="renovate-bot"` {
+		t.Errorf("approveQuery() with author = %s", got)
+	}
+}
+
+func TestSearchAndApprovePRs(t *testing.T) {
+	var mu sync.Mutex
+	var actions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/repositories/ws":
+			w.Write([]byte(`{"values": [{"slug": "web"}, {"slug": "api"}, {"slug": "broken"}]}`))
+		case r.URL.Path == "/repositories/ws/api/pullrequests" && r.Method == http.MethodGet:
+			if got := r.URL.Query().Get("q"); got != `title ~ "Bump"` {
+				t.Errorf("unexpected query %q", got)
+			}
+			w.Write([]byte(`{"values": [{"id": 9, "title": "Bump b", "author": {"uuid": "{bot}"}}, {"id": 3, "title": "Bump a", "author": {"uuid": "{me}"}}]}`))
+		case r.URL.Path == "/repositories/ws/web/pullrequests" && r.Method == http.MethodGet:
+			w.Write([]byte(`{"values": [{"id": 5, "title": "Bump c", "author": {"uuid": "{bot}"}}]}`))
+		case r.URL.Path == "/repositories/ws/broken/pullrequests":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error": {"message": "forbidden"}}`))
+		default:
+			mu.Lock()
+			actions = append(actions, r.Method+" "+r.URL.Path)
+			mu.Unlock()
+			if r.URL.Path == "/repositories/ws/web/pullrequests/5/merge" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error": {"message": "checks failed"}}`))
+				return
+			}
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+	client := api.NewClient(api.WithBaseURL(server.URL))
+
+	prs, unread, err := searchWorkspacePRs(context.Background(), client, "ws", approveQuery("Bump", ""))
+	if err != nil {
+		t.Fatalf("searchWorkspacePRs() error = %v", err)
+	}
+	var found []string
+	for _, pr := range prs {
+		found = append(found, fmt.Sprintf("%s#%d", pr.Destination.Repository.FullName, pr.ID))
+	}
+	if strings.Join(found, " ") != "ws/api#3 ws/api#9 ws/web#5" {
+		t.Errorf("unexpected pull requests %v", found)
+	}
+	if fmt.Sprint(unread) != "[broken]" {
+		t.Errorf("unexpected unread repositories %v", unread)
+	}
+
+	opts := &approveOptions{merge: true, mergeMethod: "squash"}
+	var statuses []string
+	for _, pr := range prs {
+		result := approveAndMerge(context.Background(), client, pr, "{me}", opts)
+		statuses = append(statuses, result.Status)
+	}
+	if strings.Join(statuses, " ") != "skipped merged failed" {
+		t.Errorf("unexpected statuses %v", statuses)
+	}
+	want := []string{
+		"POST /repositories/ws/api/pullrequests/9/approve",
+		"POST /repositories/ws/api/pullrequests/9/merge",
+		"POST /repositories/ws/web/pullrequests/5/approve",
+		"POST /repositories/ws/web/pullrequests/5/merge",
+	}
+	if strings.Join(actions, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected requests:\n%s", strings.Join(actions, "\n"))
+	}
+}
+
+func TestRevertDescription(t *testing.T) {
+	pr := &api.PullRequest{ID: 42, Title: "Add login"}
+	pr.Source.Branch.Name = "feature/login"
+	pr.Destination.Branch.Name = "main"
+	pr.MergeCommit = &api.Commit{Hash: "0123456789ab"}
+	pr.Links.HTML.Href = "https://bitbucket.org/team/app/pull-requests/42"
+
+	if got := revertBranchName(pr); got != "revert-42-feature/login" {
+		t.Errorf("revertBranchName() = %q", got)
+	}
+	want := "Reverts pull request #42: Add login\n\n" +
+		"Original pull request: https://bitbucket.org/team/app/pull-requests/42\n\n" +
+		"This reverts merge commit 0123456 of #42, from feature/login into main."
+	if got := revertDescription(pr); got != want {
+		t.Errorf("revertDescription() = %q, want %q", got, want)
+	}
+
+	pr.Reviewers = []api.User{{UUID: "{a}"}}
+	pr.Participants = []api.Participant{
+		{User: api.User{UUID: "{a}"}, Role: "REVIEWER"},
+		{User: api.User{UUID: "{b}"}, Role: "REVIEWER"},
+		{User: api.User{UUID: "{c}"}, Role: "PARTICIPANT"},
+	}
+	if got := originalReviewers(pr); fmt.Sprint(got) != "[{a} {b}]" {
+		t.Errorf("originalReviewers() = %v", got)
+	}
+}
+
+func TestRevertFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/repositories/team/app/src/parent/") {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		fmt.Fprintf(w, "old %s", strings.TrimPrefix(r.URL.Path, "/repositories/team/app/src/parent/"))
+	}))
+	defer server.Close()
+
+	client := api.NewClient(api.WithBaseURL(server.URL))
+	path := func(p string) *struct {
+		Path string `json:"path"`
+	} {
+		return &struct {
+			Path string `json:"path"`
+		}{Path: p}
+	}
+	changes := []api.DiffStat{
+		{Status: "added", New: path("new.go")},
+		{Status: "removed", Old: path("gone.go")},
+		{Status: "modified", Old: path("main.go"), New: path("main.go")},
+		{Status: "renamed", Old: path("a.go"), New: path("b.go")},
+	}
+
+	files, deleted, err := revertFiles(context.Background(), client, "team", "app", "parent", changes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(deleted) != "[new.go b.go]" {
+		t.Errorf("deleted = %v", deleted)
+	}
+	if len(files) != 3 || string(files["gone.go"]) != "old gone.go" || string(files["main.go"]) != "old main.go" || string(files["a.go"]) != "old a.go" {
+		t.Errorf("files = %q", files)
+	}
+	if got := diffStatPaths(changes); fmt.Sprint(got) != "[new.go gone.go main.go a.go b.go]" {
+		t.Errorf("diffStatPaths() = %v", got)
+	}
+}
+
+func TestPushBranchWithoutPrompt(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Chdir(t.TempDir())
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	bare := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "--bare", bare},
+		{"init", "-q", "-b", "feature"},
+		{"commit", "-q", "--allow-empty", "-m", "Initial commit"},
+		{"remote", "add", "origin", bare},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	var out bytes.Buffer
+	streams := &iostreams.IOStreams{Out: &out, ErrOut: &out}
+
+	err := pushBranch(streams, "feature", "feature", false)
+	if err == nil || !strings.Contains(err.Error(), "use --push") {
+		t.Fatalf("pushBranch() without a prompt = %v, want an error suggesting --push", err)
+	}
+	if sha, _ := git.RemoteBranchCommit("origin", "feature"); sha != "" {
+		t.Fatal("expected the branch not to be pushed without asking")
+	}
+
+	if err := pushBranch(streams, "feature", "feature", true); err != nil {
+		t.Fatalf("pushBranch() with --push returned error: %v", err)
+	}
+	if sha, _ := git.RemoteBranchCommit("origin", "feature"); sha == "" {
+		t.Fatal("expected --push to push the branch")
+	}
+
+	if out, err := exec.Command("git", "commit", "-q", "--allow-empty", "-m", "Unpushed").CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %v\n%s", err, out)
+	}
+	out.Reset()
+	if err := pushBranch(streams, "feature", "feature", false); err != nil {
+		t.Fatalf("pushBranch() with unpushed commits returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "not pushing") {
+		t.Errorf("expected a warning that the commits were not pushed, got %q", out.String())
+	}
+}
+
+func TestCompleteMergeKeepsBranchOfUnmovedStack(t *testing.T) {
+	deleted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repositories/workspace/repo/pullrequests":
+			w.Write([]byte(`{"values": [{"id": 2, "title": "Stacked"}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repositories/workspace/repo/pullrequests/1/merge":
+			w.Write([]byte(`{"id": 1, "state": "MERGED"}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/repositories/workspace/repo/pullrequests/2":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error": {"message": "Forbidden"}}`))
+		case r.Method == http.MethodDelete:
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	pr := &api.PullRequest{ID: 1}
+	pr.Source.Branch.Name = "feature"
+	pr.Destination.Branch.Name = "main"
+
+	var out bytes.Buffer
+	streams := &iostreams.IOStreams{Out: &out, ErrOut: &out}
+	client := api.NewClient(api.WithBaseURL(server.URL))
+	if err := completeMerge(context.Background(), streams, client, "workspace", "repo", pr, "merge", "", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if deleted {
+		t.Error("expected the branch not to be deleted while a pull request still targets it")
+	}
+	if !strings.Contains(out.String(), "Could not move pull request #2") || !strings.Contains(out.String(), "Not deleting branch feature") {
+		t.Errorf("expected the failed move and the kept branch to be reported, got:\n%s", out.String())
+	}
+}
+
+func TestNewCmdPR(t *testing.T) {
+	cmd := NewCmdPR(&iostreams.IOStreams{})
+
+	create, _, err := cmd.Find([]string{"create"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := create.ParseFlags([]string{"--push", "--no-push"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := create.ValidateFlagGroups(); err == nil {
+		t.Error("ValidateFlagGroups() = nil, want --push and --no-push rejected together")
+	}
+}
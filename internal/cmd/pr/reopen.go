@@ -33,7 +33,8 @@ Only declined pull requests can be reopened. Merged pull requests cannot be reop
 
   # Reopen a PR in a specific repository
   bb pr reopen 123 --repo workspace/repo`,
-		Args: cobra.ExactArgs(1),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: cmdutil.CompleteOpenPRs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runReopen(opts, args)
 		},
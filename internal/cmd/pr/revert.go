@@ -0,0 +1,328 @@
+package pr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+type revertOptions struct {
+	streams *iostreams.IOStreams
+	branch  string
+	title   string
+	useAPI  bool
+	repo    string
+}
+
+// NewCmdRevert creates the revert command
+func NewCmdRevert(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &revertOptions{
+		streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "revert <number>",
+		Short: "Open a pull request that reverts a merged pull request",
+		Long: `Undo a merged pull request: commit the reversal of its merge commit to
+a new branch, and open a pull request from it into the original destination
+branch, with the original reviewers.
+
+In a clone of the repository, the revert is made with git, in a temporary
+worktree that leaves your checkout alone, and pushed. Otherwise, or with
+--api, it is committed through the API, which restores the files the pull
+request changed to their content before it; this fails if any of them has
+changed on the destination branch since the merge.`,
+		Example: `  # Revert pull request #123
+  bb pr revert 123
+
+  # Revert it without git, naming the branch
+  bb pr revert 123 --api --branch undo-login-change
+
+  # Revert a pull request in another repository
+  bb pr revert 123 -R workspace/repo`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRevert(cmd.Context(), opts, args)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.branch, "branch", "", `Branch to commit the revert to (default "revert-<number>-<source branch>")`)
+	cmd.Flags().StringVarP(&opts.title, "title", "t", "", `Title of the pull request (default "Revert \"<title>\"")`)
+	cmd.Flags().BoolVar(&opts.useAPI, "api", false, "Revert through the API instead of git")
+	cmd.Flags().StringVarP(&opts.repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+
+	return cmd
+}
+
+func runRevert(ctx context.Context, opts *revertOptions, args []string) error {
+	prNum, err := parsePRNumber(args)
+	if err != nil {
+		return err
+	}
+
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	pr, err := client.GetPullRequest(ctx, workspace, repoSlug, int64(prNum))
+	if err != nil {
+		return fmt.Errorf("failed to get pull request: %w", err)
+	}
+	if pr.State != api.PRStateMerged || pr.MergeCommit == nil {
+		return fmt.Errorf("pull request #%d is not merged (current state: %s)", prNum, pr.State)
+	}
+
+	branch := opts.branch
+	if branch == "" {
+		branch = revertBranchName(pr)
+	}
+	_, err = client.GetBranch(ctx, workspace, repoSlug, branch)
+	var apiErr *api.APIError
+	switch {
+	case err == nil:
+		return fmt.Errorf("branch %q already exists; choose another with --branch", branch)
+	case !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound:
+		return fmt.Errorf("failed to check branch %s: %w", branch, err)
+	}
+
+	title := opts.title
+	if title == "" {
+		title = fmt.Sprintf("Revert %q", pr.Title)
+	}
+	message := fmt.Sprintf("%s\n\nThis reverts merge commit %s of pull request #%d.", title, pr.MergeCommit.Hash, pr.ID)
+
+	dest := pr.Destination.Branch.Name
+	if opts.repo == "" && !opts.useAPI && git.IsGitRepository() {
+		// git may ask for credentials on the terminal, so no spinner runs
+		// over it
+		opts.streams.Info("Reverting #%d on %s...", pr.ID, branch)
+		err = revertWithGit(pr.MergeCommit.Hash, dest, branch, message)
+	} else {
+		progress := opts.streams.StartProgress(fmt.Sprintf("Reverting #%d on %s", pr.ID, branch))
+		err = revertWithAPI(ctx, client, workspace, repoSlug, pr.MergeCommit.Hash, dest, branch, message)
+		progress.Stop()
+	}
+	if err != nil {
+		return err
+	}
+
+	reviewers := originalReviewers(pr)
+	createOpts := &api.PRCreateOptions{
+		Title:             title,
+		Description:       revertDescription(pr),
+		SourceBranch:      branch,
+		DestinationBranch: dest,
+		CloseSourceBranch: true,
+		Reviewers:         mergeReviewers(ctx, client, nil, reviewers),
+	}
+	revert, err := client.CreatePullRequest(ctx, workspace, repoSlug, createOpts)
+	if err != nil {
+		return fmt.Errorf("failed to create pull request: %w", err)
+	}
+
+	opts.streams.Success("Opened pull request #%d to revert #%d", revert.ID, pr.ID)
+	fmt.Fprintln(opts.streams.Out, revert.Links.HTML.Href)
+	return nil
+}
+
+// revertBranchName is the default branch for the revert of pr
+func revertBranchName(pr *api.PullRequest) string {
+	return fmt.Sprintf("revert-%d-%s", pr.ID, pr.Source.Branch.Name)
+}
+
+// revertDescription is the description of the pull request reverting pr,
+// which links back to it
+func revertDescription(pr *api.PullRequest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Reverts pull request #%d: %s\n\n", pr.ID, pr.Title)
+	if pr.Links.HTML.Href != "" {
+		fmt.Fprintf(&b, "Original pull request: %s\n\n", pr.Links.HTML.Href)
+	}
+	fmt.Fprintf(&b, "This reverts merge commit %s of #%d, from %s into %s.",
+		shortHash(pr.MergeCommit.Hash), pr.ID, pr.Source.Branch.Name, pr.Destination.Branch.Name)
+	return b.String()
+}
+
+// originalReviewers returns the UUIDs of the reviewers of pr
+func originalReviewers(pr *api.PullRequest) []string {
+	var uuids []string
+	add := func(user api.User) {
+		if user.UUID != "" && !slices.Contains(uuids, user.UUID) {
+			uuids = append(uuids, user.UUID)
+		}
+	}
+	for _, user := range pr.Reviewers {
+		add(user)
+	}
+	for _, p := range pr.Participants {
+		if p.Role == "REVIEWER" {
+			add(p.User)
+		}
+	}
+	return uuids
+}
+
+// revertWithGit commits the revert of merge on top of the remote's dest to
+// a new branch of the remote, in a temporary worktree
+func revertWithGit(merge, dest, branch, message string) error {
+	remote, err := git.GetDefaultRemote()
+	if err != nil {
+		return err
+	}
+	if err := git.Fetch(remote.Name, dest); err != nil {
+		return err
+	}
+
+	// Reverting a merge commit keeps the destination's side of it
+	parents, err := git.ParentCount(merge)
+	if err != nil {
+		return err
+	}
+	mainline := 0
+	if parents > 1 {
+		mainline = 1
+	}
+
+	dir, err := os.MkdirTemp("", "bb-revert-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	if err := git.AddWorktree(dir, remote.Name+"/"+dest); err != nil {
+		return err
+	}
+	defer func() { _ = git.RemoveWorktree(dir) }()
+
+	if err := git.Revert(dir, merge, mainline, message); err != nil {
+		return fmt.Errorf("%w; revert it by hand, or resolve the conflicts on %s first", err, dest)
+	}
+	head, err := git.HeadCommit(dir)
+	if err != nil {
+		return err
+	}
+	return git.Push(remote.Name, head+":refs/heads/"+branch, false)
+}
+
+// revertWithAPI commits the revert of merge on top of dest to a new branch,
+// by restoring the files it changed to their content in its first parent
+func revertWithAPI(ctx context.Context, client *api.Client, workspace, repoSlug, merge, dest, branch, message string) error {
+	commit, err := client.GetCommit(ctx, workspace, repoSlug, merge)
+	if err != nil {
+		return fmt.Errorf("failed to get merge commit: %w", err)
+	}
+	if len(commit.Parents) == 0 {
+		return fmt.Errorf("merge commit %s has no parent", shortHash(merge))
+	}
+	parent := commit.Parents[0].Hash
+
+	head, err := client.GetBranch(ctx, workspace, repoSlug, dest)
+	if err != nil {
+		return fmt.Errorf("failed to get branch %s: %w", dest, err)
+	}
+
+	changes, err := client.GetDiffStat(ctx, workspace, repoSlug, commit.Hash+".."+parent, &api.DiffOptions{TwoDot: true})
+	if err != nil {
+		return fmt.Errorf("failed to get the changes of %s: %w", shortHash(merge), err)
+	}
+
+	// Restoring files overwrites whatever was done to them since the merge
+	if head.Target.Hash != commit.Hash {
+		since, err := client.GetDiffStat(ctx, workspace, repoSlug, head.Target.Hash+".."+commit.Hash, &api.DiffOptions{TwoDot: true})
+		if err != nil {
+			return fmt.Errorf("failed to get the changes to %s since the merge: %w", dest, err)
+		}
+		touched := diffStatPaths(changes)
+		for _, path := range diffStatPaths(since) {
+			if slices.Contains(touched, path) {
+				return fmt.Errorf("%s has changed on %s since the merge; revert with git from a clone instead", path, dest)
+			}
+		}
+	}
+
+	files, deleted, err := revertFiles(ctx, client, workspace, repoSlug, parent, changes)
+	if err != nil {
+		return err
+	}
+	_, err = client.CommitFiles(ctx, workspace, repoSlug, &api.CommitFilesOptions{
+		Message: message,
+		Branch:  branch,
+		Parents: []string{head.Target.Hash},
+		Files:   files,
+		Delete:  deleted,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to commit the revert: %w", err)
+	}
+	return nil
+}
+
+// revertFiles returns the contents to restore, from parent, and the paths
+// to delete that undo changes
+func revertFiles(ctx context.Context, client *api.Client, workspace, repoSlug, parent string, changes []api.DiffStat) (map[string][]byte, []string, error) {
+	files := make(map[string][]byte)
+	var deleted []string
+	for _, change := range changes {
+		if change.New != nil && (change.Status == "added" || change.Status == "renamed") {
+			deleted = append(deleted, change.New.Path)
+		}
+		if change.Old == nil || change.Status == "added" {
+			continue
+		}
+		content, err := readFileAt(ctx, client, workspace, repoSlug, parent, change.Old.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		files[change.Old.Path] = content
+	}
+	return files, deleted, nil
+}
+
+// readFileAt returns the content of the file at path in commit
+func readFileAt(ctx context.Context, client *api.Client, workspace, repoSlug, commit, path string) ([]byte, error) {
+	body, err := client.OpenFile(ctx, workspace, repoSlug, commit, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", path, err)
+	}
+	defer body.Close()
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return content, nil
+}
+
+// diffStatPaths returns the paths, old and new, of the files in stats
+func diffStatPaths(stats []api.DiffStat) []string {
+	var paths []string
+	for _, s := range stats {
+		if s.Old != nil {
+			paths = append(paths, s.Old.Path)
+		}
+		if s.New != nil && (s.Old == nil || s.New.Path != s.Old.Path) {
+			paths = append(paths, s.New.Path)
+		}
+	}
+	return paths
+}
@@ -2,10 +2,12 @@ package pr
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/spf13/cobra"
 
+	"github.com/rbansal42/bitbucket-cli/internal/api"
 	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
@@ -16,6 +18,7 @@ type reviewOptions struct {
 	approve        bool
 	requestChanges bool
 	comment        bool
+	interactive    bool
 	body           string
 }
 
@@ -31,7 +34,24 @@ func NewCmdReview(streams *iostreams.IOStreams) *cobra.Command {
 		Long: `Add a review to a pull request.
 
 You can approve a pull request, request changes, or just add a review comment.
-At least one action flag (--approve, --request-changes, or --comment) must be specified.`,
+At least one action flag (--approve, --request-changes, --comment or
+--interactive) must be specified.
+
+With --interactive, the pull request's diff opens full screen, with the
+changed files in a tree beside it. Comments composed on lines of the diff
+are kept pending, shown inline, and posted together when you submit the
+review with an approval, a request for changes, or as comments only.
+
+Keys in the interactive review:
+  j/k, ↓/↑       move through the diff, or the files when they have focus
+  tab, h/l       switch focus between the files and the diff
+  n/p            next or previous file
+  space/b        page down or up
+  c              comment on the current line; enter saves, esc cancels
+  C              comment on the current line in your editor
+  d              delete the pending comment on the current line
+  s              submit the review
+  q              quit without submitting`,
 		Example: `  # Approve a pull request
   bb pr review 123 --approve
 
@@ -42,9 +62,16 @@ At least one action flag (--approve, --request-changes, or --comment) must be sp
   bb pr review 123 --comment
 
   # Add a review comment with body
-  bb pr review 123 --comment --body "Looks good overall"`,
-		Args: cobra.MaximumNArgs(1),
+  bb pr review 123 --comment --body "Looks good overall"
+
+  # Browse the diff and comment on lines before approving
+  bb pr review 123 --interactive`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: cmdutil.CompleteOpenPRs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.interactive {
+				return runInteractiveReview(cmd.Context(), opts, args)
+			}
 			return runReview(opts, args)
 		},
 	}
@@ -53,7 +80,11 @@ At least one action flag (--approve, --request-changes, or --comment) must be sp
 	cmd.Flags().BoolVarP(&opts.requestChanges, "request-changes", "r", false, "Request changes on the pull request")
 	cmd.Flags().BoolVarP(&opts.comment, "comment", "c", false, "Add a review comment")
 	cmd.Flags().StringVarP(&opts.body, "body", "b", "", "Review comment body")
+	cmd.Flags().BoolVarP(&opts.interactive, "interactive", "i", false, "Review the diff full screen, commenting on lines before submitting")
 	cmd.Flags().StringVarP(&opts.repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+	for _, flag := range []string{"approve", "request-changes", "comment", "body"} {
+		cmd.MarkFlagsMutuallyExclusive("interactive", flag)
+	}
 
 	return cmd
 }
@@ -61,7 +92,7 @@ At least one action flag (--approve, --request-changes, or --comment) must be sp
 func runReview(opts *reviewOptions, args []string) error {
 	// Validate that at least one action is specified
 	if !opts.approve && !opts.requestChanges && !opts.comment {
-		return fmt.Errorf("please specify an action: --approve, --request-changes, or --comment")
+		return fmt.Errorf("please specify an action: --approve, --request-changes, --comment, or --interactive")
 	}
 
 	// Can't approve and request changes at the same time
@@ -88,14 +119,14 @@ func runReview(opts *reviewOptions, args []string) error {
 
 	// If comment flag is set and no body provided, open editor
 	if opts.comment && opts.body == "" {
-		body, err := openEditor("")
+		content, err := cmdutil.OpenEditor("")
+		if errors.Is(err, cmdutil.ErrEditorAborted) {
+			return fmt.Errorf("comment body is required")
+		}
 		if err != nil {
 			return fmt.Errorf("failed to get comment: %w", err)
 		}
-		if body == "" {
-			return fmt.Errorf("comment body is required")
-		}
-		opts.body = body
+		opts.body = content.Body
 	}
 
 	// Add comment if body is provided (for any action)
@@ -130,3 +161,93 @@ func runReview(opts *reviewOptions, args []string) error {
 
 	return nil
 }
+
+// runInteractiveReview reviews a pull request in the full-screen diff
+// browser, then posts the pending comments and the verdict
+func runInteractiveReview(ctx context.Context, opts *reviewOptions, args []string) error {
+	if !opts.streams.CanPrompt() {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, errNoTerminal)
+	}
+	if opts.streams.IsAccessible() {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, errAccessible)
+	}
+
+	prNum, err := parsePRNumber(args)
+	if err != nil {
+		return err
+	}
+
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	progress := opts.streams.StartProgress("Fetching pull request")
+	pr, err := client.GetPullRequest(ctx, workspace, repoSlug, int64(prNum))
+	if err != nil {
+		progress.Stop()
+		return fmt.Errorf("failed to get pull request: %w", err)
+	}
+	diff, err := client.GetPullRequestDiff(ctx, workspace, repoSlug, int64(prNum))
+	progress.Stop()
+	if err != nil {
+		return fmt.Errorf("failed to get diff: %w", err)
+	}
+
+	files := parseDiff(diff)
+	if len(files) == 0 {
+		return fmt.Errorf("pull request #%d has no changes to review", prNum)
+	}
+
+	m := newReviewModel(opts.streams, fmt.Sprintf("#%d %s", prNum, pr.Title), files)
+	if err := runReviewScreen(opts.streams, m); err != nil {
+		return err
+	}
+	if m.decision == reviewAbandoned {
+		if len(m.pending) > 0 {
+			opts.streams.Info("Discarded %d pending %s", len(m.pending), pluralize(len(m.pending), "comment"))
+		}
+		return nil
+	}
+
+	return submitReview(ctx, opts.streams, client, workspace, repoSlug, prNum, m.pending, m.decision)
+}
+
+// submitReview posts the pending comments of a review, then approves the
+// pull request or requests changes as decided. Comments are posted first,
+// so the author sees them alongside the verdict.
+func submitReview(ctx context.Context, streams *iostreams.IOStreams, client *api.Client, workspace, repoSlug string, prNum int, comments []pendingComment, decision reviewDecision) error {
+	for i, c := range comments {
+		_, err := client.AddPRComment(ctx, workspace, repoSlug, int64(prNum), &api.AddPRCommentOptions{
+			Content:  c.Body,
+			Path:     c.Path,
+			Line:     c.New,
+			FromLine: c.Old,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add comment on %s (%d of %d posted): %w", c.location(), i, len(comments), err)
+		}
+	}
+	if len(comments) > 0 {
+		streams.Success("Added %d review %s to pull request #%d", len(comments), pluralize(len(comments), "comment"), prNum)
+	}
+
+	switch decision {
+	case reviewApprove:
+		if _, err := client.ApprovePullRequest(ctx, workspace, repoSlug, int64(prNum)); err != nil {
+			return fmt.Errorf("failed to approve pull request: %w", err)
+		}
+		streams.Success("Approved pull request #%d", prNum)
+	case reviewRequestChanges:
+		if _, err := client.RequestChanges(ctx, workspace, repoSlug, int64(prNum)); err != nil {
+			return fmt.Errorf("failed to request changes: %w", err)
+		}
+		streams.Success("Requested changes on pull request #%d", prNum)
+	}
+	return nil
+}
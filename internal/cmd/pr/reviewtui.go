@@ -0,0 +1,729 @@
+package pr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// Keys read from the terminal by the interactive review. Printable
+// characters are returned as themselves.
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyLeft      = "left"
+	keyRight     = "right"
+	keyPageUp    = "pgup"
+	keyPageDown  = "pgdn"
+	keyHome      = "home"
+	keyEnd       = "end"
+	keyEnter     = "enter"
+	keyEsc       = "esc"
+	keyTab       = "tab"
+	keyBackspace = "backspace"
+	keyCtrlC     = "ctrl-c"
+)
+
+// parseKeys splits input read from a terminal in raw mode into keys.
+// Escape sequences it doesn't know are dropped.
+func parseKeys(b []byte) []string {
+	var keys []string
+	for len(b) > 0 {
+		switch c := b[0]; {
+		case c == 0x1b && len(b) >= 3 && (b[1] == '[' || b[1] == 'O'):
+			end := 2
+			for end < len(b)-1 && (b[end] < 0x40 || b[end] > 0x7e) {
+				end++
+			}
+			switch string(b[2 : end+1]) {
+			case "A":
+				keys = append(keys, keyUp)
+			case "B":
+				keys = append(keys, keyDown)
+			case "C":
+				keys = append(keys, keyRight)
+			case "D":
+				keys = append(keys, keyLeft)
+			case "5~":
+				keys = append(keys, keyPageUp)
+			case "6~":
+				keys = append(keys, keyPageDown)
+			case "H", "1~", "7~":
+				keys = append(keys, keyHome)
+			case "F", "4~", "8~":
+				keys = append(keys, keyEnd)
+			}
+			b = b[end+1:]
+		case c == 0x1b:
+			keys = append(keys, keyEsc)
+			b = b[1:]
+		case c == '\r' || c == '\n':
+			keys = append(keys, keyEnter)
+			b = b[1:]
+		case c == '\t':
+			keys = append(keys, keyTab)
+			b = b[1:]
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, keyBackspace)
+			b = b[1:]
+		case c == 0x03:
+			keys = append(keys, keyCtrlC)
+			b = b[1:]
+		case c < 0x20:
+			b = b[1:]
+		default:
+			r, size := utf8.DecodeRune(b)
+			keys = append(keys, string(r))
+			b = b[size:]
+		}
+	}
+	return keys
+}
+
+// reviewMode is what the interactive review is waiting for the user to do
+type reviewMode int
+
+const (
+	reviewBrowsing reviewMode = iota
+	reviewComposing
+	reviewSubmitting
+	reviewQuitting
+)
+
+// reviewDecision is how an interactive review ended
+type reviewDecision int
+
+const (
+	reviewAbandoned reviewDecision = iota
+	reviewCommentOnly
+	reviewApprove
+	reviewRequestChanges
+)
+
+// pendingComment is an inline comment composed during an interactive
+// review, posted when the review is submitted. Old and New are the line
+// numbers of the line it is on, as in diffLine.
+type pendingComment struct {
+	Path string
+	Old  int
+	New  int
+	Body string
+}
+
+// location describes the line the comment is on, e.g. "main.go:12"
+func (c pendingComment) location() string {
+	if c.New != 0 {
+		return fmt.Sprintf("%s:%d", c.Path, c.New)
+	}
+	return fmt.Sprintf("%s:%d (old)", c.Path, c.Old)
+}
+
+// reviewRow is a row of the diff pane: a line of the diff, or a line of a
+// pending comment on it
+type reviewRow struct {
+	line    int
+	comment bool
+	text    string
+}
+
+// treeRow is a row of the file tree: a directory, or a file with its index
+type treeRow struct {
+	label string
+	depth int
+	file  int
+}
+
+// reviewModel is the state of an interactive review. Keys are fed to
+// handleKey and the screen is drawn from view, so it can be tested without
+// a terminal.
+type reviewModel struct {
+	streams *iostreams.IOStreams
+	title   string
+	files   []diffFile
+	tree    []treeRow
+
+	file         int
+	cursor       int
+	top          int
+	filesFocused bool
+	height       int
+
+	mode    reviewMode
+	input   []rune
+	message string
+	pending []pendingComment
+
+	// editRequested asks the caller to compose the comment at the cursor
+	// in the user's editor and pass it to setComment
+	editRequested bool
+	done          bool
+	decision      reviewDecision
+}
+
+// newReviewModel starts a review of files, which are shown sorted by path
+func newReviewModel(streams *iostreams.IOStreams, title string, files []diffFile) *reviewModel {
+	sort.SliceStable(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	m := &reviewModel{streams: streams, title: title, files: files, height: 20}
+	m.tree = buildTree(files)
+	m.selectFile(0)
+	return m
+}
+
+// buildTree lays out the paths of files, which are sorted, as a tree of
+// directories
+func buildTree(files []diffFile) []treeRow {
+	var rows []treeRow
+	var prev []string
+	for i, f := range files {
+		var dirs []string
+		if dir := path.Dir(f.Path); dir != "." {
+			dirs = strings.Split(dir, "/")
+		}
+		common := 0
+		for common < len(dirs) && common < len(prev) && dirs[common] == prev[common] {
+			common++
+		}
+		for d := common; d < len(dirs); d++ {
+			rows = append(rows, treeRow{label: dirs[d] + "/", depth: d, file: -1})
+		}
+		rows = append(rows, treeRow{label: path.Base(f.Path), depth: len(dirs), file: i})
+		prev = dirs
+	}
+	return rows
+}
+
+func (m *reviewModel) currentFile() *diffFile {
+	if m.file < 0 || m.file >= len(m.files) {
+		return nil
+	}
+	return &m.files[m.file]
+}
+
+// selectFile shows file i, with the cursor on its first changed line
+func (m *reviewModel) selectFile(i int) {
+	if i < 0 || i >= len(m.files) {
+		return
+	}
+	m.file, m.cursor, m.top = i, 0, 0
+	for j, l := range m.files[i].Lines {
+		if l.Kind == diffAdded || l.Kind == diffRemoved {
+			m.cursor = j
+			break
+		}
+	}
+	m.scrollToCursor()
+}
+
+// moveCursor moves the cursor delta lines within the current file
+func (m *reviewModel) moveCursor(delta int) {
+	f := m.currentFile()
+	if f == nil || len(f.Lines) == 0 {
+		return
+	}
+	m.cursor = max(0, min(len(f.Lines)-1, m.cursor+delta))
+	m.scrollToCursor()
+}
+
+// scrollToCursor scrolls the diff pane so the cursor line and its pending
+// comment are visible
+func (m *reviewModel) scrollToCursor() {
+	first, last := -1, -1
+	for i, r := range m.rows() {
+		if r.line == m.cursor {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return
+	}
+	if last-m.height+1 > m.top {
+		m.top = last - m.height + 1
+	}
+	if first < m.top {
+		m.top = first
+	}
+}
+
+// rows lays out the current file's diff with its pending comments
+func (m *reviewModel) rows() []reviewRow {
+	f := m.currentFile()
+	if f == nil {
+		return nil
+	}
+	var rows []reviewRow
+	for i, l := range f.Lines {
+		rows = append(rows, reviewRow{line: i, text: formatDiffRow(l)})
+		if c := m.pendingAt(f.Path, l); c >= 0 {
+			for j, body := range strings.Split(m.pending[c].Body, "\n") {
+				prefix := "             "
+				if j == 0 {
+					prefix = "          ✎  "
+				}
+				rows = append(rows, reviewRow{line: i, comment: true, text: prefix + body})
+			}
+		}
+	}
+	return rows
+}
+
+func formatDiffRow(l diffLine) string {
+	num := func(n int) string {
+		if n == 0 {
+			return ""
+		}
+		return fmt.Sprint(n)
+	}
+	switch l.Kind {
+	case diffHunk:
+		return strings.Repeat(" ", 11) + l.Text
+	case diffAdded:
+		return fmt.Sprintf("%4s %4s +%s", "", num(l.New), l.Text)
+	case diffRemoved:
+		return fmt.Sprintf("%4s %4s -%s", num(l.Old), "", l.Text)
+	default:
+		return fmt.Sprintf("%4s %4s  %s", num(l.Old), num(l.New), l.Text)
+	}
+}
+
+// pendingAt returns the index of the pending comment on line l of the file
+// at path, or -1
+func (m *reviewModel) pendingAt(path string, l diffLine) int {
+	if l.Kind == diffHunk {
+		return -1
+	}
+	for i, c := range m.pending {
+		if c.Path == path && c.Old == l.Old && c.New == l.New {
+			return i
+		}
+	}
+	return -1
+}
+
+// commentTarget returns the line under the cursor, if a comment can go on
+// it
+func (m *reviewModel) commentTarget() (string, diffLine, bool) {
+	f := m.currentFile()
+	if f == nil || m.cursor >= len(f.Lines) || f.Lines[m.cursor].Kind == diffHunk {
+		return "", diffLine{}, false
+	}
+	return f.Path, f.Lines[m.cursor], true
+}
+
+// cursorComment returns the body of the pending comment under the cursor
+func (m *reviewModel) cursorComment() string {
+	p, l, ok := m.commentTarget()
+	if !ok {
+		return ""
+	}
+	if i := m.pendingAt(p, l); i >= 0 {
+		return m.pending[i].Body
+	}
+	return ""
+}
+
+// setComment sets the pending comment on the line under the cursor to
+// body, or removes it if body is blank
+func (m *reviewModel) setComment(body string) {
+	p, l, ok := m.commentTarget()
+	if !ok {
+		return
+	}
+	body = strings.TrimSpace(body)
+	i := m.pendingAt(p, l)
+	switch {
+	case body == "" && i >= 0:
+		m.pending = append(m.pending[:i], m.pending[i+1:]...)
+		m.message = "Comment removed"
+	case body == "":
+	case i >= 0:
+		m.pending[i].Body = body
+	default:
+		m.pending = append(m.pending, pendingComment{Path: p, Old: l.Old, New: l.New, Body: body})
+	}
+	m.scrollToCursor()
+}
+
+// handleKey updates the review for a key press
+func (m *reviewModel) handleKey(k string) {
+	m.message = ""
+	switch m.mode {
+	case reviewComposing:
+		m.composeKey(k)
+	case reviewSubmitting:
+		m.submitKey(k)
+	case reviewQuitting:
+		if k == "y" || k == "Y" {
+			m.done, m.decision = true, reviewAbandoned
+		}
+		m.mode = reviewBrowsing
+	default:
+		m.browseKey(k)
+	}
+}
+
+func (m *reviewModel) browseKey(k string) {
+	switch k {
+	case "q", keyCtrlC:
+		if len(m.pending) > 0 {
+			m.mode = reviewQuitting
+		} else {
+			m.done, m.decision = true, reviewAbandoned
+		}
+	case keyTab:
+		m.filesFocused = !m.filesFocused
+	case "h", keyLeft:
+		m.filesFocused = true
+	case "l", keyRight:
+		m.filesFocused = false
+	case keyEnter:
+		if m.filesFocused {
+			m.filesFocused = false
+		}
+	case "j", keyDown:
+		if m.filesFocused {
+			m.selectFile(m.file + 1)
+		} else {
+			m.moveCursor(1)
+		}
+	case "k", keyUp:
+		if m.filesFocused {
+			m.selectFile(m.file - 1)
+		} else {
+			m.moveCursor(-1)
+		}
+	case "n", "]":
+		m.selectFile(m.file + 1)
+	case "p", "[":
+		m.selectFile(m.file - 1)
+	case " ", keyPageDown:
+		m.moveCursor(m.height)
+	case "b", keyPageUp:
+		m.moveCursor(-m.height)
+	case "g", keyHome:
+		m.moveCursor(-m.cursor)
+	case "G", keyEnd:
+		if f := m.currentFile(); f != nil {
+			m.moveCursor(len(f.Lines))
+		}
+	case "c", "C":
+		if _, _, ok := m.commentTarget(); !ok {
+			m.message = "Move to a line of the diff to comment on it"
+			return
+		}
+		if k == "C" {
+			m.editRequested = true
+			return
+		}
+		m.mode = reviewComposing
+		m.input = []rune(m.cursorComment())
+	case "d":
+		if m.cursorComment() == "" {
+			m.message = "No pending comment on this line"
+			return
+		}
+		m.setComment("")
+	case "s":
+		m.mode = reviewSubmitting
+	}
+}
+
+func (m *reviewModel) composeKey(k string) {
+	switch k {
+	case keyEnter:
+		m.setComment(string(m.input))
+		m.mode = reviewBrowsing
+	case keyEsc, keyCtrlC:
+		m.mode = reviewBrowsing
+	case keyBackspace:
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+	default:
+		if utf8.RuneCountInString(k) == 1 {
+			m.input = append(m.input, []rune(k)...)
+		}
+	}
+}
+
+func (m *reviewModel) submitKey(k string) {
+	m.mode = reviewBrowsing
+	switch k {
+	case "a":
+		m.done, m.decision = true, reviewApprove
+	case "r":
+		m.done, m.decision = true, reviewRequestChanges
+	case "c":
+		if len(m.pending) == 0 {
+			m.message = "No comments to submit"
+			return
+		}
+		m.done, m.decision = true, reviewCommentOnly
+	}
+}
+
+// view draws the review on a screen of width by height cells: a header,
+// the file tree beside the diff, and a status line
+func (m *reviewModel) view(width, height int) []string {
+	width, height = max(width, 20), max(height, 3)
+	m.height = height - 2
+	m.scrollToCursor()
+
+	lines := make([]string, 0, height)
+	header := "Review " + m.title
+	if n := len(m.pending); n > 0 {
+		header += fmt.Sprintf(" — %d pending %s", n, pluralize(n, "comment"))
+	}
+	lines = append(lines, m.streams.Style(iostreams.RoleHeader, fitWidth(header, width)))
+
+	treeWidth := min(40, width/3)
+	diffWidth := width - treeWidth - 1
+	tree := m.treeView(treeWidth)
+	diff := m.diffView(diffWidth)
+	for i := 0; i < m.height; i++ {
+		left, right := strings.Repeat(" ", treeWidth), ""
+		if i < len(tree) {
+			left = tree[i]
+		}
+		if i < len(diff) {
+			right = diff[i]
+		}
+		lines = append(lines, left+m.streams.Style(iostreams.RoleMuted, "│")+right)
+	}
+
+	lines = append(lines, fitWidth(m.statusLine(), width))
+	return lines
+}
+
+func (m *reviewModel) treeView(width int) []string {
+	selected := 0
+	for i, r := range m.tree {
+		if r.file == m.file {
+			selected = i
+		}
+	}
+	start := max(0, selected-m.height+1)
+
+	var lines []string
+	for _, r := range m.tree[start:min(len(m.tree), start+m.height)] {
+		if r.file < 0 {
+			lines = append(lines, m.streams.Style(iostreams.RoleMuted, fitWidth(strings.Repeat("  ", r.depth)+" "+r.label, width)))
+			continue
+		}
+		marker := " "
+		if r.file == m.file {
+			marker = "▸"
+		}
+		label := strings.Repeat("  ", r.depth) + marker + r.label
+		if n := m.fileComments(m.files[r.file].Path); n > 0 {
+			label += fmt.Sprintf(" ✎%d", n)
+		}
+		text := fitWidth(label, width)
+		if r.file == m.file {
+			text = m.highlight(text, m.filesFocused)
+		}
+		lines = append(lines, text)
+	}
+	return lines
+}
+
+func (m *reviewModel) fileComments(path string) int {
+	n := 0
+	for _, c := range m.pending {
+		if c.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+func (m *reviewModel) diffView(width int) []string {
+	f := m.currentFile()
+	if f == nil {
+		return nil
+	}
+	if len(f.Lines) == 0 {
+		return []string{m.streams.Style(iostreams.RoleMuted, fitWidth(" No textual changes", width))}
+	}
+
+	rows := m.rows()
+	var lines []string
+	for _, r := range rows[min(m.top, len(rows)):min(len(rows), m.top+m.height)] {
+		marker := " "
+		if r.line == m.cursor && !r.comment {
+			marker = ">"
+		}
+		text := fitWidth(marker+r.text, width)
+		switch l := f.Lines[r.line]; {
+		case r.comment:
+			text = m.streams.Style(iostreams.RoleWarning, text)
+		case r.line == m.cursor:
+			text = m.highlight(text, !m.filesFocused)
+		case l.Kind == diffHunk:
+			text = m.streams.Style(iostreams.RoleInfo, text)
+		case l.Kind == diffAdded:
+			text = m.streams.Style(iostreams.RoleAddition, text)
+		case l.Kind == diffRemoved:
+			text = m.streams.Style(iostreams.RoleDeletion, text)
+		}
+		lines = append(lines, text)
+	}
+	return lines
+}
+
+// highlight shows text in reverse video when color is enabled and the pane
+// it is in has focus
+func (m *reviewModel) highlight(text string, focused bool) string {
+	if !focused || !m.streams.ColorEnabled() {
+		return text
+	}
+	return "\033[7m" + text + iostreams.Reset
+}
+
+func (m *reviewModel) statusLine() string {
+	switch m.mode {
+	case reviewComposing:
+		p, l, _ := m.commentTarget()
+		c := pendingComment{Path: p, Old: l.Old, New: l.New}
+		return fmt.Sprintf("Comment on %s: %s█", c.location(), string(m.input))
+	case reviewSubmitting:
+		return fmt.Sprintf("Submit review with %d %s: [a]pprove  [r]equest changes  [c]omment only  [esc] back",
+			len(m.pending), pluralize(len(m.pending), "comment"))
+	case reviewQuitting:
+		return fmt.Sprintf("Discard %d pending %s? [y/N]", len(m.pending), pluralize(len(m.pending), "comment"))
+	}
+	if m.message != "" {
+		return m.message
+	}
+	return "j/k move  tab files/diff  n/p next/prev file  c comment  C comment in editor  d delete comment  s submit  q quit"
+}
+
+func pluralize(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
+
+// fitWidth expands tabs in s and cuts or pads it to exactly width cells.
+// Every rune is taken to be one cell wide.
+func fitWidth(s string, width int) string {
+	s = strings.ReplaceAll(s, "\t", "    ")
+	n := utf8.RuneCountInString(s)
+	if n > width {
+		return string([]rune(s)[:width])
+	}
+	return s + strings.Repeat(" ", width-n)
+}
+
+// errNoTerminal is returned when an interactive review is started without
+// a terminal to show it on
+var errNoTerminal = errors.New("--interactive requires a terminal")
+
+// errAccessible is returned for --interactive in accessible mode, since
+// screen readers can't follow a full-screen view
+var errAccessible = errors.New("--interactive is not available in accessible mode; read the diff with 'bb pr diff' and review with --approve, --request-changes or --comment")
+
+// runReviewScreen shows m full screen until the review is submitted or
+// abandoned. The terminal is restored before it returns.
+func runReviewScreen(streams *iostreams.IOStreams, m *reviewModel) error {
+	in, inOK := streams.In.(*os.File)
+	out, outOK := streams.Out.(*os.File)
+	if !inOK || !outOK || !streams.CanPrompt() {
+		return errNoTerminal
+	}
+
+	restore, err := enterScreen(in, out)
+	if err != nil {
+		return err
+	}
+	defer func() { restore() }()
+
+	buf := make([]byte, 256)
+	for !m.done {
+		width, height, err := term.GetSize(int(out.Fd()))
+		if err != nil {
+			width, height = 80, 24
+		}
+		drawScreen(out, m.view(width, height))
+
+		n, err := in.Read(buf)
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			m.handleKey(k)
+			if m.editRequested {
+				m.editRequested = false
+				restore()
+				m.editComment()
+				if restore, err = enterScreen(in, out); err != nil {
+					restore = func() {}
+					return err
+				}
+			}
+			if m.done {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// editComment composes the comment under the cursor in the user's editor
+func (m *reviewModel) editComment() {
+	p, l, _ := m.commentTarget()
+	c := pendingComment{Path: p, Old: l.Old, New: l.New}
+	hint := fmt.Sprintf("Comment on %s. Save an empty file to remove the comment.", c.location())
+
+	content, err := cmdutil.OpenEditor(m.cursorComment() + "\n\n<!-- " + hint + " -->\n")
+	switch {
+	case errors.Is(err, cmdutil.ErrEditorAborted):
+		m.setComment("")
+	case err != nil:
+		m.message = err.Error()
+	default:
+		m.setComment(content.Body)
+	}
+}
+
+// enterScreen puts the terminal in raw mode and switches to the alternate
+// screen, returning a function that undoes both
+func enterScreen(in, out *os.File) (func(), error) {
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up the terminal: %w", err)
+	}
+	fmt.Fprint(out, "\033[?1049h\033[?25l")
+	return func() {
+		fmt.Fprint(out, "\033[?25h\033[?1049l")
+		_ = term.Restore(int(in.Fd()), state)
+	}, nil
+}
+
+// drawScreen replaces the screen's contents with lines
+func drawScreen(w io.Writer, lines []string) {
+	var b strings.Builder
+	b.WriteString("\033[H")
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(line)
+		b.WriteString("\033[K")
+	}
+	b.WriteString("\033[J")
+	io.WriteString(w, b.String())
+}
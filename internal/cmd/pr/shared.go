@@ -1,15 +1,17 @@
 package pr
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"os/exec"
 	"strconv"
-	"strings"
 
-	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
 )
 
+// prBranchConfigKey is the git config key, under branch.<name>, recording
+// the pull request a local branch was checked out or created for
+const prBranchConfigKey = "bb-pr"
+
 // parsePRNumber parses a PR number from args or returns an error
 func parsePRNumber(args []string) (int, error) {
 	if len(args) == 0 {
@@ -29,65 +31,22 @@ func parsePRNumber(args []string) (int, error) {
 	return prNum, nil
 }
 
-// openEditor opens the user's preferred editor for text input
-func openEditor(initialContent string) (string, error) {
-	editor := getEditor()
-
-	// Create temp file
-	tmpFile, err := os.CreateTemp("", "bb-*.md")
+// currentBranchPR returns the number of the pull request tied to the current
+// branch: the one recorded by pr checkout or pr create, or else the open pull
+// request from the branch it tracks
+func currentBranchPR(ctx context.Context, workspace, repoSlug string) (int, error) {
+	branch, err := git.CurrentBranch()
 	if err != nil {
-		return "", fmt.Errorf("failed to create temp file: %w", err)
+		return 0, fmt.Errorf("could not determine current branch: %w", err)
 	}
-	defer os.Remove(tmpFile.Name())
-
-	// Write initial content
-	if initialContent != "" {
-		if _, err := tmpFile.WriteString(initialContent); err != nil {
-			return "", fmt.Errorf("failed to write to temp file: %w", err)
-		}
-	}
-	tmpFile.Close()
-
-	// Open editor
-	cmd := exec.Command(editor, tmpFile.Name())
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("editor exited with error: %w", err)
-	}
-
-	// Read content back
-	content, err := os.ReadFile(tmpFile.Name())
-	if err != nil {
-		return "", fmt.Errorf("failed to read temp file: %w", err)
+	if number, err := strconv.Atoi(git.BranchConfig(branch, prBranchConfigKey)); err == nil && number > 0 {
+		return number, nil
 	}
-
-	return strings.TrimSpace(string(content)), nil
+	return findPRForBranch(ctx, workspace, repoSlug, git.RemoteBranchName(branch))
 }
 
-// getEditor returns the user's preferred editor
-func getEditor() string {
-	// Check BB_EDITOR first
-	if editor := os.Getenv("BB_EDITOR"); editor != "" {
-		return editor
-	}
-
-	// Check config
-	cfg, err := config.LoadConfig()
-	if err == nil && cfg.Editor != "" {
-		return cfg.Editor
-	}
-
-	// Check standard environment variables
-	if editor := os.Getenv("VISUAL"); editor != "" {
-		return editor
-	}
-	if editor := os.Getenv("EDITOR"); editor != "" {
-		return editor
-	}
-
-	// Default to vi
-	return "vi"
+// recordBranchPR ties a local branch to a pull request for later commands
+// run without a pull request number
+func recordBranchPR(branch string, number int64) error {
+	return git.SetBranchConfig(branch, prBranchConfigKey, strconv.FormatInt(number, 10))
 }
@@ -0,0 +1,317 @@
+package pr
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// maxStackPages bounds how many pages of open pull requests are read to
+// find the stacks
+const maxStackPages = 10
+
+// NewCmdStack creates the pr stack command
+func NewCmdStack(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stack <command>",
+		Short: "Work with stacked pull requests",
+		Long: `Work with stacks of pull requests, where each pull request targets the
+source branch of the one before it.
+
+Create a stacked pull request with "bb pr create --stack". When a pull
+request in a stack is merged with "bb pr merge", the pull requests that
+targeted its branch are moved onto the branch it was merged into.`,
+		Example: `  # Show the stack of the current branch
+  bb pr stack view`,
+	}
+
+	cmd.AddCommand(NewCmdStackView(streams))
+
+	return cmd
+}
+
+// StackViewOptions holds the options for the stack view command
+type StackViewOptions struct {
+	Repo    string
+	PRID    int64
+	JSON    bool
+	Format  string
+	Streams *iostreams.IOStreams
+}
+
+// NewCmdStackView creates the pr stack view command
+func NewCmdStackView(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &StackViewOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "view [<number>]",
+		Short: "View the stack a pull request is in",
+		Long: `Show the stack a pull request is in as a tree, from the branch at its
+bottom to every open pull request built on top of it.
+
+Without a number, the stack of the current branch's pull request is shown.`,
+		Example: `  # View the stack of the current branch
+  bb pr stack view
+
+  # View the stack pull request #123 is in
+  bb pr stack view 123
+
+  # Output the stack as JSON, bottom first
+  bb pr stack view --json`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: cmdutil.CompleteOpenPRs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				number, err := parsePRNumber(args)
+				if err != nil {
+					return err
+				}
+				opts.PRID = int64(number)
+			}
+			return runStackView(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
+
+	return cmd
+}
+
+func runStackView(ctx context.Context, opts *StackViewOptions) error {
+	// Parse repository
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.Repo)
+	if err != nil {
+		return err
+	}
+
+	// Get API client
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	if opts.PRID == 0 {
+		number, err := currentBranchPR(ctx, workspace, repoSlug)
+		if err != nil {
+			return err
+		}
+		opts.PRID = int64(number)
+	}
+
+	progress := opts.Streams.StartProgress("Fetching pull requests")
+	prs, err := listOpenPRs(ctx, client, workspace, repoSlug)
+	progress.Stop()
+	if err != nil {
+		return err
+	}
+
+	stack, err := buildStack(prs, workspace+"/"+repoSlug, opts.PRID)
+	if err != nil {
+		return err
+	}
+
+	if opts.JSON || opts.Format != "" {
+		var output []api.PullRequestJSON
+		stack.walk(func(node *stackNode) {
+			output = append(output, api.PullRequestJSON{PullRequest: node.pr})
+		})
+		return cmdutil.PrintFormatted(opts.Streams, opts.Format, output)
+	}
+
+	printStack(opts.Streams, stack, opts.PRID)
+	return nil
+}
+
+// stackNode is a pull request in a stack and those stacked on it
+type stackNode struct {
+	pr       *api.PullRequest
+	children []*stackNode
+}
+
+// stack is a tree of pull requests built on top of a base branch
+type stack struct {
+	base string
+	root *stackNode
+}
+
+// walk calls fn for every pull request in the stack, parents first
+func (s *stack) walk(fn func(node *stackNode)) {
+	var visit func(node *stackNode)
+	visit = func(node *stackNode) {
+		fn(node)
+		for _, child := range node.children {
+			visit(child)
+		}
+	}
+	visit(s.root)
+}
+
+// buildStack finds the stack pull request id is in among the open pull
+// requests of repo, in WORKSPACE/REPO format. Pull requests from forks can
+// only be at the top of a stack, since their branches can't be targeted.
+func buildStack(prs []api.PullRequest, repo string, id int64) (*stack, error) {
+	bySource := map[string]*api.PullRequest{}
+	byDestination := map[string][]*api.PullRequest{}
+	var current *api.PullRequest
+	for i := range prs {
+		pr := &prs[i]
+		if pr.ID == id {
+			current = pr
+		}
+		byDestination[pr.Destination.Branch.Name] = append(byDestination[pr.Destination.Branch.Name], pr)
+		if !fromFork(pr, repo) {
+			bySource[pr.Source.Branch.Name] = pr
+		}
+	}
+	if current == nil {
+		return nil, fmt.Errorf("pull request #%d is not open", id)
+	}
+
+	// Walk down to the bottom of the stack
+	bottom := current
+	seen := map[int64]bool{bottom.ID: true}
+	for {
+		parent, ok := bySource[bottom.Destination.Branch.Name]
+		if !ok || seen[parent.ID] {
+			break
+		}
+		seen[parent.ID] = true
+		bottom = parent
+	}
+
+	// Then build the tree back up from it
+	visited := map[int64]bool{}
+	var grow func(pr *api.PullRequest) *stackNode
+	grow = func(pr *api.PullRequest) *stackNode {
+		visited[pr.ID] = true
+		node := &stackNode{pr: pr}
+		if fromFork(pr, repo) {
+			return node
+		}
+		for _, child := range byDestination[pr.Source.Branch.Name] {
+			if !visited[child.ID] {
+				node.children = append(node.children, grow(child))
+			}
+		}
+		return node
+	}
+
+	return &stack{base: bottom.Destination.Branch.Name, root: grow(bottom)}, nil
+}
+
+// fromFork reports whether pr comes from a repository other than repo
+func fromFork(pr *api.PullRequest, repo string) bool {
+	source := pr.Source.Repository
+	return source != nil && source.FullName != "" && !strings.EqualFold(source.FullName, repo)
+}
+
+func printStack(streams *iostreams.IOStreams, s *stack, current int64) {
+	fmt.Fprintln(streams.Out, streams.Style(iostreams.RoleHeader, s.base))
+
+	var printNode func(node *stackNode, prefix string, last bool)
+	printNode = func(node *stackNode, prefix string, last bool) {
+		branch, indent := "├─ ", "│  "
+		if last {
+			branch, indent = "└─ ", "   "
+		}
+		line := fmt.Sprintf("#%d  %s [%s]", node.pr.ID, node.pr.Title, node.pr.Source.Branch.Name)
+		if node.pr.ID == current {
+			line = streams.Style(iostreams.RoleAccent, line+" (current)")
+		}
+		fmt.Fprintf(streams.Out, "%s%s%s\n", prefix, branch, line)
+		for i, child := range node.children {
+			printNode(child, prefix+indent, i == len(node.children)-1)
+		}
+	}
+	printNode(s.root, "", true)
+}
+
+// listOpenPRs returns the open pull requests of a repository, reading at
+// most maxStackPages pages
+func listOpenPRs(ctx context.Context, client *api.Client, workspace, repoSlug string) ([]api.PullRequest, error) {
+	var prs []api.PullRequest
+	for page := 1; page <= maxStackPages; page++ {
+		result, err := client.ListPullRequests(ctx, workspace, repoSlug, &api.PRListOptions{
+			State: api.PRStateOpen,
+			Page:  page,
+			Limit: 50,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull requests: %w", err)
+		}
+		prs = append(prs, result.Values...)
+		if result.Next == "" {
+			break
+		}
+	}
+	return prs, nil
+}
+
+// stackParent finds the open pull request the local branch should be
+// stacked on: the one whose source branch, checked out locally, is the
+// nearest ancestor of it
+func stackParent(ctx context.Context, client *api.Client, workspace, repoSlug, localBranch string) (*api.PullRequest, error) {
+	prs, err := listOpenPRs(ctx, client, workspace, repoSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	var parent *api.PullRequest
+	nearest := -1
+	for i := range prs {
+		pr := &prs[i]
+		branch := pr.Source.Branch.Name
+		if branch == localBranch || !git.BranchExists(branch) || !git.IsAncestor(branch, localBranch) {
+			continue
+		}
+		if fromFork(pr, workspace+"/"+repoSlug) {
+			continue
+		}
+		distance, err := git.CountCommits(branch + ".." + localBranch)
+		if err != nil {
+			continue
+		}
+		if nearest < 0 || distance < nearest {
+			parent, nearest = pr, distance
+		}
+	}
+
+	if parent == nil {
+		return nil, cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("no open pull request found to stack %s on; use --base to choose the branch", localBranch))
+	}
+	return parent, nil
+}
+
+// retargetStacked moves the open pull requests targeting a merged pull
+// request's source branch onto the branch it was merged into, so a stack
+// keeps working as its bottom is merged. It reports whether every one was
+// moved.
+func retargetStacked(ctx context.Context, streams *iostreams.IOStreams, client *api.Client, workspace, repoSlug string, merged *api.PullRequest, stacked []api.PullRequest) bool {
+	moved := true
+	for _, pr := range stacked {
+		_, err := client.UpdatePullRequest(ctx, workspace, repoSlug, pr.ID, &api.PRCreateOptions{
+			DestinationBranch: merged.Destination.Branch.Name,
+			CloseSourceBranch: pr.CloseSourceBranch,
+		})
+		if err != nil {
+			streams.Warning("Could not move pull request #%d onto %s: %v", pr.ID, merged.Destination.Branch.Name, err)
+			moved = false
+			continue
+		}
+		streams.Success("Moved pull request #%d onto %s", pr.ID, merged.Destination.Branch.Name)
+	}
+	return moved
+}
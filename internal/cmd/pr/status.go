@@ -0,0 +1,212 @@
+package pr
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// StatusOptions holds the options for the status command
+type StatusOptions struct {
+	Repo    string
+	JSON    bool
+	Format  string
+	Streams *iostreams.IOStreams
+}
+
+// prStatus is the structured output of pr status
+type prStatus struct {
+	CurrentBranch   string                `json:"current_branch,omitempty"`
+	CurrentBranchPR *api.PullRequestJSON  `json:"current_branch_pr"`
+	CreatedByYou    []api.PullRequestJSON `json:"created_by_you"`
+	ReviewRequested []api.PullRequestJSON `json:"review_requested"`
+}
+
+// NewCmdStatus creates the pr status command
+func NewCmdStatus(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &StatusOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show status of relevant pull requests",
+		Long: `Show the open pull requests relevant to you in a repository: the one
+for the current branch, the ones you created and the ones you are a
+reviewer of.
+
+The current branch is matched by the name of the branch it tracks, so a
+local branch pushed under a different name still finds its pull request.`,
+		Example: `  # Show pull request status
+  bb pr status
+
+  # Output as JSON
+  bb pr status --json
+
+  # Show status for a specific repository
+  bb pr status --repo workspace/repo`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStatus(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
+
+	return cmd
+}
+
+func runStatus(ctx context.Context, opts *StatusOptions) error {
+	// Parse repository
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.Repo)
+	if err != nil {
+		return err
+	}
+
+	// Get API client
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	progress := opts.Streams.StartProgress("Fetching pull requests")
+	status, err := fetchStatus(ctx, client, workspace, repoSlug, opts.Repo == "")
+	progress.Stop()
+	if err != nil {
+		return err
+	}
+
+	if opts.JSON || opts.Format != "" {
+		return cmdutil.PrintFormatted(opts.Streams, opts.Format, status)
+	}
+
+	printStatus(opts.Streams, workspace, repoSlug, status)
+	return nil
+}
+
+// fetchStatus looks up the pull requests shown by pr status. The current
+// branch is only considered when the repository comes from the local clone.
+func fetchStatus(ctx context.Context, client *api.Client, workspace, repoSlug string, local bool) (*prStatus, error) {
+	user, err := client.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	status := &prStatus{
+		CreatedByYou:    []api.PullRequestJSON{},
+		ReviewRequested: []api.PullRequestJSON{},
+	}
+
+	if local {
+		if branch, err := git.CurrentBranch(); err == nil {
+			status.CurrentBranch = git.RemoteBranchName(branch)
+			pr, err := branchPR(ctx, client, workspace, repoSlug, branch)
+			if err != nil {
+				return nil, err
+			}
+			if pr != nil {
+				status.CurrentBranchPR = &api.PullRequestJSON{PullRequest: pr}
+			}
+		}
+	}
+
+	created, err := client.ListPullRequests(ctx, workspace, repoSlug, &api.PRListOptions{
+		State:  api.PRStateOpen,
+		Author: user.Username,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+	for i := range created.Values {
+		status.CreatedByYou = append(status.CreatedByYou, api.PullRequestJSON{PullRequest: &created.Values[i]})
+	}
+
+	reviewing, err := client.ListPullRequests(ctx, workspace, repoSlug, &api.PRListOptions{
+		State:    api.PRStateOpen,
+		Reviewer: user.UUID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+	for i := range reviewing.Values {
+		status.ReviewRequested = append(status.ReviewRequested, api.PullRequestJSON{PullRequest: &reviewing.Values[i]})
+	}
+
+	return status, nil
+}
+
+// branchPR returns the pull request recorded for a local branch, or else the
+// open pull request from the branch it tracks, or nil if there is none
+func branchPR(ctx context.Context, client *api.Client, workspace, repoSlug, branch string) (*api.PullRequest, error) {
+	if number, err := strconv.ParseInt(git.BranchConfig(branch, prBranchConfigKey), 10, 64); err == nil && number > 0 {
+		return client.GetPullRequest(ctx, workspace, repoSlug, number)
+	}
+
+	result, err := client.ListPullRequests(ctx, workspace, repoSlug, &api.PRListOptions{
+		State:        api.PRStateOpen,
+		SourceBranch: git.RemoteBranchName(branch),
+		Limit:        1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for pull request: %w", err)
+	}
+	if len(result.Values) == 0 {
+		return nil, nil
+	}
+	return &result.Values[0], nil
+}
+
+func printStatus(streams *iostreams.IOStreams, workspace, repoSlug string, status *prStatus) {
+	fmt.Fprintf(streams.Out, "\nRelevant pull requests in %s/%s\n\n", workspace, repoSlug)
+
+	if status.CurrentBranch != "" {
+		printStatusHeading(streams, "Current branch")
+		if status.CurrentBranchPR != nil {
+			printStatusPR(streams, status.CurrentBranchPR.PullRequest)
+		} else {
+			fmt.Fprintf(streams.Out, "  There is no pull request associated with [%s]\n", status.CurrentBranch)
+		}
+		fmt.Fprintln(streams.Out)
+	}
+
+	printStatusHeading(streams, "Created by you")
+	if len(status.CreatedByYou) == 0 {
+		fmt.Fprintln(streams.Out, "  You have no open pull requests")
+	}
+	for _, pr := range status.CreatedByYou {
+		printStatusPR(streams, pr.PullRequest)
+	}
+	fmt.Fprintln(streams.Out)
+
+	printStatusHeading(streams, "Requesting a code review from you")
+	if len(status.ReviewRequested) == 0 {
+		fmt.Fprintln(streams.Out, "  You have no pull requests to review")
+	}
+	for _, pr := range status.ReviewRequested {
+		printStatusPR(streams, pr.PullRequest)
+	}
+	fmt.Fprintln(streams.Out)
+}
+
+func printStatusHeading(streams *iostreams.IOStreams, heading string) {
+	fmt.Fprintln(streams.Out, streams.Style(iostreams.RoleHeader, heading))
+}
+
+func printStatusPR(streams *iostreams.IOStreams, pr *api.PullRequest) {
+	fmt.Fprintf(streams.Out, "  #%d  %s [%s]", pr.ID, pr.Title, pr.Source.Branch.Name)
+	if pr.State != "" && pr.State != api.PRStateOpen {
+		fmt.Fprintf(streams.Out, " - %s", formatStatus(streams, string(pr.State)))
+	}
+	fmt.Fprintln(streams.Out)
+}
@@ -8,14 +8,13 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/rbansal42/bitbucket-cli/internal/api"
-	"github.com/rbansal42/bitbucket-cli/internal/browser"
 	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
-	"github.com/rbansal42/bitbucket-cli/internal/git"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
 
@@ -24,7 +23,11 @@ type viewOptions struct {
 	selector  string // PR number, URL, or branch
 	repo      string
 	web       bool
+	copy      bool
 	jsonOut   bool
+	format    string
+	raw       bool
+	comments  bool
 	workspace string
 	repoSlug  string
 }
@@ -40,9 +43,22 @@ func NewCmdView(streams *iostreams.IOStreams) *cobra.Command {
 		Short: "View a pull request",
 		Long: `Display the details of a pull request.
 
-With no arguments, the pull request for the current branch is displayed.
+With no arguments, the pull request for the current branch is displayed:
+the one it was checked out or created for with bb, recorded in the
+branch.<name>.bb-pr git config key, or else the open pull request from the
+branch it tracks.
 
-You can specify a pull request by number, URL, or branch name.`,
+You can specify a pull request by number, URL, or branch name.
+
+Alongside the pull request, its build statuses, a summary of the files it
+changes and, while it is open, how many commits its source branch is ahead
+of and behind its destination are shown, and with --comments its comments. These are fetched at the
+same time as the pull request and left out if they can't be fetched.
+
+With --comments, replies are shown threaded below the comments they answer.
+Inline comments are grouped by file and ordered by line, each below the
+lines of the diff it is on, and resolved threads are marked. The comments
+that start threads are shown with their IDs, for 'bb pr comment resolve'.`,
 		Example: `  # View the PR for the current branch
   bb pr view
 
@@ -59,8 +75,18 @@ You can specify a pull request by number, URL, or branch name.`,
   bb pr view --web
 
   # Output as JSON
-  bb pr view --json`,
-		Args: cobra.MaximumNArgs(1),
+  bb pr view --json
+
+  # Output as YAML
+  bb pr view --format yaml
+
+  # Show the description as written, without markdown rendering
+  bb pr view 123 --raw
+
+  # Include the comments
+  bb pr view 123 --comments`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: cmdutil.CompleteOpenPRs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
 				opts.selector = args[0]
@@ -78,8 +104,12 @@ You can specify a pull request by number, URL, or branch name.`,
 		},
 	}
 
-	cmd.Flags().BoolVarP(&opts.web, "web", "w", false, "Open the pull request in a web browser")
+	cmdutil.AddWebFlag(cmd, &opts.web, "pull request")
+	cmdutil.AddCopyFlag(cmd, &opts.copy, "pull request")
 	cmd.Flags().BoolVar(&opts.jsonOut, "json", false, "Output in JSON format")
+	cmd.Flags().BoolVar(&opts.raw, "raw", false, "Show the description without markdown rendering")
+	cmd.Flags().BoolVarP(&opts.comments, "comments", "c", false, "Show the pull request's comments")
+	cmdutil.AddFormatFlag(cmd, &opts.format)
 	cmd.Flags().StringVarP(&opts.repo, "repo", "R", "", "Select a repository using the WORKSPACE/REPO format")
 
 	return cmd
@@ -108,38 +138,140 @@ func runView(opts *viewOptions) error {
 		return err
 	}
 
-	// Fetch PR details
-	pr, err := client.GetPullRequest(ctx, opts.workspace, opts.repoSlug, int64(prNumber))
+	// Fetch PR details, with the extra details only the formatted view shows
+	structured := opts.jsonOut || opts.format != ""
+	view, err := fetchPRView(ctx, client, opts.workspace, opts.repoSlug, int64(prNumber), !structured && !opts.web, opts.comments && !structured)
 	if err != nil {
 		return err
 	}
+	pr := view.pr
 
-	// Handle --web flag
-	if opts.web {
-		if err := browser.Open(pr.Links.HTML.Href); err != nil {
-			return fmt.Errorf("could not open browser: %w", err)
+	// Handle --copy and --web flags
+	if opts.copy {
+		if err := cmdutil.CopyURL(opts.streams, pr.Links.HTML.Href); err != nil {
+			return err
 		}
-		opts.streams.Success("Opened %s in your browser", pr.Links.HTML.Href)
-		return nil
+	}
+	if opts.web {
+		return cmdutil.OpenInBrowser(opts.streams, pr.Links.HTML.Href)
 	}
 
-	// Handle --json flag
-	if opts.jsonOut {
-		return outputJSON(opts.streams, pr)
+	// Handle --json and --format flags
+	if structured {
+		return outputStructured(opts.streams, opts.format, pr)
 	}
 
 	// Display formatted output
-	return displayPR(opts.streams, pr)
+	if view.commentsErr != nil {
+		opts.streams.Warning("Could not fetch comments: %v", view.commentsErr)
+	}
+	return displayPR(opts.streams, view, opts.raw)
+}
+
+// prView is a pull request and the details shown alongside it. A nil
+// detail could not be fetched, or was not asked for.
+type prView struct {
+	pr          *api.PullRequest
+	statuses    []api.CommitStatus
+	diffStat    []api.DiffStat
+	comments    []api.PRComment
+	commentsErr error
+	// divergence is how far the source branch of an open pull request has
+	// moved apart from the destination branch
+	divergence *api.Divergence
+	// reports are the Code Insights reports on the source commit
+	reports []api.Report
+	// diff is the pull request's diff, fetched to show the lines inline
+	// comments are on
+	diff []diffFile
+}
+
+// fetchPRView fetches a pull request and, when details is set, its build
+// statuses, diffstat, Code Insights reports and the divergence of its
+// branches, plus its comments when comments is set, and the diff if any of
+// them are inline. The requests run concurrently. Only failing to get the
+// pull request itself is an error; details that can't be fetched are left
+// out.
+func fetchPRView(ctx context.Context, client *api.Client, workspace, repoSlug string, id int64, details, comments bool) (*prView, error) {
+	view := &prView{}
+	var prErr error
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		view.pr, prErr = client.GetPullRequest(ctx, workspace, repoSlug, id)
+		if prErr != nil || !details {
+			return
+		}
+		if commit := view.pr.Source.Commit.Hash; commit != "" {
+			wg.Go(func() {
+				if reports, err := client.ListReports(ctx, workspace, repoSlug, commit); err == nil {
+					view.reports = reports
+				}
+			})
+		}
+		if !sameRepoOpenPR(view.pr) {
+			return
+		}
+		if d, err := client.GetDivergence(ctx, workspace, repoSlug, view.pr.Source.Branch.Name, view.pr.Destination.Branch.Name, prDivergenceLimit); err == nil {
+			view.divergence = d
+		}
+	})
+	if details {
+		wg.Go(func() {
+			if result, err := client.GetPullRequestStatuses(ctx, workspace, repoSlug, id); err == nil {
+				view.statuses = result.Values
+			}
+		})
+		wg.Go(func() {
+			if result, err := client.GetPullRequestDiffStat(ctx, workspace, repoSlug, id); err == nil {
+				view.diffStat = result.Values
+			}
+		})
+	}
+	if comments {
+		wg.Go(func() {
+			result, err := client.ListAllPRComments(ctx, workspace, repoSlug, id)
+			if err != nil {
+				view.commentsErr = err
+				return
+			}
+			view.comments = result
+			for _, c := range result {
+				if c.Inline != nil {
+					if diff, err := client.GetPullRequestDiff(ctx, workspace, repoSlug, id); err == nil {
+						view.diff = parseDiff(diff)
+					}
+					break
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	if prErr != nil {
+		return nil, prErr
+	}
+	return view, nil
+}
+
+// prDivergenceLimit is the most commits counted on each side of a pull
+// request's branches
+const prDivergenceLimit = 1000
+
+// sameRepoOpenPR reports whether pr is open and its branches are in the
+// same repository, so they can be compared
+func sameRepoOpenPR(pr *api.PullRequest) bool {
+	if pr.State != api.PRStateOpen {
+		return false
+	}
+	src, dst := pr.Source.Repository, pr.Destination.Repository
+	return src == nil || dst == nil || src.FullName == dst.FullName
 }
 
 func resolvePRNumber(ctx context.Context, opts *viewOptions) (int, error) {
 	// No selector - try to find PR for current branch
 	if opts.selector == "" {
-		branch, err := git.GetCurrentBranch()
-		if err != nil {
-			return 0, fmt.Errorf("could not determine current branch: %w", err)
-		}
-		return findPRForBranch(ctx, opts.workspace, opts.repoSlug, branch)
+		return currentBranchPR(ctx, opts.workspace, opts.repoSlug)
 	}
 
 	// Try as number
@@ -200,11 +332,13 @@ func findPRForBranch(ctx context.Context, workspace, repoSlug, branch string) (i
 	return int(result.Values[0].ID), nil
 }
 
-func outputJSON(streams *iostreams.IOStreams, pr *api.PullRequest) error {
-	return cmdutil.PrintJSON(streams, pr)
+func outputStructured(streams *iostreams.IOStreams, format string, pr *api.PullRequest) error {
+	return cmdutil.PrintFormatted(streams, format, pr)
 }
 
-func displayPR(streams *iostreams.IOStreams, pr *api.PullRequest) error {
+func displayPR(streams *iostreams.IOStreams, view *prView, raw bool) error {
+	pr := view.pr
+
 	// Title and state
 	fmt.Fprintf(streams.Out, "Title: %s\n", pr.Title)
 	fmt.Fprintf(streams.Out, "State: %s\n", strings.ToUpper(string(pr.State)))
@@ -216,7 +350,11 @@ func displayPR(streams *iostreams.IOStreams, pr *api.PullRequest) error {
 	// Description
 	fmt.Fprintln(streams.Out)
 	if pr.Description != "" {
-		fmt.Fprintln(streams.Out, pr.Description)
+		description := pr.Description
+		if !raw {
+			description = cmdutil.RenderMarkdown(streams, description)
+		}
+		fmt.Fprintln(streams.Out, description)
 	} else {
 		fmt.Fprintln(streams.Out, "(No description)")
 	}
@@ -244,12 +382,87 @@ func displayPR(streams *iostreams.IOStreams, pr *api.PullRequest) error {
 	fmt.Fprintf(streams.Out, "Base: %s <- %s\n",
 		pr.Destination.Branch.Name,
 		pr.Source.Branch.Name)
+	if d := view.divergence; d != nil {
+		fmt.Fprintf(streams.Out, "Branch: %s ahead, %s behind %s, merge base %s\n",
+			streams.Style(iostreams.RoleAddition, formatCommitCount(d.Ahead, d.AheadMore)),
+			streams.Style(iostreams.RoleDeletion, formatCommitCount(d.Behind, d.BehindMore)),
+			pr.Destination.Branch.Name, shortHash(d.MergeBase))
+	}
+
+	// Build statuses and changed files, when they could be fetched
+	if len(view.statuses) > 0 {
+		fmt.Fprintf(streams.Out, "Checks: %s\n", summarizeChecks(streams, view.statuses))
+	}
+	if len(view.reports) > 0 {
+		fmt.Fprintln(streams.Out, "Reports:")
+		for _, r := range view.reports {
+			fmt.Fprintf(streams.Out, "  %s (%s): %s\n", r.Title, r.ExternalID, cmdutil.SummarizeReport(streams, r))
+		}
+	}
+	if view.diffStat != nil {
+		added, removed := 0, 0
+		for _, d := range view.diffStat {
+			added += d.LinesAdded
+			removed += d.LinesRemoved
+		}
+		files := "files"
+		if len(view.diffStat) == 1 {
+			files = "file"
+		}
+		fmt.Fprintf(streams.Out, "Changes: %d %s, %s %s\n", len(view.diffStat), files,
+			streams.Style(iostreams.RoleAddition, fmt.Sprintf("+%d", added)),
+			streams.Style(iostreams.RoleDeletion, fmt.Sprintf("-%d", removed)))
+	}
 
 	// Comments
 	fmt.Fprintf(streams.Out, "Comments: %d\n", pr.CommentCount)
 
 	// Created date
-	fmt.Fprintf(streams.Out, "Created: %s\n", cmdutil.TimeAgo(pr.CreatedOn))
+	fmt.Fprintf(streams.Out, "Created: %s\n", cmdutil.FormatTime(streams, pr.CreatedOn))
+
+	if len(view.comments) > 0 {
+		displayComments(streams, buildThreads(view.comments), view.diff, raw)
+	}
 
 	return nil
 }
+
+// formatCommitCount formats a commit count, marking one that stopped at
+// the limit
+func formatCommitCount(n int, more bool) string {
+	s := strconv.Itoa(n)
+	if more {
+		s += "+"
+	}
+	return s
+}
+
+// summarizeChecks counts build statuses by state, e.g. "2 passing, 1 failing"
+func summarizeChecks(streams *iostreams.IOStreams, statuses []api.CommitStatus) string {
+	counts := map[string]int{}
+	for _, s := range statuses {
+		counts[s.State]++
+	}
+	var parts []string
+	for _, state := range []struct {
+		state, label string
+		role         iostreams.Role
+	}{
+		{"SUCCESSFUL", "passing", iostreams.RoleSuccess},
+		{"FAILED", "failing", iostreams.RoleError},
+		{"INPROGRESS", "running", iostreams.RoleWarning},
+		{"STOPPED", "stopped", iostreams.RoleMuted},
+	} {
+		if n := counts[state.state]; n > 0 {
+			parts = append(parts, streams.Style(state.role, fmt.Sprintf("%d %s", n, state.label)))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
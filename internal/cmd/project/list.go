@@ -3,7 +3,6 @@ package project
 import (
 	"context"
 	"fmt"
-	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -19,6 +18,7 @@ type listOptions struct {
 	Workspace string
 	Limit     int
 	JSON      bool
+	Format    string
 	Streams   *iostreams.IOStreams
 }
 
@@ -41,7 +41,10 @@ This command shows projects you have access to in the specified workspace.`,
   bb project list -w myworkspace --limit 10
 
   # Output as JSON
-  bb project list -w myworkspace --json`,
+  bb project list -w myworkspace --json
+
+  # Output as YAML
+  bb project list -w myworkspace --format yaml`,
 		Aliases: []string{"ls"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if opts.Workspace == "" {
@@ -60,6 +63,7 @@ This command shows projects you have access to in the specified workspace.`,
 	cmd.Flags().StringVarP(&opts.Workspace, "workspace", "w", "", "Workspace slug (required)")
 	cmd.Flags().IntVarP(&opts.Limit, "limit", "l", 30, "Maximum number of projects to list")
 	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
 
 	return cmd
 }
@@ -81,7 +85,9 @@ func runList(ctx context.Context, opts *listOptions) error {
 	}
 
 	// Fetch projects
+	progress := opts.Streams.StartProgress("Fetching projects")
 	result, err := client.ListProjects(ctx, opts.Workspace, listOpts)
+	progress.Stop()
 	if err != nil {
 		return fmt.Errorf("failed to list projects: %w", err)
 	}
@@ -91,16 +97,21 @@ func runList(ctx context.Context, opts *listOptions) error {
 		return nil
 	}
 
+	if err := opts.Streams.StartPager(); err != nil {
+		opts.Streams.Warning("%s", err)
+	}
+	defer opts.Streams.StopPager()
+
 	// Output results
-	if opts.JSON {
-		return outputListJSON(opts.Streams, result.Values)
+	if opts.JSON || opts.Format != "" {
+		return outputListStructured(opts.Streams, opts.Format, result.Values)
 	}
 
 	return outputListTable(opts.Streams, result.Values)
 }
 
-func outputListJSON(streams *iostreams.IOStreams, projects []api.ProjectFull) error {
-	// Create simplified JSON output
+func outputListStructured(streams *iostreams.IOStreams, format string, projects []api.ProjectFull) error {
+	// Create simplified output
 	output := make([]map[string]interface{}, len(projects))
 	for i, proj := range projects {
 		output[i] = map[string]interface{}{
@@ -115,39 +126,38 @@ func outputListJSON(streams *iostreams.IOStreams, projects []api.ProjectFull) er
 		}
 	}
 
-	return cmdutil.PrintJSON(streams, output)
+	return cmdutil.PrintFormatted(streams, format, output)
 }
 
 func outputListTable(streams *iostreams.IOStreams, projects []api.ProjectFull) error {
-	w := tabwriter.NewWriter(streams.Out, 0, 0, 2, ' ', 0)
+	table := cmdutil.NewTablePrinter(streams)
 
 	// Print header
-	header := "KEY\tNAME\tDESCRIPTION\tVISIBILITY"
-	cmdutil.PrintTableHeader(streams, w, header)
+	table.AddHeader("KEY", "NAME", "DESCRIPTION", "VISIBILITY")
 
 	// Print rows
 	for _, proj := range projects {
 		key := proj.Key
-		name := cmdutil.TruncateString(proj.Name, 30)
-		desc := cmdutil.TruncateString(proj.Description, 40)
+		name := proj.Name
+		desc := proj.Description
 		visibility := formatVisibility(streams, proj.IsPrivate)
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", key, name, desc, visibility)
+		table.AddRow(key, name, desc, visibility)
 	}
 
-	return w.Flush()
+	return table.Render()
 }
 
 func formatVisibility(streams *iostreams.IOStreams, isPrivate bool) string {
 	if isPrivate {
 		if streams.ColorEnabled() {
-			return iostreams.Yellow + "private" + iostreams.Reset
+			return streams.Style(iostreams.RoleWarning, "private")
 		}
 		return "private"
 	}
 
 	if streams.ColorEnabled() {
-		return iostreams.Green + "public" + iostreams.Reset
+		return streams.Style(iostreams.RoleSuccess, "public")
 	}
 	return "public"
 }
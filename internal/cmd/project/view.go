@@ -2,14 +2,12 @@ package project
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/rbansal42/bitbucket-cli/internal/api"
-	"github.com/rbansal42/bitbucket-cli/internal/browser"
 	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
 	"github.com/rbansal42/bitbucket-cli/internal/config"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
@@ -20,7 +18,9 @@ type viewOptions struct {
 	workspace string
 	key       string
 	web       bool
+	copy      bool
 	jsonOut   bool
+	format    string
 }
 
 // NewCmdView creates the project view command
@@ -43,7 +43,10 @@ short uppercase identifiers like "PROJ" or "DEV".`,
   bb project view PROJ -w myworkspace --web
 
   # Output as JSON
-  bb project view PROJ -w myworkspace --json`,
+  bb project view PROJ -w myworkspace --json
+
+  # Output as YAML
+  bb project view PROJ -w myworkspace --format yaml`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.key = args[0]
@@ -63,8 +66,10 @@ short uppercase identifiers like "PROJ" or "DEV".`,
 	}
 
 	cmd.Flags().StringVarP(&opts.workspace, "workspace", "w", "", "Workspace slug (required)")
-	cmd.Flags().BoolVar(&opts.web, "web", false, "Open the project in a web browser")
+	cmdutil.AddWebFlag(cmd, &opts.web, "project")
+	cmdutil.AddCopyFlag(cmd, &opts.copy, "project")
 	cmd.Flags().BoolVar(&opts.jsonOut, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.format)
 
 	return cmd
 }
@@ -85,31 +90,27 @@ func runView(ctx context.Context, opts *viewOptions) error {
 		return fmt.Errorf("failed to get project: %w", err)
 	}
 
-	// Handle --web flag
-	if opts.web {
-		if err := browser.Open(project.Links.HTML.Href); err != nil {
-			return fmt.Errorf("could not open browser: %w", err)
+	// Handle --copy and --web flags
+	if opts.copy {
+		if err := cmdutil.CopyURL(opts.streams, project.Links.HTML.Href); err != nil {
+			return err
 		}
-		opts.streams.Success("Opened %s in your browser", project.Links.HTML.Href)
-		return nil
+	}
+	if opts.web {
+		return cmdutil.OpenInBrowser(opts.streams, project.Links.HTML.Href)
 	}
 
-	// Handle --json flag
-	if opts.jsonOut {
-		return outputViewJSON(opts.streams, project)
+	// Handle --json and --format flags
+	if opts.jsonOut || opts.format != "" {
+		return outputViewStructured(opts.streams, opts.format, project)
 	}
 
 	// Display formatted output
 	return displayProject(opts.streams, project)
 }
 
-func outputViewJSON(streams *iostreams.IOStreams, project *api.ProjectFull) error {
-	data, err := json.MarshalIndent(project, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
-	}
-	fmt.Fprintln(streams.Out, string(data))
-	return nil
+func outputViewStructured(streams *iostreams.IOStreams, format string, project *api.ProjectFull) error {
+	return cmdutil.PrintFormatted(streams, format, project)
 }
 
 func displayProject(streams *iostreams.IOStreams, project *api.ProjectFull) error {
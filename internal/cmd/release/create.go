@@ -0,0 +1,317 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// notesLimit is the most commits listed in generated release notes
+const notesLimit = 500
+
+// CreateOptions holds the options for the create command
+type CreateOptions struct {
+	Streams      *iostreams.IOStreams
+	Tag          string
+	Target       string
+	Message      string
+	Pipeline     bool
+	Assets       []string
+	NotesFile    string
+	NotesIssue   int
+	NotesSnippet bool
+	Repo         string
+}
+
+// NewCmdCreate creates the create command
+func NewCmdCreate(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &CreateOptions{
+		Streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "create <tag>",
+		Short: "Tag a commit and publish it as a release",
+		Long: `Publish a release: tag a commit, optionally run the tag's pipeline,
+upload files to the repository's Downloads, and post release notes.
+
+The tag is made on --target, a branch or commit, which defaults to the main
+branch. With --message, it is an annotated tag.
+
+The release notes list the commits since the previous tag, or are read from
+--notes-file ("-" for standard input). They are added as a comment to the
+issue given with --notes-issue, or saved as a private snippet with
+--notes-snippet, or otherwise printed.
+
+Files to upload are checked before anything is done, and if a step fails
+the steps before it are not undone.`,
+		Example: `  # Tag the main branch and print the release notes
+  bb release create v1.2.3
+
+  # Tag a release branch with an annotated tag
+  bb release create v1.2.3 --target release/1.2 --message "Release 1.2.3"
+
+  # Run the tag pipeline and upload binaries
+  bb release create v1.2.3 --pipeline --asset dist/app-linux-amd64 --asset dist/app-darwin-arm64
+
+  # Post the notes to the issue tracking the release
+  bb release create v1.2.3 --notes-issue 42
+
+  # Save hand-written notes as a snippet
+  bb release create v1.2.3 --notes-file CHANGES.md --notes-snippet`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Tag = args[0]
+			return runCreate(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Target, "target", "", "Branch or commit to tag (default: the main branch)")
+	_ = cmd.RegisterFlagCompletionFunc("target", cmdutil.CompleteBranches)
+	cmd.Flags().StringVarP(&opts.Message, "message", "m", "", "Message of an annotated tag")
+	cmd.Flags().BoolVar(&opts.Pipeline, "pipeline", false, "Run the pipeline of the tag")
+	cmd.Flags().StringArrayVarP(&opts.Assets, "asset", "a", nil, "File to upload to Downloads (can be repeated)")
+	cmd.Flags().StringVarP(&opts.NotesFile, "notes-file", "F", "", `Read the release notes from a file ("-" for standard input)`)
+	cmd.Flags().IntVar(&opts.NotesIssue, "notes-issue", 0, "Post the release notes as a comment on this issue")
+	cmd.Flags().BoolVar(&opts.NotesSnippet, "notes-snippet", false, "Save the release notes as a private snippet")
+	cmd.MarkFlagsMutuallyExclusive("notes-issue", "notes-snippet")
+	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+
+	return cmd
+}
+
+func runCreate(ctx context.Context, opts *CreateOptions) error {
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.Repo)
+	if err != nil {
+		return err
+	}
+
+	// Fail before tagging if an asset is missing
+	for _, asset := range opts.Assets {
+		info, err := os.Stat(asset)
+		if err != nil {
+			return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("cannot upload %s: %w", asset, err))
+		}
+		if info.IsDir() {
+			return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("cannot upload %s: it is a directory", asset))
+		}
+	}
+
+	var notes string
+	if opts.NotesFile != "" {
+		content, err := readNotesFile(opts.Streams, opts.NotesFile)
+		if err != nil {
+			return err
+		}
+		notes = content
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	target := opts.Target
+	if target == "" {
+		target, err = cmdutil.DefaultBranch(ctx, client, workspace, repoSlug)
+		if err != nil {
+			return fmt.Errorf("could not determine the main branch; use --target: %w", err)
+		}
+	}
+	commit, err := client.GetCommit(ctx, workspace, repoSlug, target)
+	if err != nil {
+		return fmt.Errorf("failed to find %s: %w", target, err)
+	}
+
+	// The notes are generated before tagging, so the new tag isn't taken
+	// for the previous one
+	if notes == "" {
+		notes, err = generateNotes(ctx, client, workspace, repoSlug, opts.Tag, commit.Hash)
+		if err != nil {
+			return fmt.Errorf("failed to generate release notes: %w", err)
+		}
+	}
+
+	tagOpts := &api.TagCreateOptions{Name: opts.Tag, Message: opts.Message}
+	tagOpts.Target.Hash = commit.Hash
+	if _, err := client.CreateTag(ctx, workspace, repoSlug, tagOpts); err != nil {
+		return fmt.Errorf("failed to create tag %s: %w", opts.Tag, err)
+	}
+	opts.Streams.Success("Tagged %s as %s", shortHash(commit.Hash), opts.Tag)
+
+	if opts.Pipeline {
+		pipeline, err := client.RunPipeline(ctx, workspace, repoSlug, &api.PipelineRunOptions{
+			Target: &api.PipelineTarget{
+				Type:    "pipeline_ref_target",
+				RefType: "tag",
+				RefName: opts.Tag,
+				Commit:  &api.PipelineCommit{Type: "commit", Hash: commit.Hash},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to run the pipeline of %s: %w", opts.Tag, err)
+		}
+		opts.Streams.Success("Started pipeline #%d", pipeline.BuildNumber)
+	}
+
+	for _, asset := range opts.Assets {
+		if err := uploadAsset(ctx, opts.Streams, client, workspace, repoSlug, asset); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case opts.NotesIssue > 0:
+		comment, err := client.CreateIssueComment(ctx, workspace, repoSlug, opts.NotesIssue, notes)
+		if err != nil {
+			return fmt.Errorf("failed to post release notes to issue #%d: %w", opts.NotesIssue, err)
+		}
+		opts.Streams.Success("Posted release notes to issue #%d", opts.NotesIssue)
+		if comment.Links != nil && comment.Links.HTML != nil {
+			fmt.Fprintln(opts.Streams.Out, comment.Links.HTML.Href)
+		}
+	case opts.NotesSnippet:
+		title := fmt.Sprintf("%s/%s %s release notes", workspace, repoSlug, opts.Tag)
+		snippet, err := client.CreateSnippet(ctx, workspace, title, true, map[string]string{"RELEASE_NOTES.md": notes})
+		if err != nil {
+			return fmt.Errorf("failed to save release notes as a snippet: %w", err)
+		}
+		opts.Streams.Success("Saved release notes as snippet %d", snippet.ID)
+		fmt.Fprintln(opts.Streams.Out, snippet.Links.HTML.Href)
+	default:
+		fmt.Fprintln(opts.Streams.Out)
+		fmt.Fprint(opts.Streams.Out, notes)
+	}
+
+	return nil
+}
+
+// uploadAsset uploads the file at path to Downloads, under its base name
+func uploadAsset(ctx context.Context, streams *iostreams.IOStreams, client *api.Client, workspace, repoSlug, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("cannot upload %s: %w", path, err)
+	}
+	defer f.Close()
+
+	name := filepath.Base(path)
+	progress := streams.StartProgress("Uploading " + name)
+	err = client.UploadDownload(ctx, workspace, repoSlug, name, f)
+	progress.Stop()
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", name, err)
+	}
+	streams.Success("Uploaded %s to Downloads", name)
+	return nil
+}
+
+// generateNotes returns release notes for tag, on commit, listing the
+// commits since the most recent other tag
+func generateNotes(ctx context.Context, client *api.Client, workspace, repoSlug, tag, commit string) (string, error) {
+	tags, err := client.ListTags(ctx, workspace, repoSlug, &api.TagListOptions{Sort: "-target.date", Limit: 10})
+	if err != nil {
+		return "", err
+	}
+	var previous *api.Tag
+	for i, t := range tags.Values {
+		if t.Name != tag && t.Target != nil {
+			previous = &tags.Values[i]
+			break
+		}
+	}
+
+	listOpts := &api.CommitListOptions{Include: []string{commit}}
+	if previous != nil {
+		listOpts.Exclude = []string{previous.Target.Hash}
+	}
+	var commits []api.CommitFull
+	more := false
+	for page := 1; ; page++ {
+		listOpts.Page = page
+		listOpts.Limit = 100
+		result, err := client.ListCommits(ctx, workspace, repoSlug, listOpts)
+		if err != nil {
+			return "", err
+		}
+		commits = append(commits, result.Values...)
+		if len(commits) >= notesLimit {
+			more = len(commits) > notesLimit || result.Next != ""
+			commits = commits[:notesLimit]
+			break
+		}
+		if result.Next == "" || len(result.Values) == 0 {
+			break
+		}
+	}
+
+	previousName := ""
+	if previous != nil {
+		previousName = previous.Name
+	}
+	return formatNotes(tag, previousName, commits, more), nil
+}
+
+// formatNotes writes the release notes of tag as Markdown: the subjects of
+// its commits, newest first, since the previous tag if there is one. Merge
+// commits are left out, as the commits they merge are listed.
+func formatNotes(tag, previous string, commits []api.CommitFull, more bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", tag)
+	if previous != "" {
+		fmt.Fprintf(&b, "Changes since %s:\n\n", previous)
+	} else {
+		b.WriteString("Changes:\n\n")
+	}
+	listed := 0
+	for _, c := range commits {
+		if len(c.Parents) > 1 {
+			continue
+		}
+		subject, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+		fmt.Fprintf(&b, "- %s (%s)\n", subject, shortHash(c.Hash))
+		listed++
+	}
+	if listed == 0 {
+		b.WriteString("- No changes\n")
+	}
+	if more {
+		fmt.Fprintf(&b, "- ...and more; only the latest %d commits are listed\n", notesLimit)
+	}
+	return b.String()
+}
+
+// readNotesFile reads the release notes from path, or standard input when
+// it is -
+func readNotesFile(streams *iostreams.IOStreams, path string) (string, error) {
+	var content []byte
+	var err error
+	if path == "-" {
+		content, err = io.ReadAll(streams.In)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read release notes: %w", err)
+	}
+	return string(content), nil
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
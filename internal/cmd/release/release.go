@@ -0,0 +1,31 @@
+package release
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// NewCmdRelease creates the release command and its subcommands
+func NewCmdRelease(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "release <command>",
+		Short: "Publish releases",
+		Long: `Publish a release of a repository in one step: tag a commit, run the
+tag's pipeline, upload its binaries to the repository's Downloads, and post
+release notes generated from the commits since the previous tag.
+
+Bitbucket has no releases of its own; a release is the tag, the files in
+Downloads and the notes, which are posted to an issue or a snippet.`,
+		Example: `  # Tag the main branch as v1.2.3 and print the release notes
+  bb release create v1.2.3
+
+  # Tag, run the tag pipeline, upload binaries and post the notes to issue #42
+  bb release create v1.2.3 --pipeline --asset dist/app-linux --asset dist/app-darwin --notes-issue 42`,
+		Aliases: []string{"releases"},
+	}
+
+	cmd.AddCommand(NewCmdCreate(streams))
+
+	return cmd
+}
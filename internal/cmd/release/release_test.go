@@ -0,0 +1,61 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+func TestFormatNotes(t *testing.T) {
+	commits := []api.CommitFull{
+		{Hash: "1111111111", Message: "Fix login redirect\n\nLonger description"},
+		{Hash: "2222222222", Message: "Merge branch 'feature'", Parents: make([]struct {
+			Hash string `json:"hash"`
+		}, 2)},
+		{Hash: "3333333333", Message: "Add dark mode"},
+	}
+
+	got := formatNotes("v1.2.0", "v1.1.0", commits, false)
+	want := "## v1.2.0\n\nChanges since v1.1.0:\n\n- Fix login redirect (1111111)\n- Add dark mode (3333333)\n"
+	if got != want {
+		t.Errorf("formatNotes() = %q, want %q", got, want)
+	}
+
+	got = formatNotes("v0.1.0", "", nil, true)
+	if !strings.HasPrefix(got, "## v0.1.0\n\nChanges:\n\n- No changes\n") || !strings.Contains(got, "only the latest 500 commits") {
+		t.Errorf("formatNotes() = %q", got)
+	}
+}
+
+func TestGenerateNotes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repositories/team/app/refs/tags":
+			fmt.Fprint(w, `{"values": [{"name": "v1.2.0", "target": {"hash": "new"}}, {"name": "v1.1.0", "target": {"hash": "old"}}]}`)
+		case "/repositories/team/app/commits":
+			if got := r.URL.Query()["exclude"]; len(got) != 1 || got[0] != "old" {
+				t.Errorf("exclude = %v, want [old]", got)
+			}
+			fmt.Fprint(w, `{"values": [{"hash": "abcdef123456", "message": "Add search"}]}`)
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewClient(api.WithBaseURL(server.URL))
+	notes, err := generateNotes(context.Background(), client, "team", "app", "v1.2.0", "head")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "## v1.2.0\n\nChanges since v1.1.0:\n\n- Add search (abcdef1)\n"
+	if notes != want {
+		t.Errorf("generateNotes() = %q, want %q", notes, want)
+	}
+}
@@ -0,0 +1,166 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// accessPermissions are the permissions repo access grant can give
+var accessPermissions = []string{"read", "write", "admin"}
+
+type accessOptions struct {
+	streams    *iostreams.IOStreams
+	repo       string
+	subject    string
+	group      bool
+	permission string
+}
+
+// NewCmdAccess creates the access command and its subcommands
+func NewCmdAccess(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "access <command>",
+		Short: "Grant and revoke access to a repository",
+		Long: `Grant users and workspace groups access to a repository, or revoke it.
+
+Users are given by UUID, account ID, or the nickname, username or display
+name of a member of the repository's workspace; "me" stands for you.
+Groups are given by slug, with --group.`,
+		Example: `  # Give a user write access
+  bb repo access grant jsmith --permission write
+
+  # Give a group read access to another repository
+  bb repo access grant developers --group --permission read -R myworkspace/myrepo
+
+  # Take a user's access away
+  bb repo access revoke jsmith`,
+	}
+
+	cmd.AddCommand(newCmdAccessGrant(streams))
+	cmd.AddCommand(newCmdAccessRevoke(streams))
+
+	return cmd
+}
+
+func newCmdAccessGrant(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &accessOptions{
+		streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "grant <user|group> --permission {read|write|admin}",
+		Short: "Grant a user or group access to a repository",
+		Long: `Grant a user or workspace group a permission on a repository, replacing
+any permission granted to them on it before.`,
+		Example: `  # Give a user write access
+  bb repo access grant jsmith --permission write
+
+  # Give a group admin access
+  bb repo access grant platform-team --group --permission admin`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.subject = args[0]
+			if !slices.Contains(accessPermissions, opts.permission) {
+				return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("invalid permission %q: use read, write or admin", opts.permission))
+			}
+			return runAccess(cmd.Context(), opts)
+		},
+	}
+
+	addAccessFlags(cmd, opts)
+	cmd.Flags().StringVarP(&opts.permission, "permission", "p", "", "Permission to grant: {read|write|admin} (required)")
+	_ = cmd.MarkFlagRequired("permission")
+	_ = cmd.RegisterFlagCompletionFunc("permission", cobra.FixedCompletions(accessPermissions, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+func newCmdAccessRevoke(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &accessOptions{
+		streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "revoke <user|group>",
+		Short: "Revoke a user's or group's access to a repository",
+		Long: `Remove the permission granted to a user or workspace group on a
+repository. Access they have through the workspace or other groups is
+unaffected.`,
+		Example: `  # Take a user's access away
+  bb repo access revoke jsmith
+
+  # Take a group's access away
+  bb repo access revoke contractors --group`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.subject = args[0]
+			return runAccess(cmd.Context(), opts)
+		},
+	}
+
+	addAccessFlags(cmd, opts)
+
+	return cmd
+}
+
+func addAccessFlags(cmd *cobra.Command, opts *accessOptions) {
+	cmd.Flags().BoolVar(&opts.group, "group", false, "Treat the argument as the slug of a workspace group")
+	cmd.Flags().StringVarP(&opts.repo, "repo", "R", "", "Repository in WORKSPACE/REPO format (detects from git remote if not specified)")
+}
+
+// runAccess grants opts.permission to, or when it is empty revokes the
+// access of, the user or group opts.subject
+func runAccess(ctx context.Context, opts *accessOptions) error {
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	repo := workspace + "/" + repoSlug
+	if opts.group {
+		if opts.permission != "" {
+			if err := client.GrantRepositoryGroupPermission(ctx, workspace, repoSlug, opts.subject, opts.permission); err != nil {
+				return fmt.Errorf("failed to grant access: %w", err)
+			}
+			opts.streams.Success("Granted group %s %s access to %s", opts.subject, opts.permission, repo)
+			return nil
+		}
+		if err := client.RevokeRepositoryGroupPermission(ctx, workspace, repoSlug, opts.subject); err != nil {
+			return fmt.Errorf("failed to revoke access: %w", err)
+		}
+		opts.streams.Success("Revoked the access of group %s to %s", opts.subject, repo)
+		return nil
+	}
+
+	userID, name, err := cmdutil.ResolveWorkspaceUser(ctx, client, workspace, opts.subject)
+	if err != nil {
+		return err
+	}
+	if opts.permission != "" {
+		if err := client.GrantRepositoryUserPermission(ctx, workspace, repoSlug, userID, opts.permission); err != nil {
+			return fmt.Errorf("failed to grant access: %w", err)
+		}
+		opts.streams.Success("Granted %s %s access to %s", name, opts.permission, repo)
+		return nil
+	}
+	if err := client.RevokeRepositoryUserPermission(ctx, workspace, repoSlug, userID); err != nil {
+		return fmt.Errorf("failed to revoke access: %w", err)
+	}
+	opts.streams.Success("Revoked the access of %s to %s", name, repo)
+	return nil
+}
@@ -0,0 +1,189 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+type archiveOptions struct {
+	streams *iostreams.IOStreams
+	repo    string
+	ref     string
+	output  string
+	format  string
+}
+
+// NewCmdArchive creates the archive command
+func NewCmdArchive(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &archiveOptions{
+		streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "archive [<ref>]",
+		Short: "Download an archive of a repository",
+		Long: `Download the files of a repository at a branch, tag or commit as a
+tar.gz or zip archive. The archive is fetched from Bitbucket, so git is not
+needed and no history is downloaded.
+
+With no ref, the archive is of the main branch. The format follows the
+extension of --output, .zip or .tar.gz (also .tgz), unless --format is
+given. Without --output, the archive is saved in the current directory as
+REPO-REF.tar.gz. Use "--output -" to write it to standard output.`,
+		Example: `  # Download the main branch of the current repository
+  bb repo archive
+
+  # Download a tag as a zip file
+  bb repo archive v1.2.0 -o release.zip
+
+  # Download a commit of another repository
+  bb repo archive 4f2c1a9 -R myworkspace/myrepo -o repo.tar.gz
+
+  # Unpack an archive without saving it
+  bb repo archive main -o - | tar -xz`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				opts.ref = args[0]
+			}
+			if opts.format != "" && !slices.Contains(api.ArchiveFormats, opts.format) {
+				return cmdutil.NewExitError(cmdutil.ExitUsage,
+					fmt.Errorf("invalid --format %q: use %s", opts.format, strings.Join(api.ArchiveFormats, " or ")))
+			}
+			return runArchive(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "File to save the archive to, or - for standard output")
+	cmd.Flags().StringVar(&opts.format, "format", "", "Archive format: tar.gz or zip (default: from --output, else tar.gz)")
+
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(api.ArchiveFormats, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+func runArchive(ctx context.Context, opts *archiveOptions) error {
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	ref := opts.ref
+	if ref == "" {
+		ref, err = mainBranch(ctx, client, workspace, repoSlug)
+		if err != nil {
+			return err
+		}
+	}
+
+	format := archiveFormat(opts.format, opts.output)
+	output := opts.output
+	if output == "" {
+		output = defaultArchiveName(repoSlug, ref, format)
+	}
+
+	body, err := client.OpenRepositoryArchive(ctx, workspace, repoSlug, ref, format)
+	if err != nil {
+		return fmt.Errorf("failed to download archive: %w", err)
+	}
+	defer body.Close()
+
+	progress := opts.streams.StartByteProgress("Downloading archive", -1)
+	defer progress.Stop()
+
+	if output == "-" {
+		if _, err := io.Copy(opts.streams.Out, progress.Reader(body)); err != nil {
+			return fmt.Errorf("failed to download archive: %w", err)
+		}
+		return nil
+	}
+
+	size, err := writeArchive(output, progress.Reader(body))
+	progress.Stop()
+	if err != nil {
+		return err
+	}
+	opts.streams.Success("Saved %s/%s at %s to %s (%s)", workspace, repoSlug, ref, output, iostreams.FormatBytes(size))
+	return nil
+}
+
+// mainBranch returns the name of the main branch of a repository
+func mainBranch(ctx context.Context, client *api.Client, workspace, repoSlug string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	repo, err := client.GetRepository(ctx, workspace, repoSlug)
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository: %w", err)
+	}
+	if repo.MainBranch == nil || repo.MainBranch.Name == "" {
+		return "", fmt.Errorf("repository %s/%s has no main branch; give the ref to download", workspace, repoSlug)
+	}
+	return repo.MainBranch.Name, nil
+}
+
+// archiveFormat returns the format to download: format if given, else the
+// one the extension of output names, else tar.gz
+func archiveFormat(format, output string) string {
+	if format != "" {
+		return format
+	}
+	if strings.HasSuffix(strings.ToLower(output), ".zip") {
+		return "zip"
+	}
+	return "tar.gz"
+}
+
+// defaultArchiveName names the archive of ref, with the slashes of branch
+// names such as feature/login replaced so it stays a single file name
+func defaultArchiveName(repoSlug, ref, format string) string {
+	return fmt.Sprintf("%s-%s.%s", repoSlug, strings.ReplaceAll(ref, "/", "-"), format)
+}
+
+// writeArchive saves r to path, returning its size. The archive is written
+// to a temporary file next to path and renamed once complete, so a failed
+// download doesn't leave a truncated archive behind.
+func writeArchive(path string, r io.Reader) (int64, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), ".bb-archive-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to save archive: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	size, err := io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to download archive: %w", err)
+	}
+	if err := os.Chmod(f.Name(), 0o644); err != nil {
+		return 0, fmt.Errorf("failed to save archive: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return 0, fmt.Errorf("failed to save archive: %w", err)
+	}
+	return size, nil
+}
@@ -0,0 +1,62 @@
+package repo
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestArchiveFormat(t *testing.T) {
+	tests := []struct {
+		format, output, want string
+	}{
+		{"", "", "tar.gz"},
+		{"", "repo.tar.gz", "tar.gz"},
+		{"", "repo.tgz", "tar.gz"},
+		{"", "Repo.ZIP", "zip"},
+		{"", "-", "tar.gz"},
+		{"zip", "-", "zip"},
+		{"tar.gz", "repo.zip", "tar.gz"},
+	}
+	for _, tt := range tests {
+		if got := archiveFormat(tt.format, tt.output); got != tt.want {
+			t.Errorf("archiveFormat(%q, %q) = %q, want %q", tt.format, tt.output, got, tt.want)
+		}
+	}
+
+	if got := defaultArchiveName("web", "feature/login", "zip"); got != "web-feature-login.zip" {
+		t.Errorf("defaultArchiveName() = %q", got)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("connection reset") }
+
+func TestWriteArchive(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "repo.tar.gz")
+
+	size, err := writeArchive(path, strings.NewReader("archive"))
+	if err != nil {
+		t.Fatalf("writeArchive() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "archive" || size != 7 {
+		t.Errorf("saved %q (%d bytes), err %v", data, size, err)
+	}
+
+	// A failed download leaves neither a truncated archive nor the
+	// temporary file behind
+	failed := filepath.Join(dir, "failed.tar.gz")
+	if _, err := writeArchive(failed, io.MultiReader(strings.NewReader("part"), failingReader{})); err == nil {
+		t.Fatal("expected an error")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("expected only the first archive to be left, got %d entries", len(entries))
+	}
+}
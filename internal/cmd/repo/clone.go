@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -12,15 +11,25 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
 
 type cloneOptions struct {
-	streams   *iostreams.IOStreams
-	repoArg   string
-	directory string
-	depth     int
-	branch    string
+	streams      *iostreams.IOStreams
+	repoArg      string
+	directory    string
+	depth        int
+	branch       string
+	singleBranch bool
+	bare         bool
+	sparse       []string
+	protocol     string
+	all          bool
+	workspace    string
+	project      string
+	dryRun       bool
+	parallel     int
 }
 
 // NewCmdClone creates the repo clone command
@@ -30,16 +39,29 @@ func NewCmdClone(streams *iostreams.IOStreams) *cobra.Command {
 	}
 
 	cmd := &cobra.Command{
-		Use:   "clone <workspace/repo> [<directory>]",
+		Use:   "clone {<workspace/repo> | --all --workspace <workspace>} [<directory>]",
 		Short: "Clone a repository",
 		Long: `Clone a Bitbucket repository to your local machine.
 
 You can specify a repository using the workspace/repo format, or provide
 a full Bitbucket URL (SSH or HTTPS).
 
-The clone URL protocol (SSH or HTTPS) is determined by the git_protocol
-setting in your configuration. Use 'bb config set git_protocol <ssh|https>'
-to change this preference.`,
+The clone URL protocol (SSH or HTTPS) is taken from --protocol, then the
+git_protocol of the host in hosts.yml, then the git_protocol setting. Use
+'bb config set git_protocol <ssh|https>' to change this preference, or
+'bb config set -h <host> git_protocol <ssh|https>' for a single host.
+
+For large repositories, --depth, --single-branch and --sparse limit what is
+downloaded and checked out. --sparse takes a comma-separated list of
+directories, and needs git 2.25 or later.
+
+With --all, every repository of the workspace given with --workspace, or
+of one of its projects with --project, is cloned into a directory tree:
+<directory>/<project key>/<repository>, where the directory defaults to
+the workspace's name. Several repositories are cloned at once, as set with
+--parallel. Repositories already cloned are skipped, so an interrupted run
+is resumed by running the command again. --dry-run lists what would be
+cloned without cloning it.`,
 		Example: `  # Clone a repository
   bb repo clone myworkspace/myrepo
 
@@ -52,11 +74,45 @@ to change this preference.`,
   # Shallow clone (only latest commit)
   bb repo clone myworkspace/myrepo --depth 1
 
+  # Clone only one branch's history, checking out two directories
+  bb repo clone myworkspace/monorepo --single-branch --sparse services/api,libs
+
+  # Clone a bare repository, e.g. for a mirror
+  bb repo clone myworkspace/myrepo --bare
+
+  # Clone over SSH regardless of the configured protocol
+  bb repo clone myworkspace/myrepo --protocol ssh
+
+  # Clone every repository of a workspace into ./myworkspace
+  bb repo clone --all --workspace myworkspace
+
+  # Clone the repositories of one project, 8 at a time, into ~/src
+  bb repo clone --all --workspace myworkspace --project API --parallel 8 ~/src
+
+  # List what would be cloned
+  bb repo clone --all --workspace myworkspace --dry-run
+
   # Clone using a full URL
   bb repo clone https://bitbucket.org/myworkspace/myrepo.git
   bb repo clone git@bitbucket.org:myworkspace/myrepo.git`,
-		Args: cobra.RangeArgs(1, 2),
+		Args: cobra.RangeArgs(0, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.all {
+				if len(args) > 1 {
+					return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("--all takes at most a directory to clone into"))
+				}
+				if len(args) == 1 {
+					opts.directory = args[0]
+				}
+				return runCloneAll(cmd.Context(), opts)
+			}
+
+			if opts.workspace != "" || opts.project != "" || opts.dryRun {
+				return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("--workspace, --project and --dry-run require --all"))
+			}
+			if len(args) == 0 {
+				return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("a repository to clone is required, or --all"))
+			}
 			opts.repoArg = args[0]
 			if len(args) > 1 {
 				opts.directory = args[1]
@@ -68,6 +124,17 @@ to change this preference.`,
 
 	cmd.Flags().IntVar(&opts.depth, "depth", 0, "Create a shallow clone with a limited number of commits")
 	cmd.Flags().StringVarP(&opts.branch, "branch", "b", "", "Clone a specific branch")
+	cmd.Flags().BoolVar(&opts.singleBranch, "single-branch", false, "Clone only the history of one branch")
+	cmd.Flags().StringSliceVar(&opts.sparse, "sparse", nil, "Check out only these directories (comma-separated)")
+	cmd.Flags().BoolVar(&opts.bare, "bare", false, "Make a bare repository, without a working tree")
+	cmd.MarkFlagsMutuallyExclusive("bare", "sparse")
+	cmdutil.AddProtocolFlag(cmd, &opts.protocol)
+	cmd.Flags().BoolVar(&opts.all, "all", false, "Clone every repository of a workspace or project")
+	cmd.Flags().StringVarP(&opts.workspace, "workspace", "w", "", "Workspace to clone the repositories of, with --all")
+	cmd.Flags().StringVar(&opts.project, "project", "", "Only clone the repositories of this project key, with --all")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "List the repositories --all would clone without cloning them")
+	cmd.Flags().IntVar(&opts.parallel, "parallel", defaultCloneParallel, "Number of repositories --all clones at once")
+	cmd.MarkFlagsMutuallyExclusive("all", "sparse")
 
 	return cmd
 }
@@ -85,6 +152,11 @@ func runClone(opts *cloneOptions) error {
 			return fmt.Errorf("could not determine repository name from URL: %s", opts.repoArg)
 		}
 	} else {
+		protocol, err := cmdutil.GitProtocol(opts.protocol)
+		if err != nil {
+			return err
+		}
+
 		// Parse workspace/repo format
 		workspace, repoSlug, err := cmdutil.ParseRepository(opts.repoArg)
 		if err != nil {
@@ -106,9 +178,7 @@ func runClone(opts *cloneOptions) error {
 			return fmt.Errorf("failed to get repository: %w", err)
 		}
 
-		// Get preferred protocol and clone URL
-		protocol := getPreferredProtocol()
-		cloneURL = getCloneURL(repo.Links, protocol)
+		cloneURL = cmdutil.CloneURL(repo.Links, protocol)
 		if cloneURL == "" {
 			return fmt.Errorf("no clone URL found for repository")
 		}
@@ -119,6 +189,9 @@ func runClone(opts *cloneOptions) error {
 	// Use custom directory if specified
 	if opts.directory != "" {
 		destDir = opts.directory
+	} else if opts.bare {
+		// As git names bare clones
+		destDir += ".git"
 	}
 
 	// Check if destination already exists
@@ -128,48 +201,32 @@ func runClone(opts *cloneOptions) error {
 		}
 	}
 
-	// Build git clone command
-	args := []string{"clone"}
-
-	// Add depth flag if specified
-	if opts.depth > 0 {
-		args = append(args, "--depth", fmt.Sprintf("%d", opts.depth))
-	}
-
-	// Add branch flag if specified
-	if opts.branch != "" {
-		args = append(args, "--branch", opts.branch)
-	}
-
-	// Add progress flag for better UX
-	args = append(args, "--progress")
-
-	// Add clone URL
-	args = append(args, cloneURL)
-
-	// Add destination directory
-	if destDir != "" {
-		args = append(args, destDir)
-	}
-
 	// Execute git clone
 	opts.streams.Info("Cloning into '%s'...", destDir)
 
-	cmd := exec.Command("git", args...)
-	cmd.Stdout = opts.streams.Out
-	cmd.Stderr = opts.streams.ErrOut
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to clone repository: %w", err)
+	err := git.CloneWithOptions(cloneURL, destDir, git.CloneOptions{
+		Depth:        opts.depth,
+		Branch:       opts.branch,
+		SingleBranch: opts.singleBranch,
+		Bare:         opts.bare,
+		Sparse:       opts.sparse,
+		// Let git draw its own transfer progress, except in CI logs
+		Progress: opts.streams.ProgressEnabled(),
+		Stdout:   opts.streams.Out,
+		Stderr:   opts.streams.ErrOut,
+	})
+	if err != nil {
+		return err
 	}
 
 	// Print success message with cd hint
 	fmt.Fprintln(opts.streams.Out)
 	opts.streams.Success("Cloned repository to %s/", destDir)
 
-	// Get absolute path for cd hint
+	// Get absolute path for cd hint; a bare repository has no files to
+	// work on
 	absPath, err := filepath.Abs(destDir)
-	if err == nil {
+	if err == nil && !opts.bare {
 		fmt.Fprintf(opts.streams.Out, "\nTo get started, run:\n  cd %s\n", absPath)
 	}
 
@@ -0,0 +1,246 @@
+package repo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+const (
+	// defaultCloneParallel is how many repositories --all clones at once
+	// unless --parallel says otherwise
+	defaultCloneParallel = 4
+
+	// partialCloneSuffix is added to the directory a repository is cloned
+	// into until the clone completes, so an interrupted clone is started
+	// over rather than taken for a finished one
+	partialCloneSuffix = ".bb-partial"
+)
+
+// cloneTarget is a repository cloned by --all, and where it goes
+type cloneTarget struct {
+	Repo string
+	URL  string
+	Dest string
+	// Exists is set when Dest is already there, from an earlier run
+	Exists bool
+}
+
+// listCloneRepos lists the repositories of workspace, or of one of its
+// projects, sorted by slug
+func listCloneRepos(ctx context.Context, client *api.Client, workspace, project string) ([]api.RepositoryFull, error) {
+	var query api.Query
+	query.Eq("project.key", project)
+
+	var repos []api.RepositoryFull
+	for page := 1; ; page++ {
+		result, err := client.ListRepositories(ctx, workspace, &api.RepositoryListOptions{
+			Query: query.String(),
+			Sort:  "slug",
+			Page:  page,
+			Limit: 100,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories: %w", err)
+		}
+		repos = append(repos, result.Values...)
+		if result.Next == "" || len(result.Values) == 0 {
+			return repos, nil
+		}
+	}
+}
+
+// planClones decides where each of repos is cloned: root/PROJECT/slug, or
+// root/slug for a repository outside any project, with ".git" added for
+// bare clones
+func planClones(repos []api.RepositoryFull, root, protocol string, bare bool) []cloneTarget {
+	targets := make([]cloneTarget, 0, len(repos))
+	for _, r := range repos {
+		dest := root
+		if r.Project != nil && r.Project.Key != "" {
+			dest = filepath.Join(dest, r.Project.Key)
+		}
+		dest = filepath.Join(dest, r.Slug)
+		if bare {
+			dest += ".git"
+		}
+
+		_, err := os.Stat(dest)
+		targets = append(targets, cloneTarget{
+			Repo:   r.FullName,
+			URL:    cmdutil.CloneURL(r.Links, protocol),
+			Dest:   dest,
+			Exists: err == nil,
+		})
+	}
+	return targets
+}
+
+// runCloneAll clones every repository of a workspace, or of one of its
+// projects, into a directory tree. Repositories already cloned are skipped,
+// so an interrupted run can be resumed by running it again.
+func runCloneAll(ctx context.Context, opts *cloneOptions) error {
+	workspace, err := cmdutil.ParseWorkspace(opts.workspace)
+	if err != nil {
+		return err
+	}
+	protocol, err := cmdutil.GitProtocol(opts.protocol)
+	if err != nil {
+		return err
+	}
+	root := opts.directory
+	if root == "" {
+		root = workspace
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	progress := opts.streams.StartProgress("Fetching repositories")
+	repos, err := listCloneRepos(ctx, client, workspace, opts.project)
+	progress.Stop()
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		if opts.project != "" {
+			opts.streams.Info("No repositories found in project %s of %s", opts.project, workspace)
+		} else {
+			opts.streams.Info("No repositories found in workspace %s", workspace)
+		}
+		return nil
+	}
+
+	targets := planClones(repos, root, protocol, opts.bare)
+	if opts.dryRun {
+		return printClonePlan(opts.streams, targets)
+	}
+	return cloneTargets(ctx, opts, targets)
+}
+
+// printClonePlan lists the repositories --all would clone and where
+func printClonePlan(streams *iostreams.IOStreams, targets []cloneTarget) error {
+	tp := cmdutil.NewTablePrinter(streams)
+	tp.AddHeader("REPOSITORY", "DIRECTORY", "STATUS")
+	for _, t := range targets {
+		status := "clone"
+		if t.Exists {
+			status = streams.Style(iostreams.RoleMuted, "exists")
+		}
+		tp.AddRow(t.Repo, t.Dest, status)
+	}
+	return tp.Render()
+}
+
+// cloneTargets clones targets that don't exist yet, opts.parallel at a
+// time, reporting each as it finishes
+func cloneTargets(ctx context.Context, opts *cloneOptions, targets []cloneTarget) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	var (
+		wg                      sync.WaitGroup
+		mu                      sync.Mutex
+		limiter                 = make(chan struct{}, max(opts.parallel, 1))
+		cloned, skipped, failed int
+	)
+	for _, t := range targets {
+		if t.Exists {
+			skipped++
+			continue
+		}
+		wg.Go(func() {
+			limiter <- struct{}{}
+			defer func() { <-limiter }()
+			if ctx.Err() != nil {
+				return
+			}
+
+			err := cloneInto(ctx, opts, t)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case errors.Is(err, context.Canceled):
+			case err != nil:
+				failed++
+				opts.streams.Error("%s: %v", t.Repo, err)
+			default:
+				cloned++
+				opts.streams.Success("Cloned %s into %s", t.Repo, t.Dest)
+			}
+		})
+	}
+	wg.Wait()
+
+	summary := fmt.Sprintf("Cloned %d of %d repositories", cloned, len(targets))
+	if skipped > 0 {
+		summary += fmt.Sprintf(", %d already cloned", skipped)
+	}
+	switch {
+	case ctx.Err() != nil:
+		return fmt.Errorf("interrupted after cloning %d of %d repositories; run the command again to resume", cloned, len(targets))
+	case failed > 0:
+		return fmt.Errorf("failed to clone %d of %d repositories", failed, len(targets))
+	}
+	opts.streams.Info("%s", summary)
+	return nil
+}
+
+// cloneInto clones t into a partial directory, and renames it to t.Dest
+// once the clone is complete. A partial directory left by an earlier,
+// interrupted run is removed first.
+func cloneInto(ctx context.Context, opts *cloneOptions, t cloneTarget) error {
+	if t.URL == "" {
+		return fmt.Errorf("no clone URL found for repository")
+	}
+	partial := t.Dest + partialCloneSuffix
+	if err := os.RemoveAll(partial); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(t.Dest), 0o755); err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	err := git.CloneWithOptions(t.URL, partial, git.CloneOptions{
+		Context:      ctx,
+		Depth:        opts.depth,
+		Branch:       opts.branch,
+		SingleBranch: opts.singleBranch,
+		Bare:         opts.bare,
+		Stderr:       &stderr,
+	})
+	if err != nil {
+		os.RemoveAll(partial)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s", lastLine(msg))
+		}
+		return err
+	}
+	return os.Rename(partial, t.Dest)
+}
+
+// lastLine returns the last line of s, where git puts the reason a clone
+// failed
+func lastLine(s string) string {
+	if i := strings.LastIndex(s, "\n"); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
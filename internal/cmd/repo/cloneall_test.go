@@ -0,0 +1,112 @@
+package repo
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+func TestPlanClones(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "API", "billing"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	links := api.RepositoryLinks{Clone: []api.CloneLink{
+		{Name: "https", Href: "https://bitbucket.org/team/x.git"},
+		{Name: "ssh", Href: "git@bitbucket.org:team/x.git"},
+	}}
+	repos := []api.RepositoryFull{
+		{FullName: "team/billing", Slug: "billing", Project: &api.Project{Key: "API"}, Links: links},
+		{FullName: "team/web", Slug: "web", Project: &api.Project{Key: "FE"}, Links: links},
+		{FullName: "team/loose", Slug: "loose", Links: links},
+	}
+
+	targets := planClones(repos, root, "ssh", false)
+	want := []cloneTarget{
+		{Repo: "team/billing", URL: "git@bitbucket.org:team/x.git", Dest: filepath.Join(root, "API", "billing"), Exists: true},
+		{Repo: "team/web", URL: "git@bitbucket.org:team/x.git", Dest: filepath.Join(root, "FE", "web")},
+		{Repo: "team/loose", URL: "git@bitbucket.org:team/x.git", Dest: filepath.Join(root, "loose")},
+	}
+	if len(targets) != len(want) {
+		t.Fatalf("got %d targets, want %d", len(targets), len(want))
+	}
+	for i := range want {
+		if targets[i] != want[i] {
+			t.Errorf("target %d = %+v, want %+v", i, targets[i], want[i])
+		}
+	}
+
+	bare := planClones(repos[1:2], root, "https", true)
+	if got := bare[0].Dest; got != filepath.Join(root, "FE", "web.git") {
+		t.Errorf("bare destination = %q", got)
+	}
+}
+
+func TestCloneTargets(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+
+	// A local repository stands in for the remote one
+	dir := t.TempDir()
+	source := filepath.Join(dir, "source")
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main", source},
+		{"-C", source, "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "Initial commit"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	root := filepath.Join(dir, "clones")
+	done := filepath.Join(root, "P", "done")
+	fresh := filepath.Join(root, "P", "fresh")
+	if err := os.MkdirAll(done, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// Left behind by an interrupted run
+	if err := os.MkdirAll(fresh+partialCloneSuffix, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	opts := &cloneOptions{streams: &iostreams.IOStreams{Out: &out, ErrOut: &out}, parallel: 2}
+	err := cloneTargets(context.Background(), opts, []cloneTarget{
+		{Repo: "team/done", URL: source, Dest: done, Exists: true},
+		{Repo: "team/fresh", URL: source, Dest: fresh},
+	})
+	if err != nil {
+		t.Fatalf("cloneTargets() error = %v\n%s", err, out.String())
+	}
+
+	if _, err := os.Stat(filepath.Join(fresh, ".git")); err != nil {
+		t.Errorf("expected %s to be cloned: %v", fresh, err)
+	}
+	if _, err := os.Stat(fresh + partialCloneSuffix); !os.IsNotExist(err) {
+		t.Errorf("expected the partial clone to be gone, got %v", err)
+	}
+	if !strings.Contains(out.String(), "Cloned 1 of 2 repositories, 1 already cloned") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+
+	out.Reset()
+	err = cloneTargets(context.Background(), opts, []cloneTarget{
+		{Repo: "team/missing", URL: filepath.Join(dir, "missing"), Dest: filepath.Join(root, "P", "missing")},
+	})
+	if err == nil || err.Error() != "failed to clone 1 of 1 repositories" {
+		t.Errorf("cloneTargets() error = %v, want a failure", err)
+	}
+	if !strings.Contains(out.String(), "team/missing: ") {
+		t.Errorf("expected the failure to be reported, got:\n%s", out.String())
+	}
+}
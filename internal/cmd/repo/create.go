@@ -28,6 +28,7 @@ type createOptions struct {
 	project     string
 	clone       bool
 	gitignore   string
+	copy        bool
 }
 
 // NewCmdCreate creates the repo create command
@@ -97,6 +98,7 @@ a public repository instead.`,
 	cmd.Flags().StringVarP(&opts.project, "project", "p", "", "Project key to assign repository to")
 	cmd.Flags().BoolVarP(&opts.clone, "clone", "c", false, "Clone the repository after creation")
 	cmd.Flags().StringVar(&opts.gitignore, "gitignore", "", "Initialize with gitignore template")
+	cmdutil.AddCopyFlag(cmd, &opts.copy, "created repository")
 
 	return cmd
 }
@@ -164,18 +166,32 @@ func runCreate(opts *createOptions) error {
 
 	// Success message
 	opts.streams.Success("Created repository %s", repo.FullName)
-	fmt.Fprintln(opts.streams.Out)
 
 	// Get preferred protocol for clone URL
-	protocol := getPreferredProtocol()
-	cloneURL := getCloneURL(repo.Links, protocol)
-	fmt.Fprintf(opts.streams.Out, "Clone URL: %s\n", cloneURL)
+	protocol, _ := cmdutil.GitProtocol("")
+	cloneURL := cmdutil.CloneURL(repo.Links, protocol)
+	if opts.streams.IsQuiet() {
+		fmt.Fprintln(opts.streams.Out, cloneURL)
+	} else {
+		fmt.Fprintln(opts.streams.Out)
+		fmt.Fprintf(opts.streams.Out, "Clone URL: %s\n", cloneURL)
+	}
+
+	if opts.copy {
+		if err := cmdutil.CopyURL(opts.streams, repo.Links.HTML.Href); err != nil {
+			opts.streams.Warning("%s", err)
+		}
+	}
 
 	// Clone if requested
 	if opts.clone {
-		fmt.Fprintln(opts.streams.Out)
+		if !opts.streams.IsQuiet() {
+			fmt.Fprintln(opts.streams.Out)
+		}
 		opts.streams.Info("Cloning repository...")
 
+		// git may ask for credentials on the terminal, so no spinner
+		// runs over it
 		if err := git.Clone(cloneURL, opts.name); err != nil {
 			return fmt.Errorf("failed to clone repository: %w", err)
 		}
@@ -192,8 +208,7 @@ func getDefaultWorkspace(ctx context.Context, client *api.Client, streams *iostr
 	hosts, err := config.LoadHostsConfig()
 	if err == nil {
 		// Get active user's workspace (often same as username)
-		user := hosts.GetActiveUser(config.DefaultHost)
-		if user != "" {
+		if _, user, err := config.ActiveAccount(hosts); err == nil && user != "" {
 			// Try to use username as workspace (common pattern)
 			return user, nil
 		}
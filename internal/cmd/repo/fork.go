@@ -3,11 +3,11 @@ package repo
 import (
 	"context"
 	"fmt"
-	"os/exec"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/rbansal42/bitbucket-cli/internal/api"
 	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
 	"github.com/rbansal42/bitbucket-cli/internal/config"
 	"github.com/rbansal42/bitbucket-cli/internal/git"
@@ -21,6 +21,7 @@ type forkOptions struct {
 	name       string
 	clone      bool
 	remoteName string
+	protocol   string
 }
 
 // NewCmdFork creates the repo fork command
@@ -58,6 +59,9 @@ as a new remote (default name: "fork").`,
   # Fork and clone the result
   bb repo fork myworkspace/repo --clone
 
+  # Fork and clone over HTTPS
+  bb repo fork myworkspace/repo --clone --protocol https
+
   # Fork and add as remote with custom name
   bb repo fork --remote-name upstream`,
 		Args: cobra.MaximumNArgs(1),
@@ -74,11 +78,17 @@ as a new remote (default name: "fork").`,
 	cmd.Flags().StringVar(&opts.name, "name", "", "Name for the forked repository (default: same as original)")
 	cmd.Flags().BoolVarP(&opts.clone, "clone", "c", false, "Clone the fork after creation")
 	cmd.Flags().StringVar(&opts.remoteName, "remote-name", "fork", "Name for the new remote when in an existing clone")
+	cmdutil.AddProtocolFlag(cmd, &opts.protocol)
 
 	return cmd
 }
 
 func runFork(opts *forkOptions) error {
+	protocol, err := cmdutil.GitProtocol(opts.protocol)
+	if err != nil {
+		return err
+	}
+
 	// Get authenticated client
 	client, err := cmdutil.GetAPIClient()
 	if err != nil {
@@ -131,18 +141,23 @@ func runFork(opts *forkOptions) error {
 
 	// Success message
 	opts.streams.Success("Forked %s/%s to %s", workspace, repoSlug, fork.FullName)
-	fmt.Fprintln(opts.streams.Out)
+	if !opts.streams.IsQuiet() {
+		fmt.Fprintln(opts.streams.Out)
+	}
 	fmt.Fprintf(opts.streams.Out, "%s\n", fork.Links.HTML.Href)
 
 	// Handle post-fork actions
 	if opts.clone {
 		// Clone the fork
-		fmt.Fprintln(opts.streams.Out)
+		if !opts.streams.IsQuiet() {
+			fmt.Fprintln(opts.streams.Out)
+		}
 		opts.streams.Info("Cloning fork...")
 
-		protocol := getPreferredProtocol()
-		cloneURL := getCloneURL(fork.Links, protocol)
+		cloneURL := cmdutil.CloneURL(fork.Links, protocol)
 
+		// git may ask for credentials on the terminal, so no spinner
+		// runs over it
 		if err := git.Clone(cloneURL, forkName); err != nil {
 			return fmt.Errorf("failed to clone fork: %w", err)
 		}
@@ -150,7 +165,7 @@ func runFork(opts *forkOptions) error {
 		opts.streams.Success("Cloned to %s/", forkName)
 
 		// Optionally add the original repo as upstream remote
-		if err := addUpstreamRemote(forkName, workspace, repoSlug); err != nil {
+		if err := addUpstreamRemote(ctx, client, forkName, workspace, repoSlug, protocol); err != nil {
 			opts.streams.Warning("Could not add upstream remote: %v", err)
 		} else {
 			opts.streams.Success("Added upstream remote for %s/%s", workspace, repoSlug)
@@ -158,13 +173,14 @@ func runFork(opts *forkOptions) error {
 
 	} else if inExistingRepo && opts.remoteName != "" {
 		// Add the fork as a new remote in the existing repo
-		protocol := getPreferredProtocol()
-		cloneURL := getCloneURL(fork.Links, protocol)
+		cloneURL := cmdutil.CloneURL(fork.Links, protocol)
 
-		fmt.Fprintln(opts.streams.Out)
+		if !opts.streams.IsQuiet() {
+			fmt.Fprintln(opts.streams.Out)
+		}
 		opts.streams.Info("Adding fork as remote '%s'...", opts.remoteName)
 
-		if err := addRemote(opts.remoteName, cloneURL); err != nil {
+		if err := git.AddRemote("", opts.remoteName, cloneURL); err != nil {
 			// Remote addition failure shouldn't fail the entire fork operation
 			opts.streams.Warning("Could not add remote '%s': %v", opts.remoteName, err)
 			opts.streams.Info("You can add the remote manually with: git remote add %s %s", opts.remoteName, cloneURL)
@@ -177,21 +193,15 @@ func runFork(opts *forkOptions) error {
 }
 
 // addUpstreamRemote adds the original repository as an "upstream" remote
-func addUpstreamRemote(repoDir, workspace, repoSlug string) error {
-	protocol := getPreferredProtocol()
-	var upstreamURL string
-	if protocol == "ssh" {
-		upstreamURL = fmt.Sprintf("git@bitbucket.org:%s/%s.git", workspace, repoSlug)
-	} else {
-		upstreamURL = fmt.Sprintf("https://bitbucket.org/%s/%s.git", workspace, repoSlug)
+func addUpstreamRemote(ctx context.Context, client *api.Client, repoDir, workspace, repoSlug, protocol string) error {
+	repo, err := client.GetRepository(ctx, workspace, repoSlug)
+	if err != nil {
+		return err
+	}
+	upstreamURL := cmdutil.CloneURL(repo.Links, protocol)
+	if upstreamURL == "" {
+		return fmt.Errorf("no clone URL found for %s/%s", workspace, repoSlug)
 	}
 
-	cmd := exec.Command("git", "-C", repoDir, "remote", "add", "upstream", upstreamURL)
-	return cmd.Run()
-}
-
-// addRemote adds a new remote to the current repository
-func addRemote(name, url string) error {
-	cmd := exec.Command("git", "remote", "add", name, url)
-	return cmd.Run()
+	return git.AddRemote(repoDir, "upstream", upstreamURL)
 }
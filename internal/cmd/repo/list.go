@@ -3,7 +3,6 @@ package repo
 import (
 	"context"
 	"fmt"
-	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
@@ -19,6 +18,7 @@ type ListOptions struct {
 	Limit     int
 	Sort      string
 	JSON      bool
+	Format    string
 	Streams   *iostreams.IOStreams
 }
 
@@ -45,7 +45,10 @@ By default, repositories are sorted by last updated time.`,
   bb repo list -w myworkspace --sort name
 
   # Output as JSON
-  bb repo list -w myworkspace --json`,
+  bb repo list -w myworkspace --json
+
+  # Output as YAML
+  bb repo list -w myworkspace --format yaml`,
 		Aliases: []string{"ls"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if opts.Workspace == "" {
@@ -65,6 +68,7 @@ By default, repositories are sorted by last updated time.`,
 	cmd.Flags().IntVarP(&opts.Limit, "limit", "l", 30, "Maximum number of repositories to list")
 	cmd.Flags().StringVarP(&opts.Sort, "sort", "s", "-updated_on", "Sort field (name, -updated_on)")
 	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
 
 	return cmd
 }
@@ -83,7 +87,9 @@ func runList(ctx context.Context, opts *ListOptions) error {
 	}
 
 	// Fetch repositories
+	progress := opts.Streams.StartProgress("Fetching repositories")
 	result, err := client.ListRepositories(ctx, opts.Workspace, listOpts)
+	progress.Stop()
 	if err != nil {
 		return fmt.Errorf("failed to list repositories: %w", err)
 	}
@@ -93,16 +99,21 @@ func runList(ctx context.Context, opts *ListOptions) error {
 		return nil
 	}
 
+	if err := opts.Streams.StartPager(); err != nil {
+		opts.Streams.Warning("%s", err)
+	}
+	defer opts.Streams.StopPager()
+
 	// Output results
-	if opts.JSON {
-		return outputListJSON(opts.Streams, result.Values)
+	if opts.JSON || opts.Format != "" {
+		return outputListStructured(opts.Streams, opts.Format, result.Values)
 	}
 
 	return outputTable(opts.Streams, result.Values)
 }
 
-func outputListJSON(streams *iostreams.IOStreams, repos []api.RepositoryFull) error {
-	// Create simplified JSON output
+func outputListStructured(streams *iostreams.IOStreams, format string, repos []api.RepositoryFull) error {
+	// Create simplified output
 	output := make([]map[string]interface{}, len(repos))
 	for i, repo := range repos {
 		output[i] = map[string]interface{}{
@@ -117,39 +128,38 @@ func outputListJSON(streams *iostreams.IOStreams, repos []api.RepositoryFull) er
 		}
 	}
 
-	return cmdutil.PrintJSON(streams, output)
+	return cmdutil.PrintFormatted(streams, format, output)
 }
 
 func outputTable(streams *iostreams.IOStreams, repos []api.RepositoryFull) error {
-	w := tabwriter.NewWriter(streams.Out, 0, 0, 2, ' ', 0)
+	table := cmdutil.NewTablePrinter(streams)
 
 	// Print header
-	header := "NAME\tDESCRIPTION\tVISIBILITY\tUPDATED"
-	cmdutil.PrintTableHeader(streams, w, header)
+	table.AddHeader("NAME", "DESCRIPTION", "VISIBILITY", "UPDATED")
 
 	// Print rows
 	for _, repo := range repos {
-		name := cmdutil.TruncateString(repo.FullName, 40)
-		desc := cmdutil.TruncateString(repo.Description, 40)
+		name := repo.FullName
+		desc := repo.Description
 		visibility := formatVisibility(streams, repo.IsPrivate)
-		updated := cmdutil.TimeAgo(repo.UpdatedOn)
+		updated := cmdutil.FormatTime(streams, repo.UpdatedOn)
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, desc, visibility, updated)
+		table.AddRow(name, desc, visibility, updated)
 	}
 
-	return w.Flush()
+	return table.Render()
 }
 
 func formatVisibility(streams *iostreams.IOStreams, isPrivate bool) string {
 	if isPrivate {
 		if streams.ColorEnabled() {
-			return iostreams.Yellow + "private" + iostreams.Reset
+			return streams.Style(iostreams.RoleWarning, "private")
 		}
 		return "private"
 	}
 
 	if streams.ColorEnabled() {
-		return iostreams.Green + "public" + iostreams.Reset
+		return streams.Style(iostreams.RoleSuccess, "public")
 	}
 	return "public"
 }
@@ -0,0 +1,194 @@
+package repo
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+type moveOptions struct {
+	streams *iostreams.IOStreams
+	repos   []string
+	project string
+	json    bool
+}
+
+// moveResult is what happened to one repository moved by repo move
+type moveResult struct {
+	Repository string `json:"repository"`
+	From       string `json:"from,omitempty"`
+	To         string `json:"to"`
+	// Status is moved, unchanged when the repository was already in the
+	// project, or failed
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// NewCmdMove creates the move command
+func NewCmdMove(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &moveOptions{
+		streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "move [<workspace/repo>...] --project <key>",
+		Short: "Move repositories to another project",
+		Long: `Move one or more repositories into a project of their workspace.
+
+Repositories are given as arguments, or read from standard input, one per
+line, when it isn't a terminal; blank lines and lines starting with # are
+ignored. With neither, the current repository is moved.
+
+Each repository is moved on its own: a repository that can't be moved is
+reported and the rest are still moved. The command fails if any of them
+couldn't be.`,
+		Example: `  # Move a repository into the PLAT project
+  bb repo move myworkspace/api --project PLAT
+
+  # Move several repositories
+  bb repo move myworkspace/api myworkspace/web --project PLAT
+
+  # Move every repository listed in a file
+  bb repo move --project PLAT < repos.txt
+
+  # Move the workspace's repositories listed by another command
+  bb repo list -w myworkspace --json | jq -r '.[].full_name' | grep -- -svc | bb repo move --project SVC`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.repos = args
+			if len(opts.repos) == 0 && !opts.streams.IsStdinTTY() {
+				repos, err := readRepoList(opts.streams.In)
+				if err != nil {
+					return err
+				}
+				if len(repos) == 0 {
+					return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("no repositories given on standard input"))
+				}
+				opts.repos = repos
+			}
+			return runMove(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.project, "project", "p", "", "Key of the project to move the repositories into (required)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output the results in JSON format")
+	_ = cmd.MarkFlagRequired("project")
+
+	return cmd
+}
+
+// readRepoList reads repositories given one per line, skipping blank lines
+// and # comments
+func readRepoList(r io.Reader) ([]string, error) {
+	var repos []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		repos = append(repos, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read repositories from stdin: %w", err)
+	}
+	return repos, nil
+}
+
+func runMove(ctx context.Context, opts *moveOptions) error {
+	if len(opts.repos) == 0 {
+		// The current repository
+		opts.repos = []string{""}
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	results := make([]moveResult, 0, len(opts.repos))
+	moved, failed := 0, 0
+	for _, arg := range opts.repos {
+		result := moveRepository(ctx, client, arg, opts.project)
+		results = append(results, result)
+		switch result.Status {
+		case "moved":
+			moved++
+		case "failed":
+			failed++
+		}
+		if opts.json {
+			continue
+		}
+		switch result.Status {
+		case "moved":
+			opts.streams.Success("Moved %s from %s to %s", result.Repository, projectOrNone(result.From), result.To)
+		case "unchanged":
+			opts.streams.Info("%s is already in %s", result.Repository, result.To)
+		default:
+			opts.streams.Error("%s: %s", result.Repository, result.Error)
+		}
+	}
+
+	if opts.json {
+		if err := cmdutil.PrintJSON(opts.streams, results); err != nil {
+			return err
+		}
+	} else if len(results) > 1 {
+		opts.streams.Info("Moved %d of %d repositories to %s", moved, len(results), opts.project)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to move %d of %d repositories", failed, len(results))
+	}
+	return nil
+}
+
+// moveRepository moves the repository arg, as WORKSPACE/REPO or "" for the
+// current one, into the project with key project
+func moveRepository(ctx context.Context, client *api.Client, arg, project string) moveResult {
+	result := moveResult{Repository: arg, To: project, Status: "failed"}
+
+	workspace, repoSlug, err := cmdutil.ParseRepository(arg)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Repository = workspace + "/" + repoSlug
+
+	repo, err := client.GetRepository(ctx, workspace, repoSlug)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to get repository: %v", err)
+		return result
+	}
+	if repo.Project != nil {
+		result.From = repo.Project.Key
+	}
+	if strings.EqualFold(result.From, project) {
+		result.Status = "unchanged"
+		return result
+	}
+
+	if _, err := client.UpdateRepository(ctx, workspace, repoSlug, &api.RepositoryUpdateOptions{ProjectKey: project}); err != nil {
+		result.Error = fmt.Sprintf("failed to move repository: %v", err)
+		return result
+	}
+	result.Status = "moved"
+	return result
+}
+
+// projectOrNone names a project key for messages, which may be empty for
+// a repository outside any project
+func projectOrNone(key string) string {
+	if key == "" {
+		return "no project"
+	}
+	return key
+}
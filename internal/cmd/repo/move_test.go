@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+func TestReadRepoList(t *testing.T) {
+	input := "myworkspace/api\n\n  # legacy services\n  myworkspace/web  \n"
+	repos, err := readRepoList(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("readRepoList() error = %v", err)
+	}
+	if want := []string{"myworkspace/api", "myworkspace/web"}; !slices.Equal(repos, want) {
+		t.Errorf("readRepoList() = %q, want %q", repos, want)
+	}
+}
+
+func TestMoveRepository(t *testing.T) {
+	projects := map[string]string{"api": "OLD", "web": "NEW"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slug := strings.TrimPrefix(r.URL.Path, "/repositories/myworkspace/")
+		key, ok := projects[slug]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"message": "Repository not found"}}`))
+			return
+		}
+		if r.Method == http.MethodPut {
+			var body struct {
+				Project struct {
+					Key string `json:"key"`
+				} `json:"project"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			key = body.Project.Key
+			projects[slug] = key
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"project": {"key": "` + key + `"}}`))
+	}))
+	defer server.Close()
+	client := api.NewClient(api.WithBaseURL(server.URL))
+
+	tests := []struct {
+		repo       string
+		wantStatus string
+		wantFrom   string
+	}{
+		{"myworkspace/api", "moved", "OLD"},
+		{"myworkspace/web", "unchanged", "NEW"},
+		{"myworkspace/gone", "failed", ""},
+		{"not-a-repo", "failed", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			got := moveRepository(context.Background(), client, tt.repo, "NEW")
+			if got.Status != tt.wantStatus || got.From != tt.wantFrom || got.To != "NEW" {
+				t.Errorf("moveRepository() = %+v, want status %s from %q", got, tt.wantStatus, tt.wantFrom)
+			}
+			if (got.Status == "failed") != (got.Error != "") {
+				t.Errorf("moveRepository() error %q doesn't match status %s", got.Error, got.Status)
+			}
+		})
+	}
+	if projects["api"] != "NEW" {
+		t.Errorf("api is in project %s, want NEW", projects["api"])
+	}
+}
@@ -37,6 +37,11 @@ history. You can discuss and manage your project's work within the repository.`,
 	cmd.AddCommand(NewCmdDelete(streams))
 	cmd.AddCommand(NewCmdSync(streams))
 	cmd.AddCommand(NewCmdSetDefault(streams))
+	cmd.AddCommand(NewCmdArchive(streams))
+	cmd.AddCommand(NewCmdMove(streams))
+	cmd.AddCommand(NewCmdTransfer(streams))
+	cmd.AddCommand(NewCmdAccess(streams))
+	cmd.AddCommand(NewCmdStats(streams))
 
 	return cmd
 }
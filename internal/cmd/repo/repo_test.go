@@ -1,6 +1,8 @@
 package repo
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/rbansal42/bitbucket-cli/internal/api"
@@ -180,9 +182,9 @@ func TestGetCloneURL(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := getCloneURL(tt.links, tt.protocol)
+			got := cmdutil.CloneURL(tt.links, tt.protocol)
 			if got != tt.want {
-				t.Errorf("getCloneURL() = %q, want %q", got, tt.want)
+				t.Errorf("CloneURL() = %q, want %q", got, tt.want)
 			}
 		})
 	}
@@ -345,9 +347,12 @@ func TestNewCmdRepo(t *testing.T) {
 	}
 }
 
-// Test getPreferredProtocol returns a valid protocol
+// Test GitProtocol returns a valid protocol
 func TestGetPreferredProtocol(t *testing.T) {
-	protocol := getPreferredProtocol()
+	protocol, err := cmdutil.GitProtocol("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
 	// Should return either "https" or "ssh"
 	if protocol != "https" && protocol != "ssh" {
@@ -355,6 +360,39 @@ func TestGetPreferredProtocol(t *testing.T) {
 	}
 }
 
+// Test GitProtocol prefers --protocol, then the host, then config.yml
+func TestGitProtocol_HostOverridesConfig(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("BB_CONFIG_DIR", dir)
+	t.Setenv("BB_GIT_PROTOCOL", "")
+	t.Setenv("BB_HOST", "")
+	t.Setenv("BB_PROFILE", "")
+	t.Chdir(dir)
+
+	writeFile := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("config.yml", "git_protocol: https\n")
+	writeFile("hosts.yml", "bitbucket.org:\n  user: alice\n  git_protocol: ssh\n")
+
+	if got, err := cmdutil.GitProtocol(""); err != nil || got != "ssh" {
+		t.Errorf("GitProtocol(\"\") = %q, %v; want the host's ssh", got, err)
+	}
+	if got, err := cmdutil.GitProtocol("https"); err != nil || got != "https" {
+		t.Errorf("GitProtocol(\"https\") = %q, %v; want https", got, err)
+	}
+	if _, err := cmdutil.GitProtocol("ftp"); err == nil {
+		t.Error("expected an error for an invalid --protocol")
+	}
+
+	writeFile("hosts.yml", "bitbucket.org:\n  user: alice\n")
+	if got, err := cmdutil.GitProtocol(""); err != nil || got != "https" {
+		t.Errorf("GitProtocol(\"\") = %q, %v; want config.yml's https", got, err)
+	}
+}
+
 // Test that RepositoryLinks type is properly accessible
 func TestRepositoryLinksType(t *testing.T) {
 	links := api.RepositoryLinks{
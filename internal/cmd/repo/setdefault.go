@@ -6,27 +6,21 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
-	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 
 	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
 	"github.com/rbansal42/bitbucket-cli/internal/git"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
 
-// LocalConfig represents the .bb.yml file structure
-type LocalConfig struct {
-	DefaultRepo string `yaml:"default_repo,omitempty"`
-}
-
 // SetDefaultOptions holds the options for the set-default command
 type SetDefaultOptions struct {
 	RepoArg string
+	Remote  string
 	View    bool
 	Unset   bool
 	Streams *iostreams.IOStreams
@@ -43,17 +37,25 @@ func NewCmdSetDefault(streams *iostreams.IOStreams) *cobra.Command {
 		Short: "Set the default repository for the current directory",
 		Long: `Set the default repository for the current directory.
 
-The default repository is stored in a .bb.yml file in the current directory,
-or in git config (bb.repo) if inside a git repository.
+The default repository is pinned in your config to the root of the current
+git repository, or to the current directory if you are not inside one. Commands
+run in that directory or below it operate on the pinned repository when
+--repo and BB_REPO are not given, even outside a git checkout.
 
-This default is used when no repository is specified for commands that
-require a repository context.`,
+In a clone with several Bitbucket remotes, such as origin and upstream in a
+fork setup, commands use origin, then upstream, then the first remote by
+name. Use --remote to choose the remote for this clone instead; it is
+recorded in the bb.remote git config key. The global --remote flag and
+BB_REMOTE choose it for a single command.`,
 		Example: `  # Set default repository
   bb repo set-default myworkspace/myrepo
 
   # Detect from git remote and set as default
   bb repo set-default
 
+  # Use the upstream remote in this clone
+  bb repo set-default --remote upstream
+
   # View current default repository
   bb repo set-default --view
 
@@ -68,6 +70,7 @@ require a repository context.`,
 		},
 	}
 
+	cmd.Flags().StringVar(&opts.Remote, "remote", "", "Use this git remote of the current clone")
 	cmd.Flags().BoolVar(&opts.View, "view", false, "Show the current default repository")
 	cmd.Flags().BoolVar(&opts.Unset, "unset", false, "Remove the default repository")
 
@@ -90,6 +93,13 @@ func runSetDefault(ctx context.Context, opts *SetDefaultOptions) error {
 		return unsetDefault(opts)
 	}
 
+	if opts.Remote != "" {
+		if opts.RepoArg != "" {
+			return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("cannot specify both a repository and --remote"))
+		}
+		return setDefaultRemote(opts)
+	}
+
 	// Determine the repository to set
 	var workspace, repoSlug string
 	var err error
@@ -139,170 +149,107 @@ func runSetDefault(ctx context.Context, opts *SetDefaultOptions) error {
 	}
 
 	// Store the default
-	if err := storeDefault(fullRepo); err != nil {
+	dir, err := storeDefault(fullRepo)
+	if err != nil {
 		return err
 	}
 
-	opts.Streams.Success("Set default repository to %s", fullRepo)
+	opts.Streams.Success("Set default repository to %s for %s", fullRepo, dir)
 	return nil
 }
 
-func viewDefault(opts *SetDefaultOptions) error {
-	repo, source, err := getDefault()
-	if err != nil {
-		return err
+// setDefaultRemote records the remote commands use in the current clone
+func setDefaultRemote(opts *SetDefaultOptions) error {
+	if !git.IsGitRepository() {
+		return fmt.Errorf("--remote needs to be run inside a git repository")
 	}
 
-	if repo == "" {
-		opts.Streams.Info("No default repository set")
-		return nil
+	remotes, err := git.GetRemotes()
+	if err != nil {
+		return err
 	}
-
-	opts.Streams.Info("Default repository: %s (from %s)", repo, source)
-	return nil
-}
-
-func unsetDefault(opts *SetDefaultOptions) error {
-	// Check if we're in a git repo
-	if git.IsGitRepository() {
-		// Try to unset from git config
-		if err := unsetGitConfig(); err != nil {
-			// Git config might not have been set, try .bb.yml
-			if err := removeLocalConfig(); err != nil {
-				opts.Streams.Info("No default repository was set")
-				return nil
-			}
+	for _, r := range remotes {
+		if r.Name != opts.Remote {
+			continue
 		}
-	} else {
-		// Not in git repo, try .bb.yml
-		if err := removeLocalConfig(); err != nil {
-			opts.Streams.Info("No default repository was set")
-			return nil
+		if r.Workspace == "" || r.RepoSlug == "" {
+			return fmt.Errorf("remote %q does not point at a Bitbucket repository", opts.Remote)
 		}
-	}
-
-	opts.Streams.Success("Removed default repository")
-	return nil
-}
-
-func storeDefault(repo string) error {
-	// If in git repository, use git config
-	if git.IsGitRepository() {
-		return setGitConfig(repo)
-	}
-
-	// Otherwise, use .bb.yml
-	return setLocalConfig(repo)
-}
-
-func getDefault() (repo string, source string, err error) {
-	// First, check git config if in a git repo
-	if git.IsGitRepository() {
-		repo, err = getGitConfig()
-		if err == nil && repo != "" {
-			return repo, "git config", nil
+		if err := git.SetSelectedRemote(r.Name); err != nil {
+			return err
 		}
+		opts.Streams.Success("Set default remote to %s (%s/%s)", r.Name, r.Workspace, r.RepoSlug)
+		return nil
 	}
-
-	// Then, check .bb.yml in current directory
-	repo, err = getLocalConfig()
-	if err == nil && repo != "" {
-		return repo, ".bb.yml", nil
-	}
-
-	return "", "", nil
+	return fmt.Errorf("no remote named %q", opts.Remote)
 }
 
-func setGitConfig(repo string) error {
-	cmd := execCommand("git", "config", "--local", "bb.repo", repo)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to set git config: %w", err)
+func viewDefault(opts *SetDefaultOptions) error {
+	if remote, source := git.SelectedRemote(); remote != "" {
+		opts.Streams.Info("Default remote: %s (from %s)", remote, source)
 	}
-	return nil
-}
 
-func getGitConfig() (string, error) {
-	cmd := execCommand("git", "config", "--local", "--get", "bb.repo")
-	output, err := cmd.Output()
+	repo, dir, err := config.CurrentPinnedRepo()
 	if err != nil {
-		return "", err
+		return err
 	}
-	return strings.TrimSpace(string(output)), nil
-}
 
-func unsetGitConfig() error {
-	cmd := execCommand("git", "config", "--local", "--unset", "bb.repo")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to unset git config: %w", err)
+	if repo == "" {
+		opts.Streams.Info("No default repository set")
+		return nil
 	}
+
+	opts.Streams.Info("Default repository: %s (pinned to %s)", repo, dir)
 	return nil
 }
 
-func setLocalConfig(repo string) error {
-	config := LocalConfig{
-		DefaultRepo: repo,
+func unsetDefault(opts *SetDefaultOptions) error {
+	if remote := git.ConfiguredRemote(); remote != "" {
+		if err := git.SetSelectedRemote(""); err != nil {
+			return err
+		}
+		opts.Streams.Success("Removed default remote %s", remote)
 	}
 
-	data, err := yaml.Marshal(config)
+	cfg, err := config.LoadConfig()
 	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
+		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	configPath := filepath.Join(".", ".bb.yml")
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write .bb.yml: %w", err)
-	}
-
-	return nil
-}
-
-func getLocalConfig() (string, error) {
-	configPath := filepath.Join(".", ".bb.yml")
-
-	data, err := os.ReadFile(configPath)
+	_, dir, err := config.CurrentPinnedRepo()
 	if err != nil {
-		return "", err
+		return err
+	}
+	if dir == "" || !cfg.UnpinRepo(dir) {
+		opts.Streams.Info("No default repository was set")
+		return nil
 	}
 
-	var config LocalConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return "", err
+	if err := config.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
 	}
 
-	return config.DefaultRepo, nil
+	opts.Streams.Success("Removed default repository for %s", dir)
+	return nil
 }
 
-func removeLocalConfig() error {
-	configPath := filepath.Join(".", ".bb.yml")
-
-	// Check if file exists
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return fmt.Errorf("no .bb.yml file found")
+// storeDefault pins repo to the current git repository, or to the current
+// directory outside of one, and returns the directory it was pinned to.
+func storeDefault(repo string) (string, error) {
+	dir, err := config.PinDirectory()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine current directory: %w", err)
 	}
 
-	// Read existing config
-	data, err := os.ReadFile(configPath)
+	cfg, err := config.LoadConfig()
 	if err != nil {
-		return err
+		return "", fmt.Errorf("failed to load config: %w", err)
 	}
-
-	var config LocalConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		// File exists but invalid, just remove it
-		return os.Remove(configPath)
+	cfg.PinRepo(dir, repo)
+	if err := config.SaveConfig(cfg); err != nil {
+		return "", fmt.Errorf("failed to save config: %w", err)
 	}
-
-	// If only default_repo was set, remove the file
-	config.DefaultRepo = ""
-
-	// Check if config is now empty
-	newData, _ := yaml.Marshal(config)
-	if strings.TrimSpace(string(newData)) == "{}" || strings.TrimSpace(string(newData)) == "" {
-		return os.Remove(configPath)
-	}
-
-	// Otherwise, write back without the default_repo
-	return os.WriteFile(configPath, newData, 0644)
+	return dir, nil
 }
 
 func confirmSetDefault(streams *iostreams.IOStreams, repo string) bool {
@@ -320,50 +267,3 @@ func confirmSetDefault(streams *iostreams.IOStreams, repo string) bool {
 
 	return input == "" || input == "y" || input == "yes"
 }
-
-// execCommand is a wrapper for exec.Command to allow testing
-var execCommand = execCommandImpl
-
-func execCommandImpl(name string, args ...string) *execCmd {
-	return &execCmd{name: name, args: args}
-}
-
-type execCmd struct {
-	name string
-	args []string
-}
-
-func (c *execCmd) Run() error {
-	cmd := newOSExecCommand(c.name, c.args...)
-	return cmd.Run()
-}
-
-func (c *execCmd) Output() ([]byte, error) {
-	cmd := newOSExecCommand(c.name, c.args...)
-	return cmd.Output()
-}
-
-// newOSExecCommand wraps os/exec.Command
-func newOSExecCommand(name string, args ...string) osExecCommand {
-	return &realExecCommand{name: name, args: args}
-}
-
-type osExecCommand interface {
-	Run() error
-	Output() ([]byte, error)
-}
-
-type realExecCommand struct {
-	name string
-	args []string
-}
-
-func (c *realExecCommand) Run() error {
-	cmd := exec.Command(c.name, c.args...)
-	return cmd.Run()
-}
-
-func (c *realExecCommand) Output() ([]byte, error) {
-	cmd := exec.Command(c.name, c.args...)
-	return cmd.Output()
-}
@@ -0,0 +1,467 @@
+package repo
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// statsActivityConcurrency is how many pull requests' activity is read at
+// once
+const statsActivityConcurrency = 8
+
+type statsOptions struct {
+	streams *iostreams.IOStreams
+	repo    string
+	since   string
+	branch  string
+	limit   int
+	json    bool
+	format  string
+}
+
+// repoStats summarizes the recent work on a repository
+type repoStats struct {
+	Repository   string        `json:"repository"`
+	Branch       string        `json:"branch"`
+	Since        time.Time     `json:"since"`
+	Commits      int           `json:"commits"`
+	Authors      []authorStats `json:"authors"`
+	PullRequests prStats       `json:"pull_requests"`
+}
+
+type authorStats struct {
+	Name    string `json:"name"`
+	Commits int    `json:"commits"`
+}
+
+// prStats summarizes the pull requests merged in a period: how long they
+// took from being opened to being merged, and to their first review
+type prStats struct {
+	Merged           int             `json:"merged"`
+	LeadTime         durationStats   `json:"lead_time"`
+	Reviewed         int             `json:"reviewed"`
+	ReviewTurnaround durationStats   `json:"review_turnaround"`
+	Reviewers        []reviewerStats `json:"reviewers"`
+}
+
+type durationStats struct {
+	MedianSeconds int64 `json:"median_seconds"`
+	P90Seconds    int64 `json:"p90_seconds"`
+}
+
+type reviewerStats struct {
+	Name    string `json:"name"`
+	Reviews int    `json:"reviews"`
+}
+
+// NewCmdStats creates the stats command
+func NewCmdStats(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &statsOptions{
+		streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Summarize who works on a repository and how fast changes land",
+		Long: `Summarize the recent work on a repository: the commits on its main
+branch by author, and for the pull requests merged, their lead time, from
+being opened to being merged, and review turnaround, from being opened to
+their first approval, request for changes or comment by someone other than
+their author. Times are given as the median and the 90th percentile.
+
+--since takes a duration, in days (d), weeks (w), months (m or mo) or years
+(y), such as 6m, or a date, such as 2024-05-01.`,
+		Example: `  # Summarize the last six months
+  bb repo stats
+
+  # Summarize the last two weeks of another repository
+  bb repo stats --since 2w -R myworkspace/myrepo
+
+  # Count the commits on a release branch since a date
+  bb repo stats --branch release/2.0 --since 2024-01-01
+
+  # Output as JSON
+  bb repo stats --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.limit < 1 {
+				return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("--limit must be at least 1"))
+			}
+			return runStats(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.since, "since", "6m", "Summarize the work since a duration ago or a date")
+	cmd.Flags().StringVarP(&opts.branch, "branch", "b", "", "Branch to count commits on (default: the main branch)")
+	_ = cmd.RegisterFlagCompletionFunc("branch", cmdutil.CompleteBranches)
+	cmd.Flags().IntVarP(&opts.limit, "limit", "l", 10, "Maximum number of authors and reviewers to show")
+	cmd.Flags().StringVarP(&opts.repo, "repo", "R", "", "Repository in WORKSPACE/REPO format (detects from git remote if not specified)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.format)
+
+	return cmd
+}
+
+func runStats(ctx context.Context, opts *statsOptions) error {
+	since, err := parseStatsSince(opts.since, time.Now())
+	if err != nil {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, err)
+	}
+
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	branch := opts.branch
+	if branch == "" {
+		if branch, err = cmdutil.DefaultBranch(ctx, client, workspace, repoSlug); err != nil {
+			return fmt.Errorf("could not determine the main branch; use --branch: %w", err)
+		}
+	}
+
+	progress := opts.streams.StartProgress("Reading history")
+	var (
+		wg                sync.WaitGroup
+		commits           []api.CommitFull
+		merged            []api.PullRequest
+		activity          map[int64][]api.PRActivity
+		commitErr, prsErr error
+	)
+	wg.Go(func() {
+		commits, commitErr = listCommitsSince(ctx, client, workspace, repoSlug, branch, since)
+	})
+	wg.Go(func() {
+		merged, activity, prsErr = listMergedPRs(ctx, client, workspace, repoSlug, since)
+	})
+	wg.Wait()
+	progress.Stop()
+	if commitErr != nil {
+		return fmt.Errorf("failed to list commits: %w", commitErr)
+	}
+	if prsErr != nil {
+		return fmt.Errorf("failed to list pull requests: %w", prsErr)
+	}
+
+	stats := &repoStats{
+		Repository:   workspace + "/" + repoSlug,
+		Branch:       branch,
+		Since:        since,
+		Commits:      len(commits),
+		Authors:      countAuthors(commits),
+		PullRequests: summarizePRs(merged, activity, since),
+	}
+
+	if opts.json || opts.format != "" {
+		return cmdutil.PrintFormatted(opts.streams, opts.format, stats)
+	}
+	return printStats(opts.streams, stats, opts.limit)
+}
+
+// parseStatsSince parses --since, which unlike other commands' takes
+// months (m or mo) and years (y), as minutes are too short to summarize
+func parseStatsSince(value string, now time.Time) (time.Time, error) {
+	for _, unit := range []struct {
+		suffix        string
+		years, months int
+	}{{"mo", 0, 1}, {"m", 0, 1}, {"y", 1, 0}} {
+		if n, ok := strings.CutSuffix(value, unit.suffix); ok {
+			if count, err := strconv.Atoi(n); err == nil && count >= 0 {
+				return now.AddDate(-count*unit.years, -count*unit.months, 0), nil
+			}
+		}
+	}
+	return cmdutil.ParseTimeFlag("--since", value, now)
+}
+
+// listCommitsSince lists the commits on branch made since a time, newest
+// first. The pages have to be read in turn, as there is no telling which
+// holds the oldest commit wanted.
+func listCommitsSince(ctx context.Context, client *api.Client, workspace, repoSlug, branch string, since time.Time) ([]api.CommitFull, error) {
+	var commits []api.CommitFull
+	for page := 1; ; page++ {
+		result, err := client.ListCommits(ctx, workspace, repoSlug, &api.CommitListOptions{
+			Branch: branch,
+			Page:   page,
+			Limit:  100,
+		})
+		if err != nil {
+			return nil, err
+		}
+		older := false
+		for _, c := range result.Values {
+			if c.Date.Before(since) {
+				older = true
+				continue
+			}
+			commits = append(commits, c)
+		}
+		if older || result.Next == "" || len(result.Values) == 0 {
+			return commits, nil
+		}
+	}
+}
+
+// listMergedPRs lists the pull requests merged since a time, with their
+// activity, which is read for several pull requests at once
+func listMergedPRs(ctx context.Context, client *api.Client, workspace, repoSlug string, since time.Time) ([]api.PullRequest, map[int64][]api.PRActivity, error) {
+	// A pull request is last updated when merged or later
+	prs, err := client.ListAllPullRequests(ctx, workspace, repoSlug, &api.PRListOptions{
+		State: api.PRStateMerged,
+		Query: fmt.Sprintf("updated_on >= %s", since.UTC().Format(time.RFC3339)),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	activity := make(map[int64][]api.PRActivity, len(prs))
+	limiter := make(chan struct{}, statsActivityConcurrency)
+	for _, pr := range prs {
+		wg.Go(func() {
+			limiter <- struct{}{}
+			defer func() { <-limiter }()
+
+			events, err := client.ListAllPullRequestActivity(ctx, workspace, repoSlug, pr.ID)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to get the activity of #%d: %w", pr.ID, err)
+				}
+				return
+			}
+			activity[pr.ID] = events
+		})
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+	return prs, activity, nil
+}
+
+// countAuthors counts the commits of each author, most first
+func countAuthors(commits []api.CommitFull) []authorStats {
+	counts := make(map[string]int)
+	for _, c := range commits {
+		counts[commitAuthor(c)]++
+	}
+	authors := make([]authorStats, 0, len(counts))
+	for name, n := range counts {
+		authors = append(authors, authorStats{Name: name, Commits: n})
+	}
+	slices.SortFunc(authors, func(a, b authorStats) int {
+		return cmp.Or(cmp.Compare(b.Commits, a.Commits), cmp.Compare(a.Name, b.Name))
+	})
+	return authors
+}
+
+// commitAuthor names the author of c: the Bitbucket user if the commit is
+// linked to one, else the name in the commit
+func commitAuthor(c api.CommitFull) string {
+	if c.Author.User != nil {
+		return cmdutil.GetUserDisplayName(c.Author.User)
+	}
+	name, _, _ := strings.Cut(c.Author.Raw, "<")
+	if name = strings.TrimSpace(name); name != "" {
+		return name
+	}
+	return c.Author.Raw
+}
+
+// summarizePRs works out the lead time and review turnaround of the pull
+// requests merged since a time, from their activity
+func summarizePRs(prs []api.PullRequest, activity map[int64][]api.PRActivity, since time.Time) prStats {
+	var (
+		stats       prStats
+		leadTimes   []time.Duration
+		turnarounds []time.Duration
+	)
+	reviews := make(map[string]int)
+	for _, pr := range prs {
+		events := activity[pr.ID]
+		mergedOn := pr.UpdatedOn
+		var firstReview time.Time
+		reviewers := make(map[string]bool)
+		for _, e := range events {
+			var (
+				user   *api.User
+				date   time.Time
+				review bool
+			)
+			switch {
+			case e.Update != nil:
+				if e.Update.State == api.PRStateMerged {
+					mergedOn = e.Update.Date
+				}
+				continue
+			case e.Approval != nil:
+				user, date, review = &e.Approval.User, e.Approval.Date, true
+			case e.ChangesRequested != nil:
+				user, date, review = &e.ChangesRequested.User, e.ChangesRequested.Date, true
+			case e.Comment != nil:
+				user, date = &e.Comment.User, e.Comment.CreatedOn
+			default:
+				continue
+			}
+			if isAuthor(pr, user) {
+				continue
+			}
+			if review {
+				reviewers[cmdutil.GetUserDisplayName(user)] = true
+			}
+			if firstReview.IsZero() || date.Before(firstReview) {
+				firstReview = date
+			}
+		}
+		if mergedOn.Before(since) {
+			continue
+		}
+
+		stats.Merged++
+		leadTimes = append(leadTimes, mergedOn.Sub(pr.CreatedOn))
+		if !firstReview.IsZero() {
+			stats.Reviewed++
+			turnarounds = append(turnarounds, firstReview.Sub(pr.CreatedOn))
+		}
+		for name := range reviewers {
+			reviews[name]++
+		}
+	}
+
+	stats.LeadTime = summarizeDurations(leadTimes)
+	stats.ReviewTurnaround = summarizeDurations(turnarounds)
+	stats.Reviewers = make([]reviewerStats, 0, len(reviews))
+	for name, n := range reviews {
+		stats.Reviewers = append(stats.Reviewers, reviewerStats{Name: name, Reviews: n})
+	}
+	slices.SortFunc(stats.Reviewers, func(a, b reviewerStats) int {
+		return cmp.Or(cmp.Compare(b.Reviews, a.Reviews), cmp.Compare(a.Name, b.Name))
+	})
+	return stats
+}
+
+// isAuthor reports whether user is the author of pr
+func isAuthor(pr api.PullRequest, user *api.User) bool {
+	if user.UUID != "" || pr.Author.UUID != "" {
+		return user.UUID == pr.Author.UUID
+	}
+	return user.AccountID == pr.Author.AccountID
+}
+
+// summarizeDurations returns the median and 90th percentile of durations,
+// by the nearest rank
+func summarizeDurations(durations []time.Duration) durationStats {
+	if len(durations) == 0 {
+		return durationStats{}
+	}
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	rank := func(p int) time.Duration {
+		i := (p*len(sorted)+99)/100 - 1
+		return sorted[max(i, 0)]
+	}
+	return durationStats{
+		MedianSeconds: int64(rank(50).Seconds()),
+		P90Seconds:    int64(rank(90).Seconds()),
+	}
+}
+
+// formatSpan formats a number of seconds in the two largest units of days,
+// hours and minutes
+func formatSpan(seconds int64) string {
+	d := time.Duration(seconds) * time.Second
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+func printStats(streams *iostreams.IOStreams, s *repoStats, limit int) error {
+	out := streams.Out
+	fmt.Fprintf(out, "%s since %s\n", streams.Style(iostreams.RoleHeader, s.Repository), s.Since.Format("2 Jan 2006"))
+
+	fmt.Fprintf(out, "\nCommits on %s: %d by %d %s\n", s.Branch, s.Commits, len(s.Authors), plural(len(s.Authors), "author", "authors"))
+	if len(s.Authors) > 0 {
+		table := cmdutil.NewTablePrinter(streams)
+		table.AddHeader("AUTHOR", "COMMITS", "SHARE")
+		for _, a := range s.Authors[:min(limit, len(s.Authors))] {
+			table.AddRow(a.Name, strconv.Itoa(a.Commits), fmt.Sprintf("%d%%", a.Commits*100/s.Commits))
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+		if more := len(s.Authors) - limit; more > 0 {
+			fmt.Fprintln(out, streams.Style(iostreams.RoleMuted, fmt.Sprintf("and %d more", more)))
+		}
+	}
+
+	prs := s.PullRequests
+	fmt.Fprintf(out, "\nPull requests merged: %d\n", prs.Merged)
+	if prs.Merged == 0 {
+		return nil
+	}
+	fmt.Fprintf(out, "Lead time, opened to merged:       median %s, 90th percentile %s\n",
+		formatSpan(prs.LeadTime.MedianSeconds), formatSpan(prs.LeadTime.P90Seconds))
+	if prs.Reviewed == 0 {
+		fmt.Fprintln(out, "Review turnaround: none were reviewed")
+		return nil
+	}
+	fmt.Fprintf(out, "Review turnaround, to first review: median %s, 90th percentile %s (%d of %d reviewed)\n",
+		formatSpan(prs.ReviewTurnaround.MedianSeconds), formatSpan(prs.ReviewTurnaround.P90Seconds), prs.Reviewed, prs.Merged)
+
+	if len(prs.Reviewers) > 0 {
+		fmt.Fprintln(out)
+		table := cmdutil.NewTablePrinter(streams)
+		table.AddHeader("REVIEWER", "PULL REQUESTS REVIEWED")
+		for _, r := range prs.Reviewers[:min(limit, len(prs.Reviewers))] {
+			table.AddRow(r.Name, strconv.Itoa(r.Reviews))
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+		if more := len(prs.Reviewers) - limit; more > 0 {
+			fmt.Fprintln(out, streams.Style(iostreams.RoleMuted, fmt.Sprintf("and %d more", more)))
+		}
+	}
+	return nil
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,128 @@
+package repo
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+func TestParseStatsSince(t *testing.T) {
+	now := time.Date(2024, 8, 31, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "6m", want: time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)},
+		{value: "1mo", want: time.Date(2024, 7, 31, 12, 0, 0, 0, time.UTC)},
+		{value: "1y", want: time.Date(2023, 8, 31, 12, 0, 0, 0, time.UTC)},
+		{value: "2w", want: now.Add(-14 * 24 * time.Hour)},
+		{value: "2024-01-01", want: time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)},
+		{value: "lately", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseStatsSince(tt.value, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseStatsSince() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("parseStatsSince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountAuthors(t *testing.T) {
+	commit := func(raw string, user *api.User) api.CommitFull {
+		var c api.CommitFull
+		c.Author.Raw = raw
+		c.Author.User = user
+		return c
+	}
+	commits := []api.CommitFull{
+		commit("Ann Lee <ann@example.com>", &api.User{DisplayName: "Ann Lee"}),
+		commit("Bob <bob@example.com>", nil),
+		commit("Ann Lee <ann@example.com>", &api.User{DisplayName: "Ann Lee"}),
+		commit("ci@example.com", nil),
+	}
+
+	got := fmt.Sprint(countAuthors(commits))
+	if want := "[{Ann Lee 2} {Bob 1} {ci@example.com 1}]"; got != want {
+		t.Errorf("countAuthors() = %s, want %s", got, want)
+	}
+}
+
+func TestSummarizePRs(t *testing.T) {
+	since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	opened := time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+	author := api.User{UUID: "{author}", DisplayName: "Author"}
+	ann := api.User{UUID: "{ann}", DisplayName: "Ann"}
+	bob := api.User{UUID: "{bob}", DisplayName: "Bob"}
+
+	pr := func(id int64, updated time.Time) api.PullRequest {
+		return api.PullRequest{ID: id, Author: author, CreatedOn: opened, UpdatedOn: updated}
+	}
+	merged := func(at time.Time) api.PRActivity {
+		return api.PRActivity{Update: &api.PRActivityUpdate{State: api.PRStateMerged, Date: at}}
+	}
+	approved := func(user api.User, at time.Time) api.PRActivity {
+		return api.PRActivity{Approval: &api.PRActivityApproval{User: user, Date: at}}
+	}
+	commented := func(user api.User, at time.Time) api.PRActivity {
+		c := &api.PRComment{User: user, CreatedOn: at}
+		return api.PRActivity{Comment: c}
+	}
+
+	prs := []api.PullRequest{
+		pr(1, opened.Add(48*time.Hour)),
+		pr(2, opened.Add(10*time.Hour)),
+		pr(3, opened.Add(5*time.Hour)),
+		// Updated since, but merged before
+		pr(4, since.Add(time.Hour)),
+	}
+	activity := map[int64][]api.PRActivity{
+		1: {
+			merged(opened.Add(24 * time.Hour)),
+			approved(ann, opened.Add(4*time.Hour)),
+			commented(bob, opened.Add(2*time.Hour)),
+			commented(author, opened.Add(time.Hour)),
+		},
+		2: {approved(ann, opened.Add(6*time.Hour)), approved(bob, opened.Add(8*time.Hour))},
+		3: {commented(author, opened.Add(time.Hour))},
+		4: {merged(since.Add(-time.Hour))},
+	}
+
+	got := summarizePRs(prs, activity, since)
+	if got.Merged != 3 || got.Reviewed != 2 {
+		t.Errorf("merged %d and reviewed %d, want 3 and 2", got.Merged, got.Reviewed)
+	}
+	// Lead times are 24h, 10h and 5h
+	if got.LeadTime != (durationStats{MedianSeconds: 10 * 3600, P90Seconds: 24 * 3600}) {
+		t.Errorf("lead time = %+v", got.LeadTime)
+	}
+	// Turnarounds are 2h, Bob's comment, and 6h
+	if got.ReviewTurnaround != (durationStats{MedianSeconds: 2 * 3600, P90Seconds: 6 * 3600}) {
+		t.Errorf("review turnaround = %+v", got.ReviewTurnaround)
+	}
+	if fmt.Sprint(got.Reviewers) != "[{Ann 2} {Bob 1}]" {
+		t.Errorf("reviewers = %v", got.Reviewers)
+	}
+}
+
+func TestFormatSpan(t *testing.T) {
+	for seconds, want := range map[int64]string{
+		0:                  "0m",
+		45 * 60:            "45m",
+		3*3600 + 10*60:     "3h 10m",
+		2*86400 + 5*3600:   "2d 5h",
+		30*86400 + 59*3600: "32d 11h",
+	} {
+		if got := formatSpan(seconds); got != want {
+			t.Errorf("formatSpan(%d) = %q, want %q", seconds, got, want)
+		}
+	}
+}
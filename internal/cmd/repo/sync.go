@@ -2,11 +2,9 @@ package repo
 
 import (
 	"bufio"
-	"bytes"
 	"context"
 	"fmt"
 	"io"
-	"os/exec"
 	"strings"
 	"time"
 
@@ -112,7 +110,7 @@ func runSync(opts *syncOptions) error {
 	// Fetch from upstream
 	opts.streams.Info("Fetching from upstream %s...", parentFullName)
 	refspec := buildFetchRefspec(upstreamRemote, branch)
-	if err := fetchUpstream(upstreamRemote, refspec); err != nil {
+	if err := git.Fetch(upstreamRemote, refspec); err != nil {
 		return fmt.Errorf("failed to fetch from upstream: %w", err)
 	}
 
@@ -130,11 +128,11 @@ func runSync(opts *syncOptions) error {
 			return fmt.Errorf("force sync cancelled")
 		}
 
-		if err := resetToUpstream(upstreamRemote, branch); err != nil {
+		if err := git.ResetHard(upstreamRemote + "/" + branch); err != nil {
 			return fmt.Errorf("failed to reset to upstream: %w", err)
 		}
 	} else {
-		if err := mergeUpstream(upstreamRemote, branch); err != nil {
+		if err := git.Merge(upstreamRemote+"/"+branch, true); err != nil {
 			return fmt.Errorf("failed to merge upstream changes: %w", err)
 		}
 	}
@@ -181,60 +179,12 @@ func buildParentURL(parent *api.ParentRepository) string {
 
 // ensureUpstreamRemote ensures the upstream remote exists
 func ensureUpstreamRemote(remoteName, url string) error {
-	// Check if remote exists
-	cmd := exec.Command("git", "remote", "get-url", remoteName)
-	if err := cmd.Run(); err != nil {
+	if _, err := git.RemoteURL(remoteName); err != nil {
 		// Remote doesn't exist, add it
-		cmd = exec.Command("git", "remote", "add", remoteName, url)
-		return cmd.Run()
+		return git.AddRemote("", remoteName, url)
 	}
 	// Remote exists, update URL
-	cmd = exec.Command("git", "remote", "set-url", remoteName, url)
-	return cmd.Run()
-}
-
-// fetchUpstream fetches from the upstream remote
-func fetchUpstream(remote, refspec string) error {
-	cmd := exec.Command("git", "fetch", remote, refspec)
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
-		if stderr.Len() > 0 {
-			return fmt.Errorf("%w: %s", err, stderr.String())
-		}
-		return err
-	}
-	return nil
-}
-
-// mergeUpstream merges changes from upstream
-func mergeUpstream(remote, branch string) error {
-	ref := fmt.Sprintf("%s/%s", remote, branch)
-	cmd := exec.Command("git", "merge", ref, "--ff-only")
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
-		if stderr.Len() > 0 {
-			return fmt.Errorf("%w: %s", err, stderr.String())
-		}
-		return err
-	}
-	return nil
-}
-
-// resetToUpstream resets the current branch to upstream
-func resetToUpstream(remote, branch string) error {
-	ref := fmt.Sprintf("%s/%s", remote, branch)
-	cmd := exec.Command("git", "reset", "--hard", ref)
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
-		if stderr.Len() > 0 {
-			return fmt.Errorf("%w: %s", err, stderr.String())
-		}
-		return err
-	}
-	return nil
+	return git.SetRemoteURL(remoteName, url)
 }
 
 // confirmForceSync prompts the user to confirm force sync operation
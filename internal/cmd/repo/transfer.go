@@ -0,0 +1,255 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+type transferOptions struct {
+	streams   *iostreams.IOStreams
+	repoArg   string
+	to        string
+	yes       bool
+	noBrowser bool
+}
+
+// NewCmdTransfer creates the transfer command
+func NewCmdTransfer(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &transferOptions{
+		streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "transfer <workspace/repo> --to <workspace>",
+		Short: "Transfer a repository to another workspace",
+		Long: `Start transferring the ownership of a repository to another workspace.
+
+Bitbucket Cloud's API has no endpoint for transfers, so after checking
+that the repository and the new workspace exist, and that the new
+workspace has no repository of the same name, this opens the repository's
+transfer page in the browser to send the request. An admin of the new
+workspace then has to accept it.
+
+The transfer is recorded, so 'bb repo transfer status' can show whether
+it has completed.
+
+You will be prompted to type the repository name to confirm, unless
+--yes is given.`,
+		Example: `  # Offer a repository to another workspace
+  bb repo transfer myworkspace/myrepo --to newworkspace
+
+  # Check on pending transfers
+  bb repo transfer status`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.repoArg = args[0]
+			return runTransfer(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.to, "to", "", "Workspace to transfer the repository to (required)")
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Skip confirmation prompt")
+	cmd.Flags().BoolVar(&opts.noBrowser, "no-browser", false, "Print the transfer page's URL instead of opening it")
+	_ = cmd.MarkFlagRequired("to")
+
+	cmd.AddCommand(newCmdTransferStatus(streams))
+
+	return cmd
+}
+
+func runTransfer(ctx context.Context, opts *transferOptions) error {
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.repoArg)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(workspace, opts.to) {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("%s/%s is already in workspace %s", workspace, repoSlug, opts.to))
+	}
+
+	if !opts.yes && !opts.streams.IsStdinTTY() {
+		return fmt.Errorf("cannot confirm transfer: stdin is not a terminal\nUse --yes flag to skip confirmation in non-interactive mode")
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	repo, err := client.GetRepository(ctx, workspace, repoSlug)
+	if err != nil {
+		return fmt.Errorf("failed to get repository: %w", err)
+	}
+	if _, err := client.GetWorkspace(ctx, opts.to); err != nil {
+		return fmt.Errorf("failed to get workspace %s: %w", opts.to, err)
+	}
+	if _, err := client.GetRepository(ctx, opts.to, repoSlug); err == nil {
+		return fmt.Errorf("workspace %s already has a repository named %s", opts.to, repoSlug)
+	} else if !isNotFound(err) {
+		return fmt.Errorf("failed to check workspace %s: %w", opts.to, err)
+	}
+
+	if !opts.yes {
+		fmt.Fprintf(opts.streams.ErrOut, "! %s/%s will belong to %s once an admin of %s accepts the transfer.\n", workspace, repoSlug, opts.to, opts.to)
+		fmt.Fprintf(opts.streams.Out, "Type '%s' to confirm the transfer: ", repoSlug)
+		if !confirmDeletion(repoSlug, opts.streams.In) {
+			return fmt.Errorf("transfer cancelled: repository name did not match")
+		}
+	}
+
+	if err := config.AddTransfer(config.Transfer{
+		Workspace:   workspace,
+		Repo:        repoSlug,
+		To:          opts.to,
+		RequestedAt: time.Now(),
+	}); err != nil {
+		return fmt.Errorf("failed to record transfer: %w", err)
+	}
+
+	transferURL := strings.TrimSuffix(repo.Links.HTML.Href, "/") + "/admin/transfer"
+	if opts.noBrowser {
+		fmt.Fprintln(opts.streams.Out, transferURL)
+	} else if err := cmdutil.OpenInBrowser(opts.streams, transferURL); err != nil {
+		opts.streams.Warning("%s", err)
+		fmt.Fprintln(opts.streams.Out, transferURL)
+	}
+	opts.streams.Info("Enter %s as the new owner to send the transfer request, then run 'bb repo transfer status' to follow it", opts.to)
+	return nil
+}
+
+// transferState is how far a recorded transfer has got
+type transferState struct {
+	Repository string    `json:"repository"`
+	To         string    `json:"to"`
+	Requested  time.Time `json:"requested_at"`
+	// Status is pending while the repository is in its old workspace,
+	// completed once it is in the new one, and unknown when it is in
+	// neither, such as when it was renamed or deleted
+	Status string `json:"status"`
+}
+
+type transferStatusOptions struct {
+	streams *iostreams.IOStreams
+	json    bool
+}
+
+func newCmdTransferStatus(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &transferStatusOptions{
+		streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the status of pending repository transfers",
+		Long: `Show whether the repository transfers started with 'bb repo transfer'
+have completed. Completed transfers are reported once and then forgotten.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTransferStatus(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func runTransferStatus(ctx context.Context, opts *transferStatusOptions) error {
+	transfers, err := config.LoadTransfers()
+	if err != nil {
+		return err
+	}
+	if len(transfers) == 0 {
+		if opts.json {
+			return cmdutil.PrintJSON(opts.streams, []transferState{})
+		}
+		opts.streams.Info("No pending repository transfers")
+		return nil
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	states := make([]transferState, 0, len(transfers))
+	for _, t := range transfers {
+		status, err := checkTransfer(ctx, client, t)
+		if err != nil {
+			return err
+		}
+		if status == "completed" {
+			if _, err := config.RemoveTransfer(t); err != nil {
+				opts.streams.Warning("could not update recorded transfers: %v", err)
+			}
+		}
+		states = append(states, transferState{
+			Repository: t.Workspace + "/" + t.Repo,
+			To:         t.To,
+			Requested:  t.RequestedAt,
+			Status:     status,
+		})
+	}
+
+	if opts.json {
+		return cmdutil.PrintJSON(opts.streams, states)
+	}
+
+	table := cmdutil.NewTablePrinter(opts.streams)
+	table.AddHeader("REPOSITORY", "TO", "REQUESTED", "STATUS")
+	for _, s := range states {
+		status := s.Status
+		switch status {
+		case "completed":
+			status = opts.streams.Style(iostreams.RoleSuccess, status)
+		case "pending":
+			status = opts.streams.Style(iostreams.RoleInfo, status)
+		default:
+			status = opts.streams.Style(iostreams.RoleMuted, status)
+		}
+		table.AddRow(s.Repository, s.To, cmdutil.FormatTime(opts.streams, s.Requested), status)
+	}
+	return table.Render()
+}
+
+// checkTransfer finds where the repository of t is now: completed when it
+// is in the new workspace, pending when it is still in the old one, and
+// unknown when it is in neither
+func checkTransfer(ctx context.Context, client *api.Client, t config.Transfer) (string, error) {
+	for _, check := range []struct {
+		workspace string
+		status    string
+	}{{t.To, "completed"}, {t.Workspace, "pending"}} {
+		_, err := client.GetRepository(ctx, check.workspace, t.Repo)
+		if err == nil {
+			return check.status, nil
+		}
+		if !isNotFound(err) {
+			return "", fmt.Errorf("failed to check %s/%s: %w", check.workspace, t.Repo, err)
+		}
+	}
+	return "unknown", nil
+}
+
+// isNotFound reports whether err is the API's response to something that
+// doesn't exist, or that the user can't see
+func isNotFound(err error) bool {
+	var apiErr *api.APIError
+	return errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusForbidden)
+}
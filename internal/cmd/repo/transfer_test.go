@@ -0,0 +1,51 @@
+package repo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+)
+
+func TestCheckTransfer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repositories/acme/done", "/repositories/team/waiting":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"slug": "repo"}`))
+		case "/repositories/team/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error": {"message": "Internal server error"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"message": "Repository not found"}}`))
+		}
+	}))
+	defer server.Close()
+	client := api.NewClient(api.WithBaseURL(server.URL))
+
+	tests := []struct {
+		repo    string
+		want    string
+		wantErr bool
+	}{
+		{repo: "done", want: "completed"},
+		{repo: "waiting", want: "pending"},
+		{repo: "gone", want: "unknown"},
+		{repo: "broken", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			got, err := checkTransfer(context.Background(), client, config.Transfer{Workspace: "team", Repo: tt.repo, To: "acme"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkTransfer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("checkTransfer() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
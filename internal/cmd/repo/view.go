@@ -2,15 +2,14 @@ package repo
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/rbansal42/bitbucket-cli/internal/api"
-	"github.com/rbansal42/bitbucket-cli/internal/browser"
 	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
@@ -19,7 +18,9 @@ type viewOptions struct {
 	streams   *iostreams.IOStreams
 	repoArg   string
 	web       bool
+	copy      bool
 	jsonOut   bool
+	format    string
 	workspace string
 	repoSlug  string
 }
@@ -38,6 +39,9 @@ func NewCmdView(streams *iostreams.IOStreams) *cobra.Command {
 With no arguments, the repository for the current directory is displayed
 (detected from git remote).
 
+The numbers of open pull requests and issues are fetched alongside the
+repository and left out if they can't be fetched.
+
 You can specify a repository using the workspace/repo format.`,
 		Example: `  # View the current repository
   bb repo view
@@ -49,7 +53,10 @@ You can specify a repository using the workspace/repo format.`,
   bb repo view --web
 
   # Output as JSON
-  bb repo view --json`,
+  bb repo view --json
+
+  # Output as YAML
+  bb repo view --format yaml`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
@@ -60,8 +67,10 @@ You can specify a repository using the workspace/repo format.`,
 		},
 	}
 
-	cmd.Flags().BoolVarP(&opts.web, "web", "w", false, "Open the repository in a web browser")
+	cmdutil.AddWebFlag(cmd, &opts.web, "repository")
+	cmdutil.AddCopyFlag(cmd, &opts.copy, "repository")
 	cmd.Flags().BoolVar(&opts.jsonOut, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.format)
 
 	return cmd
 }
@@ -83,40 +92,90 @@ func runView(opts *viewOptions) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Fetch repository details
-	repo, err := client.GetRepository(ctx, opts.workspace, opts.repoSlug)
+	// Fetch repository details, with the counts only the formatted view
+	// shows
+	structured := opts.jsonOut || opts.format != ""
+	view, err := fetchRepoView(ctx, client, opts.workspace, opts.repoSlug, !structured && !opts.web)
 	if err != nil {
 		return fmt.Errorf("failed to get repository: %w", err)
 	}
+	repo := view.repo
 
-	// Handle --web flag
-	if opts.web {
-		if err := browser.Open(repo.Links.HTML.Href); err != nil {
-			return fmt.Errorf("could not open browser: %w", err)
+	// Handle --copy and --web flags
+	if opts.copy {
+		if err := cmdutil.CopyURL(opts.streams, repo.Links.HTML.Href); err != nil {
+			return err
 		}
-		opts.streams.Success("Opened %s in your browser", repo.Links.HTML.Href)
-		return nil
+	}
+	if opts.web {
+		return cmdutil.OpenInBrowser(opts.streams, repo.Links.HTML.Href)
 	}
 
-	// Handle --json flag
-	if opts.jsonOut {
-		return outputJSON(opts.streams, repo)
+	// Handle --json and --format flags
+	if structured {
+		return outputStructured(opts.streams, opts.format, repo)
 	}
 
 	// Display formatted output
-	return displayRepo(opts.streams, repo)
+	return displayRepo(opts.streams, view)
 }
 
-func outputJSON(streams *iostreams.IOStreams, repo *api.RepositoryFull) error {
-	data, err := json.MarshalIndent(repo, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
+// repoView is a repository and the counts shown alongside it. A count is -1
+// if it could not be fetched, or was not asked for.
+type repoView struct {
+	repo       *api.RepositoryFull
+	openPRs    int
+	openIssues int
+}
+
+// fetchRepoView fetches a repository and, when counts is set, its numbers
+// of open pull requests and issues. The requests run concurrently. Only
+// failing to get the repository itself is an error.
+func fetchRepoView(ctx context.Context, client *api.Client, workspace, repoSlug string, counts bool) (*repoView, error) {
+	view := &repoView{openPRs: -1, openIssues: -1}
+	var repoErr error
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		view.repo, repoErr = client.GetRepository(ctx, workspace, repoSlug)
+	})
+	if counts {
+		wg.Go(func() {
+			result, err := client.ListPullRequests(ctx, workspace, repoSlug, &api.PRListOptions{
+				State: api.PRStateOpen,
+				Limit: 1,
+			})
+			if err == nil {
+				view.openPRs = result.Size
+			}
+		})
+		// Repositories without an issue tracker answer 404, leaving the
+		// count out
+		wg.Go(func() {
+			result, err := client.ListIssues(ctx, workspace, repoSlug, &api.IssueListOptions{
+				Q:     `(state="new" OR state="open")`,
+				Limit: 1,
+			})
+			if err == nil {
+				view.openIssues = result.Size
+			}
+		})
 	}
-	fmt.Fprintln(streams.Out, string(data))
-	return nil
+	wg.Wait()
+
+	if repoErr != nil {
+		return nil, repoErr
+	}
+	return view, nil
+}
+
+func outputStructured(streams *iostreams.IOStreams, format string, repo *api.RepositoryFull) error {
+	return cmdutil.PrintFormatted(streams, format, repo)
 }
 
-func displayRepo(streams *iostreams.IOStreams, repo *api.RepositoryFull) error {
+func displayRepo(streams *iostreams.IOStreams, view *repoView) error {
+	repo := view.repo
+
 	// Header - workspace/repo
 	fmt.Fprintf(streams.Out, "%s\n\n", repo.FullName)
 
@@ -152,6 +211,14 @@ func displayRepo(streams *iostreams.IOStreams, repo *api.RepositoryFull) error {
 		fmt.Fprintf(streams.Out, "Project:     %s\n", repo.Project.Key)
 	}
 
+	// Open pull requests and issues, when they could be counted
+	if view.openPRs >= 0 {
+		fmt.Fprintf(streams.Out, "PRs:         %d open\n", view.openPRs)
+	}
+	if view.openIssues >= 0 && repo.HasIssues {
+		fmt.Fprintf(streams.Out, "Issues:      %d open\n", view.openIssues)
+	}
+
 	// Clone URLs
 	fmt.Fprintln(streams.Out)
 	fmt.Fprintln(streams.Out, "Clone URLs:")
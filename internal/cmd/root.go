@@ -1,24 +1,56 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"os/exec"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
+	"github.com/rbansal42/bitbucket-cli/internal/bugreport"
+	"github.com/rbansal42/bitbucket-cli/internal/cmd/activity"
 	"github.com/rbansal42/bitbucket-cli/internal/cmd/api"
 	"github.com/rbansal42/bitbucket-cli/internal/cmd/auth"
 	"github.com/rbansal42/bitbucket-cli/internal/cmd/branch"
 	"github.com/rbansal42/bitbucket-cli/internal/cmd/browse"
+	"github.com/rbansal42/bitbucket-cli/internal/cmd/commit"
+	"github.com/rbansal42/bitbucket-cli/internal/cmd/compare"
 	"github.com/rbansal42/bitbucket-cli/internal/cmd/completion"
 	bbconfigcmd "github.com/rbansal42/bitbucket-cli/internal/cmd/config"
+	bbcontextcmd "github.com/rbansal42/bitbucket-cli/internal/cmd/context"
+	"github.com/rbansal42/bitbucket-cli/internal/cmd/environment"
+	"github.com/rbansal42/bitbucket-cli/internal/cmd/extension"
+	"github.com/rbansal42/bitbucket-cli/internal/cmd/file"
+	"github.com/rbansal42/bitbucket-cli/internal/cmd/filter"
+	"github.com/rbansal42/bitbucket-cli/internal/cmd/group"
+	"github.com/rbansal42/bitbucket-cli/internal/cmd/hooks"
+	"github.com/rbansal42/bitbucket-cli/internal/cmd/insights"
 	"github.com/rbansal42/bitbucket-cli/internal/cmd/issue"
+	"github.com/rbansal42/bitbucket-cli/internal/cmd/limits"
+	"github.com/rbansal42/bitbucket-cli/internal/cmd/mcp"
 	"github.com/rbansal42/bitbucket-cli/internal/cmd/pipeline"
 	"github.com/rbansal42/bitbucket-cli/internal/cmd/pr"
 	"github.com/rbansal42/bitbucket-cli/internal/cmd/project"
+	"github.com/rbansal42/bitbucket-cli/internal/cmd/release"
 	"github.com/rbansal42/bitbucket-cli/internal/cmd/repo"
 	"github.com/rbansal42/bitbucket-cli/internal/cmd/snippet"
+	"github.com/rbansal42/bitbucket-cli/internal/cmd/upgrade"
+	"github.com/rbansal42/bitbucket-cli/internal/cmd/user"
+	"github.com/rbansal42/bitbucket-cli/internal/cmd/version"
+	"github.com/rbansal42/bitbucket-cli/internal/cmd/webhook"
 	"github.com/rbansal42/bitbucket-cli/internal/cmd/workspace"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+	"github.com/rbansal42/bitbucket-cli/internal/i18n"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+	"github.com/rbansal42/bitbucket-cli/internal/redact"
 )
 
 var (
@@ -47,49 +79,336 @@ Then you can start using commands like:
   bb issue create`,
 	SilenceUsage:  true,
 	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return configureStreams(cmd)
+	},
 }
 
 // streams is the global IOStreams instance
 var streams *iostreams.IOStreams
 
+// apiCalls records API requests when --verbose is set
+var apiCalls *cmdutil.APICallLog
+
 // Execute adds all child commands to the root command and sets flags appropriately.
+// The returned error can be passed to cmdutil.ExitCode to pick the exit code.
 func Execute() error {
 	streams = iostreams.New()
+	cmdutil.SetStreams(streams)
+
+	if path, ok := findExtension(rootCmd, os.Args[1:]); ok {
+		return runExtension(rootCmd, path, os.Args[1:])
+	}
+
+	addCommands(rootCmd, os.Args[1:])
+	registerRepoCompletion(rootCmd)
 
-	err := rootCmd.Execute()
+	setUsageErrors(rootCmd)
+
+	recentCalls := &bugreport.Recorder{}
+	cmdutil.AddAPIClientOption(recentCalls.ClientOption())
+
+	start := time.Now()
+	var stack []byte
+	err := unknownSubcommand(rootCmd, os.Args[1:])
+	if err == nil {
+		stack, err = executeRecovering(rootCmd)
+	}
+	logCommandEnd(start, err, stack)
+	cmdutil.SaveRateLimits()
+	if apiCalls != nil {
+		apiCalls.WriteSummary(streams)
+	}
+	cmdutil.WriteOfflineNotice(streams)
 	if err != nil {
+		err = usageError(cmdutil.ExplainOffline(err))
 		streams.Error("%s", err)
 	}
+	if stack != nil || bugreport.Unexpected(err) {
+		reportBug(bugreport.New(Version, BuildDate, os.Args[1:], err, stack, recentCalls.Calls()))
+	}
 	return err
 }
 
+// logCommandEnd logs how the command went, and closes the log
+func logCommandEnd(start time.Time, err error, stack []byte) {
+	defer cmdutil.StopLogging()
+
+	attrs := []any{"duration_ms", time.Since(start).Milliseconds(), "exit_code", cmdutil.ExitCode(err)}
+	if err == nil {
+		cmdutil.Logger().Info("command finished", attrs...)
+		return
+	}
+	attrs = append(attrs, "error", redact.Text(err.Error()))
+	if stack != nil {
+		attrs = append(attrs, "stack", redact.Text(string(stack)))
+	}
+	cmdutil.Logger().Error("command failed", attrs...)
+}
+
+// executeRecovering runs root, turning a panic into an error returned along
+// with the stack trace of the panic. Panics in other goroutines still crash bb.
+func executeRecovering(root *cobra.Command) (stack []byte, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("bb crashed unexpectedly: %v", p)
+			stack = debug.Stack()
+		}
+	}()
+	return nil, root.Execute()
+}
+
+// reportBug saves report and asks the user to report the bug, with a link
+// to an issue prefilled with the report
+func reportBug(report *bugreport.Report) {
+	fmt.Fprintln(streams.ErrOut)
+	fmt.Fprintln(streams.ErrOut, i18n.T("bugreport.intro"))
+	fmt.Fprintln(streams.ErrOut, streams.Style(iostreams.RoleAccent, bugreport.IssueURL(report)))
+
+	path, err := bugreport.Save(report)
+	if err != nil {
+		streams.Warning("%s", i18n.T("bugreport.save_failed", "Error", err))
+		return
+	}
+	fmt.Fprintln(streams.ErrOut, i18n.T("bugreport.saved", "Path", path))
+	fmt.Fprintln(streams.ErrOut, i18n.T("bugreport.check"))
+}
+
+// setUsageErrors makes invalid flags and arguments of root and its
+// children exit with cmdutil.ExitUsage
+func setUsageErrors(root *cobra.Command) {
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, err)
+	})
+	markUsageErrors(root)
+}
+
+// usageError gives an unknown command, which cobra reports before any
+// validator runs, the exit code of other usage errors
+func usageError(err error) error {
+	var exitErr *cmdutil.ExitError
+	if err != nil && !errors.As(err, &exitErr) && strings.HasPrefix(err.Error(), "unknown command") {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, err)
+	}
+	return err
+}
+
+// markUsageErrors wraps the argument validators of cmd and its children so
+// that invalid arguments exit with cmdutil.ExitUsage.
+func markUsageErrors(cmd *cobra.Command) {
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			if err := validate(cmd, args); err != nil {
+				return cmdutil.NewExitError(cmdutil.ExitUsage, err)
+			}
+			return nil
+		}
+	}
+	for _, child := range cmd.Commands() {
+		markUsageErrors(child)
+	}
+}
+
 func init() {
+	// The version is reported by commands that identify bb to other
+	// programs, such as the MCP server
+	rootCmd.Annotations = map[string]string{"version": Version, "buildDate": BuildDate}
+
+	extension.Reserved = builtinNames()
+
 	// Global flags
 	rootCmd.PersistentFlags().StringP("repo", "R", "", "Select a repository using the WORKSPACE/REPO format")
+	rootCmd.PersistentFlags().String("hostname", "", "Select a Bitbucket host, e.g. a Bitbucket Data Center server")
+	rootCmd.PersistentFlags().String("remote", "", "Select the git remote to detect the repository from")
+	rootCmd.PersistentFlags().Bool("no-pager", false, "Do not pipe long output through a pager")
+	rootCmd.PersistentFlags().String("color", iostreams.ColorAuto, "When to use color: auto, always, or never")
+	rootCmd.PersistentFlags().BoolP("yes", "y", false, "Answer yes to confirmation prompts")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Print only primary output such as URLs and IDs")
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Print a summary of API calls and their timing")
+	rootCmd.PersistentFlags().Bool("offline", false, "Show data cached from earlier runs instead of fetching it")
+	rootCmd.PersistentFlags().String("timestamps", "", "How to show times: relative, absolute, or iso (default from config, else relative)")
+}
 
-	// Version command
-	rootCmd.AddCommand(&cobra.Command{
-		Use:   "version",
-		Short: "Print the version number of bb",
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Printf("bb version %s (%s)\n", Version, BuildDate)
-		},
-	})
+// topLevelCommands build the top-level commands, listed under their names
+// and aliases. Only the commands a command line can reach are built, so
+// running one command, or completing its arguments, doesn't pay for
+// constructing the whole tree.
+var topLevelCommands = []struct {
+	names []string
+	build func(*iostreams.IOStreams) *cobra.Command
+}{
+	{[]string{"activity"}, activity.NewCmdActivity},
+	{[]string{"auth"}, auth.NewCmdAuth},
+	{[]string{"api"}, api.NewCmdAPI},
+	{[]string{"branch", "br"}, branch.NewCmdBranch},
+	{[]string{"completion"}, completion.NewCmdCompletion},
+	{[]string{"browse"}, browse.NewCmdBrowse},
+	{[]string{"commit"}, commit.NewCmdCommit},
+	{[]string{"compare"}, compare.NewCmdCompare},
+	{[]string{"config"}, bbconfigcmd.NewCmdConfig},
+	{[]string{"context", "profile"}, bbcontextcmd.NewCmdContext},
+	{[]string{"environment", "environments", "env"}, environment.NewCmdEnvironment},
+	{[]string{"extension", "extensions", "ext"}, extension.NewCmdExtension},
+	{[]string{"file", "files"}, file.NewCmdFile},
+	{[]string{"filter", "filters"}, filter.NewCmdFilter},
+	{[]string{"group", "groups"}, group.NewCmdGroup},
+	{[]string{"hooks"}, hooks.NewCmdHooks},
+	{[]string{"insights"}, insights.NewCmdInsights},
+	{[]string{"issue", "issues"}, issue.NewCmdIssue},
+	{[]string{"limits"}, limits.NewCmdLimits},
+	{[]string{"mcp"}, mcp.NewCmdMCP},
+	{[]string{"pipeline", "pipelines"}, pipeline.NewCmdPipeline},
+	{[]string{"pr", "pull-request"}, pr.NewCmdPR},
+	{[]string{"project", "proj"}, project.NewCmdProject},
+	{[]string{"release", "releases"}, release.NewCmdRelease},
+	{[]string{"repo", "repository"}, repo.NewCmdRepo},
+	{[]string{"snippet", "snip"}, snippet.NewCmdSnippet},
+	{[]string{"upgrade"}, upgrade.NewCmdUpgrade},
+	{[]string{"user", "users"}, user.NewCmdUser},
+	{[]string{"version"}, version.NewCmdVersion},
+	{[]string{"webhook", "webhooks", "hook"}, webhook.NewCmdWebhook},
+	{[]string{"workspace", "ws"}, workspace.NewCmdWorkspace},
+}
+
+// addCommands adds the top-level commands args can reach to root: the one
+// being run, or all of them when listing or completing command names, or
+// reporting an unknown command.
+func addCommands(root *cobra.Command, args []string) {
+	name, rest := commandName(root, args)
+	switch name {
+	case "help":
+		name, _ = commandName(root, rest)
+	case cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		// The last argument is the word being completed
+		name = ""
+		if len(rest) > 1 {
+			name, _ = commandName(root, rest[:len(rest)-1])
+		}
+	}
 
-	// Add subcommands
-	rootCmd.AddCommand(auth.NewCmdAuth(GetStreams()))
-	rootCmd.AddCommand(api.NewCmdAPI(GetStreams()))
-	rootCmd.AddCommand(branch.NewCmdBranch(GetStreams()))
-	rootCmd.AddCommand(completion.NewCmdCompletion(GetStreams()))
-	rootCmd.AddCommand(browse.NewCmdBrowse(GetStreams()))
-	rootCmd.AddCommand(bbconfigcmd.NewCmdConfig(GetStreams()))
-	rootCmd.AddCommand(issue.NewCmdIssue(GetStreams()))
-	rootCmd.AddCommand(pipeline.NewCmdPipeline(GetStreams()))
-	rootCmd.AddCommand(pr.NewCmdPR(GetStreams()))
-	rootCmd.AddCommand(project.NewCmdProject(GetStreams()))
-	rootCmd.AddCommand(repo.NewCmdRepo(GetStreams()))
-	rootCmd.AddCommand(snippet.NewCmdSnippet(GetStreams()))
-	rootCmd.AddCommand(workspace.NewCmdWorkspace(GetStreams()))
+	for _, c := range topLevelCommands {
+		if slices.Contains(c.names, name) {
+			root.AddCommand(c.build(GetStreams()))
+			return
+		}
+	}
+	for _, c := range topLevelCommands {
+		root.AddCommand(c.build(GetStreams()))
+	}
+}
+
+// commandName returns the first argument that is not a global flag or its
+// value, and the arguments after it
+func commandName(root *cobra.Command, args []string) (string, []string) {
+	return firstArg(root.PersistentFlags(), args)
+}
+
+// firstArg returns the first argument that is not one of flags or its
+// value, and the arguments after it
+func firstArg(flags *pflag.FlagSet, args []string) (string, []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return "", nil
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			return arg, args[i+1:]
+		}
+		if strings.Contains(arg, "=") {
+			continue
+		}
+		// Skip the value of flags that take one, unless it is attached as
+		// in -Rworkspace/repo
+		flag := flags.Lookup(strings.TrimPrefix(arg, "--"))
+		if !strings.HasPrefix(arg, "--") {
+			flag = nil
+			if len(arg) == 2 {
+				flag = flags.ShorthandLookup(arg[1:])
+			}
+		}
+		if flag != nil && flag.NoOptDefVal == "" {
+			i++
+		}
+	}
+	return "", nil
+}
+
+// unknownSubcommand returns a usage error when args run a command group,
+// such as pr, with an argument that isn't one of its commands. cobra only
+// rejects those for the root command; for a group it shows the help and
+// succeeds, which scripts would take for success.
+func unknownSubcommand(root *cobra.Command, args []string) error {
+	cmd, rest, err := root.Find(args)
+	if err != nil || cmd == root || cmd.Runnable() || !cmd.HasSubCommands() {
+		return nil
+	}
+	// InheritedFlags merges the global flags into cmd.Flags
+	cmd.InheritedFlags()
+	if name, _ := firstArg(cmd.Flags(), rest); name != "" {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("unknown command %q for %q", name, cmd.CommandPath()))
+	}
+	return nil
+}
+
+// builtinNames are the names of the top-level commands bb defines itself,
+// which take precedence over extensions
+func builtinNames() []string {
+	names := []string{"help", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd}
+	for _, c := range topLevelCommands {
+		names = append(names, c.names...)
+	}
+	return names
+}
+
+// findExtension returns the executable of the extension args run, if the
+// command they name isn't a built-in one
+func findExtension(root *cobra.Command, args []string) (string, bool) {
+	name, _ := commandName(root, args)
+	if name == "" || slices.Contains(builtinNames(), name) {
+		return "", false
+	}
+	return extension.Find(name)
+}
+
+// runExtension runs the extension at path with the arguments after its
+// name. The global flags before the name select the repository and host,
+// which are passed on in the environment.
+func runExtension(root *cobra.Command, path string, args []string) error {
+	_, rest := commandName(root, args)
+	flags := root.PersistentFlags()
+	if err := flags.Parse(args[:len(args)-len(rest)-1]); err != nil {
+		err = cmdutil.NewExitError(cmdutil.ExitUsage, err)
+		streams.Error("%s", err)
+		return err
+	}
+	for flag, env := range map[string]string{
+		"repo":     cmdutil.RepoEnvVar,
+		"hostname": config.HostEnvVar,
+		"remote":   git.RemoteEnvVar,
+		"offline":  cmdutil.OfflineEnvVar,
+	} {
+		if f := flags.Lookup(flag); f.Changed {
+			os.Setenv(env, f.Value.String())
+		}
+	}
+
+	err := extension.Run(path, rest, streams)
+	// The extension reports its own errors
+	if err != nil && !errors.As(err, new(*exec.ExitError)) {
+		streams.Error("%s", err)
+	}
+	return err
+}
+
+// registerRepoCompletion completes repository names for the --repo flag of
+// cmd and of every subcommand that defines its own
+func registerRepoCompletion(cmd *cobra.Command) {
+	if f := cmd.LocalFlags().Lookup("repo"); f != nil && f.Value.Type() == "string" {
+		_ = cmd.RegisterFlagCompletionFunc("repo", cmdutil.CompleteRepos)
+	}
+	for _, child := range cmd.Commands() {
+		registerRepoCompletion(child)
+	}
 }
 
 // GetStreams returns the global IOStreams instance
@@ -99,3 +418,109 @@ func GetStreams() *iostreams.IOStreams {
 	}
 	return streams
 }
+
+// configureStreams applies the global output flags and config settings to
+// the shared IOStreams before any command runs.
+func configureStreams(cmd *cobra.Command) error {
+	// The auth commands define their own --hostname, which shadows this one
+	if f := cmd.InheritedFlags().Lookup("hostname"); f != nil && f.Changed {
+		config.SelectHost(f.Value.String())
+	}
+	// repo set-default defines its own --remote, which records the choice
+	if f := cmd.InheritedFlags().Lookup("remote"); f != nil && f.Changed {
+		os.Setenv(git.RemoteEnvVar, f.Value.String())
+	}
+	registerServerHosts()
+
+	cfg := &config.Config{}
+	if resolver, err := config.Resolve(); err == nil {
+		cfg = resolver.Config()
+	}
+	// A broken config file should not stop commands that don't need it;
+	// commands that do will report the error themselves.
+
+	i18n.SetLocale(cfg.Locale)
+
+	s := GetStreams()
+	s.SetAccessible(cfg.Accessible)
+	configurePager(cmd, s, cfg)
+
+	// The log is for looking into problems, so failing to open it
+	// shouldn't cause one
+	if err := cmdutil.StartLogging(cfg.LogLevel); err != nil {
+		s.Warning("%s; not logging", err)
+	}
+	cmdutil.Logger().Info("command started", "command", cmd.CommandPath(),
+		"args", redact.Args(os.Args[1:]), "version", Version)
+
+	colorMode, _ := cmd.Flags().GetString("color")
+	if err := s.SetColorMode(colorMode); err != nil {
+		return err
+	}
+	if err := s.SetTheme(cfg.Theme); err != nil {
+		s.Warning("%s; using the default theme", err)
+	}
+	if err := s.SetIcons(cfg.Icons); err != nil {
+		s.Warning("%s; icons are disabled", err)
+	}
+
+	if cmd.Flags().Changed("timestamps") {
+		style, _ := cmd.Flags().GetString("timestamps")
+		if err := s.SetTimestampStyle(style); err != nil {
+			return cmdutil.NewExitError(cmdutil.ExitUsage, err)
+		}
+	} else if err := s.SetTimestampStyle(cfg.Timestamps); err != nil {
+		s.Warning("%s; using relative times", err)
+	}
+
+	s.SetNeverPrompt(cfg.Prompt == "disabled")
+	if s.CanPrompt() {
+		config.PassphrasePrompt = s.PromptSecret
+	}
+	if yes, _ := cmd.Flags().GetBool("yes"); yes {
+		s.SetAssumeYes(true)
+	}
+	if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+		s.SetQuiet(true)
+	}
+	if offline, _ := cmd.Flags().GetBool("offline"); offline || cmdutil.OfflineFromEnv() {
+		cmdutil.SetOffline(true)
+	}
+	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose && apiCalls == nil {
+		apiCalls = cmdutil.NewAPICallLog()
+	}
+	return nil
+}
+
+// configurePager uses the resolved pager setting, which includes BB_PAGER,
+// falling back to PAGER and then less.
+func configurePager(cmd *cobra.Command, s *iostreams.IOStreams, cfg *config.Config) {
+	if noPager, _ := cmd.Flags().GetBool("no-pager"); noPager {
+		s.DisablePager()
+		return
+	}
+
+	pager := cfg.Pager
+	if pager == "" {
+		pager = os.Getenv("PAGER")
+	}
+	if pager == "" {
+		pager = "less"
+	}
+	s.SetPager(pager)
+}
+
+// registerServerHosts lets the git package recognize the remote URLs of the
+// Bitbucket Server hosts in hosts.yml and of the host selected by BB_HOST.
+func registerServerHosts() {
+	var hosts []string
+	if hostsConfig, err := config.LoadHostsConfig(); err == nil {
+		for host := range hostsConfig {
+			hosts = append(hosts, host)
+		}
+	}
+	if host := config.HostOverride(); host != "" {
+		hosts = append(hosts, host)
+	}
+	git.SetServerHosts(hosts)
+}
@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+func TestTopLevelCommands_Names(t *testing.T) {
+	seen := map[string]bool{}
+	for _, c := range topLevelCommands {
+		cmd := c.build(&iostreams.IOStreams{})
+		want := append([]string{cmd.Name()}, cmd.Aliases...)
+		if !slices.Equal(c.names, want) {
+			t.Errorf("topLevelCommands lists %q, but the command is named %q", c.names, want)
+		}
+		for _, name := range c.names {
+			if seen[name] {
+				t.Errorf("%q is listed twice", name)
+			}
+			seen[name] = true
+		}
+	}
+}
+
+func TestAddCommands_BuildsTree(t *testing.T) {
+	root := &cobra.Command{Use: "bb"}
+	addCommands(root, nil)
+
+	if got, want := len(root.Commands()), len(topLevelCommands); got != want {
+		t.Errorf("root has %d commands, want %d", got, want)
+	}
+	var walk func(*cobra.Command)
+	walk = func(c *cobra.Command) {
+		// Flag groups are checked when the flags are defined; make sure
+		// every command's are valid by exercising them
+		if err := c.ValidateFlagGroups(); err != nil {
+			t.Errorf("%s: %v", c.CommandPath(), err)
+		}
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(root)
+}
+
+func TestAddCommands(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"command", []string{"pr", "list"}, []string{"pr"}},
+		{"alias", []string{"pull-request", "list"}, []string{"pr"}},
+		{"after global flags", []string{"-R", "ws/repo", "issue", "list"}, []string{"issue"}},
+		{"help for a command", []string{"help", "pipeline"}, []string{"pipeline"}},
+		{"completing a command's arguments", []string{cobra.ShellCompRequestCmd, "repo", "vi"}, []string{"repo"}},
+		{"completing command names", []string{cobra.ShellCompRequestCmd, "p"}, nil},
+		{"help", []string{"help"}, nil},
+		{"no arguments", nil, nil},
+		{"unknown command", []string{"nope"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := &cobra.Command{Use: "bb"}
+			root.PersistentFlags().AddFlagSet(rootCmd.PersistentFlags())
+			addCommands(root, tt.args)
+
+			var got []string
+			for _, c := range root.Commands() {
+				got = append(got, c.Name())
+			}
+			if tt.want == nil {
+				if len(got) != len(topLevelCommands) {
+					t.Errorf("built %d commands, want all %d", len(got), len(topLevelCommands))
+				}
+				return
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("built %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandName(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantName string
+		wantRest []string
+	}{
+		{"command", []string{"pr", "list", "--state", "open"}, "pr", []string{"list", "--state", "open"}},
+		{"long flag with a value", []string{"--repo", "ws/repo", "pr", "list"}, "pr", []string{"list"}},
+		{"short flag with a value", []string{"-R", "ws/repo", "pr"}, "pr", []string{}},
+		{"attached short value", []string{"-Rws/repo", "pr"}, "pr", []string{}},
+		{"flag=value", []string{"--repo=ws/repo", "--color=never", "issue"}, "issue", []string{}},
+		{"boolean flags", []string{"--quiet", "-y", "--no-pager", "repo", "view"}, "repo", []string{"view"}},
+		{"several flags with values", []string{"--hostname", "bb.example.com", "--color", "never", "api", "/user"}, "api", []string{"/user"}},
+		{"stdin dash", []string{"-", "pr"}, "-", []string{"pr"}},
+		{"end of flags", []string{"--", "pr"}, "", nil},
+		{"only flags", []string{"--repo", "ws/repo"}, "", nil},
+		{"no arguments", nil, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, rest := commandName(rootCmd, tt.args)
+			if name != tt.wantName {
+				t.Errorf("commandName() name = %q, want %q", name, tt.wantName)
+			}
+			if len(rest) != len(tt.wantRest) || !slices.Equal(rest, tt.wantRest) {
+				t.Errorf("commandName() rest = %q, want %q", rest, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestUsageErrorsExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"unknown flag", []string{"pr", "list", "--bogus"}},
+		{"unknown shorthand", []string{"issue", "list", "-Z"}},
+		{"bad flag value", []string{"pr", "list", "--limit", "many"}},
+		{"too many arguments", []string{"pr", "view", "1", "2"}},
+		{"unknown command", []string{"nope"}},
+		{"unknown subcommand", []string{"pr", "nope"}},
+		{"unknown subcommand after flags", []string{"-R", "ws/repo", "pr", "--repo", "ws/repo", "nope"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := &cobra.Command{Use: "bb", SilenceUsage: true, SilenceErrors: true}
+			root.PersistentFlags().AddFlagSet(rootCmd.PersistentFlags())
+			addCommands(root, tt.args)
+			setUsageErrors(root)
+			root.SetArgs(tt.args)
+			root.SetOut(io.Discard)
+			root.SetErr(io.Discard)
+
+			err := unknownSubcommand(root, tt.args)
+			if err == nil {
+				err = usageError(root.Execute())
+			}
+			if got := cmdutil.ExitCode(err); got != cmdutil.ExitUsage {
+				t.Errorf("exit code = %d (error %v), want %d", got, err, cmdutil.ExitUsage)
+			}
+		})
+	}
+}
+
+func TestUnknownSubcommand_AllowsGroups(t *testing.T) {
+	for _, args := range [][]string{
+		{"pr"},
+		{"pr", "--repo", "ws/repo"},
+		{"pr", "list"},
+		{"-R", "ws/repo", "pr", "list", "extra"},
+		{"help", "pr"},
+		{cobra.ShellCompRequestCmd, "pr", ""},
+	} {
+		root := &cobra.Command{Use: "bb"}
+		root.PersistentFlags().AddFlagSet(rootCmd.PersistentFlags())
+		addCommands(root, args)
+		if err := unknownSubcommand(root, args); err != nil {
+			t.Errorf("unknownSubcommand(%q) = %v, want nil", args, err)
+		}
+	}
+}
+
+func TestListWeb(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/team/api" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"full_name": "team/api", "links": {"html": {"href": "https://bitbucket.example.com/team/api"}}}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	hosts := "bitbucket.example.com:\n  user: alice\n  api_url: " + server.URL + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte(hosts), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BB_CONFIG_DIR", dir)
+	t.Setenv("BB_HOST", "bitbucket.example.com")
+	t.Setenv("BB_TOKEN", "token")
+	// The browser is a command that does nothing, so only the URL is seen
+	t.Setenv("BB_BROWSER", "true")
+
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{"issue", "https://bitbucket.example.com/team/api/issues"},
+		{"pr", "https://bitbucket.example.com/team/api/pull-requests"},
+		{"pipeline", "https://bitbucket.example.com/team/api/pipelines"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			var out bytes.Buffer
+			streams := &iostreams.IOStreams{Out: &out, ErrOut: &out}
+			root := &cobra.Command{Use: "bb", SilenceUsage: true, SilenceErrors: true}
+			for _, c := range topLevelCommands {
+				if c.names[0] == tt.command {
+					root.AddCommand(c.build(streams))
+				}
+			}
+			root.SetArgs([]string{tt.command, "list", "--web", "--repo", "team/api"})
+
+			if err := root.Execute(); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if !strings.Contains(out.String(), "Opened "+tt.want+" in your browser") {
+				t.Errorf("output = %q, want it to open %s", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigurePager(t *testing.T) {
+	tests := []struct {
+		name    string
+		bbPager string
+		config  string
+		pager   string
+		noPager bool
+		want    string
+	}{
+		{name: "BB_PAGER first", bbPager: "more", config: "most", pager: "pg", want: "more"},
+		{name: "then the config", config: "most", pager: "pg", want: "most"},
+		{name: "then PAGER", pager: "pg", want: "pg"},
+		{name: "then less", want: "less"},
+		{name: "BB_PAGER turns paging off", bbPager: "cat", pager: "pg", want: "cat"},
+		{name: "--no-pager", bbPager: "more", noPager: true, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BB_CONFIG_DIR", t.TempDir())
+			t.Setenv("BB_PAGER", tt.bbPager)
+			t.Setenv("PAGER", tt.pager)
+			if err := config.SaveConfig(&config.Config{Pager: tt.config}); err != nil {
+				t.Fatal(err)
+			}
+			resolver, err := config.Resolve()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cmd := &cobra.Command{Use: "bb"}
+			cmd.Flags().AddFlagSet(rootCmd.PersistentFlags())
+			if tt.noPager {
+				cmd.Flags().Set("no-pager", "true")
+			}
+			s := &iostreams.IOStreams{}
+			configurePager(cmd, s, resolver.Config())
+			if got := s.GetPager(); got != tt.want {
+				t.Errorf("pager = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
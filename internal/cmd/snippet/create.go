@@ -25,6 +25,7 @@ type CreateOptions struct {
 	Files     []string // File paths to include
 	Streams   *iostreams.IOStreams
 	JSON      bool
+	Copy      bool
 }
 
 // NewCmdCreate creates the snippet create command
@@ -56,6 +57,7 @@ If no files are specified, reads from stdin.`,
 	cmd.Flags().BoolVarP(&opts.Private, "private", "p", false, "Make snippet private")
 	cmd.Flags().StringArrayVarP(&opts.Files, "file", "f", nil, "File to include (can be repeated)")
 	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddCopyFlag(cmd, &opts.Copy, "created snippet")
 
 	cmd.MarkFlagRequired("title")
 
@@ -124,13 +126,24 @@ func runCreate(ctx context.Context, opts *CreateOptions) error {
 		return fmt.Errorf("failed to create snippet: %w", err)
 	}
 
+	if opts.Copy {
+		if err := cmdutil.CopyURL(opts.Streams, snippet.Links.HTML.Href); err != nil {
+			opts.Streams.Warning("%s", err)
+		}
+	}
+
 	// Output result
 	if opts.JSON {
 		return outputCreateJSON(opts.Streams, snippet)
 	}
 
 	opts.Streams.Success("Created snippet %d in workspace %s", snippet.ID, opts.Workspace)
-	if snippet.Links.HTML.Href != "" {
+	switch {
+	case opts.Streams.IsQuiet() && snippet.Links.HTML.Href != "":
+		fmt.Fprintln(opts.Streams.Out, snippet.Links.HTML.Href)
+	case opts.Streams.IsQuiet():
+		fmt.Fprintln(opts.Streams.Out, snippet.ID)
+	case snippet.Links.HTML.Href != "":
 		fmt.Fprintf(opts.Streams.Out, "URL: %s\n", snippet.Links.HTML.Href)
 	}
 
@@ -3,7 +3,6 @@ package snippet
 import (
 	"context"
 	"fmt"
-	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -20,6 +19,7 @@ type ListOptions struct {
 	Role      string // owner, contributor, member
 	Limit     int
 	JSON      bool
+	Format    string
 	Streams   *iostreams.IOStreams
 }
 
@@ -45,7 +45,10 @@ Snippets are workspace-scoped and can be filtered by your role.`,
   bb snippet list --workspace myworkspace --limit 10
 
   # Output as JSON
-  bb snippet list --workspace myworkspace --json`,
+  bb snippet list --workspace myworkspace --json
+
+  # Output as YAML
+  bb snippet list --workspace myworkspace --format yaml`,
 		Aliases: []string{"ls"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runList(cmd.Context(), opts)
@@ -56,6 +59,7 @@ Snippets are workspace-scoped and can be filtered by your role.`,
 	cmd.Flags().StringVar(&opts.Role, "role", "", "Filter by role: owner, contributor, member")
 	cmd.Flags().IntVarP(&opts.Limit, "limit", "l", 30, "Maximum number of snippets to list")
 	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
 
 	return cmd
 }
@@ -106,7 +110,9 @@ func runList(ctx context.Context, opts *ListOptions) error {
 	}
 
 	// Fetch snippets
+	progress := opts.Streams.StartProgress("Fetching snippets")
 	result, err := client.ListSnippets(ctx, opts.Workspace, listOpts)
+	progress.Stop()
 	if err != nil {
 		return fmt.Errorf("failed to list snippets: %w", err)
 	}
@@ -116,16 +122,21 @@ func runList(ctx context.Context, opts *ListOptions) error {
 		return nil
 	}
 
+	if err := opts.Streams.StartPager(); err != nil {
+		opts.Streams.Warning("%s", err)
+	}
+	defer opts.Streams.StopPager()
+
 	// Output results
-	if opts.JSON {
-		return outputListJSON(opts.Streams, result.Values)
+	if opts.JSON || opts.Format != "" {
+		return outputListStructured(opts.Streams, opts.Format, result.Values)
 	}
 
 	return outputListTable(opts.Streams, result.Values)
 }
 
-func outputListJSON(streams *iostreams.IOStreams, snippets []api.Snippet) error {
-	// Create simplified JSON output
+func outputListStructured(streams *iostreams.IOStreams, format string, snippets []api.Snippet) error {
+	// Create simplified output
 	output := make([]map[string]interface{}, len(snippets))
 	for i, snippet := range snippets {
 		output[i] = map[string]interface{}{
@@ -139,20 +150,19 @@ func outputListJSON(streams *iostreams.IOStreams, snippets []api.Snippet) error
 		}
 	}
 
-	return cmdutil.PrintJSON(streams, output)
+	return cmdutil.PrintFormatted(streams, format, output)
 }
 
 func outputListTable(streams *iostreams.IOStreams, snippets []api.Snippet) error {
-	w := tabwriter.NewWriter(streams.Out, 0, 0, 2, ' ', 0)
+	table := cmdutil.NewTablePrinter(streams)
 
 	// Print header
-	header := "ID\tTITLE\tVISIBILITY\tUPDATED"
-	cmdutil.PrintTableHeader(streams, w, header)
+	table.AddHeader("ID", "TITLE", "VISIBILITY", "UPDATED")
 
 	// Print rows
 	for _, snippet := range snippets {
 		id := fmt.Sprintf("%d", snippet.ID)
-		title := cmdutil.TruncateString(snippet.Title, 40)
+		title := snippet.Title
 		if title == "" {
 			title = "(untitled)"
 		}
@@ -162,10 +172,10 @@ func outputListTable(streams *iostreams.IOStreams, snippets []api.Snippet) error
 			visibility = "private"
 		}
 
-		updated := cmdutil.TimeAgoFromString(snippet.UpdatedOn)
+		updated := cmdutil.FormatTimeString(streams, snippet.UpdatedOn)
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", id, title, visibility, updated)
+		table.AddRow(id, title, visibility, updated)
 	}
 
-	return w.Flush()
+	return table.Render()
 }
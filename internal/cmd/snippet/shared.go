@@ -4,4 +4,4 @@ package snippet
 // This package uses shared utilities from cmdutil for:
 // - cmdutil.GetAPIClient() - authenticated API client
 // - cmdutil.ParseWorkspace() - workspace validation
-// - cmdutil.TruncateString() - string truncation
+// - cmdutil.NewTablePrinter() - table output
@@ -2,7 +2,6 @@ package snippet
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -11,7 +10,6 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/rbansal42/bitbucket-cli/internal/api"
-	"github.com/rbansal42/bitbucket-cli/internal/browser"
 	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
 	"github.com/rbansal42/bitbucket-cli/internal/config"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
@@ -22,7 +20,9 @@ type ViewOptions struct {
 	Workspace string
 	SnippetID string
 	Web       bool
+	Copy      bool
 	JSON      bool
+	Format    string
 	Raw       bool // Show raw file content
 	Streams   *iostreams.IOStreams
 }
@@ -47,7 +47,10 @@ By default, shows snippet metadata. Use --raw to view file contents.`,
   bb snippet view abc123 --workspace myworkspace --web
 
   # Output as JSON
-  bb snippet view abc123 --workspace myworkspace --json`,
+  bb snippet view abc123 --workspace myworkspace --json
+
+  # Output as YAML
+  bb snippet view abc123 --workspace myworkspace --format yaml`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.SnippetID = args[0]
@@ -56,8 +59,10 @@ By default, shows snippet metadata. Use --raw to view file contents.`,
 	}
 
 	cmd.Flags().StringVarP(&opts.Workspace, "workspace", "w", "", "Workspace slug (uses default workspace if not specified)")
-	cmd.Flags().BoolVar(&opts.Web, "web", false, "Open snippet in browser")
+	cmdutil.AddWebFlag(cmd, &opts.Web, "snippet")
+	cmdutil.AddCopyFlag(cmd, &opts.Copy, "snippet")
 	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
 	cmd.Flags().BoolVar(&opts.Raw, "raw", false, "Show raw file contents")
 
 	return cmd
@@ -96,22 +101,19 @@ func runView(ctx context.Context, opts *ViewOptions) error {
 		return fmt.Errorf("failed to get snippet: %w", err)
 	}
 
-	// Open in browser
-	if opts.Web {
-		url := snippet.Links.HTML.Href
-		if url == "" {
-			return fmt.Errorf("no URL available for this snippet")
-		}
-		if err := browser.Open(url); err != nil {
-			return fmt.Errorf("could not open browser: %w", err)
+	// Copy the URL or open in browser
+	if opts.Copy {
+		if err := cmdutil.CopyURL(opts.Streams, snippet.Links.HTML.Href); err != nil {
+			return err
 		}
-		opts.Streams.Success("Opened %s in your browser", url)
-		return nil
+	}
+	if opts.Web {
+		return cmdutil.OpenInBrowser(opts.Streams, snippet.Links.HTML.Href)
 	}
 
 	// JSON output
-	if opts.JSON {
-		return outputViewJSON(opts.Streams, snippet)
+	if opts.JSON || opts.Format != "" {
+		return outputViewStructured(opts.Streams, opts.Format, snippet)
 	}
 
 	// Raw file contents
@@ -123,13 +125,8 @@ func runView(ctx context.Context, opts *ViewOptions) error {
 	return outputSnippetDetails(opts.Streams, snippet)
 }
 
-func outputViewJSON(streams *iostreams.IOStreams, snippet *api.Snippet) error {
-	data, err := json.MarshalIndent(snippet, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
-	}
-	fmt.Fprintln(streams.Out, string(data))
-	return nil
+func outputViewStructured(streams *iostreams.IOStreams, format string, snippet *api.Snippet) error {
+	return cmdutil.PrintFormatted(streams, format, snippet)
 }
 
 func outputSnippetDetails(streams *iostreams.IOStreams, snippet *api.Snippet) error {
@@ -0,0 +1,155 @@
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+	"github.com/rbansal42/bitbucket-cli/internal/update"
+)
+
+// UpgradeOptions holds the options for the upgrade command
+type UpgradeOptions struct {
+	Version string
+	Check   bool
+	Force   bool
+	Streams *iostreams.IOStreams
+}
+
+// NewCmdUpgrade creates the upgrade command
+func NewCmdUpgrade(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &UpgradeOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade bb to the latest release",
+		Long: `Download the latest release of bb for this platform and replace the
+running executable with it.
+
+The download is checked against the SHA-256 checksums published with the
+release before anything is replaced, and the new executable is moved into
+place in one step, so an interrupted upgrade leaves the old one working.
+
+If bb was installed with a package manager such as Homebrew, it is left
+for the package manager to upgrade; use --force to replace it anyway.`,
+		Example: `  # Upgrade to the latest release
+  bb upgrade
+
+  # Only report whether a newer release is available
+  bb upgrade --check`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Version = cmd.Root().Annotations["version"]
+			return runUpgrade(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Check, "check", false, "Only check whether a newer release is available")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "Replace the executable even if it is up to date, a development build, or managed by a package manager")
+	cmd.MarkFlagsMutuallyExclusive("check", "force")
+
+	return cmd
+}
+
+func runUpgrade(ctx context.Context, opts *UpgradeOptions) error {
+	exe, err := executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the bb executable: %w", err)
+	}
+	if pm, ok := update.DetectPackageManager(exe); ok && !opts.Force {
+		fmt.Fprintf(opts.Streams.Out, "bb was installed with %s; upgrade it with:\n  %s\n", pm.Name, pm.Command)
+		return nil
+	}
+
+	progress := opts.Streams.StartProgress("Looking up the latest release")
+	release, err := update.Latest(ctx)
+	progress.Stop()
+	if err != nil {
+		return err
+	}
+
+	current, isRelease := update.ParseVersion(opts.Version)
+	latest, ok := update.ParseVersion(release.Version)
+	if !ok {
+		return fmt.Errorf("the latest release has an unexpected version %q", release.Version)
+	}
+	newer := !isRelease || latest.NewerThan(current)
+
+	if opts.Check {
+		if isRelease && !newer {
+			fmt.Fprintf(opts.Streams.Out, "bb %s is the latest release\n", opts.Version)
+			return nil
+		}
+		fmt.Fprintf(opts.Streams.Out, "%s %s → %s\n",
+			opts.Streams.Style(iostreams.RoleWarning, "A new release of bb is available:"),
+			opts.Version, release.Version)
+		fmt.Fprintln(opts.Streams.Out, release.URL)
+		return nil
+	}
+	if !isRelease && !opts.Force {
+		return fmt.Errorf("bb %s is a development build; use --force to replace it with release %s", opts.Version, release.Version)
+	}
+	if !newer && !opts.Force {
+		fmt.Fprintf(opts.Streams.Out, "bb %s is already the latest release\n", opts.Version)
+		return nil
+	}
+
+	binary, err := downloadRelease(ctx, opts.Streams, release)
+	if err != nil {
+		return err
+	}
+	if err := update.Replace(exe, binary); err != nil {
+		if errors.Is(err, os.ErrPermission) {
+			return fmt.Errorf("failed to replace %s: permission denied; run bb upgrade as a user who can write to %s", exe, filepath.Dir(exe))
+		}
+		return fmt.Errorf("failed to replace %s: %w", exe, err)
+	}
+
+	opts.Streams.Success("Upgraded bb from %s to %s", opts.Version, release.Version)
+	return nil
+}
+
+// downloadRelease downloads the archive of release for this platform,
+// verifies its checksum and returns the executable in it
+func downloadRelease(ctx context.Context, streams *iostreams.IOStreams, release *update.Release) ([]byte, error) {
+	name := update.ArchiveName(release.Version, runtime.GOOS, runtime.GOARCH)
+	archiveAsset, ok := release.Asset(name)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no build for %s/%s", release.Version, runtime.GOOS, runtime.GOARCH)
+	}
+	checksumsAsset, ok := release.Asset(update.ChecksumsAsset)
+	if !ok {
+		return nil, fmt.Errorf("release %s publishes no checksums, so the download can't be verified", release.Version)
+	}
+
+	progress := streams.StartProgress(fmt.Sprintf("Downloading bb %s", release.Version))
+	defer progress.Stop()
+	checksums, err := update.Download(ctx, checksumsAsset.URL)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := update.Download(ctx, archiveAsset.URL)
+	if err != nil {
+		return nil, err
+	}
+	if err := update.VerifyChecksum(checksums, name, archive); err != nil {
+		return nil, err
+	}
+	return update.ExtractBinary(archive, name)
+}
+
+// executable returns the path of the running executable, with symlinks
+// resolved so a link in a bin directory isn't replaced by a copy
+func executable() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(exe)
+}
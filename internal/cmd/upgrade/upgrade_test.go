@@ -0,0 +1,122 @@
+package upgrade
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+	"github.com/rbansal42/bitbucket-cli/internal/update"
+)
+
+// releaseArchive builds a release archive for this platform holding binary
+func releaseArchive(t *testing.T, binary string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if runtime.GOOS == "windows" {
+		zw := zip.NewWriter(&buf)
+		w, _ := zw.Create("bb.exe")
+		w.Write([]byte(binary))
+		zw.Close()
+		return buf.Bytes()
+	}
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "bb", Mode: 0o755, Size: int64(len(binary)), Typeflag: tar.TypeReg})
+	tw.Write([]byte(binary))
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func TestDownloadRelease(t *testing.T) {
+	name := update.ArchiveName("1.3.0", runtime.GOOS, runtime.GOARCH)
+	archive := releaseArchive(t, "new bb")
+	sum := sha256.Sum256(archive)
+
+	tests := []struct {
+		name      string
+		checksums string
+		wantErr   string
+	}{
+		{"verified", fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), name), ""},
+		{"tampered", fmt.Sprintf("%s  %s\n", strings.Repeat("0", 64), name), "checksum mismatch"},
+		{"unlisted", "", "no checksum listed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/checksums.txt":
+					w.Write([]byte(tt.checksums))
+				case "/" + name:
+					w.Write(archive)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			release := &update.Release{Version: "1.3.0", Assets: []update.Asset{
+				{Name: update.ChecksumsAsset, URL: server.URL + "/checksums.txt"},
+				{Name: name, URL: server.URL + "/" + name},
+			}}
+			var out bytes.Buffer
+			streams := &iostreams.IOStreams{Out: &out, ErrOut: &out}
+			binary, err := downloadRelease(context.Background(), streams, release)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("downloadRelease() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("downloadRelease() error = %v", err)
+			}
+			if string(binary) != "new bb" {
+				t.Errorf("binary = %q, want %q", binary, "new bb")
+			}
+		})
+	}
+}
+
+func TestDownloadRelease_NoBuild(t *testing.T) {
+	var out bytes.Buffer
+	streams := &iostreams.IOStreams{Out: &out, ErrOut: &out}
+	release := &update.Release{Version: "1.3.0", Assets: []update.Asset{{Name: update.ChecksumsAsset}}}
+	if _, err := downloadRelease(context.Background(), streams, release); err == nil || !strings.Contains(err.Error(), "no build for") {
+		t.Errorf("downloadRelease() error = %v, want no build", err)
+	}
+}
+
+func TestRunUpgrade_DevelopmentBuild(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name": "v1.3.0", "html_url": "https://example.com/v1.3.0"}`))
+	}))
+	defer server.Close()
+	t.Setenv("GITHUB_API_URL", server.URL)
+	t.Setenv("BB_CACHE_DIR", t.TempDir())
+
+	var out bytes.Buffer
+	streams := &iostreams.IOStreams{Out: &out, ErrOut: &out}
+	err := runUpgrade(context.Background(), &UpgradeOptions{Version: "dev", Streams: streams})
+	if err == nil || !strings.Contains(err.Error(), "development build") {
+		t.Errorf("runUpgrade() error = %v, want a development build error", err)
+	}
+
+	out.Reset()
+	err = runUpgrade(context.Background(), &UpgradeOptions{Version: "dev", Check: true, Streams: streams})
+	if err != nil || !strings.Contains(out.String(), "dev → 1.3.0") {
+		t.Errorf("runUpgrade(--check) = %v, output %q", err, out.String())
+	}
+}
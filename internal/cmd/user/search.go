@@ -0,0 +1,186 @@
+package user
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// SearchOptions holds the options for the search command
+type SearchOptions struct {
+	Query     string
+	Workspace string
+	Limit     int
+	JSON      bool
+	Format    string
+	Streams   *iostreams.IOStreams
+}
+
+// NewCmdSearch creates the user search command
+func NewCmdSearch(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &SearchOptions{
+		Streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Find workspace members by name",
+		Long: `Find the members of a workspace whose nickname, username or display name
+contains the query, ignoring case, and show their UUIDs and account IDs.
+Exact matches are listed first.
+
+The workspace is given with --workspace, or else is the default
+workspace or that of the current repository.`,
+		Example: `  # Find members named like "smith"
+  bb user search smith
+
+  # Search another workspace
+  bb user search "Jane" --workspace myworkspace
+
+  # Get the UUID of a member for a script
+  bb user search jsmith --json | jq -r '.[0].uuid'`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Query = args[0]
+			return runSearch(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Workspace, "workspace", "w", "", "Workspace to search the members of")
+	cmd.Flags().IntVarP(&opts.Limit, "limit", "l", 30, "Maximum number of users to list")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
+
+	return cmd
+}
+
+func runSearch(ctx context.Context, opts *SearchOptions) error {
+	workspace := searchWorkspace(opts.Workspace)
+	if workspace == "" {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("no workspace to search; use --workspace"))
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	progress := opts.Streams.StartProgress("Searching members")
+	var members []api.WorkspaceMember
+	for page := 1; ; page++ {
+		result, err := client.ListWorkspaceMembers(ctx, workspace, &api.WorkspaceMemberListOptions{Page: page, Limit: 100})
+		if err != nil {
+			progress.Stop()
+			return fmt.Errorf("failed to list workspace members: %w", err)
+		}
+		members = append(members, result.Values...)
+		if result.Next == "" || len(result.Values) == 0 {
+			break
+		}
+	}
+	progress.Stop()
+
+	matches := matchMembers(members, opts.Query)
+	if len(matches) > opts.Limit {
+		matches = matches[:opts.Limit]
+	}
+
+	if opts.JSON || opts.Format != "" {
+		output := make([]map[string]interface{}, len(matches))
+		for i, m := range matches {
+			output[i] = map[string]interface{}{
+				"display_name": m.User.DisplayName,
+				"nickname":     m.User.Nickname,
+				"username":     m.User.Username,
+				"uuid":         m.User.UUID,
+				"account_id":   m.User.AccountID,
+				"role":         m.Permission,
+			}
+		}
+		return cmdutil.PrintFormatted(opts.Streams, opts.Format, output)
+	}
+
+	if len(matches) == 0 {
+		return cmdutil.NewExitError(cmdutil.ExitNotFound, fmt.Errorf("no member of %s matches %q", workspace, opts.Query))
+	}
+
+	table := cmdutil.NewTablePrinter(opts.Streams)
+	table.AddHeader("NAME", "NICKNAME", "UUID", "ACCOUNT ID", "ROLE")
+	for _, m := range matches {
+		table.AddRow(m.User.DisplayName, cmp.Or(m.User.Nickname, m.User.Username), m.User.UUID, m.User.AccountID, m.Permission)
+	}
+	return table.Render()
+}
+
+// searchWorkspace returns the workspace to search: the given one, the
+// default workspace, or that of the current repository
+func searchWorkspace(workspace string) string {
+	if workspace != "" {
+		return workspace
+	}
+	if workspace, err := config.GetDefaultWorkspace(); err == nil && workspace != "" {
+		return workspace
+	}
+	if workspace, _, err := cmdutil.ParseRepository(""); err == nil {
+		return workspace
+	}
+	return ""
+}
+
+// matchMembers returns the members whose nickname, username or display name
+// contains query, ignoring case: those with a name equal to it first, then
+// the rest, each sorted by display name
+func matchMembers(members []api.WorkspaceMember, query string) []api.WorkspaceMember {
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	// rank is 0 for an exact match, 1 for a partial one and -1 for none
+	rank := func(u *api.User) int {
+		best := -1
+		for _, name := range []string{u.Nickname, u.Username, u.DisplayName} {
+			name = strings.ToLower(name)
+			switch {
+			case name == "":
+			case name == query:
+				return 0
+			case strings.Contains(name, query):
+				best = 1
+			}
+		}
+		return best
+	}
+
+	type match struct {
+		member api.WorkspaceMember
+		rank   int
+	}
+	var matches []match
+	for _, m := range members {
+		if m.User == nil {
+			continue
+		}
+		if r := rank(m.User); r >= 0 {
+			matches = append(matches, match{m, r})
+		}
+	}
+	slices.SortFunc(matches, func(a, b match) int {
+		return cmp.Or(
+			cmp.Compare(a.rank, b.rank),
+			strings.Compare(strings.ToLower(a.member.User.DisplayName), strings.ToLower(b.member.User.DisplayName)),
+		)
+	})
+
+	result := make([]api.WorkspaceMember, len(matches))
+	for i, m := range matches {
+		result[i] = m.member
+	}
+	return result
+}
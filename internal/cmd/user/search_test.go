@@ -0,0 +1,48 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+func TestMatchMembers(t *testing.T) {
+	member := func(nickname, displayName string) api.WorkspaceMember {
+		return api.WorkspaceMember{User: &api.User{UUID: "{" + nickname + "}", Nickname: nickname, DisplayName: displayName}}
+	}
+	members := []api.WorkspaceMember{
+		member("zsmith", "Zoe Smith"),
+		member("jdoe", "Jane Doe"),
+		member("smith", "Adam Smithers"),
+		member("asmith", "Alan Smith"),
+		{Permission: "member"},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"smith", []string{"smith", "asmith", "zsmith"}},
+		{"  JANE ", []string{"jdoe"}},
+		{"jane doe", []string{"jdoe"}},
+		{"nobody", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got := matchMembers(members, tt.query)
+			var nicknames []string
+			for _, m := range got {
+				nicknames = append(nicknames, m.User.Nickname)
+			}
+			if len(nicknames) != len(tt.want) {
+				t.Fatalf("matchMembers(%q) = %v, want %v", tt.query, nicknames, tt.want)
+			}
+			for i := range tt.want {
+				if nicknames[i] != tt.want[i] {
+					t.Errorf("matchMembers(%q) = %v, want %v", tt.query, nicknames, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
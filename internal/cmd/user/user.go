@@ -0,0 +1,24 @@
+package user
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// NewCmdUser creates the user command and its subcommands
+func NewCmdUser(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user <command>",
+		Short: "Look up Bitbucket users",
+		Long: `Look up Bitbucket users, such as to find the UUIDs and account IDs that
+assignee, reviewer and permission APIs need.`,
+		Example: `  # Find the members of a workspace named like "smith"
+  bb user search smith --workspace myworkspace`,
+		Aliases: []string{"users"},
+	}
+
+	cmd.AddCommand(NewCmdSearch(streams))
+
+	return cmd
+}
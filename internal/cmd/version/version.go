@@ -0,0 +1,99 @@
+package version
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+	"github.com/rbansal42/bitbucket-cli/internal/update"
+)
+
+// checkTimeout bounds how long looking for a newer release may delay the
+// command
+const checkTimeout = 3 * time.Second
+
+// VersionOptions holds the options for the version command
+type VersionOptions struct {
+	Version   string
+	BuildDate string
+	Streams   *iostreams.IOStreams
+}
+
+// NewCmdVersion creates the version command
+func NewCmdVersion(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &VersionOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print the version number of bb",
+		Long: `Print the version and build date of bb.
+
+bb also checks whether a newer release is available, at most once a day,
+and prints where to get it. Turn the check off with:
+  bb config set update_check disabled`,
+		Example: `  # Print the version
+  bb version`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Version = cmd.Root().Annotations["version"]
+			opts.BuildDate = cmd.Root().Annotations["buildDate"]
+			return runVersion(cmd.Context(), opts)
+		},
+	}
+
+	return cmd
+}
+
+func runVersion(ctx context.Context, opts *VersionOptions) error {
+	fmt.Fprintf(opts.Streams.Out, "bb version %s (%s)\n", opts.Version, opts.BuildDate)
+
+	if !updateCheckEnabled() {
+		return nil
+	}
+	current, ok := update.ParseVersion(opts.Version)
+	if !ok {
+		// Development builds have nothing to compare
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	release, err := update.CachedLatest(ctx)
+	if err != nil {
+		// The check is a courtesy; being offline is not an error
+		return nil
+	}
+	if latest, ok := update.ParseVersion(release.Version); ok && latest.NewerThan(current) {
+		fmt.Fprintf(opts.Streams.ErrOut, "\n%s %s → %s\n",
+			opts.Streams.Style(iostreams.RoleWarning, "A new release of bb is available:"),
+			opts.Version, release.Version)
+		fmt.Fprintf(opts.Streams.ErrOut, "Run %s, or download it from %s\n",
+			opts.Streams.Style(iostreams.RoleAccent, upgradeCommand()), release.URL)
+	}
+	return nil
+}
+
+// upgradeCommand returns the command that upgrades bb: that of the package
+// manager that installed it, if any, or else bb upgrade
+func upgradeCommand() string {
+	if exe, err := os.Executable(); err == nil {
+		if pm, ok := update.DetectPackageManager(exe); ok {
+			return pm.Command
+		}
+	}
+	return "bb upgrade"
+}
+
+// updateCheckEnabled reports whether update_check allows looking for a
+// newer release
+func updateCheckEnabled() bool {
+	resolver, err := config.Resolve()
+	if err != nil {
+		return true
+	}
+	return resolver.Get("update_check").Value != "disabled"
+}
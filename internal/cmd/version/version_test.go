@@ -0,0 +1,85 @@
+package version
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// newReleaseServer serves tag as the latest release and counts requests
+func newReleaseServer(t *testing.T, tag string) *int {
+	t.Helper()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"tag_name": "` + tag + `", "html_url": "https://github.com/rbansal42/bitbucket-cli/releases/tag/` + tag + `"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("GITHUB_API_URL", server.URL)
+	t.Setenv("BB_CACHE_DIR", t.TempDir())
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv("BB_UPDATE_CHECK", "")
+	return &requests
+}
+
+func TestRunVersion_UpgradeHint(t *testing.T) {
+	requests := newReleaseServer(t, "v1.3.0")
+
+	for range 2 {
+		var stdout, stderr bytes.Buffer
+		streams := &iostreams.IOStreams{Out: &stdout, ErrOut: &stderr}
+		opts := &VersionOptions{Version: "1.2.0", BuildDate: "2024-05-01", Streams: streams}
+		if err := runVersion(context.Background(), opts); err != nil {
+			t.Fatalf("runVersion() error = %v", err)
+		}
+		if got := stdout.String(); got != "bb version 1.2.0 (2024-05-01)\n" {
+			t.Errorf("stdout = %q", got)
+		}
+		if !strings.Contains(stderr.String(), "1.2.0 → 1.3.0") || !strings.Contains(stderr.String(), "releases/tag/v1.3.0") {
+			t.Errorf("expected an upgrade hint, got %q", stderr.String())
+		}
+	}
+	if *requests != 1 {
+		t.Errorf("looked up the latest release %d times, want once", *requests)
+	}
+}
+
+func TestRunVersion_NoHint(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		check   string
+	}{
+		{"up to date", "1.3.0", ""},
+		{"development build", "dev", ""},
+		{"check disabled", "1.2.0", "disabled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := newReleaseServer(t, "v1.3.0")
+			t.Setenv("BB_UPDATE_CHECK", tt.check)
+
+			var stdout, stderr bytes.Buffer
+			streams := &iostreams.IOStreams{Out: &stdout, ErrOut: &stderr}
+			opts := &VersionOptions{Version: tt.version, BuildDate: "unknown", Streams: streams}
+			if err := runVersion(context.Background(), opts); err != nil {
+				t.Fatalf("runVersion() error = %v", err)
+			}
+			if stderr.Len() != 0 {
+				t.Errorf("expected no hint, got %q", stderr.String())
+			}
+			if tt.version == "dev" || tt.check == "disabled" {
+				if *requests != 0 {
+					t.Errorf("looked up the latest release, want no request")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// defaultRelay is the relay deliveries are received through
+const defaultRelay = "https://smee.io"
+
+// forwardTimeout bounds each request to the local server
+const forwardTimeout = 30 * time.Second
+
+// eventAliases are shorthands for the categories of event keys
+var eventAliases = map[string]string{
+	"pr": "pullrequest",
+}
+
+// ForwardOptions holds the options for the forward command
+type ForwardOptions struct {
+	Repo    string
+	URL     string
+	Events  []string
+	Relay   string
+	Secret  string
+	Streams *iostreams.IOStreams
+}
+
+// NewCmdForward creates the webhook forward command
+func NewCmdForward(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &ForwardOptions{Streams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "forward --url <url>",
+		Short: "Forward a repository's webhook events to a local server",
+		Long: `Forward a repository's webhook events to a server on your machine while
+you develop it.
+
+A temporary webhook is added to the repository that delivers events to a
+public relay, which bb reads them from. Each delivery is sent on to --url
+with its original headers, so the local server sees the same requests
+Bitbucket would send it. The webhook is removed when bb stops.
+
+The relay passes on a delivery's payload decoded, not the bytes Bitbucket
+signed, so bb can't check the signature of what it receives. Instead it
+signs each delivery it forwards afresh, in X-Hub-Signature, with the
+secret given by --secret, or a random one it prints, for your server to
+check as it would Bitbucket's.
+
+Events are given by their keys, such as repo:push or pullrequest:created,
+with "pr" short for "pullrequest". Patterns such as pr:* select every
+matching event. Adding webhooks requires admin access to the repository.
+
+Deliveries pass through the relay, smee.io unless --relay is given, and
+can be read by anyone who learns the channel's URL. Don't forward events
+of repositories whose contents must not leave Bitbucket.`,
+		Example: `  # Forward pushes and pull request events to a local server
+  bb webhook forward --url http://localhost:3000/hooks --events pr:*,repo:push
+
+  # Sign deliveries with the secret your server expects
+  bb webhook forward --url http://localhost:3000/hooks --secret "$WEBHOOK_SECRET"
+
+  # Forward every event of another repository
+  bb webhook forward --url http://localhost:8080/webhook -R myworkspace/myrepo`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runForward(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository in WORKSPACE/REPO format")
+	cmd.Flags().StringVarP(&opts.URL, "url", "U", "", "URL of the local server to send events to (required)")
+	cmd.Flags().StringSliceVarP(&opts.Events, "events", "e", []string{"*"}, "Events to forward, by key or pattern")
+	cmd.Flags().StringVar(&opts.Relay, "relay", defaultRelay, "URL of the relay deliveries are received through")
+	cmd.Flags().StringVar(&opts.Secret, "secret", "", "Secret to sign forwarded deliveries with (default: a random one, which is printed)")
+	_ = cmd.MarkFlagRequired("url")
+
+	return cmd
+}
+
+func runForward(ctx context.Context, opts *ForwardOptions) error {
+	if err := validateURL(opts.URL); err != nil {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("invalid --url: %w", err))
+	}
+	if err := validateURL(opts.Relay); err != nil {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, fmt.Errorf("invalid --relay: %w", err))
+	}
+
+	workspace, repoSlug, err := cmdutil.ParseRepository(opts.Repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	known, err := client.ListHookEvents(ctx, "repository")
+	if err != nil {
+		return fmt.Errorf("failed to list webhook events: %w", err)
+	}
+	events, err := expandEvents(opts.Events, known)
+	if err != nil {
+		return cmdutil.NewExitError(cmdutil.ExitUsage, err)
+	}
+
+	// The relay is a third party, so the active host's proxy and TLS
+	// settings don't apply to it
+	relayClient := &http.Client{}
+	channel, err := newChannel(ctx, relayClient, opts.Relay)
+	if err != nil {
+		return err
+	}
+
+	secret := opts.Secret
+	if secret == "" {
+		if secret, err = newSecret(); err != nil {
+			return err
+		}
+	}
+
+	hook, err := client.CreateRepositoryWebhook(ctx, workspace, repoSlug, &api.WebhookCreateOptions{
+		Description: "bb webhook forward",
+		URL:         channel,
+		Active:      true,
+		Events:      events,
+		Secret:      secret,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	defer removeWebhook(opts.Streams, client, workspace, repoSlug, hook.UUID)
+
+	opts.Streams.Success("Forwarding %s events of %s/%s to %s", strings.Join(events, ", "), workspace, repoSlug, opts.URL)
+	if opts.Secret == "" {
+		opts.Streams.Info("Deliveries are signed with the secret %s", secret)
+	}
+	opts.Streams.Info("Receiving through %s; press Ctrl+C to stop", channel)
+
+	target := &http.Client{Timeout: forwardTimeout}
+	return streamDeliveries(ctx, relayClient, channel, func(d delivery) {
+		forwardDelivery(ctx, opts.Streams, target, opts.URL, secret, d)
+	})
+}
+
+// forwardDelivery sends d to the local server at targetURL, signed with
+// secret, and reports the outcome. The relay re-encodes the payload, so the
+// signature Bitbucket sent with it can't be checked; the delivery is signed
+// again over the payload as forwarded.
+func forwardDelivery(ctx context.Context, streams *iostreams.IOStreams, target *http.Client, targetURL, secret string, d delivery) {
+	event := d.Headers["x-event-key"]
+	if event == "" {
+		event = "(unknown event)"
+	}
+	stamp := streams.Style(iostreams.RoleMuted, time.Now().Format("15:04:05"))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, targetURL, bytes.NewReader(d.Body))
+	if err != nil {
+		streams.Warning("%s %s: %v", stamp, event, err)
+		return
+	}
+	for name, value := range d.Headers {
+		if forwardedHeader(name) {
+			req.Header.Set(name, value)
+		}
+	}
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("X-Hub-Signature", signature(secret, d.Body))
+
+	start := time.Now()
+	resp, err := target.Do(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		if ctx.Err() == nil {
+			fmt.Fprintf(streams.Out, "%s %s → %s\n", stamp, event, streams.Style(iostreams.RoleError, err.Error()))
+		}
+		return
+	}
+	resp.Body.Close()
+
+	role := iostreams.RoleSuccess
+	if resp.StatusCode >= 400 {
+		role = iostreams.RoleError
+	}
+	fmt.Fprintf(streams.Out, "%s %s → %s (%s)\n", stamp, event, streams.Style(role, resp.Status), elapsed)
+}
+
+// forwardedHeader reports whether a delivery header is sent on to the local
+// server. Bitbucket's own headers are; those the relay's proxies added,
+// and those describing the connection, are not.
+func forwardedHeader(name string) bool {
+	switch name {
+	case "content-type", "user-agent":
+		return true
+	}
+	return strings.HasPrefix(name, "x-") && !strings.HasPrefix(name, "x-forwarded-")
+}
+
+// signature returns the X-Hub-Signature header Bitbucket would send with
+// body: its HMAC-SHA256 with secret
+func signature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// expandEvents resolves the event keys and patterns in patterns to the
+// keys of known events
+func expandEvents(patterns []string, known []api.HookEvent) ([]string, error) {
+	var events []string
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if category, rest, ok := strings.Cut(pattern, ":"); ok {
+			if full, alias := eventAliases[category]; alias {
+				pattern = full + ":" + rest
+			}
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid event pattern: %s", pattern)
+		}
+
+		matched := false
+		for _, e := range known {
+			if ok, _ := path.Match(pattern, e.Event); ok {
+				matched = true
+				if !slices.Contains(events, e.Event) {
+					events = append(events, e.Event)
+				}
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("no webhook events match %q", pattern)
+		}
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("no events given to forward")
+	}
+	return events, nil
+}
+
+// removeWebhook deletes the temporary webhook. It runs as bb exits, so it
+// has its own deadline rather than the command's, which may be cancelled.
+func removeWebhook(streams *iostreams.IOStreams, client *api.Client, workspace, repoSlug, uuid string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if err := client.DeleteRepositoryWebhook(ctx, workspace, repoSlug, uuid); err != nil {
+		streams.Warning("Could not remove webhook %s; remove it in the repository settings: %v", uuid, err)
+		return
+	}
+	streams.Success("Removed the webhook")
+}
+
+// newSecret returns a random secret for signing deliveries
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// validateURL checks that value is an http:// or https:// URL
+func validateURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q is not an http:// or https:// URL", value)
+	}
+	return nil
+}
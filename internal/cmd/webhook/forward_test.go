@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestExpandEvents(t *testing.T) {
+	known := []api.HookEvent{
+		{Event: "repo:push"},
+		{Event: "repo:fork"},
+		{Event: "pullrequest:created"},
+		{Event: "pullrequest:fulfilled"},
+	}
+
+	events, err := expandEvents([]string{"pr:*", "repo:push", "pullrequest:created"}, known)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(events, ","); got != "pullrequest:created,pullrequest:fulfilled,repo:push" {
+		t.Errorf("expandEvents() = %s", got)
+	}
+
+	if _, err := expandEvents([]string{"issue:*"}, known); err == nil {
+		t.Error("expected an error for a pattern matching no events")
+	}
+}
+
+func TestForwardDelivery(t *testing.T) {
+	body := []byte(`{"push":{"changes":[]}}`)
+	var received []byte
+	var eventKey, sig string
+	local := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+		eventKey = r.Header.Get("X-Event-Key")
+		sig = r.Header.Get("X-Hub-Signature")
+		if r.Header.Get("X-Forwarded-For") != "" {
+			t.Error("expected the relay's headers not to be forwarded")
+		}
+	}))
+	defer local.Close()
+
+	// A relay streaming a delivery whose signature, made over the payload
+	// Bitbucket sent, no longer matches the payload as relayed
+	relay := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: ready\ndata: {}\n\n")
+		fmt.Fprintf(w, "data: {\"x-event-key\":\"repo:push\",\"x-hub-signature\":\"sha256=00\",\"x-forwarded-for\":\"1.2.3.4\",\"body\":%s,\"timestamp\":1}\n\n", body)
+	}))
+	defer relay.Close()
+
+	var out, errOut bytes.Buffer
+	streams := &iostreams.IOStreams{Out: &out, ErrOut: &errOut}
+	err := streamDeliveries(context.Background(), relay.Client(), relay.URL, func(d delivery) {
+		forwardDelivery(context.Background(), streams, local.Client(), local.URL, "s3cret", d)
+	})
+	if err == nil || !strings.Contains(err.Error(), "closed") {
+		t.Errorf("expected the relay closing the stream to be reported, got %v", err)
+	}
+
+	if string(received) != string(body) || eventKey != "repo:push" {
+		t.Errorf("local server received %s %q, want the delivery", eventKey, received)
+	}
+	if sig != sign("s3cret", body) {
+		t.Errorf("local server received signature %q, want the payload signed with the secret", sig)
+	}
+	if !strings.Contains(out.String(), "repo:push → 200 OK") {
+		t.Errorf("expected the forwarded delivery to be reported, got %q", out.String())
+	}
+}
@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// The relay receives deliveries on a public URL and streams them to bb. It
+// speaks the protocol of smee.io: requesting /new creates a channel and
+// redirects to its URL, and reading the channel with Accept:
+// text/event-stream streams each delivery as a server-sent event whose data
+// is a JSON object of the request's headers, lowercased, with the payload
+// under "body".
+
+// delivery is a webhook request received through the relay
+type delivery struct {
+	Headers map[string]string
+	Body    []byte
+}
+
+// relayFields are the members of an event that are not request headers
+var relayFields = map[string]bool{"body": true, "query": true, "timestamp": true}
+
+// newChannel creates a channel on the relay and returns its URL
+func newChannel(ctx context.Context, client *http.Client, relayURL string) (string, error) {
+	noRedirect := *client
+	noRedirect.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(relayURL, "/")+"/new", nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := noRedirect.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach relay: %w", err)
+	}
+	defer resp.Body.Close()
+
+	location, err := resp.Location()
+	if err != nil {
+		return "", fmt.Errorf("relay did not create a channel (%s)", resp.Status)
+	}
+	return location.String(), nil
+}
+
+// streamDeliveries reads the deliveries of channel, passing each to fn,
+// until ctx is done or the relay closes the stream
+func streamDeliveries(ctx context.Context, client *http.Client, channel string, fn func(delivery)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, channel, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("failed to connect to relay: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to connect to relay: %s", resp.Status)
+	}
+
+	err = readEvents(resp.Body, func(event, data string) {
+		if event != "" && event != "message" {
+			return
+		}
+		if d, ok := parseDelivery(data); ok {
+			fn(d)
+		}
+	})
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("lost connection to relay: %w", err)
+	}
+	return errors.New("relay closed the connection")
+}
+
+// readEvents parses a server-sent event stream, calling fn with the type
+// and data of each event
+func readEvents(r io.Reader, fn func(event, data string)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var event string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(data) > 0 {
+				fn(event, strings.Join(data, "\n"))
+			}
+			event, data = "", nil
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	return scanner.Err()
+}
+
+// parseDelivery decodes the data of a relay event
+func parseDelivery(data string) (delivery, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		return delivery{}, false
+	}
+	body, ok := fields["body"]
+	if !ok {
+		return delivery{}, false
+	}
+
+	d := delivery{Headers: map[string]string{}, Body: body}
+	for name, raw := range fields {
+		if relayFields[name] {
+			continue
+		}
+		var value string
+		if json.Unmarshal(raw, &value) == nil {
+			d.Headers[strings.ToLower(name)] = value
+		}
+	}
+	return d, true
+}
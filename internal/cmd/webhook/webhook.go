@@ -0,0 +1,26 @@
+package webhook
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// NewCmdWebhook creates the webhook command and its subcommands
+func NewCmdWebhook(streams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "webhook <command>",
+		Short: "Work with repository webhooks",
+		Long: `Work with the webhooks of a repository.
+
+Use "bb webhook forward" to receive a repository's webhook events on a
+server running on your machine while you develop it.`,
+		Example: `  # Forward pull request events to a local server
+  bb webhook forward --url http://localhost:3000/hooks --events pr:*`,
+		Aliases: []string{"webhooks", "hook"},
+	}
+
+	cmd.AddCommand(NewCmdForward(streams))
+
+	return cmd
+}
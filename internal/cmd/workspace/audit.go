@@ -0,0 +1,335 @@
+package workspace
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// auditConcurrency is how many repositories' permissions are read at once
+const auditConcurrency = 8
+
+// AuditOptions holds the options for the audit command
+type AuditOptions struct {
+	WorkspaceSlug string
+	CSV           bool
+	JSON          bool
+	Format        string
+	Streams       *iostreams.IOStreams
+}
+
+// workspaceAudit is the permission report of a workspace
+type workspaceAudit struct {
+	Workspace string        `json:"workspace"`
+	Users     []auditedUser `json:"users"`
+	// Unread lists the repositories whose permissions couldn't be read
+	Unread []string `json:"unread_repositories,omitempty"`
+}
+
+// auditedUser is a user with access to a workspace, as a member or through
+// explicit repository grants
+type auditedUser struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+	UUID        string `json:"uuid"`
+	// Role is the user's workspace role, or "" if they aren't a member
+	Role   string      `json:"workspace_role"`
+	Grants []repoGrant `json:"repository_grants"`
+	// Admin is set for workspace owners and repository admins
+	Admin bool `json:"admin"`
+	// External is set for users who are only collaborators on
+	// repositories, not members of the workspace
+	External bool `json:"external"`
+}
+
+// repoGrant is a permission granted explicitly to a user on a repository
+type repoGrant struct {
+	Repository string `json:"repository"`
+	Permission string `json:"permission"`
+}
+
+// NewCmdAudit creates the workspace audit command
+func NewCmdAudit(streams *iostreams.IOStreams) *cobra.Command {
+	opts := &AuditOptions{
+		Streams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "audit <workspace>",
+		Short: "Report who has access to a workspace and its repositories",
+		Long: `Report the members of a workspace with their workspace role, and the
+permissions granted explicitly to users on each of its repositories.
+
+Workspace owners and repository admins are flagged as admin. Users who
+aren't members of the workspace, or who are only collaborators on some of
+its repositories, are flagged as external.
+
+Permissions users have through groups aren't included. Reading every
+repository's permissions requires admin access to the workspace.
+
+With --csv, the report has a row for each repository grant, and a row for
+each member without one, for use in a spreadsheet.`,
+		Example: `  # Show the report
+  bb workspace audit myworkspace
+
+  # Export the report for a compliance review
+  bb workspace audit myworkspace --csv > access.csv
+
+  # Output as JSON
+  bb workspace audit myworkspace --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.WorkspaceSlug = args[0]
+			return runAudit(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.CSV, "csv", false, "Output in CSV format")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
+	cmd.MarkFlagsMutuallyExclusive("csv", "json", "format")
+
+	return cmd
+}
+
+func runAudit(ctx context.Context, opts *AuditOptions) error {
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	progress := opts.Streams.StartProgress("Reading workspace permissions")
+	audit, err := auditWorkspace(ctx, client, opts.WorkspaceSlug)
+	progress.Stop()
+	if err != nil {
+		return err
+	}
+	if len(audit.Unread) > 0 {
+		opts.Streams.Warning("Could not read the permissions of %d repositories: %s", len(audit.Unread), strings.Join(audit.Unread, ", "))
+	}
+
+	switch {
+	case opts.CSV:
+		return writeAuditCSV(opts.Streams.Out, audit)
+	case opts.JSON || opts.Format != "":
+		return cmdutil.PrintFormatted(opts.Streams, opts.Format, audit)
+	}
+
+	if len(audit.Users) == 0 {
+		opts.Streams.Info("No members found in workspace %s", opts.WorkspaceSlug)
+		return nil
+	}
+
+	if err := opts.Streams.StartPager(); err != nil {
+		opts.Streams.Warning("%s", err)
+	}
+	defer opts.Streams.StopPager()
+
+	return printAudit(opts.Streams, audit)
+}
+
+// auditWorkspace reads the members of workspace and the explicit user
+// permissions of each of its repositories. Repositories whose permissions
+// can't be read are listed in the report's Unread.
+func auditWorkspace(ctx context.Context, client *api.Client, workspace string) (*workspaceAudit, error) {
+	var members []api.WorkspaceMember
+	for page := 1; ; page++ {
+		result, err := client.ListWorkspaceMembers(ctx, workspace, &api.WorkspaceMemberListOptions{Page: page, Limit: 100})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list workspace members: %w", err)
+		}
+		members = append(members, result.Values...)
+		if result.Next == "" || len(result.Values) == 0 {
+			break
+		}
+	}
+
+	var repos []string
+	for page := 1; ; page++ {
+		result, err := client.ListRepositories(ctx, workspace, &api.RepositoryListOptions{Sort: "slug", Page: page, Limit: 100})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories: %w", err)
+		}
+		for _, r := range result.Values {
+			repos = append(repos, r.Slug)
+		}
+		if result.Next == "" || len(result.Values) == 0 {
+			break
+		}
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		limiter = make(chan struct{}, auditConcurrency)
+		grants  = map[string][]api.RepositoryUserPermission{}
+		unread  []string
+	)
+	for _, repo := range repos {
+		wg.Go(func() {
+			limiter <- struct{}{}
+			defer func() { <-limiter }()
+
+			permissions, err := client.ListRepositoryUserPermissions(ctx, workspace, repo)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				unread = append(unread, repo)
+				return
+			}
+			grants[repo] = permissions
+		})
+	}
+	wg.Wait()
+
+	if len(repos) > 0 && len(unread) == len(repos) {
+		return nil, fmt.Errorf("failed to read the permissions of any repository in %s; this needs admin access to the workspace", workspace)
+	}
+
+	audit := buildAudit(workspace, members, grants)
+	slices.Sort(unread)
+	audit.Unread = unread
+	return audit, nil
+}
+
+// buildAudit combines the members of workspace with the explicit user
+// permissions of its repositories, by repository slug, into a report with
+// a user per entry sorted by name
+func buildAudit(workspace string, members []api.WorkspaceMember, grants map[string][]api.RepositoryUserPermission) *workspaceAudit {
+	users := map[string]*auditedUser{}
+	user := func(u *api.User) *auditedUser {
+		if users[u.UUID] == nil {
+			users[u.UUID] = &auditedUser{
+				Username:    u.Username,
+				DisplayName: u.DisplayName,
+				UUID:        u.UUID,
+			}
+		}
+		return users[u.UUID]
+	}
+
+	for _, m := range members {
+		if m.User == nil {
+			continue
+		}
+		user(m.User).Role = m.Permission
+	}
+	for repo, permissions := range grants {
+		for _, p := range permissions {
+			if p.User == nil || p.Permission == "none" {
+				continue
+			}
+			u := user(p.User)
+			u.Grants = append(u.Grants, repoGrant{Repository: workspace + "/" + repo, Permission: p.Permission})
+		}
+	}
+
+	audit := &workspaceAudit{Workspace: workspace}
+	for _, u := range users {
+		slices.SortFunc(u.Grants, func(a, b repoGrant) int {
+			return strings.Compare(a.Repository, b.Repository)
+		})
+		u.Admin = u.Role == "owner" || slices.ContainsFunc(u.Grants, func(g repoGrant) bool {
+			return g.Permission == "admin"
+		})
+		u.External = u.Role == "" || u.Role == "collaborator"
+		audit.Users = append(audit.Users, *u)
+	}
+	slices.SortFunc(audit.Users, func(a, b auditedUser) int {
+		return cmp.Or(
+			strings.Compare(strings.ToLower(auditUserName(a)), strings.ToLower(auditUserName(b))),
+			strings.Compare(a.UUID, b.UUID),
+		)
+	})
+	return audit
+}
+
+// auditUserName returns the name a user is shown by in the report
+func auditUserName(u auditedUser) string {
+	return cmp.Or(u.DisplayName, u.Username, u.UUID)
+}
+
+func printAudit(streams *iostreams.IOStreams, audit *workspaceAudit) error {
+	table := cmdutil.NewTablePrinter(streams)
+	table.AddHeader("USER", "ROLE", "REPOSITORIES", "FLAGS")
+
+	admins, externals := 0, 0
+	for _, u := range audit.Users {
+		role := u.Role
+		if role == "" {
+			role = "-"
+		} else {
+			role = formatMemberRole(streams, role)
+		}
+
+		var flags []string
+		if u.Admin {
+			admins++
+			flags = append(flags, streams.Style(iostreams.RoleWarning, "admin"))
+		}
+		if u.External {
+			externals++
+			flags = append(flags, streams.Style(iostreams.RoleError, "external"))
+		}
+
+		table.AddRow(auditUserName(u), role, formatGrants(u.Grants), strings.Join(flags, ", "))
+	}
+	if err := table.Render(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(streams.Out, "\n%d users, %d admins, %d external\n", len(audit.Users), admins, externals)
+	return nil
+}
+
+// formatGrants summarizes a user's repository grants as the number of
+// repositories with each permission, such as "2 admin, 5 write"
+func formatGrants(grants []repoGrant) string {
+	if len(grants) == 0 {
+		return "-"
+	}
+	counts := map[string]int{}
+	for _, g := range grants {
+		counts[g.Permission]++
+	}
+	var parts []string
+	for _, permission := range []string{"admin", "write", "read"} {
+		if counts[permission] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[permission], permission))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// writeAuditCSV writes the report as CSV with a row for each repository
+// grant, and a row for each user without one
+func writeAuditCSV(w io.Writer, audit *workspaceAudit) error {
+	out := csv.NewWriter(w)
+	_ = out.Write([]string{"username", "display_name", "uuid", "workspace_role", "repository", "permission", "admin", "external"})
+	for _, u := range audit.Users {
+		row := func(g repoGrant) []string {
+			return []string{u.Username, u.DisplayName, u.UUID, u.Role, g.Repository, g.Permission,
+				strconv.FormatBool(u.Admin), strconv.FormatBool(u.External)}
+		}
+		if len(u.Grants) == 0 {
+			_ = out.Write(row(repoGrant{}))
+		}
+		for _, g := range u.Grants {
+			_ = out.Write(row(g))
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,82 @@
+package workspace
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+func TestBuildAudit(t *testing.T) {
+	alice := &api.User{UUID: "{a}", Username: "alice", DisplayName: "Alice"}
+	bob := &api.User{UUID: "{b}", Username: "bob", DisplayName: "Bob"}
+	carol := &api.User{UUID: "{c}", Username: "carol", DisplayName: "Carol"}
+	dave := &api.User{UUID: "{d}", Username: "dave", DisplayName: "Dave"}
+
+	members := []api.WorkspaceMember{
+		{Permission: "owner", User: alice},
+		{Permission: "member", User: bob},
+		{Permission: "collaborator", User: carol},
+	}
+	grants := map[string][]api.RepositoryUserPermission{
+		"web": {
+			{Permission: "admin", User: bob},
+			{Permission: "read", User: dave},
+		},
+		"api": {
+			{Permission: "write", User: bob},
+			{Permission: "none", User: carol},
+		},
+	}
+
+	audit := buildAudit("acme", members, grants)
+
+	want := []struct {
+		name     string
+		role     string
+		grants   string
+		admin    bool
+		external bool
+	}{
+		{"Alice", "owner", "-", true, false},
+		{"Bob", "member", "1 admin, 1 write", true, false},
+		{"Carol", "collaborator", "-", false, true},
+		{"Dave", "", "1 read", false, true},
+	}
+	if len(audit.Users) != len(want) {
+		t.Fatalf("got %d users, want %d: %+v", len(audit.Users), len(want), audit.Users)
+	}
+	for i, w := range want {
+		u := audit.Users[i]
+		if auditUserName(u) != w.name || u.Role != w.role || formatGrants(u.Grants) != w.grants || u.Admin != w.admin || u.External != w.external {
+			t.Errorf("users[%d] = %+v, want %+v", i, u, w)
+		}
+	}
+	if audit.Users[1].Grants[0].Repository != "acme/api" {
+		t.Errorf("grants not sorted by repository: %+v", audit.Users[1].Grants)
+	}
+}
+
+func TestWriteAuditCSV(t *testing.T) {
+	audit := &workspaceAudit{Users: []auditedUser{
+		{Username: "alice", DisplayName: "Alice, Jr.", UUID: "{a}", Role: "owner", Admin: true},
+		{Username: "dave", DisplayName: "Dave", UUID: "{d}", External: true, Grants: []repoGrant{
+			{Repository: "acme/api", Permission: "read"},
+			{Repository: "acme/web", Permission: "write"},
+		}},
+	}}
+
+	var out strings.Builder
+	if err := writeAuditCSV(&out, audit); err != nil {
+		t.Fatalf("writeAuditCSV() error = %v", err)
+	}
+
+	want := `username,display_name,uuid,workspace_role,repository,permission,admin,external
+alice,"Alice, Jr.",{a},owner,,,true,false
+dave,Dave,{d},,acme/api,read,false,true
+dave,Dave,{d},,acme/web,write,false,true
+`
+	if out.String() != want {
+		t.Errorf("CSV = %q, want %q", out.String(), want)
+	}
+}
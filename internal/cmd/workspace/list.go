@@ -3,7 +3,6 @@ package workspace
 import (
 	"context"
 	"fmt"
-	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -18,6 +17,7 @@ type ListOptions struct {
 	Role    string
 	Limit   int
 	JSON    bool
+	Format  string
 	Streams *iostreams.IOStreams
 }
 
@@ -43,7 +43,10 @@ You can filter by your role in the workspace (owner, collaborator, or member).`,
   bb workspace list --limit 10
 
   # Output as JSON
-  bb workspace list --json`,
+  bb workspace list --json
+
+  # Output as YAML
+  bb workspace list --format yaml`,
 		Aliases: []string{"ls"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runList(cmd.Context(), opts)
@@ -53,6 +56,7 @@ You can filter by your role in the workspace (owner, collaborator, or member).`,
 	cmd.Flags().StringVarP(&opts.Role, "role", "r", "", "Filter by role (owner, collaborator, member)")
 	cmd.Flags().IntVarP(&opts.Limit, "limit", "l", 30, "Maximum number of workspaces to list")
 	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
 
 	return cmd
 }
@@ -75,7 +79,9 @@ func runList(ctx context.Context, opts *ListOptions) error {
 	}
 
 	// Fetch workspaces
+	progress := opts.Streams.StartProgress("Fetching workspaces")
 	result, err := client.ListWorkspaces(ctx, listOpts)
+	progress.Stop()
 	if err != nil {
 		return fmt.Errorf("failed to list workspaces: %w", err)
 	}
@@ -85,16 +91,21 @@ func runList(ctx context.Context, opts *ListOptions) error {
 		return nil
 	}
 
+	if err := opts.Streams.StartPager(); err != nil {
+		opts.Streams.Warning("%s", err)
+	}
+	defer opts.Streams.StopPager()
+
 	// Output results
-	if opts.JSON {
-		return outputListJSON(opts.Streams, result.Values)
+	if opts.JSON || opts.Format != "" {
+		return outputListStructured(opts.Streams, opts.Format, result.Values)
 	}
 
 	return outputListTable(opts.Streams, result.Values)
 }
 
-func outputListJSON(streams *iostreams.IOStreams, memberships []api.WorkspaceMembership) error {
-	// Create simplified JSON output
+func outputListStructured(streams *iostreams.IOStreams, format string, memberships []api.WorkspaceMembership) error {
+	// Create simplified output
 	output := make([]map[string]interface{}, len(memberships))
 	for i, m := range memberships {
 		ws := m.Workspace
@@ -110,24 +121,23 @@ func outputListJSON(streams *iostreams.IOStreams, memberships []api.WorkspaceMem
 		}
 	}
 
-	return cmdutil.PrintJSON(streams, output)
+	return cmdutil.PrintFormatted(streams, format, output)
 }
 
 func outputListTable(streams *iostreams.IOStreams, memberships []api.WorkspaceMembership) error {
-	w := tabwriter.NewWriter(streams.Out, 0, 0, 2, ' ', 0)
+	table := cmdutil.NewTablePrinter(streams)
 
 	// Print header
-	header := "SLUG\tNAME\tROLE"
-	cmdutil.PrintTableHeader(streams, w, header)
+	table.AddHeader("SLUG", "NAME", "ROLE")
 
 	// Print rows
 	for _, m := range memberships {
 		ws := m.Workspace
 		role := formatRole(streams, m.Permission)
-		fmt.Fprintf(w, "%s\t%s\t%s\n", ws.Slug, ws.Name, role)
+		table.AddRow(ws.Slug, ws.Name, role)
 	}
 
-	return w.Flush()
+	return table.Render()
 }
 
 func formatRole(streams *iostreams.IOStreams, role string) string {
@@ -137,9 +147,9 @@ func formatRole(streams *iostreams.IOStreams, role string) string {
 
 	switch role {
 	case "owner":
-		return iostreams.Yellow + role + iostreams.Reset
+		return streams.Style(iostreams.RoleWarning, role)
 	case "collaborator":
-		return iostreams.Cyan + role + iostreams.Reset
+		return streams.Style(iostreams.RoleInfo, role)
 	default:
 		return role
 	}
@@ -2,9 +2,7 @@ package workspace
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -19,6 +17,7 @@ type MembersOptions struct {
 	WorkspaceSlug string
 	Limit         int
 	JSON          bool
+	Format        string
 	Streams       *iostreams.IOStreams
 }
 
@@ -41,7 +40,10 @@ Shows the username, display name, and role of each member.`,
   bb workspace members myworkspace --limit 50
 
   # Output as JSON
-  bb workspace members myworkspace --json`,
+  bb workspace members myworkspace --json
+
+  # Output as YAML
+  bb workspace members myworkspace --format yaml`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.WorkspaceSlug = args[0]
@@ -51,6 +53,7 @@ Shows the username, display name, and role of each member.`,
 
 	cmd.Flags().IntVarP(&opts.Limit, "limit", "l", 30, "Maximum number of members to list")
 	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.Format)
 
 	return cmd
 }
@@ -72,7 +75,9 @@ func runMembers(ctx context.Context, opts *MembersOptions) error {
 	}
 
 	// Fetch members
+	progress := opts.Streams.StartProgress("Fetching members")
 	result, err := client.ListWorkspaceMembers(ctx, opts.WorkspaceSlug, listOpts)
+	progress.Stop()
 	if err != nil {
 		return fmt.Errorf("failed to list workspace members: %w", err)
 	}
@@ -82,16 +87,21 @@ func runMembers(ctx context.Context, opts *MembersOptions) error {
 		return nil
 	}
 
+	if err := opts.Streams.StartPager(); err != nil {
+		opts.Streams.Warning("%s", err)
+	}
+	defer opts.Streams.StopPager()
+
 	// Output results
-	if opts.JSON {
-		return outputMembersJSON(opts.Streams, result.Values)
+	if opts.JSON || opts.Format != "" {
+		return outputMembersStructured(opts.Streams, opts.Format, result.Values)
 	}
 
 	return outputMembersTable(opts.Streams, result.Values)
 }
 
-func outputMembersJSON(streams *iostreams.IOStreams, members []api.WorkspaceMember) error {
-	// Create simplified JSON output
+func outputMembersStructured(streams *iostreams.IOStreams, format string, members []api.WorkspaceMember) error {
+	// Create simplified output
 	output := make([]map[string]interface{}, len(members))
 	for i, m := range members {
 		output[i] = map[string]interface{}{
@@ -105,25 +115,14 @@ func outputMembersJSON(streams *iostreams.IOStreams, members []api.WorkspaceMemb
 		}
 	}
 
-	data, err := json.MarshalIndent(output, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
-	}
-
-	fmt.Fprintln(streams.Out, string(data))
-	return nil
+	return cmdutil.PrintFormatted(streams, format, output)
 }
 
 func outputMembersTable(streams *iostreams.IOStreams, members []api.WorkspaceMember) error {
-	w := tabwriter.NewWriter(streams.Out, 0, 0, 2, ' ', 0)
+	table := cmdutil.NewTablePrinter(streams)
 
 	// Print header
-	header := "USERNAME\tNAME\tROLE"
-	if streams.ColorEnabled() {
-		fmt.Fprintln(w, iostreams.Bold+header+iostreams.Reset)
-	} else {
-		fmt.Fprintln(w, header)
-	}
+	table.AddHeader("USERNAME", "NAME", "ROLE")
 
 	// Print rows
 	for _, m := range members {
@@ -134,10 +133,10 @@ func outputMembersTable(streams *iostreams.IOStreams, members []api.WorkspaceMem
 			displayName = m.User.DisplayName
 		}
 		role := formatMemberRole(streams, m.Permission)
-		fmt.Fprintf(w, "%s\t%s\t%s\n", username, displayName, role)
+		table.AddRow(username, displayName, role)
 	}
 
-	return w.Flush()
+	return table.Render()
 }
 
 func formatMemberRole(streams *iostreams.IOStreams, role string) string {
@@ -147,9 +146,9 @@ func formatMemberRole(streams *iostreams.IOStreams, role string) string {
 
 	switch role {
 	case "owner":
-		return iostreams.Yellow + role + iostreams.Reset
+		return streams.Style(iostreams.RoleWarning, role)
 	case "collaborator":
-		return iostreams.Cyan + role + iostreams.Reset
+		return streams.Style(iostreams.RoleInfo, role)
 	default:
 		return role
 	}
@@ -2,14 +2,12 @@ package workspace
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/rbansal42/bitbucket-cli/internal/api"
-	"github.com/rbansal42/bitbucket-cli/internal/browser"
 	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
@@ -18,7 +16,9 @@ type viewOptions struct {
 	streams       *iostreams.IOStreams
 	workspaceSlug string
 	web           bool
+	copy          bool
 	jsonOut       bool
+	format        string
 }
 
 // NewCmdView creates the workspace view command
@@ -41,7 +41,10 @@ and the browser URL.`,
   bb workspace view myworkspace --web
 
   # Output as JSON
-  bb workspace view myworkspace --json`,
+  bb workspace view myworkspace --json
+
+  # Output as YAML
+  bb workspace view myworkspace --format yaml`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.workspaceSlug = args[0]
@@ -49,8 +52,10 @@ and the browser URL.`,
 		},
 	}
 
-	cmd.Flags().BoolVarP(&opts.web, "web", "w", false, "Open the workspace in a web browser")
+	cmdutil.AddWebFlag(cmd, &opts.web, "workspace")
+	cmdutil.AddCopyFlag(cmd, &opts.copy, "workspace")
 	cmd.Flags().BoolVar(&opts.jsonOut, "json", false, "Output in JSON format")
+	cmdutil.AddFormatFlag(cmd, &opts.format)
 
 	return cmd
 }
@@ -72,35 +77,31 @@ func runView(ctx context.Context, opts *viewOptions) error {
 		return fmt.Errorf("failed to get workspace: %w", err)
 	}
 
-	// Handle --web flag
-	if opts.web {
-		url := ws.Links.HTML.Href
-		if url == "" {
-			url = fmt.Sprintf("https://bitbucket.org/%s", ws.Slug)
-		}
-		if err := browser.Open(url); err != nil {
-			return fmt.Errorf("could not open browser: %w", err)
+	// Handle --copy and --web flags
+	url := ws.Links.HTML.Href
+	if url == "" {
+		url = fmt.Sprintf("https://bitbucket.org/%s", ws.Slug)
+	}
+	if opts.copy {
+		if err := cmdutil.CopyURL(opts.streams, url); err != nil {
+			return err
 		}
-		opts.streams.Success("Opened %s in your browser", url)
-		return nil
+	}
+	if opts.web {
+		return cmdutil.OpenInBrowser(opts.streams, url)
 	}
 
-	// Handle --json flag
-	if opts.jsonOut {
-		return outputViewJSON(opts.streams, ws)
+	// Handle --json and --format flags
+	if opts.jsonOut || opts.format != "" {
+		return outputViewStructured(opts.streams, opts.format, ws)
 	}
 
 	// Display formatted output
 	return displayWorkspace(opts.streams, ws)
 }
 
-func outputViewJSON(streams *iostreams.IOStreams, ws *api.WorkspaceFull) error {
-	data, err := json.MarshalIndent(ws, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
-	}
-	fmt.Fprintln(streams.Out, string(data))
-	return nil
+func outputViewStructured(streams *iostreams.IOStreams, format string, ws *api.WorkspaceFull) error {
+	return cmdutil.PrintFormatted(streams, format, ws)
 }
 
 func displayWorkspace(streams *iostreams.IOStreams, ws *api.WorkspaceFull) error {
@@ -0,0 +1,71 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+)
+
+// defaultBranches caches the default branch of each repository looked up
+// during this run, by WORKSPACE/REPO
+var defaultBranches sync.Map
+
+// DefaultBranch returns the default branch of workspace/repoSlug. It is read
+// from refs/remotes/<remote>/HEAD when the current git repository has a
+// remote for it, and otherwise from the repository's mainbranch, which is
+// then recorded in that ref for next time. client may be nil to only look
+// locally.
+func DefaultBranch(ctx context.Context, client *api.Client, workspace, repoSlug string) (string, error) {
+	key := strings.ToLower(workspace + "/" + repoSlug)
+	if branch, ok := defaultBranches.Load(key); ok {
+		return branch.(string), nil
+	}
+
+	remote := LocalRemoteFor(workspace, repoSlug)
+	if remote != "" {
+		if branch, err := git.DefaultBranch(remote); err == nil {
+			defaultBranches.Store(key, branch)
+			return branch, nil
+		}
+	}
+
+	if client == nil {
+		return "", fmt.Errorf("default branch of %s/%s is not known locally", workspace, repoSlug)
+	}
+	repo, err := client.GetRepository(ctx, workspace, repoSlug)
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository: %w", err)
+	}
+	if repo.MainBranch == nil || repo.MainBranch.Name == "" {
+		return "", fmt.Errorf("%s/%s has no default branch", workspace, repoSlug)
+	}
+
+	branch := repo.MainBranch.Name
+	if remote != "" {
+		// Best effort: fails if the branch hasn't been fetched
+		_ = git.SetDefaultBranch(remote, branch)
+	}
+	defaultBranches.Store(key, branch)
+	return branch, nil
+}
+
+// LocalRemoteFor returns the name of the most preferred remote of the
+// current git repository that points at workspace/repoSlug, or "" if there
+// is none.
+func LocalRemoteFor(workspace, repoSlug string) string {
+	remotes, err := git.GetBitbucketRemotes()
+	if err != nil {
+		return ""
+	}
+
+	for _, r := range remotes {
+		if strings.EqualFold(r.Workspace, workspace) && strings.EqualFold(r.RepoSlug, repoSlug) {
+			return r.Name
+		}
+	}
+	return ""
+}
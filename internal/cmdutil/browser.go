@@ -0,0 +1,56 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/browser"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// AddWebFlag registers the --web flag used by commands that can open their
+// resource in the browser instead of printing it. The -w shorthand is only
+// added when the command has not already claimed it, so call this after
+// the command's other flags are defined.
+func AddWebFlag(cmd *cobra.Command, web *bool, noun string) {
+	usage := fmt.Sprintf("Open the %s in a web browser", noun)
+	if cmd.Flags().ShorthandLookup("w") != nil {
+		cmd.Flags().BoolVar(web, "web", false, usage)
+		return
+	}
+	cmd.Flags().BoolVarP(web, "web", "w", false, usage)
+}
+
+// OpenInBrowser opens url, usually a resource's links.html, in the user's
+// browser and reports the URL that was opened.
+func OpenInBrowser(streams *iostreams.IOStreams, url string) error {
+	if url == "" {
+		return fmt.Errorf("no web URL available for this resource")
+	}
+	if err := browser.Open(url); err != nil {
+		return fmt.Errorf("could not open browser: %w", err)
+	}
+	streams.Success("Opened %s in your browser", url)
+	return nil
+}
+
+// RepoWebURL returns the URL of page, such as "pull-requests", on a
+// repository's website. It is built from the repository's links.html, so it
+// points at whichever host commands run against.
+func RepoWebURL(ctx context.Context, workspace, repoSlug, page string) (string, error) {
+	client, err := GetAPIClient()
+	if err != nil {
+		return "", err
+	}
+	repo, err := client.GetRepository(ctx, workspace, repoSlug)
+	if err != nil {
+		return "", err
+	}
+	if repo.Links.HTML.Href == "" {
+		return "", fmt.Errorf("no web URL available for %s/%s", workspace, repoSlug)
+	}
+	return strings.TrimSuffix(repo.Links.HTML.Href, "/") + "/" + page, nil
+}
@@ -0,0 +1,59 @@
+package cmdutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+func TestAddWebFlag(t *testing.T) {
+	var web bool
+	cmd := &cobra.Command{Use: "view"}
+	AddWebFlag(cmd, &web, "pull request")
+	flag := cmd.Flags().Lookup("web")
+	if flag == nil || flag.Shorthand != "w" {
+		t.Fatalf("--web = %+v, want it with the -w shorthand", flag)
+	}
+	if flag.Usage != "Open the pull request in a web browser" {
+		t.Errorf("usage = %q", flag.Usage)
+	}
+
+	// A command that already uses -w keeps it
+	var watch bool
+	cmd = &cobra.Command{Use: "list"}
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "")
+	AddWebFlag(cmd, &web, "issues page")
+	if flag := cmd.Flags().Lookup("web"); flag == nil || flag.Shorthand != "" {
+		t.Errorf("--web = %+v, want it without a shorthand", flag)
+	}
+	if cmd.Flags().ShorthandLookup("w").Name != "watch" {
+		t.Error("-w no longer means --watch")
+	}
+}
+
+func TestOpenInBrowser(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv("BB_BROWSER", "true")
+	var out bytes.Buffer
+	streams := &iostreams.IOStreams{Out: &out, ErrOut: &out}
+
+	if err := OpenInBrowser(streams, "https://bitbucket.org/team/api"); err != nil {
+		t.Fatalf("OpenInBrowser() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Opened https://bitbucket.org/team/api in your browser") {
+		t.Errorf("output = %q", out.String())
+	}
+
+	if err := OpenInBrowser(streams, ""); err == nil {
+		t.Error("OpenInBrowser() with no URL should fail")
+	}
+
+	t.Setenv("BB_BROWSER", "/nonexistent/browser")
+	if err := OpenInBrowser(streams, "https://bitbucket.org/team/api"); err == nil || !strings.Contains(err.Error(), "could not open browser") {
+		t.Errorf("OpenInBrowser() with a missing browser error = %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package cmdutil
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// CI systems whose log annotations bb can write
+const (
+	CIGitHub   = "github"
+	CITeamCity = "teamcity"
+	ciAuto     = "auto"
+	ciNone     = "none"
+)
+
+// AddCIFlag adds a --ci flag choosing the CI system to annotate output
+// for. Without the flag, the system bb runs in is detected from the
+// environment; --ci auto also falls back to GitHub Actions annotations
+// outside a system bb recognises.
+func AddCIFlag(cmd *cobra.Command, system *string) {
+	cmd.Flags().StringVar(system, "ci", "", "Annotate output for a CI system: auto, github, teamcity, or none (default: detected)")
+	_ = cmd.RegisterFlagCompletionFunc("ci", cobra.FixedCompletions([]string{ciAuto, CIGitHub, CITeamCity, ciNone}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// CI writes the annotations and log groups of a CI system, so failures
+// show up in its UI and long output can be collapsed
+type CI struct {
+	system string
+	w      io.Writer
+}
+
+// NewCI returns the CI annotator for the --ci flag value system, writing
+// to w, or nil if output should not be annotated.
+func NewCI(system string, w io.Writer) (*CI, error) {
+	switch system {
+	case "":
+		system = DetectCI()
+	case ciAuto:
+		system = DetectCI()
+		if system == "" {
+			system = CIGitHub
+		}
+	case ciNone:
+		return nil, nil
+	case CIGitHub, CITeamCity:
+	default:
+		return nil, NewExitError(ExitUsage, fmt.Errorf("invalid --ci value: %s (must be auto, github, teamcity, or none)", system))
+	}
+	if system == "" {
+		return nil, nil
+	}
+	return &CI{system: system, w: w}, nil
+}
+
+// DetectCI returns the CI system bb is running in, or "" outside one it
+// can annotate for
+func DetectCI() string {
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		return CIGitHub
+	case os.Getenv("TEAMCITY_VERSION") != "":
+		return CITeamCity
+	}
+	return ""
+}
+
+// Group starts a collapsible group of output lines named name
+func (c *CI) Group(name string) {
+	switch c.system {
+	case CIGitHub:
+		fmt.Fprintf(c.w, "::group::%s\n", githubData(name))
+	case CITeamCity:
+		fmt.Fprintf(c.w, "##teamcity[blockOpened name='%s']\n", teamCityValue(name))
+	}
+}
+
+// EndGroup ends the group started by Group(name)
+func (c *CI) EndGroup(name string) {
+	switch c.system {
+	case CIGitHub:
+		fmt.Fprintln(c.w, "::endgroup::")
+	case CITeamCity:
+		fmt.Fprintf(c.w, "##teamcity[blockClosed name='%s']\n", teamCityValue(name))
+	}
+}
+
+// Error reports a failure, titled title
+func (c *CI) Error(title, message string) {
+	c.annotate("error", "ERROR", title, message)
+}
+
+// Warning reports a problem that is not a failure, titled title
+func (c *CI) Warning(title, message string) {
+	c.annotate("warning", "WARNING", title, message)
+}
+
+func (c *CI) annotate(githubLevel, teamCityStatus, title, message string) {
+	switch c.system {
+	case CIGitHub:
+		fmt.Fprintf(c.w, "::%s title=%s::%s\n", githubLevel, githubProperty(title), githubData(message))
+	case CITeamCity:
+		text := title
+		if message != "" {
+			text += ": " + message
+		}
+		if githubLevel == "error" {
+			fmt.Fprintf(c.w, "##teamcity[buildProblem description='%s']\n", teamCityValue(text))
+			return
+		}
+		fmt.Fprintf(c.w, "##teamcity[message text='%s' status='%s']\n", teamCityValue(text), teamCityStatus)
+	}
+}
+
+// githubData escapes the message of a GitHub Actions workflow command
+func githubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// githubProperty escapes a property value of a GitHub Actions workflow
+// command
+func githubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// teamCityValue escapes a value of a TeamCity service message
+func teamCityValue(s string) string {
+	return strings.NewReplacer("|", "||", "'", "|'", "\n", "|n", "\r", "|r", "[", "|[", "]", "|]").Replace(s)
+}
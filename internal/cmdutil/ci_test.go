@@ -0,0 +1,70 @@
+package cmdutil
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestAddCIFlag(t *testing.T) {
+	var system string
+	var gotArgs []string
+	cmd := &cobra.Command{
+		Use:  "checks [<number>]",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			gotArgs = args
+			return nil
+		},
+	}
+	AddCIFlag(cmd, &system)
+	cmd.SetArgs([]string{"--ci", "teamcity", "42"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() returned error: %v", err)
+	}
+	if system != CITeamCity {
+		t.Errorf("--ci = %q, want %q", system, CITeamCity)
+	}
+	if len(gotArgs) != 1 || gotArgs[0] != "42" {
+		t.Errorf("args = %q, want [42]", gotArgs)
+	}
+}
+
+func TestNewCI(t *testing.T) {
+	tests := []struct {
+		name      string
+		system    string
+		githubEnv string
+		want      string
+		wantErr   bool
+	}{
+		{name: "not in CI", system: "", want: ""},
+		{name: "detected", system: "", githubEnv: "true", want: CIGitHub},
+		{name: "auto outside CI", system: "auto", want: CIGitHub},
+		{name: "explicit", system: "teamcity", githubEnv: "true", want: CITeamCity},
+		{name: "none in CI", system: "none", githubEnv: "true", want: ""},
+		{name: "invalid", system: "jenkins", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_ACTIONS", tt.githubEnv)
+			t.Setenv("TEAMCITY_VERSION", "")
+
+			ci, err := NewCI(tt.system, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewCI(%q) error = %v, wantErr %v", tt.system, err, tt.wantErr)
+			}
+			got := ""
+			if ci != nil {
+				got = ci.system
+			}
+			if got != tt.want {
+				t.Errorf("NewCI(%q) system = %q, want %q", tt.system, got, tt.want)
+			}
+		})
+	}
+}
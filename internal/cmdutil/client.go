@@ -3,13 +3,26 @@ package cmdutil
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/rbansal42/bitbucket-cli/internal/api"
 	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/i18n"
 )
 
+// extraClientOptions are applied to every client created by GetAPIClient.
+var extraClientOptions []api.ClientOption
+
+// AddAPIClientOption registers an option applied to every client created by
+// GetAPIClient, such as request hooks installed by global flags.
+func AddAPIClientOption(opt api.ClientOption) {
+	extraClientOptions = append(extraClientOptions, opt)
+}
+
 // GetAPIClient creates an authenticated API client.
 // This is the canonical implementation used by all commands.
 func GetAPIClient() (*api.Client, error) {
@@ -18,12 +31,21 @@ func GetAPIClient() (*api.Client, error) {
 		return nil, fmt.Errorf("failed to load hosts config: %w", err)
 	}
 
-	user := hosts.GetActiveUser(config.DefaultHost)
+	host, user, err := config.ActiveAccount(hosts)
+	if err != nil {
+		return nil, err
+	}
 	if user == "" {
-		return nil, fmt.Errorf("not logged in. Run 'bb auth login' to authenticate")
+		return nil, NewExitError(ExitAuth, errors.New(i18n.T("auth.not_logged_in")))
 	}
 
-	tokenData, _, err := config.GetTokenFromEnvOrKeyring(config.DefaultHost, user)
+	return GetAPIClientFor(hosts, host, user)
+}
+
+// GetAPIClientFor creates an API client authenticated as user on host,
+// rather than the active account.
+func GetAPIClientFor(hosts config.HostsConfig, host, user string) (*api.Client, error) {
+	tokenData, source, err := config.GetTokenFromEnvOrKeyring(host, user)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get token: %w", err)
 	}
@@ -35,17 +57,79 @@ func GetAPIClient() (*api.Client, error) {
 		if len(parts) != 2 {
 			return nil, fmt.Errorf("invalid stored credentials format")
 		}
-		return api.NewClient(api.WithBasicAuth(parts[0], parts[1])), nil
+		return newAPIClient(hosts, host, user, api.WithBasicAuth(parts[0], parts[1]))
 	}
 
 	// Try to parse as JSON (OAuth token) or use as plain token (Bearer)
-	var tokenResp struct {
-		AccessToken string `json:"access_token"`
-	}
-	token := tokenData
+	var tokenResp api.OAuthToken
 	if err := json.Unmarshal([]byte(tokenData), &tokenResp); err == nil && tokenResp.AccessToken != "" {
-		token = tokenResp.AccessToken
+		if source == config.CredentialSourceEnv {
+			return newAPIClient(hosts, host, user, api.WithToken(tokenResp.AccessToken))
+		}
+		return newAPIClient(hosts, host, user, api.WithTokenSource(NewOAuthTokenSource(host, user, source, tokenResp)))
+	}
+
+	return newAPIClient(hosts, host, user, api.WithToken(tokenData))
+}
+
+// NewOAuthTokenSource creates a token source that refreshes the OAuth token
+// of user on host when it expires and stores the new token back in source,
+// the credential store it was read from.
+// Tokens stored before the OAuth consumer was kept with them are refreshed
+// with BB_OAUTH_CLIENT_ID and BB_OAUTH_CLIENT_SECRET.
+func NewOAuthTokenSource(host, user, source string, token api.OAuthToken) *api.OAuthTokenSource {
+	if token.ClientID == "" || token.ClientSecret == "" {
+		token.ClientID = os.Getenv("BB_OAUTH_CLIENT_ID")
+		token.ClientSecret = os.Getenv("BB_OAUTH_CLIENT_SECRET")
+	}
+
+	ts := api.NewOAuthTokenSource(token, func(fresh *api.OAuthToken) error {
+		data, err := json.Marshal(fresh)
+		if err != nil {
+			return err
+		}
+		if source == config.CredentialSourceFallback {
+			return config.SetFallbackToken(host, user, string(data))
+		}
+		return config.SetToken(host, user, string(data))
+	})
+	ts.Logger = Logger()
+	if hosts, err := config.LoadHostsConfig(); err == nil {
+		if httpClient, err := NewHTTPClientFor(hosts, host, api.DefaultTimeout); err == nil {
+			ts.HTTPClient = httpClient
+		}
 	}
+	return ts
+}
 
-	return api.NewClient(api.WithToken(token)), nil
+// APIBaseURL returns the REST API base URL of the active host, for commands
+// that build request URLs themselves.
+func APIBaseURL() (string, error) {
+	hosts, err := config.LoadHostsConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load hosts config: %w", err)
+	}
+	host, _, err := config.ActiveAccount(hosts)
+	if err != nil {
+		return "", err
+	}
+	return hosts.APIURL(host), nil
+}
+
+func newAPIClient(hosts config.HostsConfig, host, user string, auth api.ClientOption) (*api.Client, error) {
+	opts := []api.ClientOption{api.WithBaseURL(hosts.APIURL(host)), auth}
+	if resolver, err := config.Resolve(); err == nil {
+		if timeout := resolver.Config().HTTPTimeout; timeout > 0 {
+			opts = append(opts, api.WithTimeout(time.Duration(timeout)*time.Second))
+		}
+	}
+	networkOpts, err := HostClientOptions(hosts, host)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, networkOpts...)
+	opts = append(opts, api.WithRequestHook(rateLimitHook(host)))
+	opts = append(opts, responseCacheOption(host, user))
+	opts = append(opts, extraClientOptions...)
+	return api.NewClient(opts...), nil
 }
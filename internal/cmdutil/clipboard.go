@@ -0,0 +1,112 @@
+package cmdutil
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// AddCopyFlag registers the --copy flag used by commands that can put their
+// resource's URL on the clipboard.
+func AddCopyFlag(cmd *cobra.Command, copy *bool, noun string) {
+	cmd.Flags().BoolVar(copy, "copy", false, fmt.Sprintf("Copy the %s URL to the clipboard", noun))
+}
+
+// CopyURL copies url to the clipboard and confirms it on stderr, so that the
+// command's regular output is left untouched.
+func CopyURL(streams *iostreams.IOStreams, url string) error {
+	if url == "" {
+		return fmt.Errorf("no web URL available to copy")
+	}
+	if err := CopyToClipboard(streams, url); err != nil {
+		return err
+	}
+	if !streams.IsQuiet() {
+		fmt.Fprintln(streams.ErrOut, streams.Style(iostreams.RoleSuccess, streams.StatusPrefix(iostreams.RoleSuccess)+"Copied "+url+" to the clipboard"))
+	}
+	return nil
+}
+
+// CopyToClipboard puts text on the system clipboard using the platform's
+// clipboard tool. Over SSH, or when no tool is installed, it falls back to
+// the OSC 52 escape sequence, which asks the local terminal emulator to set
+// the clipboard.
+func CopyToClipboard(streams *iostreams.IOStreams, text string) error {
+	if !isSSHSession() {
+		if args := clipboardCommand(); args != nil {
+			cmd := exec.Command(args[0], args[1:]...)
+			cmd.Stdin = strings.NewReader(text)
+			if err := cmd.Run(); err == nil {
+				return nil
+			}
+		}
+	}
+
+	var w io.Writer
+	switch {
+	case streams.IsStderrTTY():
+		w = streams.ErrOut
+	case streams.IsStdoutTTY():
+		w = streams.Out
+	default:
+		return fmt.Errorf("could not copy to clipboard: no clipboard tool found and not running in a terminal")
+	}
+	_, err := io.WriteString(w, osc52Sequence(text))
+	return err
+}
+
+// clipboardCommand returns the command that reads the clipboard contents
+// from stdin on this platform, or nil if none is available.
+func clipboardCommand() []string {
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip"}}
+	default:
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			candidates = append(candidates, []string{"wl-copy"})
+		}
+		if os.Getenv("DISPLAY") != "" {
+			candidates = append(candidates,
+				[]string{"xclip", "-selection", "clipboard"},
+				[]string{"xsel", "--clipboard", "--input"})
+		}
+		// WSL can reach the Windows clipboard
+		candidates = append(candidates, []string{"clip.exe"})
+	}
+
+	for _, args := range candidates {
+		if _, err := exec.LookPath(args[0]); err == nil {
+			return args
+		}
+	}
+	return nil
+}
+
+// osc52Sequence builds the escape sequence that sets the clipboard to text.
+// Inside tmux or GNU screen the sequence is wrapped so that they pass it
+// through to the outer terminal.
+func osc52Sequence(text string) string {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	case os.Getenv("STY") != "":
+		seq = "\x1bP" + seq + "\x1b\\"
+	}
+	return seq
+}
+
+func isSSHSession() bool {
+	return os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+}
@@ -0,0 +1,106 @@
+package cmdutil
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+func TestOSC52Sequence(t *testing.T) {
+	const url = "https://bitbucket.org/team/api/pull-requests/1"
+	const payload = "aHR0cHM6Ly9iaXRidWNrZXQub3JnL3RlYW0vYXBpL3B1bGwtcmVxdWVzdHMvMQ=="
+
+	tests := []struct {
+		name string
+		tmux string
+		sty  string
+		want string
+	}{
+		{name: "terminal", want: "\x1b]52;c;" + payload + "\a"},
+		{name: "tmux", tmux: "/tmp/tmux-1000/default,1,0", want: "\x1bPtmux;\x1b\x1b]52;c;" + payload + "\a\x1b\\"},
+		{name: "screen", sty: "1234.pts-0.host", want: "\x1bP\x1b]52;c;" + payload + "\a\x1b\\"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TMUX", tt.tmux)
+			t.Setenv("STY", tt.sty)
+			if got := osc52Sequence(url); got != tt.want {
+				t.Errorf("osc52Sequence() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeClipboard puts a clipboard tool on PATH that saves what it is given,
+// and returns a function reading it back
+func fakeClipboard(t *testing.T) func() string {
+	t.Helper()
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("the fake clipboard tool is a shell script named as on Linux")
+	}
+	dir := t.TempDir()
+	saved := filepath.Join(dir, "clipboard")
+	script := "#!/bin/sh\ncat > " + saved + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "clip.exe"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("DISPLAY", "")
+	t.Setenv("WAYLAND_DISPLAY", "")
+	t.Setenv("SSH_TTY", "")
+	t.Setenv("SSH_CONNECTION", "")
+	return func() string {
+		b, _ := os.ReadFile(saved)
+		return string(b)
+	}
+}
+
+func TestCopyURL(t *testing.T) {
+	clipboard := fakeClipboard(t)
+	var out, errOut bytes.Buffer
+	streams := &iostreams.IOStreams{Out: &out, ErrOut: &errOut}
+
+	if err := CopyURL(streams, "https://bitbucket.org/team/api"); err != nil {
+		t.Fatalf("CopyURL() error = %v", err)
+	}
+	if got := clipboard(); got != "https://bitbucket.org/team/api" {
+		t.Errorf("clipboard = %q", got)
+	}
+	if out.Len() != 0 || !strings.Contains(errOut.String(), "Copied https://bitbucket.org/team/api to the clipboard") {
+		t.Errorf("stdout = %q, stderr = %q, want the confirmation on stderr only", out.String(), errOut.String())
+	}
+
+	errOut.Reset()
+	streams.SetQuiet(true)
+	if err := CopyURL(streams, "https://bitbucket.org/team/api"); err != nil {
+		t.Fatalf("CopyURL() error = %v", err)
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("quiet CopyURL() wrote %q", errOut.String())
+	}
+
+	if err := CopyURL(streams, ""); err == nil {
+		t.Error("CopyURL() with no URL should fail")
+	}
+}
+
+func TestCopyToClipboard_NoTerminal(t *testing.T) {
+	// Over SSH the clipboard tools would copy on the remote machine, so
+	// only OSC 52 is tried, which needs a terminal
+	t.Setenv("SSH_TTY", "/dev/pts/0")
+	var out bytes.Buffer
+	streams := &iostreams.IOStreams{Out: &out, ErrOut: &out}
+
+	err := CopyToClipboard(streams, "text")
+	if err == nil || !strings.Contains(err.Error(), "not running in a terminal") {
+		t.Errorf("CopyToClipboard() error = %v, want one about the terminal", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("CopyToClipboard() wrote %q", out.String())
+	}
+}
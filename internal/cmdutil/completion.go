@@ -0,0 +1,180 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+)
+
+// How long the lists offered by shell completion are cached. They are kept
+// short, as branches and pull requests come and go while people work.
+const (
+	repoCacheTTL   = 10 * time.Minute
+	memberCacheTTL = 10 * time.Minute
+	branchCacheTTL = 2 * time.Minute
+	prCacheTTL     = time.Minute
+)
+
+// completionTimeout bounds the API requests made to complete a word, so a
+// slow network doesn't hang the shell
+const completionTimeout = 5 * time.Second
+
+// cachedList returns the list stored in the cache under key for the active
+// account, or else fetches it and caches it. Any failure yields no
+// completions rather than an error, since there is nowhere to report it.
+func cachedList(key string, ttl time.Duration, fetch func(ctx context.Context, client *api.Client) ([]string, error)) []string {
+	hosts, err := config.LoadHostsConfig()
+	if err != nil {
+		return nil
+	}
+	host, user, err := config.ActiveAccount(hosts)
+	if err != nil || user == "" {
+		return nil
+	}
+	key = host + "/" + user + "/" + key
+
+	var list []string
+	if config.ReadCache(key, ttl, &list) {
+		return list
+	}
+
+	client, err := GetAPIClient()
+	if err != nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	list, err = fetch(ctx, client)
+	if err != nil {
+		return nil
+	}
+	_ = config.WriteCache(key, list)
+	return list
+}
+
+// completionRepo returns the repository a command being completed operates
+// on, honouring its --repo flag
+func completionRepo(cmd *cobra.Command) (workspace, repoSlug string, ok bool) {
+	repoFlag, _ := cmd.Flags().GetString("repo")
+	workspace, repoSlug, err := ParseRepository(repoFlag)
+	return workspace, repoSlug, err == nil
+}
+
+// CompleteRepos completes WORKSPACE/REPO names from the workspace being
+// typed, or else the default workspace
+func CompleteRepos(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	workspace, _, found := strings.Cut(toComplete, "/")
+	if !found {
+		workspace, _ = config.GetDefaultWorkspace()
+	}
+	if workspace == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	repos := cachedList("repos/"+workspace, repoCacheTTL, func(ctx context.Context, client *api.Client) ([]string, error) {
+		result, err := client.ListRepositories(ctx, workspace, &api.RepositoryListOptions{
+			Sort:  "-updated_on",
+			Limit: 100,
+		})
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(result.Values))
+		for _, r := range result.Values {
+			names = append(names, r.FullName)
+		}
+		return names, nil
+	})
+	return repos, cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteBranches completes the branch names of the command's repository
+func CompleteBranches(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	workspace, repoSlug, ok := completionRepo(cmd)
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	branches := cachedList(fmt.Sprintf("branches/%s/%s", workspace, repoSlug), branchCacheTTL, func(ctx context.Context, client *api.Client) ([]string, error) {
+		result, err := client.ListBranches(ctx, workspace, repoSlug, &api.BranchListOptions{
+			Sort:  "-target.date",
+			Limit: 100,
+		})
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(result.Values))
+		for _, b := range result.Values {
+			names = append(names, b.Name)
+		}
+		return names, nil
+	})
+	return branches, cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteOpenPRs completes the numbers of the open pull requests of the
+// command's repository, described by their titles. Only the first argument
+// is completed.
+func CompleteOpenPRs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	workspace, repoSlug, ok := completionRepo(cmd)
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	prs := cachedList(fmt.Sprintf("prs/%s/%s", workspace, repoSlug), prCacheTTL, func(ctx context.Context, client *api.Client) ([]string, error) {
+		result, err := client.ListPullRequests(ctx, workspace, repoSlug, &api.PRListOptions{
+			State: api.PRStateOpen,
+			Limit: 50,
+		})
+		if err != nil {
+			return nil, err
+		}
+		numbers := make([]string, 0, len(result.Values))
+		for _, pr := range result.Values {
+			numbers = append(numbers, fmt.Sprintf("%d\t%s", pr.ID, pr.Title))
+		}
+		return numbers, nil
+	})
+	return prs, cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteMembers completes the usernames of the members of the workspace
+// of the command's repository
+func CompleteMembers(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	workspace, _, ok := completionRepo(cmd)
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	members := cachedList("members/"+workspace, memberCacheTTL, func(ctx context.Context, client *api.Client) ([]string, error) {
+		result, err := client.ListWorkspaceMembers(ctx, workspace, &api.WorkspaceMemberListOptions{
+			Limit: 100,
+		})
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(result.Values))
+		for _, m := range result.Values {
+			if m.User == nil || m.User.Username == "" {
+				continue
+			}
+			name := m.User.Username
+			if m.User.DisplayName != "" {
+				name += "\t" + m.User.DisplayName
+			}
+			names = append(names, name)
+		}
+		return names, nil
+	})
+	return members, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,157 @@
+package cmdutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+)
+
+// frontMatterDelimiter separates the front matter fields at the top of an
+// editor template from the body below it.
+const frontMatterDelimiter = "---"
+
+var htmlCommentPattern = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+// ErrEditorAborted is returned by OpenEditor when the user saved an empty
+// file, which is taken as a request to abort.
+var ErrEditorAborted = errors.New("aborted: nothing was entered in the editor")
+
+// EditorContent is the parsed result of an editing session.
+type EditorContent struct {
+	// Fields holds the "key: value" lines from the front matter block, if
+	// the template had one. Keys are lower-cased.
+	Fields map[string]string
+	// Body is the text after the front matter with HTML comments removed.
+	Body string
+}
+
+// Title returns the "title" front matter field.
+func (c *EditorContent) Title() string {
+	return c.Fields["title"]
+}
+
+// EditorTemplate builds a template with a title front matter block followed
+// by body. hint, if set, is added as an HTML comment, which is stripped from
+// the result.
+func EditorTemplate(title, body, hint string) string {
+	var b strings.Builder
+	b.WriteString(frontMatterDelimiter + "\n")
+	fmt.Fprintf(&b, "title: %s\n", title)
+	b.WriteString(frontMatterDelimiter + "\n")
+	b.WriteString(body)
+	if hint != "" {
+		if body != "" && !strings.HasSuffix(body, "\n") {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "\n<!-- %s -->\n", hint)
+	}
+	return b.String()
+}
+
+// OpenEditor opens template in the user's editor and parses the saved file.
+// It returns ErrEditorAborted if nothing but comments and empty fields
+// remain.
+func OpenEditor(template string) (*EditorContent, error) {
+	tmpFile, err := os.CreateTemp("", "bb-*.md")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.WriteString(template); err != nil {
+		tmpFile.Close()
+		return nil, fmt.Errorf("failed to write to temp file: %w", err)
+	}
+	tmpFile.Close()
+
+	// Editors are often configured with arguments, e.g. "code --wait"
+	args := strings.Fields(GetEditor())
+	if len(args) == 0 {
+		return nil, fmt.Errorf("no editor configured")
+	}
+	cmd := exec.Command(args[0], append(args[1:], tmpFile.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("editor exited with error: %w", err)
+	}
+
+	content, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read temp file: %w", err)
+	}
+
+	result := ParseEditorContent(string(content))
+	if result.isEmpty() {
+		return nil, ErrEditorAborted
+	}
+	return result, nil
+}
+
+// ParseEditorContent splits text into front matter fields and body and
+// strips HTML comments.
+func ParseEditorContent(text string) *EditorContent {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	result := &EditorContent{Fields: make(map[string]string)}
+
+	lines := strings.Split(text, "\n")
+	if strings.TrimSpace(lines[0]) == frontMatterDelimiter {
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) != frontMatterDelimiter {
+				continue
+			}
+			for _, line := range lines[1:i] {
+				key, value, found := strings.Cut(line, ":")
+				if !found {
+					continue
+				}
+				result.Fields[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+			}
+			text = strings.Join(lines[i+1:], "\n")
+			break
+		}
+	}
+
+	result.Body = strings.TrimSpace(htmlCommentPattern.ReplaceAllString(text, ""))
+	return result
+}
+
+func (c *EditorContent) isEmpty() bool {
+	if c.Body != "" {
+		return false
+	}
+	for _, v := range c.Fields {
+		if v != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// GetEditor returns the user's preferred editor
+func GetEditor() string {
+	// Check BB_EDITOR and config
+	if resolver, err := config.Resolve(); err == nil {
+		if editor := resolver.Get("editor").Value; editor != "" {
+			return editor
+		}
+	}
+
+	// Check standard environment variables
+	if editor := os.Getenv("VISUAL"); editor != "" {
+		return editor
+	}
+	if editor := os.Getenv("EDITOR"); editor != "" {
+		return editor
+	}
+
+	// Default to vi
+	return "vi"
+}
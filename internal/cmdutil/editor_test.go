@@ -0,0 +1,75 @@
+package cmdutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseEditorContent(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		wantFields map[string]string
+		wantBody   string
+	}{
+		{
+			name:       "title and body",
+			text:       "---\ntitle: Fix login\n---\nThe login form now validates input.\n",
+			wantFields: map[string]string{"title": "Fix login"},
+			wantBody:   "The login form now validates input.",
+		},
+		{
+			name:       "template round trip",
+			text:       EditorTemplate("Fix login", "Body text", "Lines in comments are ignored"),
+			wantFields: map[string]string{"title": "Fix login"},
+			wantBody:   "Body text",
+		},
+		{
+			name:       "keys are lower-cased and values keep their colons",
+			text:       "---\nTitle:  Fix: login  \nReviewers: alice, bob\nnot a field\n---\nbody",
+			wantFields: map[string]string{"title": "Fix: login", "reviewers": "alice, bob"},
+			wantBody:   "body",
+		},
+		{
+			name:       "empty title",
+			text:       "---\ntitle:\n---\n",
+			wantFields: map[string]string{"title": ""},
+			wantBody:   "",
+		},
+		{
+			name:       "no front matter",
+			text:       "Just a body\n",
+			wantFields: map[string]string{},
+			wantBody:   "Just a body",
+		},
+		{
+			name:       "unterminated front matter is body",
+			text:       "---\ntitle: Fix login\nbody",
+			wantFields: map[string]string{},
+			wantBody:   "---\ntitle: Fix login\nbody",
+		},
+		{
+			name:       "comments are stripped",
+			text:       "---\ntitle: Fix\n---\nkeep <!-- inline --> this\n<!--\nmulti-line\ncomment\n-->\nand this\n<!-- trailing -->\n",
+			wantFields: map[string]string{"title": "Fix"},
+			wantBody:   "keep  this\n\nand this",
+		},
+		{
+			name:       "CRLF line endings",
+			text:       "---\r\ntitle: Fix login\r\n---\r\nline one\r\nline two\r\n",
+			wantFields: map[string]string{"title": "Fix login"},
+			wantBody:   "line one\nline two",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseEditorContent(tt.text)
+			if !reflect.DeepEqual(got.Fields, tt.wantFields) {
+				t.Errorf("Fields = %q, want %q", got.Fields, tt.wantFields)
+			}
+			if got.Body != tt.wantBody {
+				t.Errorf("Body = %q, want %q", got.Body, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package cmdutil
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+// Exit codes returned by bb. These are part of the scripting interface and
+// must not change meaning once released.
+const (
+	ExitOK           = 0 // command succeeded
+	ExitFailure      = 1 // generic failure
+	ExitUsage        = 2 // invalid arguments or flags
+	ExitNotFound     = 3 // the requested resource does not exist
+	ExitAuth         = 4 // not logged in, or the credentials were rejected
+	ExitChecksFailed = 8 // a check or pipeline finished unsuccessfully
+	ExitTimeout      = 9 // gave up waiting before checks finished
+)
+
+// ExitError is an error that makes bb exit with a specific code.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// NewExitError wraps err so that bb exits with code.
+func NewExitError(code int, err error) *ExitError {
+	return &ExitError{Code: code, Err: err}
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the process exit code for err. Errors without an
+// explicit code are classified from the API status code where possible.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return ExitAuth
+		case http.StatusNotFound:
+			return ExitNotFound
+		}
+	}
+
+	return ExitFailure
+}
@@ -0,0 +1,36 @@
+package cmdutil
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, ExitOK},
+		{"generic error", errors.New("something broke"), ExitFailure},
+		{"exit error", NewExitError(ExitChecksFailed, errors.New("checks failed")), ExitChecksFailed},
+		{"wrapped exit error", fmt.Errorf("waiting: %w", NewExitError(ExitTimeout, errors.New("timed out"))), ExitTimeout},
+		{"usage error", NewExitError(ExitUsage, errors.New("unknown flag: --bogus")), ExitUsage},
+		{"unauthorized", &api.APIError{StatusCode: http.StatusUnauthorized}, ExitAuth},
+		{"forbidden", &api.APIError{StatusCode: http.StatusForbidden}, ExitAuth},
+		{"not found", fmt.Errorf("could not get pull request: %w", &api.APIError{StatusCode: http.StatusNotFound}), ExitNotFound},
+		{"server error", &api.APIError{StatusCode: http.StatusInternalServerError}, ExitFailure},
+		{"exit error wins over API error", NewExitError(ExitUsage, &api.APIError{StatusCode: http.StatusNotFound}), ExitUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package cmdutil
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// Column is a table column that can be selected with --fields.
+type Column[T any] struct {
+	// Name identifies the column in --fields and in config, e.g. "author"
+	Name string
+	// Header is shown above the column on a terminal
+	Header string
+	// Value renders the column for one row
+	Value func(T) string
+}
+
+// AddFieldsFlag registers the --fields flag, listing the names of columns
+// in its help text.
+func AddFieldsFlag[T any](cmd *cobra.Command, fields *[]string, columns []Column[T]) {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+	cmd.Flags().StringSliceVar(fields, "fields", nil,
+		fmt.Sprintf("Comma-separated columns to show: {%s}", strings.Join(names, "|")))
+}
+
+// SelectColumns returns the columns to show for command, e.g. "pr list",
+// in order. Columns named in fields (from --fields) take precedence, then
+// the command's fields.<command> config key, then defaults.
+func SelectColumns[T any](command string, fields []string, columns []Column[T], defaults []string) ([]Column[T], error) {
+	if len(fields) > 0 {
+		selected, err := lookupColumns(columns, fields)
+		if err != nil {
+			return nil, NewExitError(ExitUsage, err)
+		}
+		return selected, nil
+	}
+
+	name := strings.ReplaceAll(command, " ", ".")
+	if cfg, err := config.LoadConfig(); err == nil && cfg.Fields[name] != "" {
+		selected, err := lookupColumns(columns, strings.Split(cfg.Fields[name], ","))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s%s in config: %w", config.FieldsKeyPrefix, name, err)
+		}
+		return selected, nil
+	}
+
+	return lookupColumns(columns, defaults)
+}
+
+// lookupColumns returns the columns named in names, in that order. Names are
+// matched ignoring case and surrounding space, and a column named twice is
+// shown once.
+func lookupColumns[T any](columns []Column[T], names []string) ([]Column[T], error) {
+	var selected []Column[T]
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		found := false
+		for _, c := range columns {
+			if c.Name == name {
+				selected = append(selected, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			available := make([]string, len(columns))
+			for i, c := range columns {
+				available[i] = c.Name
+			}
+			return nil, fmt.Errorf("unknown field %q (available: %s)", name, strings.Join(available, ", "))
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no fields selected")
+	}
+	return selected, nil
+}
+
+// PrintColumns renders rows as a table with the given columns.
+func PrintColumns[T any](streams *iostreams.IOStreams, columns []Column[T], rows []T) error {
+	table := NewTablePrinter(streams)
+
+	headers := make([]string, len(columns))
+	for i, c := range columns {
+		headers[i] = c.Header
+	}
+	table.AddHeader(headers...)
+
+	for _, row := range rows {
+		fields := make([]string, len(columns))
+		for i, c := range columns {
+			fields[i] = c.Value(row)
+		}
+		table.AddRow(fields...)
+	}
+
+	return table.Render()
+}
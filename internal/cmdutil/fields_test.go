@@ -0,0 +1,79 @@
+package cmdutil
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+)
+
+type fieldsRow struct {
+	id, title, author string
+}
+
+var fieldsColumns = []Column[fieldsRow]{
+	{Name: "id", Header: "ID", Value: func(r fieldsRow) string { return r.id }},
+	{Name: "title", Header: "TITLE", Value: func(r fieldsRow) string { return r.title }},
+	{Name: "author", Header: "AUTHOR", Value: func(r fieldsRow) string { return r.author }},
+}
+
+func columnNames(columns []Column[fieldsRow]) []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func TestSelectColumns(t *testing.T) {
+	defaults := []string{"id", "title"}
+
+	tests := []struct {
+		name     string
+		fields   []string
+		config   string
+		want     []string
+		wantErr  string
+		wantCode int
+	}{
+		{name: "defaults", want: []string{"id", "title"}},
+		{name: "order of --fields", fields: []string{"author", "id"}, want: []string{"author", "id"}},
+		{name: "case and space", fields: []string{" Author", "ID "}, want: []string{"author", "id"}},
+		{name: "duplicates", fields: []string{"id", "title", "ID", "id"}, want: []string{"id", "title"}},
+		{name: "empty names", fields: []string{"", "title", " "}, want: []string{"title"}},
+		{name: "unknown field", fields: []string{"id", "reviewers"}, wantErr: `unknown field "reviewers" (available: id, title, author)`, wantCode: ExitUsage},
+		{name: "nothing selected", fields: []string{" "}, wantErr: "no fields selected", wantCode: ExitUsage},
+		{name: "config", config: "author,title", want: []string{"author", "title"}},
+		{name: "--fields over config", fields: []string{"id"}, config: "author,title", want: []string{"id"}},
+		{name: "unknown field in config", config: "id,nope", wantErr: `invalid fields.pr.list in config: unknown field "nope"`, wantCode: ExitFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BB_CONFIG_DIR", t.TempDir())
+			if tt.config != "" {
+				cfg := &config.Config{Fields: map[string]string{"pr.list": tt.config}}
+				if err := config.SaveConfig(cfg); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := SelectColumns("pr list", tt.fields, fieldsColumns, defaults)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("SelectColumns() error = %v, want %q", err, tt.wantErr)
+				}
+				if code := ExitCode(err); code != tt.wantCode {
+					t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectColumns() error = %v", err)
+			}
+			if names := columnNames(got); !slices.Equal(names, tt.want) {
+				t.Errorf("SelectColumns() = %q, want %q", names, tt.want)
+			}
+		})
+	}
+}
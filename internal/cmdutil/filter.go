@@ -0,0 +1,51 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+)
+
+// AddFilterFlag registers the --filter flag used by list commands that can
+// apply a saved filter, completing the names of the saved filters.
+func AddFilterFlag(cmd *cobra.Command, filter *string) {
+	cmd.Flags().StringVar(filter, "filter", "", "Apply a saved filter (see 'bb filter list')")
+	_ = cmd.RegisterFlagCompletionFunc("filter", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		names := cfg.FilterNames()
+		for i, name := range names {
+			names[i] = name + "\t" + cfg.Filters[name]
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// SavedFilter returns the query saved as the named filter, with "me"
+// compared with user fields replaced by the current user, which is looked
+// up only when needed.
+func SavedFilter(ctx context.Context, client *api.Client, name string) (string, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return "", fmt.Errorf("could not load config: %w", err)
+	}
+	query, ok := cfg.Filter(name)
+	if !ok {
+		return "", NewExitError(ExitUsage, fmt.Errorf("no saved filter named %q; see 'bb filter list'", name))
+	}
+	if !api.ReferencesMe(query) {
+		return query, nil
+	}
+
+	user, err := client.GetCurrentUser(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get current user: %w", err)
+	}
+	return api.ExpandMe(query, user), nil
+}
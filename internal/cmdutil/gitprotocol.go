@@ -0,0 +1,62 @@
+package cmdutil
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+)
+
+// AddProtocolFlag registers the --protocol flag used by commands that build
+// git URLs, overriding the configured git protocol.
+func AddProtocolFlag(cmd *cobra.Command, protocol *string) {
+	cmd.Flags().StringVar(protocol, "protocol", "", "Git protocol to use: ssh or https (default from git_protocol)")
+}
+
+// GitProtocol returns the protocol for the git URLs a command builds:
+// protocolFlag if given, then BB_GIT_PROTOCOL or .bb.yml, then the
+// git_protocol of the active host in hosts.yml, then the user config.
+func GitProtocol(protocolFlag string) (string, error) {
+	if protocolFlag != "" {
+		if protocolFlag != "ssh" && protocolFlag != "https" {
+			return "", NewExitError(ExitUsage, fmt.Errorf("invalid --protocol: %s (must be 'ssh' or 'https')", protocolFlag))
+		}
+		return protocolFlag, nil
+	}
+
+	resolver, err := config.Resolve()
+	if err != nil {
+		return "https", nil
+	}
+
+	setting := resolver.Get("git_protocol")
+	if setting.Source != config.SourceEnv && setting.Source != config.SourceRepo {
+		if hosts, err := config.LoadHostsConfig(); err == nil {
+			if host, _, err := config.ActiveAccount(hosts); err == nil && hosts[host] != nil && hosts[host].GitProtocol != "" {
+				return hosts[host].GitProtocol, nil
+			}
+		}
+	}
+
+	if setting.Value != "" {
+		return setting.Value, nil
+	}
+	return "https", nil
+}
+
+// CloneURL returns the clone URL of a repository for protocol, or any clone
+// URL if there is none for it.
+func CloneURL(links api.RepositoryLinks, protocol string) string {
+	for _, clone := range links.Clone {
+		if clone.Name == protocol {
+			return clone.Href
+		}
+	}
+
+	if len(links.Clone) > 0 {
+		return links.Clone[0].Href
+	}
+	return ""
+}
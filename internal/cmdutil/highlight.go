@@ -0,0 +1,246 @@
+package cmdutil
+
+import (
+	"path"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// syntax is what the highlighter knows about a language: enough to pick
+// out comments, strings, numbers and keywords, without parsing it
+type syntax struct {
+	lineComments []string
+	blockComment [2]string
+	quotes       string
+	keywords     []string
+	// ignoreCase matches keywords whatever their case, as SQL does
+	ignoreCase bool
+}
+
+var (
+	cSyntax    = syntax{lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: `"'`}
+	hashSyntax = syntax{lineComments: []string{"#"}, quotes: `"'`}
+)
+
+// with returns a copy of s with the given keywords
+func (s syntax) with(keywords ...string) *syntax {
+	s.keywords = keywords
+	return &s
+}
+
+var (
+	goSyntax = &syntax{lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\"'`", keywords: []string{
+		"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "false", "for",
+		"func", "go", "goto", "if", "import", "interface", "map", "nil", "package", "range", "return",
+		"select", "struct", "switch", "true", "type", "var",
+	}}
+	jsSyntax = &syntax{lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\"'`", keywords: []string{
+		"async", "await", "break", "case", "catch", "class", "const", "continue", "default", "delete", "do",
+		"else", "export", "extends", "false", "finally", "for", "from", "function", "if", "import", "in",
+		"instanceof", "interface", "let", "new", "null", "return", "switch", "this", "throw", "true", "try",
+		"type", "typeof", "undefined", "var", "void", "while", "yield",
+	}}
+	cFamilySyntax = cSyntax.with(
+		"abstract", "auto", "bool", "break", "case", "catch", "char", "class", "const", "continue", "default",
+		"do", "double", "else", "enum", "extends", "false", "final", "float", "for", "fun", "if", "implements",
+		"import", "include", "int", "interface", "long", "namespace", "new", "null", "nullptr", "override",
+		"package", "private", "protected", "public", "return", "short", "static", "struct", "switch", "this",
+		"throw", "throws", "true", "try", "typedef", "val", "var", "void", "while",
+	)
+	rustSyntax = cSyntax.with(
+		"as", "async", "await", "break", "const", "continue", "crate", "else", "enum", "false", "fn", "for",
+		"if", "impl", "in", "let", "loop", "match", "mod", "move", "mut", "pub", "ref", "return", "self",
+		"Self", "static", "struct", "super", "trait", "true", "type", "unsafe", "use", "where", "while",
+	)
+	pythonSyntax = hashSyntax.with(
+		"and", "as", "assert", "async", "await", "break", "class", "continue", "def", "del", "elif", "else",
+		"except", "False", "finally", "for", "from", "global", "if", "import", "in", "is", "lambda", "None",
+		"nonlocal", "not", "or", "pass", "raise", "return", "True", "try", "while", "with", "yield",
+	)
+	rubySyntax = hashSyntax.with(
+		"begin", "break", "case", "class", "def", "do", "else", "elsif", "end", "ensure", "false", "for", "if",
+		"in", "module", "next", "nil", "not", "or", "and", "require", "rescue", "return", "self", "then",
+		"true", "unless", "until", "when", "while", "yield",
+	)
+	shellSyntax = hashSyntax.with(
+		"case", "do", "done", "elif", "else", "esac", "export", "fi", "for", "function", "if", "in", "local",
+		"return", "then", "until", "while",
+	)
+	dataSyntax = hashSyntax.with("true", "false", "null", "yes", "no", "on", "off")
+	jsonSyntax = &syntax{quotes: `"`, keywords: []string{"true", "false", "null"}}
+	sqlSyntax  = &syntax{lineComments: []string{"--"}, blockComment: [2]string{"/*", "*/"}, quotes: `'"`, ignoreCase: true, keywords: []string{
+		"and", "as", "by", "create", "delete", "from", "group", "having", "in", "insert", "into", "is", "join",
+		"left", "limit", "not", "null", "on", "or", "order", "select", "set", "table", "update", "values", "where",
+	}}
+	dockerSyntax = hashSyntax.with(
+		"ADD", "ARG", "CMD", "COPY", "ENTRYPOINT", "ENV", "EXPOSE", "FROM", "HEALTHCHECK", "LABEL", "RUN",
+		"SHELL", "USER", "VOLUME", "WORKDIR", "AS",
+	)
+)
+
+// syntaxesByExtension are the languages highlighted, by file extension
+var syntaxesByExtension = map[string]*syntax{
+	".go":    goSyntax,
+	".js":    jsSyntax,
+	".jsx":   jsSyntax,
+	".mjs":   jsSyntax,
+	".ts":    jsSyntax,
+	".tsx":   jsSyntax,
+	".c":     cFamilySyntax,
+	".h":     cFamilySyntax,
+	".cc":    cFamilySyntax,
+	".cpp":   cFamilySyntax,
+	".hpp":   cFamilySyntax,
+	".cs":    cFamilySyntax,
+	".java":  cFamilySyntax,
+	".kt":    cFamilySyntax,
+	".kts":   cFamilySyntax,
+	".scala": cFamilySyntax,
+	".swift": cFamilySyntax,
+	".rs":    rustSyntax,
+	".py":    pythonSyntax,
+	".rb":    rubySyntax,
+	".sh":    shellSyntax,
+	".bash":  shellSyntax,
+	".zsh":   shellSyntax,
+	".yml":   dataSyntax,
+	".yaml":  dataSyntax,
+	".toml":  dataSyntax,
+	".json":  jsonSyntax,
+	".sql":   sqlSyntax,
+}
+
+// syntaxesByName are the languages of files recognised by name
+var syntaxesByName = map[string]*syntax{
+	"Dockerfile":  dockerSyntax,
+	"Makefile":    shellSyntax,
+	"Gemfile":     rubySyntax,
+	"Rakefile":    rubySyntax,
+	"Jenkinsfile": cFamilySyntax,
+}
+
+// NewHighlighter returns a function that highlights the lines of the file
+// named filename, one at a time and in order, in comments, strings, numbers
+// and keywords. It returns nil when color is off or the file's language
+// isn't known, so the result can be passed straight to CopyLines.
+func NewHighlighter(streams *iostreams.IOStreams, filename string) func(line string) string {
+	if !streams.ColorEnabled() {
+		return nil
+	}
+	base := path.Base(filename)
+	lang, ok := syntaxesByName[base]
+	if !ok {
+		lang, ok = syntaxesByExtension[strings.ToLower(path.Ext(base))]
+	}
+	if !ok {
+		return nil
+	}
+
+	h := &highlighter{streams: streams, lang: lang}
+	return h.line
+}
+
+// highlighter styles lines of a file, remembering across lines whether it
+// is in a block comment
+type highlighter struct {
+	streams *iostreams.IOStreams
+	lang    *syntax
+	inBlock bool
+}
+
+func (h *highlighter) line(line string) string {
+	var b strings.Builder
+	rest := line
+	open, end := h.lang.blockComment[0], h.lang.blockComment[1]
+	for rest != "" {
+		if h.inBlock {
+			i := strings.Index(rest, end)
+			if i < 0 {
+				b.WriteString(h.streams.Style(iostreams.RoleMuted, rest))
+				return b.String()
+			}
+			b.WriteString(h.streams.Style(iostreams.RoleMuted, rest[:i+len(end)]))
+			rest = rest[i+len(end):]
+			h.inBlock = false
+			continue
+		}
+		if open != "" && strings.HasPrefix(rest, open) {
+			h.inBlock = true
+			b.WriteString(h.streams.Style(iostreams.RoleMuted, open))
+			rest = rest[len(open):]
+			continue
+		}
+		if h.startsLineComment(rest) {
+			b.WriteString(h.streams.Style(iostreams.RoleMuted, rest))
+			return b.String()
+		}
+
+		r, size := utf8.DecodeRuneInString(rest)
+		switch {
+		case strings.ContainsRune(h.lang.quotes, r):
+			n := stringLiteralLength(rest, r)
+			b.WriteString(h.streams.Style(iostreams.RoleInfo, rest[:n]))
+			rest = rest[n:]
+		case isWordRune(r):
+			n := strings.IndexFunc(rest, func(r rune) bool { return !isWordRune(r) })
+			if n < 0 {
+				n = len(rest)
+			}
+			word := rest[:n]
+			switch {
+			case unicode.IsDigit(r):
+				b.WriteString(h.streams.Style(iostreams.RoleWarning, word))
+			case h.isKeyword(word):
+				b.WriteString(h.streams.Style(iostreams.RoleAccent, word))
+			default:
+				b.WriteString(word)
+			}
+			rest = rest[n:]
+		default:
+			b.WriteString(rest[:size])
+			rest = rest[size:]
+		}
+	}
+	return b.String()
+}
+
+func (h *highlighter) startsLineComment(s string) bool {
+	for _, prefix := range h.lang.lineComments {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *highlighter) isKeyword(word string) bool {
+	return slices.ContainsFunc(h.lang.keywords, func(k string) bool {
+		return k == word || (h.lang.ignoreCase && strings.EqualFold(k, word))
+	})
+}
+
+// stringLiteralLength returns the length of the string literal at the start
+// of s, which opens with quote, up to and including its closing quote or,
+// if it isn't closed on this line, the end of the line
+func stringLiteralLength(s string, quote rune) int {
+	escaped := false
+	for i, r := range s[utf8.RuneLen(quote):] {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && quote != '`':
+			escaped = true
+		case r == quote:
+			return utf8.RuneLen(quote) + i + utf8.RuneLen(r)
+		}
+	}
+	return len(s)
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,56 @@
+package cmdutil
+
+import (
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+func TestNewHighlighter(t *testing.T) {
+	streams := &iostreams.IOStreams{}
+	if h := NewHighlighter(streams, "main.go"); h != nil {
+		t.Error("expected no highlighter when color is off")
+	}
+	if err := streams.SetColorMode(iostreams.ColorAlways); err != nil {
+		t.Fatal(err)
+	}
+	if h := NewHighlighter(streams, "notes.txt"); h != nil {
+		t.Error("expected no highlighter for an unknown language")
+	}
+
+	style := streams.Style
+	h := NewHighlighter(streams, "cmd/main.go")
+	tests := []struct {
+		line string
+		want string
+	}{
+		{
+			line: `	return fmt.Sprintf("%d \"items\"", 42) // count`,
+			want: "\t" + style(iostreams.RoleAccent, "return") + " fmt.Sprintf(" + style(iostreams.RoleInfo, `"%d \"items\""`) +
+				", " + style(iostreams.RoleWarning, "42") + ") " + style(iostreams.RoleMuted, "// count"),
+		},
+		{
+			line: "x := 1 /* start",
+			want: "x := " + style(iostreams.RoleWarning, "1") + " " + style(iostreams.RoleMuted, "/*") + style(iostreams.RoleMuted, " start"),
+		},
+		{
+			// Still in the block comment opened on the line before
+			line: "end */ var",
+			want: style(iostreams.RoleMuted, "end */") + " " + style(iostreams.RoleAccent, "var"),
+		},
+		{
+			line: "forward := `raw`",
+			want: "forward := " + style(iostreams.RoleInfo, "`raw`"),
+		},
+	}
+	for _, tt := range tests {
+		if got := h(tt.line); got != tt.want {
+			t.Errorf("highlight(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	sql := NewHighlighter(streams, "schema.SQL")
+	if got, want := sql("SELECT 'a' -- note"), style(iostreams.RoleAccent, "SELECT")+" "+style(iostreams.RoleInfo, "'a'")+" "+style(iostreams.RoleMuted, "-- note"); got != want {
+		t.Errorf("highlight SQL = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,160 @@
+package cmdutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+)
+
+// warnedInsecureHosts records the hosts whose insecure_skip_tls has been
+// reported, so the warning is shown once per run
+var warnedInsecureHosts sync.Map
+
+// NewHTTPClient creates an HTTP client for requests bb makes to the active
+// host outside the API client, such as downloads, honouring the connection
+// pool and proxy settings in the config and the host's TLS settings.
+func NewHTTPClient(timeout time.Duration) (*http.Client, error) {
+	hosts, err := config.LoadHostsConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load hosts config: %w", err)
+	}
+	host, _, err := config.ActiveAccount(hosts)
+	if err != nil {
+		return nil, err
+	}
+	return NewHTTPClientFor(hosts, host, timeout)
+}
+
+// NewHTTPClientFor creates an HTTP client like NewHTTPClient for requests
+// to host.
+func NewHTTPClientFor(hosts config.HostsConfig, host string, timeout time.Duration) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transportConfig().Apply(transport)
+	if proxy, ok := proxyConfig(); ok {
+		transport.Proxy = proxy.ProxyFunc()
+	}
+	tlsConfig, err := HostTLSConfig(hosts, host)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+	if logFile != nil {
+		return &http.Client{Timeout: timeout, Transport: api.NewLoggingTransport(transport, logger)}, nil
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}, nil
+}
+
+// HostClientOptions returns the API client options for the network settings
+// that apply to host: the connection pool and proxy settings of the config
+// and the host's TLS settings.
+func HostClientOptions(hosts config.HostsConfig, host string) ([]api.ClientOption, error) {
+	opts := []api.ClientOption{api.WithTransportConfig(transportConfig())}
+	if proxy, ok := proxyConfig(); ok {
+		opts = append(opts, api.WithProxy(proxy))
+	}
+	tlsConfig, err := HostTLSConfig(hosts, host)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		opts = append(opts, api.WithTLSConfig(tlsConfig))
+	}
+	return opts, nil
+}
+
+// HostTLSConfig returns the TLS configuration for connections to host from
+// its ca_bundle and insecure_skip_tls settings, or nil if it has neither.
+// Disabling verification is reported on stderr, since it leaves the
+// connection open to interception.
+func HostTLSConfig(hosts config.HostsConfig, host string) (*tls.Config, error) {
+	hostConfig := hosts[host]
+	if hostConfig == nil || (hostConfig.CABundle == "" && !hostConfig.InsecureSkipTLS) {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if hostConfig.CABundle != "" {
+		pool, err := loadCABundle(hostConfig.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ca_bundle for %s: %w", host, err)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if hostConfig.InsecureSkipTLS {
+		cfg.InsecureSkipVerify = true
+		if _, warned := warnedInsecureHosts.LoadOrStore(host, true); !warned {
+			getStreams().Warning("TLS certificate verification is disabled for %s (insecure_skip_tls in %s)", host, config.HostsFileName)
+		}
+	}
+
+	return cfg, nil
+}
+
+// loadCABundle returns the system certificate pool with the PEM
+// certificates in path added to it.
+func loadCABundle(path string) (*x509.CertPool, error) {
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, path[2:])
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// proxyConfig returns the proxy settings of the config, and whether any are
+// set. Without them the standard proxy environment variables apply.
+func proxyConfig() (api.ProxyConfig, bool) {
+	resolver, err := config.Resolve()
+	if err != nil {
+		return api.ProxyConfig{}, false
+	}
+	cfg := resolver.Config()
+	proxy := api.ProxyConfig{
+		HTTPProxy:  cfg.HTTPProxy,
+		HTTPSProxy: cfg.HTTPSProxy,
+		NoProxy:    cfg.NoProxy,
+	}
+	return proxy, proxy != api.ProxyConfig{}
+}
+
+// transportConfig returns the connection pool settings of the config
+func transportConfig() api.TransportConfig {
+	resolver, err := config.Resolve()
+	if err != nil {
+		return api.TransportConfig{}
+	}
+	cfg := resolver.Config()
+	return api.TransportConfig{
+		MaxIdleConnsPerHost: cfg.HTTPIdleConns,
+		IdleConnTimeout:     time.Duration(cfg.HTTPIdleTimeout) * time.Second,
+		DisableHTTP2:        cfg.HTTP2 == "disabled",
+	}
+}
@@ -0,0 +1,31 @@
+package cmdutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+func TestHostTLSConfig_WarnsOnceWhenInsecure(t *testing.T) {
+	var errOut bytes.Buffer
+	SetStreams(&iostreams.IOStreams{ErrOut: &errOut})
+	t.Cleanup(func() { SetStreams(nil) })
+
+	hosts := config.HostsConfig{"insecure.example.com": {InsecureSkipTLS: true}}
+	for range 2 {
+		cfg, err := HostTLSConfig(hosts, "insecure.example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg == nil || !cfg.InsecureSkipVerify {
+			t.Fatalf("HostTLSConfig() = %+v, want InsecureSkipVerify", cfg)
+		}
+	}
+
+	if got := strings.Count(errOut.String(), "TLS certificate verification is disabled for insecure.example.com"); got != 1 {
+		t.Errorf("warned %d times, want once:\n%s", got, errOut.String())
+	}
+}
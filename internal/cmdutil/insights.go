@@ -0,0 +1,79 @@
+package cmdutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// reportSummaryData is how many of a report's values its summary shows
+const reportSummaryData = 3
+
+// SummarizeReport describes a Code Insights report on one line: its
+// result and its first few values, such as its coverage or the number of
+// vulnerabilities found
+func SummarizeReport(streams *iostreams.IOStreams, r api.Report) string {
+	var parts []string
+	if r.Result != "" {
+		parts = append(parts, FormatReportResult(streams, r.Result))
+	}
+	for _, d := range r.Data[:min(len(r.Data), reportSummaryData)] {
+		parts = append(parts, d.Title+" "+FormatReportData(d))
+	}
+	if more := len(r.Data) - reportSummaryData; more > 0 {
+		parts = append(parts, fmt.Sprintf("%d more", more))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatReportResult styles the result of a report or annotation
+func FormatReportResult(streams *iostreams.IOStreams, result string) string {
+	switch result {
+	case "PASSED":
+		return streams.Style(iostreams.RoleSuccess, "passed")
+	case "FAILED":
+		return streams.Style(iostreams.RoleError, "failed")
+	case "PENDING":
+		return streams.Style(iostreams.RoleWarning, "pending")
+	default:
+		return strings.ToLower(result)
+	}
+}
+
+// FormatReportData formats a value of a report by its type
+func FormatReportData(d api.ReportData) string {
+	switch v := d.Value.(type) {
+	case nil:
+		return "-"
+	case float64:
+		switch d.Type {
+		case "PERCENTAGE":
+			return strconv.FormatFloat(v, 'f', -1, 64) + "%"
+		case "DURATION":
+			return (time.Duration(v) * time.Millisecond).String()
+		case "DATE":
+			return time.UnixMilli(int64(v)).Format(time.DateTime)
+		default:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	case bool:
+		if v {
+			return "yes"
+		}
+		return "no"
+	case map[string]any:
+		// Links are given as their text and address
+		text, _ := v["text"].(string)
+		href, _ := v["href"].(string)
+		if text == "" || text == href {
+			return href
+		}
+		return fmt.Sprintf("%s (%s)", text, href)
+	default:
+		return fmt.Sprint(v)
+	}
+}
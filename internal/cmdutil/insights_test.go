@@ -0,0 +1,45 @@
+package cmdutil
+
+import (
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+func TestFormatReportData(t *testing.T) {
+	tests := []struct {
+		data api.ReportData
+		want string
+	}{
+		{api.ReportData{Type: "PERCENTAGE", Value: 82.5}, "82.5%"},
+		{api.ReportData{Type: "NUMBER", Value: float64(3)}, "3"},
+		{api.ReportData{Type: "DURATION", Value: float64(90500)}, "1m30.5s"},
+		{api.ReportData{Type: "BOOLEAN", Value: true}, "yes"},
+		{api.ReportData{Type: "TEXT", Value: "ok"}, "ok"},
+		{api.ReportData{Type: "LINK", Value: map[string]any{"text": "Dashboard", "href": "https://example.com"}}, "Dashboard (https://example.com)"},
+		{api.ReportData{Type: "NUMBER"}, "-"},
+	}
+	for _, tt := range tests {
+		if got := FormatReportData(tt.data); got != tt.want {
+			t.Errorf("FormatReportData(%+v) = %q, want %q", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestSummarizeReport(t *testing.T) {
+	streams := &iostreams.IOStreams{}
+	report := api.Report{
+		Result: "FAILED",
+		Data: []api.ReportData{
+			{Title: "Critical", Type: "NUMBER", Value: float64(1)},
+			{Title: "High", Type: "NUMBER", Value: float64(4)},
+			{Title: "Medium", Type: "NUMBER", Value: float64(0)},
+			{Title: "Low", Type: "NUMBER", Value: float64(9)},
+		},
+	}
+	want := "failed, Critical 1, High 4, Medium 0, 1 more"
+	if got := SummarizeReport(streams, report); got != want {
+		t.Errorf("SummarizeReport() = %q, want %q", got, want)
+	}
+}
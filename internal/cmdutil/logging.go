@@ -0,0 +1,59 @@
+package cmdutil
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/logging"
+)
+
+// logger is the log StartLogging opened; until then, and when logging is
+// off, entries are discarded
+var (
+	logger  = slog.New(slog.DiscardHandler)
+	logFile io.Closer
+)
+
+// StartLogging opens the log file if logging is on, which it is when level,
+// the log_level setting, or BB_LOG_FILE is set, and has the API clients
+// created from now on log to it. The level defaults to info and the file
+// to bb.log in the state directory.
+func StartLogging(level string) error {
+	path := os.Getenv(logging.FileEnvVar)
+	if level == "" && path == "" {
+		return nil
+	}
+	if level == "" {
+		level = logging.DefaultLevel
+	}
+	if path == "" {
+		var err error
+		if path, err = logging.DefaultPath(); err != nil {
+			return err
+		}
+	}
+
+	l, file, err := logging.Open(path, level)
+	if err != nil {
+		return err
+	}
+	logger, logFile = l, file
+	AddAPIClientOption(api.WithLogger(logger))
+	return nil
+}
+
+// Logger returns the log, which discards entries when logging is off
+func Logger() *slog.Logger {
+	return logger
+}
+
+// StopLogging closes the log file, if one was opened
+func StopLogging() {
+	if logFile != nil {
+		logFile.Close()
+		logFile = nil
+	}
+	logger = slog.New(slog.DiscardHandler)
+}
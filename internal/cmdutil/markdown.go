@@ -0,0 +1,122 @@
+package cmdutil
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+var (
+	mdHeadingPattern   = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdBulletPattern    = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	mdOrderedPattern   = regexp.MustCompile(`^(\s*)(\d+[.)])\s+(.*)$`)
+	mdTaskPattern      = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	mdRulePattern      = regexp.MustCompile(`^\s*([-*_])(\s*([-*_])){2,}\s*$`)
+	mdBoldPattern      = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	mdLinkPattern      = regexp.MustCompile(`!?\[([^\]]*)\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	mdInlineCodeFences = regexp.MustCompile("`+")
+)
+
+// RenderMarkdown formats markdown text, such as PR descriptions and
+// comments, for display on a terminal: headings and bold text are
+// emphasised, list bullets are drawn, code is highlighted and links show
+// their target. When stdout is not a terminal the text is returned as is.
+func RenderMarkdown(streams *iostreams.IOStreams, text string) string {
+	if !streams.IsStdoutTTY() {
+		return text
+	}
+	return renderMarkdown(streams, text)
+}
+
+// renderMarkdown formats markdown text for a terminal of the streams' width
+func renderMarkdown(streams *iostreams.IOStreams, text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	width := streams.TerminalWidth()
+
+	var out []string
+	inCode := false
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out = append(out, "    "+streams.Style(iostreams.RoleInfo, line))
+			continue
+		}
+
+		switch {
+		case mdHeadingPattern.MatchString(line):
+			m := mdHeadingPattern.FindStringSubmatch(line)
+			out = append(out, streams.Style(iostreams.RoleHeader, renderInline(streams, m[2])))
+		case mdRulePattern.MatchString(line):
+			out = append(out, streams.Style(iostreams.RoleMuted, strings.Repeat("─", min(width, 40))))
+		case strings.HasPrefix(trimmed, ">"):
+			quote := strings.TrimSpace(strings.TrimLeft(trimmed, ">"))
+			out = append(out, streams.Style(iostreams.RoleMuted, "│ "+renderInline(streams, quote)))
+		case mdBulletPattern.MatchString(line):
+			m := mdBulletPattern.FindStringSubmatch(line)
+			item := m[2]
+			bullet := "•"
+			if t := mdTaskPattern.FindStringSubmatch(item); t != nil {
+				bullet = "[ ]"
+				if t[1] != " " {
+					bullet = "[x]"
+				}
+				item = t[2]
+			}
+			out = append(out, m[1]+"  "+bullet+" "+renderInline(streams, item))
+		case mdOrderedPattern.MatchString(line):
+			m := mdOrderedPattern.FindStringSubmatch(line)
+			out = append(out, m[1]+"  "+m[2]+" "+renderInline(streams, m[3]))
+		default:
+			out = append(out, renderInline(streams, line))
+		}
+	}
+
+	return strings.TrimRight(strings.Join(out, "\n"), "\n")
+}
+
+// renderInline styles inline code spans, bold text and links. Code spans
+// are left untouched apart from their highlighting.
+func renderInline(streams *iostreams.IOStreams, line string) string {
+	bold := func(s string) string { return streams.Style(iostreams.RoleEmphasis, s) }
+
+	var b strings.Builder
+	for line != "" {
+		loc := mdInlineCodeFences.FindStringIndex(line)
+		if loc == nil {
+			b.WriteString(renderSpan(streams, line, bold))
+			break
+		}
+		fence := line[loc[0]:loc[1]]
+		end := strings.Index(line[loc[1]:], fence)
+		if end < 0 {
+			b.WriteString(renderSpan(streams, line, bold))
+			break
+		}
+		b.WriteString(renderSpan(streams, line[:loc[0]], bold))
+		code := strings.TrimSpace(line[loc[1] : loc[1]+end])
+		b.WriteString(streams.Style(iostreams.RoleInfo, code))
+		line = line[loc[1]+end+len(fence):]
+	}
+	return b.String()
+}
+
+func renderSpan(streams *iostreams.IOStreams, s string, bold func(string) string) string {
+	s = mdLinkPattern.ReplaceAllStringFunc(s, func(m string) string {
+		parts := mdLinkPattern.FindStringSubmatch(m)
+		label, target := parts[1], parts[2]
+		if label == "" || label == target {
+			return streams.Style(iostreams.RoleInfo, target)
+		}
+		return label + " (" + streams.Style(iostreams.RoleInfo, target) + ")"
+	})
+	return mdBoldPattern.ReplaceAllStringFunc(s, func(m string) string {
+		parts := mdBoldPattern.FindStringSubmatch(m)
+		return bold(parts[1] + parts[2])
+	})
+}
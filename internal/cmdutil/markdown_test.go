@@ -0,0 +1,94 @@
+package cmdutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+func TestRenderMarkdown_NotTerminal(t *testing.T) {
+	streams := &iostreams.IOStreams{Out: &bytes.Buffer{}}
+	text := "# Title\n\n- **item**\n"
+	if got := RenderMarkdown(streams, text); got != text {
+		t.Errorf("RenderMarkdown() = %q, want the text unchanged", got)
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	streams := &iostreams.IOStreams{Out: &bytes.Buffer{}}
+	if err := streams.SetColorMode(iostreams.ColorAlways); err != nil {
+		t.Fatal(err)
+	}
+	style := streams.Style
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "heading",
+			text: "## Summary ##",
+			want: style(iostreams.RoleHeader, "Summary"),
+		},
+		{
+			name: "bold",
+			text: "a **bold** and __also bold__ word",
+			want: "a " + style(iostreams.RoleEmphasis, "bold") + " and " + style(iostreams.RoleEmphasis, "also bold") + " word",
+		},
+		{
+			name: "inline code is not restyled",
+			text: "run `make **all**` now",
+			want: "run " + style(iostreams.RoleInfo, "make **all**") + " now",
+		},
+		{
+			name: "links",
+			text: "see [the docs](https://example.com/docs) or <https://example.com> [https://x.io](https://x.io)",
+			want: "see the docs (" + style(iostreams.RoleInfo, "https://example.com/docs") + ") or <https://example.com> " + style(iostreams.RoleInfo, "https://x.io"),
+		},
+		{
+			name: "lists",
+			text: "- one\n  * two\n1. first\n- [ ] todo\n- [x] done",
+			want: "  • one\n    • two\n  1. first\n  [ ] todo\n  [x] done",
+		},
+		{
+			name: "quote",
+			text: "> quoted",
+			want: style(iostreams.RoleMuted, "│ quoted"),
+		},
+		{
+			name: "rule",
+			text: "---",
+			want: style(iostreams.RoleMuted, strings.Repeat("─", 40)),
+		},
+		{
+			name: "code block",
+			text: "```go\nx := **1**\n```\nafter",
+			want: "    " + style(iostreams.RoleInfo, "x := **1**") + "\nafter",
+		},
+		{
+			name: "CRLF and trailing blank lines",
+			text: "line\r\n\r\n",
+			want: "line",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderMarkdown(streams, tt.text); got != tt.want {
+				t.Errorf("renderMarkdown(%q) =\n%q\nwant\n%q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdown_NoColor(t *testing.T) {
+	streams := &iostreams.IOStreams{Out: &bytes.Buffer{}}
+	if err := streams.SetColorMode(iostreams.ColorNever); err != nil {
+		t.Fatal(err)
+	}
+	if got := renderMarkdown(streams, "**bold**"); got != "bold" {
+		t.Errorf("renderMarkdown() without color = %q, want %q", got, "bold")
+	}
+}
@@ -0,0 +1,146 @@
+package cmdutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/i18n"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// OfflineEnvVar names the environment variable that, set to a true value,
+// runs commands offline, as --offline does
+const OfflineEnvVar = "BB_OFFLINE"
+
+// responseCacheTTL is how long API responses are kept to be served offline
+const responseCacheTTL = 30 * 24 * time.Hour
+
+// offline records whether commands run offline, and what the API clients
+// served from the cache during this run
+var offline = struct {
+	sync.Mutex
+	enabled bool
+	served  int
+	oldest  time.Time
+	cause   error
+}{}
+
+// SetOffline makes the API clients created from now on answer requests
+// from the cache only, never the network
+func SetOffline(enabled bool) {
+	offline.Lock()
+	defer offline.Unlock()
+	offline.enabled = enabled
+}
+
+// OfflineFromEnv reports whether BB_OFFLINE asks for commands to run
+// offline
+func OfflineFromEnv() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(OfflineEnvVar))
+	return enabled
+}
+
+// IsOffline reports whether commands run offline
+func IsOffline() bool {
+	offline.Lock()
+	defer offline.Unlock()
+	return offline.enabled
+}
+
+// responseCacheOption returns the client option that caches the responses
+// of user on host, and serves them offline or when the network is down
+func responseCacheOption(host, user string) api.ClientOption {
+	mode := api.CacheFallback
+	if IsOffline() {
+		mode = api.CacheOnly
+	}
+	return api.WithResponseCache(fileResponseCache{prefix: "responses/" + host + "/" + user}, mode, recordCachedResponse)
+}
+
+// recordCachedResponse is an api.CacheHook noting the responses served
+// from the cache, for WriteOfflineNotice
+func recordCachedResponse(resp *api.CachedResponse, cause error) {
+	offline.Lock()
+	defer offline.Unlock()
+	offline.served++
+	if offline.oldest.IsZero() || resp.FetchedAt.Before(offline.oldest) {
+		offline.oldest = resp.FetchedAt
+	}
+	if cause != nil && offline.cause == nil {
+		offline.cause = cause
+	}
+}
+
+// WriteOfflineNotice warns, on stderr, that what was shown came from the
+// cache and how old it is, if any of it did
+func WriteOfflineNotice(streams *iostreams.IOStreams) {
+	offline.Lock()
+	defer offline.Unlock()
+	if offline.served == 0 {
+		return
+	}
+
+	age := FormatTime(streams, offline.oldest)
+	if streams.TimestampStyle() != iostreams.TimestampsRelative {
+		age = i18n.T("offline.cached_at", "Time", age)
+	}
+	if offline.enabled {
+		streams.Warning("%s", i18n.T("offline.notice", "Age", age))
+	} else {
+		streams.Warning("%s", i18n.T("offline.fallback", "Cause", unwrapAll(offline.cause), "Age", age))
+	}
+}
+
+// unwrapAll returns the innermost error err wraps, which for network errors
+// is the readable part, e.g. "connection refused"
+func unwrapAll(err error) error {
+	for {
+		inner := errors.Unwrap(err)
+		if inner == nil {
+			return err
+		}
+		err = inner
+	}
+}
+
+// fileResponseCache keeps API responses in the cache directory, under
+// prefix and a hash of their URL
+type fileResponseCache struct {
+	prefix string
+}
+
+func (c fileResponseCache) key(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Method + " " + req.URL.String()))
+	return c.prefix + "/" + hex.EncodeToString(sum[:])
+}
+
+func (c fileResponseCache) Get(req *http.Request) (*api.CachedResponse, bool) {
+	var resp api.CachedResponse
+	if !config.ReadCache(c.key(req), responseCacheTTL, &resp) {
+		return nil, false
+	}
+	return &resp, true
+}
+
+func (c fileResponseCache) Put(req *http.Request, resp *api.CachedResponse) {
+	// The cache is a convenience, so failing to write it isn't an error
+	_ = config.WriteCache(c.key(req), resp)
+}
+
+// ExplainOffline adds how to get around err to it, if a request failed
+// because it can't be made offline
+func ExplainOffline(err error) error {
+	if !errors.Is(err, api.ErrOffline) {
+		return err
+	}
+	return fmt.Errorf("%w\n%s", err, i18n.T("offline.hint"))
+}
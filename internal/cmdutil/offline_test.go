@@ -0,0 +1,80 @@
+package cmdutil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+func TestFileResponseCache(t *testing.T) {
+	t.Setenv(config.CacheDirEnvVar, t.TempDir())
+
+	alice := fileResponseCache{prefix: "responses/bitbucket.org/alice"}
+	bob := fileResponseCache{prefix: "responses/bitbucket.org/bob"}
+	req, _ := http.NewRequest(http.MethodGet, "https://api.bitbucket.org/2.0/repositories/team/api/pullrequests?state=OPEN", nil)
+
+	if _, ok := alice.Get(req); ok {
+		t.Fatal("expected an empty cache")
+	}
+	alice.Put(req, &api.CachedResponse{StatusCode: 200, Body: []byte(`{"values": []}`), FetchedAt: time.Now()})
+	resp, ok := alice.Get(req)
+	if !ok || string(resp.Body) != `{"values": []}` {
+		t.Errorf("Get() = %+v, %v", resp, ok)
+	}
+	// Accounts don't see each other's responses
+	if _, ok := bob.Get(req); ok {
+		t.Error("expected another account's cache to be empty")
+	}
+}
+
+func TestWriteOfflineNotice(t *testing.T) {
+	defer SetOffline(false)
+	reset := func(enabled bool) {
+		offline.Lock()
+		offline.enabled, offline.served, offline.oldest, offline.cause = enabled, 0, time.Time{}, nil
+		offline.Unlock()
+	}
+
+	var out bytes.Buffer
+	streams := &iostreams.IOStreams{Out: &out, ErrOut: &out}
+
+	reset(true)
+	WriteOfflineNotice(streams)
+	if out.Len() != 0 {
+		t.Errorf("expected no notice when nothing was cached, got %q", out.String())
+	}
+
+	recordCachedResponse(&api.CachedResponse{FetchedAt: time.Now().Add(-10 * time.Minute)}, nil)
+	recordCachedResponse(&api.CachedResponse{FetchedAt: time.Now().Add(-3 * time.Hour)}, nil)
+	WriteOfflineNotice(streams)
+	if !strings.Contains(out.String(), "Offline: showing data cached 3 hours ago") {
+		t.Errorf("notice = %q", out.String())
+	}
+
+	out.Reset()
+	reset(false)
+	cause := errors.New("dial tcp: lookup api.bitbucket.org: no such host")
+	recordCachedResponse(&api.CachedResponse{FetchedAt: time.Now().Add(-2 * time.Hour)}, fmt.Errorf("request failed: %w", cause))
+	WriteOfflineNotice(streams)
+	if !strings.Contains(out.String(), "Could not reach Bitbucket (dial tcp: lookup api.bitbucket.org: no such host); showing data cached 2 hours ago") {
+		t.Errorf("notice = %q", out.String())
+	}
+}
+
+func TestExplainOffline(t *testing.T) {
+	if err := errors.New("boom"); ExplainOffline(err) != err {
+		t.Error("expected other errors to be left alone")
+	}
+	err := ExplainOffline(fmt.Errorf("failed to list pull requests: %w", api.ErrOffline))
+	if !errors.Is(err, api.ErrOffline) || !strings.Contains(err.Error(), "run the command again online") {
+		t.Errorf("ExplainOffline() = %v", err)
+	}
+}
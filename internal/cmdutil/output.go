@@ -6,11 +6,19 @@ import (
 	"fmt"
 	"io"
 	"strings"
-	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 
 	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
 
+// Structured output formats accepted by the --format flag.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
 // PrintJSON marshals v as indented JSON and writes it to streams.Out.
 func PrintJSON(streams *iostreams.IOStreams, v any) error {
 	data, err := json.MarshalIndent(v, "", "  ")
@@ -21,13 +29,86 @@ func PrintJSON(streams *iostreams.IOStreams, v any) error {
 	return nil
 }
 
-// PrintTableHeader writes a bold header line to a tabwriter if color is enabled,
-// otherwise writes a plain header.
-func PrintTableHeader(streams *iostreams.IOStreams, w *tabwriter.Writer, header string) {
-	if streams.ColorEnabled() {
-		fmt.Fprintln(w, iostreams.Bold+header+iostreams.Reset)
-	} else {
-		fmt.Fprintln(w, header)
+// PrintYAML writes v to streams.Out as YAML.
+//
+// v is encoded to JSON first so the output uses the same field names and
+// ordering as --json, then re-emitted in block style.
+func PrintYAML(streams *iostreams.IOStreams, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	return WriteJSONAsYAML(streams.Out, data)
+}
+
+// WriteJSONAsYAML converts a JSON document to YAML, preserving key order.
+func WriteJSONAsYAML(w io.Writer, data []byte) error {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	resetYAMLStyle(&node)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	return enc.Close()
+}
+
+// resetYAMLStyle clears the flow and quoting styles inherited from the JSON
+// source so the encoder picks idiomatic YAML styles. Strings that YAML 1.1
+// parsers would read as booleans stay quoted.
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+		switch strings.ToLower(node.Value) {
+		case "y", "n", "yes", "no", "on", "off":
+			node.Style = yaml.DoubleQuotedStyle
+		}
+	}
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
+
+// PrintFormatted writes v to streams.Out in the given structured format.
+// An empty format means JSON.
+func PrintFormatted(streams *iostreams.IOStreams, format string, v any) error {
+	if format == FormatYAML {
+		return PrintYAML(streams, v)
+	}
+	return PrintJSON(streams, v)
+}
+
+// formatValue is a pflag.Value restricting --format to the known formats.
+type formatValue struct {
+	target *string
+}
+
+func (f *formatValue) String() string { return *f.target }
+
+func (f *formatValue) Set(s string) error {
+	s = strings.ToLower(s)
+	if s != FormatJSON && s != FormatYAML {
+		return fmt.Errorf("invalid format %q (must be json or yaml)", s)
+	}
+	*f.target = s
+	return nil
+}
+
+func (f *formatValue) Type() string { return "string" }
+
+// AddFormatFlag registers --format on cmd. When called after a boolean --json
+// flag has been registered, the two flags are made mutually exclusive.
+func AddFormatFlag(cmd *cobra.Command, target *string) {
+	cmd.Flags().Var(&formatValue{target: target}, "format", "Output format: json or yaml")
+	_ = cmd.RegisterFlagCompletionFunc("format", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return []string{FormatJSON, FormatYAML}, cobra.ShellCompDirectiveNoFileComp
+	})
+	if f := cmd.Flags().Lookup("json"); f != nil && f.Value.Type() == "bool" {
+		cmd.MarkFlagsMutuallyExclusive("json", "format")
 	}
 }
 
@@ -0,0 +1,83 @@
+package cmdutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+func TestWriteJSONAsYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "keeps key order",
+			json: `{"title":"Fix login","id":42,"author":{"nickname":"alice","display_name":"Alice"}}`,
+			want: "title: Fix login\nid: 42\nauthor:\n  nickname: alice\n  display_name: Alice\n",
+		},
+		{
+			name: "lists and scalars",
+			json: `{"ids":[1,2],"draft":true,"closed_by":null}`,
+			want: "ids:\n  - 1\n  - 2\ndraft: true\nclosed_by: null\n",
+		},
+		{
+			name: "top-level list",
+			json: `[{"id":1},{"id":2}]`,
+			want: "- id: 1\n- id: 2\n",
+		},
+		{
+			name: "multi-line values",
+			json: `{"description":"line one\nline two\n","summary":"a\nb"}`,
+			want: "description: |\n  line one\n  line two\nsummary: |-\n  a\n  b\n",
+		},
+		{
+			name: "strings that read as other types stay quoted",
+			json: `{"answer":"yes","off":"Off","version":"123"}`,
+			want: "answer: \"yes\"\n\"off\": \"Off\"\nversion: \"123\"\n",
+		},
+		{
+			name: "empty object",
+			json: `{}`,
+			want: "{}\n",
+		},
+		{
+			name:    "invalid JSON",
+			json:    `{"id":`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := WriteJSONAsYAML(&out, []byte(tt.json))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WriteJSONAsYAML() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("WriteJSONAsYAML() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintFormatted_Quiet(t *testing.T) {
+	var out bytes.Buffer
+	streams := &iostreams.IOStreams{Out: &out, ErrOut: &out}
+	streams.SetQuiet(true)
+
+	// --quiet drops messages, not the data asked for with --format
+	if err := PrintFormatted(streams, "json", map[string]int{"id": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"id": 1`) {
+		t.Errorf("quiet --format json printed %q", out.String())
+	}
+}
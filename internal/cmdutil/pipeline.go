@@ -0,0 +1,40 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+// ResolvePipelineUUID resolves a pipeline build number or UUID to a UUID
+func ResolvePipelineUUID(ctx context.Context, client *api.Client, workspace, repoSlug, identifier string) (string, error) {
+	// Check if it's a build number
+	if buildNum, err := strconv.Atoi(identifier); err == nil {
+		// It's a build number, need to find the UUID
+		// List recent pipelines to find matching build number
+		result, err := client.ListPipelines(ctx, workspace, repoSlug, &api.PipelineListOptions{
+			Sort: "-created_on",
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to list pipelines: %w", err)
+		}
+
+		for _, p := range result.Values {
+			if p.BuildNumber == buildNum {
+				return p.UUID, nil
+			}
+		}
+		return "", fmt.Errorf("pipeline #%d not found", buildNum)
+	}
+
+	// It's already a UUID, clean it up
+	uuid := identifier
+	// Ensure UUID has curly braces
+	if len(uuid) > 0 && uuid[0] != '{' {
+		uuid = "{" + uuid + "}"
+	}
+
+	return uuid, nil
+}
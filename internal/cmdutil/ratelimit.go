@@ -0,0 +1,79 @@
+package cmdutil
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+)
+
+// rateLimitCacheTTL is how long observed rate limits are kept. Limits are
+// per hour, so older observations say nothing about the current state.
+const rateLimitCacheTTL = 24 * time.Hour
+
+// observedRateLimits holds the latest rate limit each host reported during
+// this run, by host and resource, until SaveRateLimits stores them
+var observedRateLimits = struct {
+	sync.Mutex
+	byHost map[string]map[string]api.RateLimit
+}{byHost: map[string]map[string]api.RateLimit{}}
+
+// rateLimitHook returns a request hook recording the rate limits host
+// reports
+func rateLimitHook(host string) api.RequestHook {
+	return func(info api.RequestInfo) {
+		if info.Header == nil {
+			return
+		}
+		rl, ok := api.ParseRateLimit(info.StatusCode, info.Header, time.Now())
+		if !ok {
+			return
+		}
+		observedRateLimits.Lock()
+		defer observedRateLimits.Unlock()
+		if observedRateLimits.byHost[host] == nil {
+			observedRateLimits.byHost[host] = map[string]api.RateLimit{}
+		}
+		observedRateLimits.byHost[host][rl.Resource] = rl
+	}
+}
+
+// SaveRateLimits stores the rate limits observed during this run, for
+// "bb limits" to report later. Errors are ignored, as the record is only
+// informational.
+func SaveRateLimits() {
+	observedRateLimits.Lock()
+	defer observedRateLimits.Unlock()
+	for host, observed := range observedRateLimits.byHost {
+		limits := map[string]api.RateLimit{}
+		config.ReadCache(rateLimitCacheKey(host), rateLimitCacheTTL, &limits)
+		maps.Copy(limits, observed)
+		_ = config.WriteCache(rateLimitCacheKey(host), limits)
+	}
+	clear(observedRateLimits.byHost)
+}
+
+// LoadRateLimits returns the latest rate limits host reported, by resource,
+// sorted by resource
+func LoadRateLimits(host string) []api.RateLimit {
+	limits := map[string]api.RateLimit{}
+	config.ReadCache(rateLimitCacheKey(host), rateLimitCacheTTL, &limits)
+
+	observedRateLimits.Lock()
+	defer observedRateLimits.Unlock()
+	maps.Copy(limits, observedRateLimits.byHost[host])
+
+	result := slices.Collect(maps.Values(limits))
+	slices.SortFunc(result, func(a, b api.RateLimit) int {
+		return strings.Compare(a.Resource, b.Resource)
+	})
+	return result
+}
+
+func rateLimitCacheKey(host string) string {
+	return "ratelimits/" + host
+}
@@ -0,0 +1,18 @@
+package cmdutil
+
+import (
+	"os"
+
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+)
+
+// LoadRepoConfig returns the .bb.yml settings of the current git repository.
+// The file only describes the repository it is checked into, so an empty
+// config is returned when repoFlag or BB_REPO selects a repository
+// explicitly or when not inside a git repository.
+func LoadRepoConfig(repoFlag string) (*config.RepoConfig, error) {
+	if repoFlag != "" || os.Getenv(RepoEnvVar) != "" {
+		return &config.RepoConfig{}, nil
+	}
+	return config.LoadCurrentRepoConfig()
+}
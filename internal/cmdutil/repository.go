@@ -2,14 +2,25 @@ package cmdutil
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
+	"github.com/rbansal42/bitbucket-cli/internal/config"
 	"github.com/rbansal42/bitbucket-cli/internal/git"
 )
 
-// ParseRepository parses a repository string in WORKSPACE/REPO format,
-// or detects the repository from the current git remote if not specified.
+// RepoEnvVar names the environment variable that selects the repository
+// when --repo is not given
+const RepoEnvVar = "BB_REPO"
+
+// ParseRepository parses a repository string in WORKSPACE/REPO format. If
+// repoFlag is empty, BB_REPO is used, then the repository pinned to the
+// current directory with 'bb repo set-default', then the current git remote,
+// then the active profile's default repo.
 func ParseRepository(repoFlag string) (workspace, repoSlug string, err error) {
+	if repoFlag == "" {
+		repoFlag = os.Getenv(RepoEnvVar)
+	}
 	if repoFlag != "" {
 		parts := strings.SplitN(repoFlag, "/", 2)
 		if len(parts) != 2 {
@@ -22,9 +33,16 @@ func ParseRepository(repoFlag string) (workspace, repoSlug string, err error) {
 		return parts[0], parts[1], nil
 	}
 
+	if pinned, _, err := config.CurrentPinnedRepo(); err == nil && pinned != "" {
+		return ParseRepository(pinned)
+	}
+
 	// Detect from git
 	remote, err := git.GetDefaultRemote()
 	if err != nil {
+		if profile, profileErr := config.LoadActiveProfile(); profileErr == nil && profile != nil && profile.Repo != "" {
+			return ParseRepository(profile.Repo)
+		}
 		return "", "", fmt.Errorf("could not detect repository: %w\nUse --repo WORKSPACE/REPO to specify", err)
 	}
 
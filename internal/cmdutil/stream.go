@@ -0,0 +1,66 @@
+package cmdutil
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// CopyLines copies r to w a line at a time, passing each line, without its
+// newline, through style if it is not nil. Only one line is held in memory
+// at once, so arbitrarily large diffs and logs can be shown. It returns the
+// number of bytes read from r.
+func CopyLines(w io.Writer, r io.Reader, style func(line string) string) (int64, error) {
+	br := bufio.NewReaderSize(r, 64*1024)
+	bw := bufio.NewWriterSize(w, 64*1024)
+
+	var read int64
+	for {
+		line, err := br.ReadString('\n')
+		read += int64(len(line))
+		if line != "" {
+			text, newline := strings.CutSuffix(line, "\n")
+			if style != nil {
+				text = style(text)
+			}
+			if newline {
+				text += "\n"
+			}
+			if _, werr := io.WriteString(bw, text); werr != nil {
+				return read, werr
+			}
+		}
+		if err == io.EOF {
+			return read, bw.Flush()
+		}
+		if err != nil {
+			bw.Flush()
+			return read, err
+		}
+	}
+}
+
+// ColorizeDiffLine styles a line of a diff using the theme's diff roles
+func ColorizeDiffLine(streams *iostreams.IOStreams, line string) string {
+	switch {
+	case strings.HasPrefix(line, "+++ ") || strings.HasPrefix(line, "--- "):
+		// File headers
+		return streams.Style(iostreams.RoleHeader, line)
+	case strings.HasPrefix(line, "+"):
+		// Additions
+		return streams.Style(iostreams.RoleAddition, line)
+	case strings.HasPrefix(line, "-"):
+		// Deletions
+		return streams.Style(iostreams.RoleDeletion, line)
+	case strings.HasPrefix(line, "@@"):
+		// Hunk headers
+		return streams.Style(iostreams.RoleInfo, line)
+	case strings.HasPrefix(line, "diff "):
+		// Diff headers
+		return streams.Style(iostreams.RoleHeader, line)
+	default:
+		return line
+	}
+}
@@ -0,0 +1,22 @@
+package cmdutil
+
+import "github.com/rbansal42/bitbucket-cli/internal/iostreams"
+
+// sharedStreams are the IOStreams of the running command, for warnings from
+// code that isn't given a command's streams; until SetStreams is called,
+// default ones are used
+var sharedStreams *iostreams.IOStreams
+
+// SetStreams sets the IOStreams that warnings from code outside a command,
+// such as the HTTP client setup, are written to
+func SetStreams(s *iostreams.IOStreams) {
+	sharedStreams = s
+}
+
+// getStreams returns the streams set with SetStreams, or default ones
+func getStreams() *iostreams.IOStreams {
+	if sharedStreams == nil {
+		sharedStreams = iostreams.New()
+	}
+	return sharedStreams
+}
@@ -0,0 +1,343 @@
+package cmdutil
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+const (
+	tableColumnGap = 2
+	tableMinWidth  = 5
+	ellipsis       = "..."
+)
+
+var (
+	ansiPattern    = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+	numericPattern = regexp.MustCompile(`^[#+-]?[0-9][0-9,.]*[a-zA-Z%]{0,3}$`)
+)
+
+// TablePrinter renders rows of text. On a terminal the columns are aligned
+// and fitted to the terminal width, truncating the widest columns first and
+// right-aligning numeric columns. When stdout is not a terminal the rows are
+// written as tab-separated values without a header, so output can be fed to
+// cut, awk and friends. In accessible mode each row is written on a line of
+// its own with the header of every field, for screen readers, which can't
+// follow columns.
+type TablePrinter struct {
+	streams    *iostreams.IOStreams
+	isTTY      bool
+	accessible bool
+	width      int
+	header     []string
+	rows       [][]string
+}
+
+// NewTablePrinter creates a TablePrinter writing to streams.Out.
+func NewTablePrinter(streams *iostreams.IOStreams) *TablePrinter {
+	return &TablePrinter{
+		streams:    streams,
+		isTTY:      streams.IsStdoutTTY(),
+		accessible: streams.IsAccessible(),
+		width:      streams.TerminalWidth(),
+	}
+}
+
+// AddHeader sets the column headers. Headers are only shown on a terminal.
+func (t *TablePrinter) AddHeader(columns ...string) {
+	t.header = columns
+}
+
+// AddRow appends a row. Fields may contain color escape sequences; they are
+// ignored when measuring and preserved when truncating.
+func (t *TablePrinter) AddRow(fields ...string) {
+	row := make([]string, len(fields))
+	for i, f := range fields {
+		row[i] = sanitizeField(f)
+	}
+	t.rows = append(t.rows, row)
+}
+
+// Render writes the table to the output stream.
+func (t *TablePrinter) Render() error {
+	if !t.isTTY {
+		for _, row := range t.rows {
+			if _, err := fmt.Fprintln(t.streams.Out, strings.Join(row, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if t.accessible {
+		return t.renderLinear()
+	}
+
+	numCols := len(t.header)
+	for _, row := range t.rows {
+		if len(row) > numCols {
+			numCols = len(row)
+		}
+	}
+	if numCols == 0 {
+		return nil
+	}
+
+	widths := t.fitWidths(numCols)
+	numeric := t.numericColumns(numCols)
+
+	var b strings.Builder
+	if len(t.header) > 0 {
+		t.writeLine(&b, t.header, widths, numeric, func(s string) string {
+			return t.streams.Style(iostreams.RoleHeader, s)
+		})
+	}
+	for _, row := range t.rows {
+		t.writeLine(&b, row, widths, numeric, nil)
+	}
+
+	_, err := fmt.Fprint(t.streams.Out, b.String())
+	return err
+}
+
+// renderLinear writes each row as its fields labelled with their headers,
+// e.g. "Id: 42, State: open, Title: Fix login"
+func (t *TablePrinter) renderLinear() error {
+	var b strings.Builder
+	for _, row := range t.rows {
+		fields := make([]string, 0, len(row))
+		for i, field := range row {
+			if i < len(t.header) && t.header[i] != "" {
+				field = headerLabel(t.header[i]) + ": " + field
+			}
+			fields = append(fields, field)
+		}
+		b.WriteString(strings.Join(fields, ", "))
+		b.WriteString("\n")
+	}
+	_, err := fmt.Fprint(t.streams.Out, b.String())
+	return err
+}
+
+// headerLabel turns a column header such as "UPDATED" into "Updated", which
+// screen readers say as a word rather than spell out
+func headerLabel(header string) string {
+	header = strings.ToLower(header)
+	r, size := utf8.DecodeRuneInString(header)
+	return string(unicode.ToUpper(r)) + header[size:]
+}
+
+func (t *TablePrinter) writeLine(b *strings.Builder, fields []string, widths []int, rightAlign []bool, style func(string) string) {
+	var line strings.Builder
+	for i, width := range widths {
+		field := ""
+		if i < len(fields) {
+			field = truncateVisible(fields[i], width)
+		}
+		pad := width - DisplayWidth(field)
+		last := i == len(widths)-1
+
+		if style != nil {
+			field = style(field)
+		}
+		if rightAlign != nil && rightAlign[i] {
+			line.WriteString(strings.Repeat(" ", pad))
+			line.WriteString(field)
+		} else {
+			line.WriteString(field)
+			if !last {
+				line.WriteString(strings.Repeat(" ", pad))
+			}
+		}
+		if !last {
+			line.WriteString(strings.Repeat(" ", tableColumnGap))
+		}
+	}
+	b.WriteString(strings.TrimRight(line.String(), " "))
+	b.WriteString("\n")
+}
+
+// fitWidths returns the width of each column. Columns keep their natural
+// width when everything fits; otherwise the available space is shared out
+// so that narrow columns stay intact and wide ones are truncated.
+func (t *TablePrinter) fitWidths(numCols int) []int {
+	natural := make([]int, numCols)
+	measure := func(fields []string) {
+		for i, f := range fields {
+			if w := DisplayWidth(f); w > natural[i] {
+				natural[i] = w
+			}
+		}
+	}
+	measure(t.header)
+	for _, row := range t.rows {
+		measure(row)
+	}
+
+	available := t.width - tableColumnGap*(numCols-1)
+	total := 0
+	for _, w := range natural {
+		total += w
+	}
+	if total <= available || available <= 0 {
+		return natural
+	}
+
+	widths := make([]int, numCols)
+	settled := make([]bool, numCols)
+	remaining := available
+	open := numCols
+	for open > 0 {
+		share := remaining / open
+		changed := false
+		for i := range natural {
+			if !settled[i] && natural[i] <= share {
+				widths[i] = natural[i]
+				settled[i] = true
+				remaining -= natural[i]
+				open--
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	// Split what is left between the columns that need truncating
+	for i := range natural {
+		if settled[i] {
+			continue
+		}
+		share := remaining / open
+		if share < tableMinWidth {
+			share = tableMinWidth
+		}
+		widths[i] = share
+		remaining -= share
+		open--
+	}
+	return widths
+}
+
+// numericColumns reports which columns contain only numbers (IDs, counts,
+// durations) and should be right-aligned.
+func (t *TablePrinter) numericColumns(numCols int) []bool {
+	numeric := make([]bool, numCols)
+	for i := range numeric {
+		seen := false
+		numeric[i] = true
+		for _, row := range t.rows {
+			if i >= len(row) {
+				continue
+			}
+			v := StripANSI(row[i])
+			if v == "" || v == "-" {
+				continue
+			}
+			seen = true
+			if !numericPattern.MatchString(v) {
+				numeric[i] = false
+				break
+			}
+		}
+		numeric[i] = numeric[i] && seen
+	}
+	return numeric
+}
+
+// StripANSI removes color escape sequences from s.
+func StripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
+
+// DisplayWidth returns the number of terminal cells s occupies, ignoring
+// color escape sequences.
+func DisplayWidth(s string) int {
+	width := 0
+	for _, r := range StripANSI(s) {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// wideRanges lists the code points drawn two cells wide: emoji with emoji
+// presentation and East Asian wide characters.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x23E9, 0x23EC}, {0x23F0, 0x23F0},
+	{0x23F3, 0x23F3}, {0x25FD, 0x25FE}, {0x2614, 0x2615}, {0x2648, 0x2653},
+	{0x267F, 0x267F}, {0x2693, 0x2693}, {0x26A1, 0x26A1}, {0x26AA, 0x26AB},
+	{0x26BD, 0x26BE}, {0x26C4, 0x26C5}, {0x26CE, 0x26CE}, {0x26D4, 0x26D4},
+	{0x26EA, 0x26EA}, {0x26F2, 0x26F3}, {0x26F5, 0x26F5}, {0x26FA, 0x26FA},
+	{0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B}, {0x2728, 0x2728},
+	{0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755}, {0x2757, 0x2757},
+	{0x2795, 0x2797}, {0x27B0, 0x27B0}, {0x27BF, 0x27BF}, {0x2B1B, 0x2B1C},
+	{0x2B50, 0x2B50}, {0x2B55, 0x2B55}, {0x2E80, 0xA4CF}, {0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF}, {0xFE30, 0xFE4F}, {0xFF00, 0xFF60}, {0xFFE0, 0xFFE6},
+	{0x1F300, 0x1F64F}, {0x1F680, 0x1F6FF}, {0x1F7E0, 0x1F7EB}, {0x1F900, 0x1F9FF},
+	{0x1FA70, 0x1FAFF}, {0x20000, 0x3FFFD},
+}
+
+// runeWidth returns the number of terminal cells r occupies.
+func runeWidth(r rune) int {
+	if r == 0x200D || (r >= 0xFE00 && r <= 0xFE0F) || unicode.Is(unicode.Mn, r) {
+		return 0
+	}
+	for _, rng := range wideRanges {
+		if r < rng[0] {
+			break
+		}
+		if r <= rng[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// truncateVisible shortens s to width visible characters, keeping any
+// escape sequences intact and resetting attributes if the cut happened
+// inside a styled span.
+func truncateVisible(s string, width int) string {
+	if DisplayWidth(s) <= width {
+		return s
+	}
+	keep, tail := width-DisplayWidth(ellipsis), ellipsis
+	if keep <= 0 {
+		// Too narrow for the ellipsis: cut the text to the width instead
+		keep, tail = width, ""
+	}
+
+	var b strings.Builder
+	styled := false
+	for len(s) > 0 && keep > 0 {
+		if loc := ansiPattern.FindStringIndex(s); loc != nil && loc[0] == 0 {
+			b.WriteString(s[:loc[1]])
+			styled = true
+			s = s[loc[1]:]
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s)
+		if runeWidth(r) > keep {
+			break
+		}
+		b.WriteRune(r)
+		s = s[size:]
+		keep -= runeWidth(r)
+	}
+	b.WriteString(tail)
+	if styled {
+		b.WriteString(iostreams.Reset)
+	}
+	return b.String()
+}
+
+// sanitizeField flattens multi-line text into a single line.
+func sanitizeField(s string) string {
+	if !strings.ContainsAny(s, "\r\n\t") {
+		return s
+	}
+	return strings.Join(strings.Fields(s), " ")
+}
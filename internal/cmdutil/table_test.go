@@ -0,0 +1,95 @@
+package cmdutil
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+func TestTablePrinterAccessible(t *testing.T) {
+	var out bytes.Buffer
+	streams := &iostreams.IOStreams{Out: &out}
+	streams.SetAccessible(true)
+
+	table := &TablePrinter{streams: streams, isTTY: true, accessible: streams.IsAccessible()}
+	table.AddHeader("ID", "TITLE", "STATE")
+	table.AddRow("#1", "Fix login", "OPEN")
+	table.AddRow("#2", "Add docs", "MERGED", "extra")
+	if err := table.Render(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Id: #1, Title: Fix login, State: OPEN\n" +
+		"Id: #2, Title: Add docs, State: MERGED, extra\n"
+	if got := out.String(); got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestFitWidths(t *testing.T) {
+	long := strings.Repeat("x", 30)
+	tests := []struct {
+		name  string
+		width int
+		rows  [][]string
+		want  []int
+	}{
+		{"fits", 80, [][]string{{"#1", "Fix login", "OPEN"}}, []int{2, 9, 4}},
+		{"unknown width", 0, [][]string{{"#1", long, "OPEN"}}, []int{2, 30, 4}},
+		{"one wide column", 30, [][]string{{"#1", long + long, "OPEN"}}, []int{2, 20, 4}},
+		{"two wide columns", 25, [][]string{{"#1", long, long}}, []int{2, 9, 10}},
+		{"minimum width", 10, [][]string{{long, long}}, []int{5, 5}},
+		{"wide characters", 20, [][]string{{"#1", strings.Repeat("日", 12)}}, []int{2, 16}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := &TablePrinter{width: tt.width, rows: tt.rows}
+			if got := table.fitWidths(len(tt.want)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fitWidths() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateVisible(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		want  string
+	}{
+		{"fits", "hello", 5, "hello"},
+		{"ascii", "hello world", 8, "hello..."},
+		{"wide characters", "日本語テキスト", 7, "日本..."},
+		{"wide character across the cut", "日本語テキスト", 6, "日..."},
+		{"too narrow for the ellipsis", "hello", 2, "he"},
+		{"wide characters too narrow for the ellipsis", "日本語", 3, "日"},
+		{"styled", "\x1b[31mhello world\x1b[0m", 8, "\x1b[31mhello..." + iostreams.Reset},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateVisible(tt.s, tt.width)
+			if got != tt.want {
+				t.Errorf("truncateVisible(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+			}
+			if w := DisplayWidth(got); w > tt.width {
+				t.Errorf("truncateVisible(%q, %d) is %d cells wide", tt.s, tt.width, w)
+			}
+		})
+	}
+}
+
+func TestNumericColumns(t *testing.T) {
+	table := &TablePrinter{}
+	table.AddRow("#1", "Fix login", "3", "-", "12%")
+	table.AddRow("#22", "Add docs", "1,024", "", "n/a")
+	table.AddRow("#3", "Short row")
+
+	want := []bool{true, false, true, false, false}
+	if got := table.numericColumns(len(want)); !reflect.DeepEqual(got, want) {
+		t.Errorf("numericColumns() = %v, want %v", got, want)
+	}
+}
@@ -2,17 +2,67 @@ package cmdutil
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
 )
 
+// absoluteTimeLayout is used for --timestamps absolute
+const absoluteTimeLayout = "2006-01-02 15:04"
+
+// FormatTime formats t in the style selected with --timestamps or the
+// timestamps config key: relative ("3 hours ago"), absolute local time, or
+// ISO 8601. Returns "-" for zero time values.
+func FormatTime(streams *iostreams.IOStreams, t time.Time) string {
+	return formatTime(streams, t, time.Now())
+}
+
+// formatTime is FormatTime with relative times counted back from now
+func formatTime(streams *iostreams.IOStreams, t, now time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	switch streams.TimestampStyle() {
+	case iostreams.TimestampsAbsolute:
+		return t.Local().Format(absoluteTimeLayout)
+	case iostreams.TimestampsISO:
+		return t.UTC().Format(time.RFC3339)
+	default:
+		return timeAgo(t, now)
+	}
+}
+
+// FormatTimeString is like FormatTime for an ISO 8601 / RFC3339 timestamp
+// string. Returns the raw string on parse failure.
+func FormatTimeString(streams *iostreams.IOStreams, isoTime string) string {
+	if isoTime == "" {
+		return "-"
+	}
+
+	t, err := parseTimestamp(isoTime)
+	if err != nil {
+		return isoTime
+	}
+
+	return FormatTime(streams, t)
+}
+
 // TimeAgo returns a human-readable relative time string for a time.Time value.
 // Returns "-" for zero time values.
 func TimeAgo(t time.Time) string {
+	return timeAgo(t, time.Now())
+}
+
+// timeAgo is TimeAgo counting back from now
+func timeAgo(t, now time.Time) string {
 	if t.IsZero() {
 		return "-"
 	}
 
-	duration := time.Since(t)
+	duration := now.Sub(t)
 
 	// Guard against future timestamps (clock skew, test data)
 	if duration < 0 {
@@ -55,20 +105,37 @@ func TimeAgo(t time.Time) string {
 	}
 }
 
-// TimeAgoFromString parses an ISO 8601 / RFC3339 timestamp string and returns
-// a human-readable relative time. Returns the raw string on parse failure.
-func TimeAgoFromString(isoTime string) string {
-	if isoTime == "" {
-		return "-"
+// ParseTimeFlag parses the value of a flag such as --since that takes a
+// point in time: a duration before now, which may be given in days (d) or
+// weeks (w), or a date
+func ParseTimeFlag(flag, value string, now time.Time) (time.Time, error) {
+	for _, unit := range []struct {
+		suffix string
+		length time.Duration
+	}{{"d", 24 * time.Hour}, {"w", 7 * 24 * time.Hour}} {
+		if n, ok := strings.CutSuffix(value, unit.suffix); ok {
+			if count, err := strconv.Atoi(n); err == nil && count >= 0 {
+				return now.Add(-time.Duration(count) * unit.length), nil
+			}
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
 	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid %s value %q: use a duration such as 2h, 3d or 1w, or a date such as 2024-05-01", flag, value)
+}
 
+// parseTimestamp parses the timestamp formats returned by the Bitbucket API
+func parseTimestamp(isoTime string) (time.Time, error) {
 	t, err := time.Parse(time.RFC3339, isoTime)
 	if err != nil {
 		t, err = time.Parse("2006-01-02T15:04:05.000000-07:00", isoTime)
-		if err != nil {
-			return isoTime
-		}
 	}
-
-	return TimeAgo(t)
+	return t, err
 }
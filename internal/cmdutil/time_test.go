@@ -0,0 +1,79 @@
+package cmdutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+func TestFormatTime(t *testing.T) {
+	origLocal := time.Local
+	t.Cleanup(func() { time.Local = origLocal })
+	time.Local = time.FixedZone("UTC+2", 2*60*60)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) time.Time { return now.Add(-d) }
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name  string
+		style string
+		t     time.Time
+		want  string
+	}{
+		{name: "zero", style: iostreams.TimestampsRelative, t: time.Time{}, want: "-"},
+		{name: "zero absolute", style: iostreams.TimestampsAbsolute, t: time.Time{}, want: "-"},
+		{name: "zero iso", style: iostreams.TimestampsISO, t: time.Time{}, want: "-"},
+		{name: "default style is relative", style: "", t: ago(3 * time.Hour), want: "3 hours ago"},
+		{name: "just now", style: iostreams.TimestampsRelative, t: ago(59 * time.Second), want: "just now"},
+		{name: "now", style: iostreams.TimestampsRelative, t: now, want: "just now"},
+		{name: "1 minute", style: iostreams.TimestampsRelative, t: ago(time.Minute), want: "1 minute ago"},
+		{name: "minutes", style: iostreams.TimestampsRelative, t: ago(59 * time.Minute), want: "59 minutes ago"},
+		{name: "1 hour", style: iostreams.TimestampsRelative, t: ago(time.Hour), want: "1 hour ago"},
+		{name: "hours", style: iostreams.TimestampsRelative, t: ago(23 * time.Hour), want: "23 hours ago"},
+		{name: "1 day", style: iostreams.TimestampsRelative, t: ago(day), want: "1 day ago"},
+		{name: "days", style: iostreams.TimestampsRelative, t: ago(29 * day), want: "29 days ago"},
+		{name: "1 month", style: iostreams.TimestampsRelative, t: ago(30 * day), want: "1 month ago"},
+		{name: "months", style: iostreams.TimestampsRelative, t: ago(364 * day), want: "12 months ago"},
+		{name: "1 year", style: iostreams.TimestampsRelative, t: ago(365 * day), want: "1 year ago"},
+		{name: "years", style: iostreams.TimestampsRelative, t: ago(3 * 365 * day), want: "3 years ago"},
+		{name: "future", style: iostreams.TimestampsRelative, t: now.Add(time.Hour), want: "in the future"},
+		{name: "future absolute", style: iostreams.TimestampsAbsolute, t: now.Add(time.Hour), want: "2024-05-01 15:00"},
+		{name: "absolute in local time", style: iostreams.TimestampsAbsolute, t: ago(3 * time.Hour), want: "2024-05-01 11:00"},
+		{name: "iso in UTC", style: iostreams.TimestampsISO, t: time.Date(2024, 5, 1, 14, 30, 0, 0, time.Local), want: "2024-05-01T12:30:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streams := &iostreams.IOStreams{}
+			if err := streams.SetTimestampStyle(tt.style); err != nil {
+				t.Fatal(err)
+			}
+			if got := formatTime(streams, tt.t, now); got != tt.want {
+				t.Errorf("formatTime() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatTimeString(t *testing.T) {
+	streams := &iostreams.IOStreams{}
+	if err := streams.SetTimestampStyle(iostreams.TimestampsISO); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "", want: "-"},
+		{in: "2024-05-01T12:30:00Z", want: "2024-05-01T12:30:00Z"},
+		{in: "2024-05-01T14:30:00.123456+02:00", want: "2024-05-01T12:30:00Z"},
+		{in: "yesterday", want: "yesterday"},
+	}
+	for _, tt := range tests {
+		if got := FormatTimeString(streams, tt.in); got != tt.want {
+			t.Errorf("FormatTimeString(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
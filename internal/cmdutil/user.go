@@ -1,6 +1,17 @@
 package cmdutil
 
-import "github.com/rbansal42/bitbucket-cli/internal/api"
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+// accountIDPattern matches an Atlassian account ID, such as
+// 557058:0c6f4ad0-8c8a-4d0e-9a3c-8d1e0b9c7a10 or 5b10ac8d82e05b22cc7d4ef5
+var accountIDPattern = regexp.MustCompile(`^(\d+:[0-9a-fA-F-]+|[0-9a-fA-F]{24})$`)
 
 // GetUserDisplayName returns the best available display name for a user.
 // Returns "-" if user is nil, falls back through Username → Nickname → "unknown".
@@ -19,3 +30,39 @@ func GetUserDisplayName(user *api.User) string {
 	}
 	return "unknown"
 }
+
+// ResolveWorkspaceUser returns the UUID or account ID of the user named by
+// user, and the name to show them by: "me" or "@me" for the current user, a
+// UUID or account ID as is, or the nickname, username or display name of a
+// member of workspace
+func ResolveWorkspaceUser(ctx context.Context, client *api.Client, workspace, user string) (string, string, error) {
+	switch {
+	case user == "me" || user == "@me":
+		me, err := client.GetCurrentUser(ctx)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get current user: %w", err)
+		}
+		return me.UUID, GetUserDisplayName(me), nil
+	case strings.HasPrefix(user, "{"), accountIDPattern.MatchString(user):
+		return user, user, nil
+	}
+
+	for page := 1; ; page++ {
+		members, err := client.ListWorkspaceMembers(ctx, workspace, &api.WorkspaceMemberListOptions{Page: page, Limit: 100})
+		if err != nil {
+			return "", "", fmt.Errorf("failed to list workspace members: %w", err)
+		}
+		for _, m := range members.Values {
+			if m.User == nil {
+				continue
+			}
+			if strings.EqualFold(m.User.Nickname, user) || strings.EqualFold(m.User.Username, user) || strings.EqualFold(m.User.DisplayName, user) {
+				return m.User.UUID, GetUserDisplayName(m.User), nil
+			}
+		}
+		if members.Next == "" || len(members.Values) == 0 {
+			break
+		}
+	}
+	return "", "", NewExitError(ExitNotFound, fmt.Errorf("no member of %s named %q; give users outside the workspace by UUID or account ID", workspace, user))
+}
@@ -0,0 +1,49 @@
+package cmdutil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+func TestResolveWorkspaceUser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			w.Write([]byte(`{"next": "https://api.bitbucket.org/next", "values": [
+				{"user": {"uuid": "{a}", "nickname": "asmith", "display_name": "Alice Smith"}}
+			]}`))
+			return
+		}
+		w.Write([]byte(`{"values": [{"user": {"uuid": "{b}", "nickname": "bjones", "display_name": "Bob Jones"}}]}`))
+	}))
+	defer server.Close()
+	client := api.NewClient(api.WithBaseURL(server.URL))
+
+	tests := []struct {
+		user     string
+		wantID   string
+		wantName string
+		wantErr  bool
+	}{
+		{user: "{c}", wantID: "{c}", wantName: "{c}"},
+		{user: "557058:0c6f4ad0-8c8a-4d0e-9a3c-8d1e0b9c7a10", wantID: "557058:0c6f4ad0-8c8a-4d0e-9a3c-8d1e0b9c7a10", wantName: "557058:0c6f4ad0-8c8a-4d0e-9a3c-8d1e0b9c7a10"},
+		{user: "ASMITH", wantID: "{a}", wantName: "Alice Smith"},
+		{user: "Bob Jones", wantID: "{b}", wantName: "Bob Jones"},
+		{user: "nobody", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.user, func(t *testing.T) {
+			id, name, err := ResolveWorkspaceUser(context.Background(), client, "myworkspace", tt.user)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveWorkspaceUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if id != tt.wantID || name != tt.wantName {
+				t.Errorf("ResolveWorkspaceUser() = %q, %q, want %q, %q", id, name, tt.wantID, tt.wantName)
+			}
+		})
+	}
+}
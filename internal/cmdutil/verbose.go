@@ -0,0 +1,86 @@
+package cmdutil
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/i18n"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+// APICallLog records the API requests made during a command for the
+// --verbose summary.
+type APICallLog struct {
+	mu    sync.Mutex
+	calls []api.RequestInfo
+}
+
+// NewAPICallLog creates an APICallLog that records the requests of every
+// client created by GetAPIClient from now on.
+func NewAPICallLog() *APICallLog {
+	l := &APICallLog{}
+	AddAPIClientOption(api.WithRequestHook(l.Record))
+	return l
+}
+
+// Record is an api.RequestHook that appends info to the log.
+func (l *APICallLog) Record(info api.RequestInfo) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls = append(l.calls, info)
+}
+
+// WriteSummary prints each recorded request with its status and duration,
+// and whether it was served from the response cache, followed by the
+// totals, to stderr.
+func (l *APICallLog) WriteSummary(streams *iostreams.IOStreams) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.calls) == 0 {
+		fmt.Fprintln(streams.ErrOut, streams.Style(iostreams.RoleMuted, i18n.T("verbose.no_calls")))
+		return
+	}
+
+	var total time.Duration
+	cached := 0
+	fmt.Fprintln(streams.ErrOut)
+	for _, call := range l.calls {
+		total += call.Duration
+
+		status := "ERR"
+		role := iostreams.RoleError
+		if call.StatusCode != 0 {
+			status = fmt.Sprintf("%d", call.StatusCode)
+			if call.StatusCode < 400 {
+				role = iostreams.RoleSuccess
+			}
+		}
+
+		path := call.URL.Path
+		if call.URL.RawQuery != "" {
+			path += "?" + call.URL.RawQuery
+		}
+		if call.Cached {
+			cached++
+			path += streams.Style(iostreams.RoleMuted, " ("+i18n.T("verbose.cached")+")")
+		}
+		fmt.Fprintf(streams.ErrOut, "%-6s %s %6s  %s\n",
+			call.Method, streams.Style(role, status), formatCallDuration(call.Duration), path)
+	}
+
+	summary := i18n.N("verbose.summary", len(l.calls), "Duration", formatCallDuration(total))
+	if cached > 0 {
+		summary += ", " + i18n.T("verbose.summary_cached", "Count", cached)
+	}
+	fmt.Fprintln(streams.ErrOut, streams.Style(iostreams.RoleMuted, summary))
+}
+
+func formatCallDuration(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%.2fs", d.Seconds())
+}
@@ -0,0 +1,46 @@
+package cmdutil
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+func TestAPICallLogWriteSummary(t *testing.T) {
+	l := &APICallLog{}
+	l.Record(api.RequestInfo{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Path: "/2.0/user"},
+		StatusCode: http.StatusOK,
+		Duration:   120 * time.Millisecond,
+	})
+	l.Record(api.RequestInfo{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Path: "/2.0/repositories/team", RawQuery: "page=2"},
+		StatusCode: http.StatusOK,
+		Cached:     true,
+	})
+
+	var errOut bytes.Buffer
+	l.WriteSummary(&iostreams.IOStreams{Out: &bytes.Buffer{}, ErrOut: &errOut})
+
+	lines := strings.Split(strings.TrimSpace(errOut.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 2 calls and the totals, got:\n%s", errOut.String())
+	}
+	if strings.Contains(lines[0], "cached") || !strings.Contains(lines[0], "/2.0/user") {
+		t.Errorf("fetched call = %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "/2.0/repositories/team?page=2 (cached)") {
+		t.Errorf("cached call = %q, want it marked as cached", lines[1])
+	}
+	if want := "2 API calls in 120ms, 1 from the cache"; lines[2] != want {
+		t.Errorf("totals = %q, want %q", lines[2], want)
+	}
+}
@@ -0,0 +1,183 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+const (
+	// DefaultWatchInterval is used when --watch is given without a value
+	DefaultWatchInterval = 10 * time.Second
+
+	minWatchInterval = time.Second
+)
+
+// AddWatchFlag adds a --watch flag that takes an optional refresh interval,
+// e.g. --watch or --watch=30s.
+func AddWatchFlag(cmd *cobra.Command, interval *time.Duration) {
+	cmd.Flags().DurationVar(interval, "watch", 0, "Refresh the output every `interval` until interrupted")
+	cmd.Flags().Lookup("watch").NoOptDefVal = DefaultWatchInterval.String()
+}
+
+// Watch calls render every interval until interrupted, clearing the screen
+// before each refresh. Lines that differ from the previous refresh are
+// highlighted. In accessible mode the screen is never cleared: the first
+// refresh is written in full and later ones only list the lines that
+// changed, labelled, so a screen reader reads each change once. A refresh
+// that fails shows its error and watching carries on, unless the error is
+// one refreshing again can't fix.
+func Watch(ctx context.Context, streams *iostreams.IOStreams, interval time.Duration, render func() error) error {
+	if !streams.IsStdoutTTY() {
+		return fmt.Errorf("--watch requires stdout to be a terminal")
+	}
+	if interval < minWatchInterval {
+		return fmt.Errorf("watch interval must be at least %s", minWatchInterval)
+	}
+
+	// The pager would block the refresh loop
+	streams.DisablePager()
+	// A screen reader would read out what each refresh is fetching
+	if streams.IsAccessible() {
+		streams.DisableProgress()
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	return watch(ctx, streams, interval, render)
+}
+
+// watch is the refresh loop of Watch, which runs until ctx is done
+func watch(ctx context.Context, streams *iostreams.IOStreams, interval time.Duration, render func() error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var previous []string
+	for {
+		output, err := streams.CaptureOutput(render)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil && watchStopsOn(err):
+			return err
+		case err != nil:
+			// The last good output stays what the next refresh is
+			// compared with
+			fmt.Fprint(streams.Out, watchFailed(streams, previous, err, interval, time.Now()))
+		default:
+			current := strings.Split(strings.TrimRight(output, "\n"), "\n")
+			if streams.IsAccessible() {
+				fmt.Fprint(streams.Out, watchChanges(previous, current, interval, time.Now()))
+			} else {
+				fmt.Fprint(streams.Out, watchScreen(streams, previous, current, interval, time.Now()))
+			}
+			previous = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchStopsOn reports whether err, from a refresh, ends watching because
+// refreshing again can't fix it: the credentials were refused, what is
+// watched doesn't exist, or the command was used wrongly
+func watchStopsOn(err error) bool {
+	switch ExitCode(err) {
+	case ExitAuth, ExitNotFound, ExitUsage:
+		return true
+	}
+	return false
+}
+
+// watchFailed shows err in place of a refresh. The screen keeps the
+// previous lines under it, so a brief outage doesn't blank the output; in
+// accessible mode only the error is written.
+func watchFailed(streams *iostreams.IOStreams, previous []string, err error, interval time.Duration, now time.Time) string {
+	if streams.IsAccessible() {
+		return fmt.Sprintf("\nCould not refresh at %s: %v\n", now.Format("15:04:05"), err)
+	}
+
+	var b strings.Builder
+	b.WriteString("\033[H\033[2J")
+	status := fmt.Sprintf("Every %s, refresh failed at %s. Press Ctrl-C to stop.", interval, now.Format("15:04:05"))
+	b.WriteString(streams.Style(iostreams.RoleMuted, status) + "\n")
+	b.WriteString(streams.Style(iostreams.RoleError, fmt.Sprintf("Could not refresh: %v", err)) + "\n\n")
+	for _, line := range previous {
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// watchScreen redraws the screen with lines, highlighting those that are
+// not in previous
+func watchScreen(streams *iostreams.IOStreams, previous, lines []string, interval time.Duration, now time.Time) string {
+	var b strings.Builder
+	b.WriteString("\033[H\033[2J")
+	status := fmt.Sprintf("Every %s, last updated %s. Press Ctrl-C to stop.", interval, now.Format("15:04:05"))
+	b.WriteString(streams.Style(iostreams.RoleMuted, status) + "\n\n")
+
+	seen := plainLines(previous)
+	for _, line := range lines {
+		if previous != nil && !seen[StripANSI(line)] {
+			line = streams.Style(iostreams.RoleAccent, StripANSI(line))
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// watchChanges returns lines in full the first time, when previous is nil,
+// and after that only the lines added and removed since previous, or
+// nothing if there are none
+func watchChanges(previous, lines []string, interval time.Duration, now time.Time) string {
+	var b strings.Builder
+	if previous == nil {
+		fmt.Fprintf(&b, "Refreshing every %s. Press Ctrl-C to stop.\n\n", interval)
+		for _, line := range lines {
+			b.WriteString(StripANSI(line) + "\n")
+		}
+		return b.String()
+	}
+
+	before, after := plainLines(previous), plainLines(lines)
+	var changes []string
+	for _, line := range lines {
+		if plain := StripANSI(line); !before[plain] {
+			changes = append(changes, "Added: "+plain)
+		}
+	}
+	for _, line := range previous {
+		if plain := StripANSI(line); !after[plain] {
+			changes = append(changes, "Removed: "+plain)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	fmt.Fprintf(&b, "\nUpdated at %s:\n", now.Format("15:04:05"))
+	for _, change := range changes {
+		b.WriteString(change + "\n")
+	}
+	return b.String()
+}
+
+// plainLines returns the set of lines, without color
+func plainLines(lines []string) map[string]bool {
+	set := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		set[StripANSI(line)] = true
+	}
+	return set
+}
@@ -0,0 +1,138 @@
+package cmdutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/iostreams"
+)
+
+func TestWatchChanges(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	first := []string{"#1  Fix login  OPEN", "#2  Add docs  OPEN"}
+
+	got := watchChanges(nil, first, 10*time.Second, now)
+	want := "Refreshing every 10s. Press Ctrl-C to stop.\n\n" +
+		"#1  Fix login  OPEN\n" +
+		"#2  Add docs  OPEN\n"
+	if got != want {
+		t.Errorf("first refresh = %q, want %q", got, want)
+	}
+
+	if got := watchChanges(first, first, 10*time.Second, now); got != "" {
+		t.Errorf("unchanged refresh = %q, want nothing", got)
+	}
+
+	second := []string{"#1  Fix login  \x1b[35mMERGED\x1b[0m", "#2  Add docs  OPEN"}
+	got = watchChanges(first, second, 10*time.Second, now)
+	want = "\nUpdated at 09:30:00:\n" +
+		"Added: #1  Fix login  MERGED\n" +
+		"Removed: #1  Fix login  OPEN\n"
+	if got != want {
+		t.Errorf("changed refresh = %q, want %q", got, want)
+	}
+}
+
+func TestWatch_KeepsGoingAfterErrors(t *testing.T) {
+	for _, accessible := range []bool{false, true} {
+		var out bytes.Buffer
+		streams := &iostreams.IOStreams{Out: &out, ErrOut: &out}
+		streams.SetAccessible(accessible)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// The second refresh fails, the third succeeds again and the
+		// fourth is interrupted
+		refreshes := 0
+		err := watch(ctx, streams, time.Millisecond, func() error {
+			refreshes++
+			switch refreshes {
+			case 2:
+				return errors.New("connection reset by peer")
+			case 3:
+				fmt.Fprintln(streams.Out, "#1  Fix login  MERGED")
+			case 4:
+				cancel()
+			default:
+				fmt.Fprintln(streams.Out, "#1  Fix login  OPEN")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("accessible=%v: watch() error = %v", accessible, err)
+		}
+		if refreshes != 4 {
+			t.Errorf("accessible=%v: watch() refreshed %d times, want 4", accessible, refreshes)
+		}
+		for _, want := range []string{"connection reset by peer", "#1  Fix login  OPEN", "#1  Fix login  MERGED"} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("accessible=%v: output %q doesn't contain %q", accessible, out.String(), want)
+			}
+		}
+	}
+}
+
+func TestWatch_StopsOnPermanentErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		stop bool
+	}{
+		{name: "not found", err: fmt.Errorf("failed to list pull requests: %w", &api.APIError{StatusCode: 404}), stop: true},
+		{name: "unauthorized", err: &api.APIError{StatusCode: 401}, stop: true},
+		{name: "usage", err: NewExitError(ExitUsage, errors.New("invalid state")), stop: true},
+		{name: "server error", err: &api.APIError{StatusCode: 503}},
+		{name: "rate limited", err: &api.APIError{StatusCode: 429}},
+		{name: "network", err: errors.New("dial tcp: i/o timeout")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			streams := &iostreams.IOStreams{Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}}
+
+			refreshes := 0
+			err := watch(ctx, streams, time.Millisecond, func() error {
+				refreshes++
+				if refreshes > 1 {
+					cancel()
+				}
+				return tt.err
+			})
+			if tt.stop && (err != tt.err || refreshes != 1) {
+				t.Errorf("watch() = %v after %d refreshes, want it to stop with %v", err, refreshes, tt.err)
+			}
+			if !tt.stop && (err != nil || refreshes != 2) {
+				t.Errorf("watch() = %v after %d refreshes, want it to keep refreshing", err, refreshes)
+			}
+		})
+	}
+}
+
+func TestWatchFailed(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	previous := []string{"#1  Fix login  OPEN"}
+	err := errors.New("connection reset by peer")
+
+	streams := &iostreams.IOStreams{}
+	got := watchFailed(streams, previous, err, 10*time.Second, now)
+	want := "\033[H\033[2J" +
+		"Every 10s, refresh failed at 09:30:00. Press Ctrl-C to stop.\n" +
+		"Could not refresh: connection reset by peer\n\n" +
+		"#1  Fix login  OPEN\n"
+	if got != want {
+		t.Errorf("watchFailed() = %q, want %q", got, want)
+	}
+
+	streams.SetAccessible(true)
+	got = watchFailed(streams, previous, err, 10*time.Second, now)
+	if want := "\nCould not refresh at 09:30:00: connection reset by peer\n"; got != want {
+		t.Errorf("accessible watchFailed() = %q, want %q", got, want)
+	}
+}
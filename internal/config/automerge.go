@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AutoMergeFileName is the name of the file recording the pull requests to
+// merge once they are ready
+const AutoMergeFileName = "automerge.yml"
+
+// AutoMerge records that a pull request should be merged, with the given
+// strategy, once its checks pass and it has the approvals it needs
+type AutoMerge struct {
+	Host         string    `yaml:"host"`
+	Workspace    string    `yaml:"workspace"`
+	Repo         string    `yaml:"repo"`
+	ID           int64     `yaml:"id"`
+	Method       string    `yaml:"method"`
+	Message      string    `yaml:"message,omitempty"`
+	DeleteBranch bool      `yaml:"delete_branch,omitempty"`
+	RequestedAt  time.Time `yaml:"requested_at"`
+}
+
+// Same reports whether a and b are for the same pull request
+func (a AutoMerge) Same(b AutoMerge) bool {
+	return a.Host == b.Host && a.Workspace == b.Workspace && a.Repo == b.Repo && a.ID == b.ID
+}
+
+// LoadAutoMerges loads the pull requests waiting to be merged
+func LoadAutoMerges() ([]AutoMerge, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, AutoMergeFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read auto-merge file: %w", err)
+	}
+
+	var merges []AutoMerge
+	if err := yaml.Unmarshal(data, &merges); err != nil {
+		return nil, fmt.Errorf("could not parse auto-merge file: %w", err)
+	}
+	return merges, nil
+}
+
+// SaveAutoMerges saves the pull requests waiting to be merged, removing the
+// file when there are none
+func SaveAutoMerges(merges []AutoMerge) error {
+	if len(merges) == 0 {
+		dir, err := ConfigDir()
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(filepath.Join(dir, AutoMergeFileName)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("could not remove auto-merge file: %w", err)
+		}
+		return nil
+	}
+
+	dir, err := EnsureConfigDir()
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(merges)
+	if err != nil {
+		return fmt.Errorf("could not marshal auto-merges: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, AutoMergeFileName), data, 0600); err != nil {
+		return fmt.Errorf("could not write auto-merge file: %w", err)
+	}
+	return nil
+}
+
+// AddAutoMerge records m, replacing any earlier request for the same pull
+// request
+func AddAutoMerge(m AutoMerge) error {
+	merges, err := LoadAutoMerges()
+	if err != nil {
+		return err
+	}
+	merges = removeAutoMerge(merges, m)
+	return SaveAutoMerges(append(merges, m))
+}
+
+// RemoveAutoMerge forgets the request to merge the pull request of m, and
+// reports whether there was one
+func RemoveAutoMerge(m AutoMerge) (bool, error) {
+	merges, err := LoadAutoMerges()
+	if err != nil {
+		return false, err
+	}
+	remaining := removeAutoMerge(merges, m)
+	if len(remaining) == len(merges) {
+		return false, nil
+	}
+	return true, SaveAutoMerges(remaining)
+}
+
+func removeAutoMerge(merges []AutoMerge, m AutoMerge) []AutoMerge {
+	var result []AutoMerge
+	for _, existing := range merges {
+		if !existing.Same(m) {
+			result = append(result, existing)
+		}
+	}
+	return result
+}
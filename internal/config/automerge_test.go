@@ -0,0 +1,46 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAutoMerges(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("BB_CONFIG_DIR", dir)
+
+	first := AutoMerge{Host: "bitbucket.org", Workspace: "team", Repo: "api", ID: 1, Method: "merge"}
+	second := AutoMerge{Host: "bitbucket.org", Workspace: "team", Repo: "api", ID: 2, Method: "squash"}
+	if err := AddAutoMerge(first); err != nil {
+		t.Fatalf("AddAutoMerge() error = %v", err)
+	}
+	if err := AddAutoMerge(second); err != nil {
+		t.Fatalf("AddAutoMerge() error = %v", err)
+	}
+
+	// A second request for the same pull request replaces the first
+	first.Method = "rebase"
+	if err := AddAutoMerge(first); err != nil {
+		t.Fatalf("AddAutoMerge() error = %v", err)
+	}
+	merges, err := LoadAutoMerges()
+	if err != nil {
+		t.Fatalf("LoadAutoMerges() error = %v", err)
+	}
+	if len(merges) != 2 || merges[0].ID != 2 || merges[1].Method != "rebase" {
+		t.Errorf("LoadAutoMerges() = %+v", merges)
+	}
+
+	for _, m := range []AutoMerge{first, second} {
+		if removed, err := RemoveAutoMerge(m); err != nil || !removed {
+			t.Errorf("RemoveAutoMerge(%d) = %v, %v", m.ID, removed, err)
+		}
+	}
+	if removed, _ := RemoveAutoMerge(first); removed {
+		t.Error("RemoveAutoMerge() removed a request twice")
+	}
+	if _, err := os.Stat(filepath.Join(dir, AutoMergeFileName)); !os.IsNotExist(err) {
+		t.Errorf("expected the file to be removed once empty, got %v", err)
+	}
+}
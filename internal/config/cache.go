@@ -0,0 +1,125 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CacheDirEnvVar names the environment variable that overrides where cached
+// API data is kept
+const CacheDirEnvVar = "BB_CACHE_DIR"
+
+// CacheDir returns the directory cached API data is kept in: BB_CACHE_DIR,
+// else $XDG_CACHE_HOME/bb, else ~/.cache/bb
+func CacheDir() (string, error) {
+	if dir := os.Getenv(CacheDirEnvVar); dir != "" {
+		return dir, nil
+	}
+
+	if xdg := os.Getenv("XDG_CACHE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "bb"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".cache", "bb"), nil
+}
+
+// cachePath returns the file an entry is stored in
+func cachePath(key string) (string, error) {
+	path, err := CacheFile(key)
+	if err != nil {
+		return "", err
+	}
+	return path + ".json", nil
+}
+
+// CacheFile returns the file in the cache directory kept under key, for
+// cached data that isn't a JSON entry. Each "/"-separated part of key
+// becomes a directory, escaped so keys can't leave the cache.
+func CacheFile(key string) (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	parts := strings.Split(key, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+		if parts[i] == "." || parts[i] == ".." || parts[i] == "" {
+			parts[i] = "_" + parts[i]
+		}
+	}
+	return filepath.Join(dir, filepath.Join(parts...)), nil
+}
+
+// ReadCache decodes the entry stored under key into v, and reports whether
+// there was one written less than ttl ago
+func ReadCache(key string, ttl time.Duration, v any) bool {
+	path, err := cachePath(key)
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) >= ttl {
+		return false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// WriteCache stores v under key
+func WriteCache(key string, v any) error {
+	path, err := cachePath(key)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("could not marshal cache entry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("could not create cache directory: %w", err)
+	}
+
+	// Write to a temporary file first, so a concurrent reader never sees
+	// a partial entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("could not write cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("could not write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("could not write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("could not write cache entry: %w", err)
+	}
+	return nil
+}
+
+// ClearCache removes every cached entry
+func ClearCache() error {
+	dir, err := CacheDir()
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("could not clear cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCache_ReadWrite(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(CacheDirEnvVar, dir)
+
+	var got []string
+	if ReadCache("bitbucket.org/alice/prs/team/api", time.Minute, &got) {
+		t.Fatal("expected no entry before writing one")
+	}
+
+	want := []string{"1\tFix parser", "2\tAdd docs"}
+	if err := WriteCache("bitbucket.org/alice/prs/team/api", want); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ReadCache("bitbucket.org/alice/prs/team/api", time.Minute, &got) {
+		t.Fatal("expected the entry just written")
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ReadCache() = %v, want %v", got, want)
+	}
+
+	// An entry older than the TTL is ignored
+	path := filepath.Join(dir, "bitbucket.org", "alice", "prs", "team", "api.json")
+	old := time.Now().Add(-2 * time.Minute)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if ReadCache("bitbucket.org/alice/prs/team/api", time.Minute, &got) {
+		t.Error("expected an expired entry to be ignored")
+	}
+
+	if err := ClearCache(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("expected the cache directory to be removed, got %v", err)
+	}
+}
+
+func TestCachePath_StaysInCacheDir(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(CacheDirEnvVar, dir)
+
+	for _, key := range []string{"../../etc/passwd", "a//b", "./x", `a\b`} {
+		path, err := cachePath(key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rel, err := filepath.Rel(dir, path); err != nil || strings.HasPrefix(rel, "..") {
+			t.Errorf("cachePath(%q) = %q, outside of %q", key, path, dir)
+		}
+	}
+}
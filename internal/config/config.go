@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -12,11 +13,22 @@ const (
 	// DefaultHost is the default Bitbucket host
 	DefaultHost = "bitbucket.org"
 
+	// DefaultAPIURL is the API base URL of DefaultHost
+	DefaultAPIURL = "https://api.bitbucket.org/2.0"
+
+	// HostEnvVar names the environment variable that selects the host,
+	// overriding the active profile's host. --hostname sets it too.
+	HostEnvVar = "BB_HOST"
+
 	// ConfigFileName is the name of the config file
 	ConfigFileName = "config.yml"
 
 	// HostsFileName is the name of the hosts file
 	HostsFileName = "hosts.yml"
+
+	// FieldsKeyPrefix prefixes the config keys that set a command's default
+	// table columns, e.g. "fields.pr.list"
+	FieldsKeyPrefix = "fields."
 )
 
 // Config represents the main configuration
@@ -28,13 +40,72 @@ type Config struct {
 	Browser          string `yaml:"browser,omitempty"`
 	HTTPTimeout      int    `yaml:"http_timeout,omitempty"`
 	DefaultWorkspace string `yaml:"default_workspace,omitempty"`
+	Theme            string `yaml:"theme,omitempty"`
+	Icons            string `yaml:"icons,omitempty"`
+	Timestamps       string `yaml:"timestamps,omitempty"`
+	CredentialStore  string `yaml:"credential_store,omitempty"`
+	// Locale selects the language of messages, e.g. "de" or "pt-BR",
+	// instead of the one LANG selects
+	Locale string `yaml:"locale,omitempty"`
+	// Accessible suits output to screen readers: no spinners or redrawn
+	// screens, and words rather than colors, glyphs and columns
+	Accessible bool `yaml:"accessible,omitempty"`
+	// LogLevel turns on the log file, recording entries of this level,
+	// debug, info, warn or error, and above
+	LogLevel string `yaml:"log_level,omitempty"`
+	// PRTemplate and IssueTemplate are the path of a file, or the text
+	// itself, used to start new pull request and issue descriptions
+	PRTemplate    string `yaml:"pr_template,omitempty"`
+	IssueTemplate string `yaml:"issue_template,omitempty"`
+	// HTTPProxy, HTTPSProxy and NoProxy take the place of the HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY environment variables for bb's requests
+	HTTPProxy  string `yaml:"http_proxy,omitempty"`
+	HTTPSProxy string `yaml:"https_proxy,omitempty"`
+	NoProxy    string `yaml:"no_proxy,omitempty"`
+	// HTTPIdleConns and HTTPIdleTimeout, in seconds, tune how many idle
+	// connections per host are kept open and for how long; HTTP2 is
+	// "enabled" or "disabled"
+	HTTPIdleConns   int    `yaml:"http_idle_conns,omitempty"`
+	HTTPIdleTimeout int    `yaml:"http_idle_timeout,omitempty"`
+	HTTP2           string `yaml:"http2,omitempty"`
+	// UpdateCheck is "disabled" to stop bb version looking for a newer
+	// release
+	UpdateCheck string `yaml:"update_check,omitempty"`
+	// Fields maps a command, e.g. "pr.list", to its default table columns
+	Fields map[string]string `yaml:"fields,omitempty"`
+	// Filters maps a name to a saved query in the Bitbucket query language,
+	// used by the --filter flag of issue and pull request lists
+	Filters map[string]string `yaml:"filters,omitempty"`
+	// PinnedRepos maps an absolute directory to the repository, in
+	// WORKSPACE/REPO format, that commands run in or below it operate on
+	PinnedRepos map[string]string `yaml:"pinned_repos,omitempty"`
+	// CurrentProfile is the profile used when BB_PROFILE is not set
+	CurrentProfile string              `yaml:"current_profile,omitempty"`
+	Profiles       map[string]*Profile `yaml:"profiles,omitempty"`
 }
 
+// Authentication methods recorded per host
+const (
+	AuthMethodToken    = "token"
+	AuthMethodAPIToken = "api_token"
+	AuthMethodOAuth    = "oauth"
+)
+
 // HostConfig represents per-host configuration
 type HostConfig struct {
 	Users       map[string]*UserConfig `yaml:"users,omitempty"`
 	User        string                 `yaml:"user,omitempty"`
 	GitProtocol string                 `yaml:"git_protocol,omitempty"`
+	// APIURL is the REST API base URL, needed for hosts other than
+	// bitbucket.org such as Bitbucket Data Center
+	APIURL string `yaml:"api_url,omitempty"`
+	// AuthMethod records how the active user logged in
+	AuthMethod string `yaml:"auth_method,omitempty"`
+	// CABundle is a PEM file of CA certificates trusted for the host, in
+	// addition to the system ones, for servers with a private CA
+	CABundle string `yaml:"ca_bundle,omitempty"`
+	// InsecureSkipTLS disables TLS certificate verification for the host
+	InsecureSkipTLS bool `yaml:"insecure_skip_tls,omitempty"`
 }
 
 // UserConfig represents per-user configuration
@@ -203,6 +274,28 @@ func (h HostsConfig) SetActiveUser(host, user string) {
 	}
 }
 
+// APIURL returns the REST API base URL for a host. Hosts without a
+// configured api_url are assumed to be Bitbucket Data Center servers, which
+// serve their API under /rest/api/1.0.
+func (h HostsConfig) APIURL(host string) string {
+	if hostConfig, ok := h[host]; ok && hostConfig.APIURL != "" {
+		return strings.TrimSuffix(hostConfig.APIURL, "/")
+	}
+	if host == DefaultHost {
+		return DefaultAPIURL
+	}
+	return "https://" + host + "/rest/api/1.0"
+}
+
+// GetAuthMethod returns how the active user of a host logged in, or an
+// empty string if it was not recorded
+func (h HostsConfig) GetAuthMethod(host string) string {
+	if hostConfig, ok := h[host]; ok {
+		return hostConfig.AuthMethod
+	}
+	return ""
+}
+
 // GetGitProtocol returns the git protocol for a host
 func (h HostsConfig) GetGitProtocol(host string) string {
 	if hostConfig, ok := h[host]; ok && hostConfig.GitProtocol != "" {
@@ -230,21 +323,38 @@ func (h HostsConfig) AuthenticatedHosts() []string {
 	return hosts
 }
 
-// GetDefaultWorkspace returns the default workspace from config
+// GetDefaultWorkspace returns the effective default workspace, taking
+// BB_WORKSPACE, .bb.yml and the active profile into account
 func GetDefaultWorkspace() (string, error) {
 	config, err := LoadConfig()
 	if err != nil {
 		return "", err
 	}
-	return config.DefaultWorkspace, nil
+	if _, err := config.ActiveProfile(); err != nil {
+		return "", err
+	}
+	repo, err := LoadCurrentRepoConfig()
+	if err != nil {
+		return "", err
+	}
+	return NewResolver(config, repo).Get("default_workspace").Value, nil
 }
 
-// SetDefaultWorkspace sets the default workspace in config
+// SetDefaultWorkspace sets the default workspace of the active profile, or
+// in config if no profile is in use
 func SetDefaultWorkspace(workspace string) error {
 	config, err := LoadConfig()
 	if err != nil {
 		return err
 	}
-	config.DefaultWorkspace = workspace
+	profile, err := config.ActiveProfile()
+	if err != nil {
+		return err
+	}
+	if profile != nil {
+		profile.Workspace = workspace
+	} else {
+		config.DefaultWorkspace = workspace
+	}
 	return SaveConfig(config)
 }
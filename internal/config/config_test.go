@@ -223,6 +223,27 @@ func TestHostsConfig_GetGitProtocol_CustomProtocol(t *testing.T) {
 	}
 }
 
+func TestHostsConfig_APIURL(t *testing.T) {
+	hosts := make(HostsConfig)
+	hosts["bitbucket.example.com"] = &HostConfig{}
+	hosts["git.example.com"] = &HostConfig{APIURL: "https://git.example.com/api/"}
+
+	tests := []struct {
+		host string
+		want string
+	}{
+		{DefaultHost, DefaultAPIURL},
+		{"bitbucket.example.com", "https://bitbucket.example.com/rest/api/1.0"},
+		{"git.example.com", "https://git.example.com/api"},
+	}
+
+	for _, tt := range tests {
+		if got := hosts.APIURL(tt.host); got != tt.want {
+			t.Errorf("APIURL(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
 func TestHostsConfig_AuthenticatedHosts_Empty(t *testing.T) {
 	hosts := make(HostsConfig)
 
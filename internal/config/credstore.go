@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// Credential store names accepted by the credential_store config key
+const (
+	CredentialStoreKeyring   = "keyring"
+	CredentialStoreFile      = "file"
+	CredentialStorePass      = "pass"
+	CredentialStorePlaintext = "plaintext"
+)
+
+// ErrCredentialNotFound is returned by a CredentialStore that holds no
+// secret for the requested key.
+var ErrCredentialNotFound = errors.New("credential not found")
+
+// CredentialStore keeps secrets such as tokens, keyed by host and user.
+type CredentialStore interface {
+	// Name returns the store's credential_store name, e.g. "keyring"
+	Name() string
+	Get(key string) (string, error)
+	Set(key, secret string) error
+	Delete(key string) error
+}
+
+// CredentialStoreNames returns the names accepted by credential_store.
+func CredentialStoreNames() []string {
+	return []string{CredentialStoreKeyring, CredentialStoreFile, CredentialStorePass, CredentialStorePlaintext}
+}
+
+// IsValidCredentialStore reports whether name is a known credential store.
+func IsValidCredentialStore(name string) bool {
+	for _, n := range CredentialStoreNames() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// NewCredentialStore returns the store with the given name. An empty name
+// selects the OS keyring.
+func NewCredentialStore(name string) (CredentialStore, error) {
+	switch name {
+	case "", CredentialStoreKeyring:
+		return keyringStore{}, nil
+	case CredentialStoreFile:
+		return newEncryptedFileStore(), nil
+	case CredentialStorePass:
+		return passStore{}, nil
+	case CredentialStorePlaintext:
+		return plaintextStore{}, nil
+	}
+	return nil, fmt.Errorf("unknown credential_store %q (must be one of: %s)", name, strings.Join(CredentialStoreNames(), ", "))
+}
+
+// ActiveCredentialStore returns the store selected by BB_CREDENTIAL_STORE
+// or the credential_store config key.
+func ActiveCredentialStore() (CredentialStore, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return NewCredentialStore(NewResolver(cfg, nil).Get("credential_store").Value)
+}
+
+// keyringStore keeps secrets in the OS keyring: Keychain on macOS, the
+// Secret Service on Linux and Credential Manager on Windows.
+type keyringStore struct{}
+
+func (keyringStore) Name() string { return CredentialStoreKeyring }
+
+func (keyringStore) Get(key string) (string, error) {
+	secret, err := keyring.Get(ServiceName, key)
+	if err == keyring.ErrNotFound {
+		return "", ErrCredentialNotFound
+	}
+	return secret, err
+}
+
+func (keyringStore) Set(key, secret string) error {
+	err := keyring.Set(ServiceName, key, secret)
+	if keyringUnavailable(err) {
+		return fmt.Errorf("%w: %v", ErrKeyringUnavailable, err)
+	}
+	return err
+}
+
+func (keyringStore) Delete(key string) error {
+	err := keyring.Delete(ServiceName, key)
+	if err == keyring.ErrNotFound {
+		return nil
+	}
+	if keyringUnavailable(err) {
+		return fmt.Errorf("%w: %v", ErrKeyringUnavailable, err)
+	}
+	return err
+}
+
+// keyringUnavailable reports whether err means there is no OS keyring to
+// use, as on a headless server without a Secret Service, rather than that
+// the keyring refused the request, e.g. because access was denied. The
+// Secret Service reports its absence only through D-Bus error messages.
+func keyringUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, keyring.ErrUnsupportedPlatform) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{"dbus", "org.freedesktop.secrets", "secret service"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// FallbackCredentialsFileName holds tokens when no OS keyring is
+	// available and --insecure-storage was given
+	FallbackCredentialsFileName = "credentials.fallback.enc"
+
+	// FallbackKeyFileName holds the random key the fallback file is
+	// obfuscated with
+	FallbackKeyFileName = "credentials.key"
+
+	// CredentialSourceFallback names the fallback file as a token source
+	CredentialSourceFallback = "obfuscated file (no keyring available)"
+)
+
+// ErrKeyringUnavailable is returned when the OS keyring can't store a
+// token, e.g. on a headless server without a Secret Service.
+var ErrKeyringUnavailable = errors.New("no OS keyring is available")
+
+// fallbackStore keeps tokens in a file encrypted with a key stored next to
+// it. That is obfuscation rather than encryption: it keeps tokens out of
+// sight when the config directory is browsed or grepped, but anyone who can
+// read the user's files can read the tokens.
+func fallbackStore() *encryptedFileStore {
+	return &encryptedFileStore{
+		name:       CredentialSourceFallback,
+		file:       FallbackCredentialsFileName,
+		passphrase: fallbackKey,
+		deriveKey:  fallbackCipherKey,
+	}
+}
+
+// SetFallbackToken stores a token in the fallback file. It is used when the
+// keyring is unavailable and the user opted in with --insecure-storage.
+func SetFallbackToken(host, user, token string) error {
+	return fallbackStore().Set(keyringKey(host, user), token)
+}
+
+// fallbackKey returns the key of the fallback file, creating it if needed.
+func fallbackKey(bool) (string, error) {
+	data, err := readCredentialsFile(FallbackKeyFileName)
+	if err != nil {
+		return "", err
+	}
+	if data != nil {
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	encoded := hex.EncodeToString(key)
+	if err := writeCredentialsFile(FallbackKeyFileName, []byte(encoded+"\n")); err != nil {
+		return "", err
+	}
+	return encoded, nil
+}
+
+// fallbackCipherKey decodes the fallback key, which is random and so is
+// used as the AES-256 key as is, without stretching
+func fallbackCipherKey(key string, _ []byte) ([]byte, error) {
+	raw, err := hex.DecodeString(key)
+	if err != nil || len(raw) != 32 {
+		return nil, fmt.Errorf("%s is corrupt", FallbackKeyFileName)
+	}
+	return raw, nil
+}
+
+// hasFallbackFile reports whether any token was stored in the fallback file.
+func hasFallbackFile() bool {
+	dir, err := ConfigDir()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(dir, FallbackCredentialsFileName))
+	return err == nil
+}
+
+// getFallbackToken looks up a token in the fallback file when the keyring
+// has none or can't be reached.
+func getFallbackToken(key string) (string, error) {
+	if !hasFallbackFile() {
+		return "", ErrCredentialNotFound
+	}
+	token, err := fallbackStore().Get(key)
+	if err != nil && err != ErrCredentialNotFound {
+		return "", fmt.Errorf("could not read %s: %w", FallbackCredentialsFileName, err)
+	}
+	return token, err
+}
@@ -0,0 +1,314 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// EncryptedCredentialsFileName holds secrets for the "file" store
+	EncryptedCredentialsFileName = "credentials.enc"
+
+	// PlaintextCredentialsFileName holds secrets for the "plaintext" store
+	PlaintextCredentialsFileName = "credentials.yml"
+
+	// PassphraseEnvVar names the environment variable holding the passphrase
+	// of the encrypted credentials file
+	PassphraseEnvVar = "BB_CREDENTIAL_PASSPHRASE"
+
+	saltSize         = 16
+	pbkdf2Iterations = 600000
+)
+
+// PassphrasePrompt asks the user for the passphrase of the encrypted
+// credentials file when BB_CREDENTIAL_PASSPHRASE is not set. It is set by
+// the root command when prompts are possible.
+var PassphrasePrompt func(prompt string) (string, error)
+
+// cachedPassphrase holds the passphrase so that it is asked for once per run
+var cachedPassphrase string
+
+// encryptedFileStore keeps secrets in a file encrypted with AES-256-GCM
+// under a key derived from a passphrase.
+type encryptedFileStore struct {
+	name string
+	file string
+	// passphrase returns the passphrase the file is encrypted with, asking
+	// for it to be confirmed if create is set, as the file is new
+	passphrase func(create bool) (string, error)
+	// deriveKey turns the passphrase and a random salt into the AES-256
+	// key; nil stretches the passphrase with PBKDF2
+	deriveKey func(passphrase string, salt []byte) ([]byte, error)
+}
+
+func newEncryptedFileStore() *encryptedFileStore {
+	return &encryptedFileStore{
+		name:       CredentialStoreFile,
+		file:       EncryptedCredentialsFileName,
+		passphrase: getPassphrase,
+	}
+}
+
+func (s *encryptedFileStore) Name() string { return s.name }
+
+func (s *encryptedFileStore) Get(key string) (string, error) {
+	secrets, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	secret, ok := secrets[key]
+	if !ok {
+		return "", ErrCredentialNotFound
+	}
+	return secret, nil
+}
+
+func (s *encryptedFileStore) Set(key, secret string) error {
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+	secrets[key] = secret
+	return s.save(secrets)
+}
+
+func (s *encryptedFileStore) Delete(key string) error {
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[key]; !ok {
+		return nil
+	}
+	delete(secrets, key)
+	return s.save(secrets)
+}
+
+func getPassphrase(create bool) (string, error) {
+	if p := os.Getenv(PassphraseEnvVar); p != "" {
+		return p, nil
+	}
+	if cachedPassphrase != "" {
+		return cachedPassphrase, nil
+	}
+	if PassphrasePrompt == nil {
+		return "", fmt.Errorf("the credential file is encrypted; set %s to its passphrase", PassphraseEnvVar)
+	}
+	p, err := PassphrasePrompt("Passphrase for bb credentials")
+	if err != nil {
+		return "", err
+	}
+	if p == "" {
+		return "", fmt.Errorf("passphrase cannot be empty")
+	}
+	// A mistyped passphrase for a new file would lock its secrets away
+	if create {
+		confirm, err := PassphrasePrompt("Confirm the passphrase")
+		if err != nil {
+			return "", err
+		}
+		if confirm != p {
+			return "", fmt.Errorf("passphrases don't match")
+		}
+	}
+	cachedPassphrase = p
+	return p, nil
+}
+
+func (s *encryptedFileStore) load() (map[string]string, error) {
+	data, err := readCredentialsFile(s.file)
+	if err != nil || data == nil {
+		return make(map[string]string), err
+	}
+
+	p, err := s.passphrase(false)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := decryptSecrets(data, p, s.deriveKey)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make(map[string]string)
+	if err := yaml.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("could not parse credentials file: %w", err)
+	}
+	return secrets, nil
+}
+
+func (s *encryptedFileStore) save(secrets map[string]string) error {
+	plaintext, err := yaml.Marshal(secrets)
+	if err != nil {
+		return fmt.Errorf("could not marshal credentials: %w", err)
+	}
+	existing, err := readCredentialsFile(s.file)
+	if err != nil {
+		return err
+	}
+	p, err := s.passphrase(existing == nil)
+	if err != nil {
+		return err
+	}
+	data, err := encryptSecrets(plaintext, p, s.deriveKey)
+	if err != nil {
+		return err
+	}
+	return writeCredentialsFile(s.file, data)
+}
+
+// encryptSecrets encrypts plaintext as salt || nonce || ciphertext.
+func encryptSecrets(plaintext []byte, passphrase string, deriveKey func(string, []byte) ([]byte, error)) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := newCipher(passphrase, salt, deriveKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := append(salt, nonce...)
+	return gcm.Seal(out, nonce, plaintext, nil), nil
+}
+
+func decryptSecrets(data []byte, passphrase string, deriveKey func(string, []byte) ([]byte, error)) ([]byte, error) {
+	if len(data) < saltSize {
+		return nil, fmt.Errorf("credentials file is corrupt")
+	}
+	gcm, err := newCipher(passphrase, data[:saltSize], deriveKey)
+	if err != nil {
+		return nil, err
+	}
+	data = data[saltSize:]
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("credentials file is corrupt")
+	}
+
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt credentials file: wrong passphrase?")
+	}
+	return plaintext, nil
+}
+
+func newCipher(passphrase string, salt []byte, deriveKey func(string, []byte) ([]byte, error)) (cipher.AEAD, error) {
+	if deriveKey == nil {
+		deriveKey = passphraseKey
+	}
+	key, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// passphraseKey stretches a passphrase into an AES-256 key, so that
+// guessing it from a stolen file is slow
+func passphraseKey(passphrase string, salt []byte) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, passphrase, salt, pbkdf2Iterations, 32)
+}
+
+// plaintextStore keeps secrets unencrypted in a file readable only by the
+// user. It is meant for containers and CI where nothing else is available.
+type plaintextStore struct{}
+
+func (plaintextStore) Name() string { return CredentialStorePlaintext }
+
+func (plaintextStore) Get(key string) (string, error) {
+	secrets, err := loadPlaintextSecrets()
+	if err != nil {
+		return "", err
+	}
+	secret, ok := secrets[key]
+	if !ok {
+		return "", ErrCredentialNotFound
+	}
+	return secret, nil
+}
+
+func (plaintextStore) Set(key, secret string) error {
+	secrets, err := loadPlaintextSecrets()
+	if err != nil {
+		return err
+	}
+	secrets[key] = secret
+	return savePlaintextSecrets(secrets)
+}
+
+func (plaintextStore) Delete(key string) error {
+	secrets, err := loadPlaintextSecrets()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[key]; !ok {
+		return nil
+	}
+	delete(secrets, key)
+	return savePlaintextSecrets(secrets)
+}
+
+func loadPlaintextSecrets() (map[string]string, error) {
+	secrets := make(map[string]string)
+	data, err := readCredentialsFile(PlaintextCredentialsFileName)
+	if err != nil || data == nil {
+		return secrets, err
+	}
+	if err := yaml.Unmarshal(data, &secrets); err != nil {
+		return nil, fmt.Errorf("could not parse credentials file: %w", err)
+	}
+	return secrets, nil
+}
+
+func savePlaintextSecrets(secrets map[string]string) error {
+	data, err := yaml.Marshal(secrets)
+	if err != nil {
+		return fmt.Errorf("could not marshal credentials: %w", err)
+	}
+	return writeCredentialsFile(PlaintextCredentialsFileName, data)
+}
+
+// readCredentialsFile reads name from the config directory, returning nil
+// data if it does not exist.
+func readCredentialsFile(name string) ([]byte, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read credentials file: %w", err)
+	}
+	return data, nil
+}
+
+func writeCredentialsFile(name string, data []byte) error {
+	dir, err := EnsureConfigDir()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+		return fmt.Errorf("could not write credentials file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// passStore keeps secrets in pass, the standard unix password manager,
+// under bb/<host>/<user>.
+type passStore struct{}
+
+func (passStore) Name() string { return CredentialStorePass }
+
+func (passStore) Get(key string) (string, error) {
+	out, err := runPass(nil, "show", passEntry(key))
+	if err != nil {
+		if strings.Contains(err.Error(), "is not in the password store") {
+			return "", ErrCredentialNotFound
+		}
+		return "", err
+	}
+	return strings.TrimSuffix(out, "\n"), nil
+}
+
+func (passStore) Set(key, secret string) error {
+	_, err := runPass(strings.NewReader(secret+"\n"), "insert", "--multiline", "--force", passEntry(key))
+	return err
+}
+
+func (passStore) Delete(key string) error {
+	_, err := runPass(nil, "rm", "--force", passEntry(key))
+	if err != nil && strings.Contains(err.Error(), "is not in the password store") {
+		return nil
+	}
+	return err
+}
+
+// passEntry maps a host:user key to a pass entry name. The user follows
+// the last colon, as the host may have a port.
+func passEntry(key string) string {
+	if i := strings.LastIndex(key, ":"); i >= 0 {
+		key = key[:i] + "/" + key[i+1:]
+	}
+	return "bb/" + key
+}
+
+func runPass(stdin *strings.Reader, args ...string) (string, error) {
+	if _, err := exec.LookPath("pass"); err != nil {
+		return "", fmt.Errorf("credential_store is pass, but pass is not installed")
+	}
+
+	cmd := exec.Command("pass", args...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("pass %s failed: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,317 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestNewCredentialStore(t *testing.T) {
+	for _, name := range append(CredentialStoreNames(), "") {
+		store, err := NewCredentialStore(name)
+		if err != nil {
+			t.Errorf("NewCredentialStore(%q) returned error: %v", name, err)
+			continue
+		}
+		want := name
+		if want == "" {
+			want = CredentialStoreKeyring
+		}
+		if store.Name() != want {
+			t.Errorf("NewCredentialStore(%q).Name() = %q, want %q", name, store.Name(), want)
+		}
+	}
+
+	if _, err := NewCredentialStore("vault"); err == nil {
+		t.Error("NewCredentialStore() should fail for an unknown store")
+	}
+}
+
+func TestFileStores_RoundTrip(t *testing.T) {
+	for _, name := range []string{CredentialStoreFile, CredentialStorePlaintext} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("BB_CONFIG_DIR", t.TempDir())
+			t.Setenv(PassphraseEnvVar, "correct horse")
+
+			store, _ := NewCredentialStore(name)
+			if _, err := store.Get("bitbucket.org:alice"); err != ErrCredentialNotFound {
+				t.Fatalf("Get() on empty store = %v, want ErrCredentialNotFound", err)
+			}
+			if err := store.Set("bitbucket.org:alice", "secret-token"); err != nil {
+				t.Fatalf("Set() returned error: %v", err)
+			}
+			got, err := store.Get("bitbucket.org:alice")
+			if err != nil {
+				t.Fatalf("Get() returned error: %v", err)
+			}
+			if got != "secret-token" {
+				t.Errorf("Get() = %q, want %q", got, "secret-token")
+			}
+			if err := store.Delete("bitbucket.org:alice"); err != nil {
+				t.Fatalf("Delete() returned error: %v", err)
+			}
+			if _, err := store.Get("bitbucket.org:alice"); err != ErrCredentialNotFound {
+				t.Errorf("Get() after Delete() = %v, want ErrCredentialNotFound", err)
+			}
+		})
+	}
+}
+
+func TestEncryptedFileStore_Encrypts(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("BB_CONFIG_DIR", dir)
+	t.Setenv(PassphraseEnvVar, "correct horse")
+
+	store, _ := NewCredentialStore(CredentialStoreFile)
+	if err := store.Set("bitbucket.org:alice", "secret-token"); err != nil {
+		t.Fatalf("Set() returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, EncryptedCredentialsFileName))
+	if err != nil {
+		t.Fatalf("could not read credentials file: %v", err)
+	}
+	if strings.Contains(string(data), "secret-token") {
+		t.Error("credentials file contains the token in plain text")
+	}
+
+	t.Setenv(PassphraseEnvVar, "wrong")
+	if _, err := store.Get("bitbucket.org:alice"); err == nil {
+		t.Error("Get() with the wrong passphrase should fail")
+	}
+}
+
+func TestGetTokenFromEnvOrKeyring_UsesConfiguredStore(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv("BB_TOKEN", "")
+	t.Setenv("BITBUCKET_TOKEN", "")
+	t.Setenv("BB_CREDENTIAL_STORE", CredentialStorePlaintext)
+
+	if err := SetToken("bitbucket.org", "alice", "secret-token"); err != nil {
+		t.Fatalf("SetToken() returned error: %v", err)
+	}
+	token, source, err := GetTokenFromEnvOrKeyring("bitbucket.org", "alice")
+	if err != nil {
+		t.Fatalf("GetTokenFromEnvOrKeyring() returned error: %v", err)
+	}
+	if token != "secret-token" || source != CredentialStorePlaintext {
+		t.Errorf("GetTokenFromEnvOrKeyring() = %q, %q, want %q, %q", token, source, "secret-token", CredentialStorePlaintext)
+	}
+}
+
+func TestKeyringUnavailable_FallbackFile(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv("BB_TOKEN", "")
+	t.Setenv("BITBUCKET_TOKEN", "")
+	t.Setenv("BB_CREDENTIAL_STORE", "")
+	keyring.MockInitWithError(errors.New("The name org.freedesktop.secrets was not provided by any .service files"))
+	t.Cleanup(keyring.MockInit)
+
+	err := SetToken("bitbucket.org", "alice", "secret-token")
+	if !errors.Is(err, ErrKeyringUnavailable) {
+		t.Fatalf("SetToken() error = %v, want ErrKeyringUnavailable", err)
+	}
+
+	if err := SetFallbackToken("bitbucket.org", "alice", "secret-token"); err != nil {
+		t.Fatalf("SetFallbackToken() returned error: %v", err)
+	}
+	token, source, err := GetTokenFromEnvOrKeyring("bitbucket.org", "alice")
+	if err != nil {
+		t.Fatalf("GetTokenFromEnvOrKeyring() returned error: %v", err)
+	}
+	if token != "secret-token" || source != CredentialSourceFallback {
+		t.Errorf("GetTokenFromEnvOrKeyring() = %q, %q, want %q, %q", token, source, "secret-token", CredentialSourceFallback)
+	}
+
+	if err := DeleteToken("bitbucket.org", "alice"); err != nil {
+		t.Fatalf("DeleteToken() returned error: %v", err)
+	}
+	if HasToken("bitbucket.org", "alice") {
+		t.Error("HasToken() = true after DeleteToken()")
+	}
+}
+
+func TestKeyringRefused_NotUnavailable(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv("BB_CREDENTIAL_STORE", "")
+	keyring.MockInitWithError(errors.New("The name org.freedesktop.secrets was not provided by any .service files"))
+	if err := SetFallbackToken("bitbucket.org", "alice", "secret-token"); err != nil {
+		t.Fatalf("SetFallbackToken() returned error: %v", err)
+	}
+	keyring.MockInitWithError(errors.New("access denied"))
+	t.Cleanup(keyring.MockInit)
+
+	err := SetToken("bitbucket.org", "alice", "secret-token")
+	if err == nil || errors.Is(err, ErrKeyringUnavailable) {
+		t.Errorf("SetToken() error = %v, want an error other than ErrKeyringUnavailable", err)
+	}
+	if err := DeleteToken("bitbucket.org", "alice"); err == nil {
+		t.Error("DeleteToken() returned nil, want the keyring's error")
+	}
+}
+
+func TestKeyringUnavailable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{keyring.ErrUnsupportedPlatform, true},
+		{errors.New("The name org.freedesktop.secrets was not provided by any .service files"), true},
+		{errors.New("dbus: DBUS_SESSION_BUS_ADDRESS not set"), true},
+		{errors.New("failed to unlock correct collection '/org/freedesktop/secrets/aliases/default'"), false},
+		{errors.New("access denied"), false},
+		{keyring.ErrSetDataTooBig, false},
+	}
+	for _, tt := range tests {
+		if got := keyringUnavailable(tt.err); got != tt.want {
+			t.Errorf("keyringUnavailable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestFallbackStore_UsesRandomKey(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	if err := SetFallbackToken("bitbucket.org", "alice", "secret-token"); err != nil {
+		t.Fatalf("SetFallbackToken() returned error: %v", err)
+	}
+	key, err := fallbackKey(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := readCredentialsFile(FallbackCredentialsFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decryptSecrets(data, key, fallbackCipherKey); err != nil {
+		t.Errorf("decryptSecrets() with the raw key returned error: %v", err)
+	}
+	if strings.Contains(string(data), "secret-token") {
+		t.Error("fallback file holds the token in the clear")
+	}
+}
+
+func TestEncryptedFileStore_ConfirmsNewPassphrase(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv(PassphraseEnvVar, "")
+	t.Cleanup(func() {
+		PassphrasePrompt = nil
+		cachedPassphrase = ""
+	})
+
+	var asked []string
+	answer := func(answers ...string) {
+		cachedPassphrase = ""
+		asked = nil
+		PassphrasePrompt = func(prompt string) (string, error) {
+			asked = append(asked, prompt)
+			a := answers[0]
+			answers = answers[1:]
+			return a, nil
+		}
+	}
+	store, _ := NewCredentialStore(CredentialStoreFile)
+
+	answer("correct horse", "correct hrose")
+	if err := store.Set("bitbucket.org:alice", "secret-token"); err == nil || !strings.Contains(err.Error(), "don't match") {
+		t.Fatalf("Set() with a mistyped confirmation = %v, want a mismatch error", err)
+	}
+
+	answer("correct horse", "correct horse")
+	if err := store.Set("bitbucket.org:alice", "secret-token"); err != nil {
+		t.Fatalf("Set() returned error: %v", err)
+	}
+	if len(asked) != 2 {
+		t.Errorf("creating the store asked %q, want the passphrase and its confirmation", asked)
+	}
+
+	answer("correct horse")
+	if err := store.Set("bitbucket.org:bob", "other-token"); err != nil {
+		t.Fatalf("Set() on the existing store returned error: %v", err)
+	}
+	if len(asked) != 1 {
+		t.Errorf("using the existing store asked %q, want only the passphrase", asked)
+	}
+}
+
+func TestPassEntry(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"bitbucket.org:alice", "bb/bitbucket.org/alice"},
+		{"bitbucket.example.com:8443:alice", "bb/bitbucket.example.com:8443/alice"},
+		{"bitbucket.org", "bb/bitbucket.org"},
+	}
+	for _, tt := range tests {
+		if got := passEntry(tt.key); got != tt.want {
+			t.Errorf("passEntry(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+// fakePass installs a pass on PATH that keeps entries as files in a
+// directory, which it returns
+func fakePass(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not installed")
+	}
+	bin, store := t.TempDir(), t.TempDir()
+	script := `#!/bin/sh
+store='` + store + `'
+cmd=$1; shift
+case $cmd in
+show)
+	[ -f "$store/$1" ] || { echo "Error: $1 is not in the password store." >&2; exit 1; }
+	cat "$store/$1" ;;
+insert)
+	entry=$3
+	mkdir -p "$(dirname "$store/$entry")"
+	cat > "$store/$entry" ;;
+rm)
+	entry=$2
+	[ -f "$store/$entry" ] || { echo "Error: $entry is not in the password store." >&2; exit 1; }
+	rm "$store/$entry" ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(bin, "pass"), []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return store
+}
+
+func TestPassStore_RoundTrip(t *testing.T) {
+	dir := fakePass(t)
+	store, _ := NewCredentialStore(CredentialStorePass)
+	key := "bitbucket.example.com:8443:alice"
+
+	if _, err := store.Get(key); err != ErrCredentialNotFound {
+		t.Fatalf("Get() on empty store = %v, want ErrCredentialNotFound", err)
+	}
+	if err := store.Set(key, "secret-token"); err != nil {
+		t.Fatalf("Set() returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "bb", "bitbucket.example.com:8443", "alice")); err != nil {
+		t.Errorf("expected the entry bb/bitbucket.example.com:8443/alice: %v", err)
+	}
+	got, err := store.Get(key)
+	if err != nil || got != "secret-token" {
+		t.Fatalf("Get() = %q, %v, want %q", got, err, "secret-token")
+	}
+	if err := store.Delete(key); err != nil {
+		t.Fatalf("Delete() returned error: %v", err)
+	}
+	if err := store.Delete(key); err != nil {
+		t.Errorf("Delete() of a missing entry returned error: %v", err)
+	}
+	if _, err := store.Get(key); err != ErrCredentialNotFound {
+		t.Errorf("Get() after Delete() = %v, want ErrCredentialNotFound", err)
+	}
+}
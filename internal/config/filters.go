@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// filterNamePattern matches valid saved filter names
+var filterNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// ValidateFilterName checks that name can name a saved filter: letters,
+// digits, dashes and underscores, not starting with a dash or underscore.
+func ValidateFilterName(name string) error {
+	if !filterNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid filter name %q: use letters, digits, dashes and underscores", name)
+	}
+	return nil
+}
+
+// Filter returns the query saved as the named filter
+func (c *Config) Filter(name string) (string, bool) {
+	query, ok := c.Filters[name]
+	return query, ok
+}
+
+// FilterNames returns the names of the saved filters, sorted
+func (c *Config) FilterNames() []string {
+	names := make([]string, 0, len(c.Filters))
+	for name := range c.Filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SaveFilter saves query as the named filter, replacing any filter of that
+// name.
+func (c *Config) SaveFilter(name, query string) {
+	if c.Filters == nil {
+		c.Filters = make(map[string]string)
+	}
+	c.Filters[name] = query
+}
+
+// DeleteFilter removes the named filter and reports whether it existed.
+func (c *Config) DeleteFilter(name string) bool {
+	if _, ok := c.Filters[name]; !ok {
+		return false
+	}
+	delete(c.Filters, name)
+	if len(c.Filters) == 0 {
+		c.Filters = nil
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConfig_Filters(t *testing.T) {
+	cfg := &Config{}
+	cfg.SaveFilter("needs-review", `state="OPEN"`)
+	cfg.SaveFilter("my-bugs", `kind="bug"`)
+	cfg.SaveFilter("my-bugs", `kind="bug" AND assignee.username="me"`)
+
+	if got := cfg.FilterNames(); !reflect.DeepEqual(got, []string{"my-bugs", "needs-review"}) {
+		t.Errorf("FilterNames() = %v", got)
+	}
+	if query, ok := cfg.Filter("my-bugs"); !ok || query != `kind="bug" AND assignee.username="me"` {
+		t.Errorf("Filter() = %q, %v, want the replaced query", query, ok)
+	}
+
+	if cfg.DeleteFilter("missing") {
+		t.Error("DeleteFilter() of a missing filter should report false")
+	}
+	if !cfg.DeleteFilter("my-bugs") || !cfg.DeleteFilter("needs-review") {
+		t.Error("DeleteFilter() should report true for saved filters")
+	}
+	if cfg.Filters != nil {
+		t.Errorf("expected no filters left, got %v", cfg.Filters)
+	}
+}
+
+func TestValidateFilterName(t *testing.T) {
+	for _, name := range []string{"my-bugs", "needs_review", "q3"} {
+		if err := ValidateFilterName(name); err != nil {
+			t.Errorf("ValidateFilterName(%q) = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "-x", "has space", "a.b"} {
+		if err := ValidateFilterName(name); err == nil {
+			t.Errorf("ValidateFilterName(%q) should fail", name)
+		}
+	}
+}
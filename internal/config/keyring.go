@@ -1,15 +1,17 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
-
-	"github.com/zalando/go-keyring"
 )
 
 const (
 	// ServiceName is the name used for keyring storage
 	ServiceName = "bb:bitbucket-cli"
+
+	// CredentialSourceEnv names the environment as a token source
+	CredentialSourceEnv = "environment"
 )
 
 // KeyringToken represents a token stored in the system keyring
@@ -25,56 +27,87 @@ func keyringKey(host, user string) string {
 	return fmt.Sprintf("%s:%s", host, user)
 }
 
-// SetToken stores a token in the system keyring
+// SetToken stores a token in the configured credential store
 func SetToken(host, user, token string) error {
-	key := keyringKey(host, user)
-	return keyring.Set(ServiceName, key, token)
+	store, err := ActiveCredentialStore()
+	if err != nil {
+		return err
+	}
+	return store.Set(keyringKey(host, user), token)
 }
 
-// GetToken retrieves a token from the system keyring
+// GetToken retrieves a token from the configured credential store
 func GetToken(host, user string) (string, error) {
-	key := keyringKey(host, user)
-	token, err := keyring.Get(ServiceName, key)
-	if err != nil {
-		if err == keyring.ErrNotFound {
-			return "", fmt.Errorf("no token found for %s@%s", user, host)
-		}
-		return "", fmt.Errorf("could not retrieve token: %w", err)
-	}
-	return token, nil
+	token, _, err := getStoredToken(host, user)
+	return token, err
 }
 
-// DeleteToken removes a token from the system keyring
+// DeleteToken removes a token from the configured credential store, and
+// from the fallback file if it was stored there
 func DeleteToken(host, user string) error {
+	store, err := ActiveCredentialStore()
+	if err != nil {
+		return err
+	}
+
 	key := keyringKey(host, user)
-	err := keyring.Delete(ServiceName, key)
-	if err != nil && err != keyring.ErrNotFound {
+	err = store.Delete(key)
+	if hasFallbackFile() {
+		if err := fallbackStore().Delete(key); err != nil {
+			return fmt.Errorf("could not delete token: %w", err)
+		}
+		// Without a keyring, the token can only have been in the fallback
+		if errors.Is(err, ErrKeyringUnavailable) {
+			return nil
+		}
+	}
+	if err != nil {
 		return fmt.Errorf("could not delete token: %w", err)
 	}
 	return nil
 }
 
-// HasToken checks if a token exists in the keyring
+// HasToken checks if a token exists in the configured credential store
 func HasToken(host, user string) bool {
 	_, err := GetToken(host, user)
 	return err == nil
 }
 
 // GetTokenFromEnvOrKeyring tries to get a token from environment variable first,
-// then falls back to the keyring
+// then falls back to the configured credential store. The second result
+// names where the token came from.
 func GetTokenFromEnvOrKeyring(host, user string) (string, string, error) {
 	// Check environment variable first
 	if token := getEnvToken(); token != "" {
-		return token, "environment", nil
+		return token, CredentialSourceEnv, nil
 	}
 
-	// Fall back to keyring
-	token, err := GetToken(host, user)
+	return getStoredToken(host, user)
+}
+
+// getStoredToken looks up a token in the configured credential store. When
+// that is the keyring, tokens stored in the fallback file because the
+// keyring was unavailable are found too.
+func getStoredToken(host, user string) (token, source string, err error) {
+	store, err := ActiveCredentialStore()
 	if err != nil {
 		return "", "", err
 	}
 
-	return token, "keyring", nil
+	key := keyringKey(host, user)
+	token, err = store.Get(key)
+	if err != nil && store.Name() == CredentialStoreKeyring {
+		if fallback, fallbackErr := getFallbackToken(key); fallbackErr == nil {
+			return fallback, CredentialSourceFallback, nil
+		}
+	}
+	if err != nil {
+		if err == ErrCredentialNotFound {
+			return "", "", fmt.Errorf("no token found for %s@%s", user, host)
+		}
+		return "", "", fmt.Errorf("could not retrieve token: %w", err)
+	}
+	return token, store.Name(), nil
 }
 
 // getEnvToken checks for token in environment variables
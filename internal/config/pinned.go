@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+)
+
+// PinnedRepo returns the repository pinned to dir or to the nearest of its
+// parent directories, along with the directory it is pinned to.
+func (c *Config) PinnedRepo(dir string) (repo, pinnedDir string) {
+	dir = filepath.Clean(dir)
+	for {
+		if repo, ok := c.PinnedRepos[dir]; ok && repo != "" {
+			return repo, dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+// PinRepo pins repo, in WORKSPACE/REPO format, to dir.
+func (c *Config) PinRepo(dir, repo string) {
+	if c.PinnedRepos == nil {
+		c.PinnedRepos = make(map[string]string)
+	}
+	c.PinnedRepos[filepath.Clean(dir)] = repo
+}
+
+// UnpinRepo removes the repository pinned to dir and reports whether one
+// was pinned.
+func (c *Config) UnpinRepo(dir string) bool {
+	dir = filepath.Clean(dir)
+	if _, ok := c.PinnedRepos[dir]; !ok {
+		return false
+	}
+	delete(c.PinnedRepos, dir)
+	if len(c.PinnedRepos) == 0 {
+		c.PinnedRepos = nil
+	}
+	return true
+}
+
+// PinDirectory returns the directory a repository is pinned to by
+// 'bb repo set-default': the root of the current git repository, or the
+// current directory outside of one.
+func PinDirectory() (string, error) {
+	if root, err := git.GetRepoRoot(); err == nil && root != "" {
+		return canonicalDir(root)
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return canonicalDir(dir)
+}
+
+// CurrentPinnedRepo returns the repository pinned to the current directory
+// or one of its parents, and the directory it is pinned to.
+func CurrentPinnedRepo() (repo, pinnedDir string, err error) {
+	config, err := LoadConfig()
+	if err != nil {
+		return "", "", err
+	}
+	if len(config.PinnedRepos) == 0 {
+		return "", "", nil
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", "", err
+	}
+	dir, err = canonicalDir(dir)
+	if err != nil {
+		return "", "", err
+	}
+	repo, pinnedDir = config.PinnedRepo(dir)
+	return repo, pinnedDir, nil
+}
+
+// canonicalDir makes dir absolute and resolves symlinks, so that git's
+// idea of the repository root matches the working directory.
+func canonicalDir(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		return resolved, nil
+	}
+	return dir, nil
+}
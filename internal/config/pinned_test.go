@@ -0,0 +1,72 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestConfig_PinnedRepo_NearestParent(t *testing.T) {
+	root := filepath.FromSlash("/home/alice/src")
+	cfg := &Config{}
+	cfg.PinRepo(root, "myteam/api")
+	cfg.PinRepo(filepath.Join(root, "web"), "myteam/web")
+
+	tests := []struct {
+		dir      string
+		wantRepo string
+		wantDir  string
+	}{
+		{root, "myteam/api", root},
+		{filepath.Join(root, "docs", "guide"), "myteam/api", root},
+		{filepath.Join(root, "web"), "myteam/web", filepath.Join(root, "web")},
+		{filepath.Join(root, "web", "src"), "myteam/web", filepath.Join(root, "web")},
+		{filepath.FromSlash("/home/alice"), "", ""},
+	}
+
+	for _, tt := range tests {
+		repo, dir := cfg.PinnedRepo(tt.dir)
+		if repo != tt.wantRepo || dir != tt.wantDir {
+			t.Errorf("PinnedRepo(%q) = %q, %q, want %q, %q", tt.dir, repo, dir, tt.wantRepo, tt.wantDir)
+		}
+	}
+}
+
+func TestConfig_UnpinRepo(t *testing.T) {
+	dir := filepath.FromSlash("/home/alice/src")
+	cfg := &Config{}
+	cfg.PinRepo(dir, "myteam/api")
+
+	if cfg.UnpinRepo(filepath.Join(dir, "sub")) {
+		t.Error("UnpinRepo() should only remove an exact match")
+	}
+	if !cfg.UnpinRepo(dir) {
+		t.Error("UnpinRepo() = false, want true")
+	}
+	if cfg.PinnedRepos != nil {
+		t.Errorf("PinnedRepos = %v, want nil once empty", cfg.PinnedRepos)
+	}
+}
+
+func TestCurrentPinnedRepo(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	work := t.TempDir()
+	t.Chdir(work)
+
+	dir, err := canonicalDir(work)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{}
+	cfg.PinRepo(dir, "myteam/api")
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() returned error: %v", err)
+	}
+
+	repo, pinnedDir, err := CurrentPinnedRepo()
+	if err != nil {
+		t.Fatalf("CurrentPinnedRepo() returned error: %v", err)
+	}
+	if repo != "myteam/api" || pinnedDir != dir {
+		t.Errorf("CurrentPinnedRepo() = %q, %q, want %q, %q", repo, pinnedDir, "myteam/api", dir)
+	}
+}
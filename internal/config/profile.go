@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// ProfileEnvVar names the environment variable that selects a profile,
+// overriding the current profile saved in config.
+const ProfileEnvVar = "BB_PROFILE"
+
+// Profile bundles the account and defaults that are used together, such as
+// a "work" account on one workspace and a "personal" one on another.
+type Profile struct {
+	Host      string `yaml:"host,omitempty"`
+	User      string `yaml:"user,omitempty"`
+	Workspace string `yaml:"workspace,omitempty"`
+	Repo      string `yaml:"repo,omitempty"`
+}
+
+// ProfileNames returns the names of the configured profiles, sorted.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ActiveProfileName returns the name of the profile selected by BB_PROFILE,
+// or else the current profile saved in config. It is empty when no profile
+// is in use.
+func (c *Config) ActiveProfileName() string {
+	if name := os.Getenv(ProfileEnvVar); name != "" {
+		return name
+	}
+	return c.CurrentProfile
+}
+
+// ActiveProfile returns the profile in use, or nil if there is none. It
+// fails if the selected profile does not exist.
+func (c *Config) ActiveProfile() (*Profile, error) {
+	name := c.ActiveProfileName()
+	if name == "" {
+		return nil, nil
+	}
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q does not exist. Run 'bb context list' to see the available profiles", name)
+	}
+	return profile, nil
+}
+
+// LoadActiveProfile loads the config and returns the profile in use, or nil
+// if there is none.
+func LoadActiveProfile() (*Profile, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.ActiveProfile()
+}
+
+// selectedHost is the host chosen with --hostname for this run
+var selectedHost string
+
+// SelectHost makes host the one ActiveAccount returns for the rest of the
+// run, as --hostname asks, over BB_HOST and the active profile
+func SelectHost(host string) {
+	selectedHost = host
+}
+
+// HostOverride returns the host chosen with --hostname, else BB_HOST, or ""
+// if neither is set
+func HostOverride() string {
+	if selectedHost != "" {
+		return selectedHost
+	}
+	return os.Getenv(HostEnvVar)
+}
+
+// ActiveAccount returns the host and user to authenticate as. The host is
+// taken from --hostname or BB_HOST, then the active profile, falling back to
+// the default host. The user is the profile's user when the profile is for that host,
+// otherwise the host's active user.
+func ActiveAccount(hosts HostsConfig) (host, user string, err error) {
+	profile, err := LoadActiveProfile()
+	if err != nil {
+		return "", "", err
+	}
+
+	host = DefaultHost
+	if profile != nil && profile.Host != "" {
+		host = profile.Host
+	}
+	if override := HostOverride(); override != "" && override != host {
+		host = override
+		profile = nil
+	}
+	user = hosts.GetActiveUser(host)
+	if profile != nil && profile.User != "" {
+		user = profile.User
+	}
+	return host, user, nil
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestConfig_ActiveProfileName_EnvOverridesCurrent(t *testing.T) {
+	cfg := &Config{CurrentProfile: "work"}
+
+	t.Setenv(ProfileEnvVar, "")
+	if got := cfg.ActiveProfileName(); got != "work" {
+		t.Errorf("ActiveProfileName() = %q, want %q", got, "work")
+	}
+
+	t.Setenv(ProfileEnvVar, "personal")
+	if got := cfg.ActiveProfileName(); got != "personal" {
+		t.Errorf("ActiveProfileName() = %q, want %q (BB_PROFILE should take precedence)", got, "personal")
+	}
+}
+
+func TestConfig_ActiveProfile_None(t *testing.T) {
+	t.Setenv(ProfileEnvVar, "")
+	cfg := &Config{}
+
+	profile, err := cfg.ActiveProfile()
+	if err != nil {
+		t.Fatalf("ActiveProfile() returned error: %v", err)
+	}
+	if profile != nil {
+		t.Errorf("ActiveProfile() = %+v, want nil", profile)
+	}
+}
+
+func TestConfig_ActiveProfile_Unknown(t *testing.T) {
+	t.Setenv(ProfileEnvVar, "missing")
+	cfg := &Config{Profiles: map[string]*Profile{"work": {User: "alice"}}}
+
+	if _, err := cfg.ActiveProfile(); err == nil {
+		t.Error("ActiveProfile() should fail for an unknown profile")
+	}
+}
+
+func TestActiveAccount_UsesProfile(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv(ProfileEnvVar, "")
+	t.Setenv(HostEnvVar, "")
+
+	cfg := defaultConfig()
+	cfg.CurrentProfile = "work"
+	cfg.Profiles = map[string]*Profile{"work": {Host: DefaultHost, User: "alice-work"}}
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() returned error: %v", err)
+	}
+
+	hosts := make(HostsConfig)
+	hosts.SetActiveUser(DefaultHost, "alice")
+
+	host, user, err := ActiveAccount(hosts)
+	if err != nil {
+		t.Fatalf("ActiveAccount() returned error: %v", err)
+	}
+	if host != DefaultHost || user != "alice-work" {
+		t.Errorf("ActiveAccount() = %q, %q, want %q, %q", host, user, DefaultHost, "alice-work")
+	}
+}
+
+func TestActiveAccount_WithoutProfile(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv(ProfileEnvVar, "")
+	t.Setenv(HostEnvVar, "")
+
+	hosts := make(HostsConfig)
+	hosts.SetActiveUser(DefaultHost, "alice")
+
+	host, user, err := ActiveAccount(hosts)
+	if err != nil {
+		t.Fatalf("ActiveAccount() returned error: %v", err)
+	}
+	if host != DefaultHost || user != "alice" {
+		t.Errorf("ActiveAccount() = %q, %q, want %q, %q", host, user, DefaultHost, "alice")
+	}
+}
+
+func TestActiveAccount_HostOverridesProfile(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv(ProfileEnvVar, "")
+	t.Setenv(HostEnvVar, "bitbucket.example.com")
+
+	cfg := defaultConfig()
+	cfg.CurrentProfile = "work"
+	cfg.Profiles = map[string]*Profile{"work": {Host: DefaultHost, User: "alice-work"}}
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() returned error: %v", err)
+	}
+
+	hosts := make(HostsConfig)
+	hosts.SetActiveUser("bitbucket.example.com", "alice-dc")
+
+	host, user, err := ActiveAccount(hosts)
+	if err != nil {
+		t.Fatalf("ActiveAccount() returned error: %v", err)
+	}
+	if host != "bitbucket.example.com" || user != "alice-dc" {
+		t.Errorf("ActiveAccount() = %q, %q, want %q, %q", host, user, "bitbucket.example.com", "alice-dc")
+	}
+}
+
+func TestActiveAccount_SelectedHostOverridesEnv(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv(ProfileEnvVar, "")
+	t.Setenv(HostEnvVar, "bitbucket.example.com")
+	SelectHost("bitbucket.other.com")
+	t.Cleanup(func() { SelectHost("") })
+
+	hosts := make(HostsConfig)
+	hosts.SetActiveUser("bitbucket.example.com", "alice-dc")
+	hosts.SetActiveUser("bitbucket.other.com", "alice-other")
+
+	host, user, err := ActiveAccount(hosts)
+	if err != nil {
+		t.Fatalf("ActiveAccount() returned error: %v", err)
+	}
+	if host != "bitbucket.other.com" || user != "alice-other" {
+		t.Errorf("ActiveAccount() = %q, %q, want %q, %q", host, user, "bitbucket.other.com", "alice-other")
+	}
+}
+
+func TestGetDefaultWorkspace_ProfileOverridesConfig(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv(ProfileEnvVar, "work")
+
+	cfg := defaultConfig()
+	cfg.DefaultWorkspace = "alice"
+	cfg.Profiles = map[string]*Profile{"work": {Workspace: "acme"}}
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() returned error: %v", err)
+	}
+
+	ws, err := GetDefaultWorkspace()
+	if err != nil {
+		t.Fatalf("GetDefaultWorkspace() returned error: %v", err)
+	}
+	if ws != "acme" {
+		t.Errorf("GetDefaultWorkspace() = %q, want %q", ws, "acme")
+	}
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RepoConfigFileName is the name of the per-repository config file, read
+// from the root of the current git repository
+const RepoConfigFileName = ".bb.yml"
+
+// RepoConfig represents a repository's .bb.yml file. It is checked into the
+// repository so that everyone working on it shares the same defaults.
+type RepoConfig struct {
+	// Root is the repository root the file was read from
+	Root string `yaml:"-"`
+
+	// GitProtocol and DefaultWorkspace override the user config
+	GitProtocol      string `yaml:"git_protocol,omitempty"`
+	DefaultWorkspace string `yaml:"default_workspace,omitempty"`
+
+	// Reviewers are added to every new pull request
+	Reviewers []string           `yaml:"reviewers,omitempty"`
+	PR        RepoPRConfig       `yaml:"pr,omitempty"`
+	Pipelines RepoPipelineConfig `yaml:"pipelines,omitempty"`
+	Issues    RepoIssueConfig    `yaml:"issues,omitempty"`
+	Hooks     RepoHooksConfig    `yaml:"hooks,omitempty"`
+}
+
+// RepoPRConfig holds the pull request defaults of a repository
+type RepoPRConfig struct {
+	// DefaultBranch is the destination branch for new pull requests
+	DefaultBranch     string `yaml:"default_branch,omitempty"`
+	TitlePrefix       string `yaml:"title_prefix,omitempty"`
+	CloseSourceBranch bool   `yaml:"close_source_branch,omitempty"`
+	// Template is the path, relative to the repository root, of the file
+	// used as the starting point for pull request descriptions
+	Template string `yaml:"template,omitempty"`
+}
+
+// RepoPipelineConfig holds the pipeline settings of a repository
+type RepoPipelineConfig struct {
+	// Custom maps short names to custom pipelines in bitbucket-pipelines.yml,
+	// e.g. "deploy: deploy-to-production"
+	Custom map[string]string `yaml:"custom,omitempty"`
+}
+
+// RepoIssueConfig holds the issue defaults of a repository
+type RepoIssueConfig struct {
+	Kind     string `yaml:"kind,omitempty"`
+	Priority string `yaml:"priority,omitempty"`
+	// Template is the path, relative to the repository root, of the file
+	// used as the starting point for issue descriptions
+	Template string `yaml:"template,omitempty"`
+}
+
+// RepoHooksConfig holds the rules the git hooks added by "bb hooks install"
+// enforce
+type RepoHooksConfig struct {
+	// ProtectedBranches are the branches, by name or pattern such as
+	// release/*, that pushes to are refused
+	ProtectedBranches []string `yaml:"protected_branches,omitempty"`
+	// WarnFailedPipeline warns before pushing to a branch whose latest
+	// pipeline failed
+	WarnFailedPipeline bool                    `yaml:"warn_failed_pipeline,omitempty"`
+	CommitMessage      RepoCommitMessageConfig `yaml:"commit_message,omitempty"`
+}
+
+// RepoCommitMessageConfig holds the rules commit messages must follow
+type RepoCommitMessageConfig struct {
+	// JiraKey requires a Jira issue key, such as PROJ-123, in every commit
+	// message. JiraProjects, if set, are the projects the key must be of.
+	JiraKey      bool     `yaml:"jira_key,omitempty"`
+	JiraProjects []string `yaml:"jira_projects,omitempty"`
+	// Pattern is a regular expression commit messages must match
+	Pattern string `yaml:"pattern,omitempty"`
+}
+
+// LoadRepoConfig loads the .bb.yml file in the repository root. An empty
+// config is returned if the file doesn't exist.
+func LoadRepoConfig(root string) (*RepoConfig, error) {
+	cfg := &RepoConfig{Root: root}
+
+	data, err := os.ReadFile(filepath.Join(root, RepoConfigFileName))
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", RepoConfigFileName, err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", RepoConfigFileName, err)
+	}
+
+	return cfg, nil
+}
+
+// CustomPipeline returns the custom pipeline that name refers to, resolving
+// the short names defined under pipelines.custom.
+func (c *RepoConfig) CustomPipeline(name string) string {
+	if pipeline, ok := c.Pipelines.Custom[name]; ok && pipeline != "" {
+		return pipeline
+	}
+	return name
+}
+
+// PRTemplate returns the contents of the pull request template set in
+// .bb.yml or found in the .bitbucket directory, or an empty string if there
+// is none.
+func (c *RepoConfig) PRTemplate() (string, error) {
+	return c.template(c.PR.Template, "pull request", prTemplateNames)
+}
+
+// IssueTemplate returns the contents of the issue template set in .bb.yml
+// or found in the .bitbucket directory, or an empty string if there is none.
+func (c *RepoConfig) IssueTemplate() (string, error) {
+	return c.template(c.Issues.Template, "issue", issueTemplateNames)
+}
+
+// TemplateDir is the directory, relative to the repository root, searched
+// for templates that .bb.yml doesn't name
+const TemplateDir = ".bitbucket"
+
+var (
+	prTemplateNames    = []string{"PULL_REQUEST_TEMPLATE.md", "pull_request_template.md"}
+	issueTemplateNames = []string{"ISSUE_TEMPLATE.md", "issue_template.md"}
+)
+
+func (c *RepoConfig) template(path, kind string, names []string) (string, error) {
+	if c.Root == "" {
+		return "", nil
+	}
+	if path == "" {
+		for _, name := range names {
+			full, err := c.repoPath(filepath.Join(TemplateDir, name))
+			if err != nil {
+				continue
+			}
+			if data, err := os.ReadFile(full); err == nil {
+				return string(data), nil
+			}
+		}
+		return "", nil
+	}
+
+	full, err := c.repoPath(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read %s template: %w", kind, err)
+	}
+	data, err := os.ReadFile(full)
+	if err != nil {
+		return "", fmt.Errorf("could not read %s template: %w", kind, err)
+	}
+	return string(data), nil
+}
+
+// repoPath resolves path, relative to the repository root, to a file in
+// the repository. .bb.yml is checked in and so untrusted: a path leading
+// outside the repository, directly or through a symlink, is rejected rather
+// than read into a description that may be posted to Bitbucket.
+func (c *RepoConfig) repoPath(path string) (string, error) {
+	if filepath.IsAbs(path) {
+		return "", fmt.Errorf("%s is not a path relative to the repository root", path)
+	}
+	root, err := filepath.EvalSymlinks(c.Root)
+	if err != nil {
+		return "", err
+	}
+	full, err := filepath.EvalSymlinks(filepath.Join(root, path))
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, full)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the repository", path)
+	}
+	return full, nil
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadRepoConfig_Missing(t *testing.T) {
+	root := t.TempDir()
+
+	cfg, err := LoadRepoConfig(root)
+	if err != nil {
+		t.Fatalf("LoadRepoConfig() returned error: %v", err)
+	}
+	if cfg.Root != root {
+		t.Errorf("Root = %q, want %q", cfg.Root, root)
+	}
+	if cfg.PR.DefaultBranch != "" || len(cfg.Reviewers) != 0 {
+		t.Errorf("LoadRepoConfig() = %+v, want empty config", cfg)
+	}
+}
+
+func TestLoadRepoConfig(t *testing.T) {
+	root := t.TempDir()
+	content := `reviewers:
+  - alice
+  - bob
+pr:
+  default_branch: develop
+  title_prefix: "[PROJ]"
+  close_source_branch: true
+  template: .bitbucket/pr.md
+pipelines:
+  custom:
+    deploy: deploy-to-production
+issues:
+  kind: task
+  priority: minor
+hooks:
+  protected_branches: [main, release/*]
+  warn_failed_pipeline: true
+  commit_message:
+    jira_projects: [PROJ]
+`
+	if err := os.WriteFile(filepath.Join(root, RepoConfigFileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadRepoConfig(root)
+	if err != nil {
+		t.Fatalf("LoadRepoConfig() returned error: %v", err)
+	}
+
+	if len(cfg.Reviewers) != 2 || cfg.Reviewers[0] != "alice" {
+		t.Errorf("Reviewers = %v, want [alice bob]", cfg.Reviewers)
+	}
+	if cfg.PR.DefaultBranch != "develop" {
+		t.Errorf("PR.DefaultBranch = %q, want %q", cfg.PR.DefaultBranch, "develop")
+	}
+	if cfg.PR.TitlePrefix != "[PROJ]" {
+		t.Errorf("PR.TitlePrefix = %q, want %q", cfg.PR.TitlePrefix, "[PROJ]")
+	}
+	if !cfg.PR.CloseSourceBranch {
+		t.Error("PR.CloseSourceBranch = false, want true")
+	}
+	if cfg.Issues.Kind != "task" || cfg.Issues.Priority != "minor" {
+		t.Errorf("Issues = %+v, want kind task and priority minor", cfg.Issues)
+	}
+	if len(cfg.Hooks.ProtectedBranches) != 2 || cfg.Hooks.ProtectedBranches[1] != "release/*" {
+		t.Errorf("Hooks.ProtectedBranches = %v, want [main release/*]", cfg.Hooks.ProtectedBranches)
+	}
+	if !cfg.Hooks.WarnFailedPipeline || len(cfg.Hooks.CommitMessage.JiraProjects) != 1 {
+		t.Errorf("Hooks = %+v, want the pipeline warning and a Jira project", cfg.Hooks)
+	}
+}
+
+func TestLoadRepoConfig_Invalid(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, RepoConfigFileName), []byte("reviewers: [alice\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadRepoConfig(root); err == nil {
+		t.Error("LoadRepoConfig() should fail for invalid YAML")
+	}
+}
+
+func TestRepoConfig_CustomPipeline(t *testing.T) {
+	cfg := &RepoConfig{Pipelines: RepoPipelineConfig{Custom: map[string]string{"deploy": "deploy-to-production"}}}
+
+	if got := cfg.CustomPipeline("deploy"); got != "deploy-to-production" {
+		t.Errorf("CustomPipeline(deploy) = %q, want %q", got, "deploy-to-production")
+	}
+	if got := cfg.CustomPipeline("nightly"); got != "nightly" {
+		t.Errorf("CustomPipeline(nightly) = %q, want %q", got, "nightly")
+	}
+}
+
+func TestRepoConfig_PRTemplate(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "template.md"), []byte("## Summary\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &RepoConfig{Root: root, PR: RepoPRConfig{Template: "template.md"}}
+	got, err := cfg.PRTemplate()
+	if err != nil {
+		t.Fatalf("PRTemplate() returned error: %v", err)
+	}
+	if got != "## Summary\n" {
+		t.Errorf("PRTemplate() = %q, want %q", got, "## Summary\n")
+	}
+
+	empty := &RepoConfig{Root: root}
+	if got, err := empty.PRTemplate(); err != nil || got != "" {
+		t.Errorf("PRTemplate() without template = %q, %v, want empty", got, err)
+	}
+}
+
+func TestRepoConfig_TemplatesFromBitbucketDir(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, TemplateDir)
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pull_request_template.md"), []byte("## Changes\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ISSUE_TEMPLATE.md"), []byte("## Steps\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &RepoConfig{Root: root}
+	if got, err := cfg.PRTemplate(); err != nil || got != "## Changes\n" {
+		t.Errorf("PRTemplate() = %q, %v, want %q", got, err, "## Changes\n")
+	}
+	if got, err := cfg.IssueTemplate(); err != nil || got != "## Steps\n" {
+		t.Errorf("IssueTemplate() = %q, %v, want %q", got, err, "## Steps\n")
+	}
+}
+
+func TestRepoConfig_TemplateOutsideRepo(t *testing.T) {
+	outside := t.TempDir()
+	secret := filepath.Join(outside, "id_rsa")
+	if err := os.WriteFile(secret, []byte("PRIVATE KEY"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	root := filepath.Join(outside, "repo")
+	if err := os.MkdirAll(filepath.Join(root, TemplateDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(secret, filepath.Join(root, "link.md")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(secret, filepath.Join(root, TemplateDir, "pull_request_template.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{secret, "../id_rsa", "docs/../../id_rsa", "link.md"} {
+		cfg := &RepoConfig{Root: root, PR: RepoPRConfig{Template: path}}
+		got, err := cfg.PRTemplate()
+		if err == nil || strings.Contains(got, "PRIVATE KEY") {
+			t.Errorf("PRTemplate() with template %q = %q, %v, want an error", path, got, err)
+		}
+	}
+
+	cfg := &RepoConfig{Root: root}
+	if got, err := cfg.PRTemplate(); err != nil || got != "" {
+		t.Errorf("PRTemplate() from a symlink out of %s = %q, %v, want empty", TemplateDir, got, err)
+	}
+}
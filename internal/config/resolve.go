@@ -0,0 +1,271 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+)
+
+// Sources a resolved setting can come from, from lowest to highest
+// precedence. Flags take precedence over all of them and are applied by the
+// commands that define them.
+const (
+	SourceDefault = "default"
+	SourceConfig  = "config"
+	SourceProfile = "profile"
+	SourceRepo    = "repo"
+	SourceEnv     = "env"
+)
+
+// Setting is the effective value of a config key and where it came from.
+type Setting struct {
+	Key    string
+	Value  string
+	Source string
+	// Env is the environment variable that overrides the key, if any
+	Env string
+}
+
+// setting describes a key the resolver knows about and how to read and
+// write it on a Config.
+type setting struct {
+	key string
+	env string
+	get func(*Config) string
+	set func(*Config, string)
+}
+
+var settings = []setting{
+	{"git_protocol", "BB_GIT_PROTOCOL",
+		func(c *Config) string { return c.GitProtocol },
+		func(c *Config, v string) { c.GitProtocol = v }},
+	{"editor", "BB_EDITOR",
+		func(c *Config) string { return c.Editor },
+		func(c *Config, v string) { c.Editor = v }},
+	{"prompt", "BB_NO_PROMPT",
+		func(c *Config) string { return c.Prompt },
+		func(c *Config, v string) { c.Prompt = v }},
+	{"pager", "BB_PAGER",
+		func(c *Config) string { return c.Pager },
+		func(c *Config, v string) { c.Pager = v }},
+	{"browser", "BB_BROWSER",
+		func(c *Config) string { return c.Browser },
+		func(c *Config, v string) { c.Browser = v }},
+	{"http_timeout", "BB_HTTP_TIMEOUT",
+		func(c *Config) string {
+			if c.HTTPTimeout == 0 {
+				return ""
+			}
+			return strconv.Itoa(c.HTTPTimeout)
+		},
+		func(c *Config, v string) {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				c.HTTPTimeout = n
+			}
+		}},
+	{"default_workspace", "BB_WORKSPACE",
+		func(c *Config) string { return c.DefaultWorkspace },
+		func(c *Config, v string) { c.DefaultWorkspace = v }},
+	{"theme", "BB_THEME",
+		func(c *Config) string { return c.Theme },
+		func(c *Config, v string) { c.Theme = v }},
+	{"icons", "BB_ICONS",
+		func(c *Config) string { return c.Icons },
+		func(c *Config, v string) { c.Icons = v }},
+	{"timestamps", "BB_TIMESTAMPS",
+		func(c *Config) string { return c.Timestamps },
+		func(c *Config, v string) { c.Timestamps = v }},
+	{"locale", "BB_LOCALE",
+		func(c *Config) string { return c.Locale },
+		func(c *Config, v string) { c.Locale = v }},
+	{"accessible", "BB_ACCESSIBLE",
+		func(c *Config) string {
+			if !c.Accessible {
+				return ""
+			}
+			return "true"
+		},
+		func(c *Config, v string) { c.Accessible, _ = strconv.ParseBool(v) }},
+	{"credential_store", "BB_CREDENTIAL_STORE",
+		func(c *Config) string { return c.CredentialStore },
+		func(c *Config, v string) { c.CredentialStore = v }},
+	{"pr_template", "BB_PR_TEMPLATE",
+		func(c *Config) string { return c.PRTemplate },
+		func(c *Config, v string) { c.PRTemplate = v }},
+	{"issue_template", "BB_ISSUE_TEMPLATE",
+		func(c *Config) string { return c.IssueTemplate },
+		func(c *Config, v string) { c.IssueTemplate = v }},
+	{"http_proxy", "BB_HTTP_PROXY",
+		func(c *Config) string { return c.HTTPProxy },
+		func(c *Config, v string) { c.HTTPProxy = v }},
+	{"https_proxy", "BB_HTTPS_PROXY",
+		func(c *Config) string { return c.HTTPSProxy },
+		func(c *Config, v string) { c.HTTPSProxy = v }},
+	{"no_proxy", "BB_NO_PROXY",
+		func(c *Config) string { return c.NoProxy },
+		func(c *Config, v string) { c.NoProxy = v }},
+	{"http_idle_conns", "BB_HTTP_IDLE_CONNS",
+		func(c *Config) string { return positiveInt(c.HTTPIdleConns) },
+		func(c *Config, v string) { c.HTTPIdleConns = parsePositiveInt(v) }},
+	{"http_idle_timeout", "BB_HTTP_IDLE_TIMEOUT",
+		func(c *Config) string { return positiveInt(c.HTTPIdleTimeout) },
+		func(c *Config, v string) { c.HTTPIdleTimeout = parsePositiveInt(v) }},
+	{"http2", "BB_HTTP2",
+		func(c *Config) string { return c.HTTP2 },
+		func(c *Config, v string) { c.HTTP2 = v }},
+	{"update_check", "BB_UPDATE_CHECK",
+		func(c *Config) string { return c.UpdateCheck },
+		func(c *Config, v string) { c.UpdateCheck = v }},
+	{"log_level", "BB_LOG_LEVEL",
+		func(c *Config) string { return c.LogLevel },
+		func(c *Config, v string) { c.LogLevel = v }},
+}
+
+// positiveInt formats n for a setting, or returns "" if it is unset.
+func positiveInt(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// parsePositiveInt parses a setting that must be a positive number,
+// returning 0, meaning unset, for anything else.
+func parsePositiveInt(v string) int {
+	if n, err := strconv.Atoi(v); err == nil && n > 0 {
+		return n
+	}
+	return 0
+}
+
+// Resolver determines the effective value of each setting from, in order of
+// precedence, BB_* environment variables, the repository's .bb.yml, the
+// active profile, the user config file and the built-in defaults.
+type Resolver struct {
+	config  *Config
+	repo    *RepoConfig
+	profile *Profile
+}
+
+// NewResolver creates a Resolver over the user config cfg and the repository
+// config repo, which may be nil. An unknown active profile is ignored here;
+// commands that depend on it report the error themselves.
+func NewResolver(cfg *Config, repo *RepoConfig) *Resolver {
+	if repo == nil {
+		repo = &RepoConfig{}
+	}
+	profile, _ := cfg.ActiveProfile()
+	return &Resolver{config: cfg, repo: repo, profile: profile}
+}
+
+// Resolve loads the user config and the .bb.yml of the current git
+// repository, if any, and returns a Resolver over them.
+func Resolve() (*Resolver, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	repo, err := LoadCurrentRepoConfig()
+	if err != nil {
+		return nil, err
+	}
+	return NewResolver(cfg, repo), nil
+}
+
+// LoadCurrentRepoConfig loads the .bb.yml of the git repository containing
+// the working directory. An empty config is returned outside a repository.
+func LoadCurrentRepoConfig() (*RepoConfig, error) {
+	root, err := git.GetRepoRoot()
+	if err != nil {
+		return &RepoConfig{}, nil
+	}
+	return LoadRepoConfig(root)
+}
+
+// Get returns the effective value of key.
+func (r *Resolver) Get(key string) Setting {
+	for _, s := range settings {
+		if s.key == key {
+			return r.resolve(s)
+		}
+	}
+	return Setting{Key: key, Source: SourceDefault}
+}
+
+// All returns the effective value of every setting.
+func (r *Resolver) All() []Setting {
+	result := make([]Setting, len(settings))
+	for i, s := range settings {
+		result[i] = r.resolve(s)
+	}
+	return result
+}
+
+// Config returns a copy of the user config with every setting replaced by
+// its effective value.
+func (r *Resolver) Config() *Config {
+	cfg := *r.config
+	for _, s := range settings {
+		s.set(&cfg, r.resolve(s).Value)
+	}
+	return &cfg
+}
+
+func (r *Resolver) resolve(s setting) Setting {
+	result := Setting{Key: s.key, Env: s.env}
+
+	if v, ok := envValue(s); ok {
+		result.Value, result.Source = v, SourceEnv
+		return result
+	}
+	if v := r.repoValue(s.key); v != "" {
+		result.Value, result.Source = v, SourceRepo
+		return result
+	}
+	if r.profile != nil && s.key == "default_workspace" && r.profile.Workspace != "" {
+		result.Value, result.Source = r.profile.Workspace, SourceProfile
+		return result
+	}
+	// A value equal to the default is reported as the default, since
+	// LoadConfig fills in defaults when there is no config file
+	def := s.get(defaultConfig())
+	if v := s.get(r.config); v != "" && v != def {
+		result.Value, result.Source = v, SourceConfig
+		return result
+	}
+	result.Value, result.Source = def, SourceDefault
+	return result
+}
+
+// repoValue returns the value of the settings a .bb.yml may override. Keys
+// that name commands to run, such as editor and pager, are deliberately not
+// read from a file checked into a repository.
+func (r *Resolver) repoValue(key string) string {
+	switch key {
+	case "git_protocol":
+		return r.repo.GitProtocol
+	case "default_workspace":
+		return r.repo.DefaultWorkspace
+	}
+	return ""
+}
+
+// envValue returns the value of the environment variable overriding s.
+// BB_NO_PROMPT is a switch rather than a value: any value other than 0 or
+// false disables prompts.
+func envValue(s setting) (string, bool) {
+	v := os.Getenv(s.env)
+	if v == "" {
+		return "", false
+	}
+	if s.key == "prompt" {
+		switch strings.ToLower(v) {
+		case "0", "false", "no":
+			return "enabled", true
+		}
+		return "disabled", true
+	}
+	return v, true
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"testing"
+)
+
+func clearResolverEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv(ProfileEnvVar, "")
+	for _, s := range settings {
+		t.Setenv(s.env, "")
+	}
+}
+
+func TestResolver_Precedence(t *testing.T) {
+	clearResolverEnv(t)
+
+	cfg := defaultConfig()
+	cfg.DefaultWorkspace = "from-config"
+	cfg.CurrentProfile = "work"
+	cfg.Profiles = map[string]*Profile{"work": {Workspace: "from-profile"}}
+	repo := &RepoConfig{DefaultWorkspace: "from-repo"}
+
+	tests := []struct {
+		name       string
+		env        string
+		repo       *RepoConfig
+		profile    string
+		wantValue  string
+		wantSource string
+	}{
+		{"environment", "from-env", repo, "work", "from-env", SourceEnv},
+		{"repo config", "", repo, "work", "from-repo", SourceRepo},
+		{"profile", "", nil, "work", "from-profile", SourceProfile},
+		{"user config", "", nil, "", "from-config", SourceConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BB_WORKSPACE", tt.env)
+			c := *cfg
+			c.CurrentProfile = tt.profile
+
+			got := NewResolver(&c, tt.repo).Get("default_workspace")
+			if got.Value != tt.wantValue || got.Source != tt.wantSource {
+				t.Errorf("Get() = %q from %s, want %q from %s", got.Value, got.Source, tt.wantValue, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestResolver_Default(t *testing.T) {
+	clearResolverEnv(t)
+
+	got := NewResolver(defaultConfig(), nil).Get("git_protocol")
+	if got.Value != "ssh" || got.Source != SourceDefault {
+		t.Errorf("Get() = %q from %s, want %q from %s", got.Value, got.Source, "ssh", SourceDefault)
+	}
+}
+
+func TestResolver_RepoCannotSetCommands(t *testing.T) {
+	clearResolverEnv(t)
+
+	cfg := defaultConfig()
+	cfg.Editor = "vim"
+	r := NewResolver(cfg, &RepoConfig{GitProtocol: "https"})
+
+	if got := r.Get("editor"); got.Value != "vim" || got.Source != SourceConfig {
+		t.Errorf("Get(editor) = %q from %s, want %q from %s", got.Value, got.Source, "vim", SourceConfig)
+	}
+	if got := r.Get("git_protocol"); got.Value != "https" || got.Source != SourceRepo {
+		t.Errorf("Get(git_protocol) = %q from %s, want %q from %s", got.Value, got.Source, "https", SourceRepo)
+	}
+}
+
+func TestResolver_NoPrompt(t *testing.T) {
+	tests := []struct {
+		env  string
+		want string
+	}{
+		{"", "enabled"},
+		{"1", "disabled"},
+		{"true", "disabled"},
+		{"0", "enabled"},
+		{"false", "enabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			clearResolverEnv(t)
+			t.Setenv("BB_NO_PROMPT", tt.env)
+
+			if got := NewResolver(defaultConfig(), nil).Get("prompt").Value; got != tt.want {
+				t.Errorf("Get(prompt) = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolver_Config(t *testing.T) {
+	clearResolverEnv(t)
+	t.Setenv("BB_PAGER", "more")
+	t.Setenv("BB_HTTP_TIMEOUT", "90")
+	t.Setenv("BB_HTTP_IDLE_CONNS", "32")
+	t.Setenv("BB_HTTP_IDLE_TIMEOUT", "not a number")
+
+	cfg := defaultConfig()
+	cfg.HTTPIdleTimeout = 120
+	cfg.Pager = "less"
+	resolved := NewResolver(cfg, nil).Config()
+
+	if resolved.Pager != "more" {
+		t.Errorf("Config().Pager = %q, want %q", resolved.Pager, "more")
+	}
+	if resolved.HTTPTimeout != 90 {
+		t.Errorf("Config().HTTPTimeout = %d, want %d", resolved.HTTPTimeout, 90)
+	}
+	if resolved.HTTPIdleConns != 32 {
+		t.Errorf("Config().HTTPIdleConns = %d, want %d", resolved.HTTPIdleConns, 32)
+	}
+	// An invalid value leaves the setting unset, so the default applies
+	if resolved.HTTPIdleTimeout != 0 {
+		t.Errorf("Config().HTTPIdleTimeout = %d, want 0", resolved.HTTPIdleTimeout)
+	}
+	if cfg.Pager != "less" {
+		t.Errorf("Config() modified the user config: Pager = %q", cfg.Pager)
+	}
+}
+
+func TestResolver_IconsOverride(t *testing.T) {
+	clearResolverEnv(t)
+	cfg := defaultConfig()
+	cfg.Icons = "emoji"
+
+	if got := NewResolver(cfg, nil).Config().Icons; got != "emoji" {
+		t.Errorf("Icons = %q, want the config file's %q", got, "emoji")
+	}
+	t.Setenv("BB_ICONS", "none")
+	if got := NewResolver(cfg, nil).Config().Icons; got != "none" {
+		t.Errorf("Icons with BB_ICONS=none = %q, want %q", got, "none")
+	}
+}
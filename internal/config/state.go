@@ -0,0 +1,22 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// StateDir returns the directory bb keeps state that isn't configuration or
+// cache in, such as its log: $XDG_STATE_HOME/bb, else ~/.local/state/bb
+func StateDir() (string, error) {
+	if xdg := os.Getenv("XDG_STATE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "bb"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".local", "state", "bb"), nil
+}
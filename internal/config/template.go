@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PRTemplate returns the template new pull request descriptions start from:
+// BB_PR_TEMPLATE, then the repository's template, then pr_template from the
+// user config. It returns an empty string if none is set.
+func (r *Resolver) PRTemplate() (string, error) {
+	return r.template("pr_template", r.repo.PRTemplate)
+}
+
+// IssueTemplate returns the template new issue descriptions start from:
+// BB_ISSUE_TEMPLATE, then the repository's template, then issue_template
+// from the user config. It returns an empty string if none is set.
+func (r *Resolver) IssueTemplate() (string, error) {
+	return r.template("issue_template", r.repo.IssueTemplate)
+}
+
+func (r *Resolver) template(key string, repoTemplate func() (string, error)) (string, error) {
+	setting := r.Get(key)
+	if setting.Source != SourceEnv {
+		if t, err := repoTemplate(); err != nil || t != "" {
+			return t, err
+		}
+	}
+	return ReadTemplate(setting.Value)
+}
+
+// ReadTemplate returns the template a pr_template or issue_template value
+// refers to. A value naming an existing file, optionally starting with ~/,
+// is read from that file; any other value is the template itself.
+func ReadTemplate(value string) (string, error) {
+	if value == "" || strings.Contains(value, "\n") {
+		return value, nil
+	}
+
+	path := value
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, path[2:])
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return value, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read template %s: %w", path, err)
+	}
+	return string(data), nil
+}
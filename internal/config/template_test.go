@@ -0,0 +1,59 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadTemplate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.WriteFile(filepath.Join(home, "pr.md"), []byte("## From file\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"~/pr.md", "## From file\n"},
+		{filepath.Join(home, "pr.md"), "## From file\n"},
+		{"## Summary\n\n## Testing\n", "## Summary\n\n## Testing\n"},
+		{"Fixes #", "Fixes #"},
+	}
+
+	for _, tt := range tests {
+		got, err := ReadTemplate(tt.value)
+		if err != nil {
+			t.Errorf("ReadTemplate(%q) returned error: %v", tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ReadTemplate(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestResolver_PRTemplate_Precedence(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "template.md"), []byte("from repo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repo := &RepoConfig{Root: root, PR: RepoPRConfig{Template: "template.md"}}
+	cfg := &Config{PRTemplate: "from config"}
+
+	t.Setenv("BB_PR_TEMPLATE", "")
+	if got, _ := NewResolver(cfg, repo).PRTemplate(); got != "from repo" {
+		t.Errorf("PRTemplate() = %q, want the repository template", got)
+	}
+	if got, _ := NewResolver(cfg, &RepoConfig{}).PRTemplate(); got != "from config" {
+		t.Errorf("PRTemplate() = %q, want the config template", got)
+	}
+
+	t.Setenv("BB_PR_TEMPLATE", "from env")
+	if got, _ := NewResolver(cfg, repo).PRTemplate(); got != "from env" {
+		t.Errorf("PRTemplate() = %q, want BB_PR_TEMPLATE to take precedence", got)
+	}
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TransfersFileName is the name of the file recording the repository
+// transfers started with bb and not yet seen to complete
+const TransfersFileName = "transfers.yml"
+
+// Transfer records that a repository was offered to another workspace
+type Transfer struct {
+	Workspace   string    `yaml:"workspace"`
+	Repo        string    `yaml:"repo"`
+	To          string    `yaml:"to"`
+	RequestedAt time.Time `yaml:"requested_at"`
+}
+
+// Same reports whether t and other are for the same repository
+func (t Transfer) Same(other Transfer) bool {
+	return t.Workspace == other.Workspace && t.Repo == other.Repo
+}
+
+// LoadTransfers loads the pending repository transfers
+func LoadTransfers() ([]Transfer, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, TransfersFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read transfers file: %w", err)
+	}
+
+	var transfers []Transfer
+	if err := yaml.Unmarshal(data, &transfers); err != nil {
+		return nil, fmt.Errorf("could not parse transfers file: %w", err)
+	}
+	return transfers, nil
+}
+
+// SaveTransfers saves the pending repository transfers, removing the file
+// when there are none
+func SaveTransfers(transfers []Transfer) error {
+	if len(transfers) == 0 {
+		dir, err := ConfigDir()
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(filepath.Join(dir, TransfersFileName)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("could not remove transfers file: %w", err)
+		}
+		return nil
+	}
+
+	dir, err := EnsureConfigDir()
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(transfers)
+	if err != nil {
+		return fmt.Errorf("could not marshal transfers: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, TransfersFileName), data, 0600); err != nil {
+		return fmt.Errorf("could not write transfers file: %w", err)
+	}
+	return nil
+}
+
+// AddTransfer records t, replacing any earlier transfer of the same
+// repository
+func AddTransfer(t Transfer) error {
+	transfers, err := LoadTransfers()
+	if err != nil {
+		return err
+	}
+	transfers = removeTransfer(transfers, t)
+	return SaveTransfers(append(transfers, t))
+}
+
+// RemoveTransfer forgets the transfer of the repository of t, and reports
+// whether there was one
+func RemoveTransfer(t Transfer) (bool, error) {
+	transfers, err := LoadTransfers()
+	if err != nil {
+		return false, err
+	}
+	remaining := removeTransfer(transfers, t)
+	if len(remaining) == len(transfers) {
+		return false, nil
+	}
+	return true, SaveTransfers(remaining)
+}
+
+func removeTransfer(transfers []Transfer, t Transfer) []Transfer {
+	var result []Transfer
+	for _, existing := range transfers {
+		if !existing.Same(t) {
+			result = append(result, existing)
+		}
+	}
+	return result
+}
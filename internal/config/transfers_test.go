@@ -0,0 +1,45 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTransfers(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("BB_CONFIG_DIR", dir)
+
+	first := Transfer{Workspace: "team", Repo: "api", To: "acme"}
+	second := Transfer{Workspace: "team", Repo: "web", To: "acme"}
+	for _, tr := range []Transfer{first, second} {
+		if err := AddTransfer(tr); err != nil {
+			t.Fatalf("AddTransfer() error = %v", err)
+		}
+	}
+
+	// Offering the repository again replaces the earlier transfer
+	first.To = "other"
+	if err := AddTransfer(first); err != nil {
+		t.Fatalf("AddTransfer() error = %v", err)
+	}
+	transfers, err := LoadTransfers()
+	if err != nil {
+		t.Fatalf("LoadTransfers() error = %v", err)
+	}
+	if len(transfers) != 2 || transfers[0].Repo != "web" || transfers[1].To != "other" {
+		t.Errorf("LoadTransfers() = %+v", transfers)
+	}
+
+	for _, tr := range []Transfer{first, second} {
+		if removed, err := RemoveTransfer(tr); err != nil || !removed {
+			t.Errorf("RemoveTransfer(%s) = %v, %v", tr.Repo, removed, err)
+		}
+	}
+	if removed, _ := RemoveTransfer(first); removed {
+		t.Error("RemoveTransfer() removed a transfer twice")
+	}
+	if _, err := os.Stat(filepath.Join(dir, TransfersFileName)); !os.IsNotExist(err) {
+		t.Errorf("expected the file to be removed once empty, got %v", err)
+	}
+}
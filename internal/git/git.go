@@ -2,9 +2,17 @@ package git
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	neturl "net/url"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -13,16 +21,41 @@ type Remote struct {
 	Name      string
 	FetchURL  string
 	PushURL   string
+	Host      string
 	Workspace string
 	RepoSlug  string
 }
 
 // BitbucketRemote extracts workspace and repo from a Bitbucket remote URL
 type BitbucketRemote struct {
+	// Host is bitbucket.org or the Bitbucket Server host
+	Host string
+	// Workspace is the workspace, or on Bitbucket Server the project key,
+	// which commands use in its place
 	Workspace string
-	RepoSlug  string
+	// Project is the project key of a Bitbucket Server repository, or
+	// "~user" for a personal one. It is empty for bitbucket.org.
+	Project  string
+	RepoSlug string
+}
+
+// serverHosts are the Bitbucket Server and Data Center hosts whose remote
+// URLs are recognized
+var serverHosts = map[string]bool{}
+
+// SetServerHosts registers the Bitbucket Server and Data Center hosts, such
+// as those in hosts.yml, whose remote URLs ParseBitbucketURL recognizes
+func SetServerHosts(hosts []string) {
+	serverHosts = map[string]bool{}
+	for _, host := range hosts {
+		if host = strings.ToLower(host); host != "" && host != cloudHost {
+			serverHosts[host] = true
+		}
+	}
 }
 
+const cloudHost = "bitbucket.org"
+
 var (
 	// SSH URL pattern: git@bitbucket.org:workspace/repo.git
 	sshPattern = regexp.MustCompile(`^git@bitbucket\.org:([^/]+)/([^/]+?)(?:\.git)?$`)
@@ -38,6 +71,7 @@ func ParseBitbucketURL(url string) (*BitbucketRemote, error) {
 	// Try SSH pattern
 	if matches := sshPattern.FindStringSubmatch(url); len(matches) == 3 {
 		return &BitbucketRemote{
+			Host:      cloudHost,
 			Workspace: matches[1],
 			RepoSlug:  matches[2],
 		}, nil
@@ -46,31 +80,86 @@ func ParseBitbucketURL(url string) (*BitbucketRemote, error) {
 	// Try HTTPS pattern
 	if matches := httpsPattern.FindStringSubmatch(url); len(matches) == 3 {
 		return &BitbucketRemote{
+			Host:      cloudHost,
 			Workspace: matches[1],
 			RepoSlug:  matches[2],
 		}, nil
 	}
 
+	if remote := parseServerURL(url); remote != nil {
+		return remote, nil
+	}
+
 	return nil, fmt.Errorf("not a valid Bitbucket URL: %s", url)
 }
 
-// IsBitbucketURL checks if a URL points to Bitbucket
+// scpPattern matches scp-like SSH URLs: [user@]host:path
+var scpPattern = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// parseServerURL parses the remote URLs of a registered Bitbucket Server
+// host: ssh://git@host:7999/PROJECT/repo.git, git@host:PROJECT/repo.git and
+// https://host[/context]/scm/PROJECT/repo.git. It returns nil for others.
+func parseServerURL(rawURL string) *BitbucketRemote {
+	var host, path string
+	if !strings.Contains(rawURL, "://") {
+		matches := scpPattern.FindStringSubmatch(rawURL)
+		if len(matches) != 3 {
+			return nil
+		}
+		host, path = matches[1], matches[2]
+	} else {
+		u, err := neturl.Parse(rawURL)
+		if err != nil {
+			return nil
+		}
+		host, path = u.Hostname(), u.Path
+		switch u.Scheme {
+		case "ssh":
+		case "http", "https":
+			_, after, found := strings.Cut(path, "/scm/")
+			if !found {
+				return nil
+			}
+			path = after
+		default:
+			return nil
+		}
+	}
+
+	host = strings.ToLower(host)
+	if !serverHosts[host] {
+		return nil
+	}
+
+	parts := strings.Split(strings.TrimSuffix(strings.Trim(path, "/"), ".git"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil
+	}
+	return &BitbucketRemote{
+		Host:      host,
+		Workspace: parts[0],
+		Project:   parts[0],
+		RepoSlug:  parts[1],
+	}
+}
+
+// IsBitbucketURL checks if a URL points to Bitbucket: bitbucket.org or a
+// registered Bitbucket Server host
 func IsBitbucketURL(url string) bool {
-	return strings.Contains(url, "bitbucket.org")
+	if strings.Contains(url, cloudHost) {
+		return true
+	}
+	return parseServerURL(strings.TrimSpace(url)) != nil
 }
 
 // GetRemotes returns all git remotes for the current repository
 func GetRemotes() ([]Remote, error) {
-	cmd := exec.Command("git", "remote", "-v")
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
+	out, err := run("remote", "-v")
+	if err != nil {
 		return nil, fmt.Errorf("failed to get git remotes: %w", err)
 	}
 
-	return parseRemotes(stdout.String())
+	return parseRemotes(out)
 }
 
 func parseRemotes(output string) ([]Remote, error) {
@@ -105,6 +194,7 @@ func parseRemotes(output string) ([]Remote, error) {
 		// Extract workspace and repo for Bitbucket URLs
 		if IsBitbucketURL(url) {
 			if bbRemote, err := ParseBitbucketURL(url); err == nil {
+				remotes[name].Host = bbRemote.Host
 				remotes[name].Workspace = bbRemote.Workspace
 				remotes[name].RepoSlug = bbRemote.RepoSlug
 			}
@@ -119,7 +209,17 @@ func parseRemotes(output string) ([]Remote, error) {
 	return result, nil
 }
 
-// GetBitbucketRemotes returns only Bitbucket remotes
+// RemoteEnvVar names the environment variable that selects the remote
+// commands detect the repository from. --remote sets it too.
+const RemoteEnvVar = "BB_REMOTE"
+
+// remoteConfigKey is the git config key recording the remote chosen with
+// "bb repo set-default --remote"
+const remoteConfigKey = "bb.remote"
+
+// GetBitbucketRemotes returns only Bitbucket remotes, most preferred first:
+// the selected remote (see SelectedRemote), then origin, then upstream,
+// then the rest by name
 func GetBitbucketRemotes() ([]Remote, error) {
 	allRemotes, err := GetRemotes()
 	if err != nil {
@@ -133,72 +233,265 @@ func GetBitbucketRemotes() ([]Remote, error) {
 		}
 	}
 
+	selected, _ := SelectedRemote()
+	sortRemotes(bbRemotes, selected)
 	return bbRemotes, nil
 }
 
-// GetDefaultRemote returns the default Bitbucket remote (prefers "origin")
+// sortRemotes orders remotes by preference, putting selected first
+func sortRemotes(remotes []Remote, selected string) {
+	rank := func(name string) int {
+		switch name {
+		case selected:
+			return 0
+		case "origin":
+			return 1
+		case "upstream":
+			return 2
+		default:
+			return 3
+		}
+	}
+	sort.SliceStable(remotes, func(i, j int) bool {
+		ri, rj := rank(remotes[i].Name), rank(remotes[j].Name)
+		if ri != rj {
+			return ri < rj
+		}
+		return remotes[i].Name < remotes[j].Name
+	})
+}
+
+// GetDefaultRemote returns the Bitbucket remote commands operate on: the
+// selected remote if there is one, else the most preferred of
+// GetBitbucketRemotes
 func GetDefaultRemote() (*Remote, error) {
+	dir, err := detectionDir()
+	if err != nil {
+		return nil, err
+	}
+
 	remotes, err := GetBitbucketRemotes()
 	if err != nil {
 		return nil, err
 	}
 
 	if len(remotes) == 0 {
-		return nil, fmt.Errorf("no Bitbucket remotes found")
+		return nil, fmt.Errorf("no Bitbucket remotes found in %s", dir)
 	}
 
-	// Prefer "origin"
-	for _, r := range remotes {
-		if r.Name == "origin" {
-			return &r, nil
-		}
+	if selected, source := SelectedRemote(); selected != "" && remotes[0].Name != selected {
+		return nil, fmt.Errorf("remote %q selected by %s is not a Bitbucket remote of the repository in %s", selected, source, dir)
 	}
-
-	// Return first remote
 	return &remotes[0], nil
 }
 
+// detectionDir returns the directory repository detection inspects: the
+// root of the repository containing the working directory, found with
+// "git rev-parse --show-toplevel" so commands work from any subdirectory, or
+// the git directory of a bare repository. Outside of a repository the error
+// names the working directory.
+func detectionDir() (string, error) {
+	if root, err := run("rev-parse", "--show-toplevel"); err == nil && root != "" {
+		return root, nil
+	}
+	if gitDir, err := run("rev-parse", "--absolute-git-dir"); err == nil && gitDir != "" {
+		return gitDir, nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("not a git repository")
+	}
+	return "", fmt.Errorf("%s is not inside a git repository", cwd)
+}
+
+// SelectedRemote returns the remote chosen with BB_REMOTE or --remote, or
+// else with "bb repo set-default --remote", and where the choice came from.
+// It returns an empty name if no remote was chosen.
+func SelectedRemote() (name, source string) {
+	if name := os.Getenv(RemoteEnvVar); name != "" {
+		return name, RemoteEnvVar
+	}
+	if name := ConfiguredRemote(); name != "" {
+		return name, "bb repo set-default --remote"
+	}
+	return "", ""
+}
+
+// ConfiguredRemote returns the remote recorded for the current repository
+// by SetSelectedRemote, or an empty string
+func ConfiguredRemote() string {
+	name, err := run("config", "--get", remoteConfigKey)
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+// SetSelectedRemote records name as the remote of the current repository
+// that commands operate on, or forgets the choice if name is empty
+func SetSelectedRemote(name string) error {
+	if name == "" {
+		if _, err := run("config", "--unset", remoteConfigKey); err != nil {
+			// Exit status 5 means the key was not set
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) && exitErr.ExitCode() == 5 {
+				return nil
+			}
+			return fmt.Errorf("failed to unset %s: %w", remoteConfigKey, err)
+		}
+		return nil
+	}
+	if _, err := run("config", remoteConfigKey, name); err != nil {
+		return fmt.Errorf("failed to set %s: %w", remoteConfigKey, err)
+	}
+	return nil
+}
+
 // IsGitRepository checks if the current directory is a git repository
 func IsGitRepository() bool {
-	cmd := exec.Command("git", "rev-parse", "--git-dir")
-	return cmd.Run() == nil
+	_, err := run("rev-parse", "--git-dir")
+	return err == nil
 }
 
-// GetCurrentBranch returns the current git branch
-func GetCurrentBranch() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-	var stdout bytes.Buffer
-	cmd.Stdout = &stdout
+// CurrentBranch returns the branch checked out in the current repository.
+// It fails when HEAD is detached.
+func CurrentBranch() (string, error) {
+	if !IsGitRepository() {
+		return "", fmt.Errorf("not a git repository")
+	}
+	branch, err := run("symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil || branch == "" {
+		return "", fmt.Errorf("not on any branch (detached HEAD)")
+	}
+	return branch, nil
+}
 
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to get current branch: %w", err)
+// Upstream is the remote branch a local branch tracks
+type Upstream struct {
+	// Remote is the name of the remote, or a URL for branches fetched
+	// without one
+	Remote string
+	// Branch is the name of the branch on the remote
+	Branch string
+}
+
+// UpstreamFor returns the upstream of a local branch, as set by
+// "git push -u" or "git branch --set-upstream-to"
+func UpstreamFor(branch string) (*Upstream, error) {
+	remote, err := run("config", "--get", "branch."+branch+".remote")
+	if err != nil || remote == "" {
+		return nil, fmt.Errorf("branch %s has no upstream", branch)
 	}
+	merge, err := run("config", "--get", "branch."+branch+".merge")
+	if err != nil || merge == "" {
+		return nil, fmt.Errorf("branch %s has no upstream", branch)
+	}
+	return &Upstream{Remote: remote, Branch: strings.TrimPrefix(merge, "refs/heads/")}, nil
+}
+
+// RemoteBranchName returns the name branch has on its remote: the branch
+// it tracks, or its own name if it tracks none or tracks a local branch
+func RemoteBranchName(branch string) string {
+	if upstream, err := UpstreamFor(branch); err == nil && upstream.Remote != "." {
+		return upstream.Branch
+	}
+	return branch
+}
 
-	return strings.TrimSpace(stdout.String()), nil
+// BranchConfig returns the value of branch.<branch>.<key> in the
+// repository's git config, or an empty string if it is not set
+func BranchConfig(branch, key string) string {
+	value, err := run("config", "--get", "branch."+branch+"."+key)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+// SetBranchConfig sets branch.<branch>.<key> in the repository's git config.
+// Git removes it along with the branch.
+func SetBranchConfig(branch, key, value string) error {
+	if _, err := run("config", "branch."+branch+"."+key, value); err != nil {
+		return fmt.Errorf("failed to set branch.%s.%s: %w", branch, key, err)
+	}
+	return nil
 }
 
 // GetRepoRoot returns the root directory of the git repository
 func GetRepoRoot() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
-	var stdout bytes.Buffer
-	cmd.Stdout = &stdout
-
-	if err := cmd.Run(); err != nil {
+	root, err := run("rev-parse", "--show-toplevel")
+	if err != nil {
 		return "", fmt.Errorf("failed to get repository root: %w", err)
 	}
+	return root, nil
+}
 
-	return strings.TrimSpace(stdout.String()), nil
+// HooksDir returns the directory git runs hooks from, which is
+// .git/hooks unless core.hooksPath names another
+func HooksDir() (string, error) {
+	dir, err := run("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("failed to find hooks directory: %w", err)
+	}
+	return filepath.Abs(dir)
+}
+
+// RevParse resolves a revision, such as a branch name, to a commit hash
+func RevParse(rev string) (string, error) {
+	hash, err := run("rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("unknown revision %s", rev)
+	}
+	return hash, nil
+}
+
+// BranchExists checks if a local branch exists
+func BranchExists(branch string) bool {
+	_, err := run("rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	return err == nil
+}
+
+// DeleteBranch deletes a local branch. Unless force is set, git refuses to
+// delete a branch that has not been merged.
+func DeleteBranch(branch string, force bool) error {
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	if _, err := run("branch", flag, branch); err != nil {
+		return fmt.Errorf("failed to delete branch %s: %w", branch, err)
+	}
+	return nil
 }
 
 // Checkout checks out a branch
 func Checkout(branch string) error {
-	cmd := exec.Command("git", "checkout", branch)
-	if err := cmd.Run(); err != nil {
+	if _, err := run("checkout", branch); err != nil {
 		return fmt.Errorf("failed to checkout branch %s: %w", branch, err)
 	}
 	return nil
 }
 
+// SetUpstream makes branch track the branch of the same name on remote
+func SetUpstream(branch, remote string) error {
+	if _, err := run("branch", "--set-upstream-to="+remote+"/"+branch, branch); err != nil {
+		return fmt.Errorf("failed to set upstream of %s: %w", branch, err)
+	}
+	return nil
+}
+
+// SetUpstreamURL makes branch pull from the branch of the same name at url,
+// for branches fetched from a repository that has no remote
+func SetUpstreamURL(branch, url string) error {
+	if _, err := run("config", "branch."+branch+".remote", url); err != nil {
+		return fmt.Errorf("failed to set upstream of %s: %w", branch, err)
+	}
+	if _, err := run("config", "branch."+branch+".merge", "refs/heads/"+branch); err != nil {
+		return fmt.Errorf("failed to set upstream of %s: %w", branch, err)
+	}
+	return nil
+}
+
 // Fetch fetches from a remote
 func Fetch(remote string, refspec string) error {
 	args := []string{"fetch", remote}
@@ -206,26 +499,371 @@ func Fetch(remote string, refspec string) error {
 		args = append(args, refspec)
 	}
 
-	cmd := exec.Command("git", args...)
-	if err := cmd.Run(); err != nil {
+	if _, err := run(args...); err != nil {
 		return fmt.Errorf("failed to fetch from %s: %w", remote, err)
 	}
 	return nil
 }
 
+// Push pushes branch to remote, recording remote as its upstream if
+// setUpstream is set
+func Push(remote, branch string, setUpstream bool) error {
+	args := []string{"push"}
+	if setUpstream {
+		args = append(args, "--set-upstream")
+	}
+	args = append(args, remote, branch)
+
+	if _, err := run(args...); err != nil {
+		return fmt.Errorf("failed to push %s to %s: %w", branch, remote, err)
+	}
+	return nil
+}
+
+// Merge merges ref into the current branch. With ffOnly, it fails rather
+// than create a merge commit.
+func Merge(ref string, ffOnly bool) error {
+	args := []string{"merge", ref}
+	if ffOnly {
+		args = append(args, "--ff-only")
+	}
+	if _, err := run(args...); err != nil {
+		return fmt.Errorf("failed to merge %s: %w", ref, err)
+	}
+	return nil
+}
+
+// ResetHard resets the current branch and working tree to ref, discarding
+// local changes
+func ResetHard(ref string) error {
+	if _, err := run("reset", "--hard", ref); err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", ref, err)
+	}
+	return nil
+}
+
+// CommitSubjects returns the subject lines of the commits in revRange, such
+// as "main..feature", newest first
+func CommitSubjects(revRange string) ([]string, error) {
+	out, err := run("log", "--format=%s", revRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits in %s: %w", revRange, err)
+	}
+
+	var subjects []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			subjects = append(subjects, line)
+		}
+	}
+	return subjects, nil
+}
+
+// CountCommits returns the number of commits in revRange, such as
+// "origin/main..feature"
+func CountCommits(revRange string) (int, error) {
+	out, err := run("rev-list", "--count", revRange)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count commits in %s: %w", revRange, err)
+	}
+	count, err := strconv.Atoi(out)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count commits in %s: unexpected output %q", revRange, out)
+	}
+	return count, nil
+}
+
+// IsAncestor reports whether commit ancestor is reachable from rev. It is
+// false when either is unknown locally.
+func IsAncestor(ancestor, rev string) bool {
+	_, err := run("merge-base", "--is-ancestor", ancestor, rev)
+	return err == nil
+}
+
+// RemoteBranchCommit asks remote for the commit its branch points to. It
+// returns an empty string if the remote has no such branch.
+func RemoteBranchCommit(remote, branch string) (string, error) {
+	out, err := run("ls-remote", "--heads", remote, "refs/heads/"+branch)
+	if err != nil {
+		return "", fmt.Errorf("failed to query %s: %w", remote, err)
+	}
+	if fields := strings.Fields(out); len(fields) > 0 {
+		return fields[0], nil
+	}
+	return "", nil
+}
+
+// FileStatus is the state of a changed file in the working tree
+type FileStatus struct {
+	// Index and WorkTree are the porcelain status codes of the staged and
+	// unstaged changes, e.g. 'M' for modified or '?' for untracked
+	Index    byte
+	WorkTree byte
+	Path     string
+}
+
+// Status returns the changed and untracked files of the working tree
+func Status() ([]FileStatus, error) {
+	out, err := runRaw("status", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+	return parseStatus(out), nil
+}
+
+func parseStatus(output string) []FileStatus {
+	var files []FileStatus
+	for _, line := range strings.Split(output, "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		path := line[3:]
+		// Renames are reported as "old -> new"
+		if i := strings.Index(path, " -> "); i != -1 {
+			path = path[i+4:]
+		}
+		files = append(files, FileStatus{Index: line[0], WorkTree: line[1], Path: path})
+	}
+	return files
+}
+
+// DefaultBranch returns the default branch of remote as last recorded in
+// refs/remotes/<remote>/HEAD, which git sets when cloning
+func DefaultBranch(remote string) (string, error) {
+	ref, err := run("symbolic-ref", "--quiet", "--short", "refs/remotes/"+remote+"/HEAD")
+	if err != nil || ref == "" {
+		return "", fmt.Errorf("default branch of %s is not known", remote)
+	}
+	return strings.TrimPrefix(ref, remote+"/"), nil
+}
+
+// SetDefaultBranch records branch as the default branch of remote in
+// refs/remotes/<remote>/HEAD. The branch must have been fetched.
+func SetDefaultBranch(remote, branch string) error {
+	if _, err := run("remote", "set-head", remote, branch); err != nil {
+		return fmt.Errorf("failed to set default branch of %s: %w", remote, err)
+	}
+	return nil
+}
+
+// RemoteURL returns the fetch URL of a remote
+func RemoteURL(name string) (string, error) {
+	url, err := run("remote", "get-url", name)
+	if err != nil {
+		return "", fmt.Errorf("no remote named %s", name)
+	}
+	return url, nil
+}
+
+// RemoteURLOf returns the fetch URL of a remote of the repository at dir
+func RemoteURLOf(dir, name string) (string, error) {
+	url, err := run("-C", dir, "remote", "get-url", name)
+	if err != nil {
+		return "", fmt.Errorf("no remote named %s", name)
+	}
+	return url, nil
+}
+
+// HeadCommit returns the hash of the commit checked out in the repository
+// at dir
+func HeadCommit(dir string) (string, error) {
+	hash, err := run("-C", dir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to read HEAD of %s: %w", dir, err)
+	}
+	return hash, nil
+}
+
+// ParentCount returns how many parents a commit has: more than one for a
+// merge commit
+func ParentCount(commit string) (int, error) {
+	out, err := run("rev-list", "--parents", "-n", "1", commit)
+	if err != nil {
+		return 0, fmt.Errorf("unknown commit %s: %w", commit, err)
+	}
+	return len(strings.Fields(out)) - 1, nil
+}
+
+// AddWorktree checks out commit, detached, in a new worktree at dir, so
+// it can be worked on without touching the current checkout
+func AddWorktree(dir, commit string) error {
+	if _, err := run("worktree", "add", "--quiet", "--detach", dir, commit); err != nil {
+		return fmt.Errorf("failed to create worktree: %w", err)
+	}
+	return nil
+}
+
+// RemoveWorktree removes the worktree at dir, discarding any changes in it
+func RemoveWorktree(dir string) error {
+	if _, err := run("worktree", "remove", "--force", dir); err != nil {
+		return fmt.Errorf("failed to remove worktree %s: %w", dir, err)
+	}
+	return nil
+}
+
+// Revert commits the reversal of commit in the repository at dir, with
+// message. For a merge commit, mainline is the number of the parent whose
+// side is kept, usually 1; it is 0 for other commits. If the changes
+// don't revert cleanly, the repository is left as it was.
+func Revert(dir, commit string, mainline int, message string) error {
+	args := []string{"-C", dir, "revert", "--no-commit"}
+	if mainline > 0 {
+		args = append(args, "-m", strconv.Itoa(mainline))
+	}
+	if _, err := run(append(args, commit)...); err != nil {
+		_, _ = run("-C", dir, "revert", "--abort")
+		return fmt.Errorf("failed to revert %s: %w", commit, err)
+	}
+	if _, err := run("-C", dir, "commit", "--quiet", "-m", message); err != nil {
+		_, _ = run("-C", dir, "revert", "--abort")
+		return fmt.Errorf("failed to commit the revert of %s: %w", commit, err)
+	}
+	return nil
+}
+
+// PullFastForward fast-forwards the repository at dir to its upstream
+func PullFastForward(dir string) error {
+	if _, err := run("-C", dir, "pull", "--ff-only", "--quiet"); err != nil {
+		return fmt.Errorf("failed to pull: %w", err)
+	}
+	return nil
+}
+
+// AddRemote adds a remote to the repository at dir, or to the current
+// repository if dir is empty
+func AddRemote(dir, name, url string) error {
+	args := []string{"remote", "add", name, url}
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	if _, err := run(args...); err != nil {
+		return fmt.Errorf("failed to add remote %s: %w", name, err)
+	}
+	return nil
+}
+
+// SetRemoteURL changes the URL of an existing remote
+func SetRemoteURL(name, url string) error {
+	if _, err := run("remote", "set-url", name, url); err != nil {
+		return fmt.Errorf("failed to set URL of remote %s: %w", name, err)
+	}
+	return nil
+}
+
 // Clone clones a repository
 func Clone(url string, dest string) error {
-	args := []string{"clone", url}
+	return CloneWithOptions(url, dest, CloneOptions{})
+}
+
+// CloneOptions are options for CloneWithOptions
+type CloneOptions struct {
+	// Depth makes a shallow clone of that many commits, if positive
+	Depth int
+	// Branch checks out a branch other than the default
+	Branch string
+	// SingleBranch fetches only the history of Branch, or of the default
+	// branch
+	SingleBranch bool
+	// Bare makes a bare repository, without a working tree
+	Bare bool
+	// Sparse checks out only these directories, with a cone mode sparse
+	// checkout
+	Sparse []string
+	// Progress makes git report progress even when Stderr is not a terminal
+	Progress bool
+	// Stdout and Stderr receive git's output; it is discarded if nil
+	Stdout io.Writer
+	Stderr io.Writer
+	// Context, if set, kills git when it is done
+	Context context.Context
+}
+
+// CloneWithOptions clones a repository into dest, or a directory named
+// after it if dest is empty
+func CloneWithOptions(url, dest string, opts CloneOptions) error {
+	if len(opts.Sparse) > 0 {
+		if opts.Bare {
+			return fmt.Errorf("a bare clone has no working tree to check out sparsely")
+		}
+		if dest == "" {
+			return fmt.Errorf("a sparse clone needs a destination directory")
+		}
+	}
+
+	args := []string{"clone"}
+	if opts.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(opts.Depth))
+	}
+	if opts.Branch != "" {
+		args = append(args, "--branch", opts.Branch)
+	}
+	if opts.SingleBranch {
+		args = append(args, "--single-branch")
+	}
+	if opts.Bare {
+		args = append(args, "--bare")
+	}
+	if len(opts.Sparse) > 0 {
+		args = append(args, "--sparse")
+	}
+	if opts.Progress {
+		args = append(args, "--progress")
+	}
+	args = append(args, url)
 	if dest != "" {
 		args = append(args, dest)
 	}
 
-	cmd := exec.Command("git", args...)
-	cmd.Stdout = nil
-	cmd.Stderr = nil
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stdout = opts.Stdout
+	cmd.Stderr = opts.Stderr
 
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to clone repository: %w", err)
 	}
+
+	if len(opts.Sparse) > 0 {
+		args := append([]string{"-C", dest, "sparse-checkout", "set"}, opts.Sparse...)
+		if _, err := run(args...); err != nil {
+			return fmt.Errorf("failed to set up sparse checkout: %w", err)
+		}
+	}
 	return nil
 }
+
+// Version returns the output of "git --version", e.g. "git version 2.43.0"
+func Version() (string, error) {
+	version, err := run("--version")
+	if err != nil {
+		return "", fmt.Errorf("git is not installed: %w", err)
+	}
+	return version, nil
+}
+
+// run runs git with args and returns its output with surrounding whitespace
+// removed
+func run(args ...string) (string, error) {
+	out, err := runRaw(args...)
+	return strings.TrimSpace(out), err
+}
+
+// runRaw runs git with args and returns its output. If git fails, the error
+// carries what it wrote to stderr.
+func runRaw(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
@@ -1,6 +1,10 @@
 package git
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -331,3 +335,572 @@ func TestParseBitbucketURL_TrimsWhitespace(t *testing.T) {
 		t.Errorf("expected repo 'repo', got '%s'", remote.RepoSlug)
 	}
 }
+
+func TestParseBitbucketURL_Server(t *testing.T) {
+	SetServerHosts([]string{"bitbucket.org", "Bitbucket.Example.com"})
+	t.Cleanup(func() { SetServerHosts(nil) })
+
+	tests := []struct {
+		url     string
+		project string
+		slug    string
+	}{
+		{"ssh://git@bitbucket.example.com:7999/PROJ/repo.git", "PROJ", "repo"},
+		{"ssh://git@bitbucket.example.com/PROJ/repo", "PROJ", "repo"},
+		{"git@bitbucket.example.com:PROJ/repo.git", "PROJ", "repo"},
+		{"https://bitbucket.example.com/scm/PROJ/repo.git", "PROJ", "repo"},
+		{"https://alice@bitbucket.example.com:8443/bitbucket/scm/PROJ/repo.git", "PROJ", "repo"},
+		{"https://bitbucket.example.com/scm/~alice/dotfiles.git", "~alice", "dotfiles"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			remote, err := ParseBitbucketURL(tt.url)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if remote.Host != "bitbucket.example.com" || remote.Project != tt.project || remote.Workspace != tt.project || remote.RepoSlug != tt.slug {
+				t.Errorf("unexpected remote: %+v", remote)
+			}
+			if !IsBitbucketURL(tt.url) {
+				t.Error("expected IsBitbucketURL to be true")
+			}
+		})
+	}
+
+	for _, url := range []string{
+		"ssh://git@other.example.com:7999/PROJ/repo.git",
+		"https://bitbucket.example.com/PROJ/repo.git",
+		"https://bitbucket.example.com/scm/PROJ/group/repo.git",
+		"ftp://bitbucket.example.com/scm/PROJ/repo.git",
+	} {
+		if _, err := ParseBitbucketURL(url); err == nil {
+			t.Errorf("expected an error for %s", url)
+		}
+	}
+
+	cloud, err := ParseBitbucketURL("https://bitbucket.org/workspace/repo.git")
+	if err != nil || cloud.Host != "bitbucket.org" || cloud.Project != "" {
+		t.Errorf("unexpected bitbucket.org remote: %+v, %v", cloud, err)
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	output := " M internal/git/git.go\nA  new.go\nR  old.go -> renamed.go\n?? untracked.txt\n"
+
+	files := parseStatus(output)
+	if len(files) != 4 {
+		t.Fatalf("expected 4 files, got %d: %+v", len(files), files)
+	}
+
+	want := []FileStatus{
+		{Index: ' ', WorkTree: 'M', Path: "internal/git/git.go"},
+		{Index: 'A', WorkTree: ' ', Path: "new.go"},
+		{Index: 'R', WorkTree: ' ', Path: "renamed.go"},
+		{Index: '?', WorkTree: '?', Path: "untracked.txt"},
+	}
+	for i, w := range want {
+		if files[i] != w {
+			t.Errorf("file %d: expected %+v, got %+v", i, w, files[i])
+		}
+	}
+}
+
+func TestParseStatus_Clean(t *testing.T) {
+	if files := parseStatus(""); len(files) != 0 {
+		t.Errorf("expected no files, got %+v", files)
+	}
+}
+
+// initRepo creates a repository with one commit on main in a temporary
+// directory and makes it the working directory
+func initRepo(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Chdir(t.TempDir())
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"commit", "-q", "--allow-empty", "-m", "Initial commit"},
+	} {
+		if _, err := run(args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+}
+
+func TestBranchOperations(t *testing.T) {
+	initRepo(t)
+
+	if branch, err := CurrentBranch(); err != nil || branch != "main" {
+		t.Fatalf("CurrentBranch() = %q, %v; want main", branch, err)
+	}
+	if !BranchExists("main") || BranchExists("feature") {
+		t.Error("expected only main to exist")
+	}
+
+	if _, err := run("checkout", "-q", "-b", "feature"); err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range []string{"Add parser", "Fix parser"} {
+		if _, err := run("commit", "-q", "--allow-empty", "-m", msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	subjects, err := CommitSubjects("main..feature")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(subjects) != 2 || subjects[0] != "Fix parser" || subjects[1] != "Add parser" {
+		t.Errorf("unexpected subjects: %q", subjects)
+	}
+
+	if _, err := RevParse("feature"); err != nil {
+		t.Errorf("RevParse(feature): %v", err)
+	}
+	if _, err := RevParse("missing"); err == nil {
+		t.Error("expected an error for an unknown revision")
+	}
+
+	if err := Checkout("main"); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteBranch("feature", false); err == nil {
+		t.Error("expected an unmerged branch not to be deleted without force")
+	}
+	if err := DeleteBranch("feature", true); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if BranchExists("feature") {
+		t.Error("expected feature to be deleted")
+	}
+}
+
+func TestRemoteOperations(t *testing.T) {
+	initRepo(t)
+
+	if err := AddRemote("", "origin", "git@bitbucket.org:workspace/repo.git"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := SetRemoteURL("origin", "https://bitbucket.org/workspace/other.git"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if url, err := RemoteURL("origin"); err != nil || url != "https://bitbucket.org/workspace/other.git" {
+		t.Errorf("RemoteURL(origin) = %q, %v", url, err)
+	}
+	if _, err := RemoteURL("upstream"); err == nil {
+		t.Error("expected an error for a missing remote")
+	}
+
+	remote, err := GetDefaultRemote()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if remote.Name != "origin" || remote.RepoSlug != "other" {
+		t.Errorf("unexpected default remote: %+v", remote)
+	}
+}
+
+func TestDefaultBranch(t *testing.T) {
+	initRepo(t)
+
+	if err := AddRemote("", "origin", "https://bitbucket.org/workspace/repo.git"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DefaultBranch("origin"); err == nil {
+		t.Error("expected an error before the default branch is recorded")
+	}
+
+	if err := SetDefaultBranch("origin", "main"); err == nil {
+		t.Error("expected an error for a branch that hasn't been fetched")
+	}
+
+	// Pretend main has been fetched
+	if _, err := run("update-ref", "refs/remotes/origin/main", "HEAD"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetDefaultBranch("origin", "main"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if branch, err := DefaultBranch("origin"); err != nil || branch != "main" {
+		t.Errorf("DefaultBranch(origin) = %q, %v; want main", branch, err)
+	}
+}
+
+func TestUpstreamFor(t *testing.T) {
+	initRepo(t)
+
+	if _, err := run("checkout", "-q", "-b", "local-name"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UpstreamFor("local-name"); err == nil {
+		t.Error("expected an error for a branch without an upstream")
+	}
+	if got := RemoteBranchName("local-name"); got != "local-name" {
+		t.Errorf("RemoteBranchName() = %q, want local-name", got)
+	}
+
+	if err := AddRemote("", "origin", "https://bitbucket.org/workspace/repo.git"); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"config", "branch.local-name.remote", "origin"},
+		{"config", "branch.local-name.merge", "refs/heads/feature/remote-name"},
+	} {
+		if _, err := run(args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	upstream, err := UpstreamFor("local-name")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if upstream.Remote != "origin" || upstream.Branch != "feature/remote-name" {
+		t.Errorf("unexpected upstream: %+v", upstream)
+	}
+	if got := RemoteBranchName("local-name"); got != "feature/remote-name" {
+		t.Errorf("RemoteBranchName() = %q, want feature/remote-name", got)
+	}
+
+	// Detached HEAD has no current branch
+	if _, err := run("checkout", "-q", "--detach"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CurrentBranch(); err == nil {
+		t.Error("expected an error on a detached HEAD")
+	}
+}
+
+func TestRemoteBranchCommit(t *testing.T) {
+	initRepo(t)
+
+	bare := t.TempDir()
+	if _, err := run("init", "-q", "--bare", bare); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddRemote("", "origin", bare); err != nil {
+		t.Fatal(err)
+	}
+
+	if sha, err := RemoteBranchCommit("origin", "main"); err != nil || sha != "" {
+		t.Fatalf("RemoteBranchCommit() before push = %q, %v; want no commit", sha, err)
+	}
+
+	if err := Push("origin", "main", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	head, err := RevParse("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pushed, err := RemoteBranchCommit("origin", "main")
+	if err != nil || pushed != head {
+		t.Fatalf("RemoteBranchCommit() = %q, %v; want %s", pushed, err, head)
+	}
+	if upstream, err := UpstreamFor("main"); err != nil || upstream.Remote != "origin" {
+		t.Errorf("expected push to set the upstream, got %+v, %v", upstream, err)
+	}
+
+	if _, err := run("commit", "-q", "--allow-empty", "-m", "Unpushed"); err != nil {
+		t.Fatal(err)
+	}
+	if !IsAncestor(pushed, "HEAD") || IsAncestor("HEAD", pushed) {
+		t.Error("expected the pushed commit to be an ancestor of HEAD only")
+	}
+	if count, err := CountCommits(pushed + "..HEAD"); err != nil || count != 1 {
+		t.Errorf("CountCommits() = %d, %v; want 1", count, err)
+	}
+}
+
+func TestBranchConfig(t *testing.T) {
+	initRepo(t)
+
+	if got := BranchConfig("main", "bb-pr"); got != "" {
+		t.Errorf("BranchConfig() = %q, want empty", got)
+	}
+	if err := SetBranchConfig("main", "bb-pr", "42"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := BranchConfig("main", "bb-pr"); got != "42" {
+		t.Errorf("BranchConfig() = %q, want 42", got)
+	}
+
+	// Deleting a branch drops its config
+	if _, err := run("checkout", "-q", "-b", "feature"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetBranchConfig("feature", "bb-pr", "7"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Checkout("main"); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteBranch("feature", true); err != nil {
+		t.Fatal(err)
+	}
+	if got := BranchConfig("feature", "bb-pr"); got != "" {
+		t.Errorf("expected the config to go with the branch, got %q", got)
+	}
+}
+
+func TestSortRemotes(t *testing.T) {
+	remotes := []Remote{{Name: "zeta"}, {Name: "upstream"}, {Name: "alpha"}, {Name: "origin"}}
+
+	sortRemotes(remotes, "")
+	want := []string{"origin", "upstream", "alpha", "zeta"}
+	for i, r := range remotes {
+		if r.Name != want[i] {
+			t.Fatalf("unexpected order: %v", remotes)
+		}
+	}
+
+	sortRemotes(remotes, "zeta")
+	if remotes[0].Name != "zeta" || remotes[1].Name != "origin" {
+		t.Errorf("expected the selected remote first, got %v", remotes)
+	}
+}
+
+func TestSelectedRemote(t *testing.T) {
+	initRepo(t)
+	t.Setenv(RemoteEnvVar, "")
+
+	for _, name := range []string{"origin", "upstream"} {
+		if err := AddRemote("", name, "https://bitbucket.org/"+name+"/repo.git"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	remote, err := GetDefaultRemote()
+	if err != nil || remote.Name != "origin" {
+		t.Fatalf("GetDefaultRemote() = %+v, %v; want origin", remote, err)
+	}
+
+	if err := SetSelectedRemote("upstream"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if remote, err := GetDefaultRemote(); err != nil || remote.Workspace != "upstream" {
+		t.Errorf("GetDefaultRemote() = %+v, %v; want upstream", remote, err)
+	}
+
+	t.Setenv(RemoteEnvVar, "origin")
+	if remote, err := GetDefaultRemote(); err != nil || remote.Name != "origin" {
+		t.Errorf("expected %s to override the configured remote, got %+v, %v", RemoteEnvVar, remote, err)
+	}
+	t.Setenv(RemoteEnvVar, "missing")
+	if _, err := GetDefaultRemote(); err == nil {
+		t.Error("expected an error for a missing selected remote")
+	}
+	t.Setenv(RemoteEnvVar, "")
+
+	if err := SetSelectedRemote(""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := SetSelectedRemote(""); err != nil {
+		t.Errorf("unsetting twice should not fail: %v", err)
+	}
+	if name := ConfiguredRemote(); name != "" {
+		t.Errorf("ConfiguredRemote() = %q after unset", name)
+	}
+}
+
+func TestCloneWithOptions(t *testing.T) {
+	initRepo(t)
+	source, err := GetRepoRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{"src", "docs"} {
+		if err := os.MkdirAll(filepath.Join(source, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(source, dir, "README"), []byte(dir), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"add", "."},
+		{"commit", "-q", "-m", "Add files"},
+		{"branch", "other"},
+	} {
+		if _, err := run(args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	url := "file://" + source
+
+	sparse := filepath.Join(t.TempDir(), "sparse")
+	if err := CloneWithOptions(url, sparse, CloneOptions{Sparse: []string{"src"}, SingleBranch: true, Depth: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(sparse, "src", "README")); err != nil {
+		t.Errorf("expected src to be checked out: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(sparse, "docs")); !os.IsNotExist(err) {
+		t.Errorf("expected docs to be left out, got %v", err)
+	}
+	if out, _ := run("-C", sparse, "branch", "-r"); strings.Contains(out, "other") {
+		t.Errorf("expected a single branch clone, got remote branches %q", out)
+	}
+
+	bare := filepath.Join(t.TempDir(), "bare.git")
+	if err := CloneWithOptions(url, bare, CloneOptions{Bare: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out, err := run("-C", bare, "rev-parse", "--is-bare-repository"); err != nil || out != "true" {
+		t.Errorf("expected a bare repository, got %q, %v", out, err)
+	}
+
+	if err := CloneWithOptions(url, bare+"2", CloneOptions{Bare: true, Sparse: []string{"src"}}); err == nil {
+		t.Error("expected an error for a sparse bare clone")
+	}
+}
+
+func TestGetDefaultRemote_FromSubdirectory(t *testing.T) {
+	initRepo(t)
+	t.Setenv(RemoteEnvVar, "")
+
+	root, err := GetRepoRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetDefaultRemote(); err == nil || !strings.Contains(err.Error(), root) {
+		t.Errorf("expected the error to name %s, got %v", root, err)
+	}
+
+	if err := AddRemote("", "origin", "https://bitbucket.org/workspace/repo.git"); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(sub)
+
+	remote, err := GetDefaultRemote()
+	if err != nil || remote.Workspace != "workspace" || remote.RepoSlug != "repo" {
+		t.Errorf("GetDefaultRemote() = %+v, %v; want workspace/repo", remote, err)
+	}
+
+	outside := t.TempDir()
+	t.Chdir(outside)
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(outside))
+	if _, err := GetDefaultRemote(); err == nil || !strings.Contains(err.Error(), "is not inside a git repository") {
+		t.Errorf("expected a not inside a git repository error, got %v", err)
+	}
+}
+
+func TestHooksDir(t *testing.T) {
+	initRepo(t)
+	root, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dir, err := HooksDir(); err != nil || dir != filepath.Join(root, ".git", "hooks") {
+		t.Errorf("HooksDir() = %q, %v; want .git/hooks", dir, err)
+	}
+
+	if _, err := run("config", "core.hooksPath", "githooks"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir("sub", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir("sub")
+	if dir, err := HooksDir(); err != nil || dir != filepath.Join(root, "githooks") {
+		t.Errorf("HooksDir() = %q, %v; want githooks at the repository root", dir, err)
+	}
+}
+
+func TestPullFastForward(t *testing.T) {
+	initRepo(t)
+	upstream, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone := filepath.Join(t.TempDir(), "clone")
+	if err := Clone(upstream, clone); err != nil {
+		t.Fatal(err)
+	}
+	if url, err := RemoteURLOf(clone, "origin"); err != nil || url != upstream {
+		t.Errorf("RemoteURLOf() = %q, %v; want %q", url, err, upstream)
+	}
+
+	if _, err := run("commit", "-q", "--allow-empty", "-m", "Second commit"); err != nil {
+		t.Fatal(err)
+	}
+	want, err := HeadCommit(upstream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := PullFastForward(clone); err != nil {
+		t.Fatalf("PullFastForward() error = %v", err)
+	}
+	if got, err := HeadCommit(clone); err != nil || got != want {
+		t.Errorf("HeadCommit() = %q, %v; want %q", got, err, want)
+	}
+}
+
+func TestRevertInWorktree(t *testing.T) {
+	initRepo(t)
+	if err := os.WriteFile("parser.go", []byte("package parser\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"checkout", "-q", "-b", "feature"},
+		{"add", "parser.go"},
+		{"commit", "-q", "-m", "Add parser"},
+		{"checkout", "-q", "main"},
+		{"merge", "-q", "--no-ff", "-m", "Merge feature", "feature"},
+	} {
+		if _, err := run(args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+	merge, err := RevParse("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := ParentCount(merge); err != nil || n != 2 {
+		t.Fatalf("ParentCount() = %d, %v; want 2", n, err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "revert")
+	if err := AddWorktree(dir, merge); err != nil {
+		t.Fatal(err)
+	}
+	if err := Revert(dir, merge, 1, "Revert the parser"); err != nil {
+		t.Fatalf("Revert() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "parser.go")); !os.IsNotExist(err) {
+		t.Errorf("expected parser.go to be removed in the worktree, got %v", err)
+	}
+	if subjects, err := CommitSubjects(merge + ".." + mustHead(t, dir)); err != nil || len(subjects) != 1 || subjects[0] != "Revert the parser" {
+		t.Errorf("unexpected revert commits %q, %v", subjects, err)
+	}
+	// The current checkout is untouched
+	if _, err := os.Stat("parser.go"); err != nil {
+		t.Errorf("expected parser.go to remain in the checkout: %v", err)
+	}
+
+	if err := RemoveWorktree(dir); err != nil {
+		t.Errorf("RemoveWorktree() error = %v", err)
+	}
+}
+
+func mustHead(t *testing.T, dir string) string {
+	t.Helper()
+	head, err := HeadCommit(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return head
+}
@@ -0,0 +1,275 @@
+// Package i18n translates the messages bb shows people. Messages are kept
+// in catalogs, one YAML file per language in locales/, keyed by message
+// ID. English is the source language: every message is in en.yaml, and
+// other catalogs fall back to it for those they don't translate yet.
+//
+// A message is a text/template, given its values by name:
+//
+//	offline.notice: "Offline: showing data cached {{.Age}}"
+//
+// Messages that depend on a count have a form per plural category:
+//
+//	verbose.summary:
+//	  one: "{{.Count}} API call in {{.Duration}}"
+//	  other: "{{.Count}} API calls in {{.Duration}}"
+package i18n
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLanguage is the language of the source messages
+const DefaultLanguage = "en"
+
+//go:embed locales/*.yaml
+var localeFiles embed.FS
+
+// message is a message of a catalog, with a form per plural category; a
+// message that doesn't vary with a count has only the "other" form
+type message map[string]string
+
+func (m *message) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*m = message{"other": node.Value}
+		return nil
+	}
+	var forms map[string]string
+	if err := node.Decode(&forms); err != nil {
+		return err
+	}
+	*m = forms
+	return nil
+}
+
+// catalog is the messages of a language, by ID
+type catalog map[string]message
+
+var (
+	mu        sync.Mutex
+	catalogs  map[string]catalog
+	language  string
+	templates = map[string]*template.Template{}
+)
+
+// load reads the embedded catalogs, once. A broken catalog is a bug in bb,
+// caught by the tests, so it panics.
+func load() {
+	if catalogs != nil {
+		return
+	}
+	catalogs = map[string]catalog{}
+	files, _ := localeFiles.ReadDir("locales")
+	for _, f := range files {
+		data, err := localeFiles.ReadFile(path.Join("locales", f.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var c catalog
+		if err := yaml.Unmarshal(data, &c); err != nil {
+			panic(fmt.Sprintf("i18n: %s: %v", f.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(f.Name(), ".yaml")] = c
+	}
+}
+
+// Languages returns the languages there are catalogs for, sorted
+func Languages() []string {
+	mu.Lock()
+	defer mu.Unlock()
+	load()
+	var languages []string
+	for lang := range catalogs {
+		languages = append(languages, lang)
+	}
+	slices.Sort(languages)
+	return languages
+}
+
+// IsSupported reports whether there is a catalog for locale, or for its
+// language
+func IsSupported(locale string) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	load()
+	return match(locale) != ""
+}
+
+// SetLocale selects the catalog for locale, such as "pt-BR" or "de_DE.UTF-8",
+// or if it is empty for the locale the environment selects in LC_ALL,
+// LC_MESSAGES or LANG. The closest match is used: the catalog of the
+// locale, else of its language, else English.
+func SetLocale(locale string) {
+	if locale == "" {
+		locale = envLocale()
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	load()
+	language = match(locale)
+	if language == "" {
+		language = DefaultLanguage
+	}
+}
+
+// Language returns the language messages are shown in
+func Language() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return currentLanguage()
+}
+
+// currentLanguage returns the selected language, selecting it from the
+// environment if none was
+func currentLanguage() string {
+	load()
+	if language == "" {
+		language = match(envLocale())
+		if language == "" {
+			language = DefaultLanguage
+		}
+	}
+	return language
+}
+
+// envLocale returns the locale the environment selects for messages
+func envLocale() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// match returns the catalog that best fits locale, or "" if none does.
+// The caller holds mu.
+func match(locale string) string {
+	// POSIX locales look like de_DE.UTF-8@euro; their codeset and modifier
+	// don't matter here
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	locale = strings.ReplaceAll(locale, "_", "-")
+	if locale == "" || locale == "C" || locale == "POSIX" {
+		return ""
+	}
+	for lang := range catalogs {
+		if strings.EqualFold(lang, locale) {
+			return lang
+		}
+	}
+	base, _, _ := strings.Cut(locale, "-")
+	for lang := range catalogs {
+		if strings.EqualFold(lang, base) {
+			return lang
+		}
+	}
+	return ""
+}
+
+// T returns the message id in the selected language, filled in with the
+// values given as name and value pairs:
+//
+//	i18n.T("offline.notice", "Age", age)
+//
+// A message missing from every catalog is returned as its ID, so a
+// mistake shows rather than hiding the output.
+func T(id string, args ...any) string {
+	return translate(id, -1, args)
+}
+
+// N is like T for a message that depends on count, which picks its plural
+// form and is given to it as Count
+func N(id string, count int, args ...any) string {
+	return translate(id, count, append([]any{"Count", count}, args...))
+}
+
+func translate(id string, count int, args []any) string {
+	mu.Lock()
+	lang := currentLanguage()
+	text, ok := lookup(lang, id, count)
+	mu.Unlock()
+	if !ok {
+		return id
+	}
+	if !strings.Contains(text, "{{") {
+		return text
+	}
+
+	tmpl, err := parse(text)
+	if err != nil {
+		return text
+	}
+	data := make(map[string]any, len(args)/2)
+	for i := 0; i+1 < len(args); i += 2 {
+		if name, ok := args[i].(string); ok {
+			data[name] = args[i+1]
+		}
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return text
+	}
+	return b.String()
+}
+
+// lookup finds message id in lang, else in English, and returns its form
+// for count. The caller holds mu.
+func lookup(lang, id string, count int) (string, bool) {
+	for _, l := range []string{lang, DefaultLanguage} {
+		m, ok := catalogs[l][id]
+		if !ok {
+			continue
+		}
+		if count >= 0 {
+			if form, ok := m[pluralCategory(l, count)]; ok {
+				return form, true
+			}
+		}
+		if form, ok := m["other"]; ok {
+			return form, true
+		}
+	}
+	return "", false
+}
+
+// parse returns the template of a message form, parsed once
+func parse(text string) (*template.Template, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if tmpl, ok := templates[text]; ok {
+		return tmpl, nil
+	}
+	tmpl, err := template.New("").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	templates[text] = tmpl
+	return tmpl, nil
+}
+
+// pluralRules give the plural category of a count in languages whose rules
+// differ from English. Add the rule of a language, from the CLDR plural
+// rules, along with its catalog if it needs one.
+var pluralRules = map[string]func(n int) string{}
+
+// pluralCategory returns the plural category, such as "one" or "other", of
+// count in lang
+func pluralCategory(lang string, count int) string {
+	if rule, ok := pluralRules[lang]; ok {
+		return rule(count)
+	}
+	if count == 1 {
+		return "one"
+	}
+	return "other"
+}
@@ -0,0 +1,180 @@
+package i18n
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+// withCatalogs replaces the catalogs for a test
+func withCatalogs(t *testing.T, c map[string]catalog) {
+	t.Helper()
+	mu.Lock()
+	load()
+	saved, savedLang := catalogs, language
+	catalogs, language = c, ""
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		catalogs, language = saved, savedLang
+		mu.Unlock()
+	})
+}
+
+func TestCatalogs(t *testing.T) {
+	mu.Lock()
+	load()
+	all := catalogs
+	mu.Unlock()
+
+	english, ok := all[DefaultLanguage]
+	if !ok || len(english) == 0 {
+		t.Fatal("expected an English catalog")
+	}
+	placeholder := regexp.MustCompile(`\.[A-Z]\w*`)
+	for lang, c := range all {
+		for id, forms := range c {
+			source, ok := english[id]
+			if !ok {
+				t.Errorf("%s: %s is not an English message", lang, id)
+				continue
+			}
+			if _, ok := forms["other"]; !ok {
+				t.Errorf("%s: %s has no \"other\" form", lang, id)
+			}
+			known := strings.Join(placeholder.FindAllString(strings.Join(mapValues(source), " "), -1), " ")
+			for category, text := range forms {
+				if _, err := template.New(id).Parse(text); err != nil {
+					t.Errorf("%s: %s (%s): %v", lang, id, category, err)
+				}
+				for _, name := range placeholder.FindAllString(text, -1) {
+					if !strings.Contains(known+" .Count", name) {
+						t.Errorf("%s: %s uses %s, which the English message doesn't", lang, id, name)
+					}
+				}
+			}
+		}
+	}
+}
+
+func mapValues(m message) []string {
+	var values []string
+	for _, v := range m {
+		values = append(values, v)
+	}
+	return values
+}
+
+// TestMessagesExist checks that every message bb's code asks for is in the
+// English catalog
+func TestMessagesExist(t *testing.T) {
+	mu.Lock()
+	load()
+	english := catalogs[DefaultLanguage]
+	mu.Unlock()
+
+	call := regexp.MustCompile(`i18n\.[TN]\("([^"]+)"`)
+	root := filepath.Join("..", "..")
+	found := 0
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, m := range call.FindAllStringSubmatch(string(data), -1) {
+			found++
+			if _, ok := english[m[1]]; !ok {
+				t.Errorf("%s: message %q is not in en.yaml", path, m[1])
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found == 0 {
+		t.Error("expected to find messages in the code")
+	}
+}
+
+func TestSetLocale(t *testing.T) {
+	withCatalogs(t, map[string]catalog{
+		"en":    {"greeting": {"other": "Hello"}},
+		"de":    {"greeting": {"other": "Hallo"}},
+		"pt-BR": {"greeting": {"other": "Olá"}},
+	})
+
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{"de", "de"},
+		{"de_AT.UTF-8", "de"},
+		{"pt_BR.UTF-8@latin", "pt-BR"},
+		{"pt-br", "pt-BR"},
+		{"pt_PT", "en"},
+		{"fr_FR", "en"},
+		{"C", "en"},
+	}
+	for _, tt := range tests {
+		SetLocale(tt.locale)
+		if got := Language(); got != tt.want {
+			t.Errorf("SetLocale(%q) selected %q, want %q", tt.locale, got, tt.want)
+		}
+	}
+
+	// Without a locale, the environment selects one
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "de_DE.UTF-8")
+	t.Setenv("LANG", "en_US.UTF-8")
+	SetLocale("")
+	if got := T("greeting"); got != "Hallo" {
+		t.Errorf("T(greeting) = %q, want Hallo", got)
+	}
+	if !IsSupported("pt-BR") || IsSupported("fr") {
+		t.Error("IsSupported() reports the wrong catalogs")
+	}
+}
+
+func TestT(t *testing.T) {
+	withCatalogs(t, map[string]catalog{
+		"en": {
+			"files":   {"one": "{{.Count}} file in {{.Repo}}", "other": "{{.Count}} files in {{.Repo}}"},
+			"deleted": {"other": "Deleted {{.Name}}"},
+			"plain":   {"other": "Done"},
+		},
+		"de": {
+			"deleted": {"other": "{{.Name}} gelöscht"},
+		},
+	})
+
+	SetLocale("en")
+	if got := N("files", 1, "Repo", "team/api"); got != "1 file in team/api" {
+		t.Errorf("N(files, 1) = %q", got)
+	}
+	if got := N("files", 3, "Repo", "team/api"); got != "3 files in team/api" {
+		t.Errorf("N(files, 3) = %q", got)
+	}
+	if got := T("plain"); got != "Done" {
+		t.Errorf("T(plain) = %q", got)
+	}
+	if got := T("missing.message"); got != "missing.message" {
+		t.Errorf("T(missing) = %q, want its ID", got)
+	}
+
+	// Messages a catalog doesn't translate yet fall back to English
+	SetLocale("de")
+	if got := T("deleted", "Name", "main"); got != "main gelöscht" {
+		t.Errorf("T(deleted) = %q", got)
+	}
+	if got := T("plain"); got != "Done" {
+		t.Errorf("T(plain) = %q, want the English message", got)
+	}
+}
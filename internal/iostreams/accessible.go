@@ -0,0 +1,59 @@
+package iostreams
+
+import "fmt"
+
+// statusLabels are the words that start Success, Warning and Error
+// messages in accessible mode, in place of the glyphs a screen reader
+// would read out or skip
+var statusLabels = map[Role]string{
+	RoleSuccess: "OK: ",
+	RoleWarning: "Warning: ",
+	RoleError:   "Error: ",
+}
+
+var statusGlyphs = map[Role]string{
+	RoleSuccess: "✓ ",
+	RoleWarning: "! ",
+	RoleError:   "✗ ",
+}
+
+// SetAccessible switches to output suited to screen readers, as the
+// accessible config key asks: no spinners or redrawn lines, words instead
+// of glyphs and icons, and tables written a row per line with their
+// headers.
+func (s *IOStreams) SetAccessible(accessible bool) {
+	s.accessible = accessible
+}
+
+// IsAccessible reports whether output is suited to screen readers.
+func (s *IOStreams) IsAccessible() bool {
+	return s.accessible
+}
+
+// StatusPrefix returns what a message with the state of role, success,
+// warning or error, starts with: a glyph, or in accessible mode a word.
+func (s *IOStreams) StatusPrefix(role Role) string {
+	if s.accessible {
+		return statusLabels[role]
+	}
+	return statusGlyphs[role]
+}
+
+// Symbol returns glyph followed by a space, to decorate text that already
+// says what the glyph shows, e.g. "✓ pass". It returns "" in accessible
+// mode, where the glyph would only be noise.
+func (s *IOStreams) Symbol(glyph string) string {
+	if s.accessible {
+		return ""
+	}
+	return glyph + " "
+}
+
+// announce writes label as a line on stderr, in place of a spinner, where
+// one would have been shown.
+func (s *IOStreams) announce(label string) {
+	if s.progressDisabled || !s.IsStderrTTY() || IsCI() {
+		return
+	}
+	fmt.Fprintln(s.ErrOut, label+"...")
+}
@@ -0,0 +1,24 @@
+package iostreams
+
+import "bytes"
+
+// CaptureOutput runs fn with Out redirected to a buffer and returns what was
+// written. Terminal detection and width keep reporting the real stdout, so
+// the captured text is formatted exactly as a direct render would be.
+func (s *IOStreams) CaptureOutput(fn func() error) (string, error) {
+	isTTY := s.IsStdoutTTY()
+	width := s.TerminalWidth()
+
+	origOut, origOverride, origWidth := s.Out, s.stdoutTTYOverride, s.terminalWidth
+	defer func() {
+		s.Out, s.stdoutTTYOverride, s.terminalWidth = origOut, origOverride, origWidth
+	}()
+
+	var buf bytes.Buffer
+	s.Out = &buf
+	s.stdoutTTYOverride = isTTY
+	s.terminalWidth = width
+
+	err := fn()
+	return buf.String(), err
+}
@@ -0,0 +1,118 @@
+package iostreams
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Icon names a status glyph shown next to states in table output
+type Icon string
+
+// Status icons
+const (
+	IconOpen        Icon = "open"
+	IconMerged      Icon = "merged"
+	IconDeclined    Icon = "declined"
+	IconSuccess     Icon = "success"
+	IconFailed      Icon = "failed"
+	IconStopped     Icon = "stopped"
+	IconRunning     Icon = "running"
+	IconPending     Icon = "pending"
+	IconBug         Icon = "bug"
+	IconEnhancement Icon = "enhancement"
+	IconProposal    Icon = "proposal"
+	IconTask        Icon = "task"
+)
+
+// Icon sets accepted by the icons config key
+const (
+	IconsNone  = "none"
+	IconsEmoji = "emoji"
+	IconsNerd  = "nerd"
+)
+
+var iconSets = map[string]map[Icon]string{
+	IconsNone: {},
+	IconsEmoji: {
+		IconOpen:        "🟢",
+		IconMerged:      "🟣",
+		IconDeclined:    "🔴",
+		IconSuccess:     "✅",
+		IconFailed:      "❌",
+		IconStopped:     "🛑",
+		IconRunning:     "🔄",
+		IconPending:     "⏳",
+		IconBug:         "🐛",
+		IconEnhancement: "✨",
+		IconProposal:    "💡",
+		IconTask:        "📋",
+	},
+	// Octicons from the Nerd Fonts private use area; these need a patched
+	// font to display.
+	IconsNerd: {
+		IconOpen:        "\uf407", // git-pull-request
+		IconMerged:      "\uf419", // git-merge
+		IconDeclined:    "\uf467", // x
+		IconSuccess:     "\uf42e", // check
+		IconFailed:      "\uf467", // x
+		IconStopped:     "\uf28d", // stop-circle
+		IconRunning:     "\uf46a", // sync
+		IconPending:     "\uf43a", // clock
+		IconBug:         "\uf188", // bug
+		IconEnhancement: "\uf427", // rocket
+		IconProposal:    "\uf400", // light-bulb
+		IconTask:        "\uf4a0", // tasklist
+	},
+}
+
+// IconSetNames returns the accepted values for the icons config key.
+func IconSetNames() []string {
+	return []string{IconsNone, IconsEmoji, IconsNerd}
+}
+
+// IsValidIconSet reports whether name is a known icon set.
+func IsValidIconSet(name string) bool {
+	_, ok := iconSets[name]
+	return ok
+}
+
+// SetIcons selects the icon set used by Icon. An empty name disables icons.
+// Icons are also disabled when the locale does not use UTF-8, since the
+// glyphs would be printed as garbage.
+func (s *IOStreams) SetIcons(name string) error {
+	if name == "" {
+		name = IconsNone
+	}
+	set, ok := iconSets[name]
+	if !ok {
+		return fmt.Errorf("unknown icon set %q (available: %s)", name, strings.Join(IconSetNames(), ", "))
+	}
+	if !isUTF8Locale() {
+		set = nil
+	}
+	s.icons = set
+	return nil
+}
+
+// Icon prefixes text with the glyph for icon from the configured icon set.
+// Text is returned unchanged when icons are disabled, in accessible mode or
+// when stdout is not a terminal.
+func (s *IOStreams) Icon(icon Icon, text string) string {
+	glyph := s.icons[icon]
+	if glyph == "" || s.accessible || !s.IsStdoutTTY() {
+		return text
+	}
+	return glyph + " " + text
+}
+
+// isUTF8Locale reports whether the locale environment selects UTF-8.
+func isUTF8Locale() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			v = strings.ToLower(v)
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
+	}
+	return false
+}
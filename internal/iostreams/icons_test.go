@@ -0,0 +1,106 @@
+package iostreams
+
+import (
+	"bytes"
+	"testing"
+)
+
+// setLocale sets the locale variables isUTF8Locale looks at
+func setLocale(t *testing.T, lcAll, lcCtype, lang string) {
+	t.Helper()
+	t.Setenv("LC_ALL", lcAll)
+	t.Setenv("LC_CTYPE", lcCtype)
+	t.Setenv("LANG", lang)
+}
+
+func TestIsUTF8Locale(t *testing.T) {
+	tests := []struct {
+		name                 string
+		lcAll, lcCtype, lang string
+		want                 bool
+	}{
+		{name: "LANG", lang: "en_US.UTF-8", want: true},
+		{name: "utf8 spelling", lang: "de_DE.utf8", want: true},
+		{name: "LC_CTYPE over LANG", lcCtype: "C", lang: "en_US.UTF-8", want: false},
+		{name: "LC_ALL over the rest", lcAll: "en_GB.UTF-8", lcCtype: "C", lang: "C", want: true},
+		{name: "POSIX locale", lcAll: "POSIX", lang: "en_US.UTF-8", want: false},
+		{name: "Latin-1", lang: "fr_FR.ISO-8859-1", want: false},
+		{name: "unset", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setLocale(t, tt.lcAll, tt.lcCtype, tt.lang)
+			if got := isUTF8Locale(); got != tt.want {
+				t.Errorf("isUTF8Locale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIcon(t *testing.T) {
+	tests := []struct {
+		name       string
+		set        string
+		lang       string
+		tty        bool
+		accessible bool
+		want       string
+	}{
+		{name: "emoji", set: IconsEmoji, lang: "en_US.UTF-8", tty: true, want: "✅ SUCCESSFUL"},
+		{name: "nerd font", set: IconsNerd, lang: "en_US.UTF-8", tty: true, want: "\uf42e SUCCESSFUL"},
+		{name: "not a UTF-8 locale", set: IconsEmoji, lang: "C", tty: true, want: "SUCCESSFUL"},
+		{name: "no icon set", set: "", lang: "en_US.UTF-8", tty: true, want: "SUCCESSFUL"},
+		{name: "icons turned off", set: IconsNone, lang: "en_US.UTF-8", tty: true, want: "SUCCESSFUL"},
+		{name: "not a terminal", set: IconsEmoji, lang: "en_US.UTF-8", want: "SUCCESSFUL"},
+		{name: "accessible", set: IconsEmoji, lang: "en_US.UTF-8", tty: true, accessible: true, want: "SUCCESSFUL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setLocale(t, "", "", tt.lang)
+			s := &IOStreams{Out: &bytes.Buffer{}, stdoutTTYOverride: tt.tty, accessible: tt.accessible}
+			if err := s.SetIcons(tt.set); err != nil {
+				t.Fatalf("SetIcons(%q) error = %v", tt.set, err)
+			}
+			if got := s.Icon(IconSuccess, "SUCCESSFUL"); got != tt.want {
+				t.Errorf("Icon() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetIcons_Override(t *testing.T) {
+	setLocale(t, "", "", "en_US.UTF-8")
+	s := &IOStreams{Out: &bytes.Buffer{}, stdoutTTYOverride: true}
+
+	// A later setting, such as BB_ICONS=none over the config file's icon
+	// set, replaces the earlier one
+	if err := s.SetIcons(IconsEmoji); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetIcons(IconsNone); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Icon(IconMerged, "MERGED"); got != "MERGED" {
+		t.Errorf("Icon() after turning icons off = %q", got)
+	}
+
+	// An unknown set is an error and leaves the icons as they were
+	if err := s.SetIcons(IconsEmoji); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetIcons("fancy"); err == nil {
+		t.Error("SetIcons() with an unknown set should fail")
+	}
+	if got := s.Icon(IconMerged, "MERGED"); got != "🟣 MERGED" {
+		t.Errorf("Icon() after an unknown set = %q", got)
+	}
+
+	for _, name := range IconSetNames() {
+		if !IsValidIconSet(name) {
+			t.Errorf("IsValidIconSet(%q) = false", name)
+		}
+	}
+	if IsValidIconSet("fancy") {
+		t.Error(`IsValidIconSet("fancy") = true`)
+	}
+}
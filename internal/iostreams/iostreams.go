@@ -1,6 +1,7 @@
 package iostreams
 
 import (
+	"bufio"
 	"fmt"
 	"io"
 	"os"
@@ -9,6 +10,12 @@ import (
 	"golang.org/x/term"
 )
 
+// isTerminal and readPassword are the terminal calls, replaced in tests
+var (
+	isTerminal   = term.IsTerminal
+	readPassword = term.ReadPassword
+)
+
 // IOStreams provides access to standard input/output streams
 type IOStreams struct {
 	In     io.Reader
@@ -18,6 +25,24 @@ type IOStreams struct {
 	colorEnabled  bool
 	is256enabled  bool
 	terminalWidth int
+	theme         Theme
+	icons         map[Icon]string
+
+	timestampStyle string
+
+	pagerCommand      string
+	pagerDisabled     bool
+	pagerProcess      *os.Process
+	origOut           io.Writer
+	stdoutTTYOverride bool
+
+	progressDisabled bool
+	quiet            bool
+	accessible       bool
+
+	neverPrompt bool
+	assumeYes   bool
+	inReader    *bufio.Reader
 }
 
 // New creates a new IOStreams with default stdin/stdout/stderr
@@ -37,8 +62,11 @@ func New() *IOStreams {
 
 // IsStdoutTTY returns true if stdout is a terminal
 func (s *IOStreams) IsStdoutTTY() bool {
+	if s.stdoutTTYOverride {
+		return true
+	}
 	if f, ok := s.Out.(*os.File); ok {
-		return term.IsTerminal(int(f.Fd()))
+		return isTerminal(int(f.Fd()))
 	}
 	return false
 }
@@ -46,7 +74,7 @@ func (s *IOStreams) IsStdoutTTY() bool {
 // IsStderrTTY returns true if stderr is a terminal
 func (s *IOStreams) IsStderrTTY() bool {
 	if f, ok := s.ErrOut.(*os.File); ok {
-		return term.IsTerminal(int(f.Fd()))
+		return isTerminal(int(f.Fd()))
 	}
 	return false
 }
@@ -54,7 +82,7 @@ func (s *IOStreams) IsStderrTTY() bool {
 // IsStdinTTY returns true if stdin is a terminal
 func (s *IOStreams) IsStdinTTY() bool {
 	if f, ok := s.In.(*os.File); ok {
-		return term.IsTerminal(int(f.Fd()))
+		return isTerminal(int(f.Fd()))
 	}
 	return false
 }
@@ -86,13 +114,8 @@ func (s *IOStreams) TerminalWidth() int {
 }
 
 func (s *IOStreams) shouldEnableColor() bool {
-	// Check NO_COLOR environment variable
-	if os.Getenv("NO_COLOR") != "" {
-		return false
-	}
-
-	// Check BB_NO_COLOR environment variable
-	if os.Getenv("BB_NO_COLOR") != "" {
+	// Check NO_COLOR and BB_NO_COLOR environment variables
+	if noColorRequested() {
 		return false
 	}
 
@@ -117,10 +140,12 @@ func (s *IOStreams) shouldEnable256Color() bool {
 		os.Getenv("COLORTERM") != ""
 }
 
-// Color codes
+// Color codes. Commands should style output through Style and a Role
+// rather than using these directly, so that themes apply.
 const (
 	Reset      = "\033[0m"
 	Bold       = "\033[1m"
+	Dim        = "\033[2m"
 	Red        = "\033[31m"
 	Green      = "\033[32m"
 	Yellow     = "\033[33m"
@@ -144,37 +169,46 @@ func (s *IOStreams) ColorFunc(color string) func(string) string {
 	}
 }
 
+// SetQuiet suppresses Success and Info messages and progress indicators,
+// as requested by --quiet, so only primary output such as URLs and IDs is
+// printed.
+func (s *IOStreams) SetQuiet(quiet bool) {
+	s.quiet = quiet
+	if quiet {
+		s.progressDisabled = true
+	}
+}
+
+// IsQuiet reports whether informational output is suppressed.
+func (s *IOStreams) IsQuiet() bool {
+	return s.quiet
+}
+
 // Success prints a success message (green checkmark)
 func (s *IOStreams) Success(format string, a ...interface{}) {
-	msg := fmt.Sprintf(format, a...)
-	if s.colorEnabled {
-		fmt.Fprintf(s.Out, "%s%s %s%s\n", Green, "✓", msg, Reset)
-	} else {
-		fmt.Fprintf(s.Out, "✓ %s\n", msg)
+	if s.quiet {
+		return
 	}
+	msg := fmt.Sprintf(format, a...)
+	fmt.Fprintln(s.Out, s.Style(RoleSuccess, s.StatusPrefix(RoleSuccess)+msg))
 }
 
 // Error prints an error message (red X)
 func (s *IOStreams) Error(format string, a ...interface{}) {
 	msg := fmt.Sprintf(format, a...)
-	if s.colorEnabled {
-		fmt.Fprintf(s.ErrOut, "%s%s %s%s\n", Red, "✗", msg, Reset)
-	} else {
-		fmt.Fprintf(s.ErrOut, "✗ %s\n", msg)
-	}
+	fmt.Fprintln(s.ErrOut, s.Style(RoleError, s.StatusPrefix(RoleError)+msg))
 }
 
 // Warning prints a warning message (yellow !)
 func (s *IOStreams) Warning(format string, a ...interface{}) {
 	msg := fmt.Sprintf(format, a...)
-	if s.colorEnabled {
-		fmt.Fprintf(s.ErrOut, "%s%s %s%s\n", Yellow, "!", msg, Reset)
-	} else {
-		fmt.Fprintf(s.ErrOut, "! %s\n", msg)
-	}
+	fmt.Fprintln(s.ErrOut, s.Style(RoleWarning, s.StatusPrefix(RoleWarning)+msg))
 }
 
 // Info prints an info message
 func (s *IOStreams) Info(format string, a ...interface{}) {
+	if s.quiet {
+		return
+	}
 	fmt.Fprintf(s.Out, format+"\n", a...)
 }
@@ -0,0 +1,44 @@
+package iostreams
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestSetQuiet(t *testing.T) {
+	var out, errOut bytes.Buffer
+	s := &IOStreams{Out: &out, ErrOut: &errOut}
+	if s.IsQuiet() {
+		t.Fatal("IsQuiet() = true before SetQuiet")
+	}
+
+	s.SetQuiet(true)
+	if !s.IsQuiet() {
+		t.Fatal("IsQuiet() = false after SetQuiet(true)")
+	}
+	s.Success("Created pull request #%d", 1)
+	s.Info("Fetching %s...", "feature")
+	s.Warning("Could not set upstream tracking: %s", "no remote")
+	s.Error("Failed to merge #%d", 1)
+	// What the command was asked for, such as a URL or JSON, is written
+	// to Out directly and still printed
+	fmt.Fprintln(s.Out, "https://bitbucket.org/team/api/pull-requests/1")
+
+	if got, want := out.String(), "https://bitbucket.org/team/api/pull-requests/1\n"; got != want {
+		t.Errorf("quiet stdout = %q, want only the data %q", got, want)
+	}
+	if got, want := errOut.String(), "! Could not set upstream tracking: no remote\n✗ Failed to merge #1\n"; got != want {
+		t.Errorf("quiet stderr = %q, want the warning and error %q", got, want)
+	}
+	if s.ProgressEnabled() {
+		t.Error("ProgressEnabled() = true in quiet mode")
+	}
+
+	out.Reset()
+	s.SetQuiet(false)
+	s.Info("Fetching %s...", "feature")
+	if out.String() != "Fetching feature...\n" {
+		t.Errorf("stdout after SetQuiet(false) = %q", out.String())
+	}
+}
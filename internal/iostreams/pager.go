@@ -0,0 +1,167 @@
+package iostreams
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"unicode"
+)
+
+// SetPager sets the command used to page long output. Its arguments may be
+// quoted as in a shell. An empty string or "cat" disables paging.
+func (s *IOStreams) SetPager(cmd string) {
+	s.pagerCommand = cmd
+}
+
+// GetPager returns the configured pager command.
+func (s *IOStreams) GetPager() string {
+	return s.pagerCommand
+}
+
+// DisablePager turns off paging for the rest of the process, as requested
+// by --no-pager.
+func (s *IOStreams) DisablePager() {
+	s.pagerDisabled = true
+}
+
+// StartPager starts the pager and redirects Out to it. It is a no-op when
+// stdout is not a terminal or paging is disabled. Callers must call
+// StopPager once all output has been written.
+func (s *IOStreams) StartPager() error {
+	if s.pagerDisabled || s.pagerProcess != nil {
+		return nil
+	}
+	if !s.IsStdoutTTY() {
+		return nil
+	}
+	args, err := splitCommand(s.pagerCommand)
+	if err != nil {
+		return fmt.Errorf("invalid pager %q: %w", s.pagerCommand, err)
+	}
+	if len(args) == 0 || (len(args) == 1 && args[0] == "cat") {
+		return nil
+	}
+
+	// Let less pass ANSI colors through and quit when output fits on one
+	// screen, unless the user has configured it already.
+	env := os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		env = append(env, "LESS=FRX")
+	}
+	if _, ok := os.LookupEnv("LV"); !ok {
+		env = append(env, "LV=-c")
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = env
+	cmd.Stdout = s.Out
+	cmd.Stderr = s.ErrOut
+
+	pagedOut, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	// Keep reporting the original terminal so colors and widths stay
+	// consistent while writing into the pager's pipe.
+	s.terminalWidth = s.TerminalWidth()
+	s.stdoutTTYOverride = true
+	s.origOut = s.Out
+	s.Out = &pagerWriter{pagedOut}
+
+	if err := cmd.Start(); err != nil {
+		pagedOut.Close()
+		s.Out = s.origOut
+		s.stdoutTTYOverride = false
+		return fmt.Errorf("failed to start pager %q: %w", s.pagerCommand, err)
+	}
+	s.pagerProcess = cmd.Process
+	return nil
+}
+
+// StopPager closes the pager's input and waits for it to exit.
+func (s *IOStreams) StopPager() {
+	if s.pagerProcess == nil {
+		return
+	}
+
+	_ = s.Out.(io.WriteCloser).Close()
+	_, _ = s.pagerProcess.Wait()
+
+	s.Out = s.origOut
+	s.origOut = nil
+	s.pagerProcess = nil
+	s.stdoutTTYOverride = false
+}
+
+// splitCommand splits a pager command into its arguments, which may be
+// quoted with single or double quotes, as in less --prompt='page %d'. A
+// backslash escapes a following quote, backslash or space and is otherwise
+// kept, so Windows paths need no escaping.
+func splitCommand(command string) ([]string, error) {
+	var (
+		args   []string
+		word   strings.Builder
+		inWord bool
+		quote  rune
+		escape bool
+	)
+	for _, r := range command {
+		switch {
+		case escape:
+			escaped := r == '\\' || r == '"' || (quote == 0 && (r == '\'' || unicode.IsSpace(r)))
+			if !escaped {
+				word.WriteRune('\\')
+			}
+			word.WriteRune(r)
+			escape = false
+		case r == quote:
+			quote = 0
+		case quote == '\'':
+			word.WriteRune(r)
+		case r == '\\':
+			escape, inWord = true, true
+		case quote == '"':
+			word.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case unicode.IsSpace(r):
+			if inWord {
+				args = append(args, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if escape {
+		word.WriteRune('\\')
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		args = append(args, word.String())
+	}
+	return args, nil
+}
+
+// pagerWriter swallows EPIPE so quitting the pager early does not surface as
+// a write error in the command.
+type pagerWriter struct {
+	io.WriteCloser
+}
+
+func (w *pagerWriter) Write(p []byte) (int, error) {
+	n, err := w.WriteCloser.Write(p)
+	if err != nil && (errors.Is(err, io.ErrClosedPipe) || errors.Is(err, syscall.EPIPE)) {
+		return len(p), nil
+	}
+	return n, err
+}
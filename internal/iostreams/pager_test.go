@@ -0,0 +1,121 @@
+package iostreams
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+		wantErr bool
+	}{
+		{command: "less", want: []string{"less"}},
+		{command: "  less  -R  ", want: []string{"less", "-R"}},
+		{command: "less --prompt='page %d of %D'", want: []string{"less", "--prompt=page %d of %D"}},
+		{command: `less "-P page \"%d\""`, want: []string{"less", `-P page "%d"`}},
+		{command: `my\ pager -x`, want: []string{"my pager", "-x"}},
+		{command: `"C:\Program Files\Git\usr\bin\less.exe" -R`, want: []string{`C:\Program Files\Git\usr\bin\less.exe`, "-R"}},
+		{command: `C:\tools\less.exe`, want: []string{`C:\tools\less.exe`}},
+		{command: `''`, want: []string{""}},
+		{command: "", want: nil},
+		{command: "less 'unterminated", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			got, err := splitCommand(tt.command)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("splitCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartPager_Off(t *testing.T) {
+	tests := []struct {
+		name     string
+		pager    string
+		tty      bool
+		disabled bool
+	}{
+		{name: "not a terminal", pager: "less", tty: false},
+		{name: "disabled", pager: "less", tty: true, disabled: true},
+		{name: "empty", pager: "", tty: true},
+		{name: "blank", pager: "  ", tty: true},
+		{name: "cat", pager: "cat", tty: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			s := &IOStreams{Out: &out, ErrOut: &bytes.Buffer{}, stdoutTTYOverride: tt.tty}
+			s.SetPager(tt.pager)
+			if tt.disabled {
+				s.DisablePager()
+			}
+
+			if err := s.StartPager(); err != nil {
+				t.Fatalf("StartPager() error = %v", err)
+			}
+			defer s.StopPager()
+			if s.pagerProcess != nil || s.Out != &out {
+				t.Error("StartPager() started a pager")
+			}
+		})
+	}
+}
+
+func TestStartPager(t *testing.T) {
+	if _, err := exec.LookPath("sed"); err != nil {
+		t.Skip("sed is not installed")
+	}
+	path := filepath.Join(t.TempDir(), "out")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// sed stands in for a pager, with a quoted argument holding a space
+	s := &IOStreams{Out: f, ErrOut: &bytes.Buffer{}, stdoutTTYOverride: true}
+	s.SetPager("sed -e 's/^/paged: /'")
+	if err := s.StartPager(); err != nil {
+		t.Fatalf("StartPager() error = %v", err)
+	}
+	fmt.Fprintln(s.Out, "hello")
+	s.StopPager()
+
+	if s.Out != f {
+		t.Error("StopPager() didn't restore Out")
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "paged: hello\n" {
+		t.Errorf("paged output = %q", got)
+	}
+}
+
+func TestStartPager_Errors(t *testing.T) {
+	for _, pager := range []string{"/nonexistent/pager", "less 'unterminated"} {
+		var out bytes.Buffer
+		s := &IOStreams{Out: &out, ErrOut: &bytes.Buffer{}, stdoutTTYOverride: true}
+		s.SetPager(pager)
+
+		if err := s.StartPager(); err == nil {
+			t.Errorf("StartPager() with %q succeeded", pager)
+		}
+		if s.Out != &out || s.pagerProcess != nil {
+			t.Errorf("StartPager() with %q left Out redirected", pager)
+		}
+	}
+}
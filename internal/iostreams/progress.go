@@ -0,0 +1,187 @@
+package iostreams
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+const (
+	spinnerInterval  = 100 * time.Millisecond
+	progressBarWidth = 30
+)
+
+// IsCI reports whether the process appears to run under a CI system, where
+// animated output only clutters the logs.
+func IsCI() bool {
+	for _, name := range []string{"CI", "BUILD_NUMBER", "RUN_ID", "BITBUCKET_BUILD_NUMBER", "CONTINUOUS_INTEGRATION"} {
+		if os.Getenv(name) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// ProgressEnabled reports whether animated progress may be drawn on stderr.
+// It never may in accessible mode.
+func (s *IOStreams) ProgressEnabled() bool {
+	return !s.progressDisabled && !s.accessible && s.IsStderrTTY() && !IsCI()
+}
+
+// DisableProgress turns off spinners and progress bars.
+func (s *IOStreams) DisableProgress() {
+	s.progressDisabled = true
+}
+
+// Progress is a running progress indicator. The zero value and a nil
+// pointer are valid and do nothing, so callers can Stop unconditionally.
+type Progress struct {
+	out   io.Writer
+	label string
+
+	mu      sync.Mutex
+	current int64
+	total   int64
+	bytes   bool
+	done    chan struct{}
+	stopped sync.WaitGroup
+}
+
+// StartProgress shows a spinner with label on stderr until Stop is called.
+// It does nothing when stderr is not a terminal or when running in CI. In
+// accessible mode the label is written once instead. Stop it before running
+// anything that may prompt on the terminal, such as git asking for
+// credentials, which the spinner would draw over.
+func (s *IOStreams) StartProgress(label string) *Progress {
+	if s.accessible {
+		s.announce(label)
+		return nil
+	}
+	if !s.ProgressEnabled() {
+		return nil
+	}
+	p := &Progress{out: s.ErrOut, label: label, done: make(chan struct{})}
+	p.start()
+	return p
+}
+
+// StartByteProgress shows a byte-count progress bar on stderr. total may be
+// negative when the size is not known in advance, in which case only the
+// transferred byte count is shown. In accessible mode the label is written
+// once instead.
+func (s *IOStreams) StartByteProgress(label string, total int64) *Progress {
+	if s.accessible {
+		s.announce(label)
+		return nil
+	}
+	if !s.ProgressEnabled() {
+		return nil
+	}
+	p := &Progress{out: s.ErrOut, label: label, total: total, bytes: true, done: make(chan struct{})}
+	p.start()
+	return p
+}
+
+func (p *Progress) start() {
+	p.stopped.Add(1)
+	go func() {
+		defer p.stopped.Done()
+		ticker := time.NewTicker(spinnerInterval)
+		defer ticker.Stop()
+		for frame := 0; ; frame++ {
+			p.draw(frame)
+			select {
+			case <-p.done:
+				fmt.Fprint(p.out, "\r\033[K")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (p *Progress) draw(frame int) {
+	p.mu.Lock()
+	current, total, bytes := p.current, p.total, p.bytes
+	p.mu.Unlock()
+
+	spinner := spinnerFrames[frame%len(spinnerFrames)]
+	if !bytes {
+		fmt.Fprintf(p.out, "\r\033[K%s %s", spinner, p.label)
+		return
+	}
+	if total <= 0 {
+		fmt.Fprintf(p.out, "\r\033[K%s %s %s", spinner, p.label, FormatBytes(current))
+		return
+	}
+
+	ratio := float64(current) / float64(total)
+	if ratio > 1 {
+		ratio = 1
+	}
+	filled := int(ratio * progressBarWidth)
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+	fmt.Fprintf(p.out, "\r\033[K%s [%s] %3.0f%% %s/%s", p.label, bar, ratio*100, FormatBytes(current), FormatBytes(total))
+}
+
+// Add records n more transferred bytes.
+func (p *Progress) Add(n int64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.current += n
+	p.mu.Unlock()
+}
+
+// Stop removes the indicator from the terminal. It is safe to call more
+// than once.
+func (p *Progress) Stop() {
+	if p == nil || p.done == nil {
+		return
+	}
+	select {
+	case <-p.done:
+	default:
+		close(p.done)
+	}
+	p.stopped.Wait()
+}
+
+// Reader wraps r so that bytes read through it advance the progress bar.
+func (p *Progress) Reader(r io.Reader) io.Reader {
+	if p == nil {
+		return r
+	}
+	return &progressReader{r: r, p: p}
+}
+
+type progressReader struct {
+	r io.Reader
+	p *Progress
+}
+
+func (pr *progressReader) Read(b []byte) (int, error) {
+	n, err := pr.r.Read(b)
+	pr.p.Add(int64(n))
+	return n, err
+}
+
+// FormatBytes formats a byte count using binary units, e.g. "1.5 MiB".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,136 @@
+package iostreams
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// notCI clears the variables IsCI looks at, so tests behave the same in CI
+func notCI(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{"CI", "BUILD_NUMBER", "RUN_ID", "BITBUCKET_BUILD_NUMBER", "CONTINUOUS_INTEGRATION"} {
+		t.Setenv(name, "")
+	}
+}
+
+// terminalStderr returns streams whose stderr is a file taken for a
+// terminal, and a function returning what was written to it
+func terminalStderr(t *testing.T) (*IOStreams, func() string) {
+	t.Helper()
+	f, err := os.Create(filepath.Join(t.TempDir(), "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+
+	origIsTerminal := isTerminal
+	t.Cleanup(func() { isTerminal = origIsTerminal })
+	isTerminal = func(int) bool { return true }
+
+	return &IOStreams{Out: &bytes.Buffer{}, ErrOut: f}, func() string {
+		b, err := os.ReadFile(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+}
+
+func TestStartProgress_NotTerminal(t *testing.T) {
+	notCI(t)
+	for _, accessible := range []bool{false, true} {
+		var errOut bytes.Buffer
+		s := &IOStreams{Out: &bytes.Buffer{}, ErrOut: &errOut, accessible: accessible}
+
+		p := s.StartProgress("Fetching")
+		p.Add(10)
+		p.Stop()
+		b := s.StartByteProgress("Downloading", 100)
+		b.Stop()
+
+		if p != nil || b != nil {
+			t.Errorf("accessible=%v: progress started without a terminal", accessible)
+		}
+		if errOut.Len() != 0 {
+			t.Errorf("accessible=%v: wrote %q without a terminal", accessible, errOut.String())
+		}
+	}
+}
+
+func TestStartProgress_Disabled(t *testing.T) {
+	notCI(t)
+	s, written := terminalStderr(t)
+	s.DisableProgress()
+	s.StartProgress("Fetching").Stop()
+	if got := written(); got != "" {
+		t.Errorf("wrote %q with progress disabled", got)
+	}
+
+	t.Setenv("CI", "true")
+	s, written = terminalStderr(t)
+	s.StartProgress("Fetching").Stop()
+	if got := written(); got != "" {
+		t.Errorf("wrote %q in CI", got)
+	}
+}
+
+func TestProgress_StopClearsLine(t *testing.T) {
+	notCI(t)
+	s, written := terminalStderr(t)
+
+	p := s.StartProgress("Fetching pull requests")
+	if p == nil {
+		t.Fatal("StartProgress() on a terminal returned nil")
+	}
+	p.Stop()
+	p.Stop()
+
+	got := written()
+	if !strings.Contains(got, "Fetching pull requests") {
+		t.Errorf("spinner output %q doesn't show the label", got)
+	}
+	if !strings.HasSuffix(got, "\r\033[K") {
+		t.Errorf("spinner output %q doesn't end by clearing the line", got)
+	}
+}
+
+func TestProgress_Accessible(t *testing.T) {
+	notCI(t)
+	s, written := terminalStderr(t)
+	s.accessible = true
+
+	if p := s.StartProgress("Fetching pull requests"); p != nil {
+		t.Error("StartProgress() in accessible mode started a spinner")
+	}
+	if got := written(); got != "Fetching pull requests...\n" {
+		t.Errorf("accessible output = %q, want the label once", got)
+	}
+}
+
+func TestProgress_Draw(t *testing.T) {
+	tests := []struct {
+		name  string
+		p     *Progress
+		added int64
+		want  string
+	}{
+		{name: "spinner", p: &Progress{label: "Fetching"}, want: "⠋ Fetching"},
+		{name: "unknown size", p: &Progress{label: "Downloading", bytes: true, total: -1}, added: 2048, want: "⠋ Downloading 2.0 KiB"},
+		{name: "half done", p: &Progress{label: "Uploading", bytes: true, total: 2048}, added: 1024, want: "Uploading [" + strings.Repeat("█", 15) + strings.Repeat("░", 15) + "]  50% 1.0 KiB/2.0 KiB"},
+		{name: "past the total", p: &Progress{label: "Uploading", bytes: true, total: 10}, added: 20, want: "Uploading [" + strings.Repeat("█", 30) + "] 100% 20 B/10 B"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			tt.p.out = &out
+			tt.p.Reader(bytes.NewReader(make([]byte, tt.added))).Read(make([]byte, tt.added))
+			tt.p.draw(0)
+			if got := out.String(); got != "\r\033[K"+tt.want {
+				t.Errorf("draw() = %q, want %q", got, "\r\033[K"+tt.want)
+			}
+		})
+	}
+}
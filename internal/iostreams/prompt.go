@@ -0,0 +1,230 @@
+package iostreams
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rbansal42/bitbucket-cli/internal/i18n"
+)
+
+// ErrNoPrompt is returned by the Prompt methods when input is required but
+// the session is not interactive, or prompting has been disabled in config.
+var ErrNoPrompt = errors.New("cannot prompt for input in a non-interactive session")
+
+// SetNeverPrompt disables interactive prompts, as with `prompt: disabled`.
+func (s *IOStreams) SetNeverPrompt(never bool) {
+	s.neverPrompt = never
+}
+
+// SetAssumeYes makes PromptConfirm answer yes without asking, as with --yes.
+func (s *IOStreams) SetAssumeYes(yes bool) {
+	s.assumeYes = yes
+}
+
+// AssumeYes reports whether confirmations should be skipped.
+func (s *IOStreams) AssumeYes() bool {
+	return s.assumeYes
+}
+
+// CanPrompt reports whether the user can be asked for input.
+func (s *IOStreams) CanPrompt() bool {
+	return !s.neverPrompt && s.IsStdinTTY() && s.IsStdoutTTY()
+}
+
+// reader returns a buffered reader over In that is shared between prompts,
+// so input typed ahead is not lost between questions.
+func (s *IOStreams) reader() *bufio.Reader {
+	if s.inReader == nil {
+		if br, ok := s.In.(*bufio.Reader); ok {
+			s.inReader = br
+		} else {
+			s.inReader = bufio.NewReader(s.In)
+		}
+	}
+	return s.inReader
+}
+
+func (s *IOStreams) readLine() (string, error) {
+	line, err := s.reader().ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// PromptText asks for a line of text. An empty answer returns defaultValue.
+func (s *IOStreams) PromptText(message, defaultValue string) (string, error) {
+	if !s.CanPrompt() {
+		return "", ErrNoPrompt
+	}
+
+	if defaultValue != "" {
+		fmt.Fprintf(s.Out, "%s (%s): ", message, defaultValue)
+	} else {
+		fmt.Fprintf(s.Out, "%s: ", message)
+	}
+
+	answer, err := s.readLine()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", i18n.T("prompt.read_failed"), err)
+	}
+	if answer == "" {
+		return defaultValue, nil
+	}
+	return answer, nil
+}
+
+// PromptSecret asks for a value without echoing it to the terminal.
+func (s *IOStreams) PromptSecret(message string) (string, error) {
+	if !s.CanPrompt() {
+		return "", ErrNoPrompt
+	}
+
+	fmt.Fprintf(s.Out, "%s: ", message)
+
+	// Earlier prompts read through a buffer; echo can only be turned off
+	// when nothing typed ahead is waiting in it
+	if f, ok := s.In.(*os.File); ok && isTerminal(int(f.Fd())) && (s.inReader == nil || s.inReader.Buffered() == 0) {
+		secret, err := readPassword(int(f.Fd()))
+		fmt.Fprintln(s.Out)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", i18n.T("prompt.read_failed"), err)
+		}
+		return strings.TrimSpace(string(secret)), nil
+	}
+
+	// Input was typed ahead or is not a terminal; fall back to reading a
+	// plain line.
+	answer, err := s.readLine()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", i18n.T("prompt.read_failed"), err)
+	}
+	return answer, nil
+}
+
+// PromptConfirm asks a yes/no question. With --yes it returns true without
+// asking; when prompting is not possible it returns ErrNoPrompt.
+func (s *IOStreams) PromptConfirm(message string, defaultYes bool) (bool, error) {
+	if s.assumeYes {
+		return true, nil
+	}
+	if !s.CanPrompt() {
+		return false, ErrNoPrompt
+	}
+
+	hint := "[y/N]"
+	if defaultYes {
+		hint = "[Y/n]"
+	}
+	fmt.Fprintf(s.Out, "%s %s: ", message, hint)
+
+	answer, err := s.readLine()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", i18n.T("prompt.read_failed"), err)
+	}
+	switch strings.ToLower(answer) {
+	case "":
+		return defaultYes, nil
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// PromptSelect asks the user to pick one of options and returns its index.
+// defaultIndex is used for an empty answer; pass -1 to require a choice.
+func (s *IOStreams) PromptSelect(message string, options []string, defaultIndex int) (int, error) {
+	if !s.CanPrompt() {
+		return -1, ErrNoPrompt
+	}
+	if len(options) == 0 {
+		return -1, errors.New(i18n.T("prompt.no_options"))
+	}
+
+	fmt.Fprintln(s.Out, message)
+	for i, opt := range options {
+		fmt.Fprintf(s.Out, "  [%d] %s\n", i+1, opt)
+	}
+
+	for {
+		if defaultIndex >= 0 && defaultIndex < len(options) {
+			fmt.Fprint(s.Out, i18n.T("prompt.choice_default", "Max", len(options), "Default", defaultIndex+1))
+		} else {
+			fmt.Fprint(s.Out, i18n.T("prompt.choice", "Max", len(options)))
+		}
+
+		answer, err := s.readLine()
+		if err != nil {
+			return -1, fmt.Errorf("%s: %w", i18n.T("prompt.read_failed"), err)
+		}
+		if answer == "" && defaultIndex >= 0 && defaultIndex < len(options) {
+			return defaultIndex, nil
+		}
+
+		if idx, err := strconv.Atoi(answer); err == nil && idx >= 1 && idx <= len(options) {
+			return idx - 1, nil
+		}
+		s.Warning("%s", i18n.T("prompt.invalid_choice", "Answer", answer))
+	}
+}
+
+// PromptMultiSelect asks the user to pick any number of options, entered as
+// a comma- or space-separated list of numbers, and returns their indexes.
+func (s *IOStreams) PromptMultiSelect(message string, options []string) ([]int, error) {
+	if !s.CanPrompt() {
+		return nil, ErrNoPrompt
+	}
+	if len(options) == 0 {
+		return nil, nil
+	}
+
+	fmt.Fprintln(s.Out, message)
+	for i, opt := range options {
+		fmt.Fprintf(s.Out, "  [%d] %s\n", i+1, opt)
+	}
+
+	for {
+		fmt.Fprint(s.Out, i18n.T("prompt.choices"))
+
+		answer, err := s.readLine()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", i18n.T("prompt.read_failed"), err)
+		}
+		if answer == "" {
+			return nil, nil
+		}
+
+		selected, ok := parseSelection(answer, len(options))
+		if ok {
+			return selected, nil
+		}
+		s.Warning("%s", i18n.T("prompt.invalid_selection", "Answer", answer))
+	}
+}
+
+// parseSelection parses a list such as "1, 3 4" into zero-based indexes.
+func parseSelection(answer string, count int) ([]int, bool) {
+	fields := strings.FieldsFunc(answer, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+
+	seen := make(map[int]bool)
+	var selected []int
+	for _, f := range fields {
+		idx, err := strconv.Atoi(f)
+		if err != nil || idx < 1 || idx > count {
+			return nil, false
+		}
+		if !seen[idx] {
+			seen[idx] = true
+			selected = append(selected, idx-1)
+		}
+	}
+	return selected, true
+}
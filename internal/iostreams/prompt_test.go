@@ -0,0 +1,83 @@
+package iostreams
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+// fakeTerminal makes the streams read from a pipe taking the place of a
+// terminal, and records whether secrets are read with echo off
+func fakeTerminal(t *testing.T, input string) (*IOStreams, *bool) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Close() })
+	w.WriteString(input)
+	w.Close()
+
+	noEcho := new(bool)
+	origIsTerminal, origReadPassword := isTerminal, readPassword
+	t.Cleanup(func() { isTerminal, readPassword = origIsTerminal, origReadPassword })
+	isTerminal = func(int) bool { return true }
+	readPassword = func(int) ([]byte, error) {
+		*noEcho = true
+		return []byte("s3cret"), nil
+	}
+
+	return &IOStreams{In: r, Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}, stdoutTTYOverride: true}, noEcho
+}
+
+func TestPromptSecret(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		before     func(s *IOStreams) error
+		want       string
+		wantNoEcho bool
+	}{
+		{
+			name:       "first prompt",
+			want:       "s3cret",
+			wantNoEcho: true,
+		},
+		{
+			name:  "after another prompt",
+			input: "alice\n",
+			before: func(s *IOStreams) error {
+				_, err := s.PromptText("Username", "")
+				return err
+			},
+			want:       "s3cret",
+			wantNoEcho: true,
+		},
+		{
+			name:  "typed ahead",
+			input: "1\ntyped-ahead\n",
+			before: func(s *IOStreams) error {
+				_, err := s.PromptSelect("Method", []string{"token", "oauth"}, -1)
+				return err
+			},
+			want: "typed-ahead",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, noEcho := fakeTerminal(t, tt.input)
+			if tt.before != nil {
+				if err := tt.before(s); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := s.PromptSecret("Token")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want || *noEcho != tt.wantNoEcho {
+				t.Errorf("PromptSecret() = %q with echo off %v, want %q with echo off %v", got, *noEcho, tt.want, tt.wantNoEcho)
+			}
+		})
+	}
+}
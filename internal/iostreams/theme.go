@@ -0,0 +1,172 @@
+package iostreams
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Role names the purpose of a piece of colored output. Commands style text by
+// role and the active theme decides the actual escape sequence.
+type Role string
+
+// Color roles used across commands
+const (
+	RoleSuccess  Role = "success"  // passing checks, open items
+	RoleWarning  Role = "warning"  // in-progress or attention-worthy states
+	RoleError    Role = "error"    // failures and rejected items
+	RoleAddition Role = "addition" // added lines in diffs
+	RoleDeletion Role = "deletion" // removed lines in diffs
+	RoleAccent   Role = "accent"   // merged or otherwise finished items
+	RoleInfo     Role = "info"     // pending states and secondary highlights
+	RoleMuted    Role = "muted"    // stopped or de-emphasised items
+	RoleHeader   Role = "header"   // table headers and headings
+	RoleEmphasis Role = "emphasis" // bold text in rendered markdown
+)
+
+// Theme maps color roles to ANSI escape sequences. A role missing from a
+// theme is rendered without color.
+type Theme map[Role]string
+
+// DefaultThemeName is the theme used when none is configured
+const DefaultThemeName = "default"
+
+var themes = map[string]Theme{
+	"default": {
+		RoleSuccess:  Green,
+		RoleWarning:  Yellow,
+		RoleError:    Red,
+		RoleAddition: Green,
+		RoleDeletion: Red,
+		RoleAccent:   Magenta,
+		RoleInfo:     Cyan,
+		RoleMuted:    White,
+		RoleHeader:   Bold,
+		RoleEmphasis: Bold,
+	},
+	// colorblind avoids red/green pairs, which are hard to tell apart with
+	// the most common forms of color vision deficiency.
+	"colorblind": {
+		RoleSuccess:  Blue,
+		RoleWarning:  Yellow,
+		RoleError:    "\033[38;5;208m",
+		RoleAddition: Blue,
+		RoleDeletion: "\033[38;5;208m",
+		RoleAccent:   Magenta,
+		RoleInfo:     Cyan,
+		RoleMuted:    White,
+		RoleHeader:   Bold,
+		RoleEmphasis: Bold,
+	},
+	"high-contrast": {
+		RoleSuccess:  BoldGreen,
+		RoleWarning:  BoldYellow,
+		RoleError:    BoldRed,
+		RoleAddition: BoldGreen,
+		RoleDeletion: BoldRed,
+		RoleAccent:   "\033[1;35m",
+		RoleInfo:     "\033[1;36m",
+		RoleMuted:    White,
+		RoleHeader:   "\033[1;4m",
+		RoleEmphasis: Bold,
+	},
+	// monochrome keeps emphasis but drops hues, for terminals with poor
+	// color support or users who prefer plain output.
+	"monochrome": {
+		RoleSuccess:  Bold,
+		RoleWarning:  Bold,
+		RoleError:    Bold,
+		RoleAddition: Bold,
+		RoleDeletion: Dim,
+		RoleAccent:   Bold,
+		RoleMuted:    Dim,
+		RoleHeader:   Bold,
+		RoleEmphasis: Bold,
+	},
+}
+
+// ThemeNames returns the names of the built-in themes, sorted.
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsValidTheme reports whether name is a built-in theme.
+func IsValidTheme(name string) bool {
+	_, ok := themes[name]
+	return ok
+}
+
+// SetTheme selects the theme used by Style. An empty name selects the
+// default theme.
+func (s *IOStreams) SetTheme(name string) error {
+	if name == "" {
+		name = DefaultThemeName
+	}
+	theme, ok := themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(ThemeNames(), ", "))
+	}
+	s.theme = theme
+	return nil
+}
+
+// Style wraps text in the escape sequence for role, or returns it unchanged
+// when color is disabled.
+func (s *IOStreams) Style(role Role, text string) string {
+	if !s.colorEnabled {
+		return text
+	}
+	theme := s.theme
+	if theme == nil {
+		theme = themes[DefaultThemeName]
+	}
+	code, ok := theme[role]
+	if !ok || code == "" {
+		return text
+	}
+	return code + text + Reset
+}
+
+// Color modes accepted by --color
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// SetColorMode applies a --color value. "auto" re-runs terminal and
+// environment detection; "always" and "never" override it, including
+// NO_COLOR.
+func (s *IOStreams) SetColorMode(mode string) error {
+	switch mode {
+	case ColorAuto, "":
+		s.colorEnabled = s.shouldEnableColor()
+	case ColorAlways:
+		s.colorEnabled = true
+	case ColorNever:
+		s.colorEnabled = false
+	default:
+		return fmt.Errorf("invalid color mode %q (must be auto, always, or never)", mode)
+	}
+	s.is256enabled = s.shouldEnable256Color()
+	return nil
+}
+
+// noColorRequested reports whether the user asked for colorless output via
+// the environment. Following no-color.org, NO_COLOR only counts when set to
+// a non-empty value.
+func noColorRequested() bool {
+	if v, ok := os.LookupEnv("NO_COLOR"); ok && v != "" {
+		return true
+	}
+	if v, ok := os.LookupEnv("BB_NO_COLOR"); ok && v != "" {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package iostreams
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestStyle_Themes(t *testing.T) {
+	tests := []struct {
+		theme string
+		role  Role
+		want  string
+	}{
+		{theme: "", role: RoleSuccess, want: Green + "ok" + Reset},
+		{theme: "default", role: RoleError, want: Red + "ok" + Reset},
+		{theme: "default", role: RoleHeader, want: Bold + "ok" + Reset},
+		{theme: "colorblind", role: RoleSuccess, want: Blue + "ok" + Reset},
+		{theme: "colorblind", role: RoleDeletion, want: "\033[38;5;208mok" + Reset},
+		{theme: "high-contrast", role: RoleError, want: BoldRed + "ok" + Reset},
+		{theme: "high-contrast", role: RoleHeader, want: "\033[1;4mok" + Reset},
+		{theme: "monochrome", role: RoleSuccess, want: Bold + "ok" + Reset},
+		{theme: "monochrome", role: RoleMuted, want: Dim + "ok" + Reset},
+		// Roles a theme leaves out are not colored
+		{theme: "monochrome", role: RoleInfo, want: "ok"},
+		{theme: "default", role: Role("unknown"), want: "ok"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.theme+"/"+string(tt.role), func(t *testing.T) {
+			s := &IOStreams{Out: &bytes.Buffer{}}
+			s.SetColorMode(ColorAlways)
+			if err := s.SetTheme(tt.theme); err != nil {
+				t.Fatalf("SetTheme(%q) error = %v", tt.theme, err)
+			}
+			if got := s.Style(tt.role, "ok"); got != tt.want {
+				t.Errorf("Style() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThemes_CoverRoles(t *testing.T) {
+	roles := []Role{RoleSuccess, RoleWarning, RoleError, RoleAddition, RoleDeletion, RoleAccent, RoleInfo, RoleMuted, RoleHeader, RoleEmphasis}
+	for _, name := range ThemeNames() {
+		for _, role := range roles {
+			// monochrome leaves secondary highlights plain on purpose
+			if name == "monochrome" && role == RoleInfo {
+				continue
+			}
+			if themes[name][role] == "" {
+				t.Errorf("theme %s has no style for %s", name, role)
+			}
+		}
+	}
+}
+
+func TestSetTheme_Unknown(t *testing.T) {
+	s := &IOStreams{Out: &bytes.Buffer{}}
+	s.SetColorMode(ColorAlways)
+	if err := s.SetTheme("colorblind"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"solarized", "Default", " default"} {
+		err := s.SetTheme(name)
+		if err == nil {
+			t.Errorf("SetTheme(%q) succeeded", name)
+			continue
+		}
+		if !strings.Contains(err.Error(), "available: colorblind, default, high-contrast, monochrome") {
+			t.Errorf("SetTheme(%q) error = %v, want it to list the themes", name, err)
+		}
+		if IsValidTheme(name) {
+			t.Errorf("IsValidTheme(%q) = true", name)
+		}
+	}
+	// The theme already selected stays in use
+	if got := s.Style(RoleSuccess, "ok"); got != Blue+"ok"+Reset {
+		t.Errorf("Style() after an unknown theme = %q, want the colorblind style", got)
+	}
+}
+
+func TestSetColorMode_Environment(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      string
+		noColor   string
+		bbNoColor string
+		term      string
+		tty       bool
+		want      bool
+	}{
+		{name: "terminal", mode: ColorAuto, term: "xterm-256color", tty: true, want: true},
+		{name: "not a terminal", mode: ColorAuto, term: "xterm-256color", want: false},
+		{name: "NO_COLOR", mode: ColorAuto, noColor: "1", term: "xterm-256color", tty: true, want: false},
+		{name: "BB_NO_COLOR", mode: ColorAuto, bbNoColor: "1", term: "xterm-256color", tty: true, want: false},
+		{name: "dumb terminal", mode: ColorAuto, term: "dumb", tty: true, want: false},
+		{name: "always over NO_COLOR", mode: ColorAlways, noColor: "1", want: true},
+		{name: "never on a terminal", mode: ColorNever, term: "xterm-256color", tty: true, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			t.Setenv("BB_NO_COLOR", tt.bbNoColor)
+			t.Setenv("TERM", tt.term)
+			s := &IOStreams{Out: &bytes.Buffer{}, stdoutTTYOverride: tt.tty}
+
+			if err := s.SetColorMode(tt.mode); err != nil {
+				t.Fatal(err)
+			}
+			if s.ColorEnabled() != tt.want {
+				t.Errorf("ColorEnabled() = %v, want %v", s.ColorEnabled(), tt.want)
+			}
+			styled := s.Style(RoleError, "failed") != "failed"
+			if styled != tt.want {
+				t.Errorf("Style() colored = %v, want %v", styled, tt.want)
+			}
+		})
+	}
+
+	s := &IOStreams{Out: &bytes.Buffer{}}
+	if err := s.SetColorMode("sometimes"); err == nil {
+		t.Error(`SetColorMode("sometimes") succeeded`)
+	}
+}
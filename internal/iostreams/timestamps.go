@@ -0,0 +1,45 @@
+package iostreams
+
+import "fmt"
+
+// Timestamp styles accepted by --timestamps
+const (
+	TimestampsRelative = "relative"
+	TimestampsAbsolute = "absolute"
+	TimestampsISO      = "iso"
+)
+
+// TimestampStyleNames returns the accepted timestamp styles.
+func TimestampStyleNames() []string {
+	return []string{TimestampsRelative, TimestampsAbsolute, TimestampsISO}
+}
+
+// IsValidTimestampStyle reports whether style is a known timestamp style.
+func IsValidTimestampStyle(style string) bool {
+	switch style {
+	case TimestampsRelative, TimestampsAbsolute, TimestampsISO:
+		return true
+	}
+	return false
+}
+
+// SetTimestampStyle selects how commands print times. An empty style selects
+// relative times such as "3 hours ago".
+func (s *IOStreams) SetTimestampStyle(style string) error {
+	if style == "" {
+		style = TimestampsRelative
+	}
+	if !IsValidTimestampStyle(style) {
+		return fmt.Errorf("invalid timestamp style %q (must be relative, absolute, or iso)", style)
+	}
+	s.timestampStyle = style
+	return nil
+}
+
+// TimestampStyle returns the selected timestamp style.
+func (s *IOStreams) TimestampStyle() string {
+	if s.timestampStyle == "" {
+		return TimestampsRelative
+	}
+	return s.timestampStyle
+}
@@ -0,0 +1,167 @@
+// Package logging keeps a log of what bb does, for looking into problems
+// after the fact: the commands run, the API requests they make, retries and
+// the use of the response cache. Entries are JSON objects, one per line,
+// written to a file that is rotated as it grows. The log is separate from
+// the messages on stderr, which are meant for people.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+)
+
+const (
+	// FileEnvVar names the environment variable that sets the log file,
+	// which also turns logging on
+	FileEnvVar = "BB_LOG_FILE"
+
+	// FileName is the name of the log file in the state directory
+	FileName = "bb.log"
+
+	// DefaultLevel is the level logged at when the log file is set but
+	// the level isn't
+	DefaultLevel = "info"
+
+	// maxBackups is how many rotated log files are kept, as bb.log.1, the
+	// newest, to bb.log.3
+	maxBackups = 3
+)
+
+// maxFileSize is the size a log file is rotated at
+var maxFileSize int64 = 5 << 20
+
+var levels = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+// Levels returns the accepted values for the log_level config key, most
+// verbose first
+func Levels() []string {
+	return []string{"debug", "info", "warn", "error"}
+}
+
+// IsValidLevel reports whether name is a log level
+func IsValidLevel(name string) bool {
+	_, ok := levels[strings.ToLower(name)]
+	return ok
+}
+
+// DefaultPath returns the log file used when BB_LOG_FILE doesn't name one
+func DefaultPath() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Open starts a log in the file at path, creating its directory, that
+// records entries of level and above. Close the returned closer when done.
+func Open(path, level string) (*slog.Logger, io.Closer, error) {
+	lvl, ok := levels[strings.ToLower(level)]
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid log level %q (must be one of: %s)", level, strings.Join(Levels(), ", "))
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, nil, fmt.Errorf("could not create log directory: %w", err)
+	}
+	file, err := openRotating(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	handler := slog.NewJSONHandler(file, &slog.HandlerOptions{Level: lvl})
+	return slog.New(handler).With("pid", os.Getpid()), file, nil
+}
+
+// rotatingFile appends to a log file, moving it aside to start a new one
+// once it reaches maxFileSize
+type rotatingFile struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	size int64
+}
+
+func openRotating(path string) (*rotatingFile, error) {
+	f := &rotatingFile{path: path}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("could not open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("could not open log file: %w", err)
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+// Write appends p, an entry, rotating the file first if p would take it
+// past maxFileSize. Entries are never split between files.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.size > 0 && f.size+int64(len(p)) > maxFileSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate renames the log file to path.1, shifting older ones along and
+// dropping the oldest, and starts a new one
+func (f *rotatingFile) rotate() error {
+	f.file.Close()
+	f.file = nil
+
+	backups := make([]string, 0, maxBackups)
+	for i := 1; i <= maxBackups; i++ {
+		backups = append(backups, fmt.Sprintf("%s.%d", f.path, i))
+	}
+	os.Remove(backups[len(backups)-1])
+	for i := len(backups) - 1; i > 0; i-- {
+		os.Rename(backups[i-1], backups[i])
+	}
+	// Another bb may have rotated the file already, in which case it is gone
+	if err := os.Rename(f.path, backups[0]); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not rotate log file: %w", err)
+	}
+	return f.open()
+}
+
+// Close closes the file; later writes fail
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
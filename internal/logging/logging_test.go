@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "bb.log")
+	logger, file, err := Open(path, "INFO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Debug("hidden")
+	logger.Info("command started", "command", "bb pr list")
+	logger.Warn("api request", "status", 429)
+	file.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d entries, want 2:\n%s", len(lines), data)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["msg"] != "command started" || entry["command"] != "bb pr list" || entry["pid"] == nil {
+		t.Errorf("entry = %v", entry)
+	}
+
+	if _, _, err := Open(path, "verbose"); err == nil {
+		t.Error("Open() with an invalid level succeeded")
+	}
+}
+
+func TestRotation(t *testing.T) {
+	defer func(size int64) { maxFileSize = size }(maxFileSize)
+	maxFileSize = 100
+
+	path := filepath.Join(t.TempDir(), "bb.log")
+	f, err := openRotating(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	entry := strings.Repeat("x", 59) + "\n"
+	for i := 0; i < 10; i++ {
+		if _, err := f.Write([]byte(entry)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{"bb.log", "bb.log.1", "bb.log.2", "bb.log.3"} {
+		info, err := os.Stat(filepath.Join(filepath.Dir(path), name))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if info.Size() != int64(len(entry)) {
+			t.Errorf("%s is %d bytes, want one entry", name, info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".4"); !os.IsNotExist(err) {
+		t.Errorf("expected only %d rotated files to be kept", maxBackups)
+	}
+}
@@ -0,0 +1,104 @@
+// Package redact removes secrets, such as tokens and passwords, from text
+// bb writes where others may read it: bug reports and the log file.
+package redact
+
+import (
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+)
+
+// redacted replaces secrets removed from text
+const redacted = "[REDACTED]"
+
+// sensitiveName matches the names of flags, query parameters and KEY=VALUE
+// pairs whose values are secret
+var sensitiveName = regexp.MustCompile(`(?i)(token|passw(or)?d|secret|credential|api[_-]?key|authorization)`)
+
+var (
+	// bearerPattern matches credentials in Authorization header values
+	bearerPattern = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`)
+	// userinfoPattern matches the user and password of a URL
+	userinfoPattern = regexp.MustCompile(`://[^/\s:@]+(:[^/\s@]*)?@`)
+	// assignmentPattern matches name=value pairs, as in queries and
+	// environment listings
+	assignmentPattern = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_.-]*)=([^\s&"']+)`)
+)
+
+// secretEnvVars hold credentials whose values are removed wherever they
+// appear
+var secretEnvVars = []string{"BB_TOKEN", "BITBUCKET_TOKEN", "GH_TOKEN", "GITHUB_TOKEN", config.PassphraseEnvVar}
+
+// Args returns args with the values of secret flags, such as
+// --token, and of secret KEY=VALUE arguments replaced
+func Args(args []string) []string {
+	result := make([]string, len(args))
+	redactNext := false
+	for i, arg := range args {
+		switch {
+		case redactNext:
+			result[i] = redacted
+			redactNext = false
+		case strings.HasPrefix(arg, "-"):
+			name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+			if sensitiveName.MatchString(name) {
+				if hasValue {
+					result[i] = arg[:strings.Index(arg, "=")+1] + redacted
+				} else {
+					result[i] = arg
+					redactNext = true
+				}
+				continue
+			}
+			result[i] = Text(arg)
+		default:
+			result[i] = Text(arg)
+		}
+	}
+	return result
+}
+
+// Text removes credentials from free text such as error messages:
+// Authorization values, URL passwords, secret name=value pairs, the values
+// of credential environment variables, and the home directory
+func Text(s string) string {
+	if s == "" {
+		return s
+	}
+	for _, name := range secretEnvVars {
+		if v := os.Getenv(name); len(v) >= 4 {
+			s = strings.ReplaceAll(s, v, redacted)
+		}
+	}
+	s = bearerPattern.ReplaceAllString(s, "$1 "+redacted)
+	s = userinfoPattern.ReplaceAllString(s, "://"+redacted+"@")
+	s = assignmentPattern.ReplaceAllStringFunc(s, func(m string) string {
+		name, _, _ := strings.Cut(m, "=")
+		if sensitiveName.MatchString(name) {
+			return name + "=" + redacted
+		}
+		return m
+	})
+	if home, err := os.UserHomeDir(); err == nil && len(home) > 1 {
+		s = strings.ReplaceAll(s, home, "~")
+	}
+	return s
+}
+
+// URL formats u without its user information and with the values
+// of secret query parameters removed
+func URL(u *url.URL) string {
+	c := *u
+	c.User = nil
+	q := c.Query()
+	for name := range q {
+		if sensitiveName.MatchString(name) {
+			q.Set(name, redacted)
+		}
+	}
+	c.RawQuery = strings.ReplaceAll(q.Encode(), url.QueryEscape(redacted), redacted)
+	return c.String()
+}
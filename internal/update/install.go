@@ -0,0 +1,196 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ChecksumsAsset is the release asset listing the SHA-256 checksum of each
+// archive
+const ChecksumsAsset = "checksums.txt"
+
+// maxDownloadSize bounds a download, well above the size of a release
+// archive, so a bad response can't fill memory
+const maxDownloadSize = 200 << 20
+
+// ArchiveName returns the name of the release archive of version for an
+// operating system and architecture, as .goreleaser.yml names them
+func ArchiveName(version, goos, goarch string) string {
+	ext := "tar.gz"
+	if goos == "windows" {
+		ext = "zip"
+	}
+	return fmt.Sprintf("bb_%s_%s_%s.%s", version, goos, goarch, ext)
+}
+
+// Download fetches the asset at url
+func Download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	if len(data) > maxDownloadSize {
+		return nil, fmt.Errorf("failed to download %s: larger than %d MB", url, maxDownloadSize>>20)
+	}
+	return data, nil
+}
+
+// VerifyChecksum checks data, the file called name, against its SHA-256
+// checksum in checksums, a file in the format sha256sum writes
+func VerifyChecksum(checksums []byte, name string, data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if !strings.EqualFold(fields[0], hex.EncodeToString(sum[:])) {
+			return fmt.Errorf("checksum mismatch for %s: the download may be corrupt or tampered with", name)
+		}
+		return nil
+	}
+	return fmt.Errorf("no checksum listed for %s", name)
+}
+
+// ExtractBinary returns the bb executable in archive, a release archive
+// called name
+func ExtractBinary(archive []byte, name string) ([]byte, error) {
+	if strings.HasSuffix(name, ".zip") {
+		return extractZip(archive)
+	}
+	return extractTarGz(archive)
+}
+
+func extractTarGz(archive []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("no bb executable in the archive")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeReg && path.Base(hdr.Name) == "bb" {
+			return io.ReadAll(io.LimitReader(tr, maxDownloadSize))
+		}
+	}
+}
+
+func extractZip(archive []byte) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	for _, f := range zr.File {
+		if path.Base(f.Name) != "bb.exe" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		defer rc.Close()
+		return io.ReadAll(io.LimitReader(rc, maxDownloadSize))
+	}
+	return nil, errors.New("no bb.exe executable in the archive")
+}
+
+// Replace atomically replaces the executable at exe with binary. The new
+// file is written next to exe and renamed over it, so exe is never left
+// half written. Windows can't replace a running executable, so there the
+// old one is moved aside to exe.old first.
+func Replace(exe string, binary []byte) error {
+	info, err := os.Stat(exe)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(exe)
+	tmp, err := os.CreateTemp(dir, ".bb-upgrade-*")
+	if err != nil {
+		return fmt.Errorf("failed to write to %s: %w", dir, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()|0o111); err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "windows" {
+		old := exe + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp.Name(), exe); err != nil {
+			_ = os.Rename(old, exe)
+			return err
+		}
+		return nil
+	}
+	return os.Rename(tmp.Name(), exe)
+}
+
+// PackageManager is a package manager that installed bb, and so should be
+// the one to upgrade it
+type PackageManager struct {
+	Name    string
+	Command string
+}
+
+// DetectPackageManager returns the package manager that installed the
+// executable at exe, if its path shows one did
+func DetectPackageManager(exe string) (PackageManager, bool) {
+	p := strings.ReplaceAll(exe, `\`, "/")
+	switch {
+	case strings.Contains(p, "/Cellar/") || strings.HasPrefix(p, "/opt/homebrew/") || strings.HasPrefix(p, "/home/linuxbrew/"):
+		return PackageManager{Name: "Homebrew", Command: "brew upgrade bb"}, true
+	case strings.Contains(strings.ToLower(p), "/scoop/apps/"):
+		return PackageManager{Name: "Scoop", Command: "scoop update bb"}, true
+	case strings.HasPrefix(p, "/nix/store/"):
+		return PackageManager{Name: "Nix", Command: "nix profile upgrade bb"}, true
+	}
+	return PackageManager{}, false
+}
@@ -0,0 +1,166 @@
+// Package update looks up releases of bb and installs them in place of the
+// running executable.
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+)
+
+const (
+	// repository is the GitHub repository bb is released from
+	repository = "rbansal42/bitbucket-cli"
+
+	githubAPIURL = "https://api.github.com"
+
+	// cacheKey and cacheTTL keep the latest release for a day, so checking
+	// for one costs a request at most once a day
+	cacheKey = "release/latest"
+	cacheTTL = 24 * time.Hour
+)
+
+// Release is a published release of bb
+type Release struct {
+	// Version is the release's version, without the leading "v" of its tag
+	Version string  `json:"version"`
+	URL     string  `json:"url"`
+	Assets  []Asset `json:"assets,omitempty"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// Asset returns the asset of r called name, if there is one
+func (r *Release) Asset(name string) (Asset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Asset{}, false
+}
+
+// Latest looks up the latest release of bb on GitHub, and caches it for
+// CachedLatest. GITHUB_API_URL overrides the API address, and the token in
+// GH_TOKEN or GITHUB_TOKEN is used if set, to raise the rate limit.
+func Latest(ctx context.Context) (*Release, error) {
+	base := os.Getenv("GITHUB_API_URL")
+	if base == "" {
+		base = githubAPIURL
+	}
+	url := fmt.Sprintf("%s/repos/%s/releases/latest", strings.TrimSuffix(base, "/"), repository)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if token := githubToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach GitHub: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to look up the latest release: %s", resp.Status)
+	}
+
+	var body struct {
+		TagName string `json:"tag_name"`
+		HTMLURL string `json:"html_url"`
+		Assets  []struct {
+			Name               string `json:"name"`
+			BrowserDownloadURL string `json:"browser_download_url"`
+		} `json:"assets"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to parse the latest release: %w", err)
+	}
+
+	release := &Release{Version: strings.TrimPrefix(body.TagName, "v"), URL: body.HTMLURL}
+	for _, a := range body.Assets {
+		release.Assets = append(release.Assets, Asset{Name: a.Name, URL: a.BrowserDownloadURL})
+	}
+	_ = config.WriteCache(cacheKey, Release{Version: release.Version, URL: release.URL})
+	return release, nil
+}
+
+// CachedLatest returns the latest release of bb like Latest, from the cache
+// if it was looked up in the last day. The cached release has no assets.
+func CachedLatest(ctx context.Context) (*Release, error) {
+	var r Release
+	if config.ReadCache(cacheKey, cacheTTL, &r) {
+		return &r, nil
+	}
+	return Latest(ctx)
+}
+
+func githubToken() string {
+	if token := os.Getenv("GH_TOKEN"); token != "" {
+		return token
+	}
+	return os.Getenv("GITHUB_TOKEN")
+}
+
+// Version is a MAJOR.MINOR.PATCH version, with an optional pre-release
+type Version struct {
+	parts      [3]int
+	prerelease string
+}
+
+// ParseVersion parses a version such as 1.2.3, v1.2 or 1.3.0-rc.1. Build
+// metadata after a "+" is ignored. Development builds, whose version is
+// "dev", don't parse.
+func ParseVersion(s string) (Version, bool) {
+	var v Version
+	s = strings.TrimPrefix(s, "v")
+	s, _, _ = strings.Cut(s, "+")
+	s, v.prerelease, _ = strings.Cut(s, "-")
+
+	fields := strings.Split(s, ".")
+	if len(fields) > 3 {
+		return v, false
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v.parts[i] = n
+	}
+	return v, true
+}
+
+// NewerThan reports whether v is a later version than other. A pre-release
+// comes before the release it leads up to; pre-releases of the same
+// version are compared as text.
+func (v Version) NewerThan(other Version) bool {
+	for i := range v.parts {
+		if v.parts[i] != other.parts[i] {
+			return v.parts[i] > other.parts[i]
+		}
+	}
+	switch {
+	case v.prerelease == other.prerelease:
+		return false
+	case v.prerelease == "":
+		return true
+	case other.prerelease == "":
+		return false
+	}
+	return v.prerelease > other.prerelease
+}
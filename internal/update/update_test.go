@@ -0,0 +1,196 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestNewerThan(t *testing.T) {
+	tests := []struct {
+		v, other string
+		want     bool
+	}{
+		{"1.2.0", "1.1.9", true},
+		{"1.10.0", "1.9.0", true},
+		{"2.0", "1.9.9", true},
+		{"1.2.0", "1.2.0", false},
+		{"v1.2.0", "1.2.0", false},
+		{"1.1.0", "1.2.0", false},
+		{"1.2.0", "1.2.0-rc.1", true},
+		{"1.2.0-rc.1", "1.2.0", false},
+		{"1.2.0-rc.2", "1.2.0-rc.1", true},
+		{"1.2.0+build.5", "1.2.0", false},
+	}
+	for _, tt := range tests {
+		v, ok := ParseVersion(tt.v)
+		if !ok {
+			t.Fatalf("ParseVersion(%q) failed", tt.v)
+		}
+		other, ok := ParseVersion(tt.other)
+		if !ok {
+			t.Fatalf("ParseVersion(%q) failed", tt.other)
+		}
+		if got := v.NewerThan(other); got != tt.want {
+			t.Errorf("%s NewerThan %s = %v, want %v", tt.v, tt.other, got, tt.want)
+		}
+	}
+}
+
+func TestParseVersion_Invalid(t *testing.T) {
+	for _, s := range []string{"dev", "", "1.2.3.4", "1.x"} {
+		if _, ok := ParseVersion(s); ok {
+			t.Errorf("ParseVersion(%q) succeeded, want failure", s)
+		}
+	}
+}
+
+func TestLatest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/rbansal42/bitbucket-cli/releases/latest" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer gh-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		w.Write([]byte(`{
+			"tag_name": "v1.3.0",
+			"html_url": "https://github.com/rbansal42/bitbucket-cli/releases/tag/v1.3.0",
+			"assets": [{"name": "checksums.txt", "browser_download_url": "https://example.com/checksums.txt"}]
+		}`))
+	}))
+	defer server.Close()
+	t.Setenv("GITHUB_API_URL", server.URL)
+	t.Setenv("GH_TOKEN", "gh-token")
+	t.Setenv("BB_CACHE_DIR", t.TempDir())
+
+	release, err := Latest(context.Background())
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if release.Version != "1.3.0" {
+		t.Errorf("Version = %q, want 1.3.0", release.Version)
+	}
+	if a, ok := release.Asset(ChecksumsAsset); !ok || a.URL != "https://example.com/checksums.txt" {
+		t.Errorf("Asset(%q) = %+v, %v", ChecksumsAsset, a, ok)
+	}
+
+	// The release is cached, without its assets
+	server.Close()
+	cached, err := CachedLatest(context.Background())
+	if err != nil {
+		t.Fatalf("CachedLatest() error = %v", err)
+	}
+	if cached.Version != "1.3.0" || len(cached.Assets) != 0 {
+		t.Errorf("CachedLatest() = %+v", cached)
+	}
+}
+
+func TestArchiveName(t *testing.T) {
+	if got := ArchiveName("1.3.0", "linux", "arm64"); got != "bb_1.3.0_linux_arm64.tar.gz" {
+		t.Errorf("ArchiveName() = %q", got)
+	}
+	if got := ArchiveName("1.3.0", "windows", "amd64"); got != "bb_1.3.0_windows_amd64.zip" {
+		t.Errorf("ArchiveName() = %q", got)
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("archive")
+	sum := sha256.Sum256(data)
+	checksums := []byte("0000  bb_1.3.0_darwin_arm64.tar.gz\n" + hex.EncodeToString(sum[:]) + "  bb_1.3.0_linux_amd64.tar.gz\n")
+
+	if err := VerifyChecksum(checksums, "bb_1.3.0_linux_amd64.tar.gz", data); err != nil {
+		t.Errorf("VerifyChecksum() error = %v", err)
+	}
+	if err := VerifyChecksum(checksums, "bb_1.3.0_darwin_arm64.tar.gz", data); err == nil || !strings.Contains(err.Error(), "mismatch") {
+		t.Errorf("VerifyChecksum() error = %v, want a mismatch", err)
+	}
+	if err := VerifyChecksum(checksums, "bb_1.3.0_windows_amd64.zip", data); err == nil {
+		t.Error("VerifyChecksum() succeeded for an unlisted file")
+	}
+}
+
+func TestExtractBinary(t *testing.T) {
+	var tgz bytes.Buffer
+	gz := gzip.NewWriter(&tgz)
+	tw := tar.NewWriter(gz)
+	for name, body := range map[string]string{"README.md": "readme", "bb": "binary"} {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(body)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(body))
+	}
+	tw.Close()
+	gz.Close()
+
+	got, err := ExtractBinary(tgz.Bytes(), "bb_1.3.0_linux_amd64.tar.gz")
+	if err != nil || string(got) != "binary" {
+		t.Errorf("ExtractBinary(tar.gz) = %q, %v", got, err)
+	}
+
+	var zipped bytes.Buffer
+	zw := zip.NewWriter(&zipped)
+	w, _ := zw.Create("bb.exe")
+	w.Write([]byte("windows binary"))
+	zw.Close()
+
+	got, err = ExtractBinary(zipped.Bytes(), "bb_1.3.0_windows_amd64.zip")
+	if err != nil || string(got) != "windows binary" {
+		t.Errorf("ExtractBinary(zip) = %q, %v", got, err)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes differ on Windows")
+	}
+	exe := filepath.Join(t.TempDir(), "bb")
+	if err := os.WriteFile(exe, []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Replace(exe, []byte("new")); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	data, err := os.ReadFile(exe)
+	if err != nil || string(data) != "new" {
+		t.Errorf("executable = %q, %v", data, err)
+	}
+	if info, _ := os.Stat(exe); info.Mode().Perm() != 0o755 {
+		t.Errorf("mode = %v, want 0755", info.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(exe))
+	if len(entries) != 1 {
+		t.Errorf("expected the temporary file to be gone, found %d entries", len(entries))
+	}
+}
+
+func TestDetectPackageManager(t *testing.T) {
+	tests := []struct {
+		exe  string
+		want string
+	}{
+		{"/opt/homebrew/Cellar/bb/1.2.0/bin/bb", "Homebrew"},
+		{"/usr/local/Cellar/bb/1.2.0/bin/bb", "Homebrew"},
+		{`C:\Users\me\scoop\apps\bb\current\bb.exe`, "Scoop"},
+		{"/nix/store/abc-bb-1.2.0/bin/bb", "Nix"},
+		{"/usr/local/bin/bb", ""},
+	}
+	for _, tt := range tests {
+		pm, _ := DetectPackageManager(tt.exe)
+		if pm.Name != tt.want {
+			t.Errorf("DetectPackageManager(%q) = %q, want %q", tt.exe, pm.Name, tt.want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Package extension lets bb extensions written in Go reuse bb's
+// authentication and configuration.
+//
+// bb runs an extension named bb-<name> as "bb <name>", passing the
+// repository and host selected with --repo and --hostname in BB_REPO and
+// BB_HOST, which the functions here honour:
+//
+//	client, err := extension.NewClient()
+//	if err != nil {
+//		return err
+//	}
+//	workspace, repo, err := extension.CurrentRepository()
+//	if err != nil {
+//		return err
+//	}
+//	var pr struct {
+//		Title string `json:"title"`
+//	}
+//	err = client.Get(ctx, "/repositories/"+workspace+"/"+repo+"/pullrequests/1", nil, &pr)
+package extension
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+	"github.com/rbansal42/bitbucket-cli/internal/cmdutil"
+	"github.com/rbansal42/bitbucket-cli/internal/config"
+	"github.com/rbansal42/bitbucket-cli/internal/git"
+)
+
+// Client makes requests to the Bitbucket API as the account bb is logged
+// in with
+type Client struct {
+	api *api.Client
+}
+
+// NewClient returns a client authenticated as the active account of the
+// host bb uses. Run "bb auth login" first.
+func NewClient() (*Client, error) {
+	client, err := cmdutil.GetAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return &Client{api: client}, nil
+}
+
+// HTTPError is returned for requests the API refuses
+type HTTPError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *HTTPError) Error() string {
+	return e.Message
+}
+
+// Get requests path, relative to the API's base URL, with query, and
+// decodes the JSON response into v unless it is nil
+func (c *Client) Get(ctx context.Context, path string, query url.Values, v any) error {
+	resp, err := c.api.Get(ctx, path, query)
+	return decode(resp, err, v)
+}
+
+// Post sends body as JSON to path and decodes the response into v unless
+// it is nil
+func (c *Client) Post(ctx context.Context, path string, body, v any) error {
+	resp, err := c.api.Post(ctx, path, body)
+	return decode(resp, err, v)
+}
+
+// Put sends body as JSON to path and decodes the response into v unless it
+// is nil
+func (c *Client) Put(ctx context.Context, path string, body, v any) error {
+	resp, err := c.api.Put(ctx, path, body)
+	return decode(resp, err, v)
+}
+
+// Delete deletes the resource at path
+func (c *Client) Delete(ctx context.Context, path string) error {
+	resp, err := c.api.Delete(ctx, path)
+	return decode(resp, err, nil)
+}
+
+func decode(resp *api.Response, err error, v any) error {
+	if err != nil {
+		var apiErr *api.APIError
+		if errors.As(err, &apiErr) {
+			return &HTTPError{StatusCode: apiErr.StatusCode, Message: apiErr.Error()}
+		}
+		return err
+	}
+	if v == nil || len(resp.Body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Body, v); err != nil {
+		return fmt.Errorf("could not parse response: %w", err)
+	}
+	return nil
+}
+
+// CurrentRepository returns the repository bb would use: the one given
+// with --repo, else the one pinned with "bb repo set-default", else the one
+// the git remote of the working directory points to
+func CurrentRepository() (workspace, repo string, err error) {
+	var hosts []string
+	if hostsConfig, err := config.LoadHostsConfig(); err == nil {
+		for host := range hostsConfig {
+			hosts = append(hosts, host)
+		}
+	}
+	if host := os.Getenv(config.HostEnvVar); host != "" {
+		hosts = append(hosts, host)
+	}
+	git.SetServerHosts(hosts)
+
+	return cmdutil.ParseRepository("")
+}
+
+// Config returns the effective value of a setting, such as git_protocol
+// or editor, as "bb config get" shows it, or "" if it is not set
+func Config(key string) (string, error) {
+	resolver, err := config.Resolve()
+	if err != nil {
+		return "", err
+	}
+	return resolver.Get(key).Value, nil
+}
@@ -0,0 +1,61 @@
+package extension
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rbansal42/bitbucket-cli/internal/api"
+)
+
+func TestClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repositories/team/app":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"full_name": "team/app"}`))
+		case "/repositories/team/app/refs/branches/gone":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"type": "error", "error": {"message": "Repository not found"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{api: api.NewClient(api.WithBaseURL(server.URL), api.WithToken("test-token"))}
+	ctx := context.Background()
+
+	var repo struct {
+		FullName string `json:"full_name"`
+	}
+	if err := client.Get(ctx, "/repositories/team/app", nil, &repo); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if repo.FullName != "team/app" {
+		t.Errorf("FullName = %q, want team/app", repo.FullName)
+	}
+
+	if err := client.Delete(ctx, "/repositories/team/app/refs/branches/gone"); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+
+	err := client.Get(ctx, "/repositories/team/missing", nil, &repo)
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("Get() error = %v, want an HTTPError with status 404", err)
+	}
+}
+
+func TestCurrentRepository(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv("BB_REPO", "team/app")
+
+	workspace, repo, err := CurrentRepository()
+	if err != nil || workspace != "team" || repo != "app" {
+		t.Errorf("CurrentRepository() = %q, %q, %v; want team, app", workspace, repo, err)
+	}
+}